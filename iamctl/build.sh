@@ -125,6 +125,9 @@ else
     echo "Skipping Go Tests..."
 fi
 
+# set the version of the binary
+ldflags="-X github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils.VERSION=${build_version}"
+
 # run the completion.go file to get the bash completion script
 # To do the string replace first build the script so that we have a consistent name
 go build -gcflags=-trimpath=$GOPATH -asmflags=-trimpath=$GOPATH
@@ -161,7 +164,7 @@ do
     mkdir -p $iamctl_bin_dir
     destination="$iamctl_bin_dir/$output"
 
-    GOOS=$goos GOARCH=$goarch go build -gcflags=-trimpath=$GOPATH -asmflags=-trimpath=$GOPATH -o $destination $target
+    GOOS=$goos GOARCH=$goarch go build -gcflags=-trimpath=$GOPATH -asmflags=-trimpath=$GOPATH -ldflags "$ldflags" -o $destination $target
 
     pwd=`pwd`
     cd $buildPath
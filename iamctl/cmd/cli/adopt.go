@@ -0,0 +1,88 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var adoptCmd = &cobra.Command{
+	Use:   "adopt [application|identityProvider]",
+	Short: "Adopt an existing resource",
+	Long:  `You can bring an existing resource in the target environment under the management of the local directory`,
+	Example: `  # Print the file of an existing application without writing it
+  iamctl adopt application --name hr-portal -c <config folder> --dry-run
+
+  # Adopt an application along with a .baseline file to detect the changes made on the server before the next import
+  iamctl adopt application --name crm -c <config folder> -o <base directory> --baseline
+
+  # Write the file of an identity provider in JSON format to the resource directory
+  iamctl adopt identityProvider -n Google -c <config folder> --env <environment> --encrypted-config -f json -o <base directory>`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"application", "identityProvider"},
+	Run: func(cmd *cobra.Command, args []string) {
+		resourceName, _ := cmd.Flags().GetString("name")
+		outputDirPath, _ := cmd.Flags().GetString("outputDir")
+		format, _ := cmd.Flags().GetString("format")
+		configFile, _ := cmd.Flags().GetString("config")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		utils.WRITE_BASELINE, _ = cmd.Flags().GetBool("baseline")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		if utils.WRITE_BASELINE && args[0] != "application" {
+			log.Fatalln("The --baseline flag is supported for applications only.")
+		}
+
+		baseDir := utils.LoadConfigs(configFile)
+		if outputDirPath == "" {
+			outputDirPath = baseDir
+		}
+
+		var err error
+		switch args[0] {
+		case "application":
+			err = applications.Adopt(resourceName, outputDirPath, format, dryRun)
+		case "identityProvider":
+			err = identityproviders.Adopt(resourceName, outputDirPath, format, dryRun)
+		}
+		if err != nil {
+			log.Fatalln("Error when adopting the resource.", err)
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(adoptCmd)
+	adoptCmd.Flags().StringP("name", "n", "", "Name of the resource to be adopted")
+	adoptCmd.Flags().StringP("outputDir", "o", "", "Path to the output directory")
+	adoptCmd.Flags().StringP("format", "f", "yaml", "Format of the exported file")
+	adoptCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	adoptCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	adoptCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	adoptCmd.Flags().Bool("dry-run", false, "Print the content of the file without writing it")
+	adoptCmd.Flags().Bool("baseline", false, "Write the server configuration of an application to a .baseline file to detect conflicts on import (identity providers are not supported)")
+	adoptCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,378 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package cli
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Results of the steps in the apply report.
+const STEP_DONE = "done"
+const STEP_FAILED = "failed"
+const STEP_SKIPPED = "skipped"
+const STEP_RESUMED = "completed in a previous run"
+
+type applyStepResult struct {
+	name   string
+	result string
+}
+
+type applyRun struct {
+	manifest       utils.EnvironmentManifest
+	resumedSteps   []string
+	completedSteps []string
+	report         []applyStepResult
+}
+
+var applyEnvironmentCmd = &cobra.Command{
+	Use:   "apply-environment",
+	Short: "Set up an environment from a manifest",
+	Long: `You can set up an environment by applying the resource directories declared in an environment manifest ` +
+		`in dependency order, with a single consolidated report`,
+	Example: `  # Set up an environment from a manifest
+  iamctl apply-environment -f <base directory>/environment.yml --encrypted-config --allow-partial-permissions
+
+  # Set up an environment whose applications reference identity providers managed elsewhere
+  iamctl apply-environment -f <base directory>/environment.yml --continue-on-missing-deps
+
+  # Continue from the step that failed in the previous run and send the progress events to a socket
+  iamctl apply-environment -f <base directory>/environment.yml --resume --progress-socket /tmp/iamctl.sock \
+    --audit-log /var/log/iamctl/audit.log --no-progress --backup /var/backups/iamctl --junit-report reports/iamctl-import.xml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		manifestPath, _ := cmd.Flags().GetString("file")
+		resume, _ := cmd.Flags().GetBool("resume")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.ALLOW_PARTIAL_PERMISSIONS, _ = cmd.Flags().GetBool("allow-partial-permissions")
+		utils.CONTINUE_ON_MISSING_DEPS, _ = cmd.Flags().GetBool("continue-on-missing-deps")
+		readProgressFlag(cmd)
+		readJUnitReportFlag(cmd, utils.IMPORT)
+		readAuditLogFlag(cmd)
+		readBackupFlag(cmd)
+		defer utils.CloseAuditLog()
+
+		manifest, err := utils.LoadEnvironmentManifest(manifestPath)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		run := &applyRun{manifest: manifest}
+		if resume {
+			run.resumedSteps = manifest.LoadCompletedSteps()
+		}
+		succeeded := run.apply()
+
+		run.printReport()
+		utils.PrintSummary(utils.IMPORT)
+		utils.FinishProgress(utils.IMPORT)
+		if !succeeded {
+			log.Fatalln("Applying the environment manifest failed. Run the command with the --resume flag " +
+				"to continue from the failed step.")
+		}
+		utils.ExitIfStrictWarnings()
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(applyEnvironmentCmd)
+	applyEnvironmentCmd.Flags().StringP("file", "f", "", "Path to the environment manifest")
+	applyEnvironmentCmd.Flags().Bool("resume", false, "Skip the steps completed in the previous run of the manifest")
+	applyEnvironmentCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	applyEnvironmentCmd.Flags().Bool("allow-partial-permissions", false, "Import the other resource types when read access is denied for some resource types")
+	applyEnvironmentCmd.Flags().Bool("continue-on-missing-deps", false, "Import the applications even if the referenced identity providers are not found")
+	addProgressFlag(applyEnvironmentCmd)
+	addJUnitReportFlag(applyEnvironmentCmd)
+	addAuditLogFlag(applyEnvironmentCmd)
+	addBackupFlag(applyEnvironmentCmd)
+	applyEnvironmentCmd.MarkFlagRequired("file")
+}
+
+func (run *applyRun) apply() bool {
+
+	manifest := run.manifest
+	configPath := manifest.ResolvePath(manifest.Profile.Config)
+	utils.ENVIRONMENT = manifest.Profile.Env
+
+	if timeout := manifest.GetWaitTimeout(); timeout > 0 {
+		if !run.runStep("wait for server", false, func() error {
+			return utils.WaitForServer(configPath, timeout)
+		}) {
+			return false
+		}
+	} else {
+		run.addResult("wait for server", STEP_SKIPPED)
+	}
+
+	utils.LoadConfigs(configPath)
+	run.applyOverrides()
+	utils.StartTimeBudget(manifest.GetTimeBudget())
+	utils.StartProgress(utils.IMPORT)
+
+	for _, hook := range manifest.Hooks.Pre {
+		if !run.runStep("pre hook: "+hook, true, func() error { return manifest.RunHook(hook) }) {
+			return false
+		}
+	}
+	if !run.runStep("validate", false, run.validate) || !run.runStep("plan", false, run.plan) {
+		return false
+	}
+
+	// Import each resource type from the directories in the order of the manifest, before the dependent types.
+	for _, resourceType := range importOrder {
+		for _, resource := range manifest.Resources {
+			if !utils.Contains(resource.GetTypes(), resourceType) {
+				continue
+			}
+			resourceType, inputDirPath := resourceType, manifest.ResolvePath(resource.Dir)
+			stepName := fmt.Sprintf("import %s from %s", resourceType, resource.Dir)
+			if !run.runStep(stepName, true, func() error { return importResourceType(resourceType, inputDirPath) }) {
+				return false
+			}
+		}
+	}
+	roles.ImportPendingRoles()
+
+	if !run.runStep("verify", false, run.verify) {
+		return false
+	}
+	for _, hook := range manifest.Hooks.Post {
+		if !run.runStep("post hook: "+hook, true, func() error { return manifest.RunHook(hook) }) {
+			return false
+		}
+	}
+	if err := manifest.SaveCompletedSteps(nil); err != nil {
+		log.Println(err)
+	}
+	return true
+}
+
+func (run *applyRun) runStep(name string, resumable bool, step func() error) bool {
+
+	if resumable && utils.Contains(run.resumedSteps, name) {
+		log.Println("Skipping the step completed in the previous run: " + name)
+		run.completedSteps = append(run.completedSteps, name)
+		run.addResult(name, STEP_RESUMED)
+		return true
+	}
+	log.Println("Running step: " + name)
+	if err := step(); err != nil {
+		log.Printf("Step failed: %s. %s", name, err)
+		run.addResult(name, STEP_FAILED)
+		return false
+	}
+	run.addResult(name, STEP_DONE)
+	if resumable {
+		run.completedSteps = append(run.completedSteps, name)
+		if err := run.manifest.SaveCompletedSteps(run.completedSteps); err != nil {
+			log.Println(err)
+		}
+	}
+	return true
+}
+
+func (run *applyRun) addResult(name string, result string) {
+
+	run.report = append(run.report, applyStepResult{name, result})
+}
+
+func (run *applyRun) applyOverrides() {
+
+	// Keywords of the manifest override the keyword mappings of the config folder.
+	if len(run.manifest.Keywords) > 0 {
+		utils.OverrideKeywordMappings(run.manifest.Keywords)
+	}
+
+	guardrails := run.manifest.Guardrails
+	if guardrails.AllowDelete != nil {
+		utils.TOOL_CONFIGS.AllowDelete = *guardrails.AllowDelete
+	}
+	if guardrails.Strict != nil {
+		utils.STRICT = *guardrails.Strict
+	}
+	if guardrails.AbortOnMask != nil {
+		utils.ABORT_ON_MASK = *guardrails.AbortOnMask
+	}
+}
+
+func (run *applyRun) validate() error {
+
+	var violations []string
+	for _, resource := range run.manifest.Resources {
+		inputDirPath := run.manifest.ResolvePath(resource.Dir)
+		lintViolations, err := utils.LintLocalResources(inputDirPath, nil)
+		if err != nil {
+			return fmt.Errorf("error when linting the resource files of %s: %s", resource.Dir, err)
+		}
+		for _, violation := range lintViolations {
+			if utils.Contains(resource.GetTypes(), strings.Split(filepath.ToSlash(violation.FilePath), "/")[0]) {
+				violations = append(violations, fmt.Sprintf("%s: %s", resource.Dir, violation))
+			}
+		}
+
+		filePaths, err := getManifestResourceFiles(inputDirPath, resource.GetTypes())
+		if err != nil {
+			return err
+		}
+		if utils.ABORT_ON_MASK {
+			if err := utils.CheckMaskedSecrets(filePaths); err != nil {
+				return err
+			}
+		}
+		if err := utils.CheckCertificatePlaceholders(filePaths); err != nil {
+			return err
+		}
+		if err := utils.CheckTenantUrls(inputDirPath, filePaths); err != nil {
+			return err
+		}
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("found %d violations in the resource files:\n  %s", len(violations),
+			strings.Join(violations, "\n  "))
+	}
+	return nil
+}
+
+func (run *applyRun) plan() error {
+
+	var resourceTypes []string
+	for _, resource := range run.manifest.Resources {
+		inputDirPath := run.manifest.ResolvePath(resource.Dir)
+		for _, resourceType := range resource.GetTypes() {
+			files, err := getManifestResourceFiles(inputDirPath, []string{resourceType})
+			if err != nil {
+				return err
+			}
+			if len(files) > 0 {
+				log.Printf("%s: %d files from %s", resourceType, len(files), resource.Dir)
+				if !utils.Contains(resourceTypes, resourceType) {
+					resourceTypes = append(resourceTypes, resourceType)
+				}
+			}
+		}
+		if err := checkResourceQuotas(inputDirPath); err != nil {
+			return err
+		}
+	}
+	if err := run.checkIdpDependencies(); err != nil {
+		return err
+	}
+	return utils.CheckResourcePermissions(resourceTypes)
+}
+
+func (run *applyRun) checkIdpDependencies() error {
+
+	// Identity providers of any directory in the manifest are imported before the applications.
+	var idpDirPaths, appDirPaths []string
+	for _, resource := range run.manifest.Resources {
+		inputDirPath := run.manifest.ResolvePath(resource.Dir)
+		if utils.Contains(resource.GetTypes(), utils.IDENTITY_PROVIDERS) {
+			idpDirPaths = append(idpDirPaths, inputDirPath)
+		}
+		if utils.Contains(resource.GetTypes(), utils.APPLICATIONS) {
+			appDirPaths = append(appDirPaths, inputDirPath)
+		}
+	}
+	getAvailableIdps := getAvailableIdpsFunc(idpDirPaths, nil)
+	for _, appDirPath := range appDirPaths {
+		if err := utils.CheckIdpDependencies(appDirPath, getAvailableIdps); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (run *applyRun) verify() error {
+
+	// Every local resource of the types that can be counted should exist in the target environment after the import.
+	var missing []string
+	for _, resource := range run.manifest.Resources {
+		for _, counter := range plannedCreateCounters {
+			if !utils.Contains(resource.GetTypes(), counter.resourceType) ||
+				utils.IsResourceTypeExcluded(counter.resourceType) {
+				continue
+			}
+			_, creates, err := counter.count(run.manifest.ResolvePath(resource.Dir))
+			if err != nil {
+				return fmt.Errorf("error when verifying %s: %s", counter.resourceType, err)
+			}
+			if creates > 0 {
+				missing = append(missing, fmt.Sprintf("%s from %s: %d", counter.resourceType, resource.Dir, creates))
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("resources are not found in the target environment after the import: %s",
+			strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (run *applyRun) printReport() {
+
+	fmt.Println("========================================")
+	fmt.Println("Apply Environment Report:")
+	fmt.Println("========================================")
+	for _, step := range run.report {
+		fmt.Printf("%-60s %s\n", step.name, step.result)
+	}
+}
+
+func importResourceType(resourceType string, inputDirPath string) error {
+
+	utils.LoadImportState(inputDirPath)
+	options := getImportOptions()
+	options.IncludeOnly = nil
+	err := client.NewFromLoadedConfigs().ImportResources(utils.GetRequestContext(), resourceType, inputDirPath, options)
+	if saveErr := utils.SaveImportState(); saveErr != nil {
+		log.Println("Error when saving the import state.", saveErr)
+	}
+	if err != nil {
+		return err
+	}
+	if utils.IsBudgetExhausted() {
+		return fmt.Errorf("time budget is exhausted before all resources are imported")
+	}
+	return nil
+}
+
+func getManifestResourceFiles(inputDirPath string, resourceTypes []string) ([]string, error) {
+
+	filePaths, err := utils.GetLocalResourceFilePaths(inputDirPath)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the resource files of %s: %s", inputDirPath, err)
+	}
+	var resourceFiles []string
+	for _, filePath := range filePaths {
+		relativePath, err := filepath.Rel(inputDirPath, filePath)
+		if err != nil {
+			continue
+		}
+		if utils.Contains(resourceTypes, strings.Split(filepath.ToSlash(relativePath), "/")[0]) {
+			resourceFiles = append(resourceFiles, filePath)
+		}
+	}
+	return resourceFiles, nil
+}
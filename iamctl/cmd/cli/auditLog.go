@@ -0,0 +1,36 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func addAuditLogFlag(command *cobra.Command) {
+
+	command.Flags().String("audit-log", "", "Path to the file to append the records of the created, updated and deleted resources")
+}
+
+// Read the audit log flag and open the audit log. The caller should close the audit log at the end of the run.
+func readAuditLogFlag(command *cobra.Command) {
+
+	utils.AUDIT_LOG, _ = command.Flags().GetString("audit-log")
+	utils.OpenAuditLog()
+}
@@ -0,0 +1,146 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	apiresources "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/apiResources"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	claims "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/claims"
+	emailtemplates "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/emailTemplates"
+	governanceconnectors "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/governanceConnectors"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	notificationsenders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/notificationSenders"
+	oidcscopes "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/oidcScopes"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/organizations"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/workflows"
+	xacmlpolicies "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/xacmlPolicies"
+)
+
+// Functions to find the local files of the resource types that can be matched by name with the deployed resources.
+var staleFileFinders = map[string]func(string) ([]string, error){
+	utils.CLAIMS:                claims.GetStaleLocalFiles,
+	utils.OIDC_SCOPES:           oidcscopes.GetStaleLocalFiles,
+	utils.IDENTITY_PROVIDERS:    identityproviders.GetStaleLocalFiles,
+	utils.API_RESOURCES:         apiresources.GetStaleLocalFiles,
+	utils.ROLES:                 roles.GetStaleLocalFiles,
+	utils.APPLICATIONS:          applications.GetStaleLocalFiles,
+	utils.ORGANIZATIONS:         organizations.GetStaleLocalFiles,
+	utils.USERSTORES:            userstores.GetStaleLocalFiles,
+	utils.EMAIL_TEMPLATES:       emailtemplates.GetStaleLocalFiles,
+	utils.GOVERNANCE_CONNECTORS: governanceconnectors.GetStaleLocalFiles,
+	utils.NOTIFICATION_SENDERS:  notificationsenders.GetStaleLocalFiles,
+	utils.WORKFLOWS:             workflows.GetStaleLocalFiles,
+	utils.XACML_POLICIES:        xacmlpolicies.GetStaleLocalFiles,
+}
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean",
+	Short: "Remove the local files of the resources deleted in the target environment",
+	Long: `You can remove the local resource files that no longer have a deployed resource in the target environment. ` +
+		`The files to be removed are printed, and are removed only when the --confirm flag is set`,
+	Example: `  # Print the local files of the resources that are deleted in the target environment
+  iamctl clean -c <config folder> -i <base directory>
+
+  # Print the local files of the deleted resources of a team, named with the team prefix
+  iamctl clean -c <config folder> -i <base directory> --namespace team-a-
+
+  # Remove the local files of the deleted resources, selecting the environment section from the config files
+  iamctl clean -c <config folder> --env <environment> --encrypted-config --confirm`,
+	Run: func(cmd *cobra.Command, args []string) {
+		inputDirPath, _ := cmd.Flags().GetString("inputDir")
+		configFile, _ := cmd.Flags().GetString("config")
+		confirm, _ := cmd.Flags().GetBool("confirm")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+
+		baseDir := utils.LoadConfigs(configFile)
+		if inputDirPath == "" {
+			inputDirPath = baseDir
+		}
+		staleFilePaths, failed := getStaleLocalFiles(inputDirPath)
+		if len(staleFilePaths) == 0 {
+			log.Println("No local files found for the resources deleted in the target environment.")
+		} else if !confirm {
+			fmt.Println("The following local files will be removed:")
+			for _, staleFilePath := range staleFilePaths {
+				fmt.Println("  - " + staleFilePath)
+			}
+			fmt.Println("Run the command with the --confirm flag to remove the files.")
+		} else {
+			for _, staleFilePath := range staleFilePaths {
+				if err := os.RemoveAll(staleFilePath); err != nil {
+					log.Println("Error when removing the file: ", staleFilePath, err)
+					failed = true
+				} else {
+					log.Println("Removed the file:", staleFilePath)
+				}
+			}
+		}
+		if failed {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(cleanCmd)
+	cleanCmd.Flags().StringP("inputDir", "i", "", "Path to the folder containing the resource files")
+	cleanCmd.Flags().StringP("config", "c", "", "Path to the env specific config folder")
+	cleanCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	cleanCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	cleanCmd.Flags().String("namespace", "", "Manage only the resources with names starting with the given prefix")
+	cleanCmd.Flags().Bool("confirm", false, "Remove the local files of the resources deleted in the target environment")
+}
+
+// Get the local files of the deleted resources of each resource type. The resource types that cannot be compared with
+// the target environment are skipped, and are reported as failed.
+func getStaleLocalFiles(inputDirPath string) ([]string, bool) {
+
+	var staleFilePaths []string
+	failed := false
+	for _, resourceType := range importOrder {
+		finder, ok := staleFileFinders[resourceType]
+		if !ok || utils.IsResourceTypeExcluded(resourceType) {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(inputDirPath, resourceType)); os.IsNotExist(err) {
+			continue
+		}
+		resourceTypeFilePaths, err := finder(inputDirPath)
+		if err != nil {
+			log.Printf("Error when comparing the local %s with the target environment. %s", resourceType, err)
+			failed = true
+			continue
+		}
+		staleFilePaths = append(staleFilePaths, resourceTypeFilePaths...)
+	}
+	return staleFilePaths, failed
+}
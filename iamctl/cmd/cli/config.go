@@ -0,0 +1,301 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage the CLI tool configs",
+	Long:  `You can manage the environment specific config files of the CLI tool`,
+}
+
+var encryptConfigCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt the server config file",
+	Long:  `You can encrypt the sensitive fields of the server config file using a passphrase`,
+	Example: `  # Encrypt the client secret in the server config file
+  iamctl config encrypt -c <config folder>
+
+  # Write the encrypted server configs to a different file
+  iamctl config encrypt -c <config folder> -o <config folder>/serverConfig.encrypted.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFolder, _ := cmd.Flags().GetString("config")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		transformServerConfigFile(configFolder, outputFile, utils.EncryptServerConfigs)
+		log.Println("Server config file encrypted successfully.")
+	},
+}
+
+var decryptConfigCmd = &cobra.Command{
+	Use:   "decrypt",
+	Short: "Decrypt the server config file",
+	Long:  `You can decrypt the sensitive fields of an encrypted server config file using the passphrase`,
+	Example: `  # Decrypt the client secret in the server config file
+  iamctl config decrypt -c <config folder>
+
+  # Write the decrypted server configs to a different file
+  iamctl config decrypt -c <config folder> -o /tmp/serverConfig.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFolder, _ := cmd.Flags().GetString("config")
+		outputFile, _ := cmd.Flags().GetString("output")
+
+		transformServerConfigFile(configFolder, outputFile, utils.DecryptServerConfigs)
+		log.Println("Server config file decrypted successfully.")
+	},
+}
+
+var initConfigCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create the server config file interactively",
+	Long: `You can create the server config file of an environment by entering the server details, ` +
+		`after testing the connection to the server`,
+	Example: `  # Create the config files of a new environment
+  iamctl config init -c <base directory>/configs/staging`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFolder, _ := cmd.Flags().GetString("config")
+
+		initConfigFolder(configFolder)
+	},
+}
+
+var useProfileCmd = &cobra.Command{
+	Use:   "use-profile <name>",
+	Short: "Set the default server config profile",
+	Long: `You can set the profile of the profiles file that is used when neither the --config-profile flag ` +
+		`nor the --config flag is set`,
+	Example: `  # Use the prod profile by default
+  iamctl config use-profile prod`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := utils.UseConfigProfile(args[0]); err != nil {
+			log.Fatalln("Error when setting the default profile.", err)
+		}
+		log.Println("Default profile set to: " + args[0])
+	},
+}
+
+var listProfilesCmd = &cobra.Command{
+	Use:   "list-profiles",
+	Short: "List the server config profiles",
+	Long:  `You can list the profiles of the profiles file, with the default profile marked with an asterisk`,
+	Example: `  # List the profiles of the profiles file
+  iamctl config list-profiles`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		names, currentProfile, err := utils.ListConfigProfiles()
+		if err != nil {
+			log.Fatalln("Error when listing the profiles.", err)
+		}
+		for _, name := range names {
+			if name == currentProfile {
+				fmt.Println("* " + name)
+			} else {
+				fmt.Println("  " + name)
+			}
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(encryptConfigCmd)
+	configCmd.AddCommand(decryptConfigCmd)
+	configCmd.AddCommand(initConfigCmd)
+	configCmd.AddCommand(useProfileCmd)
+	configCmd.AddCommand(listProfilesCmd)
+	initConfigCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder to be created")
+	initConfigCmd.MarkFlagRequired("config")
+
+	for _, subCmd := range []*cobra.Command{encryptConfigCmd, decryptConfigCmd} {
+		subCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+		subCmd.Flags().StringP("output", "o", "", "Path to the output file. Defaults to the server config file")
+		subCmd.MarkFlagRequired("config")
+	}
+}
+
+func transformServerConfigFile(configFolder string, outputFile string, transform func([]byte, string) ([]byte, error)) {
+
+	serverConfigFile := filepath.Join(configFolder, utils.SERVER_CONFIG_FILE)
+	if outputFile == "" {
+		outputFile = serverConfigFile
+	}
+
+	configFile, err := ioutil.ReadFile(serverConfigFile)
+	if err != nil {
+		log.Fatalln("Error when reading the server config file.", err)
+	}
+	passphrase, err := utils.GetConfigPassphrase()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	transformedConfigFile, err := transform(configFile, passphrase)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	err = ioutil.WriteFile(outputFile, transformedConfigFile, 0600)
+	if err != nil {
+		log.Fatalln("Error when writing the server config file.", err)
+	}
+}
+
+func initConfigFolder(configFolder string) {
+
+	reader := utils.GetStdinReader()
+	serverConfigs := utils.ServerConfigs{
+		ServerUrl:    strings.TrimSuffix(promptConfig(reader, "Server URL", ""), "/"),
+		ClientId:     promptConfig(reader, "Client ID", ""),
+		ClientSecret: promptSecretConfig(reader, "Client secret"),
+		TenantDomain: promptConfig(reader, "Tenant domain", utils.DEFAULT_TENANT_DOMAIN),
+	}
+
+	log.Println("Testing the connection to the server: " + serverConfigs.ServerUrl)
+	if err := utils.CheckServerConnection(serverConfigs); err != nil {
+		fmt.Println("Connection test failed.", err)
+		if !promptConfirmation(reader, "Save the server config anyway?") {
+			log.Fatalln("Server config file is not saved.")
+		}
+	} else {
+		log.Println("Connection test succeeded.")
+	}
+
+	if err := os.MkdirAll(configFolder, 0700); err != nil {
+		log.Fatalln("Error when creating the config folder.", err)
+	}
+	serverConfigFile := filepath.Join(configFolder, utils.SERVER_CONFIG_FILE)
+	serverConfigJson, err := json.MarshalIndent(map[string]string{
+		utils.SERVER_URL_CONFIG:    serverConfigs.ServerUrl,
+		utils.CLIENT_ID_CONFIG:     serverConfigs.ClientId,
+		utils.CLIENT_SECRET_CONFIG: serverConfigs.ClientSecret,
+		utils.TENANT_DOMAIN_CONFIG: serverConfigs.TenantDomain,
+	}, "", "  ")
+	if err != nil {
+		log.Fatalln("Error when creating the server config file.", err)
+	}
+	writeConfigFile(reader, serverConfigFile, serverConfigJson, 0600)
+
+	// The tool config file is optional but expected to exist in the config folder.
+	toolConfigFile := filepath.Join(configFolder, utils.TOOL_CONFIG_FILE)
+	if _, err := os.Stat(toolConfigFile); os.IsNotExist(err) {
+		writeConfigFile(reader, toolConfigFile, []byte{}, 0644)
+	}
+
+	keywordConfigFile := filepath.Join(configFolder, utils.KEYWORD_CONFIG_FILE)
+	if promptConfirmation(reader, "Generate a starter keyword config?") {
+		writeConfigFile(reader, keywordConfigFile, getStarterKeywordConfig(serverConfigs.ServerUrl), 0644)
+	} else if _, err := os.Stat(keywordConfigFile); os.IsNotExist(err) {
+		writeConfigFile(reader, keywordConfigFile, []byte{}, 0644)
+	}
+	log.Println("Config folder created successfully at: " + configFolder)
+}
+
+// Map the host of the server to a keyword, as a starting point for the environment specific values.
+func getStarterKeywordConfig(serverUrl string) []byte {
+
+	keywordMappings := map[string]string{}
+	if parsedUrl, err := url.Parse(serverUrl); err == nil && parsedUrl.Host != "" {
+		keywordMappings["SERVER_HOST"] = parsedUrl.Host
+	}
+	keywordConfigJson, _ := json.MarshalIndent(map[string]interface{}{
+		"KEYWORD_MAPPINGS": keywordMappings,
+	}, "", "  ")
+	return keywordConfigJson
+}
+
+func writeConfigFile(reader *bufio.Reader, filePath string, content []byte, perm os.FileMode) {
+
+	if _, err := os.Stat(filePath); err == nil {
+		if !promptConfirmation(reader, "The file "+filePath+" already exists. Overwrite?") {
+			log.Println("Skipped writing the file: " + filePath)
+			return
+		}
+	}
+	if err := ioutil.WriteFile(filePath, content, perm); err != nil {
+		log.Fatalln("Error when writing the file "+filePath+".", err)
+	}
+	log.Println("Config file written: " + filePath)
+}
+
+func promptConfig(reader *bufio.Reader, name string, defaultValue string) string {
+
+	for {
+		if defaultValue != "" {
+			fmt.Printf("%s [%s]: ", name, defaultValue)
+		} else {
+			fmt.Printf("%s: ", name)
+		}
+		value, err := reader.ReadString('\n')
+		value = strings.TrimSpace(value)
+		if value == "" {
+			value = defaultValue
+		}
+		if value != "" {
+			return value
+		}
+		if err != nil {
+			log.Fatalf("Error when reading the %s. %s", strings.ToLower(name), err)
+		}
+		fmt.Println(name + " cannot be empty.")
+	}
+}
+
+func promptSecretConfig(reader *bufio.Reader, name string) string {
+
+	// Read the input without masking when it is piped, since it is not echoed in that case.
+	if !terminal.IsTerminal(int(syscall.Stdin)) {
+		return promptConfig(reader, name, "")
+	}
+	for {
+		fmt.Printf("%s: ", name)
+		value, err := terminal.ReadPassword(int(syscall.Stdin))
+		fmt.Println()
+		if err != nil {
+			log.Fatalf("Error when reading the %s. %s", strings.ToLower(name), err)
+		}
+		if len(value) > 0 {
+			return string(value)
+		}
+		fmt.Println(name + " cannot be empty.")
+	}
+}
+
+func promptConfirmation(reader *bufio.Reader, question string) bool {
+
+	fmt.Print(question + " (yes/no): ")
+	answer, _ := reader.ReadString('\n')
+	return utils.IsConfirmed(answer)
+}
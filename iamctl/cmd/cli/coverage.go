@@ -0,0 +1,74 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Report the resources not managed by the tool",
+	Long: `You can compare the number of resources of each resource type in the target environment with the resources
+in the local directory, and list the resource types that are not supported by the tool`,
+	Example: `  # Report the coverage of the local directory of the config folder
+  iamctl coverage -c <config folder>
+
+  # Report the coverage of a local directory for an environment section
+  iamctl coverage -c <config folder> --env <environment> --encrypted-config -i <base directory>`,
+	Run: func(cmd *cobra.Command, args []string) {
+		inputDirPath, _ := cmd.Flags().GetString("inputDir")
+		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		baseDir := utils.LoadConfigs(configFile)
+		if inputDirPath == "" {
+			inputDirPath = baseDir
+		}
+		managedCounts, err := utils.GetLocalManagedCounts(inputDirPath)
+		if err != nil {
+			log.Fatalln("Error when reading the local resources.", err)
+		}
+		utils.PrintCoverage(utils.GetResourceCoverage(managedCounts))
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(coverageCmd)
+	coverageCmd.Flags().StringP("inputDir", "i", "", "Path to the local resource directory")
+	coverageCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	coverageCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	coverageCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+}
+
+// Print the coverage of the resources processed in the run after the summary of the run.
+func printRunCoverage() {
+
+	fmt.Println("========================================")
+	fmt.Println("Coverage:")
+	fmt.Println("========================================")
+	utils.PrintCoverage(utils.GetResourceCoverage(utils.GetRunManagedCounts()))
+}
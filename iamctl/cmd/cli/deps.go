@@ -0,0 +1,73 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var depsCmd = &cobra.Command{
+	Use:   "deps",
+	Short: "Print the dependencies of a local resource",
+	Long: `You can print the identity providers, claims, API resources, scopes and secrets referenced by a local ` +
+		`application or identity provider, or the local resources that reference a given resource. The dependencies ` +
+		`of all resources are written to the ` + utils.DEPENDENCIES_FILE + ` file when exporting and planning an import`,
+	Example: `  # Print the dependency tree of an application
+  iamctl deps -i <base directory> --resource crm
+
+  # Print the local resources that reference an identity provider
+  iamctl deps -i <base directory> --resource Google --reverse`,
+	Run: func(cmd *cobra.Command, args []string) {
+		inputDirPath, _ := cmd.Flags().GetString("inputDir")
+		resourceName, _ := cmd.Flags().GetString("resource")
+		reverse, _ := cmd.Flags().GetBool("reverse")
+
+		if resourceName == "" {
+			log.Fatalln("The --resource flag is required.")
+		}
+		graph, err := utils.BuildDependencyGraph(inputDirPath)
+		if err != nil {
+			log.Fatalln("Error when reading the dependencies of the local resources.", err)
+		}
+		var output string
+		if reverse {
+			output, err = utils.FormatReverseDependencies(graph, resourceName)
+		} else {
+			output, err = utils.FormatDependencyTree(graph, resourceName)
+		}
+		if err != nil {
+			log.Fatalln(err)
+		}
+		fmt.Print(output)
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(depsCmd)
+	depsCmd.Flags().StringP("inputDir", "i", ".", "Path to the folder containing the resource files")
+	depsCmd.Flags().String("resource", "", "Name of the resource. An application or identity provider name, or with "+
+		"--reverse, also a claim URI, API resource identifier, scope or secret keyword")
+	depsCmd.Flags().Bool("reverse", false, "Print the local resources that reference the resource")
+}
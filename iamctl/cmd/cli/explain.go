@@ -0,0 +1,183 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Placeholders in the command examples, replaced with the values found in the local directory by the explain command.
+const EXAMPLE_BASE_DIR = "<base directory>"
+const EXAMPLE_CONFIG_FOLDER = "<config folder>"
+const EXAMPLE_OTHER_CONFIG_FOLDER = "<other config folder>"
+const EXAMPLE_ENVIRONMENT = "<environment>"
+const EXAMPLE_OTHER_ENVIRONMENT = "<other environment>"
+
+var examplePlaceholderPattern = regexp.MustCompile(`<[a-z ]+>`)
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <command>",
+	Short: "Explain a command in detail",
+	Long: `You can print the flags of a command with the values resolved from the config files of the local directory, ` +
+		`the precedence of the configs, and examples based on the config folders of the local directory`,
+	Example: `  # Explain the importAll command using the config folders in the current directory
+  iamctl explain importAll
+
+  # Explain a subcommand using the config folders of a different local directory
+  iamctl explain keywords lint -d <base directory>`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(command *cobra.Command, args []string) {
+		baseDirPath, _ := command.Flags().GetString("baseDir")
+
+		targetCmd, remainingArgs, err := cmd.RootCmd.Find(args)
+		if err != nil || len(remainingArgs) > 0 || targetCmd == cmd.RootCmd {
+			log.Fatalln("Unknown command: " + strings.Join(args, " "))
+		}
+		configFolders, err := utils.DiscoverConfigFolders(baseDirPath)
+		if err != nil {
+			log.Println("Error when reading the config folders.", err)
+		}
+		fmt.Print(explainCommand(targetCmd, baseDirPath, configFolders))
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(explainCmd)
+	explainCmd.Flags().StringP("baseDir", "d", ".", "Path to the local directory that contains the configs folder")
+}
+
+func explainCommand(command *cobra.Command, baseDirPath string, configFolders []utils.ConfigFolder) string {
+
+	var explanation strings.Builder
+	fmt.Fprintf(&explanation, "%s\n\n%s\n\nUsage:\n  %s\n", command.CommandPath(), command.Long, command.UseLine())
+
+	explanation.WriteString("\nFlags:\n")
+	command.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+		if flag.Name == "help" {
+			return
+		}
+		flagName := "      --" + flag.Name
+		if flag.Shorthand != "" {
+			flagName = "  -" + flag.Shorthand + ", --" + flag.Name
+		}
+		if flag.Value.Type() != "bool" {
+			flagName += " " + flag.Value.Type()
+		}
+		fmt.Fprintf(&explanation, "%s\n          %s\n", flagName, flag.Usage)
+		defaultValue := flag.DefValue
+		if defaultValue == "" || defaultValue == "[]" {
+			defaultValue = "none"
+		}
+		fmt.Fprintf(&explanation, "          Default: %s\n", defaultValue)
+		if resolvedValue := resolveFlagValue(flag, baseDirPath, configFolders); resolvedValue != "" {
+			fmt.Fprintf(&explanation, "          Resolved: %s\n", resolvedValue)
+		}
+	})
+
+	if command.Flags().Lookup("config") != nil || command.Flags().Lookup("from") != nil {
+		explanation.WriteString("\nPrecedence of the configs, highest first:\n")
+		for i, source := range utils.CONFIG_PRECEDENCE {
+			fmt.Fprintf(&explanation, "  %d. %s\n", i+1, source)
+		}
+	}
+
+	if command.Example != "" {
+		examples := getResolvedExamples(command.Example, baseDirPath, configFolders)
+		fmt.Fprintf(&explanation, "\nExamples:\n%s\n", examples)
+		if examplePlaceholderPattern.MatchString(examples) {
+			fmt.Fprintf(&explanation, "\nThe values in angle brackets could not be resolved from %s. Use the setupCLI "+
+				"command to create the config folders.\n", filepath.Join(baseDirPath, utils.CONFIGS_DIR_NAME))
+		}
+	}
+	return explanation.String()
+}
+
+// Describe the value a flag takes when it is not set, based on the config folders of the local directory.
+func resolveFlagValue(flag *pflag.Flag, baseDirPath string, configFolders []utils.ConfigFolder) string {
+
+	switch flag.Name {
+	case "config", "from", "to":
+		if len(configFolders) == 0 {
+			if flag.Name == "config" {
+				return "No config folders found. The server configs are read from the environment variables."
+			}
+			return ""
+		}
+		var configFolderPaths []string
+		for _, configFolder := range configFolders {
+			configFolderPaths = append(configFolderPaths, configFolder.Path)
+		}
+		return "Config folders found: " + strings.Join(configFolderPaths, ", ")
+	case "env", "from-env", "to-env":
+		if environment := os.Getenv(utils.IAMCTL_ENV_CONFIG); environment != "" && flag.Name == "env" {
+			return "Set by the " + utils.IAMCTL_ENV_CONFIG + " environment variable: " + environment
+		}
+		var environments []string
+		for _, configFolder := range configFolders {
+			for _, environment := range configFolder.Environments {
+				environments = append(environments, fmt.Sprintf("%s (%s)", environment, configFolder.Path))
+			}
+		}
+		if len(environments) == 0 {
+			return ""
+		}
+		return "Environment sections found: " + strings.Join(environments, ", ")
+	case "inputDir", "outputDir":
+		if flag.DefValue != "" || len(configFolders) == 0 {
+			return ""
+		}
+		return "Defaults to the local directory of the config folder: " + utils.GetBaseDir(configFolders[0].Path)
+	case "key-env":
+		if os.Getenv(flag.DefValue) == "" {
+			return "The " + flag.DefValue + " environment variable is not set"
+		}
+		return "The " + flag.DefValue + " environment variable is set"
+	}
+	return ""
+}
+
+// Replace the placeholders of the examples with the config folders and the environments of the local directory.
+func getResolvedExamples(examples string, baseDirPath string, configFolders []utils.ConfigFolder) string {
+
+	replacements := []string{EXAMPLE_BASE_DIR, baseDirPath}
+	if len(configFolders) > 0 {
+		replacements = append(replacements, EXAMPLE_CONFIG_FOLDER, configFolders[0].Path)
+		if len(configFolders[0].Environments) > 0 {
+			replacements = append(replacements, EXAMPLE_ENVIRONMENT, configFolders[0].Environments[0])
+		}
+		if len(configFolders[0].Environments) > 1 {
+			replacements = append(replacements, EXAMPLE_OTHER_ENVIRONMENT, configFolders[0].Environments[1])
+		}
+	}
+	if len(configFolders) > 1 {
+		replacements = append(replacements, EXAMPLE_OTHER_CONFIG_FOLDER, configFolders[1].Path)
+	}
+	return strings.NewReplacer(replacements...).Replace(examples)
+}
@@ -0,0 +1,178 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/users"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	xacmlpolicies "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/xacmlPolicies"
+)
+
+var exportViewCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export views of resources",
+	Long:  `You can export cross-reference views of the resources available in the target environment`,
+}
+
+var rolesByAppCmd = &cobra.Command{
+	Use:   "roles-by-app",
+	Short: "Export roles grouped by application",
+	Long:  `You can export the roles associated with each application in the target environment into a single file`,
+	Example: `  # Export the roles of each application
+  iamctl export roles-by-app -c <config folder> -o <base directory>/reports
+
+  # Export the roles of each application in JSON format from an environment section
+  iamctl export roles-by-app -c <config folder> --env <environment> --encrypted-config --output json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputDirPath, _ := cmd.Flags().GetString("outputDir")
+		format, _ := cmd.Flags().GetString("output")
+		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		baseDir := utils.LoadConfigs(configFile)
+		if outputDirPath == "" {
+			outputDirPath = baseDir
+		}
+
+		err := applications.ExportRolesByApp(outputDirPath, format)
+		if err != nil {
+			log.Fatalln("Error when exporting roles by application.", err)
+		}
+	},
+}
+
+var exportUsersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Export the user accounts",
+	Long: `You can export the user accounts of the target environment for auditing. ` +
+		`Passwords and security questions are never exported`,
+	Example: `  # Export the users that match a filter
+  iamctl export users -c <config folder> -o <base directory> --filter "userName sw dev"
+
+  # Export the users modified after a given time from an environment section
+  iamctl export users -c <config folder> --env <environment> --encrypted-config --since 2024-01-31T00:00:00Z
+
+  # Export the users without the attributes that have null values
+  iamctl export users -c <config folder> -o <base directory> --omit-null
+
+  # Export the users with the keys of the attributes sorted alphabetically
+  iamctl export users -c <config folder> -o <base directory> --sort-keys alphabetical`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputDirPath, _ := cmd.Flags().GetString("outputDir")
+		configFile, _ := cmd.Flags().GetString("config")
+		filter, _ := cmd.Flags().GetString("filter")
+		utils.OMIT_NULL, _ = cmd.Flags().GetBool("omit-null")
+		utils.KEY_ORDER, _ = cmd.Flags().GetString("sort-keys")
+		if err := utils.ValidateKeyOrder(utils.KEY_ORDER); err != nil {
+			log.Fatalln(err)
+		}
+		readSinceFlag(cmd)
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		baseDir := utils.LoadConfigs(configFile)
+		if outputDirPath == "" {
+			outputDirPath = baseDir
+		}
+
+		err := users.ExportAll(outputDirPath, filter)
+		if err != nil {
+			log.Fatalln("Error when exporting users.", err)
+		}
+		utils.PrintSummary(utils.EXPORT)
+	},
+}
+
+var exportXacmlPoliciesCmd = &cobra.Command{
+	Use:   "xacml-policies",
+	Short: "Export the XACML policies",
+	Long:  `You can export the XACML policies of the target environment along with a manifest of the policies`,
+	Example: `  # Export the XACML policies
+  iamctl export xacml-policies -c <config folder> -o <base directory>
+
+  # Export the XACML policies modified after a given time from an environment section
+  iamctl export xacml-policies -c <config folder> --env <environment> --encrypted-config --since 2024-01-31T00:00:00Z`,
+	Run: func(cmd *cobra.Command, args []string) {
+		outputDirPath, _ := cmd.Flags().GetString("outputDir")
+		configFile, _ := cmd.Flags().GetString("config")
+		readSinceFlag(cmd)
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		baseDir := utils.LoadConfigs(configFile)
+		if outputDirPath == "" {
+			outputDirPath = baseDir
+		}
+
+		err := xacmlpolicies.ExportAll(outputDirPath)
+		if err != nil {
+			log.Fatalln("Error when exporting XACML policies.", err)
+		}
+		utils.PrintSummary(utils.EXPORT)
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(exportViewCmd)
+	exportViewCmd.AddCommand(rolesByAppCmd)
+	exportViewCmd.AddCommand(exportUsersCmd)
+	exportViewCmd.AddCommand(exportXacmlPoliciesCmd)
+	rolesByAppCmd.Flags().StringP("outputDir", "o", "", "Path to the output directory")
+	rolesByAppCmd.Flags().String("output", "yaml", "Format of the exported file: yaml or json")
+	rolesByAppCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	rolesByAppCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	rolesByAppCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+
+	exportUsersCmd.Flags().StringP("outputDir", "o", "", "Path to the output directory")
+	exportUsersCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	exportUsersCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	exportUsersCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	exportUsersCmd.Flags().Bool("omit-null", false, "Remove the fields with null values from the exported YAML files")
+	exportUsersCmd.Flags().String("sort-keys", utils.KEY_ORDER_NONE, "Order of the keys in the exported YAML files: "+
+		strings.Join(utils.KEY_ORDERS, ", "))
+	exportUsersCmd.Flags().String("filter", "", "SCIM filter to select the users to be exported. Ex: userName sw \"dev\"")
+	addSinceFlag(exportUsersCmd)
+
+	exportXacmlPoliciesCmd.Flags().StringP("outputDir", "o", "", "Path to the output directory")
+	exportXacmlPoliciesCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	exportXacmlPoliciesCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	exportXacmlPoliciesCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	addSinceFlag(exportXacmlPoliciesCmd)
+}
+
+func addSinceFlag(command *cobra.Command) {
+
+	command.Flags().String("since", "", "Export only the resources modified after the given RFC3339 time, for the resource types with a modification time. Ex: 2024-01-31T00:00:00Z")
+}
+
+func readSinceFlag(command *cobra.Command) {
+
+	since, _ := command.Flags().GetString("since")
+	if err := utils.ParseSince(since); err != nil {
+		log.Fatalln(err)
+	}
+}
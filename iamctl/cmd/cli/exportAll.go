@@ -19,12 +19,16 @@
 package cli
 
 import (
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
 	"github.com/spf13/cobra"
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
-	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
-	claims "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/claims"
-	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
-	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 )
 
@@ -32,22 +36,167 @@ var exportAllCmd = &cobra.Command{
 	Use:   "exportAll",
 	Short: "Export all applications",
 	Long:  `You can export all applications available in the target environment`,
+	Example: `  # Export all resources to the resource directory
+  iamctl exportAll -c <config folder> -o <base directory>
+
+  # Export the resources of an environment section in JSON format after printing the resolved configs
+  iamctl exportAll -c <config folder> --env <environment> --encrypted-config --show-config -f json
+
+  # Export the applications and identity providers of a sub organization to the Organizations/<name> folder
+  iamctl exportAll -c <config folder> -o <base directory> --org partner-org
+
+  # Export the resources of each tenant in the TENANTS server config to <base directory>/<tenant domain>
+  iamctl exportAll -c <config folder> -o <base directory> --all-tenants
+
+  # Export all resources without the fields that have null values
+  iamctl exportAll -c <config folder> -o <base directory> --omit-null
+
+  # Export all resources with the identifying keys of each mapping first and the other keys sorted alphabetically
+  iamctl exportAll -c <config folder> -o <base directory> --sort-keys canonical
+
+  # Export all resources for a security review, with the certificates replaced by their subject, issuer and expiry
+  iamctl exportAll -c <config folder> -o <base directory>/review --exclude-certs
+
+  # Export all resources including the system applications, such as the Console
+  iamctl exportAll -c <config folder> -o <base directory> --include-system-apps
+
+  # Export all resources along with an OpenAPI spec of the API scopes of each OAuth2 application
+  iamctl exportAll -c <config folder> -o <base directory> --generate-openapi-specs
+
+  # Export all resources and report the resources of the target environment that are not exported
+  iamctl exportAll -c <config folder> --coverage
+
+  # Export all resources of a tenant with many identity providers, fetching 10 identity providers in parallel
+  iamctl exportAll -c <config folder> -o <base directory> --concurrency 10
+
+  # Export only the resources of a team, named with the team prefix
+  iamctl exportAll -c <config folder> -o <base directory> --namespace team-a-
+
+  # Export all resources and remove the application and identity provider files of the resources deleted on the server
+  iamctl exportAll -c <config folder> -o <base directory> --prune
+
+  # Export all resources with the server configuration of each application in a .baseline file to detect conflicts on import
+  iamctl exportAll -c <config folder> -o <base directory> --baseline
+
+  # Keep the local files in sync with the server, polling every 30 seconds
+  iamctl exportAll -c <config folder> --watch --interval 30
+
+  # Export all resources to a git repository and record the changes in the CHANGELOG.yaml file
+  iamctl exportAll -c <config folder> -o <git repository> --git-log
+
+  # Export only the changed resources modified after a given time, within a time budget, in CI
+  iamctl exportAll -c <config folder> --only-changed --since 2024-01-31T00:00:00Z --gzip --prefix-sensitive-comments \
+    --strict --ignore-warning masked-secret --time-budget 10m --progress-socket /tmp/iamctl.sock \
+    --no-progress --junit-report reports/iamctl-export.xml`,
 	Run: func(cmd *cobra.Command, args []string) {
 		outputDirPath, _ := cmd.Flags().GetString("outputDir")
 		format, _ := cmd.Flags().GetString("format")
 		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.SHOW_CONFIG, _ = cmd.Flags().GetBool("show-config")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+		utils.ALL_TENANTS, _ = cmd.Flags().GetBool("all-tenants")
+		utils.ORGANIZATION, _ = cmd.Flags().GetString("org")
+		onlyChanged, _ := cmd.Flags().GetBool("only-changed")
+		gitLog, _ := cmd.Flags().GetBool("git-log")
+		utils.GZIP_EXPORT, _ = cmd.Flags().GetBool("gzip")
+		utils.PREFIX_SENSITIVE_COMMENTS, _ = cmd.Flags().GetBool("prefix-sensitive-comments")
+		utils.OMIT_NULL, _ = cmd.Flags().GetBool("omit-null")
+		utils.KEY_ORDER, _ = cmd.Flags().GetString("sort-keys")
+		if err := utils.ValidateKeyOrder(utils.KEY_ORDER); err != nil {
+			log.Fatalln(err)
+		}
+		utils.EXCLUDE_CERTS, _ = cmd.Flags().GetBool("exclude-certs")
+		utils.INCLUDE_SYSTEM_APPS, _ = cmd.Flags().GetBool("include-system-apps")
+		utils.GENERATE_OPENAPI_SPECS, _ = cmd.Flags().GetBool("generate-openapi-specs")
+		utils.PRUNE_EXPORT, _ = cmd.Flags().GetBool("prune")
+		utils.WRITE_BASELINE, _ = cmd.Flags().GetBool("baseline")
+		timeBudget, _ := cmd.Flags().GetDuration("time-budget")
+		watch, _ := cmd.Flags().GetBool("watch")
+		interval, _ := cmd.Flags().GetInt("interval")
+		coverage, _ := cmd.Flags().GetBool("coverage")
+		concurrency, _ := cmd.Flags().GetInt("concurrency")
+		readWarningFlags(cmd)
+		readProgressFlag(cmd)
+		readJUnitReportFlag(cmd, utils.EXPORT)
+		readSinceFlag(cmd)
 
+		if watch && timeBudget > 0 {
+			log.Fatalln("The --time-budget flag cannot be used with the --watch flag.")
+		}
+		if watch && utils.ALL_TENANTS {
+			log.Fatalln("The --all-tenants flag cannot be used with the --watch flag.")
+		}
+		if interval <= 0 {
+			log.Fatalln("The --interval flag should be a positive number of seconds.")
+		}
+		if concurrency <= 0 {
+			log.Fatalln("The --concurrency flag should be a positive number.")
+		}
+		if concurrency > utils.MAX_EXPORT_CONCURRENCY {
+			log.Printf("The --concurrency flag is capped at %d to avoid flooding the server.", utils.MAX_EXPORT_CONCURRENCY)
+			concurrency = utils.MAX_EXPORT_CONCURRENCY
+		}
+		utils.EXPORT_CONCURRENCY = concurrency
+		if utils.WRITE_BASELINE {
+			log.Println("Baseline files are written for the applications only. Conflicts of the other resource types are not detected on import.")
+		}
+		utils.StartTimeBudget(timeBudget)
 		baseDir := utils.LoadConfigs(configFile)
 		if outputDirPath == "" {
 			outputDirPath = baseDir
 		}
+		utils.StartProgress(utils.EXPORT)
+		if onlyChanged {
+			if utils.IsGitRepository(outputDirPath) {
+				utils.ONLY_CHANGED = true
+			} else {
+				utils.LogWarning(utils.WARNING_CONFIG, "Output directory is not inside a git repository. Ignoring the --only-changed flag.")
+			}
+		}
+		if gitLog {
+			if utils.IsGitRepository(outputDirPath) {
+				utils.GIT_LOG = true
+			} else {
+				utils.LogWarning(utils.WARNING_CONFIG, "Output directory is not inside a git repository. Ignoring the --git-log flag.")
+			}
+		}
 
-		claims.ExportAll(outputDirPath, format)
-		identityproviders.ExportAll(outputDirPath, format)
-		applications.ExportAll(outputDirPath, format)
-		userstores.ExportAll(outputDirPath, format)
+		if utils.ALL_TENANTS {
+			results := utils.RunForEachTenant(outputDirPath, func(tenantDir string) error {
+				exportAllResources(utils.GetOrganizationDir(tenantDir), format)
+				utils.PrintSummary(utils.EXPORT)
+				if coverage {
+					printRunCoverage()
+				}
+				return nil
+			})
+			utils.PrintTenantSummary(results)
+			writeChangeLogEntry(outputDirPath)
+			utils.FinishProgress(utils.EXPORT)
+			utils.ExitIfInterrupted()
+			utils.ExitIfStrictWarnings()
+			utils.ExitIfTenantFailed(results)
+			utils.ExitIfIncomplete()
+			return
+		}
+		outputDirPath = utils.GetOrganizationDir(outputDirPath)
+		if watch {
+			watchExport(outputDirPath, format, interval)
+			return
+		}
+		exportAllResources(outputDirPath, format)
+		writeChangeLogEntry(outputDirPath)
 
 		utils.PrintSummary(utils.EXPORT)
+		if coverage {
+			printRunCoverage()
+		}
+		utils.FinishProgress(utils.EXPORT)
+		utils.ExitIfInterrupted()
+		utils.ExitIfStrictWarnings()
+		utils.ExitIfIncomplete()
 	},
 }
 
@@ -57,4 +206,107 @@ func init() {
 	exportAllCmd.Flags().StringP("outputDir", "o", "", "Path to the output directory")
 	exportAllCmd.Flags().StringP("format", "f", "yaml", "Format of the exported files")
 	exportAllCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	exportAllCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	exportAllCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	exportAllCmd.Flags().Bool("show-config", false, "Print the resolved configs with secrets masked")
+	exportAllCmd.Flags().String("org", "", "Name of the sub organization to be managed instead of the root organization")
+	exportAllCmd.Flags().Bool("all-tenants", false, "Export the resources of each tenant in the TENANTS server config to a folder per tenant")
+	exportAllCmd.Flags().String("namespace", "", "Manage only the resources with names starting with the given prefix")
+	exportAllCmd.Flags().Bool("only-changed", false, "Write only the resources that differ from the last git commit")
+	exportAllCmd.Flags().Bool("git-log", false, "Append an entry with the number of changed resources to "+utils.CHANGE_LOG_FILE+" in the git repository")
+	exportAllCmd.Flags().Bool("gzip", false, "Compress each exported file with gzip")
+	exportAllCmd.Flags().Bool("prefix-sensitive-comments", false, "Add a comment with the sensitivity level to each exported YAML file")
+	exportAllCmd.Flags().Bool("omit-null", false, "Remove the fields with null values from the exported YAML files")
+	exportAllCmd.Flags().String("sort-keys", utils.KEY_ORDER_NONE, "Order of the keys in the exported YAML files: "+
+		strings.Join(utils.KEY_ORDERS, ", "))
+	exportAllCmd.Flags().Bool("include-system-apps", false, "Export and import the system applications, such as the Console and My Account applications")
+	exportAllCmd.Flags().Bool("exclude-certs", false, "Replace the certificates in the exported YAML files with a placeholder and a comment with the certificate details")
+	exportAllCmd.Flags().Bool("generate-openapi-specs", false, "Generate an OpenAPI spec from the authorized API scopes of each OAuth2 application")
+	exportAllCmd.Flags().Bool("prune", false, "Remove the application and identity provider files of the resources deleted on the server")
+	exportAllCmd.Flags().Bool("baseline", false, "Write the server configuration of each application to a .baseline file to detect conflicts on import (other resource types are not supported)")
+	addWarningFlags(exportAllCmd)
+	addProgressFlag(exportAllCmd)
+	addJUnitReportFlag(exportAllCmd)
+	addSinceFlag(exportAllCmd)
+	exportAllCmd.Flags().Duration("time-budget", 0, "Maximum duration of the run, after which the remaining resources are not processed")
+	exportAllCmd.Flags().Bool("watch", false, "Keep polling the server and update the local files of the changed resources")
+	exportAllCmd.Flags().Int("interval", utils.DEFAULT_WATCH_INTERVAL, "Polling interval in seconds of the --watch mode")
+	exportAllCmd.Flags().Bool("coverage", false, "Add the coverage of the resources in the target environment to the summary")
+	exportAllCmd.Flags().Int("concurrency", utils.DEFAULT_EXPORT_CONCURRENCY, "Number of identity providers fetched in parallel")
+}
+
+// Resource types in the order they are exported.
+var exportOrder = []string{utils.CLAIMS, utils.OIDC_SCOPES, utils.IDENTITY_PROVIDERS, utils.API_RESOURCES, utils.ROLES,
+	utils.APPLICATIONS, utils.ORGANIZATIONS, utils.USERSTORES, utils.EMAIL_TEMPLATES, utils.BRANDING,
+	utils.GOVERNANCE_CONNECTORS, utils.CORS, utils.NOTIFICATION_SENDERS, utils.WORKFLOWS}
+
+func exportAllResources(outputDirPath string, format string) {
+
+	// The client is created for each run, since the configs change between the tenants and the polls of the watch
+	// mode. The failed resources are reported in the summary, so the errors of the client are not logged again.
+	toolClient := client.NewFromLoadedConfigs()
+	options := client.ExportOptions{Format: format, Prune: utils.PRUNE_EXPORT, OpenApiSpecs: utils.GENERATE_OPENAPI_SPECS}
+	for _, resourceType := range exportOrder {
+		toolClient.ExportResources(utils.GetRequestContext(), resourceType, outputDirPath, options)
+	}
+
+	if err := utils.WriteDependencyFile(outputDirPath); err != nil {
+		log.Println("Error when writing the dependency file.", err)
+	}
+}
+
+func watchExport(outputDirPath string, format string, interval int) {
+
+	// Complete the resource being exported before stopping, so that no file is left partially written.
+	utils.StopInterruptHandling()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-stop
+		log.Println("Stopping the watch mode after the resource being exported.")
+		utils.RequestStop()
+	}()
+
+	utils.WATCH_EXPORT = true
+	stopKeywordWatch, err := utils.WatchKeywordConfigs()
+	if err != nil {
+		log.Println("Keyword configs are not reloaded when the keyword config file changes.", err)
+	} else {
+		defer stopKeywordWatch()
+	}
+	pollInterval := time.Duration(interval) * time.Second
+	log.Printf("Watching the server for changes every %s.", pollInterval)
+	for !utils.IsStopRequested() {
+		if !utils.IsServerAvailable() {
+			log.Printf("Server is not available at %s. Retrying in %s.", utils.SERVER_CONFIGS.ServerUrl, pollInterval)
+		} else if err := utils.RefreshAccessToken(); err != nil {
+			log.Printf("%s. Retrying in %s.", err, pollInterval)
+		} else {
+			utils.ResetSummary()
+			utils.HoldKeywordConfigs()
+			exportAllResources(outputDirPath, format)
+			utils.ReleaseKeywordConfigs()
+			writeChangeLogEntry(outputDirPath)
+			log.Printf("Export poll completed. Updated files: %d, failed operations: %d.",
+				utils.GetUpdatedFileCount(), utils.SummaryData.FailedOperations)
+		}
+		waitForNextPoll(pollInterval)
+	}
+	utils.PrintSummary(utils.EXPORT)
+	utils.FinishProgress(utils.EXPORT)
+}
+
+func writeChangeLogEntry(outputDirPath string) {
+
+	if err := utils.WriteChangeLogEntry(outputDirPath); err != nil {
+		log.Println("Error when writing the change log entry.", err)
+	}
+}
+
+func waitForNextPoll(pollInterval time.Duration) {
+
+	deadline := time.Now().Add(pollInterval)
+	for !utils.IsStopRequested() && time.Now().Before(deadline) {
+		time.Sleep(time.Second)
+	}
 }
@@ -0,0 +1,83 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var getCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Get a resource",
+	Long:  `You can print a resource of the target environment in the format of the exported files`,
+}
+
+var getIdpCmd = &cobra.Command{
+	Use:   "idp <name>",
+	Short: "Get an identity provider",
+	Long: `You can print an identity provider of the target environment with the sensitive fields masked. ` +
+		`The sensitive fields can be shown for authorized debugging, and the access is logged for audit purposes`,
+	Example: `  # Print an identity provider with the sensitive fields masked
+  iamctl get idp Google -c <config folder>
+
+  # Print an identity provider of an environment section with the sensitive fields, recording the access in the audit log
+  iamctl get idp Google -c <config folder> --env <environment> --encrypted-config --show-secrets --confirm-secret-access \
+    --audit-log /var/log/iamctl/audit.log`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, _ := cmd.Flags().GetString("config")
+		showSecrets, _ := cmd.Flags().GetBool("show-secrets")
+		confirmSecretAccess, _ := cmd.Flags().GetBool("confirm-secret-access")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		if showSecrets && !confirmSecretAccess {
+			log.Fatalln("The sensitive fields are shown only with the --confirm-secret-access flag, " +
+				"as the access is recorded for audit purposes.")
+		}
+		utils.LoadConfigs(configFile)
+		readAuditLogFlag(cmd)
+		defer utils.CloseAuditLog()
+
+		content, err := client.NewFromLoadedConfigs().GetIdp(utils.GetRequestContext(), args[0], showSecrets)
+		if err != nil {
+			utils.CloseAuditLog()
+			log.Fatalln("Error when getting the identity provider.", err)
+		}
+		fmt.Print(string(content))
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(getCmd)
+	getCmd.AddCommand(getIdpCmd)
+	getIdpCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	getIdpCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	getIdpCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	getIdpCmd.Flags().Bool("show-secrets", false, "Show the sensitive fields of the identity provider without masking")
+	getIdpCmd.Flags().Bool("confirm-secret-access", false, "Confirm the access to the sensitive fields shown with the --show-secrets flag")
+	addAuditLogFlag(getIdpCmd)
+}
@@ -0,0 +1,307 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Resource types referenced by the resources of each resource type, which are imported before the resource type.
+// Roles with an application audience are imported after the applications as pending roles.
+var importDependencies = map[string][]string{
+	utils.OIDC_SCOPES:        {utils.CLAIMS},
+	utils.IDENTITY_PROVIDERS: {utils.CLAIMS},
+	utils.ROLES:              {utils.API_RESOURCES},
+	utils.APPLICATIONS:       {utils.CLAIMS, utils.OIDC_SCOPES, utils.IDENTITY_PROVIDERS, utils.API_RESOURCES, utils.ROLES},
+	utils.ORGANIZATIONS:      {utils.APPLICATIONS},
+	utils.BRANDING:           {utils.APPLICATIONS, utils.ORGANIZATIONS},
+	utils.USERS:              {utils.CLAIMS, utils.ROLES, utils.USERSTORES},
+	utils.WORKFLOWS:          {utils.ROLES, utils.USERS},
+}
+
+// Resource types in the order they should be imported.
+var importOrder = getImportOrder()
+
+var importFilesCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import the given resource files",
+	Long:  `You can import selected resource files to the target environment`,
+	Example: `  # Import a single application file
+  iamctl import -c <config folder> -f Applications/hr-portal.yml
+
+  # Import files to an environment section even if unchanged since the last import
+  iamctl import -c <config folder> --env <environment> --encrypted-config -f Applications/hr-portal.yml,Roles/viewer.yml --force
+
+  # Import the customised Console application without confirmation
+  iamctl import -c <config folder> -f Applications/Console.yml --include-system-apps -y
+
+  # Import the files exported from a sub organization to the same sub organization
+  iamctl import -c <config folder> -f Organizations/partner-org/Applications/hr-portal.yml --org partner-org
+
+  # Import the files of a team, skipping the files of the resources without the team prefix
+  iamctl import -c <config folder> -f Applications/team-a-portal.yml,Applications/team-b-portal.yml --namespace team-a-
+
+  # Import an application that references identity providers managed outside the config folder
+  iamctl import -c <config folder> -f Applications/hr-portal.yml --continue-on-missing-deps
+
+  # Import the files of the permitted resource types, when the scopes of some resource types are not granted
+  iamctl import -c <config folder> -f Applications/hr-portal.yml,IdentityProviders/Google.yml --allow-partial-permissions
+
+  # Import in CI, failing on masked secrets and warnings
+  iamctl import -c <config folder> -f Applications/hr-portal.yml --abort-on-mask --strict --ignore-warning expiring-certificate \
+    --progress-socket /tmp/iamctl.sock --audit-log /var/log/iamctl/audit.log --preflight-timeout 30 \
+    --no-progress --backup /var/backups/iamctl --junit-report reports/iamctl-import.xml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		files, _ := cmd.Flags().GetStringSlice("file")
+		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.FORCE_IMPORT, _ = cmd.Flags().GetBool("force")
+		utils.ASSUME_YES, _ = cmd.Flags().GetBool("yes")
+		utils.INCLUDE_SYSTEM_APPS, _ = cmd.Flags().GetBool("include-system-apps")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+		utils.ORGANIZATION, _ = cmd.Flags().GetString("org")
+		readWarningFlags(cmd)
+		utils.ABORT_ON_MASK, _ = cmd.Flags().GetBool("abort-on-mask")
+		utils.ALLOW_PARTIAL_PERMISSIONS, _ = cmd.Flags().GetBool("allow-partial-permissions")
+		utils.CONTINUE_ON_MISSING_DEPS, _ = cmd.Flags().GetBool("continue-on-missing-deps")
+		readProgressFlag(cmd)
+		readJUnitReportFlag(cmd, utils.IMPORT)
+		readAuditLogFlag(cmd)
+		readBackupFlag(cmd)
+		defer utils.CloseAuditLog()
+
+		// Group the files by the resource type and the input directory resolved from the file path.
+		resourceFiles := make(map[string]map[string][]string)
+		for _, file := range files {
+			if _, err := os.Stat(file); err != nil {
+				log.Fatalln("Error when reading the file: " + file)
+			}
+			resourceTypeDir := filepath.Dir(file)
+			resourceName := utils.GetFileInfo(file).ResourceName
+			// Email templates are grouped in a folder per template type, so all locales of the template type are imported.
+			if filepath.Base(filepath.Dir(resourceTypeDir)) == utils.EMAIL_TEMPLATES {
+				resourceName = filepath.Base(resourceTypeDir)
+				resourceTypeDir = filepath.Dir(resourceTypeDir)
+			}
+			// Branding is grouped in a folder per organization or application, so all branding of the resource is imported.
+			if brandingDir, brandedResourceName := resolveBrandingFile(file); brandingDir != "" {
+				resourceName = brandedResourceName
+				resourceTypeDir = brandingDir
+			}
+			resourceType := filepath.Base(resourceTypeDir)
+			if !client.IsImportSupported(resourceType) {
+				log.Fatalf("Unable to resolve the resource type of the file: %s. "+
+					"The file should be inside a resource type folder such as %s.", file, utils.APPLICATIONS)
+			}
+			inputDirPath := filepath.Dir(resourceTypeDir)
+			if resourceFiles[resourceType] == nil {
+				resourceFiles[resourceType] = make(map[string][]string)
+			}
+			resourceFiles[resourceType][inputDirPath] = append(resourceFiles[resourceType][inputDirPath], resourceName)
+		}
+
+		if utils.ABORT_ON_MASK {
+			if err := utils.CheckMaskedSecrets(files); err != nil {
+				log.Fatalln("Aborting the import.", err)
+			}
+		}
+		if err := utils.CheckCertificatePlaceholders(files); err != nil {
+			log.Fatalln("Aborting the import.", err)
+		}
+
+		readPreflightFlag(cmd)
+		utils.LoadConfigs(configFile)
+		checkedDirs := make(map[string]bool)
+		for _, inputDirs := range resourceFiles {
+			for inputDirPath := range inputDirs {
+				if checkedDirs[inputDirPath] {
+					continue
+				}
+				checkedDirs[inputDirPath] = true
+				if err := utils.CheckTenantUrls(inputDirPath, files); err != nil {
+					log.Fatalln("Aborting the import.", err)
+				}
+			}
+		}
+		// Identity providers of the given files are available to the applications of the given files.
+		var localIdpNames []string
+		for _, idpNames := range resourceFiles[utils.IDENTITY_PROVIDERS] {
+			localIdpNames = append(localIdpNames, idpNames...)
+		}
+		getAvailableIdps := getAvailableIdpsFunc(nil, localIdpNames)
+		for inputDirPath, appNames := range resourceFiles[utils.APPLICATIONS] {
+			utils.INCLUDE_ONLY = appNames
+			if err := utils.CheckIdpDependencies(inputDirPath, getAvailableIdps); err != nil {
+				log.Fatalln("Aborting the import.", err)
+			}
+		}
+		utils.INCLUDE_ONLY = nil
+		var resourceTypes []string
+		for _, resourceType := range importOrder {
+			if len(resourceFiles[resourceType]) > 0 {
+				resourceTypes = append(resourceTypes, resourceType)
+			}
+		}
+		if err := utils.CheckResourcePermissions(resourceTypes); err != nil {
+			log.Fatalln("Aborting the import.", err)
+		}
+		utils.StartProgress(utils.IMPORT)
+		toolClient := client.NewFromLoadedConfigs()
+		for _, resourceType := range importOrder {
+			for inputDirPath, resourceNames := range resourceFiles[resourceType] {
+				options := getImportOptions()
+				options.IncludeOnly = resourceNames
+				utils.LoadImportState(inputDirPath)
+				toolClient.ImportResources(utils.GetRequestContext(), resourceType, inputDirPath, options)
+				if err := utils.SaveImportState(); err != nil {
+					log.Println("Error when saving the import state.", err)
+				}
+			}
+		}
+		// Roles of the applications that are not imported with the given files fail if the application does not exist.
+		roles.ImportPendingRoles()
+
+		utils.PrintSummary(utils.IMPORT)
+		utils.FinishProgress(utils.IMPORT)
+		utils.ExitIfStrictWarnings()
+	},
+}
+
+var importRolesCmd = &cobra.Command{
+	Use:   "roles",
+	Short: "Import roles from another identity provider",
+	Long:  `You can create the roles of the groups exported from another identity provider, when migrating to the target environment`,
+	Example: `  # Create a role for each group of an Azure AD group export
+  iamctl import roles -c <config folder> --from-azure-ad-groups azure-ad-groups.json
+
+  # Create the roles in an environment section
+  iamctl import roles -c <config folder> --env <environment> --encrypted-config --from-azure-ad-groups azure-ad-groups.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		groupsFile, _ := cmd.Flags().GetString("from-azure-ad-groups")
+		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		if groupsFile == "" {
+			log.Fatalln("The --from-azure-ad-groups flag is required.")
+		}
+		utils.LoadConfigs(configFile)
+		if err := roles.ImportAzureAdGroups(groupsFile); err != nil {
+			log.Fatalln("Error when importing roles from the Azure AD groups.", err)
+		}
+		utils.PrintSummary(utils.IMPORT)
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(importFilesCmd)
+	importFilesCmd.AddCommand(importRolesCmd)
+	importRolesCmd.Flags().String("from-azure-ad-groups", "", "Path to the Azure AD group export in the JSON format of the Microsoft Graph API")
+	importRolesCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	importRolesCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	importRolesCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	importFilesCmd.Flags().StringSliceP("file", "f", []string{}, "Path to the resource file to be imported")
+	importFilesCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	importFilesCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	importFilesCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	importFilesCmd.Flags().BoolP("yes", "y", false, "Import system applications without confirmation")
+	importFilesCmd.Flags().Bool("include-system-apps", false, "Export and import the system applications, such as the Console and My Account applications")
+	importFilesCmd.Flags().String("org", "", "Name of the sub organization to be managed instead of the root organization")
+	importFilesCmd.Flags().String("namespace", "", "Manage only the resources with names starting with the given prefix")
+	importFilesCmd.Flags().Bool("force", false, "Update resources even if unchanged since the last import")
+	addWarningFlags(importFilesCmd)
+	importFilesCmd.Flags().Bool("abort-on-mask", false, "Fail the import without importing any resource if a masked secret is found")
+	importFilesCmd.Flags().Bool("allow-partial-permissions", false, "Import the other resource types when read access is denied for some resource types")
+	importFilesCmd.Flags().Bool("continue-on-missing-deps", false, "Import the applications even if the referenced identity providers are not found")
+	addProgressFlag(importFilesCmd)
+	addJUnitReportFlag(importFilesCmd)
+	addAuditLogFlag(importFilesCmd)
+	addBackupFlag(importFilesCmd)
+	addPreflightFlag(importFilesCmd)
+	importFilesCmd.MarkFlagRequired("file")
+	importFilesCmd.MarkFlagRequired("config")
+}
+
+func resolveBrandingFile(filePath string) (string, string) {
+
+	// Returns the Branding folder and the name of the branded resource of a file inside the Branding folder.
+	// Ex: Branding/Organization/preference.yml, Branding/Applications/hr-portal/CustomText/login/en-US.yml
+	parts := strings.Split(filepath.ToSlash(filePath), "/")
+	for i := len(parts) - 2; i >= 0; i-- {
+		if parts[i] != utils.BRANDING {
+			continue
+		}
+		resourceName := parts[i+1]
+		if resourceName == utils.APPLICATIONS && i+2 < len(parts)-1 {
+			resourceName = parts[i+2]
+		}
+		return filepath.FromSlash(strings.Join(parts[:i+1], "/")), resourceName
+	}
+	return "", ""
+}
+
+// Get the options of the client for the import flags of the command. The failed resources are reported in the
+// summary of the command, so the errors of the client are not logged again.
+func getImportOptions() client.ImportOptions {
+
+	return client.ImportOptions{Force: utils.FORCE_IMPORT, IncludeSystemApps: utils.INCLUDE_SYSTEM_APPS,
+		AssumeYes: utils.ASSUME_YES, IncludeOnly: utils.INCLUDE_ONLY}
+}
+
+func getImportOrder() []string {
+
+	resourceTypes := []string{utils.CLAIMS, utils.OIDC_SCOPES, utils.IDENTITY_PROVIDERS, utils.API_RESOURCES, utils.ROLES,
+		utils.APPLICATIONS, utils.ORGANIZATIONS, utils.USERSTORES, utils.EMAIL_TEMPLATES, utils.BRANDING,
+		utils.GOVERNANCE_CONNECTORS, utils.CORS, utils.NOTIFICATION_SENDERS, utils.USERS, utils.WORKFLOWS, utils.XACML_POLICIES}
+	sortedTypes, err := utils.SortByDependencies(resourceTypes, importDependencies)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return sortedTypes
+}
+
+// Get a function that retrieves the identity providers available to the applications once, only when an application
+// references an identity provider.
+func getAvailableIdpsFunc(idpDirPaths []string, localIdpNames []string) func() ([]string, error) {
+
+	var availableIdps []string
+	return func() ([]string, error) {
+		if availableIdps != nil {
+			return availableIdps, nil
+		}
+		idpNames, err := identityproviders.GetAvailableIdpNames(idpDirPaths)
+		if err != nil {
+			return nil, err
+		}
+		availableIdps = append(append([]string{}, idpNames...), localIdpNames...)
+		return availableIdps, nil
+	}
+}
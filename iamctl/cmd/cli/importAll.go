@@ -19,12 +19,12 @@
 package cli
 
 import (
+	"log"
+	"strings"
+
 	"github.com/spf13/cobra"
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
-	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
-	claims "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/claims"
-	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
-	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 )
 
@@ -32,21 +32,102 @@ var importAllCmd = &cobra.Command{
 	Use:   "importAll",
 	Short: "Import all applications",
 	Long:  `You can import all applications to the target environment`,
+	Example: `  # Print the planned changes without importing
+  iamctl importAll -c <config folder> -i <base directory> --summary-only
+
+  # Import all resources and report the resources of the target environment that are not managed locally
+  iamctl importAll -c <config folder> -i <base directory> --coverage
+
+  # Import the applications and identity providers of a sub organization from the Organizations/<name> folder
+  iamctl importAll -c <config folder> -i <base directory> --org partner-org
+
+  # Import only the resources of a team, named with the team prefix
+  iamctl importAll -c <config folder> -i <base directory> --namespace team-a-
+
+  # Import the selected resources to an environment section without deleting any resource
+  iamctl importAll -c <config folder> --env <environment> --encrypted-config --show-config --include-only "payments-*" --no-delete
+
+  # Import the customised system applications, such as the Console, without confirmation
+  iamctl importAll -c <config folder> -i <base directory> --include-system-apps -y
+
+  # Import the resources of each tenant in the TENANTS server config from <base directory>/<tenant domain>
+  iamctl importAll -c <config folder> -i <base directory> --all-tenants
+
+  # Import all resources, keeping the applications edited on the server since the last export with the --baseline flag
+  iamctl importAll -c <config folder> -i <base directory> --conflict-strategy theirs
+
+  # Import the applications with a warning when the referenced identity providers are managed elsewhere
+  iamctl importAll -c <config folder> -i <base directory> --continue-on-missing-deps
+
+  # Import the resource types that the tool is permitted to manage, when the scopes of some resource types are not granted
+  iamctl importAll -c <config folder> --allow-partial-permissions
+
+  # Import all resources in CI, deleting the resources not found locally without confirmation
+  iamctl importAll -c <config folder> -i <base directory> --force -y --abort-on-mask --strict --ignore-warning expiring-certificate \
+    --time-budget 10m --progress-socket /tmp/iamctl.sock --audit-log /var/log/iamctl/audit.log --preflight-timeout 30 \
+    --no-progress --backup /var/backups/iamctl --junit-report reports/iamctl-import.xml`,
 	Run: func(cmd *cobra.Command, args []string) {
 		inputDirPath, _ := cmd.Flags().GetString("inputDir")
 		configFile, _ := cmd.Flags().GetString("config")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.SHOW_CONFIG, _ = cmd.Flags().GetBool("show-config")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+		utils.ALL_TENANTS, _ = cmd.Flags().GetBool("all-tenants")
+		utils.ORGANIZATION, _ = cmd.Flags().GetString("org")
+		utils.FORCE_IMPORT, _ = cmd.Flags().GetBool("force")
+		utils.ASSUME_YES, _ = cmd.Flags().GetBool("yes")
+		utils.NO_DELETE, _ = cmd.Flags().GetBool("no-delete")
+		utils.INCLUDE_SYSTEM_APPS, _ = cmd.Flags().GetBool("include-system-apps")
+		timeBudget, _ := cmd.Flags().GetDuration("time-budget")
+		readWarningFlags(cmd)
+		utils.ABORT_ON_MASK, _ = cmd.Flags().GetBool("abort-on-mask")
+		utils.ALLOW_PARTIAL_PERMISSIONS, _ = cmd.Flags().GetBool("allow-partial-permissions")
+		utils.CONTINUE_ON_MISSING_DEPS, _ = cmd.Flags().GetBool("continue-on-missing-deps")
+		utils.CONFLICT_STRATEGY, _ = cmd.Flags().GetString("conflict-strategy")
+		if err := utils.ValidateConflictStrategy(utils.CONFLICT_STRATEGY); err != nil {
+			log.Fatalln(err)
+		}
+		if cmd.Flags().Changed("conflict-strategy") {
+			log.Println("Conflicts are detected for the applications only. The other resource types are updated without checking the changes made on the server.")
+		}
+		readProgressFlag(cmd)
+		readJUnitReportFlag(cmd, utils.IMPORT)
+		readAuditLogFlag(cmd)
+		readBackupFlag(cmd)
+		defer utils.CloseAuditLog()
+		includeOnly, _ := cmd.Flags().GetString("include-only")
+		utils.INCLUDE_ONLY = utils.ParseIncludeFilter(includeOnly)
+		utils.SUMMARY_ONLY, _ = cmd.Flags().GetBool("summary-only")
+		coverage, _ := cmd.Flags().GetBool("coverage")
 
+		utils.StartTimeBudget(timeBudget)
+		readPreflightFlag(cmd)
 		baseDir := utils.LoadConfigs(configFile)
 		if inputDirPath == "" {
 			inputDirPath = baseDir
 		}
-
-		claims.ImportAll(inputDirPath)
-		identityproviders.ImportAll(inputDirPath)
-		applications.ImportAll(inputDirPath)
-		userstores.ImportAll(inputDirPath)
-
-		utils.PrintSummary(utils.IMPORT)
+		if utils.ALL_TENANTS {
+			results := utils.RunForEachTenant(inputDirPath, func(tenantDir string) error {
+				return importAllResources(utils.GetOrganizationDir(tenantDir), coverage)
+			})
+			utils.PrintTenantSummary(results)
+			utils.FinishProgress(utils.IMPORT)
+			utils.ExitIfInterrupted()
+			utils.ExitIfConflictAborted()
+			utils.ExitIfStrictWarnings()
+			utils.ExitIfTenantFailed(results)
+			utils.ExitIfIncomplete()
+			return
+		}
+		if err := importAllResources(utils.GetOrganizationDir(inputDirPath), coverage); err != nil {
+			log.Fatalln("Aborting the import.", err)
+		}
+		utils.FinishProgress(utils.IMPORT)
+		utils.ExitIfInterrupted()
+		utils.ExitIfConflictAborted()
+		utils.ExitIfStrictWarnings()
+		utils.ExitIfIncomplete()
 	},
 }
 
@@ -55,5 +136,95 @@ func init() {
 	cmd.RootCmd.AddCommand(importAllCmd)
 	importAllCmd.Flags().StringP("inputDir", "i", "", "Path to the input directory")
 	importAllCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	importAllCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	importAllCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	importAllCmd.Flags().Bool("show-config", false, "Print the resolved configs with secrets masked")
+	importAllCmd.Flags().String("org", "", "Name of the sub organization to be managed instead of the root organization")
+	importAllCmd.Flags().Bool("all-tenants", false, "Import the resources of each tenant in the TENANTS server config from the folder of the tenant")
+	importAllCmd.Flags().String("namespace", "", "Manage only the resources with names starting with the given prefix")
+	importAllCmd.Flags().String("include-only", "", "Comma separated list of resource names or glob patterns to be imported")
+	importAllCmd.Flags().Bool("force", false, "Delete resources without confirmation and update resources even if unchanged")
+	importAllCmd.Flags().BoolP("yes", "y", false, "Delete resources and import system applications without confirmation")
+	importAllCmd.Flags().Bool("include-system-apps", false, "Export and import the system applications, such as the Console and My Account applications")
+	importAllCmd.Flags().Bool("no-delete", false, "Skip deleting resources regardless of the ALLOW_DELETE config")
+	addWarningFlags(importAllCmd)
+	importAllCmd.Flags().Bool("abort-on-mask", false, "Fail the import without importing any resource if a masked secret is found")
+	importAllCmd.Flags().Bool("allow-partial-permissions", false, "Import the other resource types when read access is denied for some resource types")
+	importAllCmd.Flags().Bool("continue-on-missing-deps", false, "Import the applications even if the referenced identity providers are not found")
+	importAllCmd.Flags().String("conflict-strategy", utils.CONFLICT_STRATEGY_OURS, "Strategy for the applications edited on the server since the last export (other resource types are not checked): "+
+		strings.Join(utils.CONFLICT_STRATEGIES, ", "))
+	addProgressFlag(importAllCmd)
+	addJUnitReportFlag(importAllCmd)
+	addAuditLogFlag(importAllCmd)
+	addBackupFlag(importAllCmd)
+	addPreflightFlag(importAllCmd)
+	importAllCmd.Flags().Bool("summary-only", false, "Print the number of resources to be created, updated and deleted without importing")
+	importAllCmd.Flags().Bool("coverage", false, "Add the coverage of the resources in the target environment to the summary")
+	importAllCmd.Flags().Duration("time-budget", 0, "Maximum duration of the run, after which the remaining resources are not processed")
 	importAllCmd.MarkFlagRequired("config")
 }
+
+func getLocalResourceTypes(inputDirPath string) []string {
+
+	managedCounts, err := utils.GetLocalManagedCounts(inputDirPath)
+	if err != nil {
+		log.Println("Error when reading the local resources.", err)
+	}
+	var resourceTypes []string
+	for _, resourceType := range importOrder {
+		if managedCounts[resourceType] > 0 {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+	}
+	return resourceTypes
+}
+
+func importAllResources(inputDirPath string, coverage bool) error {
+
+	if utils.SUMMARY_ONLY {
+		printImportSummary(inputDirPath)
+		return nil
+	}
+	filePaths, err := utils.GetLocalResourceFilePaths(inputDirPath)
+	if err == nil && utils.ABORT_ON_MASK {
+		err = utils.CheckMaskedSecrets(filePaths)
+	}
+	if err == nil {
+		err = utils.CheckCertificatePlaceholders(filePaths)
+	}
+	if err != nil {
+		return err
+	}
+	if err := utils.CheckTenantUrls(inputDirPath, nil); err != nil {
+		return err
+	}
+	if err := checkResourceQuotas(inputDirPath); err != nil {
+		return err
+	}
+	if err := utils.CheckIdpDependencies(inputDirPath, getAvailableIdpsFunc([]string{inputDirPath}, nil)); err != nil {
+		return err
+	}
+	if err := utils.CheckResourcePermissions(getLocalResourceTypes(inputDirPath)); err != nil {
+		return err
+	}
+	utils.StartProgress(utils.IMPORT)
+	if utils.IsFilterActive() && utils.TOOL_CONFIGS.AllowDelete {
+		log.Println("Deletion of resources is disabled since the --include-only filter is set.")
+	}
+
+	utils.LoadImportState(inputDirPath)
+	toolClient := client.NewFromLoadedConfigs()
+	options := getImportOptions()
+	for _, resourceType := range importOrder {
+		toolClient.ImportResources(utils.GetRequestContext(), resourceType, inputDirPath, options)
+	}
+	if err := utils.SaveImportState(); err != nil {
+		log.Println("Error when saving the import state.", err)
+	}
+
+	utils.PrintSummary(utils.IMPORT)
+	if coverage {
+		printRunCoverage()
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	apiresources "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/apiResources"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	claims "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/claims"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	oidcscopes "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/oidcScopes"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/organizations"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/workflows"
+	xacmlpolicies "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/xacmlPolicies"
+)
+
+// Functions to plan the import of the resource types that can be matched by name with a single list request.
+var importPlanners = map[string]func(string) (utils.ImportPlan, error){
+	utils.CLAIMS:             claims.GetImportPlan,
+	utils.OIDC_SCOPES:        oidcscopes.GetImportPlan,
+	utils.IDENTITY_PROVIDERS: identityproviders.GetImportPlan,
+	utils.API_RESOURCES:      apiresources.GetImportPlan,
+	utils.ROLES:              roles.GetImportPlan,
+	utils.APPLICATIONS:       applications.GetImportPlan,
+	utils.ORGANIZATIONS:      organizations.GetImportPlan,
+	utils.USERSTORES:         userstores.GetImportPlan,
+	utils.WORKFLOWS:          workflows.GetImportPlan,
+	utils.XACML_POLICIES:     xacmlpolicies.GetImportPlan,
+}
+
+func printImportSummary(inputDirPath string) {
+
+	var plans []utils.ImportPlan
+	var unplannedResourceTypes []string
+	for _, resourceType := range importOrder {
+		if utils.IsResourceTypeExcluded(resourceType) {
+			continue
+		}
+		planner, ok := importPlanners[resourceType]
+		if !ok {
+			if _, err := os.Stat(filepath.Join(inputDirPath, resourceType)); err == nil {
+				unplannedResourceTypes = append(unplannedResourceTypes, resourceType)
+			}
+			continue
+		}
+		plan, err := planner(inputDirPath)
+		if err != nil {
+			log.Printf("Error when planning the import of %s. %s", resourceType, err)
+			continue
+		}
+		plans = append(plans, plan)
+	}
+
+	if err := utils.WriteDependencyFile(inputDirPath); err != nil {
+		log.Println("Error when writing the dependency file.", err)
+	}
+	fmt.Println("Planned changes:")
+	utils.PrintImportPlans(plans)
+	if len(unplannedResourceTypes) > 0 {
+		fmt.Println("Not included in the summary: " + strings.Join(unplannedResourceTypes, ", "))
+	}
+}
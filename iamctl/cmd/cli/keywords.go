@@ -0,0 +1,119 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+var keywordsCmd = &cobra.Command{
+	Use:   "keywords",
+	Short: "Manage the keyword configs",
+	Long:  `You can validate the keyword configs of the environments and encrypt the sensitive keyword values`,
+}
+
+var lintKeywordsCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Validate the keyword configs across environments",
+	Long: `You can check that the keyword values expected to differ across environments are not identical, ` +
+		`and that they do not point to hosts of other environments`,
+	Example: `  # Check the keyword configs of two environments
+  iamctl keywords lint -c <config folder>,<other config folder>
+
+  # Check the given keywords, allowing a shared value
+  iamctl keywords lint -c <config folder>,<other config folder> --keys "*_URL,*_HOST" --allow-shared https://cdn.example.com`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFolders, _ := cmd.Flags().GetStringSlice("config")
+		keys, _ := cmd.Flags().GetStringSlice("keys")
+		allowedShared, _ := cmd.Flags().GetStringSlice("allow-shared")
+
+		environments, err := utils.LoadKeywordEnvironments(configFolders)
+		if err != nil {
+			log.Fatalln("Error when loading the keyword configs.", err)
+		}
+		issues := utils.LintKeywordEnvironments(environments, keys, allowedShared)
+		for _, issue := range issues {
+			fmt.Println(issue)
+		}
+		if len(issues) > 0 {
+			log.Fatalf("Found %d issues in the keyword configs.", len(issues))
+		}
+		log.Printf("No issues found in the keyword configs of %d environments.", len(environments))
+	},
+}
+
+var encryptKeywordCmd = &cobra.Command{
+	Use:   "encrypt",
+	Short: "Encrypt a keyword value",
+	Long: `You can encrypt a sensitive keyword value with the AES key in the given environment variable, ` +
+		`to be used in the keyword configs`,
+	Example: `  # Encrypt a value with the key in the IAMCTL_KW_KEY environment variable
+  iamctl keywords encrypt --value "secret"
+
+  # Encrypt a value entered at the prompt with the key in a different environment variable
+  iamctl keywords encrypt --key-env CI_KEYWORD_KEY`,
+	Run: func(cmd *cobra.Command, args []string) {
+		keyEnv, _ := cmd.Flags().GetString("key-env")
+		value, _ := cmd.Flags().GetString("value")
+
+		key, err := utils.GetKeywordKey(keyEnv)
+		if err != nil {
+			log.Fatalln("Error when reading the encryption key.", err)
+		}
+		if !cmd.Flags().Changed("value") {
+			fmt.Print("Enter the value to be encrypted: ")
+			input, err := terminal.ReadPassword(int(syscall.Stdin))
+			fmt.Println()
+			if err != nil {
+				log.Fatalln("Error when reading the value.", err)
+			}
+			value = string(input)
+		}
+		if value == "" {
+			log.Fatalln("Value to be encrypted cannot be empty.")
+		}
+
+		encryptedValue, err := utils.EncryptKeywordValue(value, key)
+		if err != nil {
+			log.Fatalln("Error when encrypting the value.", err)
+		}
+		fmt.Println(encryptedValue)
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(keywordsCmd)
+	keywordsCmd.AddCommand(lintKeywordsCmd)
+	lintKeywordsCmd.Flags().StringSliceP("config", "c", []string{}, "Path to the environment specific config folder")
+	lintKeywordsCmd.Flags().StringSlice("keys", []string{}, "Names or glob patterns of the keywords to be checked. Defaults to the keywords with URL values")
+	lintKeywordsCmd.Flags().StringSlice("allow-shared", []string{}, "Keywords or values that are allowed to be shared across environments")
+	lintKeywordsCmd.MarkFlagRequired("config")
+
+	keywordsCmd.AddCommand(encryptKeywordCmd)
+	encryptKeywordCmd.Flags().String("key-env", utils.IAMCTL_KEYWORD_KEY, "Environment variable with the base64 encoded AES key")
+	encryptKeywordCmd.Flags().String("value", "", "Value to be encrypted. Prompted for if not provided")
+}
@@ -0,0 +1,78 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Detect misconfigurations in the local resource files",
+	Long: `You can check the local resource files for common misconfigurations before importing them. ` +
+		`Exits with a non-zero exit code when a violation is found, so that it can be used as a git pre-commit hook`,
+	Example: `  # Check the resource files in the resource directory
+  iamctl lint -i <base directory>
+
+  # Check the resource files against the keyword configs of an environment section
+  iamctl lint -i <base directory> -c <config folder> --env <environment> --disable idp-certificate`,
+	Run: func(cmd *cobra.Command, args []string) {
+		inputDirPath, _ := cmd.Flags().GetString("inputDir")
+		configPath, _ := cmd.Flags().GetString("config")
+		disabledRules, _ := cmd.Flags().GetStringSlice("disable")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+
+		if err := utils.ValidateLintRules(disabledRules); err != nil {
+			log.Fatalln(err)
+		}
+		if configPath != "" {
+			utils.LoadKeywordConfigs(configPath)
+		} else if !utils.Contains(disabledRules, utils.LINT_UNDEFINED_KEYWORD) {
+			log.Printf("Keyword configs are not provided. Skipping the %s rule.", utils.LINT_UNDEFINED_KEYWORD)
+			disabledRules = append(disabledRules, utils.LINT_UNDEFINED_KEYWORD)
+		}
+
+		violations, err := utils.LintLocalResources(inputDirPath, disabledRules)
+		if err != nil {
+			log.Fatalln("Error when linting the local resource files.", err)
+		}
+		for _, violation := range violations {
+			fmt.Println(violation)
+		}
+		if len(violations) > 0 {
+			log.Fatalf("Found %d violations in the local resource files.", len(violations))
+		}
+		log.Println("No violations found in the local resource files.")
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringP("inputDir", "i", ".", "Path to the folder containing the resource files")
+	lintCmd.Flags().StringP("config", "c", "", "Path to the env specific config folder. Required for the "+
+		utils.LINT_UNDEFINED_KEYWORD+" rule")
+	lintCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	lintCmd.Flags().StringSlice("disable", []string{}, "Lint rules to be disabled: "+strings.Join(utils.LINT_RULES, ", "))
+}
@@ -0,0 +1,190 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var listCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List resources",
+	Long:  `You can list the resources available in the target environment`,
+}
+
+var listAppsCmd = &cobra.Command{
+	Use:   "apps",
+	Short: "List the applications",
+	Long:  `You can list the applications of the target environment`,
+	Example: `  # List the applications
+  iamctl list apps -c <config folder>
+
+  # Print the names of the applications with jq
+  iamctl list apps -c <config folder> -o json | jq '.[].name'
+
+  # List the applications of a team in YAML, with names matching a regular expression
+  iamctl list apps -c <config folder> --env <environment> --encrypted-config --namespace team-a- --filter "portal$" -o yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, _ := cmd.Flags().GetString("config")
+		output, _ := cmd.Flags().GetString("output")
+		filter, _ := cmd.Flags().GetString("filter")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+
+		if err := utils.ValidateOutputFormat(output); err != nil {
+			log.Fatalln(err)
+		}
+		utils.LoadConfigs(configFile)
+
+		apps, err := client.NewFromLoadedConfigs().ListApplications(utils.GetRequestContext())
+		if err != nil {
+			log.Fatalln("Error when listing applications.", err)
+		}
+		isMatchingName := utils.GetNameMatcher(filter)
+		var matchingApps []applications.AppListItem
+		for _, app := range apps {
+			if isMatchingName(app.Name) {
+				matchingApps = append(matchingApps, app)
+			}
+		}
+
+		if output != utils.OUTPUT_TABLE {
+			var resources []map[string]interface{}
+			for _, app := range matchingApps {
+				resources = append(resources, app.Attributes)
+			}
+			if err := utils.WriteResourceList(os.Stdout, output, resources); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tID")
+		for _, app := range matchingApps {
+			fmt.Fprintf(writer, "%s\t%s\n", app.Name, app.Id)
+		}
+		writer.Flush()
+	},
+}
+
+var listIdpsCmd = &cobra.Command{
+	Use:   "idps",
+	Short: "List the identity providers",
+	Long:  `You can list the identity providers of the target environment with their federation protocols`,
+	Example: `  # List the identity providers
+  iamctl list idps -c <config folder>
+
+  # List the SAML identity providers of an environment section
+  iamctl list idps -c <config folder> --env <environment> --encrypted-config --filter-by-protocol saml
+
+  # List the identity providers of a team, named with the team prefix
+  iamctl list idps -c <config folder> --namespace team-a-
+
+  # Print the identity providers with names containing "google" in JSON
+  iamctl list idps -c <config folder> --filter google -o json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		configFile, _ := cmd.Flags().GetString("config")
+		protocol, _ := cmd.Flags().GetString("filter-by-protocol")
+		output, _ := cmd.Flags().GetString("output")
+		filter, _ := cmd.Flags().GetString("filter")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		utils.NAMESPACE, _ = cmd.Flags().GetString("namespace")
+
+		protocol = strings.ToLower(protocol)
+		if protocol != "" && !utils.Contains(identityproviders.FEDERATION_PROTOCOLS, protocol) {
+			log.Fatalf("Invalid protocol: %s. Supported protocols: %s", protocol,
+				strings.Join(identityproviders.FEDERATION_PROTOCOLS, ", "))
+		}
+		if err := utils.ValidateOutputFormat(output); err != nil {
+			log.Fatalln(err)
+		}
+		utils.LoadConfigs(configFile)
+
+		idps, err := client.NewFromLoadedConfigs().ListIdps(utils.GetRequestContext(), protocol)
+		if err != nil {
+			log.Fatalln("Error when listing identity providers.", err)
+		}
+		isMatchingName := utils.GetNameMatcher(filter)
+		var matchingIdps []identityproviders.IdpListItem
+		for _, idp := range idps {
+			if isMatchingName(idp.Name) {
+				matchingIdps = append(matchingIdps, idp)
+			}
+		}
+
+		if output != utils.OUTPUT_TABLE {
+			var resources []map[string]interface{}
+			for _, idp := range matchingIdps {
+				resources = append(resources, idp.Attributes)
+			}
+			if err := utils.WriteResourceList(os.Stdout, output, resources); err != nil {
+				log.Fatalln(err)
+			}
+			return
+		}
+		writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+		fmt.Fprintln(writer, "NAME\tPROTOCOLS\tENABLED")
+		for _, idp := range matchingIdps {
+			protocols := strings.Join(identityproviders.GetIdpProtocols(idp), ",")
+			if protocols == "" {
+				protocols = "-"
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%t\n", idp.Name, protocols, idp.IsEnabled)
+		}
+		writer.Flush()
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(listCmd)
+	listCmd.AddCommand(listAppsCmd)
+	listCmd.AddCommand(listIdpsCmd)
+	listAppsCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	listAppsCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	listAppsCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	listAppsCmd.Flags().String("namespace", "", "List only the applications with names starting with the given prefix")
+	addListOutputFlags(listAppsCmd)
+	listIdpsCmd.Flags().StringP("config", "c", "", "Path to the environment specific config folder")
+	listIdpsCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	listIdpsCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	listIdpsCmd.Flags().String("namespace", "", "List only the identity providers with names starting with the given prefix")
+	listIdpsCmd.Flags().String("filter-by-protocol", "", "List only the identity providers with the given federation protocol: "+
+		strings.Join(identityproviders.FEDERATION_PROTOCOLS, ", "))
+	addListOutputFlags(listIdpsCmd)
+}
+
+func addListOutputFlags(command *cobra.Command) {
+
+	command.Flags().StringP("output", "o", utils.OUTPUT_TABLE, "Output format: "+strings.Join(utils.OUTPUT_FORMATS, ", "))
+	command.Flags().String("filter", "", "List only the resources with names matching the given regular expression or substring")
+}
@@ -0,0 +1,42 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func addPreflightFlag(command *cobra.Command) {
+
+	command.Flags().Int("preflight-timeout", utils.DEFAULT_PREFLIGHT_TIMEOUT, "Maximum duration in seconds of the "+
+		"pre-flight check of the server availability, before the access token is requested")
+}
+
+func readPreflightFlag(command *cobra.Command) {
+
+	timeout, _ := command.Flags().GetInt("preflight-timeout")
+	if timeout <= 0 {
+		log.Fatalln("The --preflight-timeout flag should be a positive number of seconds.")
+	}
+	utils.PREFLIGHT_TIMEOUT = time.Duration(timeout) * time.Second
+}
@@ -0,0 +1,129 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var progressCmd = &cobra.Command{
+	Use:   "progress",
+	Short: "Inspect the progress events of a run",
+	Long:  `You can view the progress events sent by a run with the --progress-socket flag`,
+}
+
+var attachProgressCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "Print the progress events sent to a socket",
+	Long:  `You can listen on a socket and print the progress events sent by the runs that use the socket`,
+	Example: `  # Print the progress events of a run started with --progress-socket /tmp/iamctl.sock
+  iamctl progress attach -s /tmp/iamctl.sock
+
+  # Print the progress events sent to a named pipe on Windows
+  iamctl progress attach -s \\.\pipe\iamctl`,
+	Run: func(cmd *cobra.Command, args []string) {
+		socketPath, _ := cmd.Flags().GetString("socket")
+
+		listener, err := utils.ListenProgressSocket(socketPath)
+		if err != nil {
+			log.Fatalln("Error when listening on the progress socket.", err)
+		}
+		utils.StopInterruptHandling()
+		interrupt := make(chan os.Signal, 1)
+		signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-interrupt
+			listener.Close()
+		}()
+
+		log.Println("Waiting for progress events on: " + socketPath)
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			printProgressEvents(conn)
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(progressCmd)
+	progressCmd.AddCommand(attachProgressCmd)
+	attachProgressCmd.Flags().StringP("socket", "s", "", "Path to the socket to listen on")
+	attachProgressCmd.MarkFlagRequired("socket")
+}
+
+func addProgressFlag(command *cobra.Command) {
+
+	command.Flags().String("progress-socket", "", "Path to the socket to send the progress events to")
+	command.Flags().Bool("no-progress", false, "Disable the progress counter of the resources")
+}
+
+func readProgressFlag(command *cobra.Command) {
+
+	utils.PROGRESS_SOCKET, _ = command.Flags().GetString("progress-socket")
+	utils.NO_PROGRESS, _ = command.Flags().GetBool("no-progress")
+}
+
+func printProgressEvents(conn net.Conn) {
+
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var event utils.ProgressEvent
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			fmt.Println(scanner.Text())
+			continue
+		}
+		fmt.Println(formatProgressEvent(event))
+	}
+}
+
+func formatProgressEvent(event utils.ProgressEvent) string {
+
+	switch event.Event {
+	case utils.PROGRESS_RUN_STARTED:
+		return fmt.Sprintf("%s  Started %s run", event.Timestamp, event.Operation)
+	case utils.PROGRESS_RESOURCE_STARTED:
+		return fmt.Sprintf("%s  %-18s %s: %s", event.Timestamp, event.ResourceType, event.ResourceName, event.Action)
+	case utils.PROGRESS_RESOURCE_FINISHED:
+		return fmt.Sprintf("%s  %-18s %s: %s %s", event.Timestamp, event.ResourceType, event.ResourceName,
+			event.Action, event.Result)
+	case utils.PROGRESS_RUN_FINISHED:
+		if event.Summary == nil {
+			return fmt.Sprintf("%s  Finished %s run", event.Timestamp, event.Operation)
+		}
+		return fmt.Sprintf("%s  Finished %s run. Total requests: %d, successful: %d, failed: %d", event.Timestamp,
+			event.Operation, event.Summary.TotalRequests, event.Summary.SuccessfulOperations, event.Summary.FailedOperations)
+	}
+	return fmt.Sprintf("%s  %s", event.Timestamp, event.Event)
+}
@@ -0,0 +1,90 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Promote resources from one environment to another",
+	Long: `You can copy the given applications from a source environment to a target environment ` +
+		`without writing intermediate files`,
+	Example: `  # Promote applications from one environment to another
+  iamctl promote --from <config folder> --to <other config folder> --apps hr-portal,payments
+
+  # Promote applications between the environment sections of a config folder
+  iamctl promote --from <config folder> --from-env <environment> --to <config folder> --to-env <other environment> \
+    --apps hr-portal --encrypted-config --strict --ignore-warning unresolved-keyword --audit-log audit.log \
+    --backup <base directory>/backups`,
+	Run: func(cmd *cobra.Command, args []string) {
+		sourceConfig, _ := cmd.Flags().GetString("from")
+		targetConfig, _ := cmd.Flags().GetString("to")
+		sourceEnv, _ := cmd.Flags().GetString("from-env")
+		targetEnv, _ := cmd.Flags().GetString("to-env")
+		appNames, _ := cmd.Flags().GetStringSlice("apps")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+		readWarningFlags(cmd)
+		readAuditLogFlag(cmd)
+		readBackupFlag(cmd)
+		defer utils.CloseAuditLog()
+
+		log.Println("Loading the configs of the source environment.")
+		utils.ENVIRONMENT = sourceEnv
+		utils.LoadConfigs(sourceConfig)
+		contents, err := applications.GetPromotedContents(appNames)
+		if err != nil {
+			log.Fatalln("Error when fetching the applications from the source environment.", err)
+		}
+
+		log.Println("Loading the configs of the target environment.")
+		utils.ENVIRONMENT = targetEnv
+		utils.LoadConfigs(targetConfig)
+		err = applications.PromoteApps(contents)
+		if err != nil {
+			log.Fatalln("Error when promoting the applications. No application is imported.", err)
+		}
+
+		utils.PrintSummary(utils.IMPORT)
+		utils.ExitIfStrictWarnings()
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(promoteCmd)
+	promoteCmd.Flags().String("from", "", "Path to the env specific config folder of the source environment")
+	promoteCmd.Flags().String("to", "", "Path to the env specific config folder of the target environment")
+	promoteCmd.Flags().String("from-env", "", "Name of the source environment to be selected from the config files")
+	promoteCmd.Flags().String("to-env", "", "Name of the target environment to be selected from the config files")
+	promoteCmd.Flags().StringSlice("apps", []string{}, "Names of the applications to be promoted")
+	promoteCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config files")
+	addWarningFlags(promoteCmd)
+	addAuditLogFlag(promoteCmd)
+	addBackupFlag(promoteCmd)
+	promoteCmd.MarkFlagRequired("from")
+	promoteCmd.MarkFlagRequired("to")
+	promoteCmd.MarkFlagRequired("apps")
+}
@@ -0,0 +1,55 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package cli
+
+import (
+	"log"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Functions to count the deployed resources and the resources to be created, for the resource types with quotas.
+var plannedCreateCounters = []struct {
+	resourceType string
+	count        func(string) (int, int, error)
+}{
+	{utils.IDENTITY_PROVIDERS, identityproviders.GetPlannedCreates},
+	{utils.APPLICATIONS, applications.GetPlannedCreates},
+}
+
+func checkResourceQuotas(inputDirPath string) error {
+
+	// Fail before importing any resource if the resources to be created exceed the quota of the target environment.
+	var quotas []utils.ResourceQuota
+	for _, counter := range plannedCreateCounters {
+		limit, ok := utils.GetQuotaLimit(counter.resourceType)
+		if !ok {
+			continue
+		}
+		deployed, creates, err := counter.count(inputDirPath)
+		if err != nil {
+			log.Printf("Error when checking the quota of %s. %s", counter.resourceType, err)
+			continue
+		}
+		quotas = append(quotas, utils.ResourceQuota{ResourceType: counter.resourceType, Deployed: deployed,
+			Creates: creates, Limit: limit})
+	}
+	return utils.CheckResourceQuotas(quotas)
+}
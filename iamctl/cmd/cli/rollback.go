@@ -0,0 +1,87 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Restore the applications and identity providers from a backup",
+	Long: `You can restore the applications and identity providers updated by a failed import from the backup ` +
+		`taken with the --backup flag. Only the resources that exist in the target environment are updated. ` +
+		`No resource is created or deleted`,
+	Example: `  # Restore the resources from a backup of the run
+  iamctl rollback -c <config folder> --from <backup directory>/20240131-101530
+
+  # Restore a backup taken from another server, selecting the environment section from the config files
+  iamctl rollback -c <config folder> --env <environment> --encrypted-config --from <backup directory>/20240131-101530 --force`,
+	Run: func(cmd *cobra.Command, args []string) {
+		backupDir, _ := cmd.Flags().GetString("from")
+		configFile, _ := cmd.Flags().GetString("config")
+		force, _ := cmd.Flags().GetBool("force")
+		utils.ENVIRONMENT, _ = cmd.Flags().GetString("env")
+		utils.ENCRYPTED_CONFIG, _ = cmd.Flags().GetBool("encrypted-config")
+
+		if backupDir == "" {
+			log.Fatalln("The --from flag is required.")
+		}
+		utils.LoadConfigs(configFile)
+		metadata, err := utils.ReadBackupMetadata(backupDir)
+		if err == nil {
+			err = utils.ValidateBackupServer(metadata)
+		}
+		if err != nil && !force {
+			log.Fatalln(err, "Use the --force flag to restore the backup to this server.")
+		} else if err != nil {
+			log.Println("Restoring the backup since the --force flag is set.", err)
+		} else {
+			log.Printf("Restoring the backup taken at %s with the tool version %s.", metadata.Timestamp, metadata.ToolVersion)
+		}
+
+		utils.UPDATE_ONLY = true
+		utils.StartProgress(utils.IMPORT)
+		identityproviders.ImportAll(backupDir)
+		applications.ImportAll(backupDir)
+		utils.PrintSummary(utils.IMPORT)
+		utils.PrintRestoreSummary()
+		utils.FinishProgress(utils.IMPORT)
+		if utils.SummaryData.FailedOperations > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(rollbackCmd)
+	rollbackCmd.Flags().String("from", "", "Path to the timestamped backup directory to be restored")
+	rollbackCmd.Flags().StringP("config", "c", "", "Path to the env specific config folder")
+	rollbackCmd.Flags().String("env", "", "Name of the environment to be selected from the config files")
+	rollbackCmd.Flags().Bool("encrypted-config", false, "Decrypt the encrypted fields of the server config file")
+	rollbackCmd.Flags().Bool("force", false, "Restore the backup even if it is taken from another server")
+}
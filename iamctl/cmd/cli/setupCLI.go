@@ -41,6 +41,11 @@ var setupCmd = &cobra.Command{
 	Use:   "setupCLI",
 	Short: "Setup the CLI tool",
 	Long:  `You can setup the config folder structure for the CLI tool`,
+	Example: `  # Create the config folder in the current directory
+  iamctl setupCLI
+
+  # Create the config folder in the resource directory
+  iamctl setupCLI -d <base directory>`,
 	Run: func(cmd *cobra.Command, args []string) {
 		baseDirPath, _ := cmd.Flags().GetString("baseDir")
 
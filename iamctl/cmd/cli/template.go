@@ -0,0 +1,82 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/templates"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Print a skeleton file of a new resource",
+	Long: `You can print a commented skeleton file of a new resource in the format of the exported files, ` +
+		`to be saved and edited in the local directory before importing`,
+}
+
+var templateAppCmd = &cobra.Command{
+	Use:   "app",
+	Short: "Print a skeleton file of a new application",
+	Long:  `You can print a commented skeleton file of a new application of the given type`,
+	Example: `  # Save a skeleton file of a new OpenID Connect application in the local directory
+  iamctl template app --type oidc > <base directory>/Applications/my-oidc-app.yml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printTemplate(cmd, utils.APPLICATIONS)
+	},
+}
+
+var templateIdpCmd = &cobra.Command{
+	Use:   "idp",
+	Short: "Print a skeleton file of a new identity provider",
+	Long:  `You can print a commented skeleton file of a new identity provider of the given type`,
+	Example: `  # Save a skeleton file of a new Google identity provider in the local directory
+  iamctl template idp --type google > <base directory>/IdentityProviders/Google.yml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printTemplate(cmd, utils.IDENTITY_PROVIDERS)
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(templateCmd)
+	templateCmd.AddCommand(templateAppCmd)
+	templateCmd.AddCommand(templateIdpCmd)
+	templateAppCmd.Flags().String("type", "", "Type of the application. Supported types: "+
+		strings.Join(templates.APP_TEMPLATE_TYPES, ", "))
+	templateIdpCmd.Flags().String("type", "", "Type of the identity provider. Supported types: "+
+		strings.Join(templates.IDP_TEMPLATE_TYPES, ", "))
+	templateAppCmd.MarkFlagRequired("type")
+	templateIdpCmd.MarkFlagRequired("type")
+}
+
+func printTemplate(cmd *cobra.Command, resourceType string) {
+
+	templateType, _ := cmd.Flags().GetString("type")
+	content, err := templates.GetTemplate(resourceType, templateType)
+	if err != nil {
+		log.Fatalln("Error when getting the template.", err)
+	}
+	fmt.Print(string(content))
+}
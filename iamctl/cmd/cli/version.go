@@ -0,0 +1,74 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package cli
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print the version of the tool",
+	Long:  `You can print the version of the tool and check whether a newer version is available`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(utils.AppName + " version " + utils.VERSION)
+	},
+}
+
+var versionCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check whether a newer version of the tool is available",
+	Long:  `You can compare the version of the tool with the latest release published in GitHub`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if strings.EqualFold(os.Getenv(utils.IAMCTL_NO_UPDATE_CHECK), "true") {
+			log.Println("Update check is disabled with the " + utils.IAMCTL_NO_UPDATE_CHECK + " environment variable.")
+			return
+		}
+		latestVersion, err := utils.GetLatestReleaseVersion()
+		if err != nil {
+			log.Fatalln("Error when checking for updates.", err)
+		}
+		fmt.Println("Current version: " + utils.VERSION)
+		fmt.Println("Latest version: " + latestVersion)
+
+		result, err := utils.CompareVersions(utils.VERSION, latestVersion)
+		if err != nil {
+			fmt.Println("Cannot compare the versions as the tool is not built from a release.")
+			return
+		}
+		if result < 0 {
+			fmt.Println("A newer version is available. Download it from: " +
+				"https://github.com/wso2-extensions/identity-tools-cli/releases/latest")
+		} else {
+			fmt.Println("The tool is up to date.")
+		}
+	},
+}
+
+func init() {
+
+	cmd.RootCmd.AddCommand(versionCmd)
+	versionCmd.AddCommand(versionCheckCmd)
+}
@@ -0,0 +1,43 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cli
+
+import (
+	"log"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func addWarningFlags(command *cobra.Command) {
+
+	command.Flags().Bool("strict", false, "Treat warnings as errors and exit with a non-zero exit code")
+	command.Flags().StringSlice("ignore-warning", []string{},
+		"Warning categories to be ignored: "+strings.Join(utils.WARNING_CATEGORIES, ", "))
+}
+
+func readWarningFlags(command *cobra.Command) {
+
+	utils.STRICT, _ = command.Flags().GetBool("strict")
+	utils.IGNORED_WARNINGS, _ = command.Flags().GetStringSlice("ignore-warning")
+	if err := utils.ValidateWarningCategories(utils.IGNORED_WARNINGS); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -52,7 +52,7 @@ func createSPBasicApplication(spName string, spDescription string) {
 
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
-	req, err := http.NewRequest("POST", ADDAPPURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(utils.GetRequestContext(), "POST", ADDAPPURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -141,7 +141,7 @@ func createSPOauthApplication(oauthAppName string, description string, callbackU
 
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
-	req, err := http.NewRequest("POST", ADDAPPURL, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(utils.GetRequestContext(), "POST", ADDAPPURL, bytes.NewBuffer(jsonData))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -177,7 +177,7 @@ func createSPOauthApplication(oauthAppName string, description string, callbackU
 
 		http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
-		req, _ := http.NewRequest("GET", ADDAPPURL+"/"+serviceProviderID+"/export", bytes.NewBuffer(nil))
+		req, _ := http.NewRequestWithContext(utils.GetRequestContext(), "GET", ADDAPPURL+"/"+serviceProviderID+"/export", bytes.NewBuffer(nil))
 		query := req.URL.Query()
 		query.Add("exportSecrets", "true")
 		req.URL.RawQuery = query.Encode()
@@ -103,7 +103,7 @@ func exportApplication(serviceProviderID string, exportlocation string, fileType
 	token := utils.ReadFile()
 
 	var reqUrl = ADDAPPURL + "/" + serviceProviderID + "/exportFile"
-	req, err := http.NewRequest("GET", reqUrl, strings.NewReader(""))
+	req, err := http.NewRequestWithContext(utils.GetRequestContext(), "GET", reqUrl, strings.NewReader(""))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -92,7 +92,7 @@ func importApplication(importFilePath string) {
 		log.Fatal(err)
 	}
 
-	request, err := http.NewRequest("POST", ADDAPPURL, body)
+	request, err := http.NewRequestWithContext(utils.GetRequestContext(), "POST", ADDAPPURL, body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+token)
 	defer request.Body.Close()
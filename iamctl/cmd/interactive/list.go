@@ -136,7 +136,7 @@ func getList() {
 
 	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
-	req, _ := http.NewRequest("GET", GETLISTURL, bytes.NewBuffer(nil))
+	req, _ := http.NewRequestWithContext(utils.GetRequestContext(), "GET", GETLISTURL, bytes.NewBuffer(nil))
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("accept", "*/*")
 	defer req.Body.Close()
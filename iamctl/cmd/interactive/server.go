@@ -90,7 +90,7 @@ func sendOAuthRequest(userName string, password string) (string, string) {
 	body.Set("password", password)
 	body.Set("scope", SCOPE)
 
-	req, err := http.NewRequest("POST", AUTHURL, strings.NewReader(body.Encode()))
+	req, err := http.NewRequestWithContext(utils.GetRequestContext(), "POST", AUTHURL, strings.NewReader(body.Encode()))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -115,7 +115,7 @@ func callArtifactServiceApi(technology string, application string) *http.Respons
 		return nil
 	}
 
-	var req, errReq = http.NewRequest(http.MethodPost, artifactServiceUrl, bytes.NewBuffer(jsonData))
+	var req, errReq = http.NewRequestWithContext(utils.GetRequestContext(), http.MethodPost, artifactServiceUrl, bytes.NewBuffer(jsonData))
 	if errReq != nil {
 		fmt.Println("Error while creating request to artifact-service")
 		return nil
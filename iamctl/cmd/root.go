@@ -37,10 +37,24 @@ var RootCmd = &cobra.Command{
 	Short: utils.ShortAppDesc,
 	Long:  utils.LongAPPConfig,
 	Run:   func(cmd *cobra.Command, args []string) {},
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		utils.StartTracing(cmd.CommandPath())
+		utils.StartMetricsServer()
+		utils.StartInterruptHandling()
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		utils.StopInterruptHandling()
+		utils.FinishTracing()
+	},
 }
 
 func Execute() {
 
+	// The local files of the interactive mode are created only when the tool is run, so that importing the
+	// packages, such as in tests or from the client package, does not write files to the working directory.
+	utils.CreateFile()
+	utils.CreateSampleSPFile()
+
 	if err := RootCmd.Execute(); err != nil {
 		log.Fatalln(err)
 		os.Exit(1)
@@ -49,10 +63,14 @@ func Execute() {
 
 func init() {
 
-	utils.CreateFile()
-	utils.CreateSampleSPFile()
-
 	cobra.OnInitialize(initConfig)
+	RootCmd.PersistentFlags().BoolVar(&utils.DEBUG, "debug", false, "Print debug logs")
+	RootCmd.PersistentFlags().StringVar(&utils.OTEL_ENDPOINT, "otel-endpoint", "",
+		"OTLP/HTTP endpoint of the OpenTelemetry collector to send the traces of the API calls to")
+	RootCmd.PersistentFlags().IntVar(&utils.METRICS_PORT, "metrics-port", 0,
+		"Port to serve the Prometheus metrics on. Available in the builds with the metrics build tag")
+	RootCmd.PersistentFlags().StringVar(&utils.CONFIG_PROFILE, "config-profile", "",
+		"Name of the server config profile to be used from the profiles file")
 }
 
 func initConfig() {
@@ -0,0 +1,211 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package apiresources
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const API_RESOURCE_LIST_PAGE_SIZE = 100
+
+type scope struct {
+	Name        string `json:"name" yaml:"name"`
+	DisplayName string `json:"displayName" yaml:"displayName"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+}
+
+type apiResource struct {
+	Id                    string  `json:"id,omitempty" yaml:"-"`
+	Identifier            string  `json:"identifier" yaml:"identifier"`
+	Name                  string  `json:"name" yaml:"name"`
+	Description           string  `json:"description,omitempty" yaml:"description,omitempty"`
+	RequiresAuthorization bool    `json:"requiresAuthorization" yaml:"requiresAuthorization"`
+	Type                  string  `json:"type,omitempty" yaml:"-"`
+	Scopes                []scope `json:"scopes" yaml:"scopes"`
+}
+
+type apiResourceListResponse struct {
+	ApiResources []apiResource `json:"apiResources"`
+	Links        []struct {
+		Href string `json:"href"`
+		Rel  string `json:"rel"`
+	} `json:"links"`
+}
+
+func getApiResourceList() ([]apiResource, error) {
+
+	var apiResources []apiResource
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(API_RESOURCE_LIST_PAGE_SIZE))
+	for {
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.API_RESOURCES, "?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving API resource list. %w", err)
+		}
+		var response apiResourceListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved API resource list. %w", err)
+		}
+		apiResources = append(apiResources, response.ApiResources...)
+
+		// The API resources are paginated with a cursor given in the link to the next page.
+		after := getNextPageCursor(response)
+		if after == "" || len(response.ApiResources) == 0 {
+			return apiResources, nil
+		}
+		query.Set("after", after)
+	}
+}
+
+func getNextPageCursor(response apiResourceListResponse) string {
+
+	for _, link := range response.Links {
+		if link.Rel != "next" {
+			continue
+		}
+		nextUrl, err := url.Parse(link.Href)
+		if err != nil {
+			return ""
+		}
+		return nextUrl.Query().Get("after")
+	}
+	return ""
+}
+
+func getApiResource(apiResourceId string) (apiResource, error) {
+
+	var apiResourceDetails apiResource
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.API_RESOURCES, url.PathEscape(apiResourceId), nil)
+	if err != nil {
+		return apiResourceDetails, fmt.Errorf("error while retrieving the API resource. %w", err)
+	}
+	err = json.Unmarshal(body, &apiResourceDetails)
+	if err != nil {
+		return apiResourceDetails, fmt.Errorf("error when unmarshalling the retrieved API resource. %w", err)
+	}
+	return apiResourceDetails, nil
+}
+
+func GetAvailableScopes() (map[string]bool, error) {
+
+	// Collect the scopes of all API resources, including the system API resources.
+	apiResources, err := getApiResourceList()
+	if err != nil {
+		return nil, err
+	}
+	scopes := make(map[string]bool)
+	for _, apiResource := range apiResources {
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.API_RESOURCES, url.PathEscape(apiResource.Id)+"/scopes", nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving the scopes of API resource: %s. %w", apiResource.Identifier, err)
+		}
+		var apiResourceScopes []scope
+		err = json.Unmarshal(body, &apiResourceScopes)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved scopes. %w", err)
+		}
+		for _, scope := range apiResourceScopes {
+			scopes[scope.Name] = true
+		}
+	}
+	return scopes, nil
+}
+
+func getScopesNotIn(scopes []scope, existingScopes []scope) []scope {
+
+	var missingScopes []scope
+scopes:
+	for _, scope := range scopes {
+		for _, existingScope := range existingScopes {
+			if scope.Name == existingScope.Name {
+				continue scopes
+			}
+		}
+		missingScopes = append(missingScopes, scope)
+	}
+	return missingScopes
+}
+
+func getApiResourceFileName(apiResourceName string) string {
+
+	return strings.ReplaceAll(apiResourceName, "/", "_")
+}
+
+func getApiResourceKeywordMapping(apiResourceFileName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(apiResourceFileName, utils.KEYWORD_CONFIGS.ApiResourceConfigs)
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	// API resources are matched by the identifier in the file, since the name of an API resource is not unique.
+	importFilePath := filepath.Join(inputDirPath, utils.API_RESOURCES)
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil && !os.IsNotExist(err) {
+		return utils.ImportPlan{}, fmt.Errorf("error when reading the API resources: %s", err)
+	}
+	var localIdentifiers []string
+	for _, file := range files {
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		if file.IsDir() || !utils.IsResourceIncluded(fileName) ||
+			utils.IsResourceExcluded(fileName, utils.TOOL_CONFIGS.ApiResourceConfigs) {
+			continue
+		}
+		localApiResource, err := readLocalApiResource(filepath.Join(importFilePath, file.Name()))
+		if err == nil {
+			localIdentifiers = append(localIdentifiers, localApiResource.apiResource.Identifier)
+		}
+	}
+	apiResources, err := getApiResourceList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedApiResources []utils.DeployedResource
+	for _, apiResource := range apiResources {
+		isDeletable := !utils.IsSystemApiResource(apiResource.Identifier, apiResource.Type) &&
+			!utils.IsResourceExcluded(apiResource.Name, utils.TOOL_CONFIGS.ApiResourceConfigs)
+		deployedApiResources = append(deployedApiResources, utils.DeployedResource{Name: apiResource.Identifier, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.API_RESOURCES, localIdentifiers, deployedApiResources, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the API resources that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	apiResources, err := getApiResourceList()
+	if err != nil {
+		return nil, err
+	}
+	var apiResourceFileNames []string
+	for _, apiResource := range apiResources {
+		apiResourceFileNames = append(apiResourceFileNames, getApiResourceFileName(apiResource.Name))
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.API_RESOURCES), apiResourceFileNames)
+}
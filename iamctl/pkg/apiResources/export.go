@@ -0,0 +1,112 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package apiresources
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each API resource to a separate file in the ApiResources folder.
+	log.Println("Exporting API resources...")
+	exportFilePath = filepath.Join(exportFilePath, utils.API_RESOURCES)
+
+	if utils.IsResourceTypeExcluded(utils.API_RESOURCES) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.API_RESOURCES)
+	apiResources, err := getApiResourceList()
+	if err != nil {
+		log.Println("Error while retrieving API resource list.", err)
+		return
+	}
+
+	// Skip the system API resources since they are created by the server in each environment.
+	var exportedApiResources []apiResource
+	var apiResourceFileNames []string
+	for _, apiResource := range apiResources {
+		if utils.IsSystemApiResource(apiResource.Identifier, apiResource.Type) {
+			utils.LogDebug("Skipping system API resource: " + apiResource.Identifier)
+			continue
+		}
+		exportedApiResources = append(exportedApiResources, apiResource)
+		apiResourceFileNames = append(apiResourceFileNames, getApiResourceFileName(apiResource.Name))
+	}
+
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, apiResourceFileNames)
+	}
+
+	sort.SliceStable(exportedApiResources, func(i, j int) bool {
+		return utils.GetResourcePriority(exportedApiResources[i].Name) < utils.GetResourcePriority(exportedApiResources[j].Name)
+	})
+	utils.StartResourceProgress(utils.API_RESOURCES, len(exportedApiResources))
+	for _, apiResource := range exportedApiResources {
+		if utils.IsResourceExcluded(apiResource.Name, utils.TOOL_CONFIGS.ApiResourceConfigs) {
+			utils.ReportSkippedResource(utils.API_RESOURCES, apiResource.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.API_RESOURCES, apiResource.Name)
+			continue
+		}
+		log.Println("Exporting API resource: ", apiResource.Name)
+		utils.EmitResourceStarted(utils.API_RESOURCES, apiResource.Name, utils.EXPORT)
+		err := exportApiResource(apiResource.Id, apiResource.Name, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.API_RESOURCES, apiResource.Name)
+			log.Printf("Error while exporting API resource: %s. %s", apiResource.Name, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResource.Name, utils.EXPORT)
+			log.Println("API resource exported successfully: ", apiResource.Name)
+		}
+	}
+}
+
+func exportApiResource(apiResourceId string, apiResourceName string, outputDirPath string) error {
+
+	// The API resource list does not contain the scopes of the API resources.
+	apiResource, err := getApiResource(apiResourceId)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(apiResource)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	fileName := getApiResourceFileName(apiResourceName)
+	exportedFileName := filepath.Join(outputDirPath, fileName+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getApiResourceKeywordMapping(fileName),
+		utils.API_RESOURCES)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,270 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package apiresources
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+type localApiResource struct {
+	fileName    string
+	content     string
+	apiResource apiResource
+}
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.API_RESOURCES)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.API_RESOURCES) {
+		return
+	}
+
+	log.Println("Importing API resources...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing API resources: ", err)
+		return
+	}
+	apiResources, err := getApiResourceList()
+	if err != nil {
+		log.Println("Error importing API resources: ", err)
+		return
+	}
+	deployedApiResources := make(map[string]apiResource)
+	for _, apiResource := range apiResources {
+		deployedApiResources[apiResource.Identifier] = apiResource
+	}
+
+	// API resources are matched by the identifier in the file, since the name of an API resource is not unique.
+	var localApiResources []localApiResource
+	isLocalContentValid := true
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		localApiResource, err := readLocalApiResource(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			isLocalContentValid = false
+			if utils.IsResourceIncluded(fileName) {
+				utils.UpdateFailureSummary(utils.API_RESOURCES, fileName)
+				log.Printf("Invalid file configurations for API resource: %s. %s", fileName, err)
+			}
+			continue
+		}
+		localApiResources = append(localApiResources, localApiResource)
+	}
+	if utils.IsDeleteAllowed() {
+		if isLocalContentValid {
+			removeDeletedDeployedApiResources(apiResources, localApiResources)
+		} else {
+			log.Println("Skipping the deletion of API resources since some of the local files are invalid.")
+		}
+	}
+
+	sort.SliceStable(localApiResources, func(i, j int) bool {
+		return utils.GetResourcePriority(localApiResources[i].apiResource.Name) <
+			utils.GetResourcePriority(localApiResources[j].apiResource.Name)
+	})
+	utils.StartResourceProgress(utils.API_RESOURCES, len(localApiResources))
+	for _, localApiResource := range localApiResources {
+		apiResourceName := localApiResource.apiResource.Name
+		if !utils.IsResourceIncluded(localApiResource.fileName) {
+			utils.AddFilteredResourceToSummary(utils.API_RESOURCES, apiResourceName)
+			continue
+		}
+		if utils.IsSystemApiResource(localApiResource.apiResource.Identifier, "") {
+			log.Println("Skipping system API resource: " + localApiResource.apiResource.Identifier)
+			continue
+		}
+		if utils.IsResourceExcluded(apiResourceName, utils.TOOL_CONFIGS.ApiResourceConfigs) {
+			utils.ReportSkippedResource(utils.API_RESOURCES, apiResourceName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.API_RESOURCES, apiResourceName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.API_RESOURCES, apiResourceName, utils.IMPORT)
+		deployedApiResource, isUpdate := deployedApiResources[localApiResource.apiResource.Identifier]
+		if isUpdate && utils.IsSystemApiResource(deployedApiResource.Identifier, deployedApiResource.Type) {
+			utils.UpdateFailureSummary(utils.API_RESOURCES, apiResourceName)
+			log.Printf("Error when importing API resource: %s. The identifier belongs to a system API resource.", apiResourceName)
+			continue
+		}
+		var err error
+		if isUpdate {
+			err = updateApiResource(localApiResource, deployedApiResource.Id)
+		} else {
+			err = createApiResource(localApiResource)
+		}
+		if err != nil {
+			utils.UpdateFailureSummary(utils.API_RESOURCES, apiResourceName)
+			log.Printf("Error when importing API resource: %s. %s", apiResourceName, err)
+		}
+	}
+}
+
+func readLocalApiResource(importFilePath string) (localApiResource, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return localApiResource{}, fmt.Errorf("error when reading the file for API resource: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getApiResourceKeywordMapping(fileName))
+
+	var apiResource apiResource
+	err = yaml.Unmarshal([]byte(modifiedFileData), &apiResource)
+	if err != nil {
+		return localApiResource{}, fmt.Errorf("invalid file content for API resource: %s", err)
+	}
+	if apiResource.Identifier == "" || apiResource.Name == "" {
+		return localApiResource{}, fmt.Errorf("the identifier and name attributes are required")
+	}
+	if apiResource.Scopes == nil {
+		apiResource.Scopes = []scope{}
+	}
+	return localApiResource{fileName: fileName, content: modifiedFileData, apiResource: apiResource}, nil
+}
+
+func createApiResource(localApiResource localApiResource) error {
+
+	apiResourceName := localApiResource.apiResource.Name
+	utils.CheckImportContent(utils.API_RESOURCES, apiResourceName, localApiResource.content)
+
+	log.Println("Creating new API resource: " + apiResourceName)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.API_RESOURCES, "", localApiResource.apiResource)
+	if err != nil {
+		return fmt.Errorf("error when creating API resource: %s", err)
+	}
+	utils.UpdateImportState(utils.API_RESOURCES, apiResourceName, localApiResource.content)
+	utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResourceName, utils.IMPORT)
+	log.Println("API resource created successfully.")
+	return nil
+}
+
+func updateApiResource(localApiResource localApiResource, apiResourceId string) error {
+
+	apiResourceName := localApiResource.apiResource.Name
+	utils.CheckImportContent(utils.API_RESOURCES, apiResourceName, localApiResource.content)
+	if utils.IsImportStateUnchanged(utils.API_RESOURCES, apiResourceName, localApiResource.content) {
+		log.Println("API resource is unchanged since the last import. Skipping update: " + apiResourceName)
+		utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResourceName, utils.UNCHANGED)
+		return nil
+	}
+
+	deployedApiResource, err := getApiResource(apiResourceId)
+	if err != nil {
+		return err
+	}
+	if deployedApiResource.RequiresAuthorization != localApiResource.apiResource.RequiresAuthorization {
+		utils.LogWarning(utils.WARNING_CONFIG, fmt.Sprintf("%s: %s requires authorization is not updated since it "+
+			"cannot be changed after the API resource is created.", utils.API_RESOURCES, apiResourceName))
+	}
+
+	// Scopes that are removed locally are kept, since they can be used by the roles and applications.
+	addedScopes := getScopesNotIn(localApiResource.apiResource.Scopes, deployedApiResource.Scopes)
+	if removedScopes := getScopesNotIn(deployedApiResource.Scopes, localApiResource.apiResource.Scopes); len(removedScopes) > 0 {
+		var scopeNames []string
+		for _, scope := range removedScopes {
+			scopeNames = append(scopeNames, scope.Name)
+		}
+		log.Printf("Scopes of API resource: %s that are not available locally are not removed: %s",
+			apiResourceName, strings.Join(scopeNames, ", "))
+	}
+	if !utils.FORCE_IMPORT && len(addedScopes) == 0 && deployedApiResource.Name == apiResourceName &&
+		deployedApiResource.Description == localApiResource.apiResource.Description {
+		log.Println("API resource is unchanged. Skipping update: " + apiResourceName)
+		utils.UpdateImportState(utils.API_RESOURCES, apiResourceName, localApiResource.content)
+		utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResourceName, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating API resource: " + apiResourceName)
+	payload := map[string]interface{}{
+		"name":        apiResourceName,
+		"description": localApiResource.apiResource.Description,
+	}
+	if len(addedScopes) > 0 {
+		payload["addedScopes"] = addedScopes
+	}
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.API_RESOURCES, url.PathEscape(apiResourceId), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating API resource: %s", err)
+	}
+	utils.UpdateImportState(utils.API_RESOURCES, apiResourceName, localApiResource.content)
+	utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResourceName, utils.UPDATE)
+	log.Println("API resource updated successfully.")
+	return nil
+}
+
+func removeDeletedDeployedApiResources(deployedApiResources []apiResource, localApiResources []localApiResource) {
+
+	// Remove deployed API resources that do not exist locally.
+	var apiResourcesToDelete []apiResource
+deployedResources:
+	for _, apiResource := range deployedApiResources {
+		for _, localApiResource := range localApiResources {
+			if apiResource.Identifier == localApiResource.apiResource.Identifier {
+				continue deployedResources
+			}
+		}
+		if utils.IsSystemApiResource(apiResource.Identifier, apiResource.Type) ||
+			utils.IsResourceExcluded(apiResource.Name, utils.TOOL_CONFIGS.ApiResourceConfigs) {
+			continue
+		}
+		apiResourcesToDelete = append(apiResourcesToDelete, apiResource)
+	}
+
+	var apiResourceNames []string
+	for _, apiResource := range apiResourcesToDelete {
+		apiResourceNames = append(apiResourceNames, apiResource.Name)
+	}
+	if !utils.ConfirmDeletion(utils.API_RESOURCES, apiResourceNames) {
+		return
+	}
+	for _, apiResource := range apiResourcesToDelete {
+		log.Println("API resource not found locally. Deleting API resource: ", apiResource.Name)
+		utils.EmitResourceStarted(utils.API_RESOURCES, apiResource.Name, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), apiResource.Id, utils.API_RESOURCES)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.API_RESOURCES, apiResource.Name)
+			log.Println("Error deleting API resource: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.API_RESOURCES, apiResource.Name, utils.DELETE)
+	}
+}
@@ -0,0 +1,196 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package applications
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Key of the access control configurations in the exported application file. The configurations are not part of
+// the application file of the server, so they are applied separately after the application is imported.
+const ACCESS_CONTROL_CONFIG = "accessControlConfig"
+
+type AccessControlConfig struct {
+	Groups []string `yaml:"groups"`
+}
+
+type accessControlResponse struct {
+	AccessControl struct {
+		Groups []accessControlGroup `json:"groups"`
+	} `json:"accessControl"`
+}
+
+type accessControlGroup struct {
+	Id   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type groupListResponse struct {
+	Resources []struct {
+		Id          string `json:"id"`
+		DisplayName string `json:"displayName"`
+	} `json:"Resources"`
+}
+
+func addAccessControlConfig(appId string, fileContent []byte) ([]byte, error) {
+
+	// Add the groups allowed to access the application to the application file.
+	groups, err := getAccessControlGroups(appId)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return fileContent, nil
+	}
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	appYaml[ACCESS_CONTROL_CONFIG] = AccessControlConfig{Groups: groups}
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the access control configurations: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func extractAccessControlConfig(fileData string) (string, *AccessControlConfig, error) {
+
+	// Remove the access control configurations from the application file, since the server does not accept them.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	configYaml, ok := appYaml[ACCESS_CONTROL_CONFIG]
+	if !ok {
+		return fileData, nil, nil
+	}
+
+	var config AccessControlConfig
+	configContent, err := yaml.Marshal(configYaml)
+	if err == nil {
+		err = yaml.Unmarshal(configContent, &config)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid access control configurations: %s", err)
+	}
+	delete(appYaml, ACCESS_CONTROL_CONFIG)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when removing the access control configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), &config, nil
+}
+
+// Returns the application file without the access control configurations, and the groups of the target environment
+// to be allowed. The groups are nil if the file does not have access control configurations.
+func resolveAccessControl(fileData string) (string, *[]accessControlGroup, error) {
+
+	appFileData, config, err := extractAccessControlConfig(fileData)
+	if err != nil || config == nil {
+		return appFileData, nil, err
+	}
+	groups, err := getGroupIds(config.Groups)
+	if err != nil {
+		return "", nil, err
+	}
+	return appFileData, &groups, nil
+}
+
+func getAccessControlGroups(appId string) ([]string, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, appId+"?attributes=accessControl", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the access control configurations: %s", err)
+	}
+	var response accessControlResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the access control configurations: %s", err)
+	}
+	var groups []string
+	for _, group := range response.AccessControl.Groups {
+		groups = append(groups, group.Name)
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+func getGroupIds(groupNames []string) ([]accessControlGroup, error) {
+
+	// Resolve the group IDs of the target environment, which also validates that the groups exist.
+	var groups []accessControlGroup
+	var missingGroups []string
+	for _, groupName := range groupNames {
+		query := url.Values{"filter": {"displayName eq " + groupName}}.Encode()
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.GROUPS, "?"+query, nil)
+		if err != nil {
+			return nil, fmt.Errorf("error when retrieving the group: %s. %s", groupName, err)
+		}
+		var response groupListResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the group: %s. %s", groupName, err)
+		}
+		groupId := ""
+		for _, group := range response.Resources {
+			if strings.EqualFold(group.DisplayName, groupName) {
+				groupId = group.Id
+				break
+			}
+		}
+		if groupId == "" {
+			missingGroups = append(missingGroups, groupName)
+			continue
+		}
+		groups = append(groups, accessControlGroup{Id: groupId})
+	}
+	if len(missingGroups) > 0 {
+		return nil, fmt.Errorf("groups in the access control configurations do not exist: %s",
+			strings.Join(missingGroups, ", "))
+	}
+	return groups, nil
+}
+
+func setAccessControl(appId string, appName string, groups []accessControlGroup) error {
+
+	if appId == "" {
+		return fmt.Errorf("application: %s is not found to set the access control configurations", appName)
+	}
+	// An empty list of groups removes the access restriction of the application.
+	if groups == nil {
+		groups = []accessControlGroup{}
+	}
+	payload := map[string]interface{}{
+		"accessControl": map[string]interface{}{"groups": groups},
+	}
+	if _, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.APPLICATIONS, appId, payload); err != nil {
+		return fmt.Errorf("error when setting the access control configurations: %s", err)
+	}
+	log.Println("Access control configurations set for application: " + appName)
+	return nil
+}
@@ -0,0 +1,112 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func Adopt(appName string, outputDirPath string, format string, dryRun bool) error {
+
+	// Export a single application that is not yet managed locally to the Applications folder.
+	exportFilePath := filepath.Join(outputDirPath, utils.APPLICATIONS)
+
+	apps, err := getAppList()
+	if err != nil {
+		return err
+	}
+	var appId string
+	for _, app := range apps {
+		if app.Name == appName {
+			appId = app.Id
+			break
+		}
+	}
+	if appId == "" {
+		return fmt.Errorf("application: %s is not found in the target environment", appName)
+	}
+
+	// The file name given to the application is recorded, in case the name is not valid as a file name.
+	utils.StartFileNameMapping(exportFilePath)
+	excludeSecrets := utils.AreSecretsExcluded(utils.TOOL_CONFIGS.ApplicationConfigs)
+	exportedFileName, modifiedFile, serverContent, err := getExportedAppContent(appId, exportFilePath, format, excludeSecrets)
+	if err != nil {
+		return err
+	}
+	if utils.ResourceFileExists(exportedFileName) {
+		return fmt.Errorf("a managed file already exists for application: %s at %s. "+
+			"Use the exportAll command to update managed resources or remove the file to adopt the application again",
+			appName, exportedFileName)
+	}
+
+	// Replace environment specific values with the keywords defined in the keyword configs.
+	content, err := utils.AddKeywordPlaceholders(string(modifiedFile), getAppKeywordMapping(appName))
+	if err != nil {
+		return fmt.Errorf("error when adding the keyword placeholders: %s", err)
+	}
+	modifiedFile = []byte(content)
+
+	if dryRun {
+		fmt.Printf("Application: %s will be adopted to %s with the following content:\n", appName, exportedFileName)
+		fmt.Println(string(modifiedFile))
+		return nil
+	}
+
+	os.MkdirAll(exportFilePath, 0700)
+	if err := writeExportedApp(exportFilePath, format, exportedFileName, modifiedFile, serverContent); err != nil {
+		return fmt.Errorf("error when writing the adopted application to file: %w", err)
+	}
+	if err := utils.WriteFileNameMapping(exportFilePath); err != nil {
+		return err
+	}
+	if err := recordAdoptedApp(outputDirPath, exportedFileName, appName); err != nil {
+		return err
+	}
+	log.Println("Application adopted successfully: ", appName)
+	fmt.Println("Created file: " + exportedFileName)
+	return nil
+}
+
+// Record the adopted application in the import state, so that the next import does not update the application
+// unless the file is changed, and the application is not treated as an externally managed application.
+func recordAdoptedApp(inputDirPath string, appFilePath string, appName string) error {
+
+	fileBytes, err := utils.ReadResourceFile(appFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the adopted application file: %s", err)
+	}
+	fileBytes, err = injectReferencedFiles(appFilePath, fileBytes)
+	if err != nil {
+		return fmt.Errorf("error when reading the files referenced by the adopted application: %s", err)
+	}
+	content := utils.RemoveSecretMasks(utils.ReplaceKeywords(string(fileBytes), getAppKeywordMapping(appName)))
+
+	utils.LoadImportState(inputDirPath)
+	utils.UpdateImportState(utils.APPLICATIONS, appName, content)
+	utils.MarkResourceAdopted(utils.APPLICATIONS, appName)
+	if err := utils.SaveImportState(); err != nil {
+		return fmt.Errorf("error when saving the import state: %s", err)
+	}
+	return nil
+}
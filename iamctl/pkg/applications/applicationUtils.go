@@ -25,17 +25,21 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strings"
-	"text/tabwriter"
+	"time"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 	"gopkg.in/yaml.v2"
 )
 
+// Key of the application name in the exported application file.
+const APP_NAME_KEY = "applicationName"
+
 type Application struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	TemplateId  string `json:"templateId"`
+	IsSystemApp bool   `json:"isSystemApp"`
 }
 
 type AppList struct {
@@ -45,40 +49,90 @@ type AppList struct {
 
 type AppConfig struct {
 	ApplicationName string `yaml:"applicationName"`
+	OwnerId         string `yaml:"ownerId,omitempty"`
+	OwnerName       string `yaml:"ownerName,omitempty"`
+}
+
+type AppOwner struct {
+	UserName        string `yaml:"userName"`
+	UserStoreDomain string `yaml:"userStoreDomain"`
+}
+
+type AppOwnerConfig struct {
+	Owner AppOwner `yaml:"owner"`
 }
 
+type InboundAuthType string
+
+const (
+	INBOUND_AUTH_NONE   InboundAuthType = ""
+	INBOUND_AUTH_OAUTH2 InboundAuthType = utils.OAUTH2
+	INBOUND_AUTH_SAML   InboundAuthType = utils.SAMLSSO
+)
+
 type AuthConfig struct {
+	ApplicationName             string `yaml:"applicationName"`
+	CertificateContent          string `yaml:"certificateContent"`
+	JwksUri                     string `yaml:"jwksUri"`
 	InboundAuthenticationConfig struct {
 		InboundAuthenticationRequestConfigs []struct {
 			InboundAuthType              string `yaml:"inboundAuthType"`
 			InboundAuthKey               string `yaml:"inboundAuthKey"`
 			InboundConfigurationProtocol struct {
-				OauthConsumerSecret string `yaml:"oauthConsumerSecret"`
+				OauthConsumerSecret         string   `yaml:"oauthConsumerSecret"`
+				TokenEndpointAuthMethod     string   `yaml:"tokenEndpointAuthMethod"`
+				TlsClientAuthSubjectDN      string   `yaml:"tlsClientAuthSubjectDN"`
+				Issuer                      string   `yaml:"issuer"`
+				AssertionConsumerUrls       []string `yaml:"assertionConsumerUrls"`
+				DefaultAssertionConsumerUrl string   `yaml:"defaultAssertionConsumerUrl"`
 			} `yaml:"inboundConfigurationProtocol"`
 		} `yaml:"inboundAuthenticationRequestConfigs"`
 	} `yaml:"inboundAuthenticationConfig"`
 }
 
-func getDeployedAppNames() []string {
+func (app Application) isSystem() bool {
+
+	return utils.IsSystemApplication(app.Name, app.IsSystemApp)
+}
+
+// Check whether a local application is a system application, by the name or by the deployed application of the name.
+func isSystemAppName(appName string, deployedApps []Application) bool {
+
+	for _, app := range deployedApps {
+		if app.Name == appName {
+			return app.isSystem()
+		}
+	}
+	return utils.IsSystemApplication(appName, false)
+}
+
+func getDeployedAppNames() ([]string, error) {
 
-	apps := getAppList()
+	apps, err := getAppList()
+	if err != nil {
+		return nil, err
+	}
 	var appNames []string
 	for _, app := range apps {
 		appNames = append(appNames, app.Name)
 	}
-	return appNames
+	return appNames, nil
 }
 
-func getAppList() (spIdList []Application) {
+func getAppList() ([]Application, error) {
 
 	totalAppCount, err := getTotalAppCount()
 	if err != nil {
+		// The list request of a cancelled run fails in the same way, so it is not sent.
+		if ctxErr := utils.GetRequestContext().Err(); ctxErr != nil {
+			return nil, fmt.Errorf("error while retrieving the application list. %w", ctxErr)
+		}
 		log.Println("Error while retrieving application count. Retrieving only the default count.", err)
 	}
 	var list AppList
-	resp, err := utils.SendGetListRequest(utils.APPLICATIONS, totalAppCount)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.APPLICATIONS, totalAppCount)
 	if err != nil {
-		log.Println("Error while retrieving application list", err)
+		return nil, fmt.Errorf("error while retrieving the application list. %w", err)
 	}
 	defer resp.Body.Close()
 
@@ -86,31 +140,23 @@ func getAppList() (spIdList []Application) {
 	if statusCode == 200 {
 		body, err := ioutil.ReadAll(resp.Body)
 		if err != nil {
-			log.Fatalln(err)
+			return nil, fmt.Errorf("error when reading the retrieved application list. %w", err)
 		}
-		writer := new(tabwriter.Writer)
-		writer.Init(os.Stdout, 8, 8, 0, '\t', 0)
-		defer writer.Flush()
-
 		err = json.Unmarshal(body, &list)
 		if err != nil {
-			log.Fatalln(err)
+			return nil, fmt.Errorf("error when unmarshalling the retrieved application list. %w", err)
 		}
-		resp.Body.Close()
-
-		spIdList = list.Applications
+		return list.Applications, nil
 	} else if error, ok := utils.ErrorCodes[statusCode]; ok {
-		log.Println(error)
-	} else {
-		log.Println("Error while retrieving application list")
+		return nil, fmt.Errorf("error while retrieving the application list. Status code: %d, Error: %s", statusCode, error)
 	}
-	return spIdList
+	return nil, fmt.Errorf("error while retrieving the application list. Status code: %d", statusCode)
 }
 
 func getTotalAppCount() (count int, err error) {
 
 	var list AppList
-	resp, err := utils.SendGetListRequest(utils.APPLICATIONS, -1)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.APPLICATIONS, -1)
 	if err != nil {
 		return -1, fmt.Errorf("failed to retrieve available app list. %w", err)
 	}
@@ -138,25 +184,77 @@ func getTotalAppCount() (count int, err error) {
 
 func getAppKeywordMapping(appName string) map[string]interface{} {
 
-	if utils.KEYWORD_CONFIGS.ApplicationConfigs != nil {
-		return utils.ResolveAdvancedKeywordMapping(appName, utils.KEYWORD_CONFIGS.ApplicationConfigs)
-	}
-	return utils.KEYWORD_CONFIGS.KeywordMappings
+	return utils.ResolveAdvancedKeywordMapping(appName, utils.KEYWORD_CONFIGS.ApplicationConfigs)
 }
 
-func isOauthApp(fileData string) (bool, error) {
+// Get the inbound authentication type of the application. The OAuth type takes precedence when the application has
+// both OAuth and SAML inbound configurations, as the OAuth configurations need additional handling when importing.
+func getInboundAuthType(fileData string) (InboundAuthType, error) {
 
 	config, err := unmarshalAuthConfig([]byte(fileData))
 	if err != nil {
-		return false, err
+		return INBOUND_AUTH_NONE, err
 	}
 
+	authType := INBOUND_AUTH_NONE
 	for _, requestConfig := range config.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
-		if strings.ToLower(requestConfig.InboundAuthType) == utils.OAUTH2 {
-			return true, nil
+		switch strings.ToLower(requestConfig.InboundAuthType) {
+		case utils.OAUTH2:
+			return INBOUND_AUTH_OAUTH2, nil
+		case utils.SAMLSSO:
+			authType = INBOUND_AUTH_SAML
 		}
 	}
-	return false, nil
+	return authType, nil
+}
+
+// Remove the OAuth inbound protocol from the application content, so that the application is created without it.
+func removeOauthInboundConfig(fileData string) (string, error) {
+
+	appConfig, err := unmarshalAppConfig(fileData)
+	if err != nil {
+		return fileData, err
+	}
+	for _, item := range appConfig {
+		inboundConfig, ok := item.Value.(yaml.MapSlice)
+		if item.Key != "inboundAuthenticationConfig" || !ok {
+			continue
+		}
+		for i, field := range inboundConfig {
+			requestConfigs, ok := field.Value.([]interface{})
+			if field.Key != "inboundAuthenticationRequestConfigs" || !ok {
+				continue
+			}
+			var remainingConfigs []interface{}
+			for _, requestConfig := range requestConfigs {
+				if isOauthRequestConfig(requestConfig) {
+					continue
+				}
+				remainingConfigs = append(remainingConfigs, requestConfig)
+			}
+			inboundConfig[i].Value = remainingConfigs
+		}
+	}
+	modifiedContent, err := yaml.Marshal(appConfig)
+	if err != nil {
+		return fileData, fmt.Errorf("error when removing the OAuth inbound configuration: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), nil
+}
+
+func isOauthRequestConfig(requestConfig interface{}) bool {
+
+	config, ok := requestConfig.(yaml.MapSlice)
+	if !ok {
+		return false
+	}
+	for _, field := range config {
+		authType, ok := field.Value.(string)
+		if field.Key == "inboundAuthType" && ok && strings.ToLower(authType) == utils.OAUTH2 {
+			return true
+		}
+	}
+	return false
 }
 
 func unmarshalAuthConfig(data []byte) (AuthConfig, error) {
@@ -168,20 +266,16 @@ func unmarshalAuthConfig(data []byte) (AuthConfig, error) {
 	return config, nil
 }
 
+// Mask the oauthConsumerSecret and the other sensitive fields given in the server configs.
 func maskOAuthConsumerSecret(fileContent []byte) []byte {
 
-	// Find and replace the value of oauthConsumerSecret with a mask.
-	pattern := "(?m)(^\\s*oauthConsumerSecret:\\s*)null\\s*$"
-	re := regexp.MustCompile(pattern)
-	maskedContent := re.ReplaceAllString(string(fileContent), "${1}"+utils.SENSITIVE_FIELD_MASK)
-
-	return []byte(maskedContent)
+	return utils.MaskSensitiveFields(fileContent, utils.GetSensitiveFields())
 }
 
 func isToolMgtApp(file os.FileInfo, importFilePath string) (bool, error) {
 
 	appFilePath := filepath.Join(importFilePath, file.Name())
-	fileData, err := ioutil.ReadFile(appFilePath)
+	fileData, err := utils.ReadResourceFile(appFilePath)
 	if err != nil {
 		return false, fmt.Errorf("failed to read file: %s", err.Error())
 	}
@@ -193,8 +287,13 @@ func isToolMgtApp(file os.FileInfo, importFilePath string) (bool, error) {
 
 	for _, requestConfig := range config.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
 		if requestConfig.InboundAuthKey == utils.SERVER_CONFIGS.ClientId {
-			appName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+			// The file name is not used, since it can differ from the application name.
+			appName := config.ApplicationName
+			if appName == "" {
+				appName = utils.ResolveResourceName(appFilePath)
+			}
 			log.Printf("Info: Tool Management App: %s is excluded from deletion.\n", appName)
+			utils.ReportSkippedResource(utils.APPLICATIONS, appName, utils.JUNIT_SKIPPED_TOOL_APP)
 			return true, nil
 		}
 	}
@@ -217,3 +316,253 @@ func isOauthSecretGiven(modifiedFileData string) (bool, error) {
 	}
 	return false, nil
 }
+
+func ValidateClientAuthConfig(fileData string) error {
+
+	config, err := unmarshalAuthConfig([]byte(fileData))
+	if err != nil {
+		return err
+	}
+
+	for _, requestConfig := range config.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
+		if strings.ToLower(requestConfig.InboundAuthType) != utils.OAUTH2 {
+			continue
+		}
+		protocol := requestConfig.InboundConfigurationProtocol
+		switch protocol.TokenEndpointAuthMethod {
+		case utils.TLS_CLIENT_AUTH:
+			if protocol.TlsClientAuthSubjectDN == "" {
+				return fmt.Errorf("%s is configured but tlsClientAuthSubjectDN is not provided", utils.TLS_CLIENT_AUTH)
+			}
+			if !utils.IsValidSubjectDN(protocol.TlsClientAuthSubjectDN) {
+				return fmt.Errorf("invalid tlsClientAuthSubjectDN: %s", protocol.TlsClientAuthSubjectDN)
+			}
+		case utils.SELF_SIGNED_TLS_CLIENT_AUTH:
+			if config.CertificateContent == "" && config.JwksUri == "" {
+				return fmt.Errorf("%s is configured but neither a certificate nor a JWKS URI is provided", utils.SELF_SIGNED_TLS_CLIENT_AUTH)
+			}
+		}
+	}
+	return nil
+}
+
+func checkCertificateExpiry(appName string, fileData string) {
+
+	config, err := unmarshalAuthConfig([]byte(fileData))
+	if err != nil || config.CertificateContent == "" {
+		return
+	}
+	expiryTime, err := utils.GetCertificateExpiry(config.CertificateContent)
+	if err != nil {
+		log.Printf("Unable to read the certificate of application: %s. %s", appName, err)
+		return
+	}
+	if time.Until(expiryTime) < utils.CERTIFICATE_EXPIRY_WARNING_PERIOD {
+		utils.LogWarning(utils.WARNING_EXPIRING_CERTIFICATE, fmt.Sprintf("Certificate of application: %s expires on %s.",
+			appName, expiryTime.Format("2006-01-02")))
+	}
+}
+
+func GetDeployedAppIds() (map[string]string, error) {
+
+	// Application names are unique in an environment, so the resources linked to applications are matched by the name.
+	apps, err := getAppList()
+	if err != nil {
+		return nil, err
+	}
+	appIds := make(map[string]string)
+	for _, app := range apps {
+		appIds[app.Name] = app.Id
+	}
+	return appIds, nil
+}
+
+func getAppId(appName string) string {
+
+	apps, err := getAppList()
+	if err != nil {
+		log.Printf("Error when resolving the id of application: %s. %s", appName, err)
+		return ""
+	}
+	for _, app := range apps {
+		if app.Name == appName {
+			return app.Id
+		}
+	}
+	return ""
+}
+
+func isAppUnchanged(appId string, appName string, modifiedFileData string) bool {
+
+	deployedContent, err := getDeployedAppContent(appId)
+	if err != nil {
+		log.Printf("Error when retrieving the deployed application: %s to compare changes. %s", appName, err)
+		return false
+	}
+	deployedContent, err = addAccessControlConfig(appId, deployedContent)
+	if err != nil {
+		log.Printf("Error when reading the deployed application: %s to compare changes. %s", appName, err)
+		return false
+	}
+	// The owner keys are compared only if they are managed in the local file.
+	var localConfig AppConfig
+	if yaml.Unmarshal(utils.ReplaceTypeTags([]byte(modifiedFileData)), &localConfig) == nil &&
+		(localConfig.OwnerName != "" || localConfig.OwnerId != "") {
+		deployedContent, err = addAppOwner(deployedContent)
+		if err != nil {
+			log.Printf("Error when reading the deployed application: %s to compare changes. %s", appName, err)
+			return false
+		}
+	}
+	// Token configurations are compared only if they are managed in the local file.
+	if _, tokenConfig, err := extractTokenConfig(modifiedFileData); err == nil && tokenConfig != nil {
+		deployedContent, err = addTokenConfig(appId, deployedContent)
+		if err != nil {
+			log.Printf("Error when reading the deployed application: %s to compare changes. %s", appName, err)
+			return false
+		}
+	}
+	// Provisioning configurations are compared only if they are managed in the local file.
+	if _, provisioningConfig, err := extractProvisioningConfig(modifiedFileData); err == nil && provisioningConfig != nil {
+		deployedContent, err = addProvisioningConfig(appId, deployedContent)
+		if err != nil {
+			log.Printf("Error when reading the deployed application: %s to compare changes. %s", appName, err)
+			return false
+		}
+	}
+	// Multi-factor authentication configurations are compared only if they are managed in the local file.
+	if _, mfaConfig, err := extractMfaConfig(modifiedFileData); err == nil && mfaConfig != nil {
+		deployedContent, err = addMfaConfig(deployedContent)
+		if err != nil {
+			log.Printf("Error when reading the deployed application: %s to compare changes. %s", appName, err)
+			return false
+		}
+	}
+	return utils.IsContentEqual([]byte(modifiedFileData), deployedContent, utils.GetIgnoredFields(utils.APPLICATIONS))
+}
+
+// Get the configuration of a deployed application as returned by the export API, with the secrets excluded.
+func getDeployedAppContent(appId string) ([]byte, error) {
+
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), appId, utils.MEDIA_TYPE_YAML, utils.APPLICATIONS, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	deployedContent, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the response body: %s", err)
+	}
+	return deployedContent, nil
+}
+
+func isExternallyManagedApp(app Application, signatures utils.ExternallyManagedSignatures) bool {
+
+	if utils.MatchesAnyPattern(app.Name, signatures.NamePatterns) {
+		return true
+	}
+	if app.TemplateId != "" && utils.MatchesAnyPattern(app.TemplateId, signatures.TemplateIds) {
+		return true
+	}
+	if len(signatures.Creators) == 0 {
+		return false
+	}
+
+	// The creator is only available in the exported application, so it is retrieved only when creator signatures are set.
+	owner, err := getDeployedAppOwner(app.Id)
+	if err != nil {
+		log.Printf("Error when retrieving the creator of application: %s. %s", app.Name, err)
+		return false
+	}
+	return utils.MatchesAnyPattern(owner.UserName, signatures.Creators) ||
+		utils.MatchesAnyPattern(owner.UserStoreDomain+"/"+owner.UserName, signatures.Creators)
+}
+
+func GetPlannedCreates(inputDirPath string) (deployed int, creates int, err error) {
+
+	// Count the local applications that do not exist in the target environment.
+	importFilePath := filepath.Join(inputDirPath, utils.APPLICATIONS)
+	files, err := ioutil.ReadDir(importFilePath)
+	if os.IsNotExist(err) || utils.IsResourceTypeExcluded(utils.APPLICATIONS) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, fmt.Errorf("error when reading the applications: %s", err)
+	}
+	deployedAppNames, err := getDeployedAppNames()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, file := range files {
+		if file.IsDir() || utils.IsReferencedFile(file.Name()) || utils.IsBaselineFile(file.Name()) {
+			continue
+		}
+		appName := utils.ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+		if !utils.IsResourceIncluded(appName) || utils.IsResourceExcluded(appName, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			continue
+		}
+		fileContent, err := utils.ReadResourceFile(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			continue
+		}
+		var appConfig AppConfig
+		if yaml.Unmarshal(fileContent, &appConfig) == nil && appConfig.ApplicationName != "" {
+			appName = appConfig.ApplicationName
+		}
+		if !utils.Contains(deployedAppNames, appName) {
+			creates++
+		}
+	}
+	return len(deployedAppNames), creates, nil
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localAppNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.APPLICATIONS),
+		utils.TOOL_CONFIGS.ApplicationConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	apps, err := getAppList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	if !utils.INCLUDE_SYSTEM_APPS {
+		var nonSystemAppNames []string
+		for _, appName := range localAppNames {
+			if !isSystemAppName(appName, apps) {
+				nonSystemAppNames = append(nonSystemAppNames, appName)
+			}
+		}
+		localAppNames = nonSystemAppNames
+	}
+	signatures := utils.GetExternallyManagedSignatures(utils.TOOL_CONFIGS.ApplicationConfigs)
+	var deployedApps []utils.DeployedResource
+	for _, app := range apps {
+		isDeletable := !utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) &&
+			!app.isSystem() &&
+			(utils.IsExternallyManagedDeleteAllowed(utils.TOOL_CONFIGS.ApplicationConfigs) || !isExternallyManagedApp(app, signatures))
+		deployedApps = append(deployedApps, utils.DeployedResource{Name: app.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.APPLICATIONS, localAppNames, deployedApps, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the applications that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	// The application list is checked against the count, since the list request does not report the errors.
+	appCount, err := getTotalAppCount()
+	if err != nil {
+		return nil, err
+	}
+	appNames, err := getDeployedAppNames()
+	if err != nil {
+		return nil, err
+	}
+	if len(appNames) < appCount {
+		return nil, fmt.Errorf("error when retrieving the deployed applications. Retrieved %d of %d applications",
+			len(appNames), appCount)
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.APPLICATIONS), appNames)
+}
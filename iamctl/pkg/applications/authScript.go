@@ -0,0 +1,98 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const FILE_REFERENCE_PREFIX = "file://"
+
+// Path of the adaptive authentication script in the exported application file.
+var authScriptPath = []string{"localAndOutBoundAuthenticationConfig", "authenticationScriptConfig", "content"}
+
+func ExtractAuthScript(appName string, fileContent []byte) ([]byte, []byte, error) {
+
+	// Replace the adaptive authentication script with a reference to a separate script file.
+	appYaml, scriptConfig, err := getAuthScriptConfig(fileContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	script, _ := scriptConfig[authScriptPath[len(authScriptPath)-1]].(string)
+	if script == "" || strings.HasPrefix(script, FILE_REFERENCE_PREFIX) {
+		return fileContent, nil, nil
+	}
+	scriptConfig[authScriptPath[len(authScriptPath)-1]] = FILE_REFERENCE_PREFIX + appName + utils.AUTH_SCRIPT_FILE_SUFFIX
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error when adding the auth script reference: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), []byte(script), nil
+}
+
+func InjectAuthScript(appFilePath string, fileContent []byte) ([]byte, error) {
+
+	// Replace the reference to the adaptive authentication script file with the content of the script.
+	appYaml, scriptConfig, err := getAuthScriptConfig(fileContent)
+	if err != nil {
+		return nil, err
+	}
+	reference, _ := scriptConfig[authScriptPath[len(authScriptPath)-1]].(string)
+	if !strings.HasPrefix(reference, FILE_REFERENCE_PREFIX) {
+		return fileContent, nil
+	}
+
+	scriptFilePath := filepath.Join(filepath.Dir(appFilePath), strings.TrimPrefix(reference, FILE_REFERENCE_PREFIX))
+	script, err := utils.ReadResourceFile(scriptFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("auth script file of application: %s is not found at: %s",
+			utils.ResolveResourceName(appFilePath), scriptFilePath)
+	}
+	scriptConfig[authScriptPath[len(authScriptPath)-1]] = string(script)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the auth script content: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func getAuthScriptConfig(fileContent []byte) (interface{}, map[interface{}]interface{}, error) {
+
+	var appYaml interface{}
+	err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+
+	config, _ := appYaml.(map[interface{}]interface{})
+	for _, key := range authScriptPath[:len(authScriptPath)-1] {
+		config, _ = config[key].(map[interface{}]interface{})
+	}
+	if config == nil {
+		config = map[interface{}]interface{}{}
+	}
+	return appYaml, config, nil
+}
@@ -0,0 +1,117 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const CERTIFICATE_CONTENT_KEY = "certificateContent"
+
+func ExtractClientCertificate(appName string, fileContent []byte) ([]byte, []byte, error) {
+
+	// Replace the certificate of an application that authenticates with a self-signed certificate with a reference to
+	// a separate PEM file. The certificate of the other applications is kept in the application file.
+	config, err := unmarshalAuthConfig(utils.ReplaceTypeTags(fileContent))
+	if err != nil {
+		return nil, nil, err
+	}
+	certificate := config.CertificateContent
+	if certificate == "" || strings.HasPrefix(certificate, FILE_REFERENCE_PREFIX) ||
+		!usesTokenEndpointAuthMethod(config, utils.SELF_SIGNED_TLS_CLIENT_AUTH) {
+		return fileContent, nil, nil
+	}
+	appYaml, err := unmarshalAppYaml(fileContent)
+	if err != nil {
+		return nil, nil, err
+	}
+	appYaml[CERTIFICATE_CONTENT_KEY] = FILE_REFERENCE_PREFIX + appName + utils.CLIENT_CERTIFICATE_FILE_SUFFIX
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error when adding the client certificate reference: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), []byte(strings.TrimSpace(certificate) + "\n"), nil
+}
+
+func InjectClientCertificate(appFilePath string, fileContent []byte) ([]byte, error) {
+
+	// Replace the reference to the client certificate file with the content of the certificate.
+	appYaml, err := unmarshalAppYaml(fileContent)
+	if err != nil {
+		return nil, err
+	}
+	reference, _ := appYaml[CERTIFICATE_CONTENT_KEY].(string)
+	if !strings.HasPrefix(reference, FILE_REFERENCE_PREFIX) {
+		return fileContent, nil
+	}
+
+	certificateFilePath := filepath.Join(filepath.Dir(appFilePath), strings.TrimPrefix(reference, FILE_REFERENCE_PREFIX))
+	certificate, err := utils.ReadResourceFile(certificateFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("client certificate file of application: %s is not found at: %s",
+			utils.ResolveResourceName(appFilePath), certificateFilePath)
+	}
+	appYaml[CERTIFICATE_CONTENT_KEY] = strings.TrimSpace(string(certificate))
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the client certificate content: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+// Replace the references to the files exported next to the application file, such as the auth script and the
+// client certificate, with the content of the files.
+func injectReferencedFiles(appFilePath string, fileContent []byte) ([]byte, error) {
+
+	fileContent, err := InjectAuthScript(appFilePath, fileContent)
+	if err != nil {
+		return nil, err
+	}
+	return InjectClientCertificate(appFilePath, fileContent)
+}
+
+func unmarshalAppYaml(fileContent []byte) (map[interface{}]interface{}, error) {
+
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	if appYaml == nil {
+		appYaml = map[interface{}]interface{}{}
+	}
+	return appYaml, nil
+}
+
+func usesTokenEndpointAuthMethod(config AuthConfig, authMethod string) bool {
+
+	for _, requestConfig := range config.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
+		if strings.ToLower(requestConfig.InboundAuthType) == utils.OAUTH2 &&
+			requestConfig.InboundConfigurationProtocol.TokenEndpointAuthMethod == authMethod {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Keys added to the exported application files by the tool. The keys are not compared with the baseline, since the
+// baseline keeps the application configuration as returned by the export API.
+var conflictIgnoredFields = append([]string{ACCESS_CONTROL_CONFIG, TOKEN_CONFIG, PROVISIONING_CONFIG, MFA_CONFIG,
+	OWNER_NAME}, utils.GetIgnoredFields(utils.APPLICATIONS)...)
+
+// Get the content of the baseline file of an exported application. The secrets are masked in the same way as the
+// exported file, so that the secrets are not written to the baseline file.
+func getAppBaselineContent(serverContent []byte) []byte {
+
+	return maskOAuthConsumerSecret(serverContent)
+}
+
+// Check whether a local application conflicts with the changes made directly on the server since the last export,
+// and resolve the conflict with the conflict strategy. Returns whether the application should be updated.
+// Applications without a baseline file are always updated.
+func resolveAppConflict(appFilePath string, appId string, appName string, modifiedFileData string) (bool, error) {
+
+	baselineContent, err := utils.ReadBaselineFile(appFilePath)
+	if err != nil || baselineContent == nil {
+		return true, err
+	}
+	deployedContent, err := getDeployedAppContent(appId)
+	if err != nil {
+		return false, fmt.Errorf("error when retrieving the deployed application to detect conflicts: %s", err)
+	}
+	if !utils.IsConflicting(getAppBaselineContent([]byte(modifiedFileData)), getAppBaselineContent(deployedContent),
+		baselineContent, conflictIgnoredFields) {
+		return true, nil
+	}
+	return utils.ResolveConflict(utils.APPLICATIONS, appName)
+}
+
+// Update the baseline file of an imported application, if the application has a baseline file, so that the changes
+// made by the import are not detected as conflicts in the next import.
+func updateAppBaseline(appFilePath string, appId string, appName string) {
+
+	if !utils.ResourceFileExists(utils.GetBaselineFilePath(appFilePath)) {
+		return
+	}
+	deployedContent, err := getDeployedAppContent(appId)
+	if err == nil {
+		err = utils.WriteBaselineFile(appFilePath, getAppBaselineContent(deployedContent))
+	}
+	if err != nil {
+		log.Printf("Error when updating the baseline file of application: %s. %s", appName, err)
+	}
+}
@@ -0,0 +1,123 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Fields that identify an application instead of configuring it. The fields are ignored in addition to the server
+// generated fields when finding the applications with identical configurations, since the client ids and issuers
+// are unique for each application.
+var duplicateAppIgnoredFields = append([]string{APP_NAME_KEY, "inboundAuthKey", "oauthConsumerKey", "issuer",
+	OWNER_NAME}, utils.GetIgnoredFields(utils.APPLICATIONS)...)
+
+// Log a warning for each group of the exported applications with identical configurations, so that the applications
+// created by mistake can be found and consolidated. Only the YAML files of the given applications are compared.
+func ReportDuplicateApps(dirPath string, appNames []string) {
+
+	duplicateApps, err := FindDuplicateApps(dirPath, appNames)
+	if err != nil {
+		log.Printf("Error when finding the duplicate applications. %s", err)
+		return
+	}
+	for _, apps := range duplicateApps {
+		utils.LogWarning(utils.WARNING_DUPLICATE_APP, fmt.Sprintf("The following applications have identical "+
+			"configurations and may be duplicates: %s", strings.Join(apps, ", ")))
+	}
+}
+
+// Get the groups of the given applications whose exported YAML files are identical after removing the fields that
+// identify the application. The adaptive authentication scripts are compared by the content instead of the file
+// reference, since the script files are named after the application.
+func FindDuplicateApps(dirPath string, appNames []string) ([][]string, error) {
+
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	appsByHash := make(map[string][]string)
+	var hashes []string
+	for _, file := range files {
+		if file.IsDir() || utils.IsReferencedFile(file.Name()) || !utils.IsYamlFile(file.Name()) {
+			continue
+		}
+		filePath := filepath.Join(dirPath, file.Name())
+		appName, hash, err := getAppConfigHash(filePath)
+		if err != nil {
+			log.Printf("Skipping the file: %s when finding the duplicate applications. %s", filePath, err)
+			continue
+		}
+		if !utils.Contains(appNames, appName) {
+			continue
+		}
+		if _, exists := appsByHash[hash]; !exists {
+			hashes = append(hashes, hash)
+		}
+		appsByHash[hash] = append(appsByHash[hash], appName)
+	}
+
+	var duplicateApps [][]string
+	for _, hash := range hashes {
+		if len(appsByHash[hash]) > 1 {
+			sort.Strings(appsByHash[hash])
+			duplicateApps = append(duplicateApps, appsByHash[hash])
+		}
+	}
+	return duplicateApps, nil
+}
+
+func getAppConfigHash(appFilePath string) (string, string, error) {
+
+	content, err := utils.ReadResourceFile(appFilePath)
+	if err != nil {
+		return "", "", err
+	}
+	var appYaml map[string]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(content), &appYaml); err != nil {
+		return "", "", err
+	}
+	appName, _ := appYaml[APP_NAME_KEY].(string)
+	if appName == "" {
+		return "", "", fmt.Errorf("application name is not found in the file")
+	}
+	appName = utils.ReplaceKeywords(appName, utils.KEYWORD_CONFIGS.KeywordMappings)
+
+	content, err = injectReferencedFiles(appFilePath, content)
+	if err != nil {
+		return "", "", err
+	}
+	content, err = utils.OmitNullFields(content)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := utils.GetCanonicalHash(content, duplicateAppIgnoredFields)
+	if err != nil {
+		return "", "", err
+	}
+	return appName, hash, nil
+}
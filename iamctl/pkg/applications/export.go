@@ -25,6 +25,8 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 )
@@ -38,33 +40,151 @@ func ExportAll(exportFilePath string, format string) {
 	if utils.IsResourceTypeExcluded(utils.APPLICATIONS) {
 		return
 	}
+	utils.WarnUnfilteredSince(utils.APPLICATIONS)
 	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
 		os.MkdirAll(exportFilePath, 0700)
 	} else {
 		if utils.TOOL_CONFIGS.AllowDelete {
-			utils.RemoveDeletedLocalResources(exportFilePath, getDeployedAppNames())
+			if deployedAppNames, err := getDeployedAppNames(); err != nil {
+				log.Println("Error when removing the local files of the deleted applications.", err)
+			} else {
+				utils.RemoveDeletedLocalResources(exportFilePath, deployedAppNames)
+			}
 		}
 	}
 
-	apps := getAppList()
+	// The names of the applications are mapped to the file names that are valid on all platforms.
+	utils.StartFileNameMapping(exportFilePath)
+	defer func() {
+		if err := utils.WriteFileNameMapping(exportFilePath); err != nil {
+			log.Println("Error when writing the file names of the applications.", err)
+		}
+	}()
+
+	apps, err := getAppList()
+	if err != nil {
+		log.Println("Error while exporting applications.", err)
+		return
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return utils.GetResourcePriority(apps[i].Name) < utils.GetResourcePriority(apps[j].Name)
+	})
+	utils.StartResourceProgress(utils.APPLICATIONS, len(apps))
+	exportCompleted := true
+	var exportedAppNames []string
 	for _, app := range apps {
+		if app.isSystem() && !utils.INCLUDE_SYSTEM_APPS {
+			log.Println("Skipping system application: " + app.Name)
+			utils.ReportSkippedResource(utils.APPLICATIONS, app.Name, utils.JUNIT_SKIPPED_SYSTEM_APP)
+			continue
+		}
 		excludeSecrets := utils.AreSecretsExcluded(utils.TOOL_CONFIGS.ApplicationConfigs)
 		if !utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			if !utils.IsTimeBudgetAvailable() {
+				utils.AddUnprocessedResourceToSummary(utils.APPLICATIONS, app.Name)
+				exportCompleted = false
+				continue
+			}
 			log.Println("Exporting application: ", app.Name)
+			utils.EmitResourceStarted(utils.APPLICATIONS, app.Name, utils.EXPORT)
 			err := exportApp(app.Id, exportFilePath, format, excludeSecrets)
 			if err != nil {
+				exportCompleted = false
 				utils.UpdateFailureSummary(utils.APPLICATIONS, app.Name)
 				log.Printf("Error while exporting application: %s. %s", app.Name, err)
 			} else {
-				utils.UpdateSuccessSummary(utils.APPLICATIONS, utils.EXPORT)
+				utils.UpdateSuccessSummary(utils.APPLICATIONS, app.Name, utils.EXPORT)
 				log.Println("Application exported successfully: ", app.Name)
+				exportedAppNames = append(exportedAppNames, app.Name)
 			}
+		} else {
+			utils.ReportSkippedResource(utils.APPLICATIONS, app.Name, utils.JUNIT_SKIPPED_EXCLUDED)
 		}
 	}
+
+	if format != "json" && format != "xml" {
+		ReportDuplicateApps(exportFilePath, exportedAppNames)
+	}
+
+	// The files are only compared with the deployed applications if all applications are exported. The list request
+	// does not report the errors, so the list is checked against the count.
+	if !exportCompleted {
+		log.Println("Stale application files are not checked, since the export did not complete successfully.")
+		return
+	}
+	appCount, err := getTotalAppCount()
+	if err != nil || len(apps) < appCount {
+		log.Println("Stale application files are not checked, since the application list is incomplete.")
+		return
+	}
+	var appNames []string
+	for _, app := range apps {
+		appNames = append(appNames, app.Name)
+	}
+	utils.HandleStaleExportedFiles(utils.APPLICATIONS, exportFilePath, APP_NAME_KEY, appNames,
+		utils.TOOL_CONFIGS.ApplicationConfigs)
 }
 
 func exportApp(appId string, outputDirPath string, format string, excludeSecrets bool) error {
 
+	exportedFileName, modifiedFile, serverContent, err := getExportedAppContent(appId, outputDirPath, format, excludeSecrets)
+	if err != nil {
+		return err
+	}
+	return writeExportedApp(outputDirPath, format, exportedFileName, modifiedFile, serverContent)
+}
+
+// Write the exported application file, along with the auth script file, the client certificate file and the
+// baseline file of the application.
+func writeExportedApp(outputDirPath string, format string, exportedFileName string, modifiedFile []byte,
+	serverContent []byte) error {
+
+	if format == "json" || format == "xml" {
+		return utils.WriteExportedFile(exportedFileName, modifiedFile)
+	}
+	if err := utils.WriteBaselineFile(exportedFileName, getAppBaselineContent(serverContent)); err != nil {
+		return err
+	}
+
+	// Export the adaptive authentication script to a separate file to make it reviewable. The script file is named
+	// after the application file.
+	appName := utils.ResolveResourceName(exportedFileName)
+	appFileName := utils.GetFileInfo(exportedFileName).ResourceName
+	modifiedFile, script, err := ExtractAuthScript(appFileName, modifiedFile)
+	if err != nil {
+		return err
+	}
+	if script != nil {
+		scriptFileName := filepath.Join(outputDirPath, appFileName+utils.AUTH_SCRIPT_FILE_SUFFIX)
+
+		// Keep the keyword placeholders of the local script if the script is not changed.
+		if localScript, err := utils.ReadResourceFile(scriptFileName); err == nil &&
+			utils.ReplaceKeywords(string(localScript), getAppKeywordMapping(appName)) == string(script) {
+			script = localScript
+		}
+		if err := utils.WriteExportedFile(scriptFileName, script); err != nil {
+			return fmt.Errorf("error when writing the auth script file: %s", err)
+		}
+	}
+
+	// Export the certificate of an application that authenticates with a self-signed certificate to a PEM file.
+	modifiedFile, certificate, err := ExtractClientCertificate(appFileName, modifiedFile)
+	if err != nil {
+		return err
+	}
+	if certificate != nil {
+		certificateFileName := filepath.Join(outputDirPath, appFileName+utils.CLIENT_CERTIFICATE_FILE_SUFFIX)
+		if err := utils.WriteExportedFile(certificateFileName, certificate); err != nil {
+			return fmt.Errorf("error when writing the client certificate file: %s", err)
+		}
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
+
+// Get the name and the content of the exported application file, and the application configuration as returned
+// by the export API.
+func getExportedAppContent(appId string, outputDirPath string, format string, excludeSecrets bool) (string, []byte, []byte, error) {
+
 	var fileType string
 	// TODO: Extend support for json and xml formats.
 	switch format {
@@ -76,37 +196,68 @@ func exportApp(appId string, outputDirPath string, format string, excludeSecrets
 		fileType = utils.MEDIA_TYPE_YAML
 	}
 
-	resp, err := utils.SendExportRequest(appId, fileType, utils.APPLICATIONS, excludeSecrets)
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), appId, fileType, utils.APPLICATIONS, excludeSecrets)
 	if err != nil {
-		return fmt.Errorf("error while exporting the application: %s", err)
+		return "", nil, nil, fmt.Errorf("error while exporting the application: %s", err)
 	}
 	var attachmentDetail = resp.Header.Get("Content-Disposition")
 	_, params, err := mime.ParseMediaType(attachmentDetail)
 	if err != nil {
-		return fmt.Errorf("error while parsing the content disposition header: %s", err)
+		return "", nil, nil, fmt.Errorf("error while parsing the content disposition header: %s", err)
 	}
 
+	// The file is named after the application, which can contain characters that are not valid in file names.
 	fileName := params["filename"]
-	exportedFileName := filepath.Join(outputDirPath, fileName)
-	fileInfo := utils.GetFileInfo(exportedFileName)
+	appName := strings.TrimSuffix(fileName, filepath.Ext(fileName))
+	exportedFileName := filepath.Join(outputDirPath,
+		utils.GetExportedFileName(outputDirPath, appName, appId)+filepath.Ext(fileName))
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("error while reading the response body when exporting app: %s. %s", fileName, err)
+		return "", nil, nil, fmt.Errorf("error while reading the response body when exporting app: %s. %s", fileName, err)
 	}
 
+	serverContent := body
 	if excludeSecrets {
 		body = maskOAuthConsumerSecret(body)
+		if fileType == utils.MEDIA_TYPE_YAML {
+			body, err = maskSamlCertificates(body)
+			if err != nil {
+				return "", nil, nil, err
+			}
+		}
 	}
-	appKeywordMapping := getAppKeywordMapping(fileInfo.ResourceName)
+	if fileType == utils.MEDIA_TYPE_YAML {
+		body, err = addAccessControlConfig(appId, body)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		body, err = addTokenConfig(appId, body)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		body, err = addProvisioningConfig(appId, body)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		body, err = addMfaConfig(body)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		body, err = addAppOwner(body)
+		if err != nil {
+			return "", nil, nil, err
+		}
+	}
+	if err := ValidateClientAuthConfig(string(body)); err != nil {
+		utils.LogWarning(utils.WARNING_CLIENT_AUTH, fmt.Sprintf(
+			"Application: %s has incomplete client authentication configurations. %s", appName, err))
+	}
+	appKeywordMapping := getAppKeywordMapping(appName)
 	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, body, appKeywordMapping, utils.APPLICATIONS)
 	if err != nil {
-		return fmt.Errorf("error while processing exported data: %s", err)
+		return "", nil, nil, fmt.Errorf("error while processing exported data: %s", err)
 	}
 
-	err = ioutil.WriteFile(exportedFileName, modifiedFile, 0644)
-	if err != nil {
-		return fmt.Errorf("error when writing the exported content to file: %w", err)
-	}
-	return nil
+	return exportedFileName, modifiedFile, serverContent, nil
 }
@@ -24,6 +24,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
@@ -39,6 +40,7 @@ func ImportAll(inputDirPath string) {
 		return
 	}
 	var files []os.FileInfo
+	var deployedApps []Application
 	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
 		log.Println("No applications to import.")
 	} else {
@@ -46,30 +48,101 @@ func ImportAll(inputDirPath string) {
 		if err != nil {
 			log.Println("Error importing applications: ", err)
 		}
-		if utils.TOOL_CONFIGS.AllowDelete {
-			removeDeletedDeployedApps(files, importFilePath)
+		// The deployed applications are listed once for the import, since the list is paginated and costly for a
+		// target environment with many applications.
+		deployedApps, err = getAppList()
+		if err != nil {
+			log.Println("Error importing applications: ", err)
+			return
+		}
+		if utils.IsDeleteAllowed() {
+			removeDeletedDeployedApps(deployedApps, files, importFilePath)
 		}
 	}
 
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+	appFileCount := 0
 	for _, file := range files {
+		if !utils.IsReferencedFile(file.Name()) && !utils.IsBaselineFile(file.Name()) {
+			appFileCount++
+		}
+	}
+	systemApps, isSystemAppImportAllowed := getLocalSystemApps(deployedApps, files, importFilePath)
+	utils.StartResourceProgress(utils.APPLICATIONS, appFileCount)
+	for _, file := range files {
+		if utils.IsReferencedFile(file.Name()) || utils.IsBaselineFile(file.Name()) {
+			continue
+		}
 		appFilePath := filepath.Join(importFilePath, file.Name())
-		appName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
-		appExists, isValidFile := validateFile(appFilePath, appName)
+		appName := utils.ResolveResourceName(appFilePath)
+		if !utils.IsResourceIncluded(appName) {
+			utils.AddFilteredResourceToSummary(utils.APPLICATIONS, appName)
+			continue
+		}
+		if utils.Contains(systemApps, appName) && !isSystemAppImportAllowed {
+			log.Println("Skipping system application: " + appName)
+			utils.ReportSkippedResource(utils.APPLICATIONS, appName, utils.JUNIT_SKIPPED_SYSTEM_APP)
+			continue
+		}
+		if !utils.IsResourceExcluded(appName, utils.TOOL_CONFIGS.ApplicationConfigs) && !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.APPLICATIONS, appName)
+			continue
+		}
+		if utils.IsResourceExcluded(appName, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			utils.ReportSkippedResource(utils.APPLICATIONS, appName, utils.JUNIT_SKIPPED_EXCLUDED)
+		}
+		appId, isValidFile := validateFile(appFilePath, appName, deployedApps)
 
 		if isValidFile && !utils.IsResourceExcluded(appName, utils.TOOL_CONFIGS.ApplicationConfigs) {
-			importApp(appFilePath, appExists)
+			if appId == "" && utils.UPDATE_ONLY {
+				utils.SkipMissingResource(utils.APPLICATIONS, appName)
+				continue
+			}
+			utils.EmitResourceStarted(utils.APPLICATIONS, appName, utils.IMPORT)
+			importApp(appFilePath, appId)
 		}
 	}
 }
 
-func validateFile(appFilePath string, appName string) (appExists bool, isValid bool) {
+// Get the system applications of the local files, and whether they can be imported. System applications are imported
+// only with the --include-system-apps flag, after a confirmation.
+func getLocalSystemApps(deployedApps []Application, files []os.FileInfo, importFilePath string) (systemApps []string,
+	isImportAllowed bool) {
+
+	if len(files) == 0 {
+		return nil, false
+	}
+	for _, file := range files {
+		if file.IsDir() || utils.IsReferencedFile(file.Name()) || utils.IsBaselineFile(file.Name()) {
+			continue
+		}
+		appName := utils.ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+		if utils.IsResourceIncluded(appName) && isSystemAppName(appName, deployedApps) {
+			systemApps = append(systemApps, appName)
+		}
+	}
+	if len(systemApps) == 0 {
+		return nil, false
+	}
+	if !utils.INCLUDE_SYSTEM_APPS {
+		log.Printf("System applications are not imported: %s. Use the --include-system-apps flag to import them.",
+			strings.Join(systemApps, ", "))
+		return systemApps, false
+	}
+	return systemApps, utils.ConfirmSystemResourceImport(utils.APPLICATIONS, systemApps)
+}
 
-	appExists = false
+// Validate the file of an application, and get the id of the deployed application with the same name. The id is empty
+// if the application is not deployed in the target environment.
+func validateFile(appFilePath string, appName string, deployedApps []Application) (appId string, isValid bool) {
 
-	fileContent, err := ioutil.ReadFile(appFilePath)
+	fileContent, err := utils.ReadResourceFile(appFilePath)
 	if err != nil {
 		log.Println("Error when reading the file for app: ", appName, err)
-		return appExists, false
+		return "", false
 	}
 
 	// Validate the YAML format.
@@ -77,103 +150,364 @@ func validateFile(appFilePath string, appName string) (appExists bool, isValid b
 	err = yaml.Unmarshal(fileContent, &appConfig)
 	if err != nil {
 		log.Println("Invalid file content for app: ", appName, err)
-		return appExists, false
+		return "", false
 	}
 
-	existingAppList := getDeployedAppNames()
-	for _, app := range existingAppList {
-		if app == appConfig.ApplicationName {
-			appExists = true
+	for _, app := range deployedApps {
+		if app.Name == appConfig.ApplicationName {
+			appId = app.Id
 			break
 		}
 	}
 	if appConfig.ApplicationName != appName {
-		log.Println("Warning: Application name in the file " + appFilePath + " is not matching with the file name.")
+		utils.LogWarning(utils.WARNING_NAME_MISMATCH, "Application name in the file "+appFilePath+" is not matching with the file name.")
 	}
-	return appExists, true
+	return appId, true
 }
 
-func importApp(importFilePath string, isUpdate bool) error {
+// Import an application from the file. The application is updated if the id of the deployed application is given,
+// and created otherwise.
+func importApp(importFilePath string, appId string) error {
 
-	fileBytes, err := ioutil.ReadFile(importFilePath)
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
 	if err != nil {
 		return fmt.Errorf("error when reading the file for application: %s", err)
 	}
+	fileBytes, err = injectReferencedFiles(importFilePath, fileBytes)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, utils.ResolveResourceName(importFilePath))
+		log.Println("Error when reading the files referenced by the application.", err)
+		return err
+	}
 
 	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	// The file can be named differently from the application, if the name is not valid as a file name.
 	fileInfo := utils.GetFileInfo(importFilePath)
+	fileInfo.ResourceName = utils.ResolveResourceName(importFilePath)
 	appKeywordMapping := getAppKeywordMapping(fileInfo.ResourceName)
 	fileDataWithReplacedKeywords := utils.ReplaceKeywords(string(fileBytes), appKeywordMapping)
+	utils.CheckImportContent(utils.APPLICATIONS, fileInfo.ResourceName, fileDataWithReplacedKeywords)
+	fileDataWithReplacedKeywords, err = resolveSamlCertificates(appId, fileInfo.ResourceName, fileDataWithReplacedKeywords)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		log.Printf("Error when resolving the SAML certificates of application: %s. %s", fileInfo.ResourceName, err)
+		return fmt.Errorf("error when resolving the SAML certificates: %s", err)
+	}
+	checkCertificateExpiry(fileInfo.ResourceName, fileDataWithReplacedKeywords)
 	modifiedFileData := utils.RemoveSecretMasks(fileDataWithReplacedKeywords)
 
-	if isUpdate {
-		return updateApplication(importFilePath, modifiedFileData, fileInfo)
+	if err := ValidateClientAuthConfig(modifiedFileData); err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		log.Printf("Invalid client authentication configurations for application: %s. %s", fileInfo.ResourceName, err)
+		return fmt.Errorf("invalid client authentication configurations: %s", err)
+	}
+	if err := validateSamlConfig(modifiedFileData); err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		log.Printf("Invalid SAML configurations for application: %s. %s", fileInfo.ResourceName, err)
+		return fmt.Errorf("invalid SAML configurations: %s", err)
+	}
+
+	if appId != "" {
+		if utils.IsImportStateUnchanged(utils.APPLICATIONS, fileInfo.ResourceName, modifiedFileData) {
+			log.Println("Application is unchanged since the last import. Skipping update: " + fileInfo.ResourceName)
+			utils.UpdateSuccessSummary(utils.APPLICATIONS, fileInfo.ResourceName, utils.UNCHANGED)
+			return nil
+		}
+		if !utils.FORCE_IMPORT && isAppUnchanged(appId, fileInfo.ResourceName, modifiedFileData) {
+			log.Println("Application is unchanged. Skipping update: " + fileInfo.ResourceName)
+			utils.UpdateImportState(utils.APPLICATIONS, fileInfo.ResourceName, modifiedFileData)
+			utils.UpdateSuccessSummary(utils.APPLICATIONS, fileInfo.ResourceName, utils.UNCHANGED)
+			return nil
+		}
+		isUpdateAllowed, err := resolveAppConflict(importFilePath, appId, fileInfo.ResourceName, modifiedFileData)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			log.Printf("Error when checking the conflicts of application: %s. %s", fileInfo.ResourceName, err)
+			return err
+		}
+		if !isUpdateAllowed {
+			return nil
+		}
+		return updateApplication(importFilePath, appId, modifiedFileData, fileInfo)
 	}
 	return importApplication(importFilePath, modifiedFileData, fileInfo)
 }
 
-func updateApplication(importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
+func updateApplication(importFilePath string, appId string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
+	appFileData, accessControlGroups, err := resolveAccessControl(modifiedFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
+	appFileData, tokenConfig, err := resolveTokenConfig(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
+	appFileData, provisioningConfig, err := resolveProvisioningConfig(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
+	appFileData, err = resolveMfaConfig(fileInfo.ResourceName, appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
+	appFileData, owner, err := resolveAppOwner(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
+	if err := backupApp(appId, fileInfo.ResourceName); err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating application: %s", err)
+	}
 	log.Println("Updating application: " + fileInfo.ResourceName)
-	err := utils.SendUpdateRequest("", importFilePath, modifiedFileData, utils.APPLICATIONS)
+	err = utils.SendUpdateRequest(utils.GetRequestContext(), "", importFilePath, appFileData, utils.APPLICATIONS)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
 		return fmt.Errorf("error when updating application: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.APPLICATIONS, utils.UPDATE)
+	if accessControlGroups != nil {
+		if err := setAccessControl(appId, fileInfo.ResourceName, *accessControlGroups); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when updating application: %s", err)
+		}
+	}
+	if tokenConfig != nil {
+		if err := setTokenConfig(appId, fileInfo.ResourceName, *tokenConfig); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when updating application: %s", err)
+		}
+	}
+	if provisioningConfig != nil {
+		if err := setProvisioningConfig(appId, fileInfo.ResourceName, *provisioningConfig); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when updating application: %s", err)
+		}
+	}
+	if owner != nil {
+		if err := setAppOwner(appId, fileInfo.ResourceName, importFilePath, appFileData, *owner); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when updating application: %s", err)
+		}
+	}
+	updateAppBaseline(importFilePath, appId, fileInfo.ResourceName)
+	utils.UpdateImportState(utils.APPLICATIONS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, fileInfo.ResourceName, utils.UPDATE)
 	log.Println("Application updated successfully.")
 	return nil
 }
 
 func importApplication(importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
+	appFileData, accessControlGroups, err := resolveAccessControl(modifiedFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing application: %s", err)
+	}
+	appFileData, tokenConfig, err := resolveTokenConfig(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing application: %s", err)
+	}
+	appFileData, provisioningConfig, err := resolveProvisioningConfig(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing application: %s", err)
+	}
+	appFileData, err = resolveMfaConfig(fileInfo.ResourceName, appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing application: %s", err)
+	}
+	appFileData, owner, err := resolveAppOwner(appFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing application: %s", err)
+	}
 	log.Println("Creating new application: " + fileInfo.ResourceName)
-	err := utils.SendImportRequest(importFilePath, modifiedFileData, utils.APPLICATIONS)
+	if isTwoPhaseImport(appFileData, fileInfo) {
+		err = importApplicationInTwoPhases(importFilePath, appFileData, fileInfo.ResourceName)
+	} else {
+		err = utils.SendImportRequest(utils.GetRequestContext(), importFilePath, appFileData, utils.APPLICATIONS)
+	}
 	if err != nil {
 		utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
 		return fmt.Errorf("error when importing application: %s", err)
 	}
+	// The id of the created application is resolved once, only if other configurations are set on it.
+	var appId string
+	if accessControlGroups != nil || tokenConfig != nil || provisioningConfig != nil || owner != nil {
+		appId = getAppId(fileInfo.ResourceName)
+	}
+	if accessControlGroups != nil {
+		if err := setAccessControl(appId, fileInfo.ResourceName, *accessControlGroups); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when importing application: %s", err)
+		}
+	}
+	if tokenConfig != nil {
+		if err := setTokenConfig(appId, fileInfo.ResourceName, *tokenConfig); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when importing application: %s", err)
+		}
+	}
+	if provisioningConfig != nil {
+		if err := setProvisioningConfig(appId, fileInfo.ResourceName, *provisioningConfig); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when importing application: %s", err)
+		}
+	}
+	if owner != nil {
+		if err := setAppOwner(appId, fileInfo.ResourceName, importFilePath, appFileData, *owner); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, fileInfo.ResourceName)
+			return fmt.Errorf("error when importing application: %s", err)
+		}
+	}
 
-	if oauthApp, err := isOauthApp(modifiedFileData); err != nil {
+	if authType, err := getInboundAuthType(modifiedFileData); err != nil {
 		fmt.Println("Failed to check if the applications is an OAuth app:", err.Error())
 	} else if oauthSecretGiven, err := isOauthSecretGiven(modifiedFileData); err != nil {
 		fmt.Println("Failed to check if oauthConsumerSecret is given:", err.Error())
-	} else if oauthApp && !oauthSecretGiven {
+	} else if authType == INBOUND_AUTH_OAUTH2 && !oauthSecretGiven {
 		// Check if oauthConsumerSecret is given or else add an indicator to the summary informing a new secret is generated.
 		utils.AddNewSecretIndicatorToSummary(fileInfo.ResourceName)
 	}
-	utils.UpdateSuccessSummary(utils.APPLICATIONS, utils.IMPORT)
+	utils.UpdateImportState(utils.APPLICATIONS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, fileInfo.ResourceName, utils.IMPORT)
 	log.Println("Application imported successfully.")
 	return nil
 }
 
-func removeDeletedDeployedApps(localFiles []os.FileInfo, importFilePath string) {
+func isTwoPhaseImport(appFileData string, fileInfo utils.FileInfo) bool {
+
+	fileExtension := strings.ToLower(fileInfo.FileExtension)
+	if fileExtension != ".yml" && fileExtension != ".yaml" {
+		return false
+	}
+	authType, err := getInboundAuthType(appFileData)
+	if err != nil || authType != INBOUND_AUTH_OAUTH2 {
+		return false
+	}
+	return utils.GetApplicationImportStrategy() == utils.TWO_PHASE_IMPORT
+}
+
+// Some server versions reject an application created with a client id that has a stale DCR record, while the same
+// client id can be set on an existing application. The application is created without the OAuth inbound protocol first
+// and the protocol is added with an update. The created application is deleted if the update fails.
+func importApplicationInTwoPhases(importFilePath string, appFileData string, appName string) error {
+
+	baseAppData, err := removeOauthInboundConfig(appFileData)
+	if err != nil {
+		return err
+	}
+	log.Println("Creating the application without the OAuth inbound protocol: " + appName)
+	if err := utils.SendImportRequest(utils.GetRequestContext(), importFilePath, baseAppData, utils.APPLICATIONS); err != nil {
+		return err
+	}
+	log.Println("Adding the OAuth inbound protocol to the application: " + appName)
+	err = utils.SendUpdateRequest(utils.GetRequestContext(), "", importFilePath, appFileData, utils.APPLICATIONS)
+	if err == nil {
+		return nil
+	}
+
+	appId := getAppId(appName)
+	if appId == "" {
+		return fmt.Errorf("error when adding the OAuth inbound protocol: %s. The created application could not be "+
+			"found to roll back", err)
+	}
+	if deleteErr := utils.SendDeleteRequest(utils.GetRequestContext(), appId, utils.APPLICATIONS); deleteErr != nil {
+		return fmt.Errorf("error when adding the OAuth inbound protocol: %s. Rolling back the created application "+
+			"failed: %s", err, deleteErr)
+	}
+	log.Println("Rolled back the created application: " + appName)
+	return fmt.Errorf("error when adding the OAuth inbound protocol: %s. The created application was rolled back", err)
+}
+
+func removeDeletedDeployedApps(deployedApps []Application, localFiles []os.FileInfo, importFilePath string) {
 
 	// Remove deployed applications that do not exist locally.
-	deployedApps := getAppList()
+	signatures := utils.GetExternallyManagedSignatures(utils.TOOL_CONFIGS.ApplicationConfigs)
+	var appsToDelete []Application
 deployedResources:
 	for _, app := range deployedApps {
 		for _, file := range localFiles {
+			if utils.IsReferencedFile(file.Name()) || utils.IsBaselineFile(file.Name()) {
+				continue
+			}
 			isToolManagementApp, err := isToolMgtApp(file, importFilePath)
 			if err != nil {
 				log.Printf("Error checking if application is a tool management app: %s\n", err.Error())
 				log.Printf("Application: %s is excluded from deletion.\n", app.Name)
 				continue deployedResources
 			}
-			if app.Name == utils.GetFileInfo(file.Name()).ResourceName || isToolManagementApp {
+			appName := utils.ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+			if app.Name == appName || isToolManagementApp {
 				continue deployedResources
 			}
 		}
-		if utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) || app.Name == utils.CONSOLE || app.Name == utils.MY_ACCOUNT {
+		// System applications are never deleted, even if they are included with the --include-system-apps flag.
+		if utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) || app.isSystem() {
 			log.Printf("Application: %s is excluded from deletion.\n", app.Name)
 			continue
 		}
+		// Applications created by other products are not proposed for deletion unless explicitly allowed.
+		// Applications adopted from the target environment are managed by the tool, even if they match a signature.
+		if !utils.IsExternallyManagedDeleteAllowed(utils.TOOL_CONFIGS.ApplicationConfigs) &&
+			!utils.IsResourceAdopted(utils.APPLICATIONS, app.Name) && isExternallyManagedApp(app, signatures) {
+			log.Printf("Application: %s is externally managed and excluded from deletion.\n", app.Name)
+			utils.AddExternallyManagedResourceToSummary(utils.APPLICATIONS, app.Name)
+			continue
+		}
+		appsToDelete = append(appsToDelete, app)
+	}
+
+	var appNames []string
+	for _, app := range appsToDelete {
+		appNames = append(appNames, app.Name)
+	}
+	if !utils.ConfirmDeletion(utils.APPLICATIONS, appNames) {
+		return
+	}
+	for _, app := range appsToDelete {
 		log.Println("Application not found locally. Deleting app: ", app.Name)
-		err := utils.SendDeleteRequest(app.Id, utils.APPLICATIONS)
+		utils.EmitResourceStarted(utils.APPLICATIONS, app.Name, utils.DELETE)
+		if err := backupApp(app.Id, app.Name); err != nil {
+			utils.UpdateFailureSummary(utils.APPLICATIONS, app.Name)
+			log.Println("Error deleting application: ", app.Name, err)
+			continue
+		}
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), app.Id, utils.APPLICATIONS)
 		if err != nil {
 			utils.UpdateFailureSummary(utils.APPLICATIONS, app.Name)
 			log.Println("Error deleting application: ", app.Name, err)
 		}
-		utils.UpdateSuccessSummary(utils.APPLICATIONS, utils.DELETE)
+		utils.UpdateSuccessSummary(utils.APPLICATIONS, app.Name, utils.DELETE)
 	}
 }
+
+// Export the deployed application to the backup directory with the secrets masked, before it is updated or deleted.
+func backupApp(appId string, appName string) error {
+
+	if !utils.IsBackupEnabled() {
+		return nil
+	}
+	backupDir, err := utils.GetBackupDir(utils.APPLICATIONS)
+	if err != nil {
+		return err
+	}
+	log.Println("Backing up application: " + appName)
+	// The file names are mapped in the backup as well, so that the backup can be restored with the application names.
+	utils.StartFileNameMapping(backupDir)
+	err = exportApp(appId, backupDir, "yaml", true)
+	if mappingErr := utils.WriteFileNameMapping(backupDir); err == nil {
+		err = mappingErr
+	}
+	if err != nil {
+		return fmt.Errorf("error when backing up the deployed application: %s", err)
+	}
+	return nil
+}
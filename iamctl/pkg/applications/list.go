@@ -0,0 +1,80 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+type AppListItem struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	// All attributes of the application returned by the list API, for the JSON and YAML outputs.
+	Attributes map[string]interface{} `json:"-"`
+}
+
+func ListApps() ([]AppListItem, error) {
+
+	appCount, err := getTotalAppCount()
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		Applications []AppListItem `json:"applications"`
+	}
+	var attributeList struct {
+		Applications []map[string]interface{} `json:"applications"`
+	}
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(appCount))
+	if filter := utils.GetNamespaceFilter("name"); filter != "" {
+		query.Set("filter", filter)
+	}
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, "?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving application list. %w", err)
+	}
+	err = json.Unmarshal(body, &list)
+	if err == nil {
+		err = json.Unmarshal(body, &attributeList)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved application list. %w", err)
+	}
+
+	var apps []AppListItem
+	for i, app := range list.Applications {
+		if !utils.IsInNamespace(app.Name) {
+			continue
+		}
+		app.Attributes = attributeList.Applications[i]
+		apps = append(apps, app)
+	}
+	sort.SliceStable(apps, func(i, j int) bool {
+		return apps[i].Name < apps[j].Name
+	})
+	return apps, nil
+}
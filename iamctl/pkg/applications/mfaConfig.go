@@ -0,0 +1,292 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"log"
+	"reflect"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Key of the multi-factor authentication configurations in the exported application file. The configurations are
+// derived from the second and later authentication steps of the application, and take precedence over these steps
+// when the application is imported.
+const MFA_CONFIG = "mfaConfig"
+
+const FLOW_AUTHENTICATION_TYPE = "flow"
+
+type MfaConfig struct {
+	Steps []MfaStep `yaml:"steps"`
+}
+
+// The options of a step are listed in the order of priority, where the first option is offered first to the user.
+type MfaStep struct {
+	Step    int         `yaml:"step"`
+	Options []MfaOption `yaml:"options"`
+}
+
+// The identity provider of local authenticators, such as TOTP and Email OTP, is LOCAL.
+type MfaOption struct {
+	Idp           string `yaml:"idp"`
+	Authenticator string `yaml:"authenticator"`
+}
+
+func addMfaConfig(fileContent []byte) ([]byte, error) {
+
+	// Add the multi-factor authentication configurations of applications with more than one step to the file.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	config := getMfaConfig(appYaml)
+	if len(config.Steps) == 0 {
+		return fileContent, nil
+	}
+	appYaml[MFA_CONFIG] = config
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the multi-factor authentication configurations: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func extractMfaConfig(fileData string) (string, *MfaConfig, error) {
+
+	// Remove the multi-factor authentication configurations from the application file, since the server does not
+	// accept them.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	configYaml, ok := appYaml[MFA_CONFIG]
+	if !ok {
+		return fileData, nil, nil
+	}
+
+	var config MfaConfig
+	configContent, err := yaml.Marshal(configYaml)
+	if err == nil {
+		err = yaml.UnmarshalStrict(configContent, &config)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid multi-factor authentication configurations: %s", err)
+	}
+	delete(appYaml, MFA_CONFIG)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when removing the multi-factor authentication configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), &config, nil
+}
+
+// Returns the application file with the second and later authentication steps replaced by the steps of the
+// multi-factor authentication configurations. The file is returned without changes if it does not have
+// multi-factor authentication configurations.
+func resolveMfaConfig(appName string, fileData string) (string, error) {
+
+	appFileData, config, err := extractMfaConfig(fileData)
+	if err != nil || config == nil {
+		return appFileData, err
+	}
+	if err := validateMfaConfig(*config); err != nil {
+		return "", err
+	}
+
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(appFileData)), &appYaml); err != nil {
+		return "", fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	if config.Steps == nil {
+		config.Steps = []MfaStep{}
+	}
+	sort.Slice(config.Steps, func(i, j int) bool {
+		return config.Steps[i].Step < config.Steps[j].Step
+	})
+	if !reflect.DeepEqual(getMfaConfig(appYaml).Steps, config.Steps) {
+		log.Printf("Authentication steps of application: %s are different from the %s key. "+
+			"The steps in the %s key are imported.", appName, MFA_CONFIG, MFA_CONFIG)
+	}
+	setMfaSteps(appYaml, *config)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", fmt.Errorf("error when setting the multi-factor authentication configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), nil
+}
+
+func validateMfaConfig(config MfaConfig) error {
+
+	steps := make(map[int]bool)
+	for _, step := range config.Steps {
+		if step.Step < 2 {
+			return fmt.Errorf("invalid multi-factor authentication configurations: step %d should be 2 or later, "+
+				"since the first step is configured in the authentication steps of the application", step.Step)
+		}
+		if steps[step.Step] {
+			return fmt.Errorf("invalid multi-factor authentication configurations: step %d is given more than once",
+				step.Step)
+		}
+		steps[step.Step] = true
+		if len(step.Options) == 0 {
+			return fmt.Errorf("invalid multi-factor authentication configurations: step %d has no options", step.Step)
+		}
+		for _, option := range step.Options {
+			if option.Idp == "" || option.Authenticator == "" {
+				return fmt.Errorf("invalid multi-factor authentication configurations: the options of step %d "+
+					"should have an idp and an authenticator", step.Step)
+			}
+		}
+	}
+	return nil
+}
+
+func getMfaConfig(appYaml map[interface{}]interface{}) MfaConfig {
+
+	config := MfaConfig{Steps: []MfaStep{}}
+	for _, step := range getAuthenticationSteps(appYaml) {
+		stepOrder, _ := step["stepOrder"].(int)
+		if stepOrder < 2 {
+			continue
+		}
+		mfaStep := MfaStep{Step: stepOrder, Options: []MfaOption{}}
+		for _, authenticator := range getYamlMapList(step["localAuthenticatorConfigs"]) {
+			name, _ := authenticator["name"].(string)
+			mfaStep.Options = append(mfaStep.Options, MfaOption{Idp: utils.RESIDENT_IDP_NAME, Authenticator: name})
+		}
+		for _, idp := range getYamlMapList(step["federatedIdentityProviders"]) {
+			idpName, _ := idp["identityProviderName"].(string)
+			defaultAuthenticator, _ := idp["defaultAuthenticatorConfig"].(map[interface{}]interface{})
+			name, _ := defaultAuthenticator["name"].(string)
+			mfaStep.Options = append(mfaStep.Options, MfaOption{Idp: idpName, Authenticator: name})
+		}
+		config.Steps = append(config.Steps, mfaStep)
+	}
+	sort.Slice(config.Steps, func(i, j int) bool {
+		return config.Steps[i].Step < config.Steps[j].Step
+	})
+	return config
+}
+
+func setMfaSteps(appYaml map[interface{}]interface{}, config MfaConfig) {
+
+	authConfig, _ := appYaml["localAndOutBoundAuthenticationConfig"].(map[interface{}]interface{})
+	if authConfig == nil {
+		authConfig = make(map[interface{}]interface{})
+		appYaml["localAndOutBoundAuthenticationConfig"] = authConfig
+	}
+
+	// The first step is kept as it is, and the deployed configurations of the authenticators, such as the display
+	// names, are kept for the options that are already in the step.
+	var steps []interface{}
+	deployedSteps := make(map[int]map[interface{}]interface{})
+	for _, step := range getAuthenticationSteps(appYaml) {
+		stepOrder, _ := step["stepOrder"].(int)
+		if stepOrder < 2 {
+			steps = append(steps, step)
+		} else {
+			deployedSteps[stepOrder] = step
+		}
+	}
+	for _, mfaStep := range config.Steps {
+		step, ok := deployedSteps[mfaStep.Step]
+		if !ok {
+			step = map[interface{}]interface{}{"stepOrder": mfaStep.Step}
+		}
+		localAuthenticators := getYamlMapsByKey(step["localAuthenticatorConfigs"], "name")
+		idps := getYamlMapsByKey(step["federatedIdentityProviders"], "identityProviderName")
+
+		var localOptions, federatedOptions []interface{}
+		for _, option := range mfaStep.Options {
+			if option.Idp == utils.RESIDENT_IDP_NAME {
+				authenticator, ok := localAuthenticators[option.Authenticator]
+				if !ok {
+					authenticator = map[interface{}]interface{}{"name": option.Authenticator, "enabled": true}
+				}
+				localOptions = append(localOptions, authenticator)
+				continue
+			}
+			idp, ok := idps[option.Idp]
+			defaultAuthenticator, _ := idp["defaultAuthenticatorConfig"].(map[interface{}]interface{})
+			if !ok || defaultAuthenticator["name"] != option.Authenticator {
+				authenticator := map[interface{}]interface{}{"name": option.Authenticator, "enabled": true}
+				idp = map[interface{}]interface{}{
+					"identityProviderName":          option.Idp,
+					"defaultAuthenticatorConfig":    authenticator,
+					"federatedAuthenticatorConfigs": []interface{}{authenticator},
+				}
+			}
+			federatedOptions = append(federatedOptions, idp)
+		}
+		step["localAuthenticatorConfigs"] = localOptions
+		step["federatedIdentityProviders"] = federatedOptions
+		steps = append(steps, step)
+	}
+	authConfig["authenticationSteps"] = steps
+
+	// Applications with more than one step use the step based authentication flow.
+	if len(config.Steps) > 0 {
+		authConfig["authenticationType"] = FLOW_AUTHENTICATION_TYPE
+	}
+	clampStepIndex(authConfig, "subjectStepId", len(steps))
+	clampStepIndex(authConfig, "attributeStepId", len(steps))
+}
+
+func clampStepIndex(authConfig map[interface{}]interface{}, key string, stepCount int) {
+
+	// The subject and attribute steps should refer to an existing step after the steps are replaced.
+	if index, ok := authConfig[key].(int); ok && index > stepCount {
+		authConfig[key] = 1
+	}
+}
+
+func getAuthenticationSteps(appYaml map[interface{}]interface{}) []map[interface{}]interface{} {
+
+	authConfig, _ := appYaml["localAndOutBoundAuthenticationConfig"].(map[interface{}]interface{})
+	return getYamlMapList(authConfig["authenticationSteps"])
+}
+
+func getYamlMapList(value interface{}) []map[interface{}]interface{} {
+
+	list, _ := value.([]interface{})
+	var maps []map[interface{}]interface{}
+	for _, item := range list {
+		if itemMap, ok := item.(map[interface{}]interface{}); ok {
+			maps = append(maps, itemMap)
+		}
+	}
+	return maps
+}
+
+func getYamlMapsByKey(value interface{}, key string) map[string]map[interface{}]interface{} {
+
+	maps := make(map[string]map[interface{}]interface{})
+	for _, item := range getYamlMapList(value) {
+		if name, ok := item[key].(string); ok {
+			maps[name] = item
+		}
+	}
+	return maps
+}
@@ -0,0 +1,274 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const OPENAPI_SPECS_DIR = "OpenAPI"
+const OPENAPI_VERSION = "3.0.3"
+const OPENAPI_SPEC_VERSION = "1.0.0"
+const OPENAPI_SECURITY_SCHEME = "oauth2"
+const AUTHORIZED_APIS_PATH = "authorized-apis"
+
+// OpenAPI flows of the OAuth2 grant types. Grant types without an OpenAPI flow are not added to the spec.
+var openApiFlows = []struct {
+	grantType           string
+	flow                string
+	hasAuthorizationUrl bool
+	hasTokenUrl         bool
+}{
+	{"authorization_code", "authorizationCode", true, true},
+	{"implicit", "implicit", true, false},
+	{"password", "password", false, true},
+	{"client_credentials", "clientCredentials", false, true},
+}
+
+type authorizedApi struct {
+	Id               string `json:"id"`
+	Identifier       string `json:"identifier"`
+	DisplayName      string `json:"displayName"`
+	AuthorizedScopes []struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"displayName"`
+	} `json:"authorizedScopes"`
+}
+
+type apiResourceScope struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Description string `json:"description"`
+}
+
+type oidcGrantTypes struct {
+	GrantTypes []string `json:"grantTypes"`
+}
+
+func ExportOpenApiSpecs(outputDirPath string) {
+
+	// Generate an OpenAPI spec with the OAuth2 flows and the authorized API scopes of each OAuth2 application.
+	log.Println("Generating OpenAPI specs of the applications...")
+	if utils.IsResourceTypeExcluded(utils.APPLICATIONS) {
+		return
+	}
+	specsDirPath := filepath.Join(outputDirPath, OPENAPI_SPECS_DIR)
+	apps, err := getAppList()
+	if err != nil {
+		log.Println("Error while generating the OpenAPI specs of the applications.", err)
+		return
+	}
+	scopeDescriptions := make(map[string]map[string]string)
+	for _, app := range apps {
+		if utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			continue
+		}
+		spec, err := getOpenApiSpec(app, scopeDescriptions)
+		if err != nil {
+			log.Printf("Error while generating the OpenAPI spec of application: %s. %s", app.Name, err)
+			continue
+		}
+		if spec == nil {
+			continue
+		}
+		if err := writeOpenApiSpec(specsDirPath, app.Name, spec); err != nil {
+			log.Printf("Error while writing the OpenAPI spec of application: %s. %s", app.Name, err)
+			continue
+		}
+		log.Println("OpenAPI spec generated successfully for application: ", app.Name)
+	}
+}
+
+// Get the OpenAPI spec of the application. Returns nil if the application is not an OAuth2 application or does not
+// have authorized API scopes.
+func getOpenApiSpec(app Application, scopeDescriptions map[string]map[string]string) (yaml.MapSlice, error) {
+
+	grantTypes, err := getOidcGrantTypes(app.Id)
+	if err != nil || grantTypes == nil {
+		return nil, err
+	}
+	authorizedApis, err := getAuthorizedApis(app.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	scopes := make(map[string]string)
+	for _, api := range authorizedApis {
+		if len(api.AuthorizedScopes) == 0 {
+			continue
+		}
+		if _, ok := scopeDescriptions[api.Id]; !ok {
+			descriptions, err := getApiScopeDescriptions(api.Id)
+			if err != nil {
+				return nil, fmt.Errorf("error when retrieving the scopes of API resource: %s. %s", api.Identifier, err)
+			}
+			scopeDescriptions[api.Id] = descriptions
+		}
+		for _, scope := range api.AuthorizedScopes {
+			description := scopeDescriptions[api.Id][scope.Name]
+			if description == "" {
+				description = scope.DisplayName
+			}
+			scopes[scope.Name] = description
+		}
+	}
+	if len(scopes) == 0 {
+		log.Println("Application does not have authorized API scopes. Skipping the OpenAPI spec of application: ", app.Name)
+		return nil, nil
+	}
+	flows := getOpenApiFlows(grantTypes, scopes)
+	if len(flows) == 0 {
+		log.Println("Application does not have a grant type supported by OpenAPI. Skipping the OpenAPI spec of application: ",
+			app.Name)
+		return nil, nil
+	}
+
+	scopeNames := getSortedScopeNames(scopes)
+	return yaml.MapSlice{
+		{Key: "openapi", Value: OPENAPI_VERSION},
+		{Key: "info", Value: yaml.MapSlice{
+			{Key: "title", Value: app.Name},
+			{Key: "description", Value: "OAuth2 scopes authorized for the application: " + app.Name},
+			{Key: "version", Value: OPENAPI_SPEC_VERSION},
+		}},
+		{Key: "paths", Value: yaml.MapSlice{}},
+		{Key: "components", Value: yaml.MapSlice{
+			{Key: "securitySchemes", Value: yaml.MapSlice{
+				{Key: OPENAPI_SECURITY_SCHEME, Value: yaml.MapSlice{
+					{Key: "type", Value: "oauth2"},
+					{Key: "flows", Value: flows},
+				}},
+			}},
+		}},
+		{Key: "security", Value: []yaml.MapSlice{{{Key: OPENAPI_SECURITY_SCHEME, Value: scopeNames}}}},
+	}, nil
+}
+
+func getOpenApiFlows(grantTypes []string, scopes map[string]string) yaml.MapSlice {
+
+	scopeMap := yaml.MapSlice{}
+	for _, scopeName := range getSortedScopeNames(scopes) {
+		scopeMap = append(scopeMap, yaml.MapItem{Key: scopeName, Value: scopes[scopeName]})
+	}
+	var flows yaml.MapSlice
+	for _, openApiFlow := range openApiFlows {
+		if !utils.Contains(grantTypes, openApiFlow.grantType) {
+			continue
+		}
+		var flow yaml.MapSlice
+		if openApiFlow.hasAuthorizationUrl {
+			flow = append(flow, yaml.MapItem{Key: "authorizationUrl", Value: utils.GetTenantUrl() + "/oauth2/authorize"})
+		}
+		if openApiFlow.hasTokenUrl {
+			flow = append(flow, yaml.MapItem{Key: "tokenUrl", Value: utils.GetTenantUrl() + "/oauth2/token"})
+		}
+		if openApiFlow.hasTokenUrl && utils.Contains(grantTypes, "refresh_token") {
+			flow = append(flow, yaml.MapItem{Key: "refreshUrl", Value: utils.GetTenantUrl() + "/oauth2/token"})
+		}
+		flow = append(flow, yaml.MapItem{Key: "scopes", Value: scopeMap})
+		flows = append(flows, yaml.MapItem{Key: openApiFlow.flow, Value: flow})
+	}
+	return flows
+}
+
+func getSortedScopeNames(scopes map[string]string) []string {
+
+	var scopeNames []string
+	for scopeName := range scopes {
+		scopeNames = append(scopeNames, scopeName)
+	}
+	sort.Strings(scopeNames)
+	return scopeNames
+}
+
+func getOidcGrantTypes(appId string) ([]string, error) {
+
+	// Applications without the OIDC inbound protocol are not OAuth2 applications.
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, appId+"/"+OIDC_PROTOCOL_PATH, nil)
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the OAuth2 configurations: %s", err)
+	}
+	var config oidcGrantTypes
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the OAuth2 configurations: %s", err)
+	}
+	return config.GrantTypes, nil
+}
+
+func getAuthorizedApis(appId string) ([]authorizedApi, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, appId+"/"+AUTHORIZED_APIS_PATH, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the authorized APIs: %s", err)
+	}
+	var authorizedApis []authorizedApi
+	if err := json.Unmarshal(body, &authorizedApis); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the authorized APIs: %s", err)
+	}
+	return authorizedApis, nil
+}
+
+func getApiScopeDescriptions(apiResourceId string) (map[string]string, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.API_RESOURCES, url.PathEscape(apiResourceId)+"/scopes", nil)
+	if err != nil {
+		return nil, err
+	}
+	var scopes []apiResourceScope
+	if err := json.Unmarshal(body, &scopes); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved scopes: %s", err)
+	}
+	descriptions := make(map[string]string)
+	for _, scope := range scopes {
+		descriptions[scope.Name] = scope.Description
+		if scope.Description == "" {
+			descriptions[scope.Name] = scope.DisplayName
+		}
+	}
+	return descriptions, nil
+}
+
+func writeOpenApiSpec(specsDirPath string, appName string, spec yaml.MapSlice) error {
+
+	content, err := yaml.Marshal(spec)
+	if err != nil {
+		return fmt.Errorf("error when creating the OpenAPI spec content: %s", err)
+	}
+	if err := os.MkdirAll(specsDirPath, 0700); err != nil {
+		return fmt.Errorf("error when creating the OpenAPI specs directory: %s", err)
+	}
+	specFileName := filepath.Join(specsDirPath, strings.ReplaceAll(appName, "/", "_")+".yaml")
+	return ioutil.WriteFile(specFileName, content, 0644)
+}
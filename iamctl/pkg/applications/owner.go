@@ -0,0 +1,190 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/users"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Keys of the application owner in the exported application file. The owner is identified by the name when both keys
+// are given, since the ids of the users are different in each environment.
+const OWNER_ID = "ownerId"
+const OWNER_NAME = "ownerName"
+
+const PRIMARY_USER_STORE_DOMAIN = "PRIMARY"
+
+func addAppOwner(fileContent []byte) ([]byte, error) {
+
+	var ownerConfig AppOwnerConfig
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &ownerConfig); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	if ownerConfig.Owner.UserName == "" {
+		return fileContent, nil
+	}
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	ownerName := ownerConfig.Owner.getQualifiedName()
+	appYaml[OWNER_NAME] = ownerName
+
+	// The owner id is not added if the users cannot be read, since the owner is identified by the name when importing.
+	ownerId, err := users.GetUserId(ownerName)
+	if err != nil {
+		log.Printf("Error when retrieving the id of the application owner: %s. %s", ownerName, err)
+	} else if ownerId != "" {
+		appYaml[OWNER_ID] = ownerId
+	}
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the application owner: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+// Returns the application file with the owner set to the owner given with the owner keys, and the owner resolved in the
+// target environment. The owner is nil if the file does not have the owner keys.
+func resolveAppOwner(fileData string) (string, *AppOwner, error) {
+
+	var appConfig AppConfig
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appConfig); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	if appConfig.OwnerName == "" && appConfig.OwnerId == "" {
+		return fileData, nil, nil
+	}
+
+	ownerName := appConfig.OwnerName
+	if ownerName == "" {
+		userName, err := users.GetUserName(appConfig.OwnerId)
+		if err != nil {
+			return "", nil, fmt.Errorf("error when retrieving the application owner with the id: %s. %s", appConfig.OwnerId, err)
+		}
+		ownerName = userName
+	} else {
+		ownerId, err := users.GetUserId(ownerName)
+		if err != nil {
+			return "", nil, fmt.Errorf("error when retrieving the application owner: %s. %s", ownerName, err)
+		}
+		if ownerId == "" {
+			return "", nil, fmt.Errorf("application owner: %s is not found in the target environment", ownerName)
+		}
+	}
+	owner := newAppOwner(ownerName)
+
+	// The owner keys are replaced with the owner of the application file, which the server sets when updating.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	delete(appYaml, OWNER_ID)
+	delete(appYaml, OWNER_NAME)
+	appYaml["owner"] = map[string]string{
+		"userName":        owner.UserName,
+		"userStoreDomain": owner.UserStoreDomain,
+		"tenantDomain":    utils.SERVER_CONFIGS.TenantDomain,
+	}
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when setting the application owner: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), &owner, nil
+}
+
+// Set the owner of the application if the deployed owner is different. A new application is owned by the user of the
+// tool, and the owner is set with an update of the application.
+func setAppOwner(appId string, appName string, importFilePath string, appFileData string, owner AppOwner) error {
+
+	if appId == "" {
+		return fmt.Errorf("application: %s is not found to set the owner", appName)
+	}
+	deployedOwner, err := getDeployedAppOwner(appId)
+	if err != nil {
+		return err
+	}
+	if deployedOwner.isSameUser(owner) {
+		return nil
+	}
+	log.Printf("Setting the owner of application: %s to %s", appName, owner.getQualifiedName())
+	if err := utils.SendUpdateRequest(utils.GetRequestContext(), "", importFilePath, appFileData, utils.APPLICATIONS); err != nil {
+		return fmt.Errorf("error when setting the application owner: %s", err)
+	}
+
+	// The server does not change the owner if the user cannot own the application.
+	deployedOwner, err = getDeployedAppOwner(appId)
+	if err != nil {
+		return err
+	}
+	if !deployedOwner.isSameUser(owner) {
+		return fmt.Errorf("the owner of the application is not changed to %s by the server", owner.getQualifiedName())
+	}
+	return nil
+}
+
+func getDeployedAppOwner(appId string) (AppOwner, error) {
+
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), appId, utils.MEDIA_TYPE_YAML, utils.APPLICATIONS, true)
+	if err != nil {
+		return AppOwner{}, fmt.Errorf("error when retrieving the application owner: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var ownerConfig AppOwnerConfig
+	body, err := ioutil.ReadAll(resp.Body)
+	if err == nil {
+		err = yaml.Unmarshal(utils.ReplaceTypeTags(body), &ownerConfig)
+	}
+	if err != nil {
+		return AppOwner{}, fmt.Errorf("error when reading the application owner: %s", err)
+	}
+	return ownerConfig.Owner, nil
+}
+
+func newAppOwner(userName string) AppOwner {
+
+	parts := strings.SplitN(userName, "/", 2)
+	if len(parts) == 2 {
+		return AppOwner{UserName: parts[1], UserStoreDomain: strings.ToUpper(parts[0])}
+	}
+	return AppOwner{UserName: userName, UserStoreDomain: PRIMARY_USER_STORE_DOMAIN}
+}
+
+// Returns the user name of the owner in the form used by the user management APIs, qualified with the user store
+// domain for the users of the secondary user stores.
+func (owner AppOwner) getQualifiedName() string {
+
+	if owner.UserStoreDomain == "" || strings.EqualFold(owner.UserStoreDomain, PRIMARY_USER_STORE_DOMAIN) {
+		return owner.UserName
+	}
+	return owner.UserStoreDomain + "/" + owner.UserName
+}
+
+func (owner AppOwner) isSameUser(other AppOwner) bool {
+
+	return strings.EqualFold(owner.getQualifiedName(), other.getQualifiedName())
+}
@@ -0,0 +1,125 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func GetPromotedContents(appNames []string) (map[string]string, error) {
+
+	// Export the applications from the source environment with the secrets masked, and replace the environment
+	// specific values with the keywords defined in the keyword configs of the source environment.
+	deployedApps, err := GetDeployedAppIds()
+	if err != nil {
+		return nil, err
+	}
+
+	contents := make(map[string]string)
+	for _, appName := range appNames {
+		appId, ok := deployedApps[appName]
+		if !ok {
+			return nil, fmt.Errorf("application: %s is not found in the source environment", appName)
+		}
+		log.Println("Fetching application: " + appName)
+		resp, err := utils.SendExportRequest(utils.GetRequestContext(), appId, utils.MEDIA_TYPE_YAML, utils.APPLICATIONS, true)
+		if err != nil {
+			return nil, fmt.Errorf("error while fetching the application: %s. %s", appName, err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error while reading the application: %s. %s", appName, err)
+		}
+		body = maskOAuthConsumerSecret(body)
+		contents[appName], err = utils.AddKeywordPlaceholders(string(body), getAppKeywordMapping(appName))
+		if err != nil {
+			return nil, fmt.Errorf("error while adding the keyword placeholders to the application: %s. %s", appName, err)
+		}
+	}
+	return contents, nil
+}
+
+func PromoteApps(contents map[string]string) error {
+
+	// Resolve the content of all applications for the target environment before importing any of them,
+	// so that an application with missing keyword mappings does not leave the target partially updated.
+	modifiedContents, err := ResolvePromotedContents(contents)
+	if err != nil {
+		return err
+	}
+	var appNames []string
+	for appName := range modifiedContents {
+		appNames = append(appNames, appName)
+	}
+	sort.Strings(appNames)
+
+	deployedAppIds, err := GetDeployedAppIds()
+	if err != nil {
+		return err
+	}
+	for _, appName := range appNames {
+		if utils.IsResourceExcluded(appName, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			continue
+		}
+		utils.EmitResourceStarted(utils.APPLICATIONS, appName, utils.IMPORT)
+
+		// The content is never written to a file. The file name is only used to resolve the media type.
+		fileInfo := utils.GetFileInfo(utils.GetSafeFileName(appName) + ".yml")
+		fileInfo.ResourceName = appName
+		if appId, ok := deployedAppIds[appName]; ok {
+			err = updateApplication(fileInfo.FileName, appId, modifiedContents[appName], fileInfo)
+		} else {
+			err = importApplication(fileInfo.FileName, modifiedContents[appName], fileInfo)
+		}
+		if err != nil {
+			log.Printf("Error when promoting application: %s. %s", appName, err)
+		}
+	}
+	return nil
+}
+
+func ResolvePromotedContents(contents map[string]string) (map[string]string, error) {
+
+	// Replace the keywords with the values of the target environment and remove the secret masks.
+	modifiedContents := make(map[string]string)
+	for appName, content := range contents {
+		modifiedFileData := utils.ReplaceKeywords(content, getAppKeywordMapping(appName))
+		if unresolvedKeywords := utils.FindUnresolvedKeywords(modifiedFileData); len(unresolvedKeywords) > 0 {
+			return nil, fmt.Errorf("keywords of application: %s are not defined in the keyword configs of the target environment: %s",
+				appName, strings.Join(unresolvedKeywords, ", "))
+		}
+		if err := utils.CheckTenantMismatches(utils.APPLICATIONS, appName, modifiedFileData); err != nil {
+			return nil, err
+		}
+		checkCertificateExpiry(appName, modifiedFileData)
+		modifiedFileData = utils.RemoveSecretMasks(modifiedFileData)
+		if err := ValidateClientAuthConfig(modifiedFileData); err != nil {
+			return nil, fmt.Errorf("invalid client authentication configurations for application: %s. %s", appName, err)
+		}
+		modifiedContents[appName] = modifiedFileData
+	}
+	return modifiedContents, nil
+}
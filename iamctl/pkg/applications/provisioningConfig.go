@@ -0,0 +1,185 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Key of the provisioning configurations in the exported application file. The configurations are applied with the
+// application API after the application is imported, and take precedence over the provisioning configurations of
+// the service provider in the application file.
+const PROVISIONING_CONFIG = "provisioningConfig"
+
+type ProvisioningConfig struct {
+	InboundProvisioning      *InboundProvisioningConfig `yaml:"inboundProvisioning,omitempty" json:"inboundProvisioning,omitempty"`
+	OutboundProvisioningIdps []OutboundProvisioningIdp  `yaml:"outboundProvisioningIdps,omitempty" json:"outboundProvisioningIdps"`
+}
+
+// Inbound provisioning of the users and groups created through the SCIM endpoints with the application.
+type InboundProvisioningConfig struct {
+	ProxyMode       bool   `yaml:"proxyMode" json:"proxyMode"`
+	UserStoreDomain string `yaml:"userStoreDomain,omitempty" json:"provisioningUserstoreDomain,omitempty"`
+}
+
+// Identity provider to which the users of the application are provisioned, with just-in-time provisioning if jit
+// is enabled.
+type OutboundProvisioningIdp struct {
+	Idp       string `yaml:"idp" json:"idp"`
+	Connector string `yaml:"connector" json:"connector"`
+	Blocking  bool   `yaml:"blocking" json:"blocking"`
+	Rules     bool   `yaml:"rules" json:"rules"`
+	Jit       bool   `yaml:"jit" json:"jit"`
+}
+
+type provisioningConfigResponse struct {
+	ProvisioningConfigurations ProvisioningConfig `json:"provisioningConfigurations"`
+}
+
+func addProvisioningConfig(appId string, fileContent []byte) ([]byte, error) {
+
+	// Add the provisioning configurations of the application to the application file, if they are not the defaults.
+	config, err := getProvisioningConfig(appId)
+	if err != nil || config == nil {
+		return fileContent, err
+	}
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	appYaml[PROVISIONING_CONFIG] = *config
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the provisioning configurations: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func extractProvisioningConfig(fileData string) (string, *ProvisioningConfig, error) {
+
+	// Remove the provisioning configurations from the application file, since the server does not accept them.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	configYaml, ok := appYaml[PROVISIONING_CONFIG]
+	if !ok {
+		return fileData, nil, nil
+	}
+
+	var config ProvisioningConfig
+	configContent, err := yaml.Marshal(configYaml)
+	if err == nil {
+		err = yaml.UnmarshalStrict(configContent, &config)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid provisioning configurations: %s", err)
+	}
+	delete(appYaml, PROVISIONING_CONFIG)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when removing the provisioning configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), &config, nil
+}
+
+// Returns the application file without the provisioning configurations, and the validated provisioning
+// configurations. The configurations are nil if the file does not have provisioning configurations.
+func resolveProvisioningConfig(fileData string) (string, *ProvisioningConfig, error) {
+
+	appFileData, config, err := extractProvisioningConfig(fileData)
+	if err != nil || config == nil {
+		return appFileData, nil, err
+	}
+	if err := validateProvisioningConfig(*config); err != nil {
+		return "", nil, err
+	}
+	return appFileData, config, nil
+}
+
+func validateProvisioningConfig(config ProvisioningConfig) error {
+
+	var idps []string
+	for _, idp := range config.OutboundProvisioningIdps {
+		if idp.Idp == "" || idp.Connector == "" {
+			return fmt.Errorf("invalid provisioning configurations: the idp and the connector are required for " +
+				"each outbound provisioning identity provider")
+		}
+		if utils.Contains(idps, idp.Idp) {
+			return fmt.Errorf("invalid provisioning configurations: identity provider: %s is given more than once", idp.Idp)
+		}
+		idps = append(idps, idp.Idp)
+	}
+	return nil
+}
+
+func getProvisioningConfig(appId string) (*ProvisioningConfig, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, appId, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the provisioning configurations: %s", err)
+	}
+	var response provisioningConfigResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the provisioning configurations: %s", err)
+	}
+	config := response.ProvisioningConfigurations
+	if isDefaultProvisioningConfig(config) {
+		return nil, nil
+	}
+	return &config, nil
+}
+
+// Check whether the provisioning configurations are the defaults of a new application, which are not added to the
+// application file.
+func isDefaultProvisioningConfig(config ProvisioningConfig) bool {
+
+	if len(config.OutboundProvisioningIdps) > 0 {
+		return false
+	}
+	inbound := config.InboundProvisioning
+	return inbound == nil || (!inbound.ProxyMode &&
+		(inbound.UserStoreDomain == "" || strings.EqualFold(inbound.UserStoreDomain, PRIMARY_USER_STORE_DOMAIN)))
+}
+
+func setProvisioningConfig(appId string, appName string, config ProvisioningConfig) error {
+
+	if appId == "" {
+		return fmt.Errorf("application: %s is not found to set the provisioning configurations", appName)
+	}
+	// An empty list of identity providers removes the outbound provisioning of the application.
+	if config.OutboundProvisioningIdps == nil {
+		config.OutboundProvisioningIdps = []OutboundProvisioningIdp{}
+	}
+	payload := map[string]interface{}{"provisioningConfigurations": config}
+	if _, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.APPLICATIONS, appId, payload); err != nil {
+		return fmt.Errorf("error when setting the provisioning configurations: %s", err)
+	}
+	log.Println("Provisioning configurations set for application: " + appName)
+	return nil
+}
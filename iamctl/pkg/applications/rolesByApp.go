@@ -0,0 +1,121 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const ROLES_BY_APP_FILE_NAME = "roles-by-app"
+
+type appRoles struct {
+	AllowedAudience string   `json:"allowedAudience" yaml:"allowedAudience"`
+	Roles           []string `json:"roles" yaml:"roles"`
+}
+
+type associatedRolesResponse struct {
+	AssociatedRoles struct {
+		AllowedAudience string `json:"allowedAudience"`
+		Roles           []struct {
+			Id   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"roles"`
+	} `json:"associatedRoles"`
+}
+
+func ExportRolesByApp(outputDirPath string, format string) error {
+
+	// Export the roles associated with each application into a single cross-reference file.
+	log.Println("Exporting roles by application...")
+	apps, err := getAppList()
+	if err != nil {
+		return err
+	}
+	rolesByApp := make(map[string]appRoles)
+	for _, app := range apps {
+		if utils.IsResourceExcluded(app.Name, utils.TOOL_CONFIGS.ApplicationConfigs) {
+			continue
+		}
+		roles, err := getAppRoles(app.Id)
+		if err != nil {
+			log.Printf("Error while retrieving roles of application: %s. %s", app.Name, err)
+			continue
+		}
+		rolesByApp[app.Name] = roles
+	}
+
+	var content []byte
+	var fileName string
+	switch format {
+	case "json":
+		fileName = ROLES_BY_APP_FILE_NAME + ".json"
+		content, err = json.MarshalIndent(rolesByApp, "", "  ")
+	default:
+		fileName = ROLES_BY_APP_FILE_NAME + ".yaml"
+		content, err = yaml.Marshal(rolesByApp)
+	}
+	if err != nil {
+		return fmt.Errorf("error when creating the roles by application content: %s", err)
+	}
+
+	os.MkdirAll(outputDirPath, 0700)
+	exportedFileName := filepath.Join(outputDirPath, fileName)
+	err = ioutil.WriteFile(exportedFileName, content, 0644)
+	if err != nil {
+		return fmt.Errorf("error when writing the roles by application file: %w", err)
+	}
+	log.Println("Roles by application exported successfully to: " + exportedFileName)
+	return nil
+}
+
+func getAppRoles(appId string) (appRoles, error) {
+
+	resp, err := utils.SendGetRequest(utils.GetRequestContext(), utils.APPLICATIONS, appId, map[string]string{"attributes": "associatedRoles"})
+	if err != nil {
+		return appRoles{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return appRoles{}, fmt.Errorf("error when reading the application details: %s", err)
+	}
+	var response associatedRolesResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return appRoles{}, fmt.Errorf("error when unmarshalling the application details: %s", err)
+	}
+
+	roles := appRoles{
+		AllowedAudience: response.AssociatedRoles.AllowedAudience,
+		Roles:           []string{},
+	}
+	for _, role := range response.AssociatedRoles.Roles {
+		roles.Roles = append(roles.Roles, role.Name)
+	}
+	return roles, nil
+}
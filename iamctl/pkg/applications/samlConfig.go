@@ -0,0 +1,242 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Certificate of the application, which is used to validate the signatures of the SAML requests.
+const APP_CERTIFICATE_FIELD = "certificateContent"
+
+// Certificate given in the SAML inbound configuration of the application.
+const SAML_CERTIFICATE_FIELD = "samlsso.certificateContent"
+
+// Mask the certificates of the SAML applications in the same way the OAuth consumer secrets are masked, when the
+// secrets are excluded from the exported files.
+func maskSamlCertificates(fileContent []byte) ([]byte, error) {
+
+	appConfig, err := unmarshalAppConfig(string(fileContent))
+	if err != nil {
+		return fileContent, err
+	}
+	isMasked := false
+	visitSamlCertificates(appConfig, func(field string, value interface{}) interface{} {
+		if certificate, ok := value.(string); ok && certificate != "" {
+			isMasked = true
+			return getCertificateMask()
+		}
+		return value
+	})
+	if !isMasked {
+		return fileContent, nil
+	}
+	return marshalAppConfig(appConfig)
+}
+
+// Resolve the masked certificates of a SAML application before importing. The certificates of the deployed
+// application are retained when updating, while a new application cannot be created with a masked certificate.
+func resolveSamlCertificates(appId string, appName string, fileData string) (string, error) {
+
+	if !strings.Contains(fileData, utils.SENSITIVE_FIELD_MASK) {
+		return fileData, nil
+	}
+	appConfig, err := unmarshalAppConfig(fileData)
+	if err != nil {
+		return fileData, err
+	}
+	var maskedFields []string
+	visitSamlCertificates(appConfig, func(field string, value interface{}) interface{} {
+		if value == getCertificateMask() {
+			maskedFields = append(maskedFields, field)
+		}
+		return value
+	})
+	if len(maskedFields) == 0 {
+		return fileData, nil
+	}
+	if appId == "" {
+		return fileData, fmt.Errorf("the SAML certificate is masked in the file. Add the certificate to the file " +
+			"or to the keyword mappings of the application to create it")
+	}
+
+	deployedCertificates, err := getDeployedSamlCertificates(appId)
+	if err != nil {
+		return fileData, err
+	}
+	log.Println("Retaining the SAML certificates of the deployed application: " + appName)
+	visitSamlCertificates(appConfig, func(field string, value interface{}) interface{} {
+		if value == getCertificateMask() {
+			return deployedCertificates[field]
+		}
+		return value
+	})
+	modifiedContent, err := marshalAppConfig(appConfig)
+	if err != nil {
+		return fileData, err
+	}
+	return string(modifiedContent), nil
+}
+
+func getDeployedSamlCertificates(appId string) (map[string]interface{}, error) {
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), appId, utils.MEDIA_TYPE_YAML, utils.APPLICATIONS, true)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the deployed SAML certificates: %s", err)
+	}
+	defer resp.Body.Close()
+
+	deployedContent, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the deployed SAML certificates: %s", err)
+	}
+	deployedConfig, err := unmarshalAppConfig(string(deployedContent))
+	if err != nil {
+		return nil, err
+	}
+	certificates := make(map[string]interface{})
+	visitSamlCertificates(deployedConfig, func(field string, value interface{}) interface{} {
+		certificates[field] = value
+		return value
+	})
+	return certificates, nil
+}
+
+func validateSamlConfig(fileData string) error {
+
+	config, err := unmarshalAuthConfig([]byte(fileData))
+	if err != nil {
+		return err
+	}
+
+	for _, requestConfig := range config.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
+		if strings.ToLower(requestConfig.InboundAuthType) != utils.SAMLSSO {
+			continue
+		}
+		protocol := requestConfig.InboundConfigurationProtocol
+		if protocol.Issuer == "" {
+			return fmt.Errorf("issuer of the SAML inbound configuration is not provided")
+		}
+		if len(protocol.AssertionConsumerUrls) == 0 {
+			return fmt.Errorf("no assertion consumer URLs are provided for the SAML issuer: %s", protocol.Issuer)
+		}
+		if protocol.DefaultAssertionConsumerUrl != "" &&
+			!utils.Contains(protocol.AssertionConsumerUrls, protocol.DefaultAssertionConsumerUrl) {
+			return fmt.Errorf("default assertion consumer URL: %s is not one of the assertion consumer URLs",
+				protocol.DefaultAssertionConsumerUrl)
+		}
+	}
+	return nil
+}
+
+// Call the visit function for each certificate field of a SAML application and set the returned value to the field.
+// The application level certificate is visited only if the application has a SAML inbound configuration.
+func visitSamlCertificates(appConfig yaml.MapSlice, visit func(field string, value interface{}) interface{}) {
+
+	protocolConfigs := getSamlProtocolConfigs(appConfig)
+	if len(protocolConfigs) == 0 {
+		return
+	}
+	visitField(appConfig, "certificateContent", func(value interface{}) interface{} {
+		return visit(APP_CERTIFICATE_FIELD, value)
+	})
+	for _, protocolConfig := range protocolConfigs {
+		visitField(protocolConfig, "certificateContent", func(value interface{}) interface{} {
+			return visit(SAML_CERTIFICATE_FIELD, value)
+		})
+	}
+}
+
+func getSamlProtocolConfigs(appConfig yaml.MapSlice) []yaml.MapSlice {
+
+	var protocolConfigs []yaml.MapSlice
+	for _, item := range appConfig {
+		inboundConfig, ok := item.Value.(yaml.MapSlice)
+		if item.Key != "inboundAuthenticationConfig" || !ok {
+			continue
+		}
+		for _, field := range inboundConfig {
+			requestConfigs, ok := field.Value.([]interface{})
+			if field.Key != "inboundAuthenticationRequestConfigs" || !ok {
+				continue
+			}
+			for _, requestConfig := range requestConfigs {
+				config, ok := requestConfig.(yaml.MapSlice)
+				if !ok || !isSamlRequestConfig(config) {
+					continue
+				}
+				for _, configField := range config {
+					if protocolConfig, ok := configField.Value.(yaml.MapSlice); ok &&
+						configField.Key == "inboundConfigurationProtocol" {
+						protocolConfigs = append(protocolConfigs, protocolConfig)
+					}
+				}
+			}
+		}
+	}
+	return protocolConfigs
+}
+
+func isSamlRequestConfig(config yaml.MapSlice) bool {
+
+	for _, field := range config {
+		authType, ok := field.Value.(string)
+		if field.Key == "inboundAuthType" && ok && strings.ToLower(authType) == utils.SAMLSSO {
+			return true
+		}
+	}
+	return false
+}
+
+func visitField(config yaml.MapSlice, key string, visit func(value interface{}) interface{}) {
+
+	for i, field := range config {
+		if field.Key == key {
+			config[i].Value = visit(field.Value)
+		}
+	}
+}
+
+func unmarshalAppConfig(fileData string) (yaml.MapSlice, error) {
+
+	var appConfig yaml.MapSlice
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appConfig); err != nil {
+		return nil, fmt.Errorf("invalid file content for application: %s", err)
+	}
+	return appConfig, nil
+}
+
+func marshalAppConfig(appConfig yaml.MapSlice) ([]byte, error) {
+
+	modifiedContent, err := yaml.Marshal(appConfig)
+	if err != nil {
+		return nil, fmt.Errorf("error when writing the application content: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func getCertificateMask() string {
+
+	return strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+}
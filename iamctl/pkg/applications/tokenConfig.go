@@ -0,0 +1,243 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package applications
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Key of the token issuance configurations in the exported application file. The configurations are applied with
+// the OIDC inbound protocol API after the application is imported, and take precedence over the token configurations
+// of the OAuth inbound configuration in the application file.
+const TOKEN_CONFIG = "tokenConfig"
+
+const OIDC_PROTOCOL_PATH = "inbound-protocols/oidc"
+const OIDC_META_PATH = utils.INBOUND_PROTOCOLS_META_PATH + "/oidc"
+
+type TokenConfig struct {
+	AccessTokenType                       string `yaml:"accessTokenType,omitempty"`
+	UserAccessTokenExpiryInSeconds        int64  `yaml:"userAccessTokenExpiryInSeconds,omitempty"`
+	ApplicationAccessTokenExpiryInSeconds int64  `yaml:"applicationAccessTokenExpiryInSeconds,omitempty"`
+	RefreshTokenExpiryInSeconds           int64  `yaml:"refreshTokenExpiryInSeconds,omitempty"`
+	IdTokenExpiryInSeconds                int64  `yaml:"idTokenExpiryInSeconds,omitempty"`
+	IdTokenSignatureAlgorithm             string `yaml:"idTokenSignatureAlgorithm,omitempty"`
+}
+
+type oidcConfig struct {
+	AccessToken struct {
+		Type                                  string `json:"type"`
+		UserAccessTokenExpiryInSeconds        int64  `json:"userAccessTokenExpiryInSeconds"`
+		ApplicationAccessTokenExpiryInSeconds int64  `json:"applicationAccessTokenExpiryInSeconds"`
+	} `json:"accessToken"`
+	RefreshToken struct {
+		ExpiryInSeconds int64 `json:"expiryInSeconds"`
+	} `json:"refreshToken"`
+	IdToken struct {
+		ExpiryInSeconds          int64  `json:"expiryInSeconds"`
+		IdTokenSignedResponseAlg string `json:"idTokenSignedResponseAlg"`
+	} `json:"idToken"`
+}
+
+type oidcMetadata struct {
+	AccessTokenType struct {
+		Options []string `json:"options"`
+	} `json:"accessTokenType"`
+	IdTokenSignatureAlgorithm struct {
+		Options []string `json:"options"`
+	} `json:"idTokenSignatureAlgorithm"`
+}
+
+func addTokenConfig(appId string, fileContent []byte) ([]byte, error) {
+
+	// Add the token issuance configurations of OAuth applications to the application file.
+	config, err := getTokenConfig(appId)
+	if err != nil || config == nil {
+		return fileContent, err
+	}
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &appYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	appYaml[TOKEN_CONFIG] = *config
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the token configurations: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func extractTokenConfig(fileData string) (string, *TokenConfig, error) {
+
+	// Remove the token configurations from the application file, since the server does not accept them.
+	var appYaml map[interface{}]interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &appYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the application file: %s", err)
+	}
+	configYaml, ok := appYaml[TOKEN_CONFIG]
+	if !ok {
+		return fileData, nil, nil
+	}
+
+	var config TokenConfig
+	configContent, err := yaml.Marshal(configYaml)
+	if err == nil {
+		err = yaml.UnmarshalStrict(configContent, &config)
+	}
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid token configurations: %s", err)
+	}
+	delete(appYaml, TOKEN_CONFIG)
+
+	modifiedContent, err := yaml.Marshal(appYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when removing the token configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), &config, nil
+}
+
+// Returns the application file without the token configurations, and the token configurations validated against
+// the token types and algorithms supported by the target environment. The configurations are nil if the file does
+// not have token configurations.
+func resolveTokenConfig(fileData string) (string, *TokenConfig, error) {
+
+	appFileData, config, err := extractTokenConfig(fileData)
+	if err != nil || config == nil {
+		return appFileData, nil, err
+	}
+	if err := validateTokenConfig(*config); err != nil {
+		return "", nil, err
+	}
+	return appFileData, config, nil
+}
+
+func getTokenConfig(appId string) (*TokenConfig, error) {
+
+	// Applications without the OIDC inbound protocol do not have token configurations.
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, appId+"/"+OIDC_PROTOCOL_PATH, nil)
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the token configurations: %s", err)
+	}
+	var config oidcConfig
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the token configurations: %s", err)
+	}
+	return &TokenConfig{
+		AccessTokenType:                       config.AccessToken.Type,
+		UserAccessTokenExpiryInSeconds:        config.AccessToken.UserAccessTokenExpiryInSeconds,
+		ApplicationAccessTokenExpiryInSeconds: config.AccessToken.ApplicationAccessTokenExpiryInSeconds,
+		RefreshTokenExpiryInSeconds:           config.RefreshToken.ExpiryInSeconds,
+		IdTokenExpiryInSeconds:                config.IdToken.ExpiryInSeconds,
+		IdTokenSignatureAlgorithm:             config.IdToken.IdTokenSignedResponseAlg,
+	}, nil
+}
+
+func validateTokenConfig(config TokenConfig) error {
+
+	expiryTimes := map[string]int64{
+		"userAccessTokenExpiryInSeconds":        config.UserAccessTokenExpiryInSeconds,
+		"applicationAccessTokenExpiryInSeconds": config.ApplicationAccessTokenExpiryInSeconds,
+		"refreshTokenExpiryInSeconds":           config.RefreshTokenExpiryInSeconds,
+		"idTokenExpiryInSeconds":                config.IdTokenExpiryInSeconds,
+	}
+	for field, expiryTime := range expiryTimes {
+		if expiryTime < 0 {
+			return fmt.Errorf("invalid token configurations: %s should be a positive number of seconds", field)
+		}
+	}
+	if config.AccessTokenType == "" && config.IdTokenSignatureAlgorithm == "" {
+		return nil
+	}
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, OIDC_META_PATH, nil)
+	if err != nil {
+		return fmt.Errorf("error when retrieving the supported token configurations: %s", err)
+	}
+	var metadata oidcMetadata
+	if err := json.Unmarshal(body, &metadata); err != nil {
+		return fmt.Errorf("error when unmarshalling the supported token configurations: %s", err)
+	}
+	if config.AccessTokenType != "" && !utils.Contains(metadata.AccessTokenType.Options, config.AccessTokenType) {
+		return fmt.Errorf("access token type: %s is not supported by the server. Supported types: %s",
+			config.AccessTokenType, strings.Join(metadata.AccessTokenType.Options, ", "))
+	}
+	if config.IdTokenSignatureAlgorithm != "" &&
+		!utils.Contains(metadata.IdTokenSignatureAlgorithm.Options, config.IdTokenSignatureAlgorithm) {
+		return fmt.Errorf("ID token signature algorithm: %s is not supported by the server. Supported algorithms: %s",
+			config.IdTokenSignatureAlgorithm, strings.Join(metadata.IdTokenSignatureAlgorithm.Options, ", "))
+	}
+	return nil
+}
+
+func setTokenConfig(appId string, appName string, config TokenConfig) error {
+
+	if appId == "" {
+		return fmt.Errorf("application: %s is not found to set the token configurations", appName)
+	}
+
+	// The OIDC configurations are replaced as a whole, so the deployed configurations are updated with the given
+	// token configurations to keep the other configurations unchanged.
+	path := appId + "/" + OIDC_PROTOCOL_PATH
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, path, nil)
+	if err != nil {
+		return fmt.Errorf("error when retrieving the OIDC configurations: %s", err)
+	}
+	var deployedConfig map[string]interface{}
+	if err := json.Unmarshal(body, &deployedConfig); err != nil {
+		return fmt.Errorf("error when unmarshalling the OIDC configurations: %s", err)
+	}
+	setOidcConfigValue(deployedConfig, "accessToken", "type", config.AccessTokenType)
+	setOidcConfigValue(deployedConfig, "accessToken", "userAccessTokenExpiryInSeconds", config.UserAccessTokenExpiryInSeconds)
+	setOidcConfigValue(deployedConfig, "accessToken", "applicationAccessTokenExpiryInSeconds",
+		config.ApplicationAccessTokenExpiryInSeconds)
+	setOidcConfigValue(deployedConfig, "refreshToken", "expiryInSeconds", config.RefreshTokenExpiryInSeconds)
+	setOidcConfigValue(deployedConfig, "idToken", "expiryInSeconds", config.IdTokenExpiryInSeconds)
+	setOidcConfigValue(deployedConfig, "idToken", "idTokenSignedResponseAlg", config.IdTokenSignatureAlgorithm)
+
+	if _, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.APPLICATIONS, path, deployedConfig); err != nil {
+		return fmt.Errorf("error when setting the token configurations: %s", err)
+	}
+	log.Println("Token configurations set for application: " + appName)
+	return nil
+}
+
+func setOidcConfigValue(oidcConfig map[string]interface{}, section string, field string, value interface{}) {
+
+	// Fields that are not given in the token configurations keep the deployed values.
+	if value == "" || value == int64(0) {
+		return
+	}
+	sectionConfig, ok := oidcConfig[section].(map[string]interface{})
+	if !ok {
+		sectionConfig = make(map[string]interface{})
+		oidcConfig[section] = sectionConfig
+	}
+	sectionConfig[field] = value
+}
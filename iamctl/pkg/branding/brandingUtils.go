@@ -0,0 +1,174 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package branding
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const ORGANIZATION = "Organization"
+const PREFERENCE_FILE_NAME = "preference"
+const CUSTOM_TEXT_DIR_NAME = "CustomText"
+
+// Branding types of the server.
+const ORG_BRANDING_TYPE = "ORG"
+const APP_BRANDING_TYPE = "APP"
+
+// Organization or application that the branding belongs to.
+type brandedResource struct {
+	brandingType string
+	// Tenant domain of the organization or the id of the application in the target environment.
+	id string
+	// Name used for the folder and the summary.
+	name string
+}
+
+type brandingPreference struct {
+	Type       string      `json:"type" yaml:"-"`
+	Name       string      `json:"name" yaml:"-"`
+	Locale     string      `json:"locale" yaml:"locale"`
+	Preference interface{} `json:"preference" yaml:"preference"`
+}
+
+type customText struct {
+	Type       string      `json:"type" yaml:"-"`
+	Name       string      `json:"name" yaml:"-"`
+	Locale     string      `json:"locale" yaml:"-"`
+	Screen     string      `json:"screen" yaml:"-"`
+	Preference interface{} `json:"preference" yaml:"preference"`
+}
+
+func getOrganization() brandedResource {
+
+	return brandedResource{ORG_BRANDING_TYPE, utils.SERVER_CONFIGS.TenantDomain, ORGANIZATION}
+}
+
+// Returns the folder of the branded resource. Ex: Branding/Organization, Branding/Applications/hr-portal
+func (resource brandedResource) getDirPath(brandingDirPath string) string {
+
+	if resource.brandingType == APP_BRANDING_TYPE {
+		return filepath.Join(brandingDirPath, utils.APPLICATIONS, resource.name)
+	}
+	return filepath.Join(brandingDirPath, resource.name)
+}
+
+func (resource brandedResource) getQuery(locale string) url.Values {
+
+	return url.Values{"type": {resource.brandingType}, "name": {resource.id}, "locale": {locale}}
+}
+
+func getPreference(resource brandedResource, locale string) (*brandingPreference, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.BRANDING, "?"+resource.getQuery(locale).Encode(), nil)
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while retrieving the branding preference of %s. %w", resource.name, err)
+	}
+	var preference brandingPreference
+	err = json.Unmarshal(body, &preference)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved branding preference. %w", err)
+	}
+	return &preference, nil
+}
+
+func getCustomText(resource brandedResource, screen string, locale string) (*customText, error) {
+
+	query := resource.getQuery(locale)
+	query.Set("screen", screen)
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.BRANDING, "text?"+query.Encode(), nil)
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while retrieving the custom text of %s. %w", getSummaryName(resource, screen, locale), err)
+	}
+	var text customText
+	err = json.Unmarshal(body, &text)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved custom text. %w", err)
+	}
+	return &text, nil
+}
+
+func deleteCustomText(resource brandedResource, screen string, locale string) error {
+
+	query := resource.getQuery(locale)
+	query.Set("screen", screen)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodDelete, utils.BRANDING, "text?"+query.Encode(), nil)
+	return err
+}
+
+func isPreferenceEqual(localPreference interface{}, deployedPreference interface{}) bool {
+
+	// The preferences are compared in the JSON form, since the local files are read as YAML.
+	localJson, err := json.Marshal(toJsonValue(localPreference))
+	if err != nil {
+		return false
+	}
+	deployedJson, err := json.Marshal(toJsonValue(deployedPreference))
+	if err != nil {
+		return false
+	}
+	return string(localJson) == string(deployedJson)
+}
+
+func toJsonValue(value interface{}) interface{} {
+
+	// YAML maps are unmarshalled with interface keys, which cannot be marshalled to JSON.
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		jsonMap := make(map[string]interface{})
+		for key, val := range v {
+			jsonMap[fmt.Sprintf("%v", key)] = toJsonValue(val)
+		}
+		return jsonMap
+	case map[string]interface{}:
+		jsonMap := make(map[string]interface{})
+		for key, val := range v {
+			jsonMap[key] = toJsonValue(val)
+		}
+		return jsonMap
+	case []interface{}:
+		jsonList := make([]interface{}, len(v))
+		for i, val := range v {
+			jsonList[i] = toJsonValue(val)
+		}
+		return jsonList
+	case int:
+		return float64(v)
+	}
+	return value
+}
+
+func getSummaryName(resource brandedResource, screen string, locale string) string {
+
+	return resource.name + "/" + screen + "/" + locale
+}
+
+func getBrandingKeywordMapping(resourceName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(resourceName, utils.KEYWORD_CONFIGS.BrandingConfigs)
+}
@@ -0,0 +1,137 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package branding
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string, format string) {
+
+	// Export the branding of the organization and the applications to a folder per branded resource in the
+	// Branding folder.
+	log.Println("Exporting branding...")
+	exportFilePath = filepath.Join(exportFilePath, utils.BRANDING)
+
+	if utils.IsResourceTypeExcluded(utils.BRANDING) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.BRANDING)
+	if format != "yaml" {
+		log.Println("Branding is exported in the YAML format only.")
+	}
+
+	resources := []brandedResource{getOrganization()}
+	var appNames []string
+	appIds, err := applications.GetDeployedAppIds()
+	if err != nil {
+		log.Println("Error while exporting the branding of the applications.", err)
+	}
+	for appName := range appIds {
+		appNames = append(appNames, appName)
+	}
+	sort.SliceStable(appNames, func(i, j int) bool {
+		return utils.GetResourcePriority(appNames[i]) < utils.GetResourcePriority(appNames[j])
+	})
+	for _, appName := range appNames {
+		resources = append(resources, brandedResource{APP_BRANDING_TYPE, appIds[appName], appName})
+	}
+
+	for _, resource := range resources {
+		if utils.IsResourceExcluded(resource.name, utils.TOOL_CONFIGS.BrandingConfigs) {
+			utils.ReportSkippedResource(utils.BRANDING, resource.name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.BRANDING, resource.name)
+			continue
+		}
+		utils.EmitResourceStarted(utils.BRANDING, resource.name, utils.EXPORT)
+		isExported, err := exportBrandedResource(resource, resource.getDirPath(exportFilePath))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+			log.Printf("Error while exporting branding of: %s. %s", resource.name, err)
+		} else if isExported {
+			utils.UpdateSuccessSummary(utils.BRANDING, resource.name, utils.EXPORT)
+			log.Println("Branding exported successfully: ", resource.name)
+		}
+	}
+}
+
+func exportBrandedResource(resource brandedResource, outputDirPath string) (bool, error) {
+
+	// Applications without a branding preference or custom text use the branding of the organization.
+	isExported := false
+	preference, err := getPreference(resource, utils.DEFAULT_BRANDING_LOCALE)
+	if err != nil {
+		return false, err
+	}
+	if preference != nil {
+		log.Println("Exporting branding preference of: ", resource.name)
+		err := exportContent(preference, filepath.Join(outputDirPath, PREFERENCE_FILE_NAME+".yml"), resource.name)
+		if err != nil {
+			return false, err
+		}
+		isExported = true
+	}
+
+	// The server does not list the custom text of a resource, so the configured screens and locales are checked.
+	for _, screen := range utils.GetBrandingScreens() {
+		for _, locale := range utils.GetBrandingLocales() {
+			text, err := getCustomText(resource, screen, locale)
+			if err != nil {
+				return false, err
+			}
+			if text == nil {
+				continue
+			}
+			log.Println("Exporting custom text: ", getSummaryName(resource, screen, locale))
+			exportedFileName := filepath.Join(outputDirPath, CUSTOM_TEXT_DIR_NAME, screen, locale+".yml")
+			if err := exportContent(text, exportedFileName, resource.name); err != nil {
+				return false, err
+			}
+			isExported = true
+		}
+	}
+	return isExported, nil
+}
+
+func exportContent(content interface{}, exportedFileName string, resourceName string) error {
+
+	yamlContent, err := yaml.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(exportedFileName), 0700); err != nil {
+		return fmt.Errorf("error while creating the export directory: %s", err)
+	}
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, yamlContent,
+		getBrandingKeywordMapping(resourceName), utils.BRANDING)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,322 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package branding
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.BRANDING)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.BRANDING) {
+		return
+	}
+
+	log.Println("Importing branding...")
+	var resources []brandedResource
+	if _, err := os.Stat(filepath.Join(importFilePath, ORGANIZATION)); err == nil {
+		resources = append(resources, getOrganization())
+	}
+
+	// Application branding is linked by the application name, since the application ids differ in each environment.
+	appDirs, err := ioutil.ReadDir(filepath.Join(importFilePath, utils.APPLICATIONS))
+	if err != nil && !os.IsNotExist(err) {
+		log.Println("Error importing branding: ", err)
+		return
+	}
+	var appIds map[string]string
+	if len(appDirs) > 0 {
+		appIds, err = applications.GetDeployedAppIds()
+		if err != nil {
+			log.Println("Error importing the branding of the applications: ", err)
+			appDirs = nil
+		}
+	}
+	sort.SliceStable(appDirs, func(i, j int) bool {
+		return utils.GetResourcePriority(appDirs[i].Name()) < utils.GetResourcePriority(appDirs[j].Name())
+	})
+	for _, appDir := range appDirs {
+		if appDir.IsDir() {
+			resources = append(resources, brandedResource{APP_BRANDING_TYPE, appIds[appDir.Name()], appDir.Name()})
+		}
+	}
+
+	for _, resource := range resources {
+		if !utils.IsResourceIncluded(resource.name) {
+			utils.AddFilteredResourceToSummary(utils.BRANDING, resource.name)
+			continue
+		}
+		if utils.IsResourceExcluded(resource.name, utils.TOOL_CONFIGS.BrandingConfigs) {
+			utils.ReportSkippedResource(utils.BRANDING, resource.name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.BRANDING, resource.name)
+			continue
+		}
+		if resource.id == "" {
+			utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+			log.Printf("Error when importing branding of application: %s. The application does not exist in the "+
+				"target environment.", resource.name)
+			continue
+		}
+		importBrandedResource(resource, resource.getDirPath(importFilePath))
+	}
+}
+
+func importBrandedResource(resource brandedResource, dirPath string) {
+
+	log.Println("Importing branding of: ", resource.name)
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+		log.Printf("Error when reading the branding of: %s. %s", resource.name, err)
+		return
+	}
+	for _, file := range files {
+		if file.IsDir() || utils.GetFileInfo(file.Name()).ResourceName != PREFERENCE_FILE_NAME {
+			continue
+		}
+		utils.EmitResourceStarted(utils.BRANDING, resource.name, utils.IMPORT)
+		err := importPreference(resource, filepath.Join(dirPath, file.Name()))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+			log.Printf("Error when importing branding preference of: %s. %s", resource.name, err)
+		}
+	}
+
+	// Custom text is grouped in a folder per screen, with a file per locale.
+	localLocales := make(map[string][]string)
+	customTextDirPath := filepath.Join(dirPath, CUSTOM_TEXT_DIR_NAME)
+	screenDirs, err := ioutil.ReadDir(customTextDirPath)
+	if err != nil && !os.IsNotExist(err) {
+		utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+		log.Printf("Error when reading the custom text of: %s. %s", resource.name, err)
+		return
+	}
+	for _, screenDir := range screenDirs {
+		if !screenDir.IsDir() {
+			continue
+		}
+		screen := screenDir.Name()
+		localeFiles, err := ioutil.ReadDir(filepath.Join(customTextDirPath, screen))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.BRANDING, resource.name)
+			log.Printf("Error when reading the custom text of: %s. %s", resource.name, err)
+			return
+		}
+		for _, localeFile := range localeFiles {
+			if localeFile.IsDir() {
+				continue
+			}
+			locale := utils.GetFileInfo(localeFile.Name()).ResourceName
+			localLocales[screen] = append(localLocales[screen], locale)
+			summaryName := getSummaryName(resource, screen, locale)
+			utils.EmitResourceStarted(utils.BRANDING, summaryName, utils.IMPORT)
+			err := importCustomText(resource, screen, filepath.Join(customTextDirPath, screen, localeFile.Name()))
+			if err != nil {
+				utils.UpdateFailureSummary(utils.BRANDING, summaryName)
+				log.Printf("Error when importing custom text: %s. %s", summaryName, err)
+			}
+		}
+	}
+	if utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.BrandingConfigs) {
+		removeDeletedCustomText(resource, localLocales)
+	}
+}
+
+func readLocalContent(importFilePath string, resourceName string, summaryName string, content interface{}) (string, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return "", fmt.Errorf("error when reading the file: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getBrandingKeywordMapping(resourceName))
+	utils.CheckImportContent(utils.BRANDING, summaryName, modifiedFileData)
+	err = yaml.Unmarshal([]byte(modifiedFileData), content)
+	if err != nil {
+		return "", fmt.Errorf("invalid file content: %s", err)
+	}
+	return modifiedFileData, nil
+}
+
+func importPreference(resource brandedResource, importFilePath string) error {
+
+	var preference brandingPreference
+	modifiedFileData, err := readLocalContent(importFilePath, resource.name, resource.name, &preference)
+	if err != nil {
+		return err
+	}
+	if preference.Locale == "" {
+		preference.Locale = utils.DEFAULT_BRANDING_LOCALE
+	}
+	preference.Type, preference.Name = resource.brandingType, resource.id
+	preference.Preference = toJsonValue(preference.Preference)
+
+	if utils.IsImportStateUnchanged(utils.BRANDING, resource.name, modifiedFileData) {
+		log.Println("Branding preference is unchanged since the last import. Skipping update: " + resource.name)
+		utils.UpdateSuccessSummary(utils.BRANDING, resource.name, utils.UNCHANGED)
+		return nil
+	}
+	deployedPreference, err := getPreference(resource, preference.Locale)
+	if err != nil {
+		return err
+	}
+	if deployedPreference == nil {
+		log.Println("Creating branding preference of: " + resource.name)
+		_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.BRANDING, "", preference)
+		if err != nil {
+			return fmt.Errorf("error when creating branding preference: %s", err)
+		}
+		utils.UpdateImportState(utils.BRANDING, resource.name, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.BRANDING, resource.name, utils.IMPORT)
+		return nil
+	}
+	if !utils.FORCE_IMPORT && isPreferenceEqual(preference.Preference, deployedPreference.Preference) {
+		log.Println("Branding preference is unchanged. Skipping update: " + resource.name)
+		utils.UpdateImportState(utils.BRANDING, resource.name, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.BRANDING, resource.name, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating branding preference of: " + resource.name)
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.BRANDING, "", preference)
+	if err != nil {
+		return fmt.Errorf("error when updating branding preference: %s", err)
+	}
+	utils.UpdateImportState(utils.BRANDING, resource.name, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.BRANDING, resource.name, utils.UPDATE)
+	return nil
+}
+
+func importCustomText(resource brandedResource, screen string, importFilePath string) error {
+
+	locale := utils.GetFileInfo(importFilePath).ResourceName
+	summaryName := getSummaryName(resource, screen, locale)
+	var text customText
+	modifiedFileData, err := readLocalContent(importFilePath, resource.name, summaryName, &text)
+	if err != nil {
+		return err
+	}
+	text.Type, text.Name, text.Screen, text.Locale = resource.brandingType, resource.id, screen, locale
+	text.Preference = toJsonValue(text.Preference)
+
+	if utils.IsImportStateUnchanged(utils.BRANDING, summaryName, modifiedFileData) {
+		log.Println("Custom text is unchanged since the last import. Skipping update: " + summaryName)
+		utils.UpdateSuccessSummary(utils.BRANDING, summaryName, utils.UNCHANGED)
+		return nil
+	}
+	deployedText, err := getCustomText(resource, screen, locale)
+	if err != nil {
+		return err
+	}
+	if deployedText == nil {
+		log.Println("Creating custom text: " + summaryName)
+		_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.BRANDING, "text", text)
+		if err != nil {
+			return fmt.Errorf("error when creating custom text: %s", err)
+		}
+		utils.UpdateImportState(utils.BRANDING, summaryName, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.BRANDING, summaryName, utils.IMPORT)
+		return nil
+	}
+	if !utils.FORCE_IMPORT && isPreferenceEqual(text.Preference, deployedText.Preference) {
+		log.Println("Custom text is unchanged. Skipping update: " + summaryName)
+		utils.UpdateImportState(utils.BRANDING, summaryName, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.BRANDING, summaryName, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating custom text: " + summaryName)
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.BRANDING, "text", text)
+	if err != nil {
+		return fmt.Errorf("error when updating custom text: %s", err)
+	}
+	utils.UpdateImportState(utils.BRANDING, summaryName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.BRANDING, summaryName, utils.UPDATE)
+	return nil
+}
+
+func removeDeletedCustomText(resource brandedResource, localLocales map[string][]string) {
+
+	// Remove the deployed custom text of the locales that do not exist locally. The server does not list the custom
+	// text, so the configured screens and locales, and the screens and locales of the local files are checked.
+	screens := utils.GetBrandingScreens()
+	locales := utils.GetBrandingLocales()
+	for screen, screenLocales := range localLocales {
+		if !utils.Contains(screens, screen) {
+			screens = append(screens, screen)
+		}
+		for _, locale := range screenLocales {
+			if !utils.Contains(locales, locale) {
+				locales = append(locales, locale)
+			}
+		}
+	}
+
+	type screenLocale struct{ screen, locale string }
+	var textToDelete []screenLocale
+	var textNames []string
+	for _, screen := range screens {
+		for _, locale := range locales {
+			if utils.Contains(localLocales[screen], locale) {
+				continue
+			}
+			deployedText, err := getCustomText(resource, screen, locale)
+			if err != nil {
+				log.Println("Error when checking the deployed custom text: ", err)
+				continue
+			}
+			if deployedText != nil {
+				textToDelete = append(textToDelete, screenLocale{screen, locale})
+				textNames = append(textNames, getSummaryName(resource, screen, locale))
+			}
+		}
+	}
+	if !utils.ConfirmDeletion(utils.BRANDING, textNames) {
+		return
+	}
+	for _, text := range textToDelete {
+		summaryName := getSummaryName(resource, text.screen, text.locale)
+		log.Println("Custom text not found locally. Deleting custom text: ", summaryName)
+		utils.EmitResourceStarted(utils.BRANDING, summaryName, utils.DELETE)
+		err := deleteCustomText(resource, text.screen, text.locale)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.BRANDING, summaryName)
+			log.Println("Error deleting custom text: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.BRANDING, summaryName, utils.DELETE)
+	}
+}
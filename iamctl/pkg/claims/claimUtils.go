@@ -22,6 +22,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"regexp"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
@@ -34,14 +35,22 @@ type claimDialect struct {
 }
 
 type ClaimDialectConfigurations struct {
-	URI string `yaml:"dialectURI"`
-	ID  string `yaml:"id"`
+	URI    string `yaml:"dialectURI"`
+	ID     string `yaml:"id"`
+	Claims []struct {
+		ClaimURI            string `yaml:"claimURI"`
+		MappedLocalClaimURI string `yaml:"mappedLocalClaimURI"`
+		Properties          []struct {
+			Key   string `yaml:"key"`
+			Value string `yaml:"value"`
+		} `yaml:"properties"`
+	} `yaml:"claims"`
 }
 
 func getClaimDialectsList() ([]claimDialect, error) {
 
 	var list []claimDialect
-	resp, err := utils.SendGetListRequest(utils.CLAIMS, -1)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.CLAIMS, -1)
 	if err != nil {
 		return nil, fmt.Errorf("error while retrieving claim dialect list. %w", err)
 	}
@@ -69,10 +78,7 @@ func getClaimDialectsList() ([]claimDialect, error) {
 
 func getClaimKeywordMapping(claimDialectName string) map[string]interface{} {
 
-	if utils.KEYWORD_CONFIGS.ClaimConfigs != nil {
-		return utils.ResolveAdvancedKeywordMapping(claimDialectName, utils.KEYWORD_CONFIGS.ClaimConfigs)
-	}
-	return utils.KEYWORD_CONFIGS.KeywordMappings
+	return utils.ResolveAdvancedKeywordMapping(claimDialectName, utils.KEYWORD_CONFIGS.ClaimConfigs)
 }
 
 func getDeployedClaimDialectNames() []string {
@@ -101,7 +107,7 @@ func formatFileName(fileName string) string {
 
 func getClaimDialectId(claimDialectFilePath string) (string, error) {
 
-	fileContent, err := ioutil.ReadFile(claimDialectFilePath)
+	fileContent, err := utils.ReadResourceFile(claimDialectFilePath)
 	if err != nil {
 		return "", fmt.Errorf("error when reading the file: %s. %s", claimDialectFilePath, err)
 	}
@@ -125,3 +131,104 @@ func getClaimDialectId(claimDialectFilePath string) (string, error) {
 	// Claim dialect does not exist, returning an empty user ID
 	return "", nil
 }
+
+func isLocalClaimDialect(claimDialectConfig ClaimDialectConfigurations) bool {
+
+	return claimDialectConfig.URI == utils.LOCAL_CLAIM_DIALECT_URI || claimDialectConfig.ID == utils.LOCAL_CLAIM_DIALECT_ID
+}
+
+func getLocalClaimURIs(localClaimDialectFilePath string) (map[string]bool, error) {
+
+	var content []byte
+	var err error
+	if localClaimDialectFilePath != "" {
+		content, err = utils.ReadResourceFile(localClaimDialectFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the local claim dialect file: %s", err)
+		}
+	} else {
+		// Local claim dialect is not available locally. Use the deployed local claims instead.
+		resp, err := utils.SendExportRequest(utils.GetRequestContext(), utils.LOCAL_CLAIM_DIALECT_ID, utils.MEDIA_TYPE_YAML, utils.CLAIMS, true)
+		if err != nil {
+			return nil, fmt.Errorf("error when retrieving the deployed local claims: %s", err)
+		}
+		defer resp.Body.Close()
+		content, err = ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the deployed local claims: %s", err)
+		}
+	}
+
+	var localClaimDialect ClaimDialectConfigurations
+	err = yaml.Unmarshal(content, &localClaimDialect)
+	if err != nil {
+		return nil, fmt.Errorf("invalid content for the local claim dialect: %s", err)
+	}
+
+	localClaimURIs := make(map[string]bool)
+	for _, claim := range localClaimDialect.Claims {
+		localClaimURIs[claim.ClaimURI] = true
+	}
+	return localClaimURIs, nil
+}
+
+func getDanglingClaimReferences(claimDialectConfig ClaimDialectConfigurations, localClaimURIs map[string]bool) []string {
+
+	var danglingReferences []string
+	for _, claim := range claimDialectConfig.Claims {
+		if claim.MappedLocalClaimURI != "" && !localClaimURIs[claim.MappedLocalClaimURI] {
+			danglingReferences = append(danglingReferences, claim.ClaimURI+" -> "+claim.MappedLocalClaimURI)
+		}
+	}
+	return danglingReferences
+}
+
+func getSystemClaimURIs(claimDialectConfig ClaimDialectConfigurations) map[string]bool {
+
+	systemClaimURIs := make(map[string]bool)
+	for _, claim := range claimDialectConfig.Claims {
+		claimProperties := make(map[string]string)
+		for _, property := range claim.Properties {
+			claimProperties[property.Key] = property.Value
+		}
+		if utils.IsSystemClaim(claim.ClaimURI, claimProperties) {
+			systemClaimURIs[claim.ClaimURI] = true
+		}
+	}
+	return systemClaimURIs
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localDialectNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.CLAIMS),
+		utils.TOOL_CONFIGS.ClaimConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	claimDialects, err := getClaimDialectsList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedDialects []utils.DeployedResource
+	for _, claimDialect := range claimDialects {
+		deployedDialects = append(deployedDialects, utils.DeployedResource{
+			Name:      formatFileName(claimDialect.DialectURI),
+			Deletable: !utils.IsResourceExcluded(claimDialect.DialectURI, utils.TOOL_CONFIGS.ClaimConfigs),
+		})
+	}
+	return utils.NewImportPlan(utils.CLAIMS, localDialectNames, deployedDialects, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the claim dialects that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	claimDialects, err := getClaimDialectsList()
+	if err != nil {
+		return nil, err
+	}
+	var claimDialectNames []string
+	for _, claimDialect := range claimDialects {
+		claimDialectNames = append(claimDialectNames, formatFileName(claimDialect.DialectURI))
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.CLAIMS), claimDialectNames)
+}
@@ -25,6 +25,7 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 )
@@ -38,6 +39,7 @@ func ExportAll(exportFilePath string, format string) {
 	if utils.IsResourceTypeExcluded(utils.CLAIMS) {
 		return
 	}
+	utils.WarnUnfilteredSince(utils.CLAIMS)
 	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
 		os.MkdirAll(exportFilePath, 0700)
 	} else {
@@ -50,18 +52,29 @@ func ExportAll(exportFilePath string, format string) {
 	if err != nil {
 		log.Println("Error while retrieving Claim Dialect list.", err)
 	} else {
+		sort.SliceStable(claimDialects, func(i, j int) bool {
+			return utils.GetResourcePriority(claimDialects[i].DialectURI) < utils.GetResourcePriority(claimDialects[j].DialectURI)
+		})
+		utils.StartResourceProgress(utils.CLAIMS, len(claimDialects))
 		for _, dialect := range claimDialects {
 			if !utils.IsResourceExcluded(dialect.DialectURI, utils.TOOL_CONFIGS.ClaimConfigs) {
+				if !utils.IsTimeBudgetAvailable() {
+					utils.AddUnprocessedResourceToSummary(utils.CLAIMS, dialect.DialectURI)
+					continue
+				}
 				log.Println("Exporting Claim Dialect: ", dialect.DialectURI)
+				utils.EmitResourceStarted(utils.CLAIMS, dialect.DialectURI, utils.EXPORT)
 
 				err := exportClaimDialect(dialect.Id, exportFilePath, format)
 				if err != nil {
 					utils.UpdateFailureSummary(utils.CLAIMS, dialect.DialectURI)
 					log.Printf("Error while exporting Claim Dialect: %s. %s", dialect.DialectURI, err)
 				} else {
-					utils.UpdateSuccessSummary(utils.CLAIMS, dialect.DialectURI)
+					utils.UpdateSuccessSummary(utils.CLAIMS, dialect.DialectURI, utils.EXPORT)
 					log.Println("Claim Dialect exported successfully: ", dialect.DialectURI)
 				}
+			} else {
+				utils.ReportSkippedResource(utils.CLAIMS, dialect.DialectURI, utils.JUNIT_SKIPPED_EXCLUDED)
 			}
 		}
 	}
@@ -80,7 +93,7 @@ func exportClaimDialect(dialectId string, outputDirPath string, format string) e
 		fileType = utils.MEDIA_TYPE_YAML
 	}
 
-	resp, err := utils.SendExportRequest(dialectId, fileType, utils.CLAIMS, true)
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), dialectId, fileType, utils.CLAIMS, true)
 	if err != nil {
 		return fmt.Errorf("error while exporting the claim dialect: %s", err)
 	}
@@ -106,9 +119,5 @@ func exportClaimDialect(dialectId string, outputDirPath string, format string) e
 		return fmt.Errorf("error while processing the exported content: %s", err)
 	}
 
-	err = ioutil.WriteFile(exportedFileName, modifiedFile, 0644)
-	if err != nil {
-		return fmt.Errorf("error when writing the exported content to file: %w", err)
-	}
-	return nil
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
 }
@@ -19,11 +19,13 @@
 package claims
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
@@ -46,40 +48,78 @@ func ImportAll(inputDirPath string) {
 		if err != nil {
 			log.Println("Error importing claim dialects: ", err)
 		}
-		if utils.TOOL_CONFIGS.AllowDelete {
+		if utils.IsDeleteAllowed() {
 			removeDeletedDeployedClaimdialect(files, importFilePath)
 		}
 	}
 
-	// Move the local claims file to the front of the array to import it first
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+
+	// Move the local claims file to the front of the array to import it first, since external claims are mapped to local claims.
+	localClaimDialectFilePath := ""
 	for i, file := range files {
-		if file.Name() == "http_wso2_org_claims.yml" {
-			files[0], files[i] = files[i], files[0]
+		claimFilePath := filepath.Join(importFilePath, file.Name())
+		content, err := utils.ReadResourceFile(claimFilePath)
+		if err != nil {
+			continue
+		}
+		var claimDialectConfigurations ClaimDialectConfigurations
+		if yaml.Unmarshal(content, &claimDialectConfigurations) == nil && isLocalClaimDialect(claimDialectConfigurations) {
+			files = append([]os.FileInfo{file}, append(files[:i], files[i+1:]...)...)
+			dialectName := utils.GetFileInfo(file.Name()).ResourceName
+			if !utils.IsResourceExcluded(dialectName, utils.TOOL_CONFIGS.ClaimConfigs) && utils.IsResourceIncluded(dialectName) {
+				localClaimDialectFilePath = claimFilePath
+			}
 			break
 		}
 	}
 
+	utils.StartResourceProgress(utils.CLAIMS, len(files))
+	var localClaimURIs map[string]bool
 	for _, file := range files {
 		claimFilePath := filepath.Join(importFilePath, file.Name())
-		dialectName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		dialectName := utils.GetFileInfo(file.Name()).ResourceName
+		if !utils.IsResourceIncluded(dialectName) {
+			utils.AddFilteredResourceToSummary(utils.CLAIMS, dialectName)
+			continue
+		}
+		if !utils.IsResourceExcluded(dialectName, utils.TOOL_CONFIGS.ClaimConfigs) && !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.CLAIMS, dialectName)
+			continue
+		}
 
 		if !utils.IsResourceExcluded(dialectName, utils.TOOL_CONFIGS.ClaimConfigs) {
+			if claimFilePath != localClaimDialectFilePath && localClaimURIs == nil {
+				var err error
+				localClaimURIs, err = getLocalClaimURIs(localClaimDialectFilePath)
+				if err != nil {
+					utils.LogWarning(utils.WARNING_CLAIM_REFERENCE, fmt.Sprintf(
+						"Unable to resolve local claims. References of external claims will not be validated. %s", err))
+					localClaimURIs = map[string]bool{}
+				}
+			}
 			dialectId, err := getClaimDialectId(claimFilePath)
 			if err != nil {
 				log.Printf("Invalid file configurations for Claim Dialect: %s. %s", dialectName, err)
 			} else {
-				err := importClaimDialect(dialectId, claimFilePath)
+				utils.EmitResourceStarted(utils.CLAIMS, dialectName, utils.IMPORT)
+				err := importClaimDialect(dialectId, claimFilePath, localClaimURIs)
 				if err != nil {
 					log.Println("error importing claim dialect:", err)
 				}
 			}
+		} else {
+			utils.ReportSkippedResource(utils.CLAIMS, dialectName, utils.JUNIT_SKIPPED_EXCLUDED)
 		}
 	}
 }
 
-func importClaimDialect(dialectId string, importFilePath string) error {
+func importClaimDialect(dialectId string, importFilePath string, localClaimURIs map[string]bool) error {
 
-	fileBytes, err := ioutil.ReadFile(importFilePath)
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
 	if err != nil {
 		return fmt.Errorf("error when reading the file for claim dialect: %s", err)
 	}
@@ -88,6 +128,7 @@ func importClaimDialect(dialectId string, importFilePath string) error {
 	fileInfo := utils.GetFileInfo(importFilePath)
 	claimKeywordMapping := getClaimKeywordMapping(fileInfo.ResourceName)
 	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), claimKeywordMapping)
+	utils.CheckImportContent(utils.CLAIMS, fileInfo.ResourceName, modifiedFileData)
 
 	// Unmarshal the file data to get the dialect URI as the resource name.
 	var claimDialectConfigurations ClaimDialectConfigurations
@@ -97,38 +138,80 @@ func importClaimDialect(dialectId string, importFilePath string) error {
 	}
 	fileInfo.ResourceName = claimDialectConfigurations.URI
 
+	// Skip the external claim dialects with claims mapped to local claims that do not exist.
+	if !isLocalClaimDialect(claimDialectConfigurations) && len(localClaimURIs) > 0 {
+		danglingReferences := getDanglingClaimReferences(claimDialectConfigurations, localClaimURIs)
+		if len(danglingReferences) > 0 {
+			utils.UpdateFailureSummary(utils.CLAIMS, fileInfo.ResourceName)
+			return fmt.Errorf("claim dialect: %s has claims mapped to local claims that do not exist:\n%s",
+				fileInfo.ResourceName, strings.Join(danglingReferences, "\n"))
+		}
+	}
+
 	if dialectId == "" {
 		return importDialect(importFilePath, modifiedFileData, fileInfo)
 	}
-	return updateDialect(dialectId, importFilePath, modifiedFileData, fileInfo)
+	if utils.IsImportStateUnchanged(utils.CLAIMS, fileInfo.ResourceName, modifiedFileData) {
+		log.Println("Claim dialect is unchanged since the last import. Skipping update: " + fileInfo.ResourceName)
+		utils.UpdateSuccessSummary(utils.CLAIMS, fileInfo.ResourceName, utils.UNCHANGED)
+		return nil
+	}
+	return updateDialect(dialectId, importFilePath, modifiedFileData, fileInfo, getSystemClaimURIs(claimDialectConfigurations))
 }
 
 func importDialect(importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
 	log.Println("Creating new claim dialect: " + fileInfo.ResourceName)
-	err := utils.SendImportRequest(importFilePath, modifiedFileData, utils.CLAIMS)
+	err := utils.SendImportRequest(utils.GetRequestContext(), importFilePath, modifiedFileData, utils.CLAIMS)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.CLAIMS, fileInfo.ResourceName)
 		return fmt.Errorf("error when importing claim dialect: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.CLAIMS, utils.IMPORT)
+	utils.UpdateImportState(utils.CLAIMS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.CLAIMS, fileInfo.ResourceName, utils.IMPORT)
 	log.Println("Claim dialect imported successfully.")
 	return nil
 }
 
-func updateDialect(dialectId string, importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
+func updateDialect(dialectId string, importFilePath string, modifiedFileData string, fileInfo utils.FileInfo,
+	systemClaimURIs map[string]bool) error {
 
 	log.Println("Updating claim dialect: " + fileInfo.ResourceName)
-	err := utils.SendUpdateRequest(dialectId, importFilePath, modifiedFileData, utils.CLAIMS)
+	err := utils.SendUpdateRequest(utils.GetRequestContext(), dialectId, importFilePath, modifiedFileData, utils.CLAIMS)
+	err = skipSystemClaimFailures(err, systemClaimURIs)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.CLAIMS, fileInfo.ResourceName)
 		return fmt.Errorf("error when updating claim dialect: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.CLAIMS, utils.UPDATE)
+	utils.UpdateImportState(utils.CLAIMS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.CLAIMS, fileInfo.ResourceName, utils.UPDATE)
 	log.Println("Claim dialect updated successfully.")
 	return nil
 }
 
+func skipSystemClaimFailures(err error, systemClaimURIs map[string]bool) error {
+
+	// System claims that cannot be modified are skipped with a warning, instead of failing the claim dialect.
+	var claimImportError *utils.ClaimImportError
+	if !errors.As(err, &claimImportError) || len(claimImportError.FailedOperations) == 0 {
+		return err
+	}
+	var failedOperations []utils.FailedOperation
+	for _, failedOperation := range claimImportError.FailedOperations {
+		if systemClaimURIs[failedOperation.ClaimURI] {
+			utils.LogWarning(utils.WARNING_SYSTEM_CLAIM, fmt.Sprintf("Skipping the system claim: %s, which cannot be modified. %s",
+				failedOperation.ClaimURI, strings.TrimSpace(failedOperation.Message)))
+		} else {
+			failedOperations = append(failedOperations, failedOperation)
+		}
+	}
+	if len(failedOperations) == 0 {
+		return nil
+	}
+	claimImportError.FailedOperations = failedOperations
+	return claimImportError
+}
+
 func removeDeletedDeployedClaimdialect(localFiles []os.FileInfo, importFilePath string) {
 
 	// Remove deployed claim dialects that do not exist locally.
@@ -137,6 +220,7 @@ func removeDeletedDeployedClaimdialect(localFiles []os.FileInfo, importFilePath
 		log.Println("Error retrieving deployed claim dialects: ", err)
 		return
 	}
+	var claimDialectsToDelete []claimDialect
 deployedResourcess:
 	for _, claimDialect := range deployedClaimDialects {
 		for _, file := range localFiles {
@@ -163,25 +247,30 @@ deployedResourcess:
 			log.Printf("Claim dialect: %s is excluded from deletion.\n", claimDialect.DialectURI)
 			continue
 		}
+		claimDialectsToDelete = append(claimDialectsToDelete, claimDialect)
+	}
+
+	var claimDialectNames []string
+	for _, claimDialect := range claimDialectsToDelete {
+		claimDialectNames = append(claimDialectNames, claimDialect.DialectURI)
+	}
+	if !utils.ConfirmDeletion(utils.CLAIMS, claimDialectNames) {
+		return
+	}
+	for _, claimDialect := range claimDialectsToDelete {
 		log.Println("Claim dialect not found locally. Deleting claim dialect: ", claimDialect.DialectURI)
-		err := utils.SendDeleteRequest(claimDialect.Id, utils.CLAIMS)
+		utils.EmitResourceStarted(utils.CLAIMS, claimDialect.DialectURI, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), claimDialect.Id, utils.CLAIMS)
 		if err != nil {
+			utils.UpdateFailureSummary(utils.CLAIMS, claimDialect.DialectURI)
 			log.Println("Error deleting claim dialect: ", err)
+			continue
 		}
+		utils.UpdateSuccessSummary(utils.CLAIMS, claimDialect.DialectURI, utils.DELETE)
 	}
 }
 
 func readFileContent(filename string) ([]byte, error) {
 
-	file, err := os.Open(filename)
-	if err != nil {
-		return nil, err
-	}
-	defer file.Close()
-
-	content, err := ioutil.ReadAll(file)
-	if err != nil {
-		return nil, err
-	}
-	return content, nil
+	return utils.ReadResourceFile(filename)
 }
@@ -0,0 +1,53 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package client
+
+import (
+	"context"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// List the applications, sorted by the name.
+func (client *Client) ListApplications(ctx context.Context) ([]applications.AppListItem, error) {
+
+	var apps []applications.AppListItem
+	err := client.run(ctx, func() error {
+		var err error
+		apps, err = applications.ListApps()
+		return err
+	})
+	return apps, err
+}
+
+// Export the applications to the Applications folder of the output directory.
+func (client *Client) ExportApplications(ctx context.Context, outputDirPath string, options ExportOptions) error {
+
+	return client.runOperation(ctx, utils.APPLICATIONS, func() {
+		utils.PRUNE_EXPORT = options.Prune
+		applications.ExportAll(outputDirPath, getExportFormat(options))
+	})
+}
+
+// Import an application file from the Applications folder of a local directory.
+func (client *Client) ImportApplication(ctx context.Context, filePath string, options ImportOptions) error {
+
+	return client.importFile(ctx, utils.APPLICATIONS, filePath, options, applications.ImportAll)
+}
@@ -0,0 +1,190 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+// Package client exposes the export and import logic of the tool to other Go programs, such as Terraform providers
+// and custom controllers, without running the tool as a separate process.
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Options to connect to a target environment.
+type Options struct {
+	ServerUrl    string
+	TenantDomain string
+	ClientId     string
+	ClientSecret string
+	// Access token of the target environment. An access token is requested with the client credentials if not given.
+	Token          string
+	ClientCertFile string
+	ClientKeyFile  string
+	// Tool configs, such as the resources to be excluded, and the keyword mappings to be applied during import.
+	ToolConfigs    utils.ToolConfigs
+	KeywordConfigs utils.KeywordConfigs
+	// Middlewares of the HTTP requests of the client, such as request signing, applied inside the middlewares added
+	// with utils.UseHttpMiddleware. The first middleware is the outermost.
+	Middlewares []utils.HttpMiddleware
+	// Transport to send the HTTP requests of the client with, such as a transport with a proxy. The requests are sent
+	// with a transport configured for the server, including the client certificate, if not given.
+	Transport http.RoundTripper
+}
+
+// Options of the export methods.
+type ExportOptions struct {
+	// Format of the exported files: yaml, json or xml. The files are exported in YAML if not given.
+	Format string
+	// Remove the YAML files of the resources deleted on the server after a successful export.
+	Prune bool
+	// Generate an OpenAPI spec from the authorized API scopes of each OAuth2 application, when exporting the
+	// applications.
+	OpenApiSpecs bool
+}
+
+// Options of the import methods.
+type ImportOptions struct {
+	// Update the resource even if it is unchanged since the last import.
+	Force bool
+	// Import the file of a system application, such as the Console. The import is confirmed in the same way as
+	// with the --include-system-apps flag of the tool, so AssumeYes should be set when there is no terminal.
+	IncludeSystemApps bool
+	// Answer the confirmations of the import, such as the import of a system application, with yes in the same
+	// way as the --yes flag of the tool.
+	AssumeYes bool
+	// Names or glob patterns of the resources to be imported by ImportResources. All resources of the resource type
+	// are imported if not given.
+	IncludeOnly []string
+}
+
+// Client of a target environment. The resource packages read the configs of the target environment and the options
+// of a call from the package level variables of the utils package. A client is not safe for concurrent use: the calls
+// of all clients in a process are serialized, and each call swaps in the configs and the options of its client for
+// the duration of the call, so a call blocks until the calls started before it are completed.
+type Client struct {
+	configs utils.RunConfigs
+}
+
+var callLock sync.Mutex
+
+// Create a client of the target environment, requesting an access token if the token is not given in the options.
+func New(ctx context.Context, options Options) (*Client, error) {
+
+	serverConfigs := utils.ServerConfigs{ServerUrl: options.ServerUrl, TenantDomain: options.TenantDomain,
+		ClientId: options.ClientId, ClientSecret: options.ClientSecret, Token: options.Token,
+		ClientCertFile: options.ClientCertFile, ClientKeyFile: options.ClientKeyFile}
+	configs, err := utils.NewRunConfigs(serverConfigs, options.ToolConfigs, options.KeywordConfigs, options.Transport,
+		options.Middlewares...)
+	if err != nil {
+		return nil, err
+	}
+	client := &Client{configs: configs}
+	if options.Token != "" {
+		return client, nil
+	}
+	err = client.run(ctx, func() error {
+		token, err := utils.RequestAccessToken(ctx, utils.SERVER_CONFIGS)
+		if err != nil {
+			return fmt.Errorf("error when requesting the access token: %s", err)
+		}
+		client.configs.ServerConfigs.Token = token
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// Create a client of the target environment of the configs loaded by the tool, such as with the --config flag.
+func NewFromLoadedConfigs() *Client {
+
+	return &Client{configs: utils.GetRunConfigs()}
+}
+
+func (client *Client) run(ctx context.Context, call func() error) error {
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	callLock.Lock()
+	defer callLock.Unlock()
+
+	previousConfigs, previousContext := utils.GetRunConfigs(), utils.GetRequestContext()
+	includeOnly, forceImport := utils.INCLUDE_ONLY, utils.FORCE_IMPORT
+	includeSystemApps, assumeYes := utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES
+	pruneExport := utils.PRUNE_EXPORT
+	utils.SetRunConfigs(client.configs)
+	utils.SetRequestContext(ctx)
+	defer func() {
+		utils.SetRequestContext(previousContext)
+		utils.SetRunConfigs(previousConfigs)
+		utils.INCLUDE_ONLY, utils.FORCE_IMPORT = includeOnly, forceImport
+		utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES = includeSystemApps, assumeYes
+		utils.PRUNE_EXPORT = pruneExport
+	}()
+
+	err := call()
+	// The calls cancelled with the context are reported as failed calls by the resource packages.
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
+}
+
+// Run an export or import of a resource type, and report the resources that failed as an error.
+func (client *Client) runOperation(ctx context.Context, resourceType string, operation func()) error {
+
+	return client.run(ctx, func() error {
+		failedBefore := utils.SummaryData.FailedOperations
+		operation()
+		if failed := utils.SummaryData.FailedOperations - failedBefore; failed > 0 {
+			return fmt.Errorf("%d %s failed. See the logs for the details", failed, resourceType)
+		}
+		return nil
+	})
+}
+
+// Import a single resource file from the folder of its resource type.
+func (client *Client) importFile(ctx context.Context, resourceType string, filePath string, options ImportOptions,
+	importAll func(string)) error {
+
+	resourceTypeDir := filepath.Dir(filePath)
+	if filepath.Base(resourceTypeDir) != resourceType {
+		return fmt.Errorf("the file should be inside the %s folder: %s", resourceType, filePath)
+	}
+	return client.runOperation(ctx, resourceType, func() {
+		utils.INCLUDE_ONLY = []string{utils.ResolveResourceName(filePath)}
+		utils.FORCE_IMPORT = options.Force
+		utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES = options.IncludeSystemApps, options.AssumeYes
+		importAll(filepath.Dir(resourceTypeDir))
+	})
+}
+
+func getExportFormat(options ExportOptions) string {
+
+	if options.Format == "" {
+		return "yaml"
+	}
+	return options.Format
+}
@@ -0,0 +1,66 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package client
+
+import (
+	"context"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// List the identity providers, with the given federation protocol if the protocol is not empty.
+func (client *Client) ListIdps(ctx context.Context, protocol string) ([]identityproviders.IdpListItem, error) {
+
+	var idps []identityproviders.IdpListItem
+	err := client.run(ctx, func() error {
+		var err error
+		idps, err = identityproviders.ListIdps(protocol)
+		return err
+	})
+	return idps, err
+}
+
+// Get the YAML content of an identity provider. The secrets are masked unless showSecrets is set, in which case the
+// access is recorded in the audit log.
+func (client *Client) GetIdp(ctx context.Context, idpName string, showSecrets bool) ([]byte, error) {
+
+	var content []byte
+	err := client.run(ctx, func() error {
+		var err error
+		content, err = identityproviders.Get(idpName, showSecrets)
+		return err
+	})
+	return content, err
+}
+
+// Export the identity providers to the IdentityProviders folder of the output directory.
+func (client *Client) ExportIdps(ctx context.Context, outputDirPath string, options ExportOptions) error {
+
+	return client.runOperation(ctx, utils.IDENTITY_PROVIDERS, func() {
+		utils.PRUNE_EXPORT = options.Prune
+		identityproviders.ExportAll(outputDirPath, getExportFormat(options))
+	})
+}
+
+// Import an identity provider file from the IdentityProviders folder of a local directory.
+func (client *Client) ImportIdp(ctx context.Context, filePath string, options ImportOptions) error {
+
+	return client.importFile(ctx, utils.IDENTITY_PROVIDERS, filePath, options, identityproviders.ImportAll)
+}
@@ -0,0 +1,123 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	apiresources "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/apiResources"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/branding"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/claims"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/cors"
+	emailtemplates "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/emailTemplates"
+	governanceconnectors "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/governanceConnectors"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	notificationsenders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/notificationSenders"
+	oidcscopes "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/oidcScopes"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/organizations"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/users"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/workflows"
+	xacmlpolicies "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/xacmlPolicies"
+)
+
+var exporters = map[string]func(outputDirPath string, format string){
+	utils.CLAIMS:                claims.ExportAll,
+	utils.OIDC_SCOPES:           func(outputDirPath string, format string) { oidcscopes.ExportAll(outputDirPath) },
+	utils.IDENTITY_PROVIDERS:    identityproviders.ExportAll,
+	utils.API_RESOURCES:         func(outputDirPath string, format string) { apiresources.ExportAll(outputDirPath) },
+	utils.ROLES:                 func(outputDirPath string, format string) { roles.ExportAll(outputDirPath) },
+	utils.APPLICATIONS:          applications.ExportAll,
+	utils.ORGANIZATIONS:         func(outputDirPath string, format string) { organizations.ExportAll(outputDirPath) },
+	utils.USERSTORES:            userstores.ExportAll,
+	utils.EMAIL_TEMPLATES:       emailtemplates.ExportAll,
+	utils.BRANDING:              branding.ExportAll,
+	utils.GOVERNANCE_CONNECTORS: func(outputDirPath string, format string) { governanceconnectors.ExportAll(outputDirPath) },
+	utils.CORS:                  func(outputDirPath string, format string) { cors.ExportAll(outputDirPath) },
+	utils.NOTIFICATION_SENDERS:  func(outputDirPath string, format string) { notificationsenders.ExportAll(outputDirPath) },
+	utils.WORKFLOWS:             func(outputDirPath string, format string) { workflows.ExportAll(outputDirPath) },
+}
+
+var importers = map[string]func(string){
+	utils.CLAIMS:                claims.ImportAll,
+	utils.OIDC_SCOPES:           oidcscopes.ImportAll,
+	utils.IDENTITY_PROVIDERS:    identityproviders.ImportAll,
+	utils.API_RESOURCES:         apiresources.ImportAll,
+	utils.ROLES:                 roles.ImportAll,
+	utils.APPLICATIONS:          applications.ImportAll,
+	utils.ORGANIZATIONS:         organizations.ImportAll,
+	utils.USERSTORES:            userstores.ImportAll,
+	utils.EMAIL_TEMPLATES:       emailtemplates.ImportAll,
+	utils.BRANDING:              branding.ImportAll,
+	utils.GOVERNANCE_CONNECTORS: governanceconnectors.ImportAll,
+	utils.CORS:                  cors.ImportAll,
+	utils.NOTIFICATION_SENDERS:  notificationsenders.ImportAll,
+	utils.USERS:                 users.ImportAll,
+	utils.WORKFLOWS:             workflows.ImportAll,
+	utils.XACML_POLICIES:        xacmlpolicies.ImportAll,
+}
+
+// Export the resources of a resource type to the folder of the resource type in the output directory. Users and
+// XACML policies are not exported with this method, since they are exported only on demand by the tool.
+func (client *Client) ExportResources(ctx context.Context, resourceType string, outputDirPath string,
+	options ExportOptions) error {
+
+	exportAll, ok := exporters[resourceType]
+	if !ok {
+		return fmt.Errorf("resource type: %s cannot be exported", resourceType)
+	}
+	return client.runOperation(ctx, resourceType, func() {
+		utils.PRUNE_EXPORT = options.Prune
+		exportAll(outputDirPath, getExportFormat(options))
+		if resourceType == utils.APPLICATIONS && options.OpenApiSpecs {
+			applications.ExportOpenApiSpecs(outputDirPath)
+		}
+	})
+}
+
+// Import the resources of a resource type from the folder of the resource type in the input directory. The roles
+// with an application audience, which are imported before the applications, are imported after the applications.
+func (client *Client) ImportResources(ctx context.Context, resourceType string, inputDirPath string,
+	options ImportOptions) error {
+
+	importAll, ok := importers[resourceType]
+	if !ok {
+		return fmt.Errorf("resource type: %s cannot be imported", resourceType)
+	}
+	return client.runOperation(ctx, resourceType, func() {
+		utils.INCLUDE_ONLY = options.IncludeOnly
+		utils.FORCE_IMPORT = options.Force
+		utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES = options.IncludeSystemApps, options.AssumeYes
+		importAll(inputDirPath)
+		if resourceType == utils.APPLICATIONS {
+			roles.ImportPendingRoles()
+		}
+	})
+}
+
+// Check whether the resources of the resource type can be imported with ImportResources.
+func IsImportSupported(resourceType string) bool {
+
+	_, ok := importers[resourceType]
+	return ok
+}
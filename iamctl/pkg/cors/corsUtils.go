@@ -0,0 +1,104 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// The allowed origins of the tenant are kept in a single file.
+const CORS_FILE_NAME = "cors"
+
+type corsOrigin struct {
+	Id  string `json:"id,omitempty"`
+	Url string `json:"url"`
+}
+
+type corsConfig struct {
+	Origins []string `yaml:"origins"`
+}
+
+func getCorsOriginList() ([]corsOrigin, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.CORS, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving CORS origin list. %w", err)
+	}
+	var origins []corsOrigin
+	err = json.Unmarshal(body, &origins)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved CORS origin list. %w", err)
+	}
+	return origins, nil
+}
+
+// Origins are compared without the trailing slash and case insensitively, since the scheme and host are case insensitive.
+func normalizeOrigin(origin string) string {
+
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+}
+
+func validateOrigins(origins []string, fileData string) error {
+
+	for _, origin := range origins {
+		parsedOrigin, err := url.Parse(origin)
+		isValid := err == nil && (parsedOrigin.Scheme == "http" || parsedOrigin.Scheme == "https") &&
+			parsedOrigin.Host != "" && (parsedOrigin.Path == "" || parsedOrigin.Path == "/") &&
+			parsedOrigin.RawQuery == "" && parsedOrigin.Fragment == ""
+		if !isValid {
+			return fmt.Errorf("invalid origin: %s at line %d. An origin should be an http or https URL without a path",
+				origin, getOriginLine(fileData, origin))
+		}
+	}
+	return nil
+}
+
+// Get the line number of the origin in the file, so that the offending line is reported when an origin is invalid.
+func getOriginLine(fileData string, origin string) int {
+
+	for i, line := range strings.Split(fileData, "\n") {
+		value := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "-"))
+		if strings.Trim(value, `'"`) == origin {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+func getSortedOrigins(origins []corsOrigin) []string {
+
+	urls := []string{}
+	for _, origin := range origins {
+		urls = append(urls, origin.Url)
+	}
+	sort.Strings(urls)
+	return urls
+}
+
+func getCorsKeywordMapping() map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(CORS_FILE_NAME, utils.KEYWORD_CONFIGS.CorsConfigs)
+}
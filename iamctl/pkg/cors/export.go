@@ -0,0 +1,77 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cors
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export the allowed CORS origins of the tenant to the cors.yml file in the Cors folder.
+	log.Println("Exporting CORS origins...")
+	exportFilePath = filepath.Join(exportFilePath, utils.CORS)
+
+	if utils.IsResourceTypeExcluded(utils.CORS) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.CORS)
+	if !utils.IsTimeBudgetAvailable() {
+		utils.AddUnprocessedResourceToSummary(utils.CORS, CORS_FILE_NAME)
+		return
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	}
+
+	utils.EmitResourceStarted(utils.CORS, CORS_FILE_NAME, utils.EXPORT)
+	err := exportCorsOrigins(exportFilePath)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.CORS, CORS_FILE_NAME)
+		log.Printf("Error while exporting CORS origins. %s", err)
+	} else {
+		utils.UpdateSuccessSummary(utils.CORS, CORS_FILE_NAME, utils.EXPORT)
+		log.Println("CORS origins exported successfully.")
+	}
+}
+
+func exportCorsOrigins(outputDirPath string) error {
+
+	origins, err := getCorsOriginList()
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(corsConfig{Origins: getSortedOrigins(origins)})
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, CORS_FILE_NAME+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getCorsKeywordMapping(), utils.CORS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,161 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package cors
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.CORS, CORS_FILE_NAME+".yml")
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.CORS) {
+		return
+	}
+	if !utils.IsResourceIncluded(CORS_FILE_NAME) {
+		utils.AddFilteredResourceToSummary(utils.CORS, CORS_FILE_NAME)
+		return
+	}
+	if !utils.IsTimeBudgetAvailable() {
+		utils.AddUnprocessedResourceToSummary(utils.CORS, CORS_FILE_NAME)
+		return
+	}
+
+	log.Println("Importing CORS origins...")
+	utils.EmitResourceStarted(utils.CORS, CORS_FILE_NAME, utils.IMPORT)
+	if err := importCorsOrigins(importFilePath); err != nil {
+		utils.UpdateFailureSummary(utils.CORS, CORS_FILE_NAME)
+		log.Printf("Error when importing CORS origins. %s", err)
+	}
+}
+
+func importCorsOrigins(importFilePath string) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for CORS origins: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getCorsKeywordMapping())
+	var localConfig corsConfig
+	err = yaml.Unmarshal([]byte(modifiedFileData), &localConfig)
+	if err != nil {
+		return fmt.Errorf("invalid file content for CORS origins: %s", err)
+	}
+	if err := validateOrigins(localConfig.Origins, modifiedFileData); err != nil {
+		return err
+	}
+
+	utils.CheckImportContent(utils.CORS, CORS_FILE_NAME, modifiedFileData)
+	if utils.IsImportStateUnchanged(utils.CORS, CORS_FILE_NAME, modifiedFileData) {
+		log.Println("CORS origins are unchanged since the last import. Skipping update.")
+		utils.UpdateSuccessSummary(utils.CORS, CORS_FILE_NAME, utils.UNCHANGED)
+		return nil
+	}
+	deployedOrigins, err := getCorsOriginList()
+	if err != nil {
+		return err
+	}
+
+	// Only the differences are sent to the server, since the origins are added and removed individually.
+	localOrigins := make(map[string]bool)
+	for _, origin := range localConfig.Origins {
+		localOrigins[normalizeOrigin(origin)] = true
+	}
+	deployedOriginsByUrl := make(map[string]corsOrigin)
+	for _, origin := range deployedOrigins {
+		deployedOriginsByUrl[normalizeOrigin(origin.Url)] = origin
+	}
+	isUpdated := false
+	for _, origin := range localConfig.Origins {
+		if _, exists := deployedOriginsByUrl[normalizeOrigin(origin)]; exists {
+			continue
+		}
+		log.Println("Adding CORS origin: " + origin)
+		_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.CORS, "", corsOrigin{Url: origin})
+		if err != nil {
+			return fmt.Errorf("error when adding CORS origin: %s. %s", origin, err)
+		}
+		deployedOriginsByUrl[normalizeOrigin(origin)] = corsOrigin{Url: origin}
+		isUpdated = true
+	}
+
+	var originsToRemove []corsOrigin
+	for _, origin := range deployedOrigins {
+		if !localOrigins[normalizeOrigin(origin.Url)] {
+			originsToRemove = append(originsToRemove, origin)
+		}
+	}
+	isRemoved, err := removeDeletedDeployedOrigins(originsToRemove)
+	if err != nil {
+		return err
+	}
+
+	// The import state is not updated when origins are kept, so that they are removed once deleting is allowed.
+	if len(originsToRemove) == 0 || isRemoved {
+		utils.UpdateImportState(utils.CORS, CORS_FILE_NAME, modifiedFileData)
+	}
+	if !isUpdated && !isRemoved {
+		log.Println("CORS origins are unchanged. Skipping update.")
+		utils.UpdateSuccessSummary(utils.CORS, CORS_FILE_NAME, utils.UNCHANGED)
+		return nil
+	}
+	utils.UpdateSuccessSummary(utils.CORS, CORS_FILE_NAME, utils.UPDATE)
+	log.Println("CORS origins updated successfully.")
+	return nil
+}
+
+func removeDeletedDeployedOrigins(originsToRemove []corsOrigin) (bool, error) {
+
+	// Remove deployed origins that do not exist locally, only if deleting is allowed.
+	if len(originsToRemove) == 0 {
+		return false, nil
+	}
+	var originUrls []string
+	for _, origin := range originsToRemove {
+		originUrls = append(originUrls, origin.Url)
+	}
+	if !utils.IsDeleteAllowed() {
+		log.Printf("CORS origins not found locally are kept since deleting is not allowed: %v", originUrls)
+		return false, nil
+	}
+	if !utils.ConfirmDeletion(utils.CORS, originUrls) {
+		return false, nil
+	}
+	for _, origin := range originsToRemove {
+		log.Println("CORS origin not found locally. Removing origin: ", origin.Url)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), url.PathEscape(origin.Id), utils.CORS)
+		if err != nil {
+			return false, fmt.Errorf("error when removing CORS origin: %s. %s", origin.Url, err)
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,163 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package emailtemplates
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+type templateType struct {
+	Id          string `json:"id"`
+	DisplayName string `json:"displayName"`
+}
+
+type emailTemplateLocale struct {
+	Id string `json:"id"`
+}
+
+type EmailTemplate struct {
+	Id          string `json:"id" yaml:"id"`
+	ContentType string `json:"contentType" yaml:"contentType"`
+	Subject     string `json:"subject" yaml:"subject"`
+	Body        string `json:"body" yaml:"body"`
+	Footer      string `json:"footer,omitempty" yaml:"footer,omitempty"`
+}
+
+func getTemplateTypeList() ([]templateType, error) {
+
+	var list []templateType
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.EMAIL_TEMPLATES, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving email template type list. %w", err)
+	}
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved email template type list. %w", err)
+	}
+	return list, nil
+}
+
+func getTemplateLocales(templateTypeId string) ([]string, error) {
+
+	var list []emailTemplateLocale
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.EMAIL_TEMPLATES, getTemplatesPath(templateTypeId), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving email templates. %w", err)
+	}
+	err = json.Unmarshal(body, &list)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved email templates. %w", err)
+	}
+
+	var locales []string
+	for _, template := range list {
+		locales = append(locales, template.Id)
+	}
+	return locales, nil
+}
+
+func getTemplate(templateTypeId string, locale string) (EmailTemplate, error) {
+
+	var template EmailTemplate
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.EMAIL_TEMPLATES, getTemplatePath(templateTypeId, locale), nil)
+	if err != nil {
+		return template, fmt.Errorf("error while retrieving email template: %s. %w", locale, err)
+	}
+	err = json.Unmarshal(body, &template)
+	if err != nil {
+		return template, fmt.Errorf("error when unmarshalling the retrieved email template: %s. %w", locale, err)
+	}
+	return template, nil
+}
+
+func getTemplateTypeId(templateTypeName string) (string, error) {
+
+	templateTypes, err := getTemplateTypeList()
+	if err != nil {
+		return "", err
+	}
+	for _, templateType := range templateTypes {
+		if templateType.DisplayName == templateTypeName {
+			return templateType.Id, nil
+		}
+	}
+	return "", nil
+}
+
+func getEmailTemplateKeywordMapping(templateTypeName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(templateTypeName, utils.KEYWORD_CONFIGS.EmailTemplateConfigs)
+}
+
+func getTemplatesPath(templateTypeId string) string {
+
+	return url.PathEscape(templateTypeId) + "/templates"
+}
+
+func getTemplatePath(templateTypeId string, locale string) string {
+
+	return getTemplatesPath(templateTypeId) + "/" + url.PathEscape(locale)
+}
+
+func getSummaryName(templateTypeName string, locale string) string {
+
+	return templateTypeName + "/" + locale
+}
+
+// Get the local folders of the email template types and the local files of the email templates that do not exist
+// in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	templateTypes, err := getTemplateTypeList()
+	if err != nil {
+		return nil, err
+	}
+	importFilePath := filepath.Join(inputDirPath, utils.EMAIL_TEMPLATES)
+	var templateTypeNames []string
+	for _, templateType := range templateTypes {
+		templateTypeNames = append(templateTypeNames, templateType.DisplayName)
+	}
+	staleFilePaths, err := utils.GetStaleLocalFiles(importFilePath, templateTypeNames)
+	if err != nil {
+		return nil, err
+	}
+	for _, templateType := range templateTypes {
+		templateTypeDirPath := filepath.Join(importFilePath, templateType.DisplayName)
+		if _, err := os.Stat(templateTypeDirPath); os.IsNotExist(err) {
+			continue
+		}
+		locales, err := getTemplateLocales(templateType.Id)
+		if err != nil {
+			return nil, err
+		}
+		localeFilePaths, err := utils.GetStaleLocalFiles(templateTypeDirPath, locales)
+		if err != nil {
+			return nil, err
+		}
+		staleFilePaths = append(staleFilePaths, localeFilePaths...)
+	}
+	return staleFilePaths, nil
+}
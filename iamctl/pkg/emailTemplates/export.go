@@ -0,0 +1,117 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package emailtemplates
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string, format string) {
+
+	// Export each email template type to a folder with a file per locale in the EmailTemplates folder.
+	log.Println("Exporting email templates...")
+	exportFilePath = filepath.Join(exportFilePath, utils.EMAIL_TEMPLATES)
+
+	if utils.IsResourceTypeExcluded(utils.EMAIL_TEMPLATES) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.EMAIL_TEMPLATES)
+	if format != "yaml" {
+		log.Println("Email templates are exported in the YAML format only.")
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	}
+
+	templateTypes, err := getTemplateTypeList()
+	if err != nil {
+		log.Println("Error: when exporting email templates.", err)
+		return
+	}
+	sort.SliceStable(templateTypes, func(i, j int) bool {
+		return utils.GetResourcePriority(templateTypes[i].DisplayName) < utils.GetResourcePriority(templateTypes[j].DisplayName)
+	})
+	for _, templateType := range templateTypes {
+		if utils.IsResourceExcluded(templateType.DisplayName, utils.TOOL_CONFIGS.EmailTemplateConfigs) {
+			utils.ReportSkippedResource(utils.EMAIL_TEMPLATES, templateType.DisplayName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.EMAIL_TEMPLATES, templateType.DisplayName)
+			continue
+		}
+		log.Println("Exporting email template type: ", templateType.DisplayName)
+		exportTemplateType(templateType, filepath.Join(exportFilePath, templateType.DisplayName))
+	}
+}
+
+func exportTemplateType(templateType templateType, outputDirPath string) {
+
+	locales, err := getTemplateLocales(templateType.Id)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, templateType.DisplayName)
+		log.Printf("Error while exporting email template type: %s. %s", templateType.DisplayName, err)
+		return
+	}
+	if _, err := os.Stat(outputDirPath); os.IsNotExist(err) {
+		os.MkdirAll(outputDirPath, 0700)
+	} else if utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.EmailTemplateConfigs) {
+		utils.RemoveDeletedLocalResources(outputDirPath, locales)
+	}
+
+	for _, locale := range locales {
+		summaryName := getSummaryName(templateType.DisplayName, locale)
+		utils.EmitResourceStarted(utils.EMAIL_TEMPLATES, summaryName, utils.EXPORT)
+		err := exportTemplate(templateType, locale, outputDirPath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, summaryName)
+			log.Printf("Error while exporting email template: %s. %s", summaryName, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.EXPORT)
+			log.Println("Email template exported successfully: ", summaryName)
+		}
+	}
+}
+
+func exportTemplate(templateType templateType, locale string, outputDirPath string) error {
+
+	template, err := getTemplate(templateType.Id, locale)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(template)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, locale+".yml")
+	keywordMapping := getEmailTemplateKeywordMapping(templateType.DisplayName)
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, keywordMapping, utils.EMAIL_TEMPLATES)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,239 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package emailtemplates
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	log.Println("Importing email templates...")
+	importFilePath := filepath.Join(inputDirPath, utils.EMAIL_TEMPLATES)
+
+	if utils.IsResourceTypeExcluded(utils.EMAIL_TEMPLATES) {
+		return
+	}
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		log.Println("No email templates to import.")
+		return
+	}
+	dirs, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing email templates: ", err)
+		return
+	}
+
+	sort.SliceStable(dirs, func(i, j int) bool {
+		return utils.GetResourcePriority(dirs[i].Name()) < utils.GetResourcePriority(dirs[j].Name())
+	})
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		templateTypeName := dir.Name()
+		if !utils.IsResourceIncluded(templateTypeName) {
+			utils.AddFilteredResourceToSummary(utils.EMAIL_TEMPLATES, templateTypeName)
+			continue
+		}
+		if utils.IsResourceExcluded(templateTypeName, utils.TOOL_CONFIGS.EmailTemplateConfigs) {
+			utils.ReportSkippedResource(utils.EMAIL_TEMPLATES, templateTypeName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.EMAIL_TEMPLATES, templateTypeName)
+			continue
+		}
+		importTemplateType(templateTypeName, filepath.Join(importFilePath, templateTypeName))
+	}
+}
+
+func importTemplateType(templateTypeName string, templateTypeDirPath string) {
+
+	log.Println("Importing email template type: ", templateTypeName)
+	files, err := ioutil.ReadDir(templateTypeDirPath)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, templateTypeName)
+		log.Printf("Error when reading the email templates of type: %s. %s", templateTypeName, err)
+		return
+	}
+
+	templateTypeId, err := getTemplateTypeId(templateTypeName)
+	if err == nil && templateTypeId == "" {
+		templateTypeId, err = createTemplateType(templateTypeName)
+	}
+	if err != nil {
+		utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, templateTypeName)
+		log.Printf("Error when resolving the email template type: %s. %s", templateTypeName, err)
+		return
+	}
+	deployedLocales, err := getTemplateLocales(templateTypeId)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, templateTypeName)
+		log.Printf("Error when retrieving the email templates of type: %s. %s", templateTypeName, err)
+		return
+	}
+	if utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.EmailTemplateConfigs) {
+		removeDeletedDeployedLocales(templateTypeName, templateTypeId, deployedLocales, files)
+	}
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		locale := utils.GetFileInfo(file.Name()).ResourceName
+		summaryName := getSummaryName(templateTypeName, locale)
+		utils.EmitResourceStarted(utils.EMAIL_TEMPLATES, summaryName, utils.IMPORT)
+		err := importTemplate(templateTypeName, templateTypeId, filepath.Join(templateTypeDirPath, file.Name()),
+			utils.Contains(deployedLocales, locale))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, summaryName)
+			log.Printf("Error when importing email template: %s. %s", summaryName, err)
+		}
+	}
+}
+
+func importTemplate(templateTypeName string, templateTypeId string, importFilePath string, isUpdate bool) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for email template: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	locale := utils.GetFileInfo(importFilePath).ResourceName
+	summaryName := getSummaryName(templateTypeName, locale)
+	keywordMapping := getEmailTemplateKeywordMapping(templateTypeName)
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), keywordMapping)
+	utils.CheckImportContent(utils.EMAIL_TEMPLATES, summaryName, modifiedFileData)
+
+	var template EmailTemplate
+	err = yaml.Unmarshal([]byte(modifiedFileData), &template)
+	if err != nil {
+		return fmt.Errorf("invalid file content for email template: %s", err)
+	}
+	if template.Id != locale {
+		utils.LogWarning(utils.WARNING_NAME_MISMATCH, "Locale in the file "+importFilePath+" is not matching with the file name.")
+		template.Id = locale
+	}
+
+	if !isUpdate {
+		log.Println("Creating new email template: " + summaryName)
+		_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.EMAIL_TEMPLATES, getTemplatesPath(templateTypeId), template)
+		if err != nil {
+			return fmt.Errorf("error when creating email template: %s", err)
+		}
+		utils.UpdateImportState(utils.EMAIL_TEMPLATES, summaryName, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.IMPORT)
+		log.Println("Email template created successfully.")
+		return nil
+	}
+
+	if utils.IsImportStateUnchanged(utils.EMAIL_TEMPLATES, summaryName, modifiedFileData) {
+		log.Println("Email template is unchanged since the last import. Skipping update: " + summaryName)
+		utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.UNCHANGED)
+		return nil
+	}
+	if !utils.FORCE_IMPORT {
+		deployedTemplate, err := getTemplate(templateTypeId, locale)
+		if err == nil && deployedTemplate == template {
+			log.Println("Email template is unchanged. Skipping update: " + summaryName)
+			utils.UpdateImportState(utils.EMAIL_TEMPLATES, summaryName, modifiedFileData)
+			utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.UNCHANGED)
+			return nil
+		}
+	}
+
+	log.Println("Updating email template: " + summaryName)
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.EMAIL_TEMPLATES, getTemplatePath(templateTypeId, locale), template)
+	if err != nil {
+		return fmt.Errorf("error when updating email template: %s", err)
+	}
+	utils.UpdateImportState(utils.EMAIL_TEMPLATES, summaryName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.UPDATE)
+	log.Println("Email template updated successfully.")
+	return nil
+}
+
+func createTemplateType(templateTypeName string) (string, error) {
+
+	log.Println("Creating new email template type: " + templateTypeName)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.EMAIL_TEMPLATES, "", templateType{DisplayName: templateTypeName})
+	if err != nil {
+		return "", fmt.Errorf("error when creating email template type: %s", err)
+	}
+	templateTypeId, err := getTemplateTypeId(templateTypeName)
+	if err != nil {
+		return "", err
+	}
+	if templateTypeId == "" {
+		return "", fmt.Errorf("email template type is not available after creation")
+	}
+	return templateTypeId, nil
+}
+
+func removeDeletedDeployedLocales(templateTypeName string, templateTypeId string, deployedLocales []string, localFiles []os.FileInfo) {
+
+	// Remove deployed templates of the locales that do not exist locally.
+	var localesToDelete []string
+deployedResources:
+	for _, locale := range deployedLocales {
+		for _, file := range localFiles {
+			if locale == utils.GetFileInfo(file.Name()).ResourceName {
+				continue deployedResources
+			}
+		}
+		// The templates of the default locale belong to the default template set and are never deleted.
+		if locale == utils.DEFAULT_EMAIL_TEMPLATE_LOCALE {
+			log.Printf("Email template: %s is the default template and is excluded from deletion.\n",
+				getSummaryName(templateTypeName, locale))
+			continue
+		}
+		localesToDelete = append(localesToDelete, locale)
+	}
+
+	var templateNames []string
+	for _, locale := range localesToDelete {
+		templateNames = append(templateNames, getSummaryName(templateTypeName, locale))
+	}
+	if !utils.ConfirmDeletion(utils.EMAIL_TEMPLATES, templateNames) {
+		return
+	}
+	for _, locale := range localesToDelete {
+		summaryName := getSummaryName(templateTypeName, locale)
+		log.Println("Email template not found locally. Deleting email template: ", summaryName)
+		utils.EmitResourceStarted(utils.EMAIL_TEMPLATES, summaryName, utils.DELETE)
+		_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodDelete, utils.EMAIL_TEMPLATES, getTemplatePath(templateTypeId, locale), nil)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.EMAIL_TEMPLATES, summaryName)
+			log.Println("Error deleting email template: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.EMAIL_TEMPLATES, summaryName, utils.DELETE)
+	}
+}
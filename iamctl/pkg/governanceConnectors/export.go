@@ -0,0 +1,103 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package governanceconnectors
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export the connectors of each governance connector category to a separate file in the GovernanceConnectors folder.
+	log.Println("Exporting governance connectors...")
+	exportFilePath = filepath.Join(exportFilePath, utils.GOVERNANCE_CONNECTORS)
+
+	if utils.IsResourceTypeExcluded(utils.GOVERNANCE_CONNECTORS) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.GOVERNANCE_CONNECTORS)
+	categories, err := getCategoryList()
+	if err != nil {
+		log.Println("Error while retrieving governance connector categories.", err)
+		return
+	}
+
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		var categoryFileNames []string
+		for _, category := range categories {
+			categoryFileNames = append(categoryFileNames, getCategoryFileName(category.Name))
+		}
+		utils.RemoveDeletedLocalResources(exportFilePath, categoryFileNames)
+	}
+
+	sort.SliceStable(categories, func(i, j int) bool {
+		return utils.GetResourcePriority(categories[i].Name) < utils.GetResourcePriority(categories[j].Name)
+	})
+	utils.StartResourceProgress(utils.GOVERNANCE_CONNECTORS, len(categories))
+	for _, category := range categories {
+		if utils.IsResourceExcluded(category.Name, utils.TOOL_CONFIGS.GovernanceConnectorConfigs) {
+			utils.ReportSkippedResource(utils.GOVERNANCE_CONNECTORS, category.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.GOVERNANCE_CONNECTORS, category.Name)
+			continue
+		}
+		log.Println("Exporting governance connector category: ", category.Name)
+		utils.EmitResourceStarted(utils.GOVERNANCE_CONNECTORS, category.Name, utils.EXPORT)
+		err := exportCategory(category.Id, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.GOVERNANCE_CONNECTORS, category.Name)
+			log.Printf("Error while exporting governance connector category: %s. %s", category.Name, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.GOVERNANCE_CONNECTORS, category.Name, utils.EXPORT)
+			log.Println("Governance connector category exported successfully: ", category.Name)
+		}
+	}
+}
+
+func exportCategory(categoryId string, outputDirPath string) error {
+
+	// The category list does not contain the properties of the connectors.
+	category, err := getCategory(categoryId)
+	if err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(category)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	fileName := getCategoryFileName(category.Name)
+	exportedFileName := filepath.Join(outputDirPath, fileName+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content,
+		getGovernanceConnectorKeywordMapping(fileName), utils.GOVERNANCE_CONNECTORS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,133 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package governanceconnectors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+type property struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type connector struct {
+	Id           string     `json:"id" yaml:"id"`
+	Name         string     `json:"name" yaml:"name"`
+	FriendlyName string     `json:"friendlyName,omitempty" yaml:"friendlyName,omitempty"`
+	Properties   []property `json:"properties" yaml:"properties"`
+}
+
+type category struct {
+	Id         string      `json:"id" yaml:"id"`
+	Name       string      `json:"name" yaml:"name"`
+	Connectors []connector `json:"connectors" yaml:"connectors"`
+}
+
+type connectorPatch struct {
+	Operation  string     `json:"operation"`
+	Properties []property `json:"properties"`
+}
+
+func getCategoryList() ([]category, error) {
+
+	// The category list contains the ids of the connectors of each category without the properties.
+	var categories []category
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.GOVERNANCE_CONNECTORS, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving governance connector categories. %w", err)
+	}
+	err = json.Unmarshal(body, &categories)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved governance connector categories. %w", err)
+	}
+	return categories, nil
+}
+
+func getCategory(categoryId string) (category, error) {
+
+	var categoryDetails category
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.GOVERNANCE_CONNECTORS, url.PathEscape(categoryId), nil)
+	if err != nil {
+		return categoryDetails, fmt.Errorf("error while retrieving the governance connector category. %w", err)
+	}
+	err = json.Unmarshal(body, &categoryDetails)
+	if err != nil {
+		return categoryDetails, fmt.Errorf("error when unmarshalling the retrieved governance connector category. %w", err)
+	}
+	return categoryDetails, nil
+}
+
+func updateConnectorProperties(categoryId string, connectorId string, properties []property) error {
+
+	path := url.PathEscape(categoryId) + "/connectors/" + url.PathEscape(connectorId)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.GOVERNANCE_CONNECTORS, path,
+		connectorPatch{Operation: "UPDATE", Properties: properties})
+	return err
+}
+
+func getChangedProperties(localProperties []property, deployedProperties []property) (changed []property, unknown []string) {
+
+	// Properties that are not in the local file are not returned, so that they are kept unchanged in the server.
+	deployedValues := make(map[string]string)
+	for _, deployedProperty := range deployedProperties {
+		deployedValues[deployedProperty.Name] = deployedProperty.Value
+	}
+	for _, localProperty := range localProperties {
+		deployedValue, ok := deployedValues[localProperty.Name]
+		if !ok {
+			unknown = append(unknown, localProperty.Name)
+			continue
+		}
+		if utils.FORCE_IMPORT || deployedValue != localProperty.Value {
+			changed = append(changed, localProperty)
+		}
+	}
+	return changed, unknown
+}
+
+func getCategoryFileName(categoryName string) string {
+
+	return strings.ReplaceAll(categoryName, "/", "_")
+}
+
+func getGovernanceConnectorKeywordMapping(categoryFileName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(categoryFileName, utils.KEYWORD_CONFIGS.GovernanceConnectorConfigs)
+}
+
+// Get the local files of the governance connector categories that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	categories, err := getCategoryList()
+	if err != nil {
+		return nil, err
+	}
+	var categoryFileNames []string
+	for _, category := range categories {
+		categoryFileNames = append(categoryFileNames, getCategoryFileName(category.Name))
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.GOVERNANCE_CONNECTORS), categoryFileNames)
+}
@@ -0,0 +1,181 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package governanceconnectors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.GOVERNANCE_CONNECTORS)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.GOVERNANCE_CONNECTORS) {
+		return
+	}
+
+	log.Println("Importing governance connectors...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing governance connectors: ", err)
+		return
+	}
+	categories, err := getCategoryList()
+	if err != nil {
+		log.Println("Error importing governance connectors: ", err)
+		return
+	}
+	deployedCategories := make(map[string]bool)
+	for _, category := range categories {
+		deployedCategories[category.Id] = true
+	}
+
+	// Governance connectors are configurations of the server, so they are only updated and never created or deleted.
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+	utils.StartResourceProgress(utils.GOVERNANCE_CONNECTORS, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		if !utils.IsResourceIncluded(fileName) {
+			utils.AddFilteredResourceToSummary(utils.GOVERNANCE_CONNECTORS, fileName)
+			continue
+		}
+		if utils.IsResourceExcluded(fileName, utils.TOOL_CONFIGS.GovernanceConnectorConfigs) {
+			utils.ReportSkippedResource(utils.GOVERNANCE_CONNECTORS, fileName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.GOVERNANCE_CONNECTORS, fileName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.GOVERNANCE_CONNECTORS, fileName, utils.IMPORT)
+		err := importCategory(filepath.Join(importFilePath, file.Name()), deployedCategories)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.GOVERNANCE_CONNECTORS, fileName)
+			log.Printf("Error when importing governance connector category: %s. %s", fileName, err)
+		}
+	}
+}
+
+func importCategory(importFilePath string, deployedCategories map[string]bool) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for governance connector category: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getGovernanceConnectorKeywordMapping(fileName))
+	var localCategory category
+	err = yaml.Unmarshal([]byte(modifiedFileData), &localCategory)
+	if err != nil {
+		return fmt.Errorf("invalid file content for governance connector category: %s", err)
+	}
+	if localCategory.Id == "" {
+		return fmt.Errorf("the id attribute is required")
+	}
+
+	utils.CheckImportContent(utils.GOVERNANCE_CONNECTORS, fileName, modifiedFileData)
+	if !deployedCategories[localCategory.Id] {
+		var connectorIds []string
+		for _, connector := range localCategory.Connectors {
+			connectorIds = append(connectorIds, connector.Id)
+		}
+		logUnsupportedConnectors(fileName, connectorIds)
+		return nil
+	}
+	if utils.IsImportStateUnchanged(utils.GOVERNANCE_CONNECTORS, fileName, modifiedFileData) {
+		log.Println("Governance connector category is unchanged since the last import. Skipping update: " + fileName)
+		utils.UpdateSuccessSummary(utils.GOVERNANCE_CONNECTORS, fileName, utils.UNCHANGED)
+		return nil
+	}
+
+	deployedCategory, err := getCategory(localCategory.Id)
+	if err != nil {
+		return err
+	}
+	deployedConnectors := make(map[string]connector)
+	for _, connector := range deployedCategory.Connectors {
+		deployedConnectors[connector.Id] = connector
+	}
+
+	// Connectors that are not available in the target server version are skipped instead of failing the category.
+	var unsupportedConnectorIds []string
+	isUpdated := false
+	for _, localConnector := range localCategory.Connectors {
+		deployedConnector, ok := deployedConnectors[localConnector.Id]
+		if !ok {
+			unsupportedConnectorIds = append(unsupportedConnectorIds, localConnector.Id)
+			continue
+		}
+		changedProperties, unknownProperties := getChangedProperties(localConnector.Properties, deployedConnector.Properties)
+		if len(unknownProperties) > 0 {
+			log.Printf("Properties of governance connector: %s that are not available in the target environment "+
+				"are skipped: %s", localConnector.Name, strings.Join(unknownProperties, ", "))
+		}
+		if len(changedProperties) == 0 {
+			continue
+		}
+		log.Println("Updating governance connector: " + localConnector.Name)
+		err := updateConnectorProperties(localCategory.Id, localConnector.Id, changedProperties)
+		if err != nil {
+			return fmt.Errorf("error when updating governance connector: %s. %s", localConnector.Name, err)
+		}
+		isUpdated = true
+	}
+	logUnsupportedConnectors(fileName, unsupportedConnectorIds)
+
+	// The import state is not updated when connectors are skipped, so that they are imported after the server is upgraded.
+	if len(unsupportedConnectorIds) == 0 {
+		utils.UpdateImportState(utils.GOVERNANCE_CONNECTORS, fileName, modifiedFileData)
+	}
+	if !isUpdated {
+		log.Println("Governance connector category is unchanged. Skipping update: " + fileName)
+		utils.UpdateSuccessSummary(utils.GOVERNANCE_CONNECTORS, fileName, utils.UNCHANGED)
+		return nil
+	}
+	utils.UpdateSuccessSummary(utils.GOVERNANCE_CONNECTORS, fileName, utils.UPDATE)
+	log.Println("Governance connector category updated successfully: " + fileName)
+	return nil
+}
+
+func logUnsupportedConnectors(categoryName string, connectorIds []string) {
+
+	if len(connectorIds) == 0 {
+		return
+	}
+	utils.LogWarning(utils.WARNING_UNSUPPORTED_CONNECTOR, fmt.Sprintf("%s: %s: connectors that are not available in "+
+		"the target environment are skipped: %s", utils.GOVERNANCE_CONNECTORS, categoryName, strings.Join(connectorIds, ", ")))
+}
@@ -0,0 +1,96 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package identityproviders
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func Adopt(idpName string, outputDirPath string, format string, dryRun bool) error {
+
+	// Export a single identity provider that is not yet managed locally to the IdentityProviders folder.
+	exportFilePath := filepath.Join(outputDirPath, utils.IDENTITY_PROVIDERS)
+
+	idps, err := getIdpList()
+	if err != nil {
+		return fmt.Errorf("error when retrieving the deployed identity providers: %s", err)
+	}
+	var idpId string
+	for _, idp := range idps {
+		if idp.Name == idpName {
+			idpId = idp.Id
+			break
+		}
+	}
+	if idpId == "" {
+		return fmt.Errorf("identity provider: %s is not found in the target environment", idpName)
+	}
+
+	// The file name given to the identity provider is recorded, in case the name is not valid as a file name.
+	utils.StartFileNameMapping(exportFilePath)
+	excludeSecrets := utils.AreSecretsExcluded(utils.TOOL_CONFIGS.IdpConfigs)
+	exportedFileName, modifiedFile, err := getExportedIdpContent(idpId, exportFilePath, format, excludeSecrets)
+	if err != nil {
+		return err
+	}
+	if utils.ResourceFileExists(exportedFileName) {
+		return fmt.Errorf("a managed file already exists for identity provider: %s at %s. "+
+			"Use the exportAll command to update managed resources or remove the file to adopt the identity provider again",
+			idpName, exportedFileName)
+	}
+
+	// Replace environment specific values with the keywords defined in the keyword configs.
+	idpKeywordMapping := getIdpKeywordMapping(idpName)
+	content, err := utils.AddKeywordPlaceholders(string(modifiedFile), idpKeywordMapping)
+	if err != nil {
+		return fmt.Errorf("error when adding the keyword placeholders: %s", err)
+	}
+	modifiedFile = []byte(content)
+
+	if dryRun {
+		fmt.Printf("Identity provider: %s will be adopted to %s with the following content:\n", idpName, exportedFileName)
+		fmt.Println(string(modifiedFile))
+		return nil
+	}
+
+	os.MkdirAll(exportFilePath, 0700)
+	if err := utils.WriteExportedFile(exportedFileName, modifiedFile); err != nil {
+		return fmt.Errorf("error when writing the adopted identity provider to file: %w", err)
+	}
+	if err := utils.WriteFileNameMapping(exportFilePath); err != nil {
+		return err
+	}
+
+	// Record the adopted identity provider in the import state, so that the next import does not update the
+	// identity provider unless the file is changed.
+	utils.LoadImportState(outputDirPath)
+	utils.UpdateImportState(utils.IDENTITY_PROVIDERS, idpName, utils.ReplaceKeywords(content, idpKeywordMapping))
+	utils.MarkResourceAdopted(utils.IDENTITY_PROVIDERS, idpName)
+	if err := utils.SaveImportState(); err != nil {
+		return fmt.Errorf("error when saving the import state: %s", err)
+	}
+	log.Println("Identity provider adopted successfully: ", idpName)
+	fmt.Println("Created file: " + exportedFileName)
+	return nil
+}
@@ -0,0 +1,374 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package identityproviders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Keys of the authenticator configurations in the identity provider files.
+const FEDERATED_AUTHENTICATOR_CONFIGS = "federatedAuthenticatorConfigs"
+const LOCAL_AUTHENTICATOR_CONFIGS = "localAuthenticatorConfigs"
+
+const LOCAL_AUTHENTICATOR_TYPE = "LOCAL"
+
+// Patterns of the property keys of the local authenticators with secrets, such as the API keys of the SMS gateways.
+// The properties of the federated authenticators are marked as confidential by the server.
+var sensitiveAuthenticatorProperties = []string{"*secret*", "*password*", "*apikey*", "*api_key*", "*api-key*", "*token*"}
+
+type LocalAuthenticatorConfig struct {
+	Name        string                  `yaml:"name" json:"name"`
+	DisplayName string                  `yaml:"displayName,omitempty" json:"displayName,omitempty"`
+	IsEnabled   bool                    `yaml:"isEnabled" json:"isEnabled"`
+	Properties  []AuthenticatorProperty `yaml:"properties,omitempty" json:"properties,omitempty"`
+}
+
+type AuthenticatorProperty struct {
+	Key   string `yaml:"key" json:"key"`
+	Value string `yaml:"value" json:"value"`
+}
+
+type authenticatorListItem struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// Add the configurations of the local authenticators to the resident identity provider file, since the local
+// authenticators are configured for the tenant and not for an identity provider.
+func addLocalAuthenticatorConfigs(fileContent []byte, excludeSecrets bool) ([]byte, error) {
+
+	authenticators, err := getLocalAuthenticators()
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		log.Println("Local authenticator configurations are not supported by the server. Skipping the local authenticators.")
+		return fileContent, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var configs []LocalAuthenticatorConfig
+	for _, authenticator := range authenticators {
+		config, err := getLocalAuthenticatorConfig(authenticator.Id)
+		if err != nil {
+			return nil, err
+		}
+		if excludeSecrets {
+			maskLocalAuthenticatorSecrets(&config)
+		}
+		configs = append(configs, config)
+	}
+	if len(configs) == 0 {
+		return fileContent, nil
+	}
+
+	var idpYaml yaml.MapSlice
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &idpYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the resident identity provider file: %s", err)
+	}
+	idpYaml = append(idpYaml, yaml.MapItem{Key: LOCAL_AUTHENTICATOR_CONFIGS, Value: configs})
+	modifiedContent, err := yaml.Marshal(idpYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when adding the local authenticator configurations: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+// Returns the resident identity provider file without the local authenticator configurations, and the configurations
+// to be set after the resident identity provider is updated. The configurations are nil if the file does not have them.
+func resolveLocalAuthenticatorConfigs(fileData string) (string, []LocalAuthenticatorConfig, error) {
+
+	var idpYaml yaml.MapSlice
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(fileData)), &idpYaml); err != nil {
+		return "", nil, fmt.Errorf("error when parsing the resident identity provider file: %s", err)
+	}
+	var configs []LocalAuthenticatorConfig
+	var found bool
+	var modifiedYaml yaml.MapSlice
+	for _, item := range idpYaml {
+		if item.Key != LOCAL_AUTHENTICATOR_CONFIGS {
+			modifiedYaml = append(modifiedYaml, item)
+			continue
+		}
+		found = true
+		configBytes, err := yaml.Marshal(item.Value)
+		if err == nil {
+			err = yaml.Unmarshal(configBytes, &configs)
+		}
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid local authenticator configurations: %s", err)
+		}
+	}
+	if !found {
+		return fileData, nil, nil
+	}
+	modifiedContent, err := yaml.Marshal(modifiedYaml)
+	if err != nil {
+		return "", nil, fmt.Errorf("error when removing the local authenticator configurations: %s", err)
+	}
+	return string(utils.AddTypeTags(modifiedContent)), configs, nil
+}
+
+// Update the local authenticators with configurations that are different from the deployed configurations. The
+// masked secrets keep the deployed values.
+func setLocalAuthenticatorConfigs(configs []LocalAuthenticatorConfig) error {
+
+	authenticators, err := getLocalAuthenticators()
+	if err != nil {
+		return err
+	}
+	authenticatorIds := make(map[string]string)
+	for _, authenticator := range authenticators {
+		authenticatorIds[authenticator.Name] = authenticator.Id
+	}
+	for _, config := range configs {
+		authenticatorId, ok := authenticatorIds[config.Name]
+		if !ok {
+			return fmt.Errorf("local authenticator: %s is not available in the target environment", config.Name)
+		}
+		deployedConfig, err := getLocalAuthenticatorConfig(authenticatorId)
+		if err != nil {
+			return err
+		}
+		config.DisplayName = deployedConfig.DisplayName
+		config.Properties = resolveMaskedProperties(config.Properties, deployedConfig.Properties)
+		if isSameLocalAuthenticatorConfig(config, deployedConfig) {
+			continue
+		}
+		log.Println("Updating local authenticator: " + config.Name)
+		if _, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.AUTHENTICATORS, authenticatorId, config); err != nil {
+			return fmt.Errorf("error when updating the local authenticator: %s. %s", config.Name, err)
+		}
+	}
+	return nil
+}
+
+func getLocalAuthenticators() ([]authenticatorListItem, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.AUTHENTICATORS, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the local authenticators: %w", err)
+	}
+	var authenticators []authenticatorListItem
+	if err := json.Unmarshal(body, &authenticators); err != nil {
+		return nil, fmt.Errorf("error when reading the local authenticators: %s", err)
+	}
+	var localAuthenticators []authenticatorListItem
+	for _, authenticator := range authenticators {
+		if authenticator.Type == LOCAL_AUTHENTICATOR_TYPE {
+			localAuthenticators = append(localAuthenticators, authenticator)
+		}
+	}
+	sort.SliceStable(localAuthenticators, func(i, j int) bool {
+		return localAuthenticators[i].Name < localAuthenticators[j].Name
+	})
+	return localAuthenticators, nil
+}
+
+func getLocalAuthenticatorConfig(authenticatorId string) (LocalAuthenticatorConfig, error) {
+
+	var config LocalAuthenticatorConfig
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.AUTHENTICATORS, authenticatorId, nil)
+	if err != nil {
+		return config, fmt.Errorf("error when retrieving the local authenticator configurations: %s", err)
+	}
+	if err := json.Unmarshal(body, &config); err != nil {
+		return config, fmt.Errorf("error when reading the local authenticator configurations: %s", err)
+	}
+	sort.SliceStable(config.Properties, func(i, j int) bool {
+		return config.Properties[i].Key < config.Properties[j].Key
+	})
+	return config, nil
+}
+
+func maskLocalAuthenticatorSecrets(config *LocalAuthenticatorConfig) {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	for i, property := range config.Properties {
+		if property.Value != "" && utils.MatchesAnyPattern(strings.ToLower(property.Key), sensitiveAuthenticatorProperties) {
+			config.Properties[i].Value = mask
+		}
+	}
+}
+
+func resolveMaskedProperties(properties []AuthenticatorProperty, deployedProperties []AuthenticatorProperty) []AuthenticatorProperty {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	deployedValues := make(map[string]string)
+	for _, property := range deployedProperties {
+		deployedValues[property.Key] = property.Value
+	}
+	resolvedProperties := make([]AuthenticatorProperty, 0, len(properties))
+	for _, property := range properties {
+		if property.Value == mask {
+			property.Value = deployedValues[property.Key]
+		}
+		resolvedProperties = append(resolvedProperties, property)
+	}
+	return resolvedProperties
+}
+
+func isSameLocalAuthenticatorConfig(config LocalAuthenticatorConfig, deployedConfig LocalAuthenticatorConfig) bool {
+
+	if config.IsEnabled != deployedConfig.IsEnabled {
+		return false
+	}
+	properties := append([]AuthenticatorProperty{}, config.Properties...)
+	sort.SliceStable(properties, func(i, j int) bool {
+		return properties[i].Key < properties[j].Key
+	})
+	return reflect.DeepEqual(properties, deployedConfig.Properties) ||
+		(len(properties) == 0 && len(deployedConfig.Properties) == 0)
+}
+
+// Mask the values of the confidential properties of the federated authenticators, such as the client secrets.
+func maskFederatedAuthenticatorSecrets(fileContent []byte) ([]byte, error) {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	return visitConfidentialProperties(fileContent, func(authenticatorName string, property yaml.MapSlice) interface{} {
+		return mask
+	})
+}
+
+// Replace the masked confidential properties of the federated authenticators with the deployed values. A new identity
+// provider cannot be created with masked confidential properties, since there are no deployed values to keep.
+func resolveFederatedAuthenticatorSecrets(idpId string, fileData string) (string, error) {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	var maskedProperties []string
+	_, err := visitConfidentialProperties([]byte(fileData), func(authenticatorName string, property yaml.MapSlice) interface{} {
+		if getMapValue(property, "value") == mask {
+			maskedProperties = append(maskedProperties, getPropertyKey(authenticatorName, property))
+		}
+		return getMapValue(property, "value")
+	})
+	if err != nil || len(maskedProperties) == 0 {
+		return fileData, err
+	}
+	if idpId == "" {
+		return "", fmt.Errorf("confidential properties of the federated authenticators are masked: %s. Set the values "+
+			"with keywords to create the identity provider", strings.Join(maskedProperties, ", "))
+	}
+
+	deployedValues, err := getDeployedConfidentialProperties(idpId)
+	if err != nil {
+		return "", err
+	}
+	resolvedContent, err := visitConfidentialProperties([]byte(fileData), func(authenticatorName string, property yaml.MapSlice) interface{} {
+		if getMapValue(property, "value") == mask {
+			return deployedValues[getPropertyKey(authenticatorName, property)]
+		}
+		return getMapValue(property, "value")
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(resolvedContent), nil
+}
+
+func getDeployedConfidentialProperties(idpId string) (map[string]interface{}, error) {
+
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), idpId, utils.MEDIA_TYPE_YAML, utils.IDENTITY_PROVIDERS, false)
+	if err != nil {
+		return nil, fmt.Errorf("error when retrieving the deployed identity provider: %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the deployed identity provider: %s", err)
+	}
+
+	deployedValues := make(map[string]interface{})
+	_, err = visitConfidentialProperties(body, func(authenticatorName string, property yaml.MapSlice) interface{} {
+		value := getMapValue(property, "value")
+		deployedValues[getPropertyKey(authenticatorName, property)] = value
+		return value
+	})
+	return deployedValues, err
+}
+
+// Visit the confidential properties of the federated authenticators, and set the values returned by the visitor.
+func visitConfidentialProperties(fileContent []byte, visit func(authenticatorName string, property yaml.MapSlice) interface{}) ([]byte, error) {
+
+	var idpYaml yaml.MapSlice
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(fileContent), &idpYaml); err != nil {
+		return nil, fmt.Errorf("error when parsing the identity provider file: %s", err)
+	}
+	authenticators, _ := getMapValue(idpYaml, FEDERATED_AUTHENTICATOR_CONFIGS).([]interface{})
+	if len(authenticators) == 0 {
+		return fileContent, nil
+	}
+	for _, authenticator := range authenticators {
+		authenticatorConfig, ok := authenticator.(yaml.MapSlice)
+		if !ok {
+			continue
+		}
+		authenticatorName := fmt.Sprint(getMapValue(authenticatorConfig, "name"))
+		properties, _ := getMapValue(authenticatorConfig, "properties").([]interface{})
+		for i, property := range properties {
+			propertyConfig, ok := property.(yaml.MapSlice)
+			if !ok || getMapValue(propertyConfig, "confidential") != true {
+				continue
+			}
+			properties[i] = setMapValue(propertyConfig, "value", visit(authenticatorName, propertyConfig))
+		}
+	}
+	modifiedContent, err := yaml.Marshal(idpYaml)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the identity provider file: %s", err)
+	}
+	return utils.AddTypeTags(modifiedContent), nil
+}
+
+func getMapValue(mapSlice yaml.MapSlice, key string) interface{} {
+
+	for _, item := range mapSlice {
+		if item.Key == key {
+			return item.Value
+		}
+	}
+	return nil
+}
+
+func setMapValue(mapSlice yaml.MapSlice, key string, value interface{}) yaml.MapSlice {
+
+	for i, item := range mapSlice {
+		if item.Key == key {
+			mapSlice[i].Value = value
+			return mapSlice
+		}
+	}
+	return append(mapSlice, yaml.MapItem{Key: key, Value: value})
+}
+
+// Key of a confidential property, qualified with the name of the federated authenticator. Ex: GoogleOIDCAuthenticator.ClientSecret
+func getPropertyKey(authenticatorName string, property yaml.MapSlice) string {
+
+	return authenticatorName + "." + fmt.Sprint(getMapValue(property, "name"))
+}
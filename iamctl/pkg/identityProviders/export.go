@@ -19,12 +19,15 @@
 package identityproviders
 
 import (
+	"context"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"mime"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 )
@@ -38,6 +41,7 @@ func ExportAll(exportFilePath string, format string) {
 	if utils.IsResourceTypeExcluded(utils.IDENTITY_PROVIDERS) {
 		return
 	}
+	utils.WarnUnfilteredSince(utils.IDENTITY_PROVIDERS)
 	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
 		os.MkdirAll(exportFilePath, 0700)
 	} else {
@@ -47,80 +51,202 @@ func ExportAll(exportFilePath string, format string) {
 		}
 	}
 
+	// The names of the identity providers are mapped to the file names that are valid on all platforms.
+	utils.StartFileNameMapping(exportFilePath)
+	defer func() {
+		if err := utils.WriteFileNameMapping(exportFilePath); err != nil {
+			log.Println("Error when writing the file names of the identity providers.", err)
+		}
+	}()
+
 	excludeSecerts := utils.AreSecretsExcluded(utils.TOOL_CONFIGS.IdpConfigs)
 	idps, err := getIdpList()
+	exportCompleted := err == nil
 	if err != nil {
 		log.Println("Error: when exporting identity providers.", err)
 	} else {
+		sort.SliceStable(idps, func(i, j int) bool {
+			return utils.GetResourcePriority(idps[i].Name) < utils.GetResourcePriority(idps[j].Name)
+		})
+		utils.StartResourceProgress(utils.IDENTITY_PROVIDERS, len(idps))
+		var idpsToExport []identityProvider
 		for _, idp := range idps {
 			if !utils.IsResourceExcluded(idp.Name, utils.TOOL_CONFIGS.IdpConfigs) {
-				log.Println("Exporting identity provider: ", idp.Name)
-
-				err := exportIdp(idp.Id, exportFilePath, format, excludeSecerts)
-				if err != nil {
-					utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, idp.Name)
-					log.Printf("Error while exporting identity providers: %s. %s", idp.Name, err)
-				} else {
-					utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, utils.EXPORT)
-					log.Println("Identity provider exported successfully: ", idp.Name)
-				}
+				idpsToExport = append(idpsToExport, idp)
+			} else {
+				utils.ReportSkippedResource(utils.IDENTITY_PROVIDERS, idp.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			}
+		}
+
+		// The identity providers are fetched in parallel, and written in order as the fetching completes.
+		pool := utils.StartFetchPool(len(idpsToExport), func(ctx context.Context, index int) (interface{}, error) {
+			return fetchIdp(ctx, idpsToExport[index].Id, format, excludeSecerts)
+		})
+		for i, idp := range idpsToExport {
+			if !utils.IsTimeBudgetAvailable() {
+				pool.Stop()
+				utils.AddUnprocessedResourceToSummary(utils.IDENTITY_PROVIDERS, idp.Name)
+				exportCompleted = false
+				continue
+			}
+			log.Println("Exporting identity provider: ", idp.Name)
+			utils.EmitResourceStarted(utils.IDENTITY_PROVIDERS, idp.Name, utils.EXPORT)
+
+			exported, err := pool.Get(i)
+			if err == nil {
+				err = writeExportedIdp(idp.Id, exported.(exportedIdp), exportFilePath, format, excludeSecerts)
+			}
+			if err != nil {
+				exportCompleted = false
+				utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, idp.Name)
+				log.Printf("Error while exporting identity providers: %s. %s", idp.Name, err)
+			} else {
+				utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, idp.Name, utils.EXPORT)
+				log.Println("Identity provider exported successfully: ", idp.Name)
 			}
 		}
+		pool.Stop()
 	}
 	if !utils.IsResourceExcluded(utils.RESIDENT_IDP_NAME, utils.TOOL_CONFIGS.IdpConfigs) {
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.IDENTITY_PROVIDERS, utils.RESIDENT_IDP_NAME)
+			return
+		}
 		log.Println("Exporting Resident identity provider")
 		err := exportIdp(utils.RESIDENT_IDP_NAME, exportFilePath, format, excludeSecerts)
 		if err != nil {
+			exportCompleted = false
 			log.Printf("Error while exporting resident identity provider: %s", err)
 		} else {
 			log.Println("Resident identity provider exported successfully")
 		}
+	} else {
+		utils.ReportSkippedResource(utils.IDENTITY_PROVIDERS, utils.RESIDENT_IDP_NAME, utils.JUNIT_SKIPPED_EXCLUDED)
+	}
+
+	// The files are only compared with the deployed identity providers if all identity providers are exported.
+	if !exportCompleted {
+		log.Println("Stale identity provider files are not checked, since the export did not complete successfully.")
+	} else {
+		idpNames := []string{utils.RESIDENT_IDP_NAME}
+		for _, idp := range idps {
+			idpNames = append(idpNames, idp.Name)
+		}
+		utils.HandleStaleExportedFiles(utils.IDENTITY_PROVIDERS, exportFilePath, IDP_NAME_KEY, idpNames,
+			utils.TOOL_CONFIGS.IdpConfigs)
 	}
 }
 
 func exportIdp(idpId string, outputDirPath string, format string, excludeSecrets bool) error {
 
-	var fileType string
-	// TODO: Extend support for json and xml formats.
-	switch format {
-	case "json":
-		fileType = utils.MEDIA_TYPE_JSON
-	case "xml":
-		fileType = utils.MEDIA_TYPE_XML
-	default:
-		fileType = utils.MEDIA_TYPE_YAML
+	exportedFileName, modifiedFile, err := getExportedIdpContent(idpId, outputDirPath, format, excludeSecrets)
+	if err != nil {
+		return err
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
+
+func writeExportedIdp(idpId string, exported exportedIdp, outputDirPath string, format string, excludeSecrets bool) error {
+
+	exportedFileName, modifiedFile, err := processExportedIdp(idpId, exported, outputDirPath, format, excludeSecrets)
+	if err != nil {
+		return err
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
+
+// Response of the export request of an identity provider, before the content is processed for the local files.
+type exportedIdp struct {
+	fileName string
+	body     []byte
+}
+
+func getExportedIdpContent(idpId string, outputDirPath string, format string, excludeSecrets bool) (string, []byte, error) {
+
+	exported, err := fetchIdp(utils.GetRequestContext(), idpId, format, excludeSecrets)
+	if err != nil {
+		return "", nil, err
 	}
+	return processExportedIdp(idpId, exported, outputDirPath, format, excludeSecrets)
+}
 
-	resp, err := utils.SendExportRequest(idpId, fileType, utils.IDENTITY_PROVIDERS, excludeSecrets)
-	defer resp.Body.Close()
+func fetchIdp(ctx context.Context, idpId string, format string, excludeSecrets bool) (exportedIdp, error) {
 
+	resp, err := utils.SendExportRequest(ctx, idpId, getIdpFileType(format), utils.IDENTITY_PROVIDERS, excludeSecrets)
+	if resp != nil {
+		defer resp.Body.Close()
+	}
 	if err != nil {
-		return fmt.Errorf("error while exporting the identity provider: %s", err)
+		return exportedIdp{}, fmt.Errorf("error while exporting the identity provider: %s", err)
 	}
 	var attachmentDetail = resp.Header.Get("Content-Disposition")
 	_, params, err := mime.ParseMediaType(attachmentDetail)
 	if err != nil {
-		return fmt.Errorf("error while parsing the content disposition header: %s", err)
+		return exportedIdp{}, fmt.Errorf("error while parsing the content disposition header: %s", err)
 	}
 
 	fileName := params["filename"]
-	exportedFileName := filepath.Join(outputDirPath, fileName)
-	fileInfo := utils.GetFileInfo(exportedFileName)
-
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("error while reading the response body when exporting IDP: %s. %s", fileName, err)
+		return exportedIdp{}, fmt.Errorf("error while reading the response body when exporting IDP: %s. %s", fileName, err)
 	}
+	return exportedIdp{fileName: fileName, body: body}, nil
+}
 
-	idpKeywordMapping := getIdpKeywordMapping(fileInfo.ResourceName)
+func processExportedIdp(idpId string, exported exportedIdp, outputDirPath string, format string,
+	excludeSecrets bool) (string, []byte, error) {
+
+	// The file is named after the identity provider, which can contain characters that are not valid in file names.
+	idpName := strings.TrimSuffix(exported.fileName, filepath.Ext(exported.fileName))
+	exportedFileName := filepath.Join(outputDirPath,
+		utils.GetExportedFileName(outputDirPath, idpName, idpId)+filepath.Ext(exported.fileName))
+	body, err := getIdpContent(idpId, exported.body, getIdpFileType(format), excludeSecrets)
+	if err != nil {
+		return "", nil, err
+	}
+	idpKeywordMapping := getIdpKeywordMapping(idpName)
 	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, body, idpKeywordMapping, utils.IDENTITY_PROVIDERS)
 	if err != nil {
-		return fmt.Errorf("error while processing the exported content: %s", err)
+		return "", nil, fmt.Errorf("error while processing the exported content: %s", err)
 	}
 
-	err = ioutil.WriteFile(exportedFileName, modifiedFile, 0644)
-	if err != nil {
-		return fmt.Errorf("error when writing the exported content to file: %w", err)
+	return exportedFileName, modifiedFile, nil
+}
+
+// Mask the secrets of the exported identity provider, and add the local authenticator configs of the resident
+// identity provider.
+func getIdpContent(idpId string, body []byte, fileType string, excludeSecrets bool) ([]byte, error) {
+
+	var err error
+	if excludeSecrets {
+		body = utils.MaskSensitiveFields(body, utils.GetSensitiveFields())
+	}
+	if fileType == utils.MEDIA_TYPE_YAML {
+		if excludeSecrets {
+			body, err = maskFederatedAuthenticatorSecrets(body)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if idpId == utils.RESIDENT_IDP_NAME {
+			body, err = addLocalAuthenticatorConfigs(body, excludeSecrets)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return body, nil
+}
+
+func getIdpFileType(format string) string {
+
+	// TODO: Extend support for json and xml formats.
+	switch format {
+	case "json":
+		return utils.MEDIA_TYPE_JSON
+	case "xml":
+		return utils.MEDIA_TYPE_XML
+	default:
+		return utils.MEDIA_TYPE_YAML
 	}
-	return nil
 }
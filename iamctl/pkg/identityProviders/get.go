@@ -0,0 +1,62 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package identityproviders
+
+import (
+	"fmt"
+	"log"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Get the content of a deployed identity provider in the format of the exported files. The sensitive fields are
+// masked unless showSecrets is set. The identity provider is then exported with the secrets, and the access is
+// recorded in the audit log.
+func Get(idpName string, showSecrets bool) ([]byte, error) {
+
+	idpId := utils.RESIDENT_IDP_NAME
+	if idpName != utils.RESIDENT_IDP_NAME {
+		idps, err := getIdpList()
+		if err != nil {
+			return nil, fmt.Errorf("error when retrieving the deployed identity providers: %s", err)
+		}
+		idpId = ""
+		for _, idp := range idps {
+			if idp.Name == idpName {
+				idpId = idp.Id
+				break
+			}
+		}
+		if idpId == "" {
+			return nil, fmt.Errorf("identity provider: %s is not found in the target environment", idpName)
+		}
+	}
+
+	if showSecrets {
+		log.Printf("Retrieving identity provider: %s with the sensitive fields unmasked.", idpName)
+	}
+	exported, err := fetchIdp(utils.GetRequestContext(), idpId, utils.OUTPUT_YAML, !showSecrets)
+	if showSecrets {
+		utils.RecordSecretAccess(utils.IDENTITY_PROVIDERS, idpName, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return getIdpContent(idpId, exported.body, utils.MEDIA_TYPE_YAML, !showSecrets)
+}
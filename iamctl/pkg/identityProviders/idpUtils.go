@@ -23,10 +23,16 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"os"
+	"path/filepath"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
 )
 
+// Key of the identity provider name in the exported identity provider file.
+const IDP_NAME_KEY = "identityProviderName"
+
 type identityProvider struct {
 	Id   string `json:"id"`
 	Name string `json:"name"`
@@ -49,7 +55,7 @@ func getIdpList() ([]identityProvider, error) {
 		log.Println("Error: when retrieving IDP count. Retrieving only the default count.", err)
 	}
 	var list idpList
-	resp, err := utils.SendGetListRequest(utils.IDENTITY_PROVIDERS, idpCount)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.IDENTITY_PROVIDERS, idpCount)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve available IDP list. %w", err)
 	}
@@ -68,7 +74,16 @@ func getIdpList() ([]identityProvider, error) {
 		}
 		resp.Body.Close()
 
-		return list.IdentityProviders, nil
+		// Skip the system IDPs since they cannot be exported or imported.
+		var idps []identityProvider
+		for _, idp := range list.IdentityProviders {
+			if utils.IsSystemIdp(idp.Name) {
+				utils.LogDebug("Skipping system identity provider: " + idp.Name)
+				continue
+			}
+			idps = append(idps, idp)
+		}
+		return idps, nil
 	} else if error, ok := utils.ErrorCodes[statusCode]; ok {
 		return nil, fmt.Errorf("error while retrieving IDP list. Status code: %d, Error: %s", statusCode, error)
 	}
@@ -78,7 +93,7 @@ func getIdpList() ([]identityProvider, error) {
 func getTotalIdpCount() (count int, err error) {
 
 	var list idpList
-	resp, err := utils.SendGetListRequest(utils.IDENTITY_PROVIDERS, -1)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.IDENTITY_PROVIDERS, -1)
 	if err != nil {
 		return -1, fmt.Errorf("failed to retrieve available IDP list. %w", err)
 	}
@@ -118,10 +133,124 @@ func getDeployedIdpNames() []string {
 	return idpNames
 }
 
+// Get the names of the identity providers deployed in the target environment and the local identity providers to be
+// imported from the given directories.
+func GetAvailableIdpNames(inputDirPaths []string) ([]string, error) {
+
+	deployedIdps, err := getIdpList()
+	if err != nil {
+		return nil, err
+	}
+	var idpNames []string
+	for _, idp := range deployedIdps {
+		idpNames = append(idpNames, idp.Name)
+	}
+	if utils.IsResourceTypeExcluded(utils.IDENTITY_PROVIDERS) {
+		return idpNames, nil
+	}
+
+	for _, inputDirPath := range inputDirPaths {
+		importFilePath := filepath.Join(inputDirPath, utils.IDENTITY_PROVIDERS)
+		files, err := ioutil.ReadDir(importFilePath)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error when reading the identity providers: %s", err)
+		}
+		for _, file := range files {
+			idpName := utils.ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+			if file.IsDir() || !utils.IsResourceIncluded(idpName) ||
+				utils.IsResourceExcluded(idpName, utils.TOOL_CONFIGS.IdpConfigs) {
+				continue
+			}
+			idpNames = append(idpNames, idpName)
+			fileContent, err := utils.ReadResourceFile(filepath.Join(importFilePath, file.Name()))
+			if err != nil {
+				continue
+			}
+			var idpConfig idpConfig
+			if yaml.Unmarshal(fileContent, &idpConfig) == nil && idpConfig.IdentityProviderName != "" {
+				idpNames = append(idpNames, idpConfig.IdentityProviderName)
+			}
+		}
+	}
+	return idpNames, nil
+}
+
 func getIdpKeywordMapping(idpName string) map[string]interface{} {
 
-	if utils.KEYWORD_CONFIGS.IdpConfigs != nil {
-		return utils.ResolveAdvancedKeywordMapping(idpName, utils.KEYWORD_CONFIGS.IdpConfigs)
+	return utils.ResolveAdvancedKeywordMapping(idpName, utils.KEYWORD_CONFIGS.IdpConfigs)
+}
+
+func GetPlannedCreates(inputDirPath string) (deployed int, creates int, err error) {
+
+	// Count the local identity providers that do not exist in the target environment.
+	importFilePath := filepath.Join(inputDirPath, utils.IDENTITY_PROVIDERS)
+	files, err := ioutil.ReadDir(importFilePath)
+	if os.IsNotExist(err) || utils.IsResourceTypeExcluded(utils.IDENTITY_PROVIDERS) {
+		return 0, 0, nil
+	} else if err != nil {
+		return 0, 0, fmt.Errorf("error when reading the identity providers: %s", err)
+	}
+	deployedIdps, err := getIdpList()
+	if err != nil {
+		return 0, 0, err
+	}
+	var deployedIdpNames []string
+	for _, idp := range deployedIdps {
+		deployedIdpNames = append(deployedIdpNames, idp.Name)
+	}
+
+	for _, file := range files {
+		idpName := utils.ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+		if file.IsDir() || utils.IsSystemIdp(idpName) || !utils.IsResourceIncluded(idpName) ||
+			utils.IsResourceExcluded(idpName, utils.TOOL_CONFIGS.IdpConfigs) {
+			continue
+		}
+		fileContent, err := utils.ReadResourceFile(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			continue
+		}
+		var idpConfig idpConfig
+		if yaml.Unmarshal(fileContent, &idpConfig) == nil && idpConfig.IdentityProviderName != "" {
+			idpName = idpConfig.IdentityProviderName
+		}
+		if !utils.Contains(deployedIdpNames, idpName) {
+			creates++
+		}
+	}
+	return len(deployedIdpNames), creates, nil
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localIdpNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.IDENTITY_PROVIDERS),
+		utils.TOOL_CONFIGS.IdpConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	idps, err := getIdpList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedIdps []utils.DeployedResource
+	for _, idp := range idps {
+		isDeletable := !utils.IsResourceExcluded(idp.Name, utils.TOOL_CONFIGS.IdpConfigs) && idp.Name != utils.RESIDENT_IDP_NAME
+		deployedIdps = append(deployedIdps, utils.DeployedResource{Name: idp.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.IDENTITY_PROVIDERS, localIdpNames, deployedIdps, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the identity providers that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	idps, err := getIdpList()
+	if err != nil {
+		return nil, err
+	}
+	idpNames := []string{utils.RESIDENT_IDP_NAME}
+	for _, idp := range idps {
+		idpNames = append(idpNames, idp.Name)
 	}
-	return utils.KEYWORD_CONFIGS.KeywordMappings
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.IDENTITY_PROVIDERS), idpNames)
 }
@@ -24,7 +24,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 	"gopkg.in/yaml.v2"
@@ -46,15 +46,28 @@ func ImportAll(inputDirPath string) {
 		if err != nil {
 			log.Println("Error importing identity providers: ", err)
 		}
-		if utils.TOOL_CONFIGS.AllowDelete {
-			removeDeletedDeployedIdps(files)
+		if utils.IsDeleteAllowed() {
+			removeDeletedDeployedIdps(files, importFilePath)
 		}
 
 	}
 
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+	utils.StartResourceProgress(utils.IDENTITY_PROVIDERS, len(files))
 	for _, file := range files {
 		idpFilePath := filepath.Join(importFilePath, file.Name())
-		idpName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		idpName := utils.ResolveResourceName(idpFilePath)
+		if !utils.IsResourceIncluded(idpName) {
+			utils.AddFilteredResourceToSummary(utils.IDENTITY_PROVIDERS, idpName)
+			continue
+		}
+		if !utils.IsResourceExcluded(idpName, utils.TOOL_CONFIGS.IdpConfigs) && !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.IDENTITY_PROVIDERS, idpName)
+			continue
+		}
 
 		if !utils.IsResourceExcluded(idpName, utils.TOOL_CONFIGS.IdpConfigs) {
 			var idpId string
@@ -67,63 +80,103 @@ func ImportAll(inputDirPath string) {
 
 			if err != nil {
 				log.Printf("Invalid file configurations for identity provider: %s. %s", idpName, err)
+			} else if idpId == "" && utils.UPDATE_ONLY {
+				utils.SkipMissingResource(utils.IDENTITY_PROVIDERS, idpName)
 			} else {
+				utils.EmitResourceStarted(utils.IDENTITY_PROVIDERS, idpName, utils.IMPORT)
 				err := importIdp(idpId, idpFilePath)
 				if err != nil {
 					log.Println("Error importing identity provider: ", err)
 				}
 			}
+		} else {
+			utils.ReportSkippedResource(utils.IDENTITY_PROVIDERS, idpName, utils.JUNIT_SKIPPED_EXCLUDED)
 		}
 	}
 }
 
 func importIdp(idpId string, importFilePath string) error {
 
-	fileBytes, err := ioutil.ReadFile(importFilePath)
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
 	if err != nil {
 		return fmt.Errorf("error when reading the file for identity provider: %s", err)
 	}
 
 	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	// The file can be named differently from the identity provider, if the name is not valid as a file name.
 	fileInfo := utils.GetFileInfo(importFilePath)
+	fileInfo.ResourceName = utils.ResolveResourceName(importFilePath)
 	idpKeywordMapping := getIdpKeywordMapping(fileInfo.ResourceName)
 	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), idpKeywordMapping)
+	utils.CheckImportContent(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, modifiedFileData)
 
 	if idpId == "" {
 		return importIdentityProvider(importFilePath, modifiedFileData, fileInfo)
 	}
+	if utils.IsImportStateUnchanged(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, modifiedFileData) {
+		log.Println("Identity provider is unchanged since the last import. Skipping update: " + fileInfo.ResourceName)
+		utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, utils.UNCHANGED)
+		return nil
+	}
 	return updateIdentityProvider(idpId, importFilePath, modifiedFileData, fileInfo)
 }
 
 func importIdentityProvider(importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
+	idpFileData, err := resolveFederatedAuthenticatorSecrets("", modifiedFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
+		return fmt.Errorf("error when importing identity provider: %s", err)
+	}
 	log.Println("Creating new identity provider: " + fileInfo.ResourceName)
-	err := utils.SendImportRequest(importFilePath, modifiedFileData, utils.IDENTITY_PROVIDERS)
+	err = utils.SendImportRequest(utils.GetRequestContext(), importFilePath, idpFileData, utils.IDENTITY_PROVIDERS)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
 		return fmt.Errorf("error when importing identity provider: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, utils.IMPORT)
+	utils.UpdateImportState(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, utils.IMPORT)
 	log.Println("Identity provider imported successfully.")
 	return nil
 }
 
 func updateIdentityProvider(idpId string, importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
+	idpFileData, localAuthenticatorConfigs, err := resolveLocalAuthenticatorConfigs(modifiedFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating identity provider: %s", err)
+	}
+	idpFileData, err = resolveFederatedAuthenticatorSecrets(idpId, idpFileData)
+	if err != nil {
+		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating identity provider: %s", err)
+	}
+	if err := backupIdp(idpId, fileInfo.ResourceName); err != nil {
+		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
+		return fmt.Errorf("error when updating identity provider: %s", err)
+	}
 	log.Println("Updating identity provider: " + fileInfo.ResourceName)
-	err := utils.SendUpdateRequest(idpId, importFilePath, modifiedFileData, utils.IDENTITY_PROVIDERS)
+	err = utils.SendUpdateRequest(utils.GetRequestContext(), idpId, importFilePath, idpFileData, utils.IDENTITY_PROVIDERS)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
 		return fmt.Errorf("error when updating identity provider: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, utils.UPDATE)
+	if localAuthenticatorConfigs != nil {
+		if err := setLocalAuthenticatorConfigs(localAuthenticatorConfigs); err != nil {
+			utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName)
+			return fmt.Errorf("error when updating identity provider: %s", err)
+		}
+	}
+	utils.UpdateImportState(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, fileInfo.ResourceName, utils.UPDATE)
 	log.Println("Identity provider updated successfully.")
 	return nil
 }
 
 func getIdpId(idpFilePath string, idpName string) (string, error) {
 
-	fileContent, err := ioutil.ReadFile(idpFilePath)
+	fileContent, err := utils.ReadResourceFile(idpFilePath)
 	if err != nil {
 		return "", fmt.Errorf("error when reading the file for idp: %s. %s", idpName, err)
 	}
@@ -145,7 +198,7 @@ func getIdpId(idpFilePath string, idpName string) (string, error) {
 	return "", nil
 }
 
-func removeDeletedDeployedIdps(localFiles []os.FileInfo) {
+func removeDeletedDeployedIdps(localFiles []os.FileInfo, importFilePath string) {
 
 	// Remove deployed identity providers that do not exist locally.
 	deployedIdps, err := getIdpList()
@@ -153,10 +206,11 @@ func removeDeletedDeployedIdps(localFiles []os.FileInfo) {
 		log.Println("Error retrieving deployed identity providers: ", err)
 		return
 	}
+	var idpsToDelete []identityProvider
 deployedResourcess:
 	for _, idp := range deployedIdps {
 		for _, file := range localFiles {
-			if idp.Name == utils.GetFileInfo(file.Name()).ResourceName {
+			if idp.Name == utils.ResolveResourceName(filepath.Join(importFilePath, file.Name())) {
 				continue deployedResourcess
 			}
 		}
@@ -164,12 +218,52 @@ deployedResourcess:
 			log.Println("Identity provider is excluded from deletion: ", idp.Name)
 			continue
 		}
+		idpsToDelete = append(idpsToDelete, idp)
+	}
+
+	var idpNames []string
+	for _, idp := range idpsToDelete {
+		idpNames = append(idpNames, idp.Name)
+	}
+	if !utils.ConfirmDeletion(utils.IDENTITY_PROVIDERS, idpNames) {
+		return
+	}
+	for _, idp := range idpsToDelete {
 		log.Printf("Identity provider: %s not found locally. Deleting idp.\n", idp.Name)
-		err := utils.SendDeleteRequest(idp.Id, utils.IDENTITY_PROVIDERS)
+		utils.EmitResourceStarted(utils.IDENTITY_PROVIDERS, idp.Name, utils.DELETE)
+		if err := backupIdp(idp.Id, idp.Name); err != nil {
+			utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, idp.Name)
+			log.Println("Error deleting idp: ", idp.Name, err)
+			continue
+		}
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), idp.Id, utils.IDENTITY_PROVIDERS)
 		if err != nil {
 			utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, idp.Name)
 			log.Println("Error deleting idp: ", idp.Name, err)
 		}
-		utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, utils.DELETE)
+		utils.UpdateSuccessSummary(utils.IDENTITY_PROVIDERS, idp.Name, utils.DELETE)
 	}
 }
+
+// Export the deployed identity provider to the backup directory with the secrets masked, before it is updated or deleted.
+func backupIdp(idpId string, idpName string) error {
+
+	if !utils.IsBackupEnabled() {
+		return nil
+	}
+	backupDir, err := utils.GetBackupDir(utils.IDENTITY_PROVIDERS)
+	if err != nil {
+		return err
+	}
+	log.Println("Backing up identity provider: " + idpName)
+	// The file names are mapped in the backup as well, so that the backup can be restored with the identity provider names.
+	utils.StartFileNameMapping(backupDir)
+	err = exportIdp(idpId, backupDir, "yaml", true)
+	if mappingErr := utils.WriteFileNameMapping(backupDir); err == nil {
+		err = mappingErr
+	}
+	if err != nil {
+		return fmt.Errorf("error when backing up the deployed identity provider: %s", err)
+	}
+	return nil
+}
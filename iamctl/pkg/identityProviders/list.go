@@ -0,0 +1,163 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package identityproviders
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Federation protocols that identity providers can be filtered by.
+const PROTOCOL_OIDC = "oidc"
+const PROTOCOL_SAML = "saml"
+const PROTOCOL_WS_FEDERATION = "ws-federation"
+
+var FEDERATION_PROTOCOLS = []string{PROTOCOL_OIDC, PROTOCOL_SAML, PROTOCOL_WS_FEDERATION}
+
+// Federation protocols of the federated authenticators that are available by default in the server.
+var authenticatorProtocols = map[string]string{
+	"OpenIDConnectAuthenticator": PROTOCOL_OIDC,
+	"SAMLSSOAuthenticator":       PROTOCOL_SAML,
+	"PassiveSTSAuthenticator":    PROTOCOL_WS_FEDERATION,
+}
+
+type FederatedAuthenticator struct {
+	Name      string `json:"name" yaml:"name"`
+	IsEnabled bool   `json:"isEnabled" yaml:"isEnabled"`
+}
+
+type FederatedAuthenticatorList struct {
+	Authenticators []FederatedAuthenticator `json:"authenticators" yaml:"authenticators"`
+}
+
+type IdpListItem struct {
+	Id                      string                      `json:"id"`
+	Name                    string                      `json:"name"`
+	Description             string                      `json:"description"`
+	IsEnabled               bool                        `json:"isEnabled"`
+	FederatedAuthenticators *FederatedAuthenticatorList `json:"federatedAuthenticators"`
+	// All attributes of the identity provider returned by the list API, for the JSON and YAML outputs.
+	Attributes map[string]interface{} `json:"-"`
+}
+
+func ListIdps(protocol string) ([]IdpListItem, error) {
+
+	idpCount, err := getTotalIdpCount()
+	if err != nil {
+		return nil, err
+	}
+	var list struct {
+		IdentityProviders []IdpListItem `json:"identityProviders"`
+	}
+	var attributeList struct {
+		IdentityProviders []map[string]interface{} `json:"identityProviders"`
+	}
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(idpCount))
+	query.Set("requiredAttributes", "federatedAuthenticators")
+	if filter := utils.GetNamespaceFilter("name"); filter != "" {
+		query.Set("filter", filter)
+	}
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.IDENTITY_PROVIDERS, "?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving identity provider list. %w", err)
+	}
+	err = json.Unmarshal(body, &list)
+	if err == nil {
+		err = json.Unmarshal(body, &attributeList)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved identity provider list. %w", err)
+	}
+
+	var idps []IdpListItem
+	for i, idp := range list.IdentityProviders {
+		idp.Attributes = attributeList.IdentityProviders[i]
+		if !utils.IsInNamespace(idp.Name) {
+			continue
+		}
+		if utils.IsSystemIdp(idp.Name) {
+			utils.LogDebug("Skipping system identity provider: " + idp.Name)
+			continue
+		}
+		// Servers that do not support the required attributes of the list API do not return the authenticators.
+		if idp.FederatedAuthenticators == nil {
+			idp.FederatedAuthenticators, err = getFederatedAuthenticators(idp.Id)
+			if err != nil {
+				return nil, err
+			}
+			idp.Attributes["federatedAuthenticators"] = idp.FederatedAuthenticators
+		}
+		idps = append(idps, idp)
+	}
+	sort.SliceStable(idps, func(i, j int) bool {
+		return idps[i].Name < idps[j].Name
+	})
+	if protocol != "" {
+		idps = FilterIdpsByProtocol(idps, protocol)
+	}
+	return idps, nil
+}
+
+func getFederatedAuthenticators(idpId string) (*FederatedAuthenticatorList, error) {
+
+	var authenticators FederatedAuthenticatorList
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.IDENTITY_PROVIDERS, idpId+"/federated-authenticators", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving the federated authenticators of identity provider: %s. %w", idpId, err)
+	}
+	err = json.Unmarshal(body, &authenticators)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved federated authenticators. %w", err)
+	}
+	return &authenticators, nil
+}
+
+func FilterIdpsByProtocol(idps []IdpListItem, protocol string) []IdpListItem {
+
+	var filteredIdps []IdpListItem
+	for _, idp := range idps {
+		if utils.Contains(GetIdpProtocols(idp), protocol) {
+			filteredIdps = append(filteredIdps, idp)
+		}
+	}
+	return filteredIdps
+}
+
+func GetIdpProtocols(idp IdpListItem) []string {
+
+	// Only the enabled authenticators are considered, since the disabled authenticators are not used for federation.
+	var protocols []string
+	if idp.FederatedAuthenticators == nil {
+		return protocols
+	}
+	for _, authenticator := range idp.FederatedAuthenticators.Authenticators {
+		protocol, ok := authenticatorProtocols[authenticator.Name]
+		if authenticator.IsEnabled && ok && !utils.Contains(protocols, protocol) {
+			protocols = append(protocols, protocol)
+		}
+	}
+	return protocols
+}
@@ -0,0 +1,111 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package notificationsenders
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each notification sender to a separate file in the folder of its channel. Ex: NotificationSenders/Email
+	log.Println("Exporting notification senders...")
+	exportFilePath = filepath.Join(exportFilePath, utils.NOTIFICATION_SENDERS)
+
+	if utils.IsResourceTypeExcluded(utils.NOTIFICATION_SENDERS) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.NOTIFICATION_SENDERS)
+	if !utils.AreSecretsExcluded(utils.TOOL_CONFIGS.NotificationSenderConfigs) {
+		utils.LogWarning(utils.WARNING_MASKED_SECRET, "Secrets exclusion cannot be disabled for notification senders. "+
+			"All secrets will be masked.")
+	}
+	for _, channel := range senderChannels {
+		exportChannelSenders(channel, filepath.Join(exportFilePath, channel.name))
+	}
+}
+
+func exportChannelSenders(channel senderChannel, exportFilePath string) {
+
+	senders, err := getSenderList(channel)
+	if err != nil {
+		log.Println("Error while retrieving notification senders.", err)
+		return
+	}
+	var senderNames []string
+	for _, sender := range senders {
+		senderNames = append(senderNames, getSenderName(sender))
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, senderNames)
+	}
+
+	sort.SliceStable(senders, func(i, j int) bool {
+		return utils.GetResourcePriority(getSenderName(senders[i])) < utils.GetResourcePriority(getSenderName(senders[j]))
+	})
+	for _, sender := range senders {
+		senderName := getSenderName(sender)
+		summaryName := getSummaryName(channel, senderName)
+		if utils.IsResourceExcluded(senderName, utils.TOOL_CONFIGS.NotificationSenderConfigs) {
+			utils.ReportSkippedResource(utils.NOTIFICATION_SENDERS, summaryName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.NOTIFICATION_SENDERS, summaryName)
+			continue
+		}
+		log.Println("Exporting notification sender: ", summaryName)
+		utils.EmitResourceStarted(utils.NOTIFICATION_SENDERS, summaryName, utils.EXPORT)
+		err := exportSender(channel, sender, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.NOTIFICATION_SENDERS, summaryName)
+			log.Printf("Error while exporting notification sender: %s. %s", summaryName, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.NOTIFICATION_SENDERS, summaryName, utils.EXPORT)
+			log.Println("Notification sender exported successfully: ", summaryName)
+		}
+	}
+}
+
+func exportSender(channel senderChannel, sender map[string]interface{}, outputDirPath string) error {
+
+	maskSecrets(channel, sender)
+	content, err := yaml.Marshal(sender)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	senderName := getSenderName(sender)
+	exportedFileName := filepath.Join(outputDirPath, senderName+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getSenderKeywordMapping(senderName),
+		utils.NOTIFICATION_SENDERS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
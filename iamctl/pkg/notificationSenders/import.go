@@ -0,0 +1,219 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package notificationsenders
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.NOTIFICATION_SENDERS)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.NOTIFICATION_SENDERS) {
+		return
+	}
+
+	log.Println("Importing notification senders...")
+	for _, channel := range senderChannels {
+		importChannelSenders(channel, filepath.Join(importFilePath, channel.name))
+	}
+}
+
+func importChannelSenders(channel senderChannel, importFilePath string) {
+
+	files, err := ioutil.ReadDir(importFilePath)
+	if os.IsNotExist(err) {
+		return
+	} else if err != nil {
+		log.Println("Error importing notification senders: ", err)
+		return
+	}
+	senders, err := getSenderList(channel)
+	if err != nil {
+		log.Println("Error importing notification senders: ", err)
+		return
+	}
+	deployedSenders := make(map[string]map[string]interface{})
+	for _, sender := range senders {
+		deployedSenders[getSenderName(sender)] = sender
+	}
+
+	var localSenderNames []string
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		senderName := utils.GetFileInfo(file.Name()).ResourceName
+		summaryName := getSummaryName(channel, senderName)
+		localSenderNames = append(localSenderNames, senderName)
+		if !utils.IsResourceIncluded(senderName) {
+			utils.AddFilteredResourceToSummary(utils.NOTIFICATION_SENDERS, summaryName)
+			continue
+		}
+		if utils.IsResourceExcluded(senderName, utils.TOOL_CONFIGS.NotificationSenderConfigs) {
+			utils.ReportSkippedResource(utils.NOTIFICATION_SENDERS, summaryName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.NOTIFICATION_SENDERS, summaryName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.NOTIFICATION_SENDERS, summaryName, utils.IMPORT)
+		err := importSender(channel, filepath.Join(importFilePath, file.Name()), deployedSenders[senderName])
+		if err != nil {
+			utils.UpdateFailureSummary(utils.NOTIFICATION_SENDERS, summaryName)
+			log.Printf("Error when importing notification sender: %s. %s", summaryName, err)
+		}
+	}
+
+	// Senders are never deleted, since removing the email channel would lock the users out of the recovery flows.
+	if utils.IsDeleteAllowed() {
+		for senderName := range deployedSenders {
+			if !utils.Contains(localSenderNames, senderName) &&
+				!utils.IsResourceExcluded(senderName, utils.TOOL_CONFIGS.NotificationSenderConfigs) {
+				log.Printf("Notification sender: %s is not available locally. Notification senders are not deleted "+
+					"from the target environment.", getSummaryName(channel, senderName))
+			}
+		}
+	}
+}
+
+func importSender(channel senderChannel, importFilePath string, deployedSender map[string]interface{}) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for notification sender: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	keywordMapping := getSenderKeywordMapping(fileName)
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), keywordMapping)
+
+	var senderConfig map[string]interface{}
+	err = yaml.Unmarshal([]byte(modifiedFileData), &senderConfig)
+	if err != nil {
+		return fmt.Errorf("invalid file content for notification sender: %s", err)
+	}
+	sender, _ := toJsonValue(senderConfig).(map[string]interface{})
+	senderName := getSenderName(sender)
+	if senderName == "" {
+		return fmt.Errorf("the name attribute is required")
+	}
+	summaryName := getSummaryName(channel, senderName)
+
+	// The secrets are masked in the exported files and are resolved from the configs of the environment.
+	maskedAttributes := resolveSecrets(channel, sender, keywordMapping)
+	resolvedContent, err := yaml.Marshal(sender)
+	if err != nil {
+		return fmt.Errorf("error when creating the request body: %s", err)
+	}
+	utils.CheckImportContent(utils.NOTIFICATION_SENDERS, summaryName, string(resolvedContent))
+
+	if deployedSender == nil {
+		if len(maskedAttributes) > 0 {
+			return fmt.Errorf("%s of the new notification sender is masked. Set the secrets with the keywords or the "+
+				"environment variables: %s", strings.Join(maskedAttributes, ", "),
+				strings.Join(getSecretEnvNames(channel, senderName, maskedAttributes), ", "))
+		}
+		return createSender(channel, sender, string(resolvedContent))
+	}
+
+	// Masked secrets are not updated, so that the secrets of the target environment are kept.
+	for _, attribute := range maskedAttributes {
+		if deployedValue, ok := deployedSender[attribute]; ok {
+			sender[attribute] = deployedValue
+		} else {
+			delete(sender, attribute)
+		}
+	}
+	return updateSender(channel, sender, deployedSender, string(resolvedContent))
+}
+
+func getSecretEnvNames(channel senderChannel, senderName string, attributes []string) []string {
+
+	var envNames []string
+	for _, attribute := range attributes {
+		envNames = append(envNames, GetSecretEnvName(senderName, senderSecrets[channel.name][attribute]))
+	}
+	return envNames
+}
+
+func createSender(channel senderChannel, sender map[string]interface{}, content string) error {
+
+	summaryName := getSummaryName(channel, getSenderName(sender))
+	log.Println("Creating new notification sender: " + summaryName)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.NOTIFICATION_SENDERS, channel.path, sender)
+	if err != nil {
+		return fmt.Errorf("error when creating notification sender: %s", err)
+	}
+	utils.UpdateImportState(utils.NOTIFICATION_SENDERS, summaryName, content)
+	utils.UpdateSuccessSummary(utils.NOTIFICATION_SENDERS, summaryName, utils.IMPORT)
+	log.Println("Notification sender created successfully.")
+	return nil
+}
+
+func updateSender(channel senderChannel, sender map[string]interface{}, deployedSender map[string]interface{},
+	content string) error {
+
+	senderName := getSenderName(sender)
+	summaryName := getSummaryName(channel, senderName)
+	if utils.IsImportStateUnchanged(utils.NOTIFICATION_SENDERS, summaryName, content) {
+		log.Println("Notification sender is unchanged since the last import. Skipping update: " + summaryName)
+		utils.UpdateSuccessSummary(utils.NOTIFICATION_SENDERS, summaryName, utils.UNCHANGED)
+		return nil
+	}
+	if !utils.FORCE_IMPORT && isSenderEqual(sender, deployedSender) {
+		log.Println("Notification sender is unchanged. Skipping update: " + summaryName)
+		utils.UpdateImportState(utils.NOTIFICATION_SENDERS, summaryName, content)
+		utils.UpdateSuccessSummary(utils.NOTIFICATION_SENDERS, summaryName, utils.UNCHANGED)
+		return nil
+	}
+
+	// The name of a sender cannot be updated, hence it is not sent in the payload.
+	log.Println("Updating notification sender: " + summaryName)
+	payload := make(map[string]interface{})
+	for key, value := range sender {
+		if key != "name" {
+			payload[key] = value
+		}
+	}
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.NOTIFICATION_SENDERS,
+		channel.path+"/"+url.PathEscape(senderName), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating notification sender: %s", err)
+	}
+	utils.UpdateImportState(utils.NOTIFICATION_SENDERS, summaryName, content)
+	utils.UpdateSuccessSummary(utils.NOTIFICATION_SENDERS, summaryName, utils.UPDATE)
+	log.Println("Notification sender updated successfully.")
+	return nil
+}
@@ -0,0 +1,191 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package notificationsenders
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const EMAIL = "Email"
+const SMS = "SMS"
+const SENDER_SECRET_ENV_PREFIX = "IAMCTL_NOTIFICATION_SENDER_"
+
+// Channel of the notification senders, with the folder of the exported files and the path of the API.
+type senderChannel struct {
+	name string
+	path string
+}
+
+var senderChannels = []senderChannel{{EMAIL, "email"}, {SMS, "sms"}}
+
+// Secret attributes of the senders of each channel, with the keywords that can be used to set them during import.
+var senderSecrets = map[string]map[string]string{
+	EMAIL: {"password": "SMTP_PASSWORD", "clientSecret": "SMTP_CLIENT_SECRET"},
+	SMS:   {"key": "SMS_API_KEY", "secret": "SMS_API_SECRET"},
+}
+
+var envNameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9]`)
+
+func getSenderList(channel senderChannel) ([]map[string]interface{}, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.NOTIFICATION_SENDERS, channel.path, nil)
+	if errors.Is(err, utils.ErrResourceNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error while retrieving %s sender list. %w", channel.name, err)
+	}
+	var senders []map[string]interface{}
+	err = json.Unmarshal(body, &senders)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved %s sender list. %w", channel.name, err)
+	}
+	return senders, nil
+}
+
+func getSenderName(sender map[string]interface{}) string {
+
+	name, _ := sender["name"].(string)
+	return name
+}
+
+func getSummaryName(channel senderChannel, senderName string) string {
+
+	return channel.name + "/" + senderName
+}
+
+func maskSecrets(channel senderChannel, sender map[string]interface{}) {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	for attribute := range senderSecrets[channel.name] {
+		if value, ok := sender[attribute].(string); ok && value != "" {
+			sender[attribute] = mask
+		}
+	}
+}
+
+// Get the name of the environment variable with the given secret of the notification sender.
+// Ex: IAMCTL_NOTIFICATION_SENDER_EMAILPUBLISHER_SMTP_PASSWORD
+func GetSecretEnvName(senderName string, keyword string) string {
+
+	return SENDER_SECRET_ENV_PREFIX + strings.ToUpper(envNameInvalidChars.ReplaceAllString(senderName, "_")) + "_" + keyword
+}
+
+// Replace the masked secrets of the sender with the values of the environment variables or the keywords, and
+// return the attributes that are still masked.
+func resolveSecrets(channel senderChannel, sender map[string]interface{}, keywordMapping map[string]interface{}) []string {
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	var maskedAttributes []string
+	for attribute, keyword := range senderSecrets[channel.name] {
+		if sender[attribute] != mask {
+			continue
+		}
+
+		// The environment variable overrides the keyword config, so that the secret can be injected by a pipeline.
+		if secret := os.Getenv(GetSecretEnvName(getSenderName(sender), keyword)); secret != "" {
+			sender[attribute] = secret
+		} else if secret, ok := keywordMapping[keyword].(string); ok && secret != "" {
+			sender[attribute] = secret
+		} else {
+			maskedAttributes = append(maskedAttributes, attribute)
+		}
+	}
+	return maskedAttributes
+}
+
+func getSenderKeywordMapping(senderName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(senderName, utils.KEYWORD_CONFIGS.NotificationSenderConfigs)
+}
+
+func isSenderEqual(localSender map[string]interface{}, deployedSender map[string]interface{}) bool {
+
+	localJson, err := json.Marshal(toJsonValue(localSender))
+	if err != nil {
+		return false
+	}
+	deployedJson, err := json.Marshal(toJsonValue(deployedSender))
+	if err != nil {
+		return false
+	}
+	return string(localJson) == string(deployedJson)
+}
+
+func toJsonValue(value interface{}) interface{} {
+
+	// YAML maps are unmarshalled with interface keys, which cannot be marshalled to JSON.
+	switch v := value.(type) {
+	case map[interface{}]interface{}:
+		jsonMap := make(map[string]interface{})
+		for key, val := range v {
+			jsonMap[fmt.Sprintf("%v", key)] = toJsonValue(val)
+		}
+		return jsonMap
+	case map[string]interface{}:
+		jsonMap := make(map[string]interface{})
+		for key, val := range v {
+			jsonMap[key] = toJsonValue(val)
+		}
+		return jsonMap
+	case []interface{}:
+		jsonList := make([]interface{}, len(v))
+		for i, val := range v {
+			jsonList[i] = toJsonValue(val)
+		}
+		return jsonList
+	case int:
+		return float64(v)
+	}
+	return value
+}
+
+// Get the local files of the notification senders that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	var staleFilePaths []string
+	for _, channel := range senderChannels {
+		channelDirPath := filepath.Join(inputDirPath, utils.NOTIFICATION_SENDERS, channel.name)
+		if _, err := os.Stat(channelDirPath); os.IsNotExist(err) {
+			continue
+		}
+		senders, err := getSenderList(channel)
+		if err != nil {
+			return nil, err
+		}
+		var senderNames []string
+		for _, sender := range senders {
+			senderNames = append(senderNames, getSenderName(sender))
+		}
+		channelFilePaths, err := utils.GetStaleLocalFiles(channelDirPath, senderNames)
+		if err != nil {
+			return nil, err
+		}
+		staleFilePaths = append(staleFilePaths, channelFilePaths...)
+	}
+	return staleFilePaths, nil
+}
@@ -0,0 +1,102 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package oidcscopes
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each OIDC scope to a separate file in the OidcScopes folder.
+	log.Println("Exporting OIDC scopes...")
+	exportFilePath = filepath.Join(exportFilePath, utils.OIDC_SCOPES)
+
+	if utils.IsResourceTypeExcluded(utils.OIDC_SCOPES) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.OIDC_SCOPES)
+	oidcScopes, err := getOidcScopeList()
+	if err != nil {
+		log.Println("Error while retrieving OIDC scope list.", err)
+		return
+	}
+
+	// The default scopes are exported as well, since their claim bindings can be updated.
+	var scopeNames []string
+	for _, scope := range oidcScopes {
+		scopeNames = append(scopeNames, scope.Name)
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, scopeNames)
+	}
+
+	sort.SliceStable(oidcScopes, func(i, j int) bool {
+		return utils.GetResourcePriority(oidcScopes[i].Name) < utils.GetResourcePriority(oidcScopes[j].Name)
+	})
+	utils.StartResourceProgress(utils.OIDC_SCOPES, len(oidcScopes))
+	for _, scope := range oidcScopes {
+		if utils.IsResourceExcluded(scope.Name, utils.TOOL_CONFIGS.OidcScopeConfigs) {
+			utils.ReportSkippedResource(utils.OIDC_SCOPES, scope.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.OIDC_SCOPES, scope.Name)
+			continue
+		}
+		log.Println("Exporting OIDC scope: ", scope.Name)
+		utils.EmitResourceStarted(utils.OIDC_SCOPES, scope.Name, utils.EXPORT)
+		err := exportOidcScope(scope, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.OIDC_SCOPES, scope.Name)
+			log.Printf("Error while exporting OIDC scope: %s. %s", scope.Name, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scope.Name, utils.EXPORT)
+			log.Println("OIDC scope exported successfully: ", scope.Name)
+		}
+	}
+}
+
+func exportOidcScope(scope oidcScope, outputDirPath string) error {
+
+	if scope.Claims == nil {
+		scope.Claims = []string{}
+	}
+	content, err := yaml.Marshal(scope)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, scope.Name+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getOidcScopeKeywordMapping(scope.Name),
+		utils.OIDC_SCOPES)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,234 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package oidcscopes
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+type localOidcScope struct {
+	fileName string
+	content  string
+	scope    oidcScope
+}
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.OIDC_SCOPES)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.OIDC_SCOPES) {
+		return
+	}
+
+	log.Println("Importing OIDC scopes...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing OIDC scopes: ", err)
+		return
+	}
+	oidcScopes, err := getOidcScopeList()
+	if err != nil {
+		log.Println("Error importing OIDC scopes: ", err)
+		return
+	}
+	deployedScopes := make(map[string]oidcScope)
+	for _, scope := range oidcScopes {
+		deployedScopes[scope.Name] = scope
+	}
+
+	var localScopes []localOidcScope
+	isLocalContentValid := true
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		localScope, err := readLocalOidcScope(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			isLocalContentValid = false
+			if utils.IsResourceIncluded(fileName) {
+				utils.UpdateFailureSummary(utils.OIDC_SCOPES, fileName)
+				log.Printf("Invalid file configurations for OIDC scope: %s. %s", fileName, err)
+			}
+			continue
+		}
+		localScopes = append(localScopes, localScope)
+	}
+	if utils.IsDeleteAllowed() {
+		if isLocalContentValid {
+			removeDeletedDeployedOidcScopes(oidcScopes, localScopes)
+		} else {
+			log.Println("Skipping the deletion of OIDC scopes since some of the local files are invalid.")
+		}
+	}
+
+	sort.SliceStable(localScopes, func(i, j int) bool {
+		return utils.GetResourcePriority(localScopes[i].scope.Name) < utils.GetResourcePriority(localScopes[j].scope.Name)
+	})
+	utils.StartResourceProgress(utils.OIDC_SCOPES, len(localScopes))
+	for _, localScope := range localScopes {
+		scopeName := localScope.scope.Name
+		if !utils.IsResourceIncluded(localScope.fileName) {
+			utils.AddFilteredResourceToSummary(utils.OIDC_SCOPES, scopeName)
+			continue
+		}
+		if utils.IsResourceExcluded(scopeName, utils.TOOL_CONFIGS.OidcScopeConfigs) {
+			utils.ReportSkippedResource(utils.OIDC_SCOPES, scopeName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.OIDC_SCOPES, scopeName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.OIDC_SCOPES, scopeName, utils.IMPORT)
+		var err error
+		if deployedScope, isUpdate := deployedScopes[scopeName]; isUpdate {
+			err = updateOidcScope(localScope, deployedScope)
+		} else {
+			err = createOidcScope(localScope)
+		}
+		if err != nil {
+			utils.UpdateFailureSummary(utils.OIDC_SCOPES, scopeName)
+			log.Printf("Error when importing OIDC scope: %s. %s", scopeName, err)
+		}
+	}
+}
+
+func readLocalOidcScope(importFilePath string) (localOidcScope, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return localOidcScope{}, fmt.Errorf("error when reading the file for OIDC scope: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getOidcScopeKeywordMapping(fileName))
+
+	var scope oidcScope
+	err = yaml.Unmarshal([]byte(modifiedFileData), &scope)
+	if err != nil {
+		return localOidcScope{}, fmt.Errorf("invalid file content for OIDC scope: %s", err)
+	}
+	if scope.Name == "" {
+		return localOidcScope{}, fmt.Errorf("the name attribute is required")
+	}
+	if scope.DisplayName == "" {
+		scope.DisplayName = scope.Name
+	}
+	if scope.Claims == nil {
+		scope.Claims = []string{}
+	}
+	return localOidcScope{fileName: fileName, content: modifiedFileData, scope: scope}, nil
+}
+
+func createOidcScope(localScope localOidcScope) error {
+
+	scopeName := localScope.scope.Name
+	utils.CheckImportContent(utils.OIDC_SCOPES, scopeName, localScope.content)
+
+	log.Println("Creating new OIDC scope: " + scopeName)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.OIDC_SCOPES, "", localScope.scope)
+	if err != nil {
+		return fmt.Errorf("error when creating OIDC scope: %s", err)
+	}
+	utils.UpdateImportState(utils.OIDC_SCOPES, scopeName, localScope.content)
+	utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scopeName, utils.IMPORT)
+	log.Println("OIDC scope created successfully.")
+	return nil
+}
+
+func updateOidcScope(localScope localOidcScope, deployedScope oidcScope) error {
+
+	scopeName := localScope.scope.Name
+	utils.CheckImportContent(utils.OIDC_SCOPES, scopeName, localScope.content)
+	if utils.IsImportStateUnchanged(utils.OIDC_SCOPES, scopeName, localScope.content) {
+		log.Println("OIDC scope is unchanged since the last import. Skipping update: " + scopeName)
+		utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scopeName, utils.UNCHANGED)
+		return nil
+	}
+	if !utils.FORCE_IMPORT && isScopeEqual(localScope.scope, deployedScope) {
+		log.Println("OIDC scope is unchanged. Skipping update: " + scopeName)
+		utils.UpdateImportState(utils.OIDC_SCOPES, scopeName, localScope.content)
+		utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scopeName, utils.UNCHANGED)
+		return nil
+	}
+
+	// The name of a scope cannot be updated, hence it is not sent in the payload.
+	log.Println("Updating OIDC scope: " + scopeName)
+	payload := map[string]interface{}{
+		"displayName": localScope.scope.DisplayName,
+		"description": localScope.scope.Description,
+		"claims":      localScope.scope.Claims,
+	}
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.OIDC_SCOPES, url.PathEscape(scopeName), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating OIDC scope: %s", err)
+	}
+	utils.UpdateImportState(utils.OIDC_SCOPES, scopeName, localScope.content)
+	utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scopeName, utils.UPDATE)
+	log.Println("OIDC scope updated successfully.")
+	return nil
+}
+
+func removeDeletedDeployedOidcScopes(deployedScopes []oidcScope, localScopes []localOidcScope) {
+
+	// Remove deployed OIDC scopes that do not exist locally. The default scopes are never deleted.
+	var scopesToDelete []string
+deployedResources:
+	for _, scope := range deployedScopes {
+		for _, localScope := range localScopes {
+			if scope.Name == localScope.scope.Name {
+				continue deployedResources
+			}
+		}
+		if utils.IsDefaultOidcScope(scope.Name) ||
+			utils.IsResourceExcluded(scope.Name, utils.TOOL_CONFIGS.OidcScopeConfigs) {
+			continue
+		}
+		scopesToDelete = append(scopesToDelete, scope.Name)
+	}
+
+	if !utils.ConfirmDeletion(utils.OIDC_SCOPES, scopesToDelete) {
+		return
+	}
+	for _, scopeName := range scopesToDelete {
+		log.Println("OIDC scope not found locally. Deleting OIDC scope: ", scopeName)
+		utils.EmitResourceStarted(utils.OIDC_SCOPES, scopeName, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), url.PathEscape(scopeName), utils.OIDC_SCOPES)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.OIDC_SCOPES, scopeName)
+			log.Println("Error deleting OIDC scope: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.OIDC_SCOPES, scopeName, utils.DELETE)
+	}
+}
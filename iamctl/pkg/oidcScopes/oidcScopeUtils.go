@@ -0,0 +1,108 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package oidcscopes
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+type oidcScope struct {
+	Name        string   `json:"name" yaml:"name"`
+	DisplayName string   `json:"displayName" yaml:"displayName"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Claims      []string `json:"claims" yaml:"claims"`
+}
+
+func getOidcScopeList() ([]oidcScope, error) {
+
+	// The list contains the claims of the scopes, so the scopes are not retrieved separately.
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.OIDC_SCOPES, "", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving OIDC scope list. %w", err)
+	}
+	var oidcScopes []oidcScope
+	err = json.Unmarshal(body, &oidcScopes)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved OIDC scope list. %w", err)
+	}
+	return oidcScopes, nil
+}
+
+func isScopeEqual(localScope oidcScope, deployedScope oidcScope) bool {
+
+	// The order of the claims is not significant.
+	localClaims := append([]string{}, localScope.Claims...)
+	deployedClaims := append([]string{}, deployedScope.Claims...)
+	sort.Strings(localClaims)
+	sort.Strings(deployedClaims)
+	if len(localClaims) != len(deployedClaims) {
+		return false
+	}
+	for i := range localClaims {
+		if localClaims[i] != deployedClaims[i] {
+			return false
+		}
+	}
+	return localScope.DisplayName == deployedScope.DisplayName && localScope.Description == deployedScope.Description
+}
+
+func getOidcScopeKeywordMapping(scopeName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(scopeName, utils.KEYWORD_CONFIGS.OidcScopeConfigs)
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localScopeNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.OIDC_SCOPES),
+		utils.TOOL_CONFIGS.OidcScopeConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	oidcScopes, err := getOidcScopeList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedScopes []utils.DeployedResource
+	for _, scope := range oidcScopes {
+		isDeletable := !utils.IsDefaultOidcScope(scope.Name) &&
+			!utils.IsResourceExcluded(scope.Name, utils.TOOL_CONFIGS.OidcScopeConfigs)
+		deployedScopes = append(deployedScopes, utils.DeployedResource{Name: scope.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.OIDC_SCOPES, localScopeNames, deployedScopes, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the OIDC scopes that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	oidcScopes, err := getOidcScopeList()
+	if err != nil {
+		return nil, err
+	}
+	var scopeNames []string
+	for _, scope := range oidcScopes {
+		scopeNames = append(scopeNames, scope.Name)
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.OIDC_SCOPES), scopeNames)
+}
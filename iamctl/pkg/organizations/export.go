@@ -0,0 +1,132 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package organizations
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each organization to a separate file in the Organizations folder, next to the folders of the
+	// resources exported from the sub organizations.
+	log.Println("Exporting organizations...")
+	exportFilePath = filepath.Join(exportFilePath, utils.ORGANIZATIONS)
+
+	if utils.IsResourceTypeExcluded(utils.ORGANIZATIONS) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.ORGANIZATIONS)
+	organizations, err := getDeployedOrganizations()
+	if err != nil {
+		log.Println("Error while retrieving organization list.", err)
+		return
+	}
+	sharedApplications := make(map[string][]string)
+	if rootOrganizationId := getRootOrganizationId(organizations); rootOrganizationId != "" {
+		sharedApplications, err = getSharedApplications(rootOrganizationId)
+		if err != nil {
+			log.Println("Error while retrieving the shared applications of the organizations.", err)
+			return
+		}
+	} else {
+		log.Printf("Skipping the shared applications of the organizations since the id of the root organization "+
+			"is not found. Add the id to the %s keyword config with the name: %s.", utils.ORGANIZATION_IDS_CONFIG,
+			utils.SERVER_CONFIGS.TenantDomain)
+	}
+
+	var organizationNames []string
+	for _, organization := range organizations {
+		organizationNames = append(organizationNames, organization.Name)
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, organizationNames)
+	}
+
+	// Export the parent organizations before the children, in the order the organizations are imported.
+	sort.SliceStable(organizations, func(i, j int) bool {
+		return utils.GetResourcePriority(organizations[i].Name) < utils.GetResourcePriority(organizations[j].Name)
+	})
+	organizations = sortOrganizationsByHierarchy(organizations)
+	utils.StartResourceProgress(utils.ORGANIZATIONS, len(organizations))
+	for _, organization := range organizations {
+		if utils.IsResourceExcluded(organization.Name, utils.TOOL_CONFIGS.OrganizationConfigs) {
+			utils.ReportSkippedResource(utils.ORGANIZATIONS, organization.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.ORGANIZATIONS, organization.Name)
+			continue
+		}
+		log.Println("Exporting organization: ", organization.Name)
+		utils.EmitResourceStarted(utils.ORGANIZATIONS, organization.Name, utils.EXPORT)
+		organization.SharedApplications = sharedApplications[organization.Id]
+		err := exportOrganization(organization, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ORGANIZATIONS, organization.Name)
+			log.Printf("Error while exporting organization: %s. %s", organization.Name, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.EXPORT)
+			log.Println("Organization exported successfully: ", organization.Name)
+		}
+	}
+}
+
+func exportOrganization(organization organization, outputDirPath string) error {
+
+	content, err := yaml.Marshal(organization)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, organization.Name+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content,
+		getOrganizationKeywordMapping(organization.Name), utils.ORGANIZATIONS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
+
+func sortOrganizationsByHierarchy(organizations []organization) []organization {
+
+	var organizationNames []string
+	parentNames := make(map[string]string)
+	organizationsByName := make(map[string]organization)
+	for _, organization := range organizations {
+		organizationNames = append(organizationNames, organization.Name)
+		parentNames[organization.Name] = organization.ParentName
+		organizationsByName[organization.Name] = organization
+	}
+	sortedNames, cyclicNames := sortByHierarchy(organizationNames, parentNames)
+	var sortedOrganizations []organization
+	for _, organizationName := range append(sortedNames, cyclicNames...) {
+		sortedOrganizations = append(sortedOrganizations, organizationsByName[organizationName])
+	}
+	return sortedOrganizations
+}
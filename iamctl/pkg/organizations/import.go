@@ -0,0 +1,437 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package organizations
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+type localOrganization struct {
+	fileName     string
+	content      string
+	organization organization
+}
+
+// References of the organizations and the applications shared with them in the target environment.
+type organizationReferences struct {
+	organizationIds    map[string]string
+	rootOrganizationId string
+	appIds             map[string]string
+	sharedApplications map[string][]string
+}
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.ORGANIZATIONS)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.ORGANIZATIONS) {
+		return
+	}
+
+	log.Println("Importing organizations...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing organizations: ", err)
+		return
+	}
+	organizations, err := getDeployedOrganizations()
+	if err != nil {
+		log.Println("Error importing organizations: ", err)
+		return
+	}
+	references, err := newOrganizationReferences(organizations)
+	if err != nil {
+		log.Println("Error importing organizations: ", err)
+		return
+	}
+	deployedOrganizations := make(map[string]organization)
+	for _, organization := range organizations {
+		deployedOrganizations[organization.Name] = organization
+	}
+
+	var localOrganizations []localOrganization
+	isLocalContentValid := true
+	for _, file := range files {
+		// The folders of the organizations keep the resources exported from the sub organizations.
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		localOrganization, err := readLocalOrganization(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			isLocalContentValid = false
+			if utils.IsResourceIncluded(fileName) {
+				utils.UpdateFailureSummary(utils.ORGANIZATIONS, fileName)
+				log.Printf("Invalid file configurations for organization: %s. %s", fileName, err)
+			}
+			continue
+		}
+		localOrganizations = append(localOrganizations, localOrganization)
+	}
+	if utils.IsDeleteAllowed() {
+		if isLocalContentValid {
+			removeDeletedDeployedOrganizations(organizations, localOrganizations)
+		} else {
+			log.Println("Skipping the deletion of organizations since some of the local files are invalid.")
+		}
+	}
+
+	// Import the parent organizations before the children, as the parent of an organization is required to create it.
+	sort.SliceStable(localOrganizations, func(i, j int) bool {
+		return utils.GetResourcePriority(localOrganizations[i].organization.Name) <
+			utils.GetResourcePriority(localOrganizations[j].organization.Name)
+	})
+	localOrganizations, cyclicOrganizations := sortLocalOrganizationsByHierarchy(localOrganizations)
+	for _, localOrganization := range cyclicOrganizations {
+		utils.UpdateFailureSummary(utils.ORGANIZATIONS, localOrganization.organization.Name)
+		log.Printf("Error when importing organization: %s. The organization is in a cycle of parent organizations.",
+			localOrganization.organization.Name)
+	}
+	utils.StartResourceProgress(utils.ORGANIZATIONS, len(localOrganizations))
+	for _, localOrganization := range localOrganizations {
+		organizationName := localOrganization.organization.Name
+		if !utils.IsResourceIncluded(localOrganization.fileName) {
+			utils.AddFilteredResourceToSummary(utils.ORGANIZATIONS, organizationName)
+			continue
+		}
+		if utils.IsResourceExcluded(organizationName, utils.TOOL_CONFIGS.OrganizationConfigs) {
+			utils.ReportSkippedResource(utils.ORGANIZATIONS, organizationName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.ORGANIZATIONS, organizationName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.ORGANIZATIONS, organizationName, utils.IMPORT)
+		var err error
+		if deployedOrganization, isUpdate := deployedOrganizations[organizationName]; isUpdate {
+			err = updateOrganization(localOrganization, deployedOrganization, references)
+		} else {
+			err = createOrganization(localOrganization, references)
+		}
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ORGANIZATIONS, organizationName)
+			log.Printf("Error when importing organization: %s. %s", organizationName, err)
+		}
+	}
+}
+
+func readLocalOrganization(importFilePath string) (localOrganization, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return localOrganization{}, fmt.Errorf("error when reading the file for organization: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getOrganizationKeywordMapping(fileName))
+
+	var organization organization
+	err = yaml.Unmarshal([]byte(modifiedFileData), &organization)
+	if err != nil {
+		return localOrganization{}, fmt.Errorf("invalid file content for organization: %s", err)
+	}
+	if organization.Name == "" {
+		return localOrganization{}, fmt.Errorf("the name attribute is required")
+	}
+	if organization.ParentName == organization.Name {
+		return localOrganization{}, fmt.Errorf("the organization cannot be the parent of itself")
+	}
+	return localOrganization{fileName: fileName, content: modifiedFileData, organization: organization}, nil
+}
+
+func sortLocalOrganizationsByHierarchy(localOrganizations []localOrganization) ([]localOrganization,
+	[]localOrganization) {
+
+	var organizationNames []string
+	parentNames := make(map[string]string)
+	localOrganizationsByName := make(map[string]localOrganization)
+	for _, localOrganization := range localOrganizations {
+		organizationName := localOrganization.organization.Name
+		organizationNames = append(organizationNames, organizationName)
+		parentNames[organizationName] = localOrganization.organization.ParentName
+		localOrganizationsByName[organizationName] = localOrganization
+	}
+	sortedNames, cyclicNames := sortByHierarchy(organizationNames, parentNames)
+	var sortedOrganizations, cyclicOrganizations []localOrganization
+	for _, organizationName := range sortedNames {
+		sortedOrganizations = append(sortedOrganizations, localOrganizationsByName[organizationName])
+	}
+	for _, organizationName := range cyclicNames {
+		cyclicOrganizations = append(cyclicOrganizations, localOrganizationsByName[organizationName])
+	}
+	return sortedOrganizations, cyclicOrganizations
+}
+
+func newOrganizationReferences(deployedOrganizations []organization) (*organizationReferences, error) {
+
+	appIds, err := applications.GetDeployedAppIds()
+	if err != nil {
+		return nil, err
+	}
+	references := &organizationReferences{
+		organizationIds:    make(map[string]string),
+		rootOrganizationId: getRootOrganizationId(deployedOrganizations),
+		appIds:             appIds,
+		sharedApplications: make(map[string][]string),
+	}
+	for _, organization := range deployedOrganizations {
+		references.organizationIds[organization.Name] = organization.Id
+	}
+	if references.rootOrganizationId == "" {
+		return references, nil
+	}
+	sharedApplications, err := getSharedApplications(references.rootOrganizationId)
+	if err != nil {
+		return nil, err
+	}
+	references.sharedApplications = sharedApplications
+	return references, nil
+}
+
+// Get the id of the parent organization in the target environment. The organizations created in the run are
+// resolved by the name, and the organizations not managed by the tool from the ORGANIZATION_IDS keyword config.
+func (references *organizationReferences) getParentId(parentName string) (string, error) {
+
+	if parentName == "" {
+		return "", nil
+	}
+	if parentId, ok := references.organizationIds[parentName]; ok {
+		return parentId, nil
+	}
+	if parentId := getMappedOrganizationId(parentName); parentId != "" {
+		return parentId, nil
+	}
+	return "", fmt.Errorf("parent organization: %s is not found", parentName)
+}
+
+func (references *organizationReferences) getAppIds(appNames []string) ([]string, error) {
+
+	var appIds, missingApps []string
+	for _, appName := range appNames {
+		appId, ok := references.appIds[appName]
+		if !ok {
+			missingApps = append(missingApps, appName)
+		}
+		appIds = append(appIds, appId)
+	}
+	if len(missingApps) > 0 {
+		return nil, fmt.Errorf("shared applications: %s are not found", strings.Join(missingApps, ", "))
+	}
+	return appIds, nil
+}
+
+func createOrganization(localOrganization localOrganization, references *organizationReferences) error {
+
+	organization := localOrganization.organization
+	utils.CheckImportContent(utils.ORGANIZATIONS, organization.Name, localOrganization.content)
+	parentId, err := references.getParentId(organization.ParentName)
+	if err != nil {
+		return fmt.Errorf("error when resolving the dependencies of organization: %s", err)
+	}
+	if _, err := references.getAppIds(organization.SharedApplications); err != nil {
+		return fmt.Errorf("error when resolving the dependencies of organization: %s", err)
+	}
+
+	log.Println("Creating new organization: " + organization.Name)
+	payload := map[string]interface{}{
+		"name":        organization.Name,
+		"description": organization.Description,
+		"attributes":  getAttributes(organization),
+	}
+	if organization.Type != "" {
+		payload["type"] = organization.Type
+	}
+	if parentId != "" {
+		payload["parentId"] = parentId
+	}
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.ORGANIZATIONS, "", payload)
+	if err != nil {
+		return fmt.Errorf("error when creating organization: %s", err)
+	}
+	var createdOrganization struct {
+		Id string `json:"id"`
+	}
+	if err := json.Unmarshal(body, &createdOrganization); err != nil || createdOrganization.Id == "" {
+		return fmt.Errorf("error when reading the id of the created organization: %s", string(body))
+	}
+	references.organizationIds[organization.Name] = createdOrganization.Id
+	if err := references.updateSharedApplications(createdOrganization.Id, organization.SharedApplications); err != nil {
+		return err
+	}
+	utils.UpdateImportState(utils.ORGANIZATIONS, organization.Name, localOrganization.content)
+	utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.IMPORT)
+	log.Println("Organization created successfully.")
+	return nil
+}
+
+func updateOrganization(localOrganization localOrganization, deployedOrganization organization,
+	references *organizationReferences) error {
+
+	organization := localOrganization.organization
+	utils.CheckImportContent(utils.ORGANIZATIONS, organization.Name, localOrganization.content)
+	if organization.ParentName != deployedOrganization.ParentName {
+		return fmt.Errorf("the parent of the organization cannot be changed from: %q to: %q",
+			deployedOrganization.ParentName, organization.ParentName)
+	}
+	if utils.IsImportStateUnchanged(utils.ORGANIZATIONS, organization.Name, localOrganization.content) {
+		log.Println("Organization is unchanged since the last import. Skipping update: " + organization.Name)
+		utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.UNCHANGED)
+		return nil
+	}
+	if _, err := references.getAppIds(organization.SharedApplications); err != nil {
+		return fmt.Errorf("error when resolving the dependencies of organization: %s", err)
+	}
+	deployedOrganization.SharedApplications = references.sharedApplications[deployedOrganization.Id]
+	if !utils.FORCE_IMPORT && isOrganizationEqual(organization, deployedOrganization) {
+		log.Println("Organization is unchanged. Skipping update: " + organization.Name)
+		utils.UpdateImportState(utils.ORGANIZATIONS, organization.Name, localOrganization.content)
+		utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating organization: " + organization.Name)
+	payload := map[string]interface{}{
+		"name":        organization.Name,
+		"description": organization.Description,
+		"status":      deployedOrganization.Status,
+		"attributes":  getAttributes(organization),
+	}
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.ORGANIZATIONS, url.PathEscape(deployedOrganization.Id), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating organization: %s", err)
+	}
+	if err := references.updateSharedApplications(deployedOrganization.Id, organization.SharedApplications); err != nil {
+		return err
+	}
+	utils.UpdateImportState(utils.ORGANIZATIONS, organization.Name, localOrganization.content)
+	utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.UPDATE)
+	log.Println("Organization updated successfully.")
+	return nil
+}
+
+func getAttributes(organization organization) []organizationAttribute {
+
+	// The attributes are sent as an empty list to remove the deployed attributes.
+	if organization.Attributes == nil {
+		return []organizationAttribute{}
+	}
+	return organization.Attributes
+}
+
+// Share the applications in the local file with the organization, and stop sharing the applications removed
+// from the file, similar to the other attributes of the organization.
+func (references *organizationReferences) updateSharedApplications(organizationId string, appNames []string) error {
+
+	deployedAppNames := references.sharedApplications[organizationId]
+	if len(appNames) == 0 && len(deployedAppNames) == 0 {
+		return nil
+	}
+	if references.rootOrganizationId == "" {
+		return fmt.Errorf("the id of the root organization is not found. Add the id to the %s keyword config "+
+			"with the name: %s", utils.ORGANIZATION_IDS_CONFIG, utils.SERVER_CONFIGS.TenantDomain)
+	}
+	for _, appName := range appNames {
+		if utils.Contains(deployedAppNames, appName) {
+			continue
+		}
+		payload := map[string]interface{}{
+			"shareWithAllChildren": false,
+			"sharedOrganizations":  []string{organizationId},
+		}
+		_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.ORGANIZATIONS,
+			getApplicationSharingPath(references.rootOrganizationId, references.appIds[appName])+"/share", payload)
+		if err != nil {
+			return fmt.Errorf("error when sharing the application: %s. %s", appName, err)
+		}
+	}
+	for _, appName := range deployedAppNames {
+		if utils.Contains(appNames, appName) {
+			continue
+		}
+		log.Println("Shared application not found locally. Stop sharing the application: ", appName)
+		_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodDelete, utils.ORGANIZATIONS,
+			getApplicationSharingPath(references.rootOrganizationId, references.appIds[appName])+
+				"/shared-organizations/"+url.PathEscape(organizationId), nil)
+		if err != nil && !errors.Is(err, utils.ErrResourceNotFound) {
+			return fmt.Errorf("error when stop sharing the application: %s. %s", appName, err)
+		}
+	}
+	references.sharedApplications[organizationId] = appNames
+	return nil
+}
+
+func removeDeletedDeployedOrganizations(deployedOrganizations []organization, localOrganizations []localOrganization) {
+
+	// Remove deployed organizations that do not exist locally.
+	var organizationsToDelete []organization
+deployedResources:
+	for _, organization := range deployedOrganizations {
+		for _, localOrganization := range localOrganizations {
+			if organization.Name == localOrganization.organization.Name {
+				continue deployedResources
+			}
+		}
+		if utils.IsResourceExcluded(organization.Name, utils.TOOL_CONFIGS.OrganizationConfigs) {
+			log.Printf("Organization: %s is excluded from deletion.\n", organization.Name)
+			continue
+		}
+		organizationsToDelete = append(organizationsToDelete, organization)
+	}
+
+	var organizationNames []string
+	for _, organization := range organizationsToDelete {
+		organizationNames = append(organizationNames, organization.Name)
+	}
+	if !utils.ConfirmDeletion(utils.ORGANIZATIONS, organizationNames) {
+		return
+	}
+
+	// An organization with child organizations cannot be deleted, so the children are deleted first.
+	organizationsToDelete = sortOrganizationsByHierarchy(organizationsToDelete)
+	for i := len(organizationsToDelete) - 1; i >= 0; i-- {
+		organization := organizationsToDelete[i]
+		log.Println("Organization not found locally. Deleting organization: ", organization.Name)
+		utils.EmitResourceStarted(utils.ORGANIZATIONS, organization.Name, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), url.PathEscape(organization.Id), utils.ORGANIZATIONS)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ORGANIZATIONS, organization.Name)
+			log.Println("Error deleting organization: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.ORGANIZATIONS, organization.Name, utils.DELETE)
+	}
+}
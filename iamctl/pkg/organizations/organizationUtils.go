@@ -0,0 +1,304 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package organizations
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const ORGANIZATION_LIST_PAGE_SIZE = 100
+
+// Id of the root organization of the super tenant, which is the same in all environments.
+const SUPER_TENANT_ROOT_ORGANIZATION_ID = "10084a8d-113f-4211-a0d5-efe36b082211"
+
+type organizationAttribute struct {
+	Key   string `json:"key" yaml:"key"`
+	Value string `json:"value" yaml:"value"`
+}
+
+type organizationParent struct {
+	Id string `json:"id"`
+}
+
+// The parent and the shared applications of an organization are referred by the ids in the server, which differ
+// between environments. They are kept in the local files by the names.
+type organization struct {
+	Id                 string                  `json:"id,omitempty" yaml:"-"`
+	Name               string                  `json:"name" yaml:"name"`
+	Description        string                  `json:"description,omitempty" yaml:"description,omitempty"`
+	Type               string                  `json:"type,omitempty" yaml:"type,omitempty"`
+	Status             string                  `json:"status,omitempty" yaml:"-"`
+	Parent             organizationParent      `json:"parent,omitempty" yaml:"-"`
+	ParentName         string                  `json:"-" yaml:"parent,omitempty"`
+	Attributes         []organizationAttribute `json:"attributes,omitempty" yaml:"attributes,omitempty"`
+	SharedApplications []string                `json:"-" yaml:"sharedApplications,omitempty"`
+}
+
+type organizationListResponse struct {
+	Organizations []organization `json:"organizations"`
+	Links         []struct {
+		Href string `json:"href"`
+		Rel  string `json:"rel"`
+	} `json:"links"`
+}
+
+func getOrganizationList() ([]organization, error) {
+
+	// Organizations of all levels of the hierarchy are listed with the recursive query.
+	var organizations []organization
+	query := url.Values{}
+	query.Set("recursive", "true")
+	query.Set("limit", strconv.Itoa(ORGANIZATION_LIST_PAGE_SIZE))
+	for {
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.ORGANIZATIONS, "?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving organization list. %w", err)
+		}
+		var response organizationListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved organization list. %w", err)
+		}
+		organizations = append(organizations, response.Organizations...)
+
+		// The organizations are paginated with a cursor given in the link to the next page.
+		after := getNextPageCursor(response)
+		if after == "" || len(response.Organizations) == 0 {
+			return organizations, nil
+		}
+		query.Set("after", after)
+	}
+}
+
+func getNextPageCursor(response organizationListResponse) string {
+
+	for _, link := range response.Links {
+		if link.Rel != "next" {
+			continue
+		}
+		nextUrl, err := url.Parse(link.Href)
+		if err != nil {
+			return ""
+		}
+		return nextUrl.Query().Get("after")
+	}
+	return ""
+}
+
+func getOrganization(organizationId string) (organization, error) {
+
+	var organizationDetails organization
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.ORGANIZATIONS, url.PathEscape(organizationId), nil)
+	if err != nil {
+		return organizationDetails, fmt.Errorf("error while retrieving the organization. %w", err)
+	}
+	err = json.Unmarshal(body, &organizationDetails)
+	if err != nil {
+		return organizationDetails, fmt.Errorf("error when unmarshalling the retrieved organization. %w", err)
+	}
+	return organizationDetails, nil
+}
+
+// Get the details of the deployed organizations, with the names of the parent organizations. The list of the
+// organizations does not contain the parents, so each organization is retrieved separately.
+func getDeployedOrganizations() ([]organization, error) {
+
+	organizations, err := getOrganizationList()
+	if err != nil {
+		return nil, err
+	}
+	var deployedOrganizations []organization
+	organizationNames := make(map[string]string)
+	for _, organization := range organizations {
+		organizationDetails, err := getOrganization(organization.Id)
+		if err != nil {
+			return nil, fmt.Errorf("error when retrieving the organization: %s. %s", organization.Name, err)
+		}
+		organizationNames[organizationDetails.Id] = organizationDetails.Name
+		deployedOrganizations = append(deployedOrganizations, organizationDetails)
+	}
+	for i, organization := range deployedOrganizations {
+		// Organizations at the top level of the hierarchy have the root organization as the parent.
+		deployedOrganizations[i].ParentName = organizationNames[organization.Parent.Id]
+	}
+	return deployedOrganizations, nil
+}
+
+// Get the id of the root organization of the tenant, which is the parent of the organizations at the top level
+// of the hierarchy. The id can be set in the ORGANIZATION_IDS keyword config with the tenant domain as the name.
+func getRootOrganizationId(deployedOrganizations []organization) string {
+
+	if organizationId := getMappedOrganizationId(utils.SERVER_CONFIGS.TenantDomain); organizationId != "" {
+		return organizationId
+	}
+	for _, organization := range deployedOrganizations {
+		if organization.ParentName == "" && organization.Parent.Id != "" {
+			return organization.Parent.Id
+		}
+	}
+	if utils.SERVER_CONFIGS.TenantDomain == utils.DEFAULT_TENANT_DOMAIN {
+		return SUPER_TENANT_ROOT_ORGANIZATION_ID
+	}
+	return ""
+}
+
+// Get the id of an organization in the target environment from the ORGANIZATION_IDS keyword config. The config is
+// used for the organizations that are not managed by the tool, as the ids differ between environments.
+func getMappedOrganizationId(organizationName string) string {
+
+	organizationId, _ := utils.KEYWORD_CONFIGS.OrganizationIds[organizationName].(string)
+	return organizationId
+}
+
+// Sort the organizations so that the parent of an organization comes before it. Organizations with a parent that
+// is not in the given list are placed at the top level, in the given order. The names of the organizations that
+// cannot be sorted, due to a cycle in the parents, are returned separately.
+func sortByHierarchy(organizationNames []string, parentNames map[string]string) ([]string, []string) {
+
+	children := make(map[string][]string)
+	var sortedNames []string
+	for _, organizationName := range organizationNames {
+		parentName := parentNames[organizationName]
+		if parentName == "" || !utils.Contains(organizationNames, parentName) {
+			sortedNames = append(sortedNames, organizationName)
+			continue
+		}
+		children[parentName] = append(children[parentName], organizationName)
+	}
+	for i := 0; i < len(sortedNames); i++ {
+		sortedNames = append(sortedNames, children[sortedNames[i]]...)
+	}
+
+	var cyclicNames []string
+	for _, organizationName := range organizationNames {
+		if !utils.Contains(sortedNames, organizationName) {
+			cyclicNames = append(cyclicNames, organizationName)
+		}
+	}
+	return sortedNames, cyclicNames
+}
+
+// Get the names of the applications shared with each organization, by the id of the organization.
+func getSharedApplications(rootOrganizationId string) (map[string][]string, error) {
+
+	appIds, err := applications.GetDeployedAppIds()
+	if err != nil {
+		return nil, err
+	}
+	sharedApplications := make(map[string][]string)
+	for appName, appId := range appIds {
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.ORGANIZATIONS, getApplicationSharingPath(
+			rootOrganizationId, appId)+"/shared-organizations", nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving the shared organizations of the application: %s. %w",
+				appName, err)
+		}
+		var response organizationListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved shared organizations. %w", err)
+		}
+		for _, organization := range response.Organizations {
+			sharedApplications[organization.Id] = append(sharedApplications[organization.Id], appName)
+		}
+	}
+	for _, appNames := range sharedApplications {
+		sort.Strings(appNames)
+	}
+	return sharedApplications, nil
+}
+
+func getApplicationSharingPath(rootOrganizationId string, appId string) string {
+
+	return url.PathEscape(rootOrganizationId) + "/applications/" + url.PathEscape(appId)
+}
+
+func isOrganizationEqual(localOrganization organization, deployedOrganization organization) bool {
+
+	return reflect.DeepEqual(normalizeOrganization(localOrganization), normalizeOrganization(deployedOrganization))
+}
+
+// Get a copy of the organization that can be compared, without the server generated fields and with the
+// attributes and the shared applications sorted. The type cannot be changed after the organization is created.
+func normalizeOrganization(organization organization) organization {
+
+	normalized := organization
+	normalized.Id = ""
+	normalized.Type = ""
+	normalized.Status = ""
+	normalized.Parent = organizationParent{}
+	normalized.Attributes = append([]organizationAttribute{}, organization.Attributes...)
+	sort.SliceStable(normalized.Attributes, func(i, j int) bool {
+		return normalized.Attributes[i].Key < normalized.Attributes[j].Key
+	})
+	normalized.SharedApplications = append([]string{}, organization.SharedApplications...)
+	sort.Strings(normalized.SharedApplications)
+	return normalized
+}
+
+func getOrganizationKeywordMapping(organizationName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(organizationName, utils.KEYWORD_CONFIGS.OrganizationConfigs)
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localOrganizationNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.ORGANIZATIONS),
+		utils.TOOL_CONFIGS.OrganizationConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	organizations, err := getOrganizationList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedOrganizations []utils.DeployedResource
+	for _, organization := range organizations {
+		isDeletable := !utils.IsResourceExcluded(organization.Name, utils.TOOL_CONFIGS.OrganizationConfigs)
+		deployedOrganizations = append(deployedOrganizations,
+			utils.DeployedResource{Name: organization.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.ORGANIZATIONS, localOrganizationNames, deployedOrganizations,
+		utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the organizations that do not exist in the target environment. The folders of the
+// resources exported from a deleted sub organization are included.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	organizations, err := getOrganizationList()
+	if err != nil {
+		return nil, err
+	}
+	var organizationNames []string
+	for _, organization := range organizations {
+		organizationNames = append(organizationNames, organization.Name)
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.ORGANIZATIONS), organizationNames)
+}
@@ -0,0 +1,135 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package roles
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Group in the response of the groups API of Microsoft Graph.
+type azureAdGroup struct {
+	Id              string `json:"id"`
+	DisplayName     string `json:"displayName"`
+	SecurityEnabled *bool  `json:"securityEnabled"`
+}
+
+type azureAdGroupList struct {
+	Value []azureAdGroup `json:"value"`
+}
+
+// Create a role in the organization audience for each group of an Azure AD group export. The export is the response of
+// the groups API of Microsoft Graph, or the list of groups in the value attribute of the response.
+func ImportAzureAdGroups(filePath string) error {
+
+	groups, err := readAzureAdGroups(filePath)
+	if err != nil {
+		return err
+	}
+	deployedRoles, err := getRoleList()
+	if err != nil {
+		return fmt.Errorf("error when retrieving the deployed roles: %s", err)
+	}
+	deployedRoleKeys := make(map[string]bool)
+	for _, role := range deployedRoles {
+		deployedRoleKeys[role.getKey()] = true
+	}
+
+	// Groups mapped to the same role import the role once.
+	importedRoles := make(map[string]bool)
+	log.Printf("Importing roles from %d Azure AD groups...", len(groups))
+	utils.StartResourceProgress(utils.ROLES, len(groups))
+	for _, group := range groups {
+		if group.SecurityEnabled != nil && !*group.SecurityEnabled {
+			log.Println("Skipping the Azure AD group that is not a security group: " + group.DisplayName)
+			continue
+		}
+		roleName, ok := getAzureAdGroupRoleName(group)
+		if !ok {
+			log.Println("Skipping the Azure AD group that is not mapped to a role: " + group.DisplayName)
+			continue
+		}
+		if utils.IsSystemRole(roleName) || utils.IsResourceExcluded(roleName, utils.TOOL_CONFIGS.RoleConfigs) {
+			log.Println("Skipping the excluded role: " + roleName)
+			continue
+		}
+		if importedRoles[roleName] {
+			log.Printf("Role: %s is already imported for another Azure AD group. Skipping the group: %s", roleName, group.DisplayName)
+			continue
+		}
+		importedRoles[roleName] = true
+		if deployedRoleKeys[roleName] {
+			log.Printf("Role: %s of the Azure AD group: %s already exists. Skipping the role.", roleName, group.DisplayName)
+			utils.UpdateSuccessSummary(utils.ROLES, roleName, utils.UNCHANGED)
+			continue
+		}
+
+		utils.EmitResourceStarted(utils.ROLES, roleName, utils.IMPORT)
+		log.Printf("Creating new role: %s for the Azure AD group: %s", roleName, group.DisplayName)
+		_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.ROLES, "", buildRolePayload(role{DisplayName: roleName}, ""))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, roleName)
+			log.Printf("Error when creating role: %s. %s", roleName, err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.ROLES, roleName, utils.IMPORT)
+		log.Println("Role created successfully.")
+	}
+	return nil
+}
+
+func readAzureAdGroups(filePath string) ([]azureAdGroup, error) {
+
+	fileContent, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the Azure AD group export: %s", err)
+	}
+	var groups []azureAdGroup
+	if strings.HasPrefix(strings.TrimSpace(string(fileContent)), "[") {
+		err = json.Unmarshal(fileContent, &groups)
+	} else {
+		var groupList azureAdGroupList
+		err = json.Unmarshal(fileContent, &groupList)
+		groups = groupList.Value
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure AD group export: %s", err)
+	}
+	return groups, nil
+}
+
+// Get the name of the role of an Azure AD group. The AZURE_AD_GROUP_MAPPINGS config under roles maps the name or the
+// id of a group to the name of the role, and a group mapped to an empty name is not imported. Groups that are not
+// mapped are imported with the name of the group.
+func getAzureAdGroupRoleName(group azureAdGroup) (string, bool) {
+
+	groupMappings, _ := utils.TOOL_CONFIGS.RoleConfigs[utils.AZURE_AD_GROUP_MAPPINGS_CONFIG].(map[string]interface{})
+	for _, key := range []string{group.Id, group.DisplayName} {
+		if roleName, ok := groupMappings[key].(string); ok && key != "" {
+			return roleName, roleName != ""
+		}
+	}
+	return group.DisplayName, group.DisplayName != ""
+}
@@ -0,0 +1,122 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package roles
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each role to a separate file in the Roles folder.
+	log.Println("Exporting roles...")
+	exportFilePath = filepath.Join(exportFilePath, utils.ROLES)
+
+	if utils.IsResourceTypeExcluded(utils.ROLES) {
+		return
+	}
+	roles, err := getRoleList()
+	if err != nil {
+		log.Println("Error while retrieving role list.", err)
+		return
+	}
+
+	// Skip the system roles since they are created by the server in each environment.
+	var exportedRoles []role
+	var roleFileNames []string
+	for _, role := range roles {
+		roleKey := role.getKey()
+		if utils.IsSystemRole(roleKey) {
+			utils.LogDebug("Skipping system role: " + roleKey)
+			continue
+		}
+		exportedRoles = append(exportedRoles, role)
+		roleFileNames = append(roleFileNames, getRoleFileName(roleKey))
+	}
+
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, roleFileNames)
+	}
+
+	sort.SliceStable(exportedRoles, func(i, j int) bool {
+		return utils.GetResourcePriority(exportedRoles[i].getKey()) < utils.GetResourcePriority(exportedRoles[j].getKey())
+	})
+	utils.StartResourceProgress(utils.ROLES, len(exportedRoles))
+	for _, role := range exportedRoles {
+		roleKey := role.getKey()
+		if utils.IsResourceExcluded(roleKey, utils.TOOL_CONFIGS.RoleConfigs) {
+			utils.ReportSkippedResource(utils.ROLES, roleKey, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.ROLES, roleKey)
+			continue
+		}
+		if !utils.IsModifiedSince(role.Meta.LastModified) {
+			log.Println("Role is not modified since the given time. Skipping role: ", roleKey)
+			utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.UNCHANGED)
+			continue
+		}
+		log.Println("Exporting role: ", roleKey)
+		utils.EmitResourceStarted(utils.ROLES, roleKey, utils.EXPORT)
+		err := exportRole(role.Id, roleKey, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, roleKey)
+			log.Printf("Error while exporting role: %s. %s", roleKey, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.EXPORT)
+			log.Println("Role exported successfully: ", roleKey)
+		}
+	}
+}
+
+func exportRole(roleId string, roleKey string, outputDirPath string) error {
+
+	// The role list does not contain the permissions of the roles.
+	role, err := getRole(roleId)
+	if err != nil {
+		return err
+	}
+	// The organization of the audience is the organization of the target environment when importing.
+	if !strings.EqualFold(role.Audience.Type, AUDIENCE_APPLICATION) {
+		role.Audience.Display = ""
+	}
+	content, err := yaml.Marshal(role)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	fileName := getRoleFileName(roleKey)
+	exportedFileName := filepath.Join(outputDirPath, fileName+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getRoleKeywordMapping(fileName), utils.ROLES)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
@@ -0,0 +1,318 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package roles
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiresources "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/apiResources"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+type localRole struct {
+	fileName string
+	content  string
+	role     role
+}
+
+// Roles of an application audience are imported after the applications, if the application does not exist yet.
+var pendingRoles []localRole
+
+// Scopes of the API resources in the target environment, retrieved when the first role is validated.
+var availableScopes map[string]bool
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.ROLES)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.ROLES) {
+		return
+	}
+
+	log.Println("Importing roles...")
+	// The scopes are retrieved again for each run, since the target environment may change between the runs.
+	availableScopes = nil
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing roles: ", err)
+		return
+	}
+	roles, err := getRoleList()
+	if err != nil {
+		log.Println("Error importing roles: ", err)
+		return
+	}
+	deployedRoles := make(map[string]role)
+	for _, role := range roles {
+		deployedRoles[role.getKey()] = role
+	}
+	if utils.IsDeleteAllowed() {
+		removeDeletedDeployedRoles(roles, files)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+	utils.StartResourceProgress(utils.ROLES, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		if !utils.IsResourceIncluded(fileName) {
+			utils.AddFilteredResourceToSummary(utils.ROLES, fileName)
+			continue
+		}
+		localRole, err := readLocalRole(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, fileName)
+			log.Printf("Invalid file configurations for role: %s. %s", fileName, err)
+			continue
+		}
+		roleKey := localRole.role.getKey()
+		if utils.IsSystemRole(roleKey) {
+			log.Println("Skipping system role: " + roleKey)
+			continue
+		}
+		if utils.IsResourceExcluded(roleKey, utils.TOOL_CONFIGS.RoleConfigs) {
+			utils.ReportSkippedResource(utils.ROLES, roleKey, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.ROLES, roleKey)
+			continue
+		}
+		utils.EmitResourceStarted(utils.ROLES, roleKey, utils.IMPORT)
+		deployedRole, isUpdate := deployedRoles[roleKey]
+		if err := importRole(localRole, deployedRole, isUpdate, true); err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, roleKey)
+			log.Printf("Error when importing role: %s. %s", roleKey, err)
+		}
+	}
+}
+
+func ImportPendingRoles() {
+
+	// Import the roles of the applications that were created after the roles were imported.
+	if len(pendingRoles) == 0 {
+		return
+	}
+	log.Println("Importing roles of the created applications...")
+	roles := pendingRoles
+	pendingRoles = nil
+	for _, localRole := range roles {
+		roleKey := localRole.role.getKey()
+		if err := importRole(localRole, role{}, false, false); err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, roleKey)
+			log.Printf("Error when importing role: %s. %s", roleKey, err)
+		}
+	}
+}
+
+func readLocalRole(importFilePath string) (localRole, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return localRole{}, fmt.Errorf("error when reading the file for role: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getRoleKeywordMapping(fileName))
+
+	var role role
+	err = yaml.Unmarshal([]byte(modifiedFileData), &role)
+	if err != nil {
+		return localRole{}, fmt.Errorf("invalid file content for role: %s", err)
+	}
+	if role.DisplayName == "" {
+		return localRole{}, fmt.Errorf("the displayName attribute is required")
+	}
+	if role.Audience.Type == "" {
+		role.Audience.Type = AUDIENCE_ORGANIZATION
+	}
+	if strings.EqualFold(role.Audience.Type, AUDIENCE_APPLICATION) && role.Audience.Display == "" {
+		return localRole{}, fmt.Errorf("the application name is required in the display attribute of the audience")
+	}
+	return localRole{fileName: fileName, content: modifiedFileData, role: role}, nil
+}
+
+func importRole(localRole localRole, deployedRole role, isUpdate bool, allowPending bool) error {
+
+	roleKey := localRole.role.getKey()
+	utils.CheckImportContent(utils.ROLES, roleKey, localRole.content)
+	if isUpdate && utils.IsImportStateUnchanged(utils.ROLES, roleKey, localRole.content) {
+		log.Println("Role is unchanged since the last import. Skipping update: " + roleKey)
+		utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.UNCHANGED)
+		return nil
+	}
+
+	// Validate the permissions of the role, so that a missing API resource fails only the roles that refer to it.
+	permissions := getPermissionValues(localRole.role.Permissions)
+	if len(permissions) > 0 && availableScopes == nil {
+		scopes, err := apiresources.GetAvailableScopes()
+		if err != nil {
+			return err
+		}
+		availableScopes = scopes
+	}
+	if missingScopes := findMissingScopes(permissions, availableScopes); len(missingScopes) > 0 {
+		return fmt.Errorf("the following API resource scopes of the role are not available in the target environment: %s",
+			strings.Join(missingScopes, ", "))
+	}
+
+	if isUpdate {
+		return updateRole(localRole, deployedRole.Id)
+	}
+
+	var appId string
+	if strings.EqualFold(localRole.role.Audience.Type, AUDIENCE_APPLICATION) {
+		var err error
+		appId, err = getApplicationId(localRole.role.Audience.Display)
+		if err != nil {
+			return err
+		}
+		if appId == "" && allowPending {
+			log.Printf("Application: %s of role: %s does not exist. The role will be imported after the applications.",
+				localRole.role.Audience.Display, roleKey)
+			pendingRoles = append(pendingRoles, localRole)
+			return nil
+		} else if appId == "" {
+			return fmt.Errorf("application: %s of the role audience does not exist in the target environment",
+				localRole.role.Audience.Display)
+		}
+	}
+
+	log.Println("Creating new role: " + roleKey)
+	_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.ROLES, "", buildRolePayload(localRole.role, appId))
+	if err != nil {
+		return fmt.Errorf("error when creating role: %s", err)
+	}
+	utils.UpdateImportState(utils.ROLES, roleKey, localRole.content)
+	utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.IMPORT)
+	log.Println("Role created successfully.")
+	return nil
+}
+
+func updateRole(localRole localRole, roleId string) error {
+
+	roleKey := localRole.role.getKey()
+	deployedRole, err := getRole(roleId)
+	if err == nil && !utils.FORCE_IMPORT &&
+		reflect.DeepEqual(getPermissionValues(deployedRole.Permissions), getPermissionValues(localRole.role.Permissions)) {
+		log.Println("Role is unchanged. Skipping update: " + roleKey)
+		utils.UpdateImportState(utils.ROLES, roleKey, localRole.content)
+		utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.UNCHANGED)
+		return nil
+	}
+
+	// The name and the audience of a role are not changed, since the role is matched by them.
+	log.Println("Updating role: " + roleKey)
+	payload := map[string]interface{}{
+		"schemas": []string{SCIM_PATCH_SCHEMA},
+		"Operations": []map[string]interface{}{{
+			"op":    "replace",
+			"path":  "permissions",
+			"value": buildPermissionsPayload(localRole.role.Permissions),
+		}},
+	}
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.ROLES, url.PathEscape(roleId), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating role: %s", err)
+	}
+	utils.UpdateImportState(utils.ROLES, roleKey, localRole.content)
+	utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.UPDATE)
+	log.Println("Role updated successfully.")
+	return nil
+}
+
+func buildRolePayload(role role, appId string) map[string]interface{} {
+
+	// Roles are created in the organization audience when the audience is not given.
+	payload := map[string]interface{}{
+		"schemas":     []string{SCIM_ROLE_SCHEMA},
+		"displayName": role.DisplayName,
+		"permissions": buildPermissionsPayload(role.Permissions),
+	}
+	if appId != "" {
+		payload["audience"] = map[string]string{"type": AUDIENCE_APPLICATION, "value": appId}
+	}
+	return payload
+}
+
+func buildPermissionsPayload(permissions []rolePermission) []map[string]string {
+
+	payload := []map[string]string{}
+	for _, permission := range getPermissionValues(permissions) {
+		payload = append(payload, map[string]string{"value": permission})
+	}
+	return payload
+}
+
+func removeDeletedDeployedRoles(deployedRoles []role, localFiles []os.FileInfo) {
+
+	// Remove deployed roles that do not exist locally.
+	var rolesToDelete []role
+deployedResources:
+	for _, role := range deployedRoles {
+		roleKey := role.getKey()
+		for _, file := range localFiles {
+			if getRoleFileName(roleKey) == utils.GetFileInfo(file.Name()).ResourceName {
+				continue deployedResources
+			}
+		}
+		if utils.IsSystemRole(roleKey) || utils.IsResourceExcluded(roleKey, utils.TOOL_CONFIGS.RoleConfigs) {
+			continue
+		}
+		rolesToDelete = append(rolesToDelete, role)
+	}
+
+	var roleKeys []string
+	for _, role := range rolesToDelete {
+		roleKeys = append(roleKeys, role.getKey())
+	}
+	if !utils.ConfirmDeletion(utils.ROLES, roleKeys) {
+		return
+	}
+	for _, role := range rolesToDelete {
+		roleKey := role.getKey()
+		log.Println("Role not found locally. Deleting role: ", roleKey)
+		utils.EmitResourceStarted(utils.ROLES, roleKey, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), role.Id, utils.ROLES)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.ROLES, roleKey)
+			log.Println("Error deleting role: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.ROLES, roleKey, utils.DELETE)
+	}
+}
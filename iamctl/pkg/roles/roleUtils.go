@@ -0,0 +1,226 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package roles
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const ROLE_LIST_PAGE_SIZE = 100
+const SCIM_ROLE_SCHEMA = "urn:ietf:params:scim:schemas:extension:2.0:Role"
+const SCIM_PATCH_SCHEMA = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+const AUDIENCE_ORGANIZATION = "organization"
+const AUDIENCE_APPLICATION = "application"
+
+type roleAudience struct {
+	Value   string `json:"value,omitempty" yaml:"-"`
+	Display string `json:"display,omitempty" yaml:"display,omitempty"`
+	Type    string `json:"type" yaml:"type"`
+}
+
+type rolePermission struct {
+	Value   string `json:"value" yaml:"value"`
+	Display string `json:"display,omitempty" yaml:"display,omitempty"`
+}
+
+type role struct {
+	Id          string           `json:"id,omitempty" yaml:"-"`
+	DisplayName string           `json:"displayName" yaml:"displayName"`
+	Audience    roleAudience     `json:"audience" yaml:"audience"`
+	Permissions []rolePermission `json:"permissions" yaml:"permissions"`
+	Meta        struct {
+		LastModified string `json:"lastModified"`
+	} `json:"meta" yaml:"-"`
+}
+
+type roleListResponse struct {
+	TotalResults int    `json:"totalResults"`
+	Resources    []role `json:"Resources"`
+}
+
+type applicationListResponse struct {
+	Applications []struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"applications"`
+}
+
+func getRoleList() ([]role, error) {
+
+	var roles []role
+	startIndex := 1
+	for {
+		query := url.Values{}
+		query.Set("startIndex", strconv.Itoa(startIndex))
+		query.Set("count", strconv.Itoa(ROLE_LIST_PAGE_SIZE))
+		if filter := utils.GetNamespaceFilter("displayName"); filter != "" {
+			query.Set("filter", filter)
+		}
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.ROLES, "?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving role list. %w", err)
+		}
+		var response roleListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved role list. %w", err)
+		}
+
+		roles = append(roles, response.Resources...)
+		startIndex += len(response.Resources)
+		if len(response.Resources) == 0 || startIndex > response.TotalResults {
+			return roles, nil
+		}
+	}
+}
+
+func getRole(roleId string) (role, error) {
+
+	var roleDetails role
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.ROLES, url.PathEscape(roleId), nil)
+	if err != nil {
+		return roleDetails, fmt.Errorf("error while retrieving the role. %w", err)
+	}
+	err = json.Unmarshal(body, &roleDetails)
+	if err != nil {
+		return roleDetails, fmt.Errorf("error when unmarshalling the retrieved role. %w", err)
+	}
+	return roleDetails, nil
+}
+
+func getApplicationId(appName string) (string, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.APPLICATIONS, "?"+url.Values{"filter": {"name eq " + appName}}.Encode(), nil)
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving the application: %s. %w", appName, err)
+	}
+	var response applicationListResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return "", fmt.Errorf("error when unmarshalling the retrieved application list. %w", err)
+	}
+	for _, app := range response.Applications {
+		if app.Name == appName {
+			return app.Id, nil
+		}
+	}
+	return "", nil
+}
+
+// Get the keys of the deployed roles by the role id, for the resources that refer to roles by the id.
+func GetDeployedRoleKeys() (map[string]string, error) {
+
+	roles, err := getRoleList()
+	if err != nil {
+		return nil, err
+	}
+	roleKeys := make(map[string]string)
+	for _, role := range roles {
+		roleKeys[role.Id] = role.getKey()
+	}
+	return roleKeys, nil
+}
+
+// Roles of an application audience are identified by the application name, since a role name is only unique
+// within the audience. Ex: hr-portal/viewer
+func GetRoleKey(roleName string, audienceType string, audienceName string) string {
+
+	if strings.EqualFold(audienceType, AUDIENCE_APPLICATION) {
+		return audienceName + "/" + roleName
+	}
+	return roleName
+}
+
+func (role role) getKey() string {
+
+	return GetRoleKey(role.DisplayName, role.Audience.Type, role.Audience.Display)
+}
+
+func getRoleFileName(roleKey string) string {
+
+	return strings.ReplaceAll(roleKey, "/", "_")
+}
+
+func getPermissionValues(permissions []rolePermission) []string {
+
+	values := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		values = append(values, permission.Value)
+	}
+	sort.Strings(values)
+	return values
+}
+
+func findMissingScopes(permissions []string, availableScopes map[string]bool) []string {
+
+	var missingScopes []string
+	for _, permission := range permissions {
+		if !availableScopes[permission] {
+			missingScopes = append(missingScopes, permission)
+		}
+	}
+	return missingScopes
+}
+
+func getRoleKeywordMapping(roleFileName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(roleFileName, utils.KEYWORD_CONFIGS.RoleConfigs)
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localRoleNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.ROLES), utils.TOOL_CONFIGS.RoleConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	roles, err := getRoleList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedRoles []utils.DeployedResource
+	for _, role := range roles {
+		roleKey := role.getKey()
+		isDeletable := !utils.IsSystemRole(roleKey) && !utils.IsResourceExcluded(roleKey, utils.TOOL_CONFIGS.RoleConfigs)
+		deployedRoles = append(deployedRoles, utils.DeployedResource{Name: getRoleFileName(roleKey), Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.ROLES, localRoleNames, deployedRoles, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the roles that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	roles, err := getRoleList()
+	if err != nil {
+		return nil, err
+	}
+	var roleFileNames []string
+	for _, role := range roles {
+		roleFileNames = append(roleFileNames, getRoleFileName(role.getKey()))
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.ROLES), roleFileNames)
+}
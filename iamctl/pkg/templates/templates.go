@@ -0,0 +1,63 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Skeleton files of the resources, in the format of the exported files. The templates are validated against the
+// struct types of the tool by the tests, so update them along with the file formats.
+//
+//go:embed apps/*.yml idps/*.yml
+var templateFS embed.FS
+
+var APP_TEMPLATE_TYPES = []string{"oauth2", "oidc", "saml"}
+var IDP_TEMPLATE_TYPES = []string{"oidc", "saml", "google", "github"}
+
+// Get the skeleton file of an application or identity provider of the given type.
+func GetTemplate(resourceType string, templateType string) ([]byte, error) {
+
+	var templateDir string
+	var templateTypes []string
+	switch resourceType {
+	case utils.APPLICATIONS:
+		templateDir, templateTypes = "apps", APP_TEMPLATE_TYPES
+	case utils.IDENTITY_PROVIDERS:
+		templateDir, templateTypes = "idps", IDP_TEMPLATE_TYPES
+	default:
+		return nil, fmt.Errorf("templates are not available for the resource type: %s", resourceType)
+	}
+
+	templateType = strings.ToLower(templateType)
+	if !utils.Contains(templateTypes, templateType) {
+		return nil, fmt.Errorf("unknown template type: %s. Supported types: %s", templateType,
+			strings.Join(templateTypes, ", "))
+	}
+	content, err := templateFS.ReadFile(path.Join(templateDir, templateType+".yml"))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the template: %s", err)
+	}
+	return content, nil
+}
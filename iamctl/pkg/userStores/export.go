@@ -25,6 +25,7 @@ import (
 	"mime"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
@@ -39,6 +40,7 @@ func ExportAll(exportFilePath string, format string) {
 	if utils.IsResourceTypeExcluded(utils.USERSTORES) {
 		return
 	}
+	utils.WarnUnfilteredSince(utils.USERSTORES)
 	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
 		os.MkdirAll(exportFilePath, 0700)
 	} else {
@@ -52,20 +54,31 @@ func ExportAll(exportFilePath string, format string) {
 		log.Println("Error: when exporting userstores.", err)
 	} else {
 		if !utils.AreSecretsExcluded(utils.TOOL_CONFIGS.ApplicationConfigs) {
-			log.Println("Warn: Secrets exclusion cannot be disabled for userstores. All secrets will be masked.")
+			utils.LogWarning(utils.WARNING_MASKED_SECRET, "Secrets exclusion cannot be disabled for userstores. All secrets will be masked.")
 		}
+		sort.SliceStable(userstores, func(i, j int) bool {
+			return utils.GetResourcePriority(userstores[i].Name) < utils.GetResourcePriority(userstores[j].Name)
+		})
+		utils.StartResourceProgress(utils.USERSTORES, len(userstores))
 		for _, userstore := range userstores {
 			if !utils.IsResourceExcluded(userstore.Name, utils.TOOL_CONFIGS.UserStoreConfigs) {
+				if !utils.IsTimeBudgetAvailable() {
+					utils.AddUnprocessedResourceToSummary(utils.USERSTORES, userstore.Name)
+					continue
+				}
 				log.Println("Exporting user store: ", userstore.Name)
+				utils.EmitResourceStarted(utils.USERSTORES, userstore.Name, utils.EXPORT)
 
 				err := exportUserStore(userstore.Id, exportFilePath, format)
 				if err != nil {
 					utils.UpdateFailureSummary(utils.USERSTORES, userstore.Name)
 					log.Printf("Error while exporting user store: %s. %s", userstore.Name, err)
 				} else {
-					utils.UpdateSuccessSummary(utils.USERSTORES, utils.EXPORT)
+					utils.UpdateSuccessSummary(utils.USERSTORES, userstore.Name, utils.EXPORT)
 					log.Println("User store exported successfully: ", userstore.Name)
 				}
+			} else {
+				utils.ReportSkippedResource(utils.USERSTORES, userstore.Name, utils.JUNIT_SKIPPED_EXCLUDED)
 			}
 		}
 	}
@@ -84,7 +97,7 @@ func exportUserStore(userStoreId string, outputDirPath string, format string) er
 		fileType = utils.MEDIA_TYPE_YAML
 	}
 
-	resp, err := utils.SendExportRequest(userStoreId, fileType, utils.USERSTORES, true)
+	resp, err := utils.SendExportRequest(utils.GetRequestContext(), userStoreId, fileType, utils.USERSTORES, true)
 	if err != nil {
 		return fmt.Errorf("error while exporting the identity provider: %s", err)
 	}
@@ -106,6 +119,7 @@ func exportUserStore(userStoreId string, outputDirPath string, format string) er
 
 	// Use the common mask for senstive data.
 	modifiedBody := []byte(strings.ReplaceAll(string(body), USERSTORE_SECRET_MASK, utils.SENSITIVE_FIELD_MASK))
+	modifiedBody = utils.MaskSensitiveFields(modifiedBody, utils.GetSensitiveFields())
 
 	userStoreKeywordMapping := getUserStoreKeywordMapping(fileInfo.ResourceName)
 	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, modifiedBody, userStoreKeywordMapping, utils.USERSTORES)
@@ -113,9 +127,5 @@ func exportUserStore(userStoreId string, outputDirPath string, format string) er
 		return fmt.Errorf("error while processing the exported content: %s", err)
 	}
 
-	err = ioutil.WriteFile(exportedFileName, modifiedFile, 0644)
-	if err != nil {
-		return fmt.Errorf("error when writing the exported content to file: %w", err)
-	}
-	return nil
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
 }
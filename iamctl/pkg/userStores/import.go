@@ -24,6 +24,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
@@ -45,56 +46,102 @@ func ImportAll(inputDirPath string) {
 		if err != nil {
 			log.Println("Error importing user stores: ", err)
 		}
-		if utils.TOOL_CONFIGS.AllowDelete {
+		if utils.IsDeleteAllowed() {
 			removeDeletedDeployedUserstores(files)
 		}
 	}
 
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(utils.GetFileInfo(files[i].Name()).ResourceName) <
+			utils.GetResourcePriority(utils.GetFileInfo(files[j].Name()).ResourceName)
+	})
+	utils.StartResourceProgress(utils.USERSTORES, len(files))
 	for _, file := range files {
 		userStoreFilePath := filepath.Join(importFilePath, file.Name())
-		userStoreName := strings.TrimSuffix(file.Name(), filepath.Ext(file.Name()))
+		userStoreName := utils.GetFileInfo(file.Name()).ResourceName
+		if !utils.IsResourceIncluded(userStoreName) {
+			utils.AddFilteredResourceToSummary(utils.USERSTORES, userStoreName)
+			continue
+		}
+		if !utils.IsResourceExcluded(userStoreName, utils.TOOL_CONFIGS.UserStoreConfigs) && !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.USERSTORES, userStoreName)
+			continue
+		}
 
 		if !utils.IsResourceExcluded(userStoreName, utils.TOOL_CONFIGS.UserStoreConfigs) {
 			userStoreId, err := getUserStoreId(userStoreFilePath)
 			if err != nil {
 				log.Printf("Invalid file configurations for user store: %s. %s", userStoreName, err)
 			} else {
+				utils.EmitResourceStarted(utils.USERSTORES, userStoreName, utils.IMPORT)
 				err := importUserStore(userStoreId, userStoreFilePath)
 				if err != nil {
 					log.Println("Error importing user store: ", err)
 				}
 			}
+		} else {
+			utils.ReportSkippedResource(utils.USERSTORES, userStoreName, utils.JUNIT_SKIPPED_EXCLUDED)
 		}
 	}
 }
 
 func importUserStore(userStoreId string, importFilePath string) error {
 
-	fileBytes, err := ioutil.ReadFile(importFilePath)
+	fileInfo := utils.GetFileInfo(importFilePath)
+	if userStoreId == "" && isPrimaryUserStore(fileInfo.ResourceName) {
+		log.Println("The PRIMARY user store cannot be created. Skipping user store: " + fileInfo.ResourceName)
+		return nil
+	}
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
 	if err != nil {
 		return fmt.Errorf("error when reading the file for user store: %s", err)
 	}
 
 	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
-	fileInfo := utils.GetFileInfo(importFilePath)
 	userStoreKeywordMapping := getUserStoreKeywordMapping(fileInfo.ResourceName)
 	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), userStoreKeywordMapping)
 
+	// The connection password is masked in the exported files and is resolved from the configs of the environment.
+	passwordResolved := false
+	if password := resolveConnectionPassword(fileInfo.ResourceName, userStoreKeywordMapping); password != "" {
+		modifiedFileData, passwordResolved, err = SetConnectionPassword(modifiedFileData, password)
+		if err != nil {
+			return err
+		}
+	}
+	utils.CheckImportContent(utils.USERSTORES, fileInfo.ResourceName, modifiedFileData)
+
 	if userStoreId == "" {
+		if !passwordResolved && strings.Contains(modifiedFileData, utils.SENSITIVE_FIELD_MASK) {
+			utils.UpdateFailureSummary(utils.USERSTORES, fileInfo.ResourceName)
+			return fmt.Errorf("connection password of the new user store: %s is masked. Set the password with the %s "+
+				"keyword or the %s environment variable", fileInfo.ResourceName, CONNECTION_PASSWORD_KEYWORD,
+				GetConnectionPasswordEnvName(fileInfo.ResourceName))
+		}
 		return importUserStoreOperation(importFilePath, modifiedFileData, fileInfo)
 	}
+	if utils.IsImportStateUnchanged(utils.USERSTORES, fileInfo.ResourceName, modifiedFileData) {
+		log.Println("User store is unchanged since the last import. Skipping update: " + fileInfo.ResourceName)
+		utils.UpdateSuccessSummary(utils.USERSTORES, fileInfo.ResourceName, utils.UNCHANGED)
+		return nil
+	}
 	return updateUserStoreOperation(userStoreId, importFilePath, modifiedFileData, fileInfo)
 }
 
 func importUserStoreOperation(importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
 	log.Println("Creating new user store: " + fileInfo.ResourceName)
-	err := utils.SendImportRequest(importFilePath, modifiedFileData, utils.USERSTORES)
+	err := utils.SendImportRequest(utils.GetRequestContext(), importFilePath, modifiedFileData, utils.USERSTORES)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.USERSTORES, fileInfo.ResourceName)
 		return fmt.Errorf("error when importing user store: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.USERSTORES, utils.IMPORT)
+	if err := waitForUserStoreDeployment(getUserStoreName(modifiedFileData, fileInfo)); err != nil {
+		utils.UpdateFailureSummary(utils.USERSTORES, fileInfo.ResourceName)
+		return fmt.Errorf("error when deploying user store: %s", err)
+	}
+	utils.UpdateImportState(utils.USERSTORES, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.USERSTORES, fileInfo.ResourceName, utils.IMPORT)
 	log.Println("User store imported successfully.")
 	return nil
 }
@@ -102,12 +149,13 @@ func importUserStoreOperation(importFilePath string, modifiedFileData string, fi
 func updateUserStoreOperation(userStoreId string, importFilePath string, modifiedFileData string, fileInfo utils.FileInfo) error {
 
 	log.Println("Updating user store: " + fileInfo.ResourceName)
-	err := utils.SendUpdateRequest(userStoreId, importFilePath, modifiedFileData, utils.USERSTORES)
+	err := utils.SendUpdateRequest(utils.GetRequestContext(), userStoreId, importFilePath, modifiedFileData, utils.USERSTORES)
 	if err != nil {
 		utils.UpdateFailureSummary(utils.USERSTORES, fileInfo.ResourceName)
 		return fmt.Errorf("error when updating user store: %s", err)
 	}
-	utils.UpdateSuccessSummary(utils.USERSTORES, utils.UPDATE)
+	utils.UpdateImportState(utils.USERSTORES, fileInfo.ResourceName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.USERSTORES, fileInfo.ResourceName, utils.UPDATE)
 	log.Println("User store updated successfully.")
 	return nil
 }
@@ -120,6 +168,7 @@ func removeDeletedDeployedUserstores(localFiles []os.FileInfo) {
 		log.Println("Error retrieving deployed userstores: ", err)
 		return
 	}
+	var userstoresToDelete []userStore
 deployedResourcess:
 	for _, userstore := range deployedUserstores {
 		for _, file := range localFiles {
@@ -127,16 +176,31 @@ deployedResourcess:
 				continue deployedResourcess
 			}
 		}
+		if isPrimaryUserStore(userstore.Name) {
+			continue
+		}
 		if utils.IsResourceExcluded(userstore.Name, utils.TOOL_CONFIGS.ApplicationConfigs) {
 			log.Printf("Userstore: %s is excluded from deletion.\n", userstore.Name)
 			continue
 		}
+		userstoresToDelete = append(userstoresToDelete, userstore)
+	}
+
+	var userstoreNames []string
+	for _, userstore := range userstoresToDelete {
+		userstoreNames = append(userstoreNames, userstore.Name)
+	}
+	if !utils.ConfirmDeletion(utils.USERSTORES, userstoreNames) {
+		return
+	}
+	for _, userstore := range userstoresToDelete {
 		log.Println("User store not found locally. Deleting userstore: ", userstore.Name)
-		err := utils.SendDeleteRequest(userstore.Id, utils.USERSTORES)
+		utils.EmitResourceStarted(utils.USERSTORES, userstore.Name, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), userstore.Id, utils.USERSTORES)
 		if err != nil {
 			utils.UpdateFailureSummary(utils.USERSTORES, userstore.Name)
 			log.Println("Error deleting user store: ", err)
 		}
-		utils.UpdateSuccessSummary(utils.USERSTORES, utils.DELETE)
+		utils.UpdateSuccessSummary(utils.USERSTORES, userstore.Name, utils.DELETE)
 	}
 }
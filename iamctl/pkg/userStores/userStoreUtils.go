@@ -22,16 +22,32 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
 
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
 	"gopkg.in/yaml.v2"
 )
 
 const USERSTORE_SECRET_MASK = "ENCRYPTED PROPERTY"
+const CONNECTION_PASSWORD_PROPERTY = "ConnectionPassword"
+const CONNECTION_PASSWORD_KEYWORD = "CONNECTION_PASSWORD"
+const CONNECTION_PASSWORD_ENV_PREFIX = "IAMCTL_USERSTORE_PASSWORD_"
+
+// User stores are deployed asynchronously by the server after the import request is accepted.
+const userStoreDeploymentTimeout = 60 * time.Second
+const userStorePollInterval = 2 * time.Second
+
+var envNameInvalidChars = regexp.MustCompile(`[^A-Za-z0-9]`)
 
 type userStore struct {
-	Id   string `json:"id"`
-	Name string `json:"name"`
+	Id      string `json:"id"`
+	Name    string `json:"name"`
+	Enabled *bool  `json:"enabled,omitempty"`
 }
 
 type UserStoreConfigurations struct {
@@ -42,7 +58,7 @@ type UserStoreConfigurations struct {
 func getUserStoreList() ([]userStore, error) {
 
 	var list []userStore
-	resp, err := utils.SendGetListRequest(utils.USERSTORES, -1)
+	resp, err := utils.SendGetListRequest(utils.GetRequestContext(), utils.USERSTORES, -1)
 	if err != nil {
 		return nil, fmt.Errorf("error while retrieving userstore list. %w", err)
 	}
@@ -84,15 +100,12 @@ func getDeployedUserstoreNames() []string {
 
 func getUserStoreKeywordMapping(userStoreName string) map[string]interface{} {
 
-	if utils.KEYWORD_CONFIGS.UserStoreConfigs != nil {
-		return utils.ResolveAdvancedKeywordMapping(userStoreName, utils.KEYWORD_CONFIGS.UserStoreConfigs)
-	}
-	return utils.KEYWORD_CONFIGS.KeywordMappings
+	return utils.ResolveAdvancedKeywordMapping(userStoreName, utils.KEYWORD_CONFIGS.UserStoreConfigs)
 }
 
 func getUserStoreId(userStoreFilePath string) (string, error) {
 
-	fileContent, err := ioutil.ReadFile(userStoreFilePath)
+	fileContent, err := utils.ReadResourceFile(userStoreFilePath)
 	if err != nil {
 		return "", fmt.Errorf("error when reading the file: %s. %s", userStoreFilePath, err)
 	}
@@ -114,3 +127,133 @@ func getUserStoreId(userStoreFilePath string) (string, error) {
 	}
 	return "", nil
 }
+
+func isPrimaryUserStore(userStoreName string) bool {
+
+	return strings.EqualFold(userStoreName, utils.PRIMARY_USERSTORE_DOMAIN)
+}
+
+// Get the name of the environment variable with the connection password of the given user store domain.
+func GetConnectionPasswordEnvName(userStoreName string) string {
+
+	return CONNECTION_PASSWORD_ENV_PREFIX + strings.ToUpper(envNameInvalidChars.ReplaceAllString(userStoreName, "_"))
+}
+
+func resolveConnectionPassword(userStoreName string, keywordMapping map[string]interface{}) string {
+
+	// The environment variable overrides the keyword config, so that the password can be injected by a pipeline.
+	if password := os.Getenv(GetConnectionPasswordEnvName(userStoreName)); password != "" {
+		return password
+	}
+	password, _ := keywordMapping[CONNECTION_PASSWORD_KEYWORD].(string)
+	return password
+}
+
+// Replace the masked value of the connection password property with the given password.
+func SetConnectionPassword(fileContent string, password string) (string, bool, error) {
+
+	var userStoreConfig yaml.MapSlice
+	err := yaml.Unmarshal([]byte(fileContent), &userStoreConfig)
+	if err != nil {
+		return fileContent, false, fmt.Errorf("invalid file content for user store: %s", err)
+	}
+
+	mask := strings.Trim(utils.SENSITIVE_FIELD_MASK, "'")
+	replaced := false
+	for _, item := range userStoreConfig {
+		properties, ok := item.Value.([]interface{})
+		if item.Key != "properties" || !ok {
+			continue
+		}
+		for _, property := range properties {
+			propertyConfig, ok := property.(yaml.MapSlice)
+			if !ok {
+				continue
+			}
+			isPasswordProperty := false
+			for _, field := range propertyConfig {
+				if field.Key == "name" && field.Value == CONNECTION_PASSWORD_PROPERTY {
+					isPasswordProperty = true
+				}
+			}
+			for i, field := range propertyConfig {
+				if isPasswordProperty && field.Key == "value" && field.Value == mask {
+					propertyConfig[i].Value = password
+					replaced = true
+				}
+			}
+		}
+	}
+	if !replaced {
+		return fileContent, false, nil
+	}
+	modifiedContent, err := yaml.Marshal(userStoreConfig)
+	if err != nil {
+		return fileContent, false, fmt.Errorf("error when setting the connection password: %s", err)
+	}
+	return string(modifiedContent), true, nil
+}
+
+func waitForUserStoreDeployment(userStoreName string) error {
+
+	deadline := time.Now().Add(userStoreDeploymentTimeout)
+	for {
+		userstores, err := getUserStoreList()
+		if err != nil {
+			return err
+		}
+		for _, userstore := range userstores {
+			if userstore.Name == userStoreName && (userstore.Enabled == nil || *userstore.Enabled) {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("user store is not enabled within %s", userStoreDeploymentTimeout)
+		}
+		log.Println("Waiting for the user store to be deployed: " + userStoreName)
+		time.Sleep(userStorePollInterval)
+	}
+}
+
+func getUserStoreName(fileContent string, fileInfo utils.FileInfo) string {
+
+	var userStoreConfig UserStoreConfigurations
+	if err := yaml.Unmarshal([]byte(fileContent), &userStoreConfig); err == nil && userStoreConfig.Name != "" {
+		return userStoreConfig.Name
+	}
+	return fileInfo.ResourceName
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localUserStoreNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.USERSTORES),
+		utils.TOOL_CONFIGS.UserStoreConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	userStores, err := getUserStoreList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedUserStores []utils.DeployedResource
+	for _, userStore := range userStores {
+		isDeletable := !isPrimaryUserStore(userStore.Name) &&
+			!utils.IsResourceExcluded(userStore.Name, utils.TOOL_CONFIGS.UserStoreConfigs)
+		deployedUserStores = append(deployedUserStores, utils.DeployedResource{Name: userStore.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.USERSTORES, localUserStoreNames, deployedUserStores, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the user stores that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	userstores, err := getUserStoreList()
+	if err != nil {
+		return nil, err
+	}
+	var userstoreNames []string
+	for _, userstore := range userstores {
+		userstoreNames = append(userstoreNames, userstore.Name)
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.USERSTORES), userstoreNames)
+}
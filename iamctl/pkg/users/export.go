@@ -0,0 +1,94 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package users
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string, filter string) error {
+
+	// Export each user to a separate file in the Users folder.
+	log.Println("Exporting users...")
+	exportFilePath = filepath.Join(exportFilePath, utils.USERS)
+
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	}
+
+	users, err := getUserList(filter)
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		return fmt.Sprintf("%v", users[i]["userName"]) < fmt.Sprintf("%v", users[j]["userName"])
+	})
+	utils.StartResourceProgress(utils.USERS, len(users))
+	for _, user := range users {
+		userName, _ := user["userName"].(string)
+		if userName == "" {
+			continue
+		}
+		if utils.IsResourceExcluded(userName, utils.TOOL_CONFIGS.UserConfigs) {
+			utils.ReportSkippedResource(utils.USERS, userName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.USERS, userName)
+			continue
+		}
+		if !utils.IsModifiedSince(getLastModifiedTime(user)) {
+			log.Println("User is not modified since the given time. Skipping user: ", userName)
+			utils.UpdateSuccessSummary(utils.USERS, userName, utils.UNCHANGED)
+			continue
+		}
+		log.Println("Exporting user: ", userName)
+		utils.EmitResourceStarted(utils.USERS, userName, utils.EXPORT)
+		err := exportUser(user, userName, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.USERS, userName)
+			log.Printf("Error while exporting user: %s. %s", userName, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.USERS, userName, utils.EXPORT)
+			log.Println("User exported successfully: ", userName)
+		}
+	}
+	return nil
+}
+
+func exportUser(user map[string]interface{}, userName string, outputDirPath string) error {
+
+	content, err := yaml.Marshal(RemoveSensitiveAttributes(user))
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, getUserFileName(userName)+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getUserKeywordMapping(userName), utils.USERS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
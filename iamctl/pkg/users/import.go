@@ -0,0 +1,142 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package users
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.USERS)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) || !utils.IsOrganizationResourceType(utils.USERS) {
+		return
+	}
+	// Importing users is disabled by default to avoid overwriting the users of the target environment.
+	if !isUserImportAllowed() {
+		log.Printf("Skipping users since the %s config is not enabled in the %s section.",
+			utils.ALLOW_IMPORT_CONFIG, utils.USERS_CONFIG)
+		return
+	}
+
+	log.Println("Importing users...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing users: ", err)
+		return
+	}
+	utils.StartResourceProgress(utils.USERS, len(files))
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		userFilePath := filepath.Join(importFilePath, file.Name())
+		fileName := utils.GetFileInfo(userFilePath).ResourceName
+		if !utils.IsResourceIncluded(fileName) {
+			utils.AddFilteredResourceToSummary(utils.USERS, fileName)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.USERS, fileName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.USERS, fileName, utils.IMPORT)
+		if err := importUser(userFilePath); err != nil {
+			utils.UpdateFailureSummary(utils.USERS, fileName)
+			log.Printf("Error when importing user: %s. %s", fileName, err)
+		}
+	}
+}
+
+func importUser(importFilePath string) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for user: %s", err)
+	}
+
+	var userInfo struct {
+		UserName string `yaml:"userName"`
+	}
+	err = yaml.Unmarshal(fileBytes, &userInfo)
+	if err != nil || userInfo.UserName == "" {
+		return fmt.Errorf("invalid file content for user. The userName attribute is required")
+	}
+	userName := userInfo.UserName
+	if utils.IsResourceExcluded(userName, utils.TOOL_CONFIGS.UserConfigs) {
+		utils.ReportSkippedResource(utils.USERS, userName, utils.JUNIT_SKIPPED_EXCLUDED)
+		return nil
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getUserKeywordMapping(userName))
+	utils.CheckImportContent(utils.USERS, userName, modifiedFileData)
+
+	var user map[interface{}]interface{}
+	err = yaml.Unmarshal([]byte(modifiedFileData), &user)
+	if err != nil {
+		return fmt.Errorf("invalid file content for user: %s", err)
+	}
+	for _, attribute := range readOnlyUserAttributes {
+		delete(user, attribute)
+	}
+	if _, ok := user["schemas"]; !ok {
+		user["schemas"] = []interface{}{SCIM_USER_SCHEMA}
+	}
+	payload := convertToJsonMap(user)
+
+	userId, err := GetUserId(userName)
+	if err != nil {
+		return err
+	}
+	if userId == "" {
+		log.Println("Creating new user: " + userName)
+		_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.USERS, "", payload)
+		if err != nil {
+			return fmt.Errorf("error when creating user: %s", err)
+		}
+		utils.UpdateImportState(utils.USERS, userName, modifiedFileData)
+		utils.UpdateSuccessSummary(utils.USERS, userName, utils.IMPORT)
+		log.Println("User created successfully.")
+		return nil
+	}
+
+	if utils.IsImportStateUnchanged(utils.USERS, userName, modifiedFileData) {
+		log.Println("User is unchanged since the last import. Skipping update: " + userName)
+		utils.UpdateSuccessSummary(utils.USERS, userName, utils.UNCHANGED)
+		return nil
+	}
+	log.Println("Updating user: " + userName)
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.USERS, url.PathEscape(userId), payload)
+	if err != nil {
+		return fmt.Errorf("error when updating user: %s", err)
+	}
+	utils.UpdateImportState(utils.USERS, userName, modifiedFileData)
+	utils.UpdateSuccessSummary(utils.USERS, userName, utils.UPDATE)
+	log.Println("User updated successfully.")
+	return nil
+}
@@ -0,0 +1,180 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package users
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const USER_LIST_PAGE_SIZE = 100
+const SCIM_USER_SCHEMA = "urn:ietf:params:scim:schemas:core:2.0:User"
+const SCIM_SEARCH_SCHEMA = "urn:ietf:params:scim:api:messages:2.0:SearchRequest"
+const USER_SEARCH_PATH = "scim2/Users/.search"
+
+// Attributes of the users that are never exported, matched case insensitively at any level of the user.
+var sensitiveUserAttributes = []string{
+	"*password*",
+	"*challengequestion*",
+	"*securityquestion*",
+}
+
+// Server generated and read only attributes that are not sent when importing users.
+var readOnlyUserAttributes = []string{"id", "meta", "groups", "roles"}
+
+type userListResponse struct {
+	TotalResults int                      `json:"totalResults"`
+	ItemsPerPage int                      `json:"itemsPerPage"`
+	Resources    []map[string]interface{} `json:"Resources"`
+}
+
+func getUserList(filter string) ([]map[string]interface{}, error) {
+
+	// The search endpoint is used, so that long filters are not limited by the length of the URL.
+	var users []map[string]interface{}
+	startIndex := 1
+	for {
+		searchRequest := map[string]interface{}{
+			"schemas":    []string{SCIM_SEARCH_SCHEMA},
+			"startIndex": startIndex,
+		}
+		if filter != "" {
+			searchRequest["filter"] = filter
+		}
+		resp, err := utils.SendPostListRequest(utils.GetRequestContext(), USER_SEARCH_PATH, searchRequest, USER_LIST_PAGE_SIZE)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving user list. %w", err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the retrieved user list. %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			if error, ok := utils.ErrorCodes[resp.StatusCode]; ok {
+				return nil, fmt.Errorf("error while retrieving user list. Status code: %d, Error: %s", resp.StatusCode, error)
+			}
+			return nil, fmt.Errorf("unexpected error while retrieving user list: %s", resp.Status)
+		}
+		var response userListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved user list. %w", err)
+		}
+
+		users = append(users, response.Resources...)
+		startIndex += len(response.Resources)
+		if len(response.Resources) == 0 || startIndex > response.TotalResults {
+			return users, nil
+		}
+	}
+}
+
+func GetUserId(userName string) (string, error) {
+
+	users, err := getUserList(fmt.Sprintf("userName eq %q", userName))
+	if err != nil {
+		return "", err
+	}
+	for _, user := range users {
+		if user["userName"] == userName {
+			id, _ := user["id"].(string)
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+func GetUserName(userId string) (string, error) {
+
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.USERS, url.PathEscape(userId), nil)
+	if err != nil {
+		return "", fmt.Errorf("error while retrieving the user. %w", err)
+	}
+	var user struct {
+		UserName string `json:"userName"`
+	}
+	err = json.Unmarshal(body, &user)
+	if err != nil {
+		return "", fmt.Errorf("error when unmarshalling the retrieved user. %w", err)
+	}
+	return user.UserName, nil
+}
+
+// Remove the sensitive attributes of the user, such as the password and the security questions.
+func RemoveSensitiveAttributes(user map[string]interface{}) map[string]interface{} {
+
+	for key, value := range user {
+		if utils.MatchesAnyPattern(strings.ToLower(key), sensitiveUserAttributes) {
+			delete(user, key)
+			continue
+		}
+		if attributes, ok := value.(map[string]interface{}); ok {
+			user[key] = RemoveSensitiveAttributes(attributes)
+		}
+	}
+	return user
+}
+
+// Convert the maps parsed from YAML to maps with string keys, so that they can be sent as JSON.
+func convertToJsonMap(data interface{}) interface{} {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		jsonMap := make(map[string]interface{})
+		for key, value := range v {
+			jsonMap[fmt.Sprintf("%v", key)] = convertToJsonMap(value)
+		}
+		return jsonMap
+	case []interface{}:
+		for i, value := range v {
+			v[i] = convertToJsonMap(value)
+		}
+	}
+	return data
+}
+
+func getLastModifiedTime(user map[string]interface{}) string {
+
+	meta, _ := user["meta"].(map[string]interface{})
+	lastModified, _ := meta["lastModified"].(string)
+	return lastModified
+}
+
+func getUserFileName(userName string) string {
+
+	// User names of secondary user stores are prefixed with the user store domain. Ex: SECONDARY/john
+	return strings.ReplaceAll(userName, "/", "_")
+}
+
+func getUserKeywordMapping(userName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(userName, utils.KEYWORD_CONFIGS.UserConfigs)
+}
+
+func isUserImportAllowed() bool {
+
+	allowImport, _ := utils.TOOL_CONFIGS.UserConfigs[utils.ALLOW_IMPORT_CONFIG].(bool)
+	return allowImport
+}
@@ -20,9 +20,13 @@ package utils
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"log"
 	"mime"
 	"mime/multipart"
@@ -38,11 +42,22 @@ const IMPORT = "import"
 const UPDATE = "update"
 const DELETE = "delete"
 const LIST = "list"
+const UNCHANGED = "unchanged"
+const GET = "get"
 
-func SendExportRequest(resourceId, fileType, resourceType string, excludeSecrets bool) (resp *http.Response, err error) {
+// Error of the JSON requests for resources that do not exist in the target environment.
+var ErrResourceNotFound = errors.New(ErrorCodes[http.StatusNotFound])
+
+// Error of the JSON requests for resources that the tool is not permitted to access.
+var ErrPermissionDenied = errors.New(ErrorCodes[http.StatusForbidden])
+
+// Error of the JSON requests for methods that the server does not support on a resource.
+var ErrMethodNotAllowed = errors.New(ErrorCodes[http.StatusMethodNotAllowed])
+
+func SendExportRequest(ctx context.Context, resourceId, fileType, resourceType string, excludeSecrets bool) (resp *http.Response, err error) {
 
 	reqUrl := buildRequestUrl(EXPORT, resourceType, resourceId)
-	req, err := http.NewRequest("GET", reqUrl, strings.NewReader(""))
+	req, err := http.NewRequestWithContext(ctx, "GET", reqUrl, strings.NewReader(""))
 	if err != nil {
 		return resp, fmt.Errorf("error while creating the export request: %s", err)
 	}
@@ -61,13 +76,7 @@ func SendExportRequest(resourceId, fileType, resourceType string, excludeSecrets
 
 	defer req.Body.Close()
 
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	httpClient := GetHttpClient()
 
 	resp, err = httpClient.Do(req)
 	if err != nil {
@@ -83,7 +92,7 @@ func SendExportRequest(resourceId, fileType, resourceType string, excludeSecrets
 	return resp, fmt.Errorf("unexpected error while exporting the resource with status code: %s", strconv.FormatInt(int64(statusCode), 10))
 }
 
-func SendImportRequest(importFilePath, fileData, resourceType string) error {
+func SendImportRequest(ctx context.Context, importFilePath, fileData, resourceType string) error {
 
 	reqUrl := buildRequestUrl(IMPORT, resourceType, "")
 
@@ -103,7 +112,6 @@ func SendImportRequest(importFilePath, fileData, resourceType string) error {
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	defer writer.Close()
 
 	part, err := writer.CreatePart(textproto.MIMEHeader{
 		"Content-Disposition": []string{fmt.Sprintf(`form-data; name="%s"; filename="%s"`, "file", fileInfo.FileName)},
@@ -117,8 +125,12 @@ func SendImportRequest(importFilePath, fileData, resourceType string) error {
 	if err != nil {
 		return fmt.Errorf("error when creating the import request: %s", err)
 	}
+	// Close the writer to add the closing boundary before the body is sent.
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("error when creating the import request: %s", err)
+	}
 
-	request, err := http.NewRequest("POST", reqUrl, body)
+	request, err := http.NewRequestWithContext(ctx, "POST", reqUrl, body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
 	defer request.Body.Close()
@@ -126,13 +138,7 @@ func SendImportRequest(importFilePath, fileData, resourceType string) error {
 	if err != nil {
 		return fmt.Errorf("error when creating the import request: %s", err)
 	}
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	client := GetHttpClient()
 	resp, err := client.Do(request)
 	if err != nil {
 		return fmt.Errorf("error when sending the import request: %s", err)
@@ -147,7 +153,7 @@ func SendImportRequest(importFilePath, fileData, resourceType string) error {
 	return fmt.Errorf("unexpected error when importing resource: %s", resp.Status)
 }
 
-func SendUpdateRequest(resourceId, importFilePath, fileData, resourceType string) error {
+func SendUpdateRequest(ctx context.Context, resourceId, importFilePath, fileData, resourceType string) error {
 
 	reqUrl := buildRequestUrl(UPDATE, resourceType, resourceId)
 	formattedReqUrl := addQueryParams(reqUrl, resourceType)
@@ -168,7 +174,6 @@ func SendUpdateRequest(resourceId, importFilePath, fileData, resourceType string
 
 	body := new(bytes.Buffer)
 	writer := multipart.NewWriter(body)
-	defer writer.Close()
 
 	part, err := writer.CreatePart(textproto.MIMEHeader{
 		"Content-Disposition": []string{fmt.Sprintf(`form-data; name="%s"; filename="%s"`, "file", fileInfo.FileName)},
@@ -182,8 +187,12 @@ func SendUpdateRequest(resourceId, importFilePath, fileData, resourceType string
 	if err != nil {
 		return fmt.Errorf("error when creating the import request: %s", err)
 	}
+	// Close the writer to add the closing boundary before the body is sent.
+	if err = writer.Close(); err != nil {
+		return fmt.Errorf("error when creating the import request: %s", err)
+	}
 
-	request, err := http.NewRequest("PUT", formattedReqUrl, body)
+	request, err := http.NewRequestWithContext(ctx, "PUT", formattedReqUrl, body)
 	request.Header.Add("Content-Type", writer.FormDataContentType())
 	request.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
 	defer request.Body.Close()
@@ -191,13 +200,7 @@ func SendUpdateRequest(resourceId, importFilePath, fileData, resourceType string
 	if err != nil {
 		return fmt.Errorf("error when creating the import request: %s", err)
 	}
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	client := GetHttpClient()
 	resp, err := client.Do(request)
 	if err != nil {
 		return fmt.Errorf("error when sending the import request: %s", err)
@@ -215,10 +218,10 @@ func SendUpdateRequest(resourceId, importFilePath, fileData, resourceType string
 	return fmt.Errorf("unexpected error when importing resource: %s", resp.Status)
 }
 
-func SendDeleteRequest(resourceId string, resourceType string) error {
+func SendDeleteRequest(ctx context.Context, resourceId string, resourceType string) error {
 
 	reqUrl := buildRequestUrl(DELETE, resourceType, resourceId)
-	request, err := http.NewRequest("DELETE", reqUrl, bytes.NewBuffer(nil))
+	request, err := http.NewRequestWithContext(ctx, "DELETE", reqUrl, bytes.NewBuffer(nil))
 	request.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
 	defer request.Body.Close()
 
@@ -226,13 +229,7 @@ func SendDeleteRequest(resourceId string, resourceType string) error {
 		return fmt.Errorf("error when creating the delete request: %s", err)
 	}
 
-	client := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	client := GetHttpClient()
 	resp, err := client.Do(request)
 	if err != nil {
 		return fmt.Errorf("error when sending the delete request: %s", err)
@@ -248,23 +245,27 @@ func SendDeleteRequest(resourceId string, resourceType string) error {
 	return fmt.Errorf("unexpected error when deleting resource: %s", resp.Status)
 }
 
-func SendGetListRequest(resourceType string, resourceLimit int) (*http.Response, error) {
+func SendGetListRequest(ctx context.Context, resourceType string, resourceLimit int) (*http.Response, error) {
 
 	var reqUrl = buildRequestUrl(LIST, resourceType, "")
-	http.DefaultTransport.(*http.Transport).TLSClientConfig = &tls.Config{InsecureSkipVerify: true}
 
-	req, _ := http.NewRequest("GET", reqUrl, bytes.NewBuffer(nil))
+	req, _ := http.NewRequestWithContext(ctx, "GET", reqUrl, bytes.NewBuffer(nil))
 	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
 	req.Header.Set("accept", "*/*")
 
+	query := req.URL.Query()
 	if resourceLimit != -1 {
-		query := req.URL.Query()
 		query.Add("limit", strconv.Itoa(resourceLimit))
-		req.URL.RawQuery = query.Encode()
 	}
+	if resourceType == APPLICATIONS || resourceType == IDENTITY_PROVIDERS {
+		if filter := GetNamespaceFilter("name"); filter != "" {
+			query.Add("filter", filter)
+		}
+	}
+	req.URL.RawQuery = query.Encode()
 	defer req.Body.Close()
 
-	httpClient := &http.Client{}
+	httpClient := GetHttpClient()
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve available userstore list. %w", err)
@@ -272,6 +273,177 @@ func SendGetListRequest(resourceType string, resourceLimit int) (*http.Response,
 	return resp, nil
 }
 
+// Send a list request for the endpoints that accept the filter in the request body, such as the SCIM search endpoints.
+// The path is relative to the tenant URL. Ex: scim2/Users/.search
+func SendPostListRequest(ctx context.Context, path string, filter interface{}, count int) (*http.Response, error) {
+
+	reqBody := make(map[string]interface{})
+	if filter != nil {
+		filterBytes, err := json.Marshal(filter)
+		if err != nil {
+			return nil, fmt.Errorf("error when creating the request body: %s", err)
+		}
+		if err := json.Unmarshal(filterBytes, &reqBody); err != nil {
+			return nil, fmt.Errorf("the filter of the list request should be a JSON object: %s", err)
+		}
+	}
+	if count != -1 {
+		reqBody["count"] = count
+	}
+	reqBytes, err := json.Marshal(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the request body: %s", err)
+	}
+
+	reqUrl := GetTenantUrl() + "/" + strings.TrimPrefix(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, bytes.NewBuffer(reqBytes))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the list request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
+	req.Header.Set("accept", MEDIA_TYPE_JSON)
+	req.Header.Set("Content-Type", MEDIA_TYPE_JSON)
+
+	httpClient := GetHttpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when sending the list request: %w", err)
+	}
+	return resp, nil
+}
+
+func SendGetRequest(ctx context.Context, resourceType string, resourceId string, queryParams map[string]string) (*http.Response, error) {
+
+	reqUrl := buildRequestUrl(GET, resourceType, resourceId)
+	req, err := http.NewRequestWithContext(ctx, "GET", reqUrl, bytes.NewBuffer(nil))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the get request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
+	req.Header.Set("accept", MEDIA_TYPE_JSON)
+
+	query := req.URL.Query()
+	for key, value := range queryParams {
+		query.Add(key, value)
+	}
+	req.URL.RawQuery = query.Encode()
+	defer req.Body.Close()
+
+	httpClient := GetHttpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when sending the get request: %s", err)
+	}
+
+	statusCode := resp.StatusCode
+	if statusCode == 200 {
+		return resp, nil
+	}
+	resp.Body.Close()
+	if error, ok := ErrorCodes[statusCode]; ok {
+		return nil, fmt.Errorf("error response for the get request: %s", error)
+	}
+	return nil, fmt.Errorf("unexpected error when retrieving resource: %s", resp.Status)
+}
+
+func SendJsonRequest(ctx context.Context, method string, resourceType string, resourcePath string, payload interface{}) ([]byte, error) {
+
+	// Query parameters can be given with the resource path. Ex: ?filter=userName eq "john"
+	pathParts := strings.SplitN(resourcePath, "?", 2)
+	reqUrl := strings.TrimSuffix(getResourceBaseUrl(resourceType)+pathParts[0], "/")
+	if len(pathParts) == 2 {
+		reqUrl += "?" + pathParts[1]
+	}
+	var reqBody []byte
+	if payload != nil {
+		var err error
+		reqBody, err = json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("error when creating the request body: %s", err)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, reqUrl, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
+	req.Header.Set("accept", MEDIA_TYPE_JSON)
+	if payload != nil {
+		req.Header.Set("Content-Type", MEDIA_TYPE_JSON)
+	}
+
+	httpClient := GetHttpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when sending the request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the response: %s", err)
+	}
+	statusCode := resp.StatusCode
+	if statusCode >= 200 && statusCode < 300 {
+		return respBody, nil
+	} else if statusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("error response for the request: %w", ErrResourceNotFound)
+	} else if statusCode == http.StatusForbidden {
+		return nil, fmt.Errorf("error response for the request: %w", ErrPermissionDenied)
+	} else if statusCode == http.StatusMethodNotAllowed {
+		return nil, fmt.Errorf("error response for the request: %w", ErrMethodNotAllowed)
+	} else if error, ok := ErrorCodes[statusCode]; ok {
+		return nil, fmt.Errorf("error response for the request: %s", error)
+	}
+	return nil, fmt.Errorf("unexpected error for the request: %s", resp.Status)
+}
+
+func SendSoapRequest(ctx context.Context, service string, operation string, payload string) ([]byte, error) {
+
+	// Admin services such as the entitlement policy admin service are only available as SOAP services.
+	reqUrl := SERVER_CONFIGS.ServerUrl + "/t/" + SERVER_CONFIGS.TenantDomain + "/services/" + service
+	envelope := `<soapenv:Envelope xmlns:soapenv="http://schemas.xmlsoap.org/soap/envelope/"><soapenv:Body>` +
+		payload + `</soapenv:Body></soapenv:Envelope>`
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reqUrl, strings.NewReader(envelope))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
+	req.Header.Set("Content-Type", MEDIA_TYPE_SOAP)
+	req.Header.Set("SOAPAction", "urn:"+operation)
+
+	httpClient := GetHttpClient()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error when sending the request: %s", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the response: %s", err)
+	}
+	statusCode := resp.StatusCode
+	if statusCode == 200 || statusCode == 202 {
+		return respBody, nil
+	}
+
+	// Errors of the service are returned as SOAP faults with the reason of the error.
+	var fault struct {
+		Body struct {
+			Fault struct {
+				Reason string `xml:"faultstring"`
+			} `xml:"Fault"`
+		} `xml:"Body"`
+	}
+	if xml.Unmarshal(respBody, &fault) == nil && fault.Body.Fault.Reason != "" {
+		return nil, fmt.Errorf("error response for the request: %s", fault.Body.Fault.Reason)
+	} else if error, ok := ErrorCodes[statusCode]; ok {
+		return nil, fmt.Errorf("error response for the request: %s", error)
+	}
+	return nil, fmt.Errorf("unexpected error for the request: %s", resp.Status)
+}
+
 func getResourcePath(resourceType string) string {
 
 	switch resourceType {
@@ -283,13 +455,44 @@ func getResourcePath(resourceType string) string {
 		return "userstores"
 	case CLAIMS:
 		return "claim-dialects"
+	case EMAIL_TEMPLATES:
+		return "email/template-types"
+	case API_RESOURCES:
+		return "api-resources"
+	case GOVERNANCE_CONNECTORS:
+		return "identity-governance"
+	case BRANDING:
+		return "branding-preference"
+	case OIDC_SCOPES:
+		return "oidc/scopes"
+	case NOTIFICATION_SENDERS:
+		return "notification-senders"
+	case WORKFLOWS:
+		return "workflows"
+	case CORS:
+		return "cors/origins"
+	case ORGANIZATIONS:
+		return "organizations"
+	case WORKFLOW_ASSOCIATIONS:
+		return "workflow-associations"
+	case AUTHENTICATORS:
+		return "configs/authenticators"
 	}
 	return ""
 }
 
 func getResourceBaseUrl(resourceType string) string {
 
-	return SERVER_CONFIGS.ServerUrl + "/t/" + SERVER_CONFIGS.TenantDomain + "/api/server/v1/" + getResourcePath(resourceType) + "/"
+	if resourceType == USERS {
+		return GetTenantUrl() + "/scim2/Users/"
+	}
+	if resourceType == ROLES {
+		return GetTenantUrl() + "/scim2/v2/Roles/"
+	}
+	if resourceType == GROUPS {
+		return GetTenantUrl() + "/scim2/Groups/"
+	}
+	return GetTenantUrl() + "/api/server/v1/" + getResourcePath(resourceType) + "/"
 }
 
 func buildRequestUrl(requestType, resourceType, resourceId string) (reqUrl string) {
@@ -311,6 +514,8 @@ func buildRequestUrl(requestType, resourceType, resourceId string) (reqUrl strin
 		}
 	case LIST:
 		reqUrl = getResourceBaseUrl(resourceType)
+	case GET:
+		reqUrl = getResourceBaseUrl(resourceType) + resourceId
 	case DELETE:
 		reqUrl = getResourceBaseUrl(resourceType) + resourceId
 	}
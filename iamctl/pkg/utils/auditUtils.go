@@ -0,0 +1,231 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Operations recorded in the audit log
+const AUDIT_CREATE = "CREATE"
+const AUDIT_UPDATE = "UPDATE"
+const AUDIT_DELETE = "DELETE"
+const AUDIT_SECRET_ACCESS = "SECRET_ACCESS"
+
+// Path to the audit log file. Set by the --audit-log flag.
+var AUDIT_LOG string
+
+type AuditRecord struct {
+	Timestamp    string `json:"timestamp"`
+	Operation    string `json:"operation"`
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	Success      bool   `json:"success"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+var auditWriter io.Writer
+var auditFile *os.File
+
+// Operation and error of the last failed request that modifies the resource in progress. Used to describe the
+// failures, since the failures are reported to the summary without the error.
+var failedRequestOperation string
+var failedRequestError string
+
+// Open the audit log file to append the records of the operations that create, update or delete resources.
+// The records are written to stderr if the file cannot be opened, so that the audit log never stops the run.
+func OpenAuditLog() {
+
+	if AUDIT_LOG == "" {
+		return
+	}
+	file, err := os.OpenFile(AUDIT_LOG, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "WARNING: Unable to open the audit log: %s. Audit records are written to stderr. %s\n",
+			AUDIT_LOG, err)
+		auditWriter = os.Stderr
+		return
+	}
+	auditFile = file
+	auditWriter = file
+}
+
+func CloseAuditLog() {
+
+	if auditFile != nil {
+		auditFile.Close()
+	}
+	auditFile = nil
+	auditWriter = nil
+	resetFailedRequest()
+}
+
+func IsAuditEnabled() bool {
+
+	return auditWriter != nil
+}
+
+func recordAuditSuccess(resourceType string, resourceName string, action string) {
+
+	if operation := getAuditOperation(action); operation != "" {
+		writeAuditRecord(AuditRecord{
+			Operation:    operation,
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Success:      true,
+		})
+	}
+	resetFailedRequest()
+}
+
+func recordAuditFailure(resourceType string, resourceName string, action string) {
+
+	// The operation of the failed request is more accurate than the action, since an import can be a create or an update.
+	operation := failedRequestOperation
+	if operation == "" {
+		operation = getAuditOperation(action)
+	}
+	errorMessage := failedRequestError
+	if errorMessage == "" {
+		errorMessage = "The operation failed before the request was sent to the server. Check the logs for the error."
+	}
+	if operation != "" {
+		writeAuditRecord(AuditRecord{
+			Operation:    operation,
+			ResourceType: resourceType,
+			ResourceName: resourceName,
+			Success:      false,
+			ErrorMessage: errorMessage,
+		})
+	}
+	resetFailedRequest()
+}
+
+// Record the retrieval of the unmasked sensitive fields of a resource. The access is logged even if the audit log
+// is not enabled, with the client that accessed the secrets.
+func RecordSecretAccess(resourceType string, resourceName string, err error) {
+
+	log.Printf("AUDIT: Sensitive fields of %s: %s are accessed by the client: %s of the server: %s.", resourceType,
+		resourceName, SERVER_CONFIGS.ClientId, SERVER_CONFIGS.ServerUrl)
+	record := AuditRecord{
+		Operation:    AUDIT_SECRET_ACCESS,
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Success:      err == nil,
+	}
+	if err != nil {
+		record.ErrorMessage = redactSensitiveValues(err.Error())
+	}
+	writeAuditRecord(record)
+}
+
+func writeAuditRecord(record AuditRecord) {
+
+	if auditWriter == nil {
+		return
+	}
+	record.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return
+	}
+	if _, err := auditWriter.Write(append(recordBytes, '\n')); err != nil && auditWriter != os.Stderr {
+		fmt.Fprintf(os.Stderr, "WARNING: Unable to write to the audit log: %s. Audit records are written to stderr. %s\n",
+			AUDIT_LOG, err)
+		auditFile.Close()
+		auditFile = nil
+		auditWriter = os.Stderr
+		auditWriter.Write(append(recordBytes, '\n'))
+	}
+}
+
+func getAuditOperation(action string) string {
+
+	switch action {
+	case IMPORT:
+		return AUDIT_CREATE
+	case UPDATE:
+		return AUDIT_UPDATE
+	case DELETE:
+		return AUDIT_DELETE
+	}
+	return ""
+}
+
+func getRequestAuditOperation(req *http.Request) string {
+
+	// Token requests, search requests and SOAP requests do not have a resource operation in the method.
+	if strings.HasSuffix(req.URL.Path, "/token") || strings.HasSuffix(req.URL.Path, "/.search") ||
+		strings.Contains(req.URL.Path, "/services/") {
+		return ""
+	}
+	switch req.Method {
+	case http.MethodPost:
+		return AUDIT_CREATE
+	case http.MethodPut, http.MethodPatch:
+		return AUDIT_UPDATE
+	case http.MethodDelete:
+		return AUDIT_DELETE
+	}
+	return ""
+}
+
+func resetFailedRequest() {
+
+	failedRequestOperation = ""
+	failedRequestError = ""
+}
+
+// Keep the error of the failed requests that modify resources when the audit log is enabled, so that the
+// audit record of the resource has the error returned by the server.
+func AuditMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		operation := getRequestAuditOperation(req)
+		if !IsAuditEnabled() || operation == "" {
+			return next.RoundTrip(req)
+		}
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			failedRequestOperation = operation
+			failedRequestError = redactSensitiveValues(err.Error())
+			return resp, err
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			body, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			failedRequestOperation = operation
+			failedRequestError = strings.TrimSpace(resp.Status + " " + RedactBody(body))
+		}
+		return resp, nil
+	})
+}
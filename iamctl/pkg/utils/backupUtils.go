@@ -0,0 +1,124 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const BACKUP_TIMESTAMP_FORMAT = "20060102-150405"
+const BACKUP_METADATA_FILE = "backup-metadata.yml"
+
+// Path to the directory to back up the deployed resources before they are updated or deleted. Set by the --backup
+// flag, which overrides the BACKUP_DIR tool config.
+var BACKUP_DIR string
+
+// Timestamped directory of the backups of the run, created when the first resource is backed up.
+var backupRunDir string
+
+// Details of the environment of a backup, written next to the backed up resource type folders.
+type BackupMetadata struct {
+	ServerUrl    string `yaml:"serverUrl"`
+	TenantDomain string `yaml:"tenantDomain"`
+	Timestamp    string `yaml:"timestamp"`
+	ToolVersion  string `yaml:"toolVersion"`
+}
+
+func IsBackupEnabled() bool {
+
+	return getBackupBaseDir() != ""
+}
+
+// Get the directory to back up the resources of the given type. The resources of each tenant are backed up to a
+// separate folder when the resources of all tenants are imported.
+func GetBackupDir(resourceType string) (string, error) {
+
+	if backupRunDir == "" {
+		backupRunDir = filepath.Join(getBackupBaseDir(), time.Now().Format(BACKUP_TIMESTAMP_FORMAT))
+	}
+	backupDir := backupRunDir
+	if ALL_TENANTS {
+		backupDir = filepath.Join(backupDir, SERVER_CONFIGS.TenantDomain)
+	}
+	if err := writeBackupMetadata(backupDir); err != nil {
+		return "", err
+	}
+	backupDir = filepath.Join(backupDir, resourceType)
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return "", fmt.Errorf("error when creating the backup directory: %s", err)
+	}
+	return backupDir, nil
+}
+
+func ReadBackupMetadata(backupDir string) (BackupMetadata, error) {
+
+	var metadata BackupMetadata
+	content, err := ioutil.ReadFile(filepath.Join(backupDir, BACKUP_METADATA_FILE))
+	if err != nil {
+		return metadata, fmt.Errorf("error when reading the backup metadata file: %s", err)
+	}
+	if err := yaml.Unmarshal(content, &metadata); err != nil {
+		return metadata, fmt.Errorf("error when parsing the backup metadata file: %s", err)
+	}
+	return metadata, nil
+}
+
+func writeBackupMetadata(backupDir string) error {
+
+	metadataFilePath := filepath.Join(backupDir, BACKUP_METADATA_FILE)
+	if _, err := os.Stat(metadataFilePath); err == nil {
+		return nil
+	}
+	content, err := yaml.Marshal(BackupMetadata{
+		ServerUrl:    SERVER_CONFIGS.ServerUrl,
+		TenantDomain: SERVER_CONFIGS.TenantDomain,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+		ToolVersion:  VERSION,
+	})
+	if err != nil {
+		return fmt.Errorf("error when creating the backup metadata: %s", err)
+	}
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		return fmt.Errorf("error when creating the backup directory: %s", err)
+	}
+	if err := ioutil.WriteFile(metadataFilePath, content, 0644); err != nil {
+		return fmt.Errorf("error when writing the backup metadata file: %s", err)
+	}
+	return nil
+}
+
+// Get the timestamped backup directory of the run. Empty if no resource is backed up.
+func GetBackupRunDir() string {
+
+	return backupRunDir
+}
+
+func getBackupBaseDir() string {
+
+	if BACKUP_DIR != "" {
+		return BACKUP_DIR
+	}
+	return TOOL_CONFIGS.BackupDir
+}
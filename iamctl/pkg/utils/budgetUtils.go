@@ -0,0 +1,129 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"log"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// Exit code used when the run stopped before processing all resources, but all attempted operations succeeded.
+const PARTIAL_COMPLETION_EXIT_CODE = 2
+
+// Exit code used when the run is interrupted with Ctrl-C, following the convention of the shells for SIGINT.
+const INTERRUPTED_EXIT_CODE = 130
+
+// Maximum duration of the run. Set by the --time-budget flag.
+var TIME_BUDGET time.Duration
+
+var budgetStartTime time.Time
+var scheduledResources int
+var isBudgetExhausted bool
+var stopRequested int32
+
+func StartTimeBudget(budget time.Duration) {
+
+	TIME_BUDGET = budget
+	budgetStartTime = time.Now()
+	scheduledResources = 0
+	isBudgetExhausted = false
+	atomic.StoreInt32(&stopRequested, 0)
+}
+
+// Stop scheduling new resources, so that the resource being processed is completed before the run ends.
+func RequestStop() {
+
+	atomic.StoreInt32(&stopRequested, 1)
+}
+
+func IsStopRequested() bool {
+
+	return atomic.LoadInt32(&stopRequested) == 1
+}
+
+func IsTimeBudgetAvailable() bool {
+
+	// The remaining resources are reported as unprocessed when the run is interrupted.
+	if IsStopRequested() || IsCancelled() {
+		return false
+	}
+	if TIME_BUDGET <= 0 {
+		return true
+	}
+	if isBudgetExhausted {
+		return false
+	}
+
+	// Stop scheduling new resources if processing another one is expected to exceed the budget,
+	// based on the average time taken for the resources processed so far.
+	elapsed := time.Since(budgetStartTime)
+	var averageDuration time.Duration
+	if scheduledResources > 0 {
+		averageDuration = elapsed / time.Duration(scheduledResources)
+	}
+	if elapsed+averageDuration >= TIME_BUDGET {
+		log.Printf("Time budget of %s is about to be exhausted. Remaining resources will not be processed.", TIME_BUDGET)
+		isBudgetExhausted = true
+		return false
+	}
+	scheduledResources++
+	return true
+}
+
+func GetResourcePriority(resourceName string) int {
+
+	// Resources matching an earlier priority pattern get a higher priority. Resources that do not match any pattern come last.
+	for i, pattern := range TOOL_CONFIGS.Priority {
+		if pattern == resourceName {
+			return i
+		}
+		if matched, err := filepath.Match(pattern, resourceName); err == nil && matched {
+			return i
+		}
+	}
+	return len(TOOL_CONFIGS.Priority)
+}
+
+func IsBudgetExhausted() bool {
+
+	return isBudgetExhausted
+}
+
+// Exit with a distinct exit code after the summary is printed, if the run was interrupted.
+func ExitIfInterrupted() {
+
+	if IsCancelled() {
+		log.Println("Run interrupted. The summary covers the resources processed before the interrupt.")
+		os.Exit(INTERRUPTED_EXIT_CODE)
+	}
+}
+
+func ExitIfIncomplete() {
+
+	if !isBudgetExhausted {
+		return
+	}
+	if SummaryData.FailedOperations > 0 {
+		os.Exit(1)
+	}
+	os.Exit(PARTIAL_COMPLETION_EXIT_CODE)
+}
@@ -0,0 +1,78 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+)
+
+// Application import strategies. Set with the IMPORT_STRATEGY config under applications.
+const SINGLE_PHASE_IMPORT = "SINGLE_PHASE"
+const TWO_PHASE_IMPORT = "TWO_PHASE"
+
+const INBOUND_PROTOCOLS_META_PATH = "meta/inbound-protocols"
+
+// Features of the target server that differ between the server versions, detected with a request to the server.
+type ServerCapabilities struct {
+	// Inbound protocols of an application can be configured after the application is created.
+	SeparateInboundProtocols bool
+}
+
+// Capabilities of each server and tenant, so that the server is probed once per run.
+var serverCapabilities = make(map[string]ServerCapabilities)
+
+func GetServerCapabilities() ServerCapabilities {
+
+	serverKey := GetTenantUrl()
+	if capabilities, ok := serverCapabilities[serverKey]; ok {
+		return capabilities
+	}
+	var capabilities ServerCapabilities
+	_, err := SendJsonRequest(GetRequestContext(), http.MethodGet, APPLICATIONS, INBOUND_PROTOCOLS_META_PATH, nil)
+	if err != nil && !errors.Is(err, ErrResourceNotFound) && !errors.Is(err, ErrMethodNotAllowed) {
+		// Only a missing endpoint means that the capability is not supported. The result of other failures such as
+		// timeouts and server errors is not cached, so that the server is probed again.
+		log.Printf("Warning: Could not detect the capabilities of %s. The capabilities are assumed to be "+
+			"unsupported: %s", serverKey, err)
+		return capabilities
+	}
+	capabilities.SeparateInboundProtocols = err == nil
+	LogDebug(fmt.Sprintf("Server capabilities of %s: %+v", serverKey, capabilities))
+	serverCapabilities[serverKey] = capabilities
+	return capabilities
+}
+
+func GetApplicationImportStrategy() string {
+
+	// The strategy in the tool configs overrides the strategy selected from the capabilities of the server.
+	if strategy, ok := TOOL_CONFIGS.ApplicationConfigs[IMPORT_STRATEGY_CONFIG].(string); ok && strategy != "" {
+		if strategy == SINGLE_PHASE_IMPORT || strategy == TWO_PHASE_IMPORT {
+			return strategy
+		}
+		LogWarning(WARNING_CONFIG, fmt.Sprintf("Invalid application import strategy: %s. The strategy is selected "+
+			"from the capabilities of the server.", strategy))
+	}
+	if GetServerCapabilities().SeparateInboundProtocols {
+		return TWO_PHASE_IMPORT
+	}
+	return SINGLE_PHASE_IMPORT
+}
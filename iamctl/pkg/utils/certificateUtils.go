@@ -0,0 +1,151 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/x509"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const CERTIFICATE_PLACEHOLDER = "<certificate>"
+
+// Replace the certificates in the exported YAML files with a placeholder. Set by the --exclude-certs flag.
+var EXCLUDE_CERTS bool
+
+// Fields of the applications and identity providers that hold certificates.
+var certificateFields = []string{"certificate", "certificateContent", "certValue"}
+
+var certificateFieldPattern = regexp.MustCompile("^([ \\t]*)(-[ \\t]+)?(" +
+	strings.Join(quoteFields(certificateFields), "|") + "):[ \\t]*(.*)$")
+
+// Replace the certificates in the YAML content with a placeholder, and add a comment with the subject, issuer and
+// expiry of each certificate. Values that are not certificates, such as masked values and keyword placeholders,
+// are kept as they are.
+func ExcludeCertificates(content []byte) []byte {
+
+	lines := strings.Split(string(content), "\n")
+	var excludedLines []string
+	for i := 0; i < len(lines); i++ {
+		match := certificateFieldPattern.FindStringSubmatch(lines[i])
+		if match == nil {
+			excludedLines = append(excludedLines, lines[i])
+			continue
+		}
+		// Multi-line certificates are written as block scalars, with the lines indented under the field.
+		fieldIndent := len(match[1]) + len(match[2])
+		fieldLines := []string{lines[i]}
+		if strings.HasPrefix(match[4], "|") || strings.HasPrefix(match[4], ">") {
+			for i+1 < len(lines) && strings.TrimSpace(lines[i+1]) != "" && getIndent(lines[i+1]) > fieldIndent {
+				i++
+				fieldLines = append(fieldLines, lines[i])
+			}
+		}
+		certificate, err := parseCertificateField(match[3], fieldIndent, match[4], fieldLines[1:])
+		if err != nil {
+			excludedLines = append(excludedLines, fieldLines...)
+			continue
+		}
+		excludedLines = append(excludedLines, match[1]+"# "+describeCertificate(certificate),
+			match[1]+match[2]+match[3]+": "+CERTIFICATE_PLACEHOLDER)
+	}
+	return []byte(strings.Join(excludedLines, "\n"))
+}
+
+// Certificate placeholders are detected before importing any resource, since the placeholder is not a valid
+// certificate and the import would be partially applied.
+func CheckCertificatePlaceholders(filePaths []string) error {
+
+	var placeholderFields []string
+	for _, filePath := range filePaths {
+		fileContent, err := ReadResourceFile(filePath)
+		if err != nil {
+			return fmt.Errorf("error when reading the file: %s. %s", filePath, err)
+		}
+		for _, field := range FindCertificatePlaceholders(fileContent) {
+			placeholderFields = append(placeholderFields, fmt.Sprintf("%s: %s", filePath, field))
+		}
+	}
+	if len(placeholderFields) > 0 {
+		return fmt.Errorf("found certificate placeholders of the --exclude-certs flag in the following fields. "+
+			"Replace the placeholders with the certificates or export the files without the flag before importing.\n  %s",
+			strings.Join(placeholderFields, "\n  "))
+	}
+	return nil
+}
+
+func FindCertificatePlaceholders(fileContent []byte) []string {
+
+	var placeholderFields []string
+	var content interface{}
+	if yaml.Unmarshal(ReplaceTypeTags(fileContent), &content) != nil {
+		// Report the line numbers when the content cannot be parsed.
+		for i, line := range strings.Split(string(fileContent), "\n") {
+			if strings.Contains(line, CERTIFICATE_PLACEHOLDER) {
+				placeholderFields = append(placeholderFields, "line "+strconv.Itoa(i+1))
+			}
+		}
+		return placeholderFields
+	}
+	walkStringFields(content, "", func(path string, value string) {
+		if value == CERTIFICATE_PLACEHOLDER {
+			placeholderFields = append(placeholderFields, path)
+		}
+	})
+	return placeholderFields
+}
+
+func parseCertificateField(field string, fieldIndent int, value string, blockLines []string) (*x509.Certificate, error) {
+
+	fieldContent := strings.Repeat(" ", fieldIndent) + field + ": " + value + "\n" + strings.Join(blockLines, "\n")
+	var fieldData map[string]interface{}
+	if err := yaml.Unmarshal([]byte(fieldContent), &fieldData); err != nil {
+		return nil, err
+	}
+	certificateContent, ok := fieldData[field].(string)
+	if !ok || strings.TrimSpace(certificateContent) == "" {
+		return nil, fmt.Errorf("field: %s does not have a certificate", field)
+	}
+	return parseCertificate(certificateContent)
+}
+
+func describeCertificate(certificate *x509.Certificate) string {
+
+	return fmt.Sprintf("Certificate subject: %s, issuer: %s, expires: %s", certificate.Subject.String(),
+		certificate.Issuer.String(), certificate.NotAfter.UTC().Format(time.RFC3339))
+}
+
+func getIndent(line string) int {
+
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func quoteFields(fields []string) []string {
+
+	var quotedFields []string
+	for _, field := range fields {
+		quotedFields = append(quotedFields, regexp.QuoteMeta(field))
+	}
+	return quotedFields
+}
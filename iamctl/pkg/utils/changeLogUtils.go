@@ -0,0 +1,112 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+const CHANGE_LOG_FILE = "CHANGELOG.yaml"
+
+// Append an entry to the change log of the output directory after the export. Set by the --git-log flag.
+var GIT_LOG bool
+
+var changedFileCount int
+
+type changeLogEntry struct {
+	Timestamp        string `yaml:"timestamp"`
+	User             string `yaml:"user"`
+	ChangedResources int    `yaml:"changedResources"`
+	// Commit of the git repository that the exported changes are applied on.
+	Commit string `yaml:"commit,omitempty"`
+}
+
+func countChangedFile(exportedFileName string, content []byte) {
+
+	if GIT_LOG && !isExportedFileUnchanged(exportedFileName, content) {
+		changedFileCount++
+	}
+}
+
+// Append an entry for the resources changed since the last entry to the change log. No entry is added if no
+// resource changed, so that the change log does not change when the server is unchanged.
+func WriteChangeLogEntry(outputDirPath string) error {
+
+	if changedFileCount == 0 {
+		return nil
+	}
+	gitRootDir, err := getGitRootDir(outputDirPath)
+	if err != nil {
+		return err
+	}
+	entry := changeLogEntry{
+		Timestamp:        time.Now().UTC().Format(time.RFC3339),
+		User:             getExporterUser(gitRootDir),
+		ChangedResources: changedFileCount,
+		Commit:           getHeadCommit(gitRootDir),
+	}
+	content, err := yaml.Marshal([]changeLogEntry{entry})
+	if err != nil {
+		return fmt.Errorf("error when writing the change log entry: %s", err)
+	}
+
+	// The entries are appended as items of a YAML list, so that the previous entries are kept as they are.
+	file, err := os.OpenFile(filepath.Join(outputDirPath, CHANGE_LOG_FILE), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("error when opening the change log file: %s", err)
+	}
+	defer file.Close()
+	if _, err := file.Write(content); err != nil {
+		return fmt.Errorf("error when writing the change log entry: %s", err)
+	}
+	changedFileCount = 0
+	return nil
+}
+
+// Get the git user of the repository, or the user running the tool if the git user is not configured.
+func getExporterUser(gitRootDir string) string {
+
+	if output, err := exec.Command("git", "-C", gitRootDir, "config", "user.name").Output(); err == nil {
+		if name := strings.TrimSpace(string(output)); name != "" {
+			return name
+		}
+	}
+	if currentUser, err := user.Current(); err == nil {
+		return currentUser.Username
+	}
+	return ""
+}
+
+// Get the hash of the HEAD commit. Empty if the repository has no commits yet.
+func getHeadCommit(gitRootDir string) string {
+
+	output, err := exec.Command("git", "-C", gitRootDir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
@@ -19,10 +19,22 @@
 package utils
 
 import (
+	"bytes"
+	"compress/gzip"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 )
 
+// Attribute type of a relative distinguished name, given as a name or as an OID.
+var subjectDNAttributeTypeRegex = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|[0-9]+(\.[0-9]+)*)$`)
+
 type FileInfo struct {
 	ResourceName  string
 	FileName      string
@@ -31,13 +43,76 @@ type FileInfo struct {
 
 func GetFileInfo(filePath string) (fileInfo FileInfo) {
 
-	fileInfo.FileName = filepath.Base(filePath)
+	// Resolve the file info of the compressed files based on the original file name.
+	fileInfo.FileName = strings.TrimSuffix(filepath.Base(filePath), GZIP_EXTENSION)
 	fileInfo.FileExtension = filepath.Ext(fileInfo.FileName)
 	fileInfo.ResourceName = strings.TrimSuffix(fileInfo.FileName, fileInfo.FileExtension)
 
 	return fileInfo
 }
 
+func IsAuthScriptFile(filePath string) bool {
+
+	return strings.HasSuffix(GetFileInfo(filePath).FileName, AUTH_SCRIPT_FILE_SUFFIX)
+}
+
+func IsClientCertificateFile(filePath string) bool {
+
+	return strings.HasSuffix(GetFileInfo(filePath).FileName, CLIENT_CERTIFICATE_FILE_SUFFIX)
+}
+
+// Check whether a file is exported next to an application file and referenced by it, such as an auth script file or
+// a client certificate file.
+func IsReferencedFile(filePath string) bool {
+
+	return IsAuthScriptFile(filePath) || IsClientCertificateFile(filePath)
+}
+
+// Get the name of the file that references a file, without the extension. The name of the other files is returned
+// without the extension.
+func getReferencingFileName(filePath string) string {
+
+	fileName := GetFileInfo(filePath).FileName
+	for _, suffix := range []string{AUTH_SCRIPT_FILE_SUFFIX, CLIENT_CERTIFICATE_FILE_SUFFIX} {
+		if strings.HasSuffix(fileName, suffix) {
+			return strings.TrimSuffix(fileName, suffix)
+		}
+	}
+	return GetFileInfo(filePath).ResourceName
+}
+
+func GetCertificateExpiry(certificateContent string) (time.Time, error) {
+
+	certificate, err := parseCertificate(certificateContent)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return certificate.NotAfter, nil
+}
+
+func parseCertificate(certificateContent string) (*x509.Certificate, error) {
+
+	// Certificates can be given in the PEM format with or without line breaks.
+	encodedCertificate := certificateContent
+	encodedCertificate = strings.Replace(encodedCertificate, "-----BEGIN CERTIFICATE-----", "", 1)
+	encodedCertificate = strings.Replace(encodedCertificate, "-----END CERTIFICATE-----", "", 1)
+	encodedCertificate = strings.Join(strings.Fields(encodedCertificate), "")
+
+	decodedCertificate, err := base64.StdEncoding.DecodeString(encodedCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("certificate is not in the PEM format: %s", err)
+	}
+	// The certificate content exported from the server is the base64 encoded PEM certificate.
+	if strings.HasPrefix(string(decodedCertificate), "-----BEGIN CERTIFICATE-----") {
+		return parseCertificate(string(decodedCertificate))
+	}
+	certificate, err := x509.ParseCertificate(decodedCertificate)
+	if err != nil {
+		return nil, fmt.Errorf("error when parsing the certificate: %s", err)
+	}
+	return certificate, nil
+}
+
 func Contains(slice []string, item string) bool {
 
 	for _, s := range slice {
@@ -47,3 +122,116 @@ func Contains(slice []string, item string) bool {
 	}
 	return false
 }
+
+func IsValidSubjectDN(subjectDN string) bool {
+
+	// Split the DN into RDNs on commas that are not escaped with a backslash.
+	var rdns []string
+	var current strings.Builder
+	escaped := false
+	for _, char := range subjectDN {
+		if escaped {
+			current.WriteRune(char)
+			escaped = false
+			continue
+		}
+		if char == '\\' {
+			current.WriteRune(char)
+			escaped = true
+			continue
+		}
+		if char == ',' {
+			rdns = append(rdns, current.String())
+			current.Reset()
+			continue
+		}
+		current.WriteRune(char)
+	}
+	rdns = append(rdns, current.String())
+
+	for _, rdn := range rdns {
+		parts := strings.SplitN(rdn, "=", 2)
+		if len(parts) != 2 {
+			return false
+		}
+		if !subjectDNAttributeTypeRegex.MatchString(strings.TrimSpace(parts[0])) || strings.TrimSpace(parts[1]) == "" {
+			return false
+		}
+	}
+	return true
+}
+
+func ReadResourceFile(filePath string) ([]byte, error) {
+
+	// Fall back to the compressed file if the uncompressed file does not exist.
+	if _, err := os.Stat(filePath); os.IsNotExist(err) && !strings.HasSuffix(filePath, GZIP_EXTENSION) {
+		if _, err := os.Stat(filePath + GZIP_EXTENSION); err == nil {
+			filePath += GZIP_EXTENSION
+		}
+	}
+
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(filePath, GZIP_EXTENSION) {
+		return decompress(content)
+	}
+	return content, nil
+}
+
+func ResourceFileExists(filePath string) bool {
+
+	if _, err := os.Stat(filePath); err == nil {
+		return true
+	}
+	_, err := os.Stat(filePath + GZIP_EXTENSION)
+	return err == nil
+}
+
+func compress(content []byte) ([]byte, error) {
+
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(content); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decompress(content []byte) ([]byte, error) {
+
+	reader, err := gzip.NewReader(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return ioutil.ReadAll(reader)
+}
+
+// Write the content to a temporary file in the same directory and rename it to the given file, so that the file is
+// either replaced completely or kept unchanged if the tool is stopped during the write.
+func writeFileAtomically(filePath string, content []byte, perm os.FileMode) error {
+
+	tempFile, err := ioutil.TempFile(filepath.Dir(filePath), filepath.Base(filePath)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = tempFile.Write(content)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tempFile.Name(), perm)
+	}
+	if err == nil {
+		err = os.Rename(tempFile.Name(), filePath)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+	}
+	return err
+}
@@ -0,0 +1,95 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const CONFIGS_DIR_NAME = "configs"
+
+// Environment specific config folder found in the configs folder of a local directory.
+type ConfigFolder struct {
+	Name         string
+	Path         string
+	Environments []string
+}
+
+// Get the local directory of the resource files, that contains the configs folder of the given config folder.
+func GetBaseDir(envConfigPath string) string {
+
+	return filepath.Dir(filepath.Dir(envConfigPath))
+}
+
+// Find the environment specific config folders in the configs folder of the given local directory, in the layout
+// created by the setupCLI command.
+func DiscoverConfigFolders(baseDir string) ([]ConfigFolder, error) {
+
+	configsDir := filepath.Join(baseDir, CONFIGS_DIR_NAME)
+	files, err := ioutil.ReadDir(configsDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error when reading the configs folder: %s", err)
+	}
+
+	var configFolders []ConfigFolder
+	for _, file := range files {
+		configFolderPath := filepath.Join(configsDir, file.Name())
+		if _, err := os.Stat(filepath.Join(configFolderPath, SERVER_CONFIG_FILE)); !file.IsDir() || err != nil {
+			continue
+		}
+		environments, err := GetConfigEnvironments(configFolderPath)
+		if err != nil {
+			return nil, err
+		}
+		configFolders = append(configFolders, ConfigFolder{Name: file.Name(), Path: configFolderPath, Environments: environments})
+	}
+	return configFolders, nil
+}
+
+// Get the names of the environment sections in the server config file of the given config folder.
+func GetConfigEnvironments(envConfigPath string) ([]string, error) {
+
+	serverConfigFile, err := ioutil.ReadFile(filepath.Join(envConfigPath, SERVER_CONFIG_FILE))
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the server config file in %s: %s", envConfigPath, err)
+	}
+	if len(serverConfigFile) == 0 {
+		return nil, nil
+	}
+	var configs map[string]interface{}
+	if err := json.Unmarshal(serverConfigFile, &configs); err != nil {
+		return nil, fmt.Errorf("server configs in %s are not in the correct format: %s", envConfigPath, err)
+	}
+
+	var environments []string
+	for key, value := range configs {
+		if _, ok := value.(map[string]interface{}); ok && key != DEFAULT_ENV_SECTION {
+			environments = append(environments, key)
+		}
+	}
+	sort.Strings(environments)
+	return environments, nil
+}
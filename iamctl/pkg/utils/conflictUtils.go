@@ -0,0 +1,135 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Extension of the files that keep the server configuration of the resources at the last export, which is the base
+// of the three-way comparison to detect the resources edited directly on the server.
+const BASELINE_FILE_EXTENSION = ".baseline"
+
+const CONFLICT_STRATEGY_OURS = "ours"
+const CONFLICT_STRATEGY_THEIRS = "theirs"
+const CONFLICT_STRATEGY_FAIL = "fail"
+
+var CONFLICT_STRATEGIES = []string{CONFLICT_STRATEGY_OURS, CONFLICT_STRATEGY_THEIRS, CONFLICT_STRATEGY_FAIL}
+
+// Strategy to resolve the conflicts of the resources edited on the server. Set by the --conflict-strategy flag.
+var CONFLICT_STRATEGY = CONFLICT_STRATEGY_OURS
+
+// Write the baseline files of all exported resources, in addition to the resources with a baseline file.
+// Set by the --baseline flag.
+var WRITE_BASELINE bool
+
+var isConflictAborted bool
+
+func ValidateConflictStrategy(strategy string) error {
+
+	if !Contains(CONFLICT_STRATEGIES, strategy) {
+		return fmt.Errorf("invalid conflict strategy: %s. Supported strategies are: %s", strategy,
+			strings.Join(CONFLICT_STRATEGIES, ", "))
+	}
+	return nil
+}
+
+func IsBaselineFile(filePath string) bool {
+
+	return GetFileInfo(filePath).FileExtension == BASELINE_FILE_EXTENSION
+}
+
+func GetBaselineFilePath(resourceFilePath string) string {
+
+	return filepath.Join(filepath.Dir(resourceFilePath), GetFileInfo(resourceFilePath).ResourceName+BASELINE_FILE_EXTENSION)
+}
+
+// Write the server configuration of an exported resource to the baseline file next to the resource file. The file is
+// only written with the --baseline flag or if the resource already has a baseline file, so that the baselines
+// are kept up to date once the conflict detection is used.
+func WriteBaselineFile(resourceFilePath string, serverContent []byte) error {
+
+	baselineFilePath := GetBaselineFilePath(resourceFilePath)
+	if !WRITE_BASELINE && !ResourceFileExists(baselineFilePath) {
+		return nil
+	}
+	if err := writeFileAtomically(baselineFilePath, serverContent, 0644); err != nil {
+		return fmt.Errorf("error when writing the baseline file: %s", err)
+	}
+	return nil
+}
+
+// Get the content of the baseline file of a local resource file. Returns nil if the resource has no baseline file.
+func ReadBaselineFile(resourceFilePath string) ([]byte, error) {
+
+	content, err := ioutil.ReadFile(GetBaselineFilePath(resourceFilePath))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error when reading the baseline file: %s", err)
+	}
+	return content, nil
+}
+
+// Check whether a resource is edited on the server since the last export, in a way that differs from the local file.
+// The given fields, such as the server generated fields, are ignored in the comparison.
+func IsConflicting(localContent []byte, deployedContent []byte, baselineContent []byte, ignoredFields []string) bool {
+
+	return !IsContentEqual(deployedContent, baselineContent, ignoredFields) &&
+		!IsContentEqual(deployedContent, localContent, ignoredFields)
+}
+
+// Resolve the conflict of a resource edited on the server according to the --conflict-strategy flag. Returns whether
+// the local file should be imported. The fail strategy stops the import and fails the run after the summary.
+func ResolveConflict(resourceType string, resourceName string) (bool, error) {
+
+	message := fmt.Sprintf("%s: %s is edited on the server since the last export and differs from the local file.",
+		resourceType, resourceName)
+	switch CONFLICT_STRATEGY {
+	case CONFLICT_STRATEGY_THEIRS:
+		LogWarning(WARNING_CONFLICT, message+" The server version is kept.")
+		AddConflictedResourceToSummary(resourceType, resourceName)
+		EmitResourceFinished(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED)
+		recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_CONFLICT)
+		return false, nil
+	case CONFLICT_STRATEGY_FAIL:
+		isConflictAborted = true
+		RequestStop()
+		return false, fmt.Errorf("%s Aborting the import since the conflict strategy is %s", message,
+			CONFLICT_STRATEGY_FAIL)
+	default:
+		LogWarning(WARNING_CONFLICT, message+" The server version is overwritten.")
+		return true, nil
+	}
+}
+
+// Exit with a non-zero exit code after the summary is printed, if the import is aborted due to a conflict.
+func ExitIfConflictAborted() {
+
+	if isConflictAborted {
+		log.Println("Import aborted due to a conflict. The remaining resources are not imported.")
+		os.Exit(1)
+	}
+}
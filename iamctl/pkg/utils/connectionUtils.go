@@ -0,0 +1,129 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DEFAULT_PREFLIGHT_TIMEOUT = 10
+
+// Maximum duration of the pre-flight check run before connecting to the server. The check is skipped if not set.
+var PREFLIGHT_TIMEOUT time.Duration
+
+// Suggestions shown when the token request fails with the given status.
+var tokenErrorRemediations = map[int]string{
+	http.StatusBadRequest:   "Check that the management application allows the client credentials grant type.",
+	http.StatusUnauthorized: "Check the client ID and the client secret.",
+	http.StatusNotFound:     "Check the server URL and the tenant domain.",
+}
+
+// Suggestions shown when the management API request fails with the given status.
+var apiErrorRemediations = map[int]string{
+	http.StatusUnauthorized: "Check that the management application has the correct scopes.",
+	http.StatusForbidden:    "Check that the management application has the correct scopes.",
+	http.StatusNotFound:     "Check the server URL and the tenant domain.",
+}
+
+// Check that an access token can be obtained with the given server configs and that it can be used to call
+// the management APIs. The returned error includes the HTTP status and a suggestion to fix the configs.
+func CheckServerConnection(serverConfigs ServerConfigs) error {
+
+	client, err := NewHttpClient(serverConfigs)
+	if err != nil {
+		return fmt.Errorf("error when configuring the HTTP client: %s", err)
+	}
+	serverUrl := strings.TrimSuffix(serverConfigs.ServerUrl, "/")
+	tenantUrl := serverUrl + "/t/" + serverConfigs.TenantDomain
+
+	body := url.Values{}
+	body.Set("grant_type", "client_credentials")
+	body.Set("scope", SCOPE)
+	req, err := http.NewRequestWithContext(GetRequestContext(), http.MethodPost, tenantUrl+"/oauth2/token", strings.NewReader(body.Encode()))
+	if err != nil {
+		return fmt.Errorf("error when creating the token request: %s", err)
+	}
+	req.SetBasicAuth(serverConfigs.ClientId, serverConfigs.ClientSecret)
+	req.Header.Set("Content-Type", MEDIA_TYPE_FORM)
+
+	var response oAuthResponse
+	if err := sendConnectionCheckRequest(client, req, &response, tokenErrorRemediations); err != nil {
+		return fmt.Errorf("error when getting the access token: %s", err)
+	}
+
+	req, err = http.NewRequestWithContext(GetRequestContext(), http.MethodGet, tenantUrl+"/api/server/v1/applications?limit=1", nil)
+	if err != nil {
+		return fmt.Errorf("error when creating the management API request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+response.AccessToken)
+	if err := sendConnectionCheckRequest(client, req, nil, apiErrorRemediations); err != nil {
+		return fmt.Errorf("error when calling the management API: %s", err)
+	}
+	return nil
+}
+
+func sendConnectionCheckRequest(client *http.Client, req *http.Request, response interface{}, remediations map[int]string) error {
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s. Check that the server URL is correct and the server is running", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		remediation, ok := remediations[resp.StatusCode]
+		if !ok {
+			remediation = "Check the server logs for more details."
+		}
+		return fmt.Errorf("response status: %s. %s", resp.Status, remediation)
+	}
+	if response != nil {
+		return json.NewDecoder(resp.Body).Decode(response)
+	}
+	return nil
+}
+
+// Check that the server responds to the health check API within the given timeout, so that the run fails with a
+// clear message when the server is down instead of failing with a timeout for each resource.
+func RunPreflightCheck(serverConfigs ServerConfigs, timeout time.Duration) error {
+
+	client, err := NewHttpClient(serverConfigs)
+	if err != nil {
+		return fmt.Errorf("error when configuring the HTTP client: %s", err)
+	}
+	client.Timeout = timeout
+	serverUrl := strings.TrimSuffix(serverConfigs.ServerUrl, "/")
+
+	resp, err := client.Get(serverUrl + HEALTH_CHECK_PATH)
+	if err != nil {
+		return fmt.Errorf("server at %s did not respond within %s. Check that the server URL is correct and the "+
+			"server is running. %s", serverUrl, timeout, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("server at %s is not available. Health check response status: %s", serverUrl, resp.Status)
+	}
+	return nil
+}
@@ -18,53 +18,171 @@
 
 package utils
 
+import "time"
+
 // Resource type configs
 const APPLICATIONS_CONFIG = "APPLICATIONS"
 const IDP_CONFIG = "IDENTITY_PROVIDERS"
 const CLAIM_CONFIG = "CLAIMS"
 const USERSTORES_CONFIG = "USERSTORES"
+const EMAIL_TEMPLATES_CONFIG = "EMAIL_TEMPLATES"
+const USERS_CONFIG = "USERS"
+const XACML_POLICIES_CONFIG = "XACML_POLICIES"
+const ROLES_CONFIG = "ROLES"
+const API_RESOURCES_CONFIG = "API_RESOURCES"
+const GOVERNANCE_CONNECTORS_CONFIG = "GOVERNANCE_CONNECTORS"
+const BRANDING_CONFIG = "BRANDING"
+const OIDC_SCOPES_CONFIG = "OIDC_SCOPES"
+const NOTIFICATION_SENDERS_CONFIG = "NOTIFICATION_SENDERS"
+const WORKFLOWS_CONFIG = "WORKFLOWS"
+const CORS_CONFIG = "CORS"
+const ORGANIZATIONS_CONFIG = "ORGANIZATIONS"
 
 // Tool configs
 const EXCLUDE_CONFIG = "EXCLUDE"
 const INCLUDE_ONLY_CONFIG = "INCLUDE_ONLY"
 const EXCLUDE_SECRETS_CONFIG = "EXCLUDE_SECRETS"
 const ALLOW_DELETE_CONFIG = "ALLOW_DELETE"
+const SYSTEM_IDPS_CONFIG = "SYSTEM_IDPS"
+const SYSTEM_CLAIMS_CONFIG = "SYSTEM_CLAIMS"
+const SYSTEM_ROLES_CONFIG = "SYSTEM_ROLES"
+const SYSTEM_APPS_CONFIG = "SYSTEM_APPS"
+const SYSTEM_API_RESOURCES_CONFIG = "SYSTEM_API_RESOURCES"
+const SCREENS_CONFIG = "SCREENS"
+const LOCALES_CONFIG = "LOCALES"
+const EXTERNALLY_MANAGED_CONFIG = "EXTERNALLY_MANAGED"
+const NAME_PATTERNS_CONFIG = "NAME_PATTERNS"
+const TEMPLATE_IDS_CONFIG = "TEMPLATE_IDS"
+const CREATORS_CONFIG = "CREATORS"
+const QUOTA_CONFIG = "QUOTA"
+const DELETE_EXTERNALLY_MANAGED_CONFIG = "DELETE_EXTERNALLY_MANAGED"
+const ALLOW_IMPORT_CONFIG = "ALLOW_IMPORT"
+const IMPORT_STRATEGY_CONFIG = "IMPORT_STRATEGY"
+const AZURE_AD_GROUP_MAPPINGS_CONFIG = "AZURE_AD_GROUP_MAPPINGS"
 
 // Keyword configs
 const KEYWORD_MAPPINGS_CONFIG = "KEYWORD_MAPPINGS"
+const TENANTS_CONFIG = "TENANTS"
+const ORGANIZATION_IDS_CONFIG = "ORGANIZATION_IDS"
 
 // Server configs
 const SERVER_URL_CONFIG = "SERVER_URL"
 const CLIENT_ID_CONFIG = "CLIENT_ID"
 const CLIENT_SECRET_CONFIG = "CLIENT_SECRET"
 const TENANT_DOMAIN_CONFIG = "TENANT_DOMAIN"
+const ORGANIZATION_ID_CONFIG = "ORGANIZATION_ID"
+const CLIENT_CERT_FILE_CONFIG = "CLIENT_CERT_FILE"
+const CLIENT_KEY_FILE_CONFIG = "CLIENT_KEY_FILE"
+const REQUEST_TIMEOUT_CONFIG = "REQUEST_TIMEOUT"
 const TOOL_CONFIG_PATH = "TOOL_CONFIG_PATH"
 const KEYWORD_CONFIG_PATH = "KEYWORD_CONFIG_PATH"
 const TOKEN_CONFIG = "TOKEN"
+const IAMCTL_ENV_CONFIG = "IAMCTL_ENV"
+const IAMCTL_CONFIG_PASSPHRASE = "IAMCTL_CONFIG_PASSPHRASE"
+const IAMCTL_CLIENT_KEY_PASSPHRASE = "IAMCTL_CLIENT_KEY_PASSPHRASE"
+const IAMCTL_KEYWORD_KEY = "IAMCTL_KW_KEY"
+const DEFAULT_ENV_SECTION = "default"
+const HOSTS_CONFIG = "HOSTS"
 
 // Resource types
 const APPLICATIONS = "Applications"
 const IDENTITY_PROVIDERS = "IdentityProviders"
 const CLAIMS = "Claims"
 const USERSTORES = "UserStores"
+const EMAIL_TEMPLATES = "EmailTemplates"
+const USERS = "Users"
+const XACML_POLICIES = "XacmlPolicies"
+const ROLES = "Roles"
+const API_RESOURCES = "ApiResources"
+const GOVERNANCE_CONNECTORS = "GovernanceConnectors"
+const BRANDING = "Branding"
+const OIDC_SCOPES = "OidcScopes"
+const NOTIFICATION_SENDERS = "NotificationSenders"
+const WORKFLOWS = "Workflows"
+const CORS = "Cors"
+const ORGANIZATIONS = "Organizations"
+
+// Resources referenced by other resource types
+const GROUPS = "Groups"
+const WORKFLOW_ASSOCIATIONS = "WorkflowAssociations"
+const AUTHENTICATORS = "Authenticators"
 
 // Config file names
 const SERVER_CONFIG_FILE = "serverConfig.json"
 const TOOL_CONFIG_FILE = "toolConfig.json"
 const KEYWORD_CONFIG_FILE = "keywordConfig.json"
+const GZIP_EXTENSION = ".gz"
+const CERTIFICATE_EXPIRY_WARNING_PERIOD = 30 * 24 * time.Hour
+const AUTH_SCRIPT_FILE_SUFFIX = ".authscript.js"
+const CLIENT_CERTIFICATE_FILE_SUFFIX = ".cert.pem"
+const XACML_POLICY_MANIFEST_FILE = "policies.yml"
 
 // Media types
 const MEDIA_TYPE_JSON = "application/json"
 const MEDIA_TYPE_XML = "application/xml"
 const MEDIA_TYPE_YAML = "application/yaml"
 const MEDIA_TYPE_FORM = "application/x-www-form-urlencoded"
+const MEDIA_TYPE_SOAP = "text/xml; charset=UTF-8"
 
+const HEALTH_CHECK_PATH = "/api/health-check/v1.0/health"
+const SERVER_WAIT_INTERVAL = 5 * time.Second
 const DEFAULT_TENANT_DOMAIN = "carbon.super"
 const SENSITIVE_FIELD_MASK = "'********'"
 const RESIDENT_IDP_NAME = "LOCAL"
+const PRIMARY_USERSTORE_DOMAIN = "PRIMARY"
+const FILE_BASED_IDP_NAME = "FILE_BASED"
+const LOCAL_CLAIM_DIALECT_URI = "http://wso2.org/claims"
+const LOCAL_CLAIM_DIALECT_ID = "local"
+const SYSTEM_CLAIM_PROPERTY = "isSystemClaim"
+const BUSINESS_API_RESOURCE_TYPE = "BUSINESS"
 const CONSOLE = "Console"
 const MY_ACCOUNT = "My Account"
+const DEFAULT_EMAIL_TEMPLATE_LOCALE = "en_US"
+const DEFAULT_BRANDING_LOCALE = "en-US"
 const OAUTH2 = "oauth2"
+const SAMLSSO = "samlsso"
+const TLS_CLIENT_AUTH = "tls_client_auth"
+const SELF_SIGNED_TLS_CLIENT_AUTH = "self_signed_tls_client_auth"
+
+// Server generated and sensitive fields that are ignored when comparing local and deployed resources.
+var applicationIgnoredFields = []string{
+	"applicationID",
+	"applicationResourceId",
+	"id",
+	"createdTime",
+	"lastModifiedTime",
+	"oauthConsumerSecret",
+	"ownerId",
+}
+
+// Fields masked in the exported files when secrets are excluded, in addition to the SENSITIVE_FIELDS server config.
+var defaultSensitiveFields = []string{"oauthConsumerSecret"}
+
+// Names of the applications created by WSO2 products that integrate with the identity server,
+// such as the key manager applications created by WSO2 API Manager.
+var defaultExternallyManagedAppNames = []string{
+	"*_PRODUCTION",
+	"*_SANDBOX",
+	"apim_publisher",
+	"apim_devportal",
+	"apim_admin",
+}
+
+// OIDC scopes created by the server, which are not deleted during import.
+var defaultOidcScopes = []string{"openid", "profile", "email", "address", "phone"}
+
+// Screens of the login flow with custom text, used when the SCREENS config under branding is not set.
+var defaultBrandingScreens = []string{
+	"common",
+	"login",
+	"sign-up",
+	"email-otp",
+	"sms-otp",
+	"totp",
+	"password-recovery",
+	"password-reset",
+	"password-reset-success",
+}
 
 // Error codes
 var ErrorCodes = map[int]string{
@@ -73,6 +191,7 @@ var ErrorCodes = map[int]string{
 	401: "Unauthorized access.\nPlease check your server configurations.",
 	403: "Forbidden request.",
 	404: "Resource not found for the given ID.",
+	405: "Method not allowed for the resource.",
 	409: "A resource with the same name already exists.",
 	500: "Internal server error.",
 }
@@ -116,9 +235,42 @@ var userStoreArrayIdentifiers = map[string]string{
 	"properties":             "name",
 }
 
+var apiResourceArrayIdentifiers = map[string]string{
+
+	"scopes": "name",
+}
+
+var governanceConnectorArrayIdentifiers = map[string]string{
+
+	"connectors": "name",
+	"properties": "name",
+}
+
+var roleArrayIdentifiers = map[string]string{
+
+	"permissions": "value",
+}
+
+var notificationSenderArrayIdentifiers = map[string]string{
+
+	"properties": "key",
+}
+
+var workflowArrayIdentifiers = map[string]string{
+
+	"steps":        "step",
+	"options":      "entity",
+	"associations": "associationName",
+}
+
+var organizationArrayIdentifiers = map[string]string{
+
+	"attributes": "key",
+}
+
 var claimArrayIdentifiers = map[string]string{
 
 	"properties":       "key",
-	"attributeMapping": "mappedAttribute",
+	"attributeMapping": "userstore",
 	"claims":           "id",
 }
@@ -0,0 +1,89 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+)
+
+// Context of the API calls. Cancelled when the tool is interrupted, or set by the clients of the client package.
+var requestContext context.Context
+
+var stopInterruptHandling func()
+
+// Set the context of the API calls sent after the call. The calls are sent with a background context if it is nil.
+func SetRequestContext(ctx context.Context) {
+
+	requestContext = ctx
+}
+
+// Get the context to send the API calls with.
+func GetRequestContext() context.Context {
+
+	if requestContext == nil {
+		return context.Background()
+	}
+	return requestContext
+}
+
+// Cancel the ongoing API calls when the tool is interrupted with Ctrl-C, so that the command completes with the
+// calls failed instead of being killed in the middle of writing a file. A second interrupt kills the tool.
+func StartInterruptHandling() {
+
+	StopInterruptHandling()
+	ctx, cancel := context.WithCancel(context.Background())
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-interrupt:
+			log.Println("Interrupted. Cancelling the ongoing operations.")
+			cancel()
+		case <-done:
+		}
+		signal.Stop(interrupt)
+	}()
+
+	SetRequestContext(ctx)
+	stopInterruptHandling = func() {
+		close(done)
+		cancel()
+	}
+}
+
+// Stop cancelling the API calls on interrupts, such as when the command handles the interrupts itself.
+func StopInterruptHandling() {
+
+	if stopInterruptHandling == nil {
+		return
+	}
+	stopInterruptHandling()
+	stopInterruptHandling = nil
+	SetRequestContext(nil)
+}
+
+// Check whether the ongoing operations are cancelled, so that the remaining resources can be skipped.
+func IsCancelled() bool {
+
+	return GetRequestContext().Err() != nil
+}
@@ -0,0 +1,198 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Resource types of the server that are not supported by the tool.
+var UNSUPPORTED_RESOURCE_TYPES = []string{"Groups", "Organizations", "Actions", "Secrets", "Consent purposes",
+	"Keystores", "Organization discovery", "Registration flows"}
+
+// Request to count the deployed resources of a resource type. The count is read from the countField of the response,
+// or from the length of the response when the response is a list. The counts of all the paths are added.
+type resourceCountQuery struct {
+	resourceType string
+	paths        []string
+	countField   string
+	// A path that is not found is counted as an empty list instead of an unavailable resource type.
+	notFoundAsEmpty bool
+}
+
+// Resource types without paths cannot be counted with a JSON request.
+var resourceCountQueries = []resourceCountQuery{
+	{resourceType: CLAIMS, paths: []string{""}},
+	{resourceType: OIDC_SCOPES, paths: []string{""}},
+	{resourceType: IDENTITY_PROVIDERS, paths: []string{"?limit=1"}, countField: "totalResults"},
+	{resourceType: API_RESOURCES, paths: []string{"?limit=1"}, countField: "totalResults"},
+	{resourceType: ROLES, paths: []string{"?count=1"}, countField: "totalResults"},
+	{resourceType: APPLICATIONS, paths: []string{"?limit=1"}, countField: "totalResults"},
+	{resourceType: USERSTORES, paths: []string{""}},
+	{resourceType: EMAIL_TEMPLATES, paths: []string{""}},
+	{resourceType: BRANDING},
+	{resourceType: GOVERNANCE_CONNECTORS, paths: []string{""}},
+	{resourceType: CORS, paths: []string{""}},
+	{resourceType: NOTIFICATION_SENDERS, paths: []string{"email", "sms"}, notFoundAsEmpty: true},
+	{resourceType: USERS, paths: []string{"?count=1"}, countField: "totalResults"},
+	{resourceType: WORKFLOWS, paths: []string{"?limit=1"}, countField: "totalResults"},
+	{resourceType: XACML_POLICIES},
+	{resourceType: ORGANIZATIONS},
+}
+
+const COVERAGE_PERMISSION_DENIED = "permission denied"
+const COVERAGE_NOT_AVAILABLE = "not available in the server"
+const COVERAGE_NOT_COUNTABLE = "not countable"
+
+type ResourceCoverage struct {
+	ResourceType string
+	// The number of deployed resources, or -1 if it is unknown.
+	Deployed int
+	Managed  int
+	// Reason for an unknown number of deployed resources.
+	Reason string
+}
+
+func GetResourceCoverage(managedCounts map[string]int) []ResourceCoverage {
+
+	var coverages []ResourceCoverage
+	for _, query := range resourceCountQueries {
+		coverage := ResourceCoverage{ResourceType: query.resourceType, Managed: managedCounts[query.resourceType]}
+		if isResourceTypeDenied(query.resourceType) {
+			coverage.Deployed = -1
+			coverage.Reason = COVERAGE_PERMISSION_DENIED
+			coverages = append(coverages, coverage)
+			continue
+		}
+		deployed, err := countDeployedResources(query)
+		if err != nil {
+			LogDebug(fmt.Sprintf("Error when counting %s: %s", query.resourceType, err))
+			coverage.Deployed = -1
+			coverage.Reason = getCoverageErrorReason(err)
+		} else {
+			coverage.Deployed = deployed
+		}
+		coverages = append(coverages, coverage)
+	}
+	return coverages
+}
+
+func countDeployedResources(query resourceCountQuery) (int, error) {
+
+	if len(query.paths) == 0 {
+		return -1, errors.New(COVERAGE_NOT_COUNTABLE)
+	}
+	count := 0
+	for _, path := range query.paths {
+		body, err := SendJsonRequest(GetRequestContext(), http.MethodGet, query.resourceType, path, nil)
+		if errors.Is(err, ErrResourceNotFound) && query.notFoundAsEmpty {
+			continue
+		} else if err != nil {
+			return -1, err
+		}
+		if query.countField == "" {
+			var resources []interface{}
+			if err := json.Unmarshal(body, &resources); err != nil {
+				return -1, fmt.Errorf("error when unmarshalling the retrieved list. %w", err)
+			}
+			count += len(resources)
+			continue
+		}
+		var response map[string]interface{}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return -1, fmt.Errorf("error when unmarshalling the retrieved list. %w", err)
+		}
+		total, ok := response[query.countField].(float64)
+		if !ok {
+			return -1, fmt.Errorf("the %s field is not found in the response", query.countField)
+		}
+		count += int(total)
+	}
+	return count, nil
+}
+
+func getCoverageErrorReason(err error) string {
+
+	switch {
+	case errors.Is(err, ErrPermissionDenied):
+		return COVERAGE_PERMISSION_DENIED
+	case errors.Is(err, ErrResourceNotFound):
+		return COVERAGE_NOT_AVAILABLE
+	case err.Error() == COVERAGE_NOT_COUNTABLE:
+		return COVERAGE_NOT_COUNTABLE
+	}
+	return "error"
+}
+
+// Get the number of resources of each resource type processed in the current run.
+func GetRunManagedCounts() map[string]int {
+
+	managedCounts := make(map[string]int)
+	for resourceType, summary := range ResourceSummaries {
+		managedCounts[resourceType] = summary.SuccessfulExport + summary.SuccessfulImport + summary.SuccessfulUpdate +
+			summary.Unchanged + summary.Failed
+	}
+	return managedCounts
+}
+
+// Get the number of resources of each resource type in the local directory.
+func GetLocalManagedCounts(inputDirPath string) (map[string]int, error) {
+
+	resourceFiles, err := getLocalResourceFiles(inputDirPath)
+	if err != nil {
+		return nil, err
+	}
+	// Files of the same resource, such as the auth scripts of the applications, are counted once.
+	resourceNames := make(map[string]bool)
+	managedCounts := make(map[string]int)
+	for _, resourceFile := range resourceFiles {
+		key := resourceFile.resourceType + "/" + resourceFile.resourceName
+		if resourceNames[key] {
+			continue
+		}
+		resourceNames[key] = true
+		managedCounts[resourceFile.resourceType]++
+	}
+	return managedCounts, nil
+}
+
+func PrintCoverage(coverages []ResourceCoverage) {
+
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE TYPE\tDEPLOYED\tMANAGED\tUNMANAGED")
+	for _, coverage := range coverages {
+		if coverage.Deployed < 0 {
+			fmt.Fprintf(writer, "%s\tunknown (%s)\t%d\t-\n", coverage.ResourceType, coverage.Reason, coverage.Managed)
+			continue
+		}
+		unmanaged := coverage.Deployed - coverage.Managed
+		if unmanaged < 0 {
+			unmanaged = 0
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", coverage.ResourceType, coverage.Deployed, coverage.Managed, unmanaged)
+	}
+	writer.Flush()
+	fmt.Printf("Resource types not supported by the tool: %s\n", strings.Join(UNSUPPORTED_RESOURCE_TYPES, ", "))
+}
@@ -0,0 +1,510 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// File with the resources referenced by each local application and identity provider, written to the base directory.
+const DEPENDENCIES_FILE = "dependencies.yaml"
+
+const dependenciesFileHeader = "# Resources referenced by the local applications and identity providers. Generated by iamctl, do not edit.\n"
+
+// Values of the secrets in the dependency file, other than the keyword placeholders.
+const SECRET_MASKED = "masked"
+const SECRET_INLINE = "inline"
+
+// Import the applications even if they reference identity providers that are not found locally or in the target
+// environment. Set by the --continue-on-missing-deps flag.
+var CONTINUE_ON_MISSING_DEPS bool
+
+type DependencyGraph struct {
+	Applications      map[string]ResourceDependencies `yaml:"applications,omitempty"`
+	IdentityProviders map[string]ResourceDependencies `yaml:"identityProviders,omitempty"`
+}
+
+type ResourceDependencies struct {
+	IdentityProviders []string `yaml:"identityProviders,omitempty"`
+	Claims            []string `yaml:"claims,omitempty"`
+	// Scopes of the application audience roles, grouped by the identifier of the local API resource of the scope.
+	ApiResources map[string][]string `yaml:"apiResources,omitempty"`
+	// Scopes that are not found in the local API resources, such as the scopes of the system APIs.
+	Scopes  []string          `yaml:"scopes,omitempty"`
+	Secrets map[string]string `yaml:"secrets,omitempty"`
+}
+
+type dependencyClaimConfig struct {
+	ClaimMappings []struct {
+		LocalClaim struct {
+			ClaimUri string `yaml:"claimUri"`
+		} `yaml:"localClaim"`
+	} `yaml:"claimMappings"`
+}
+
+type dependencyApplication struct {
+	ClaimConfig                          dependencyClaimConfig `yaml:"claimConfig"`
+	LocalAndOutBoundAuthenticationConfig struct {
+		AuthenticationSteps []struct {
+			FederatedIdentityProviders []struct {
+				IdentityProviderName string `yaml:"identityProviderName"`
+			} `yaml:"federatedIdentityProviders"`
+		} `yaml:"authenticationSteps"`
+	} `yaml:"localAndOutBoundAuthenticationConfig"`
+	MfaConfig struct {
+		Steps []struct {
+			Options []struct {
+				Idp string `yaml:"idp"`
+			} `yaml:"options"`
+		} `yaml:"steps"`
+	} `yaml:"mfaConfig"`
+	OutboundProvisioningConfig struct {
+		ProvisioningIdentityProviders []struct {
+			IdentityProviderName string `yaml:"identityProviderName"`
+		} `yaml:"provisioningIdentityProviders"`
+	} `yaml:"outboundProvisioningConfig"`
+	ProvisioningConfig struct {
+		OutboundProvisioningIdps []struct {
+			Idp string `yaml:"idp"`
+		} `yaml:"outboundProvisioningIdps"`
+	} `yaml:"provisioningConfig"`
+}
+
+type dependencyIdentityProvider struct {
+	ClaimConfig dependencyClaimConfig `yaml:"claimConfig"`
+}
+
+type dependencyRole struct {
+	Audience struct {
+		Display string `yaml:"display"`
+		Type    string `yaml:"type"`
+	} `yaml:"audience"`
+	Permissions []struct {
+		Value string `yaml:"value"`
+	} `yaml:"permissions"`
+}
+
+type dependencyApiResource struct {
+	Identifier string `yaml:"identifier"`
+	Scopes     []struct {
+		Name string `yaml:"name"`
+	} `yaml:"scopes"`
+}
+
+// Build the dependency graph from the local resource files. Keyword placeholders are kept as they are in the files.
+func BuildDependencyGraph(inputDirPath string) (DependencyGraph, error) {
+
+	graph := DependencyGraph{Applications: make(map[string]ResourceDependencies),
+		IdentityProviders: make(map[string]ResourceDependencies)}
+	resourceFiles, err := getLocalResourceFiles(inputDirPath)
+	if err != nil {
+		return graph, err
+	}
+
+	appScopes := make(map[string][]string)
+	scopeApiResources := make(map[string]string)
+	for _, resourceFile := range resourceFiles {
+		if IsReferencedFile(resourceFile.path) || !isDependencySource(resourceFile.resourceType) {
+			continue
+		}
+		fileContent, err := ReadResourceFile(resourceFile.path)
+		if err != nil {
+			return graph, fmt.Errorf("error when reading the file: %s. %s", resourceFile.path, err)
+		}
+
+		switch resourceFile.resourceType {
+		case APPLICATIONS:
+			var app dependencyApplication
+			if err := yaml.Unmarshal(fileContent, &app); err != nil {
+				return graph, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			var idpNames []string
+			for _, step := range app.LocalAndOutBoundAuthenticationConfig.AuthenticationSteps {
+				for _, idp := range step.FederatedIdentityProviders {
+					idpNames = append(idpNames, idp.IdentityProviderName)
+				}
+			}
+			for _, step := range app.MfaConfig.Steps {
+				for _, option := range step.Options {
+					if option.Idp != RESIDENT_IDP_NAME {
+						idpNames = append(idpNames, option.Idp)
+					}
+				}
+			}
+			for _, idp := range app.OutboundProvisioningConfig.ProvisioningIdentityProviders {
+				idpNames = append(idpNames, idp.IdentityProviderName)
+			}
+			for _, idp := range app.ProvisioningConfig.OutboundProvisioningIdps {
+				idpNames = append(idpNames, idp.Idp)
+			}
+			graph.Applications[resourceFile.resourceName] = ResourceDependencies{
+				IdentityProviders: sortedUnique(idpNames),
+				Claims:            getDependentClaims(app.ClaimConfig),
+				Secrets:           getDependentSecrets(string(fileContent)),
+			}
+		case IDENTITY_PROVIDERS:
+			var idp dependencyIdentityProvider
+			if err := yaml.Unmarshal(fileContent, &idp); err != nil {
+				return graph, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			graph.IdentityProviders[resourceFile.resourceName] = ResourceDependencies{
+				Claims:  getDependentClaims(idp.ClaimConfig),
+				Secrets: getDependentSecrets(string(fileContent)),
+			}
+		case ROLES:
+			var role dependencyRole
+			if err := yaml.Unmarshal(fileContent, &role); err != nil {
+				return graph, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			if !strings.EqualFold(role.Audience.Type, "application") {
+				continue
+			}
+			for _, permission := range role.Permissions {
+				appScopes[role.Audience.Display] = append(appScopes[role.Audience.Display], permission.Value)
+			}
+		case API_RESOURCES:
+			var apiResource dependencyApiResource
+			if err := yaml.Unmarshal(fileContent, &apiResource); err != nil {
+				return graph, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			for _, scope := range apiResource.Scopes {
+				scopeApiResources[scope.Name] = apiResource.Identifier
+			}
+		}
+	}
+
+	// The scopes of an application are the permissions of the roles with the audience of the application.
+	for appName, dependencies := range graph.Applications {
+		for _, scope := range sortedUnique(appScopes[appName]) {
+			identifier, ok := scopeApiResources[scope]
+			if !ok {
+				dependencies.Scopes = append(dependencies.Scopes, scope)
+				continue
+			}
+			if dependencies.ApiResources == nil {
+				dependencies.ApiResources = make(map[string][]string)
+			}
+			dependencies.ApiResources[identifier] = append(dependencies.ApiResources[identifier], scope)
+		}
+		graph.Applications[appName] = dependencies
+	}
+	return graph, nil
+}
+
+// Sort the resource types so that each resource type comes after the resource types it depends on. The given order is
+// kept for the resource types that do not depend on each other.
+func SortByDependencies(resourceTypes []string, dependencies map[string][]string) ([]string, error) {
+
+	var sorted []string
+	for len(sorted) < len(resourceTypes) {
+		added := false
+		for _, resourceType := range resourceTypes {
+			if Contains(sorted, resourceType) {
+				continue
+			}
+			ready := true
+			for _, dependency := range dependencies[resourceType] {
+				if Contains(resourceTypes, dependency) && !Contains(sorted, dependency) {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				sorted = append(sorted, resourceType)
+				added = true
+				break
+			}
+		}
+		if !added {
+			var remaining []string
+			for _, resourceType := range resourceTypes {
+				if !Contains(sorted, resourceType) {
+					remaining = append(remaining, resourceType)
+				}
+			}
+			return nil, fmt.Errorf("cyclic dependency between the resource types: %s", strings.Join(remaining, ", "))
+		}
+	}
+	return sorted, nil
+}
+
+// Fail the import before importing any resource if a local application references identity providers that are
+// neither deployed in the target environment nor imported with the application. The available identity providers are
+// retrieved only if an application references an identity provider.
+func CheckIdpDependencies(inputDirPath string, getAvailableIdps func() ([]string, error)) error {
+
+	if IsResourceTypeExcluded(APPLICATIONS) {
+		return nil
+	}
+	graph, err := BuildDependencyGraph(inputDirPath)
+	if err != nil {
+		return err
+	}
+	var appNames []string
+	referencedIdps := make(map[string][]string)
+	for appName, dependencies := range graph.Applications {
+		if !IsResourceIncluded(appName) || IsResourceExcluded(appName, TOOL_CONFIGS.ApplicationConfigs) {
+			continue
+		}
+		appKeywordMapping := ResolveAdvancedKeywordMapping(appName, KEYWORD_CONFIGS.ApplicationConfigs)
+		for _, idpName := range dependencies.IdentityProviders {
+			idpName = ReplaceKeywords(idpName, appKeywordMapping)
+			if !IsSystemIdp(idpName) {
+				referencedIdps[appName] = append(referencedIdps[appName], idpName)
+			}
+		}
+		if len(referencedIdps[appName]) > 0 {
+			appNames = append(appNames, appName)
+		}
+	}
+	if len(appNames) == 0 {
+		return nil
+	}
+	availableIdps, err := getAvailableIdps()
+	if err != nil {
+		return fmt.Errorf("error when checking the identity providers referenced by the applications: %s", err)
+	}
+
+	sort.Strings(appNames)
+	var missingDependencies []string
+	for _, appName := range appNames {
+		var missingIdps []string
+		for _, idpName := range referencedIdps[appName] {
+			if !Contains(availableIdps, idpName) {
+				missingIdps = append(missingIdps, idpName)
+			}
+		}
+		if len(missingIdps) > 0 {
+			missingDependencies = append(missingDependencies, fmt.Sprintf("%s: %s", appName, strings.Join(missingIdps, ", ")))
+		}
+	}
+	if len(missingDependencies) == 0 {
+		return nil
+	}
+	message := fmt.Sprintf("applications reference identity providers that are not found in the local files or "+
+		"the target environment:\n  %s", strings.Join(missingDependencies, "\n  "))
+	if CONTINUE_ON_MISSING_DEPS {
+		LogWarning(WARNING_MISSING_DEPENDENCY, message)
+		return nil
+	}
+	return fmt.Errorf("%s\nImport the identity providers first, or use the --continue-on-missing-deps flag "+
+		"if they are managed elsewhere", message)
+}
+
+// Write the dependency file of the local resources. The file is written from the local files on each run, so that the
+// entries of the removed resources are pruned, and is not rewritten if the dependencies are not changed.
+func WriteDependencyFile(inputDirPath string) error {
+
+	graph, err := BuildDependencyGraph(inputDirPath)
+	if err != nil {
+		return err
+	}
+	filePath := filepath.Join(inputDirPath, DEPENDENCIES_FILE)
+	if len(graph.Applications) == 0 && len(graph.IdentityProviders) == 0 {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error when removing the stale dependency file: %s", err)
+		}
+		return nil
+	}
+
+	content, err := yaml.Marshal(graph)
+	if err != nil {
+		return fmt.Errorf("error when writing the dependency file: %s", err)
+	}
+	content = append([]byte(dependenciesFileHeader), content...)
+	if existingContent, err := ioutil.ReadFile(filePath); err == nil && bytes.Equal(existingContent, content) {
+		return nil
+	}
+	if err := ioutil.WriteFile(filePath, content, 0644); err != nil {
+		return fmt.Errorf("error when writing the dependency file: %s", err)
+	}
+	return nil
+}
+
+// Get the dependency tree of an application or an identity provider, including the dependencies of the identity providers.
+func FormatDependencyTree(graph DependencyGraph, resourceName string) (string, error) {
+
+	var tree strings.Builder
+	if dependencies, ok := graph.Applications[resourceName]; ok {
+		fmt.Fprintf(&tree, "%s/%s\n", APPLICATIONS, resourceName)
+		writeDependencies(&tree, graph, dependencies, "  ")
+	}
+	if dependencies, ok := graph.IdentityProviders[resourceName]; ok {
+		fmt.Fprintf(&tree, "%s/%s\n", IDENTITY_PROVIDERS, resourceName)
+		writeDependencies(&tree, graph, dependencies, "  ")
+	}
+	if tree.Len() == 0 {
+		return "", fmt.Errorf("no application or identity provider found with the name: %s", resourceName)
+	}
+	return tree.String(), nil
+}
+
+// Get the applications and identity providers that reference the given identity provider, claim, API resource, scope
+// or secret keyword.
+func FormatReverseDependencies(graph DependencyGraph, resourceName string) (string, error) {
+
+	var dependents []string
+	for appName, dependencies := range graph.Applications {
+		if dependencies.references(resourceName) {
+			dependents = append(dependents, APPLICATIONS+"/"+appName)
+		}
+	}
+	for idpName, dependencies := range graph.IdentityProviders {
+		if dependencies.references(resourceName) {
+			dependents = append(dependents, IDENTITY_PROVIDERS+"/"+idpName)
+		}
+	}
+	if len(dependents) == 0 {
+		return "", fmt.Errorf("no local resource references: %s", resourceName)
+	}
+	sort.Strings(dependents)
+	return fmt.Sprintf("%s\n  Referenced by:\n    %s\n", resourceName, strings.Join(dependents, "\n    ")), nil
+}
+
+func (dependencies ResourceDependencies) references(resourceName string) bool {
+
+	if Contains(dependencies.IdentityProviders, resourceName) || Contains(dependencies.Claims, resourceName) ||
+		Contains(dependencies.Scopes, resourceName) {
+		return true
+	}
+	for identifier, scopes := range dependencies.ApiResources {
+		if identifier == resourceName || Contains(scopes, resourceName) {
+			return true
+		}
+	}
+	for _, value := range dependencies.Secrets {
+		if strings.Contains(value, "{{"+resourceName+"}}") {
+			return true
+		}
+	}
+	return false
+}
+
+func writeDependencies(tree *strings.Builder, graph DependencyGraph, dependencies ResourceDependencies, indent string) {
+
+	if len(dependencies.IdentityProviders) > 0 {
+		fmt.Fprintf(tree, "%sIdentity providers:\n", indent)
+		for _, idpName := range dependencies.IdentityProviders {
+			idpDependencies, ok := graph.IdentityProviders[idpName]
+			if !ok {
+				fmt.Fprintf(tree, "%s  %s (not found in the local files)\n", indent, idpName)
+				continue
+			}
+			fmt.Fprintf(tree, "%s  %s\n", indent, idpName)
+			writeDependencies(tree, graph, idpDependencies, indent+"    ")
+		}
+	}
+	writeDependencyList(tree, "Claims", dependencies.Claims, indent)
+	if len(dependencies.ApiResources) > 0 {
+		fmt.Fprintf(tree, "%sAPI resources:\n", indent)
+		var identifiers []string
+		for identifier := range dependencies.ApiResources {
+			identifiers = append(identifiers, identifier)
+		}
+		sort.Strings(identifiers)
+		for _, identifier := range identifiers {
+			fmt.Fprintf(tree, "%s  %s: %s\n", indent, identifier, strings.Join(dependencies.ApiResources[identifier], ", "))
+		}
+	}
+	writeDependencyList(tree, "Scopes", dependencies.Scopes, indent)
+	if len(dependencies.Secrets) > 0 {
+		fmt.Fprintf(tree, "%sSecrets:\n", indent)
+		var fields []string
+		for field := range dependencies.Secrets {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			fmt.Fprintf(tree, "%s  %s: %s\n", indent, field, dependencies.Secrets[field])
+		}
+	}
+}
+
+func writeDependencyList(tree *strings.Builder, title string, values []string, indent string) {
+
+	if len(values) == 0 {
+		return
+	}
+	fmt.Fprintf(tree, "%s%s:\n", indent, title)
+	for _, value := range values {
+		fmt.Fprintf(tree, "%s  %s\n", indent, value)
+	}
+}
+
+func isDependencySource(resourceType string) bool {
+
+	return resourceType == APPLICATIONS || resourceType == IDENTITY_PROVIDERS || resourceType == ROLES ||
+		resourceType == API_RESOURCES
+}
+
+func getDependentClaims(claimConfig dependencyClaimConfig) []string {
+
+	var claimUris []string
+	for _, claimMapping := range claimConfig.ClaimMappings {
+		claimUris = append(claimUris, claimMapping.LocalClaim.ClaimUri)
+	}
+	return sortedUnique(claimUris)
+}
+
+// Get the sensitive fields given in the file, with the keyword placeholders used for their values.
+func getDependentSecrets(fileContent string) map[string]string {
+
+	secrets := make(map[string]string)
+	for _, field := range GetSensitiveFields() {
+		re := regexp.MustCompile("(?m)^[ \\t]*(?:-[ \\t]+)?" + regexp.QuoteMeta(field) + ":[ \\t]*(.*?)[ \\t]*$")
+		for _, match := range re.FindAllStringSubmatch(fileContent, -1) {
+			value := match[1]
+			switch {
+			case value == "" || value == "null":
+				continue
+			case value == SENSITIVE_FIELD_MASK:
+				secrets[field] = SECRET_MASKED
+			case keywordPlaceholderRegex.MatchString(value):
+				secrets[field] = strings.Join(keywordPlaceholderRegex.FindAllString(value, -1), " ")
+			default:
+				secrets[field] = SECRET_INLINE
+			}
+		}
+	}
+	if len(secrets) == 0 {
+		return nil
+	}
+	return secrets
+}
+
+func sortedUnique(values []string) []string {
+
+	var uniqueValues []string
+	for _, value := range values {
+		if value != "" && !Contains(uniqueValues, value) {
+			uniqueValues = append(uniqueValues, value)
+		}
+	}
+	sort.Strings(uniqueValues)
+	return uniqueValues
+}
@@ -0,0 +1,349 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const ENCRYPTED_VALUE_PREFIX = "ENC("
+const ENCRYPTED_VALUE_SUFFIX = ")"
+const ENCRYPTED_KEYWORD_PREFIX = "enc:v1:"
+
+const saltLength = 16
+const keyLength = 32
+
+// Argon2id parameters used to derive the encryption key from the passphrase.
+const argon2Time = 1
+const argon2Memory = 64 * 1024
+const argon2Threads = 4
+
+// Decrypt the encrypted values in the server config file. Set by the --encrypted-config flag.
+var ENCRYPTED_CONFIG bool
+
+// Server config fields that are encrypted by the config encrypt command.
+var sensitiveServerConfigs = []string{CLIENT_SECRET_CONFIG}
+
+func EncryptValue(plainText string, passphrase string) (string, error) {
+
+	salt := make([]byte, saltLength)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return "", fmt.Errorf("error when generating the salt: %s", err)
+	}
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("error when generating the nonce: %s", err)
+	}
+
+	cipherText := gcm.Seal(nil, nonce, []byte(plainText), nil)
+	encryptedValue := append(append(salt, nonce...), cipherText...)
+	return ENCRYPTED_VALUE_PREFIX + base64.StdEncoding.EncodeToString(encryptedValue) + ENCRYPTED_VALUE_SUFFIX, nil
+}
+
+func DecryptValue(encryptedValue string, passphrase string) (string, error) {
+
+	if !IsEncryptedValue(encryptedValue) {
+		return encryptedValue, nil
+	}
+	encodedValue := strings.TrimSuffix(strings.TrimPrefix(encryptedValue, ENCRYPTED_VALUE_PREFIX), ENCRYPTED_VALUE_SUFFIX)
+	decodedValue, err := base64.StdEncoding.DecodeString(encodedValue)
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is not in the correct format: %s", err)
+	}
+	if len(decodedValue) < saltLength {
+		return "", fmt.Errorf("encrypted value is not in the correct format")
+	}
+
+	salt := decodedValue[:saltLength]
+	gcm, err := newGCM(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	if len(decodedValue) < saltLength+gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted value is not in the correct format")
+	}
+	nonce := decodedValue[saltLength : saltLength+gcm.NonceSize()]
+	cipherText := decodedValue[saltLength+gcm.NonceSize():]
+
+	plainText, err := gcm.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return "", fmt.Errorf("error when decrypting the value. Please check the passphrase")
+	}
+	return string(plainText), nil
+}
+
+func IsEncryptedValue(value string) bool {
+
+	return strings.HasPrefix(value, ENCRYPTED_VALUE_PREFIX) && strings.HasSuffix(value, ENCRYPTED_VALUE_SUFFIX)
+}
+
+func EncryptServerConfigs(configFile []byte, passphrase string) ([]byte, error) {
+
+	return transformSensitiveConfigs(configFile, func(value string) (string, error) {
+		if IsEncryptedValue(value) {
+			return value, nil
+		}
+		return EncryptValue(value, passphrase)
+	})
+}
+
+func DecryptServerConfigs(configFile []byte, passphrase string) ([]byte, error) {
+
+	return transformSensitiveConfigs(configFile, func(value string) (string, error) {
+		return DecryptValue(value, passphrase)
+	})
+}
+
+func GetConfigPassphrase() (string, error) {
+
+	if passphrase := os.Getenv(IAMCTL_CONFIG_PASSPHRASE); passphrase != "" {
+		return passphrase, nil
+	}
+
+	fmt.Print("Enter the config passphrase: ")
+	passphrase, err := terminal.ReadPassword(int(syscall.Stdin))
+	fmt.Println()
+	if err != nil {
+		return "", fmt.Errorf("error when reading the passphrase: %s", err)
+	}
+	if len(passphrase) == 0 {
+		return "", fmt.Errorf("passphrase cannot be empty")
+	}
+	return string(passphrase), nil
+}
+
+// Read the base64 encoded AES key used to encrypt the keyword values from the given environment variable.
+func GetKeywordKey(keyEnv string) ([]byte, error) {
+
+	encodedKey := os.Getenv(keyEnv)
+	if encodedKey == "" {
+		return nil, fmt.Errorf("environment variable %s is not set", keyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encodedKey))
+	if err != nil {
+		return nil, fmt.Errorf("key in the environment variable %s is not base64 encoded", keyEnv)
+	}
+	if len(key) != 16 && len(key) != 24 && len(key) != 32 {
+		return nil, fmt.Errorf("key in the environment variable %s should be 16, 24 or 32 bytes long", keyEnv)
+	}
+	return key, nil
+}
+
+func EncryptKeywordValue(plainText string, key []byte) (string, error) {
+
+	gcm, err := newKeywordGCM(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("error when generating the nonce: %s", err)
+	}
+
+	cipherText := gcm.Seal(nonce, nonce, []byte(plainText), nil)
+	return ENCRYPTED_KEYWORD_PREFIX + base64.StdEncoding.EncodeToString(cipherText), nil
+}
+
+// Decrypt a keyword value. The returned errors do not include the encrypted value.
+func DecryptKeywordValue(encryptedValue string, key []byte) (string, error) {
+
+	if !IsEncryptedKeyword(encryptedValue) {
+		return encryptedValue, nil
+	}
+	decodedValue, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encryptedValue, ENCRYPTED_KEYWORD_PREFIX))
+	if err != nil {
+		return "", fmt.Errorf("encrypted value is not in the correct format")
+	}
+	gcm, err := newKeywordGCM(key)
+	if err != nil {
+		return "", err
+	}
+	if len(decodedValue) < gcm.NonceSize() {
+		return "", fmt.Errorf("encrypted value is not in the correct format")
+	}
+
+	nonce := decodedValue[:gcm.NonceSize()]
+	plainText, err := gcm.Open(nil, nonce, decodedValue[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("error when decrypting the value. Please check the key")
+	}
+	return string(plainText), nil
+}
+
+func IsEncryptedKeyword(value string) bool {
+
+	return strings.HasPrefix(value, ENCRYPTED_KEYWORD_PREFIX)
+}
+
+// Decrypt the encrypted keyword values in the keyword config file with the key in the given environment variable.
+// The decrypted values are registered to be redacted from the logs.
+func DecryptKeywordConfigs(configFile []byte, keyEnv string) ([]byte, error) {
+
+	if !strings.Contains(string(configFile), ENCRYPTED_KEYWORD_PREFIX) {
+		return configFile, nil
+	}
+
+	var configs map[string]interface{}
+	err := json.Unmarshal(configFile, &configs)
+	if err != nil {
+		return nil, fmt.Errorf("keyword configs are not in the correct format: %s", err)
+	}
+
+	var key []byte
+	err = decryptKeywords(configs, "", func(keyName string, value string) (string, error) {
+		if key == nil {
+			if key, err = GetKeywordKey(keyEnv); err != nil {
+				return "", fmt.Errorf("error when decrypting the keyword: %s. %s", keyName, err)
+			}
+		}
+		plainText, err := DecryptKeywordValue(value, key)
+		if err != nil {
+			return "", fmt.Errorf("error when decrypting the keyword: %s. %s", keyName, err)
+		}
+		RegisterSensitiveValue(plainText)
+		return plainText, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(configs)
+}
+
+func decryptKeywords(configs map[string]interface{}, parentKey string,
+	decrypt func(keyName string, value string) (string, error)) error {
+
+	for key, value := range configs {
+		keyName := key
+		if parentKey != "" {
+			keyName = parentKey + "." + key
+		}
+		switch typedValue := value.(type) {
+		case string:
+			if !IsEncryptedKeyword(typedValue) {
+				continue
+			}
+			plainText, err := decrypt(keyName, typedValue)
+			if err != nil {
+				return err
+			}
+			configs[key] = plainText
+		case map[string]interface{}:
+			if err := decryptKeywords(typedValue, keyName, decrypt); err != nil {
+				return err
+			}
+		case []interface{}:
+			for i, item := range typedValue {
+				itemName := fmt.Sprintf("%s[%d]", keyName, i)
+				if itemValue, ok := item.(string); ok && IsEncryptedKeyword(itemValue) {
+					plainText, err := decrypt(itemName, itemValue)
+					if err != nil {
+						return err
+					}
+					typedValue[i] = plainText
+				} else if itemMap, ok := item.(map[string]interface{}); ok {
+					if err := decryptKeywords(itemMap, itemName, decrypt); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func newKeywordGCM(key []byte) (cipher.AEAD, error) {
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the cipher: %s", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the cipher: %s", err)
+	}
+	return gcm, nil
+}
+
+func newGCM(passphrase string, salt []byte) (cipher.AEAD, error) {
+
+	key := argon2.IDKey([]byte(passphrase), salt, argon2Time, argon2Memory, argon2Threads, keyLength)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the cipher: %s", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the cipher: %s", err)
+	}
+	return gcm, nil
+}
+
+func transformSensitiveConfigs(configFile []byte, transform func(string) (string, error)) ([]byte, error) {
+
+	var configs map[string]interface{}
+	err := json.Unmarshal(configFile, &configs)
+	if err != nil {
+		return nil, fmt.Errorf("server configs are not in the correct format: %s", err)
+	}
+
+	for key, value := range configs {
+		// Transform the sensitive configs in environment sections as well.
+		if section, ok := value.(map[string]interface{}); ok {
+			if err := transformConfigs(section, transform); err != nil {
+				return nil, fmt.Errorf("error in environment: %s. %s", key, err)
+			}
+		}
+	}
+	if err := transformConfigs(configs, transform); err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(configs, "", "  ")
+}
+
+func transformConfigs(configs map[string]interface{}, transform func(string) (string, error)) error {
+
+	for _, key := range sensitiveServerConfigs {
+		value, ok := configs[key].(string)
+		if !ok || value == "" {
+			continue
+		}
+		transformedValue, err := transform(value)
+		if err != nil {
+			return err
+		}
+		configs[key] = transformedValue
+	}
+	return nil
+}
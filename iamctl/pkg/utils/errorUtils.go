@@ -41,6 +41,18 @@ type FailedOperation struct {
 	ClaimURI    string `json:"claimURI,omitempty"`
 }
 
+// Error of a claim dialect import request with the claims that could not be imported.
+type ClaimImportError struct {
+	Message          string
+	FailedOperations []FailedOperation
+}
+
+func (e *ClaimImportError) Error() string {
+
+	return fmt.Sprintf("error response for the import request: %s\n%s", e.Message,
+		strings.Join(collectFailedOperations(e.FailedOperations), "\n"))
+}
+
 func handleClaimImportErrorResponse(resp *http.Response) error {
 
 	responseBody, err := ioutil.ReadAll(resp.Body)
@@ -54,8 +66,7 @@ func handleClaimImportErrorResponse(resp *http.Response) error {
 		return fmt.Errorf("failed to parse error response: %s", err.Error())
 	}
 
-	errorMessages := collectFailedOperations(errorResponse.FailedOperations)
-	return fmt.Errorf("error response for the import request: %s\n%s", errorResponse.Message, strings.Join(errorMessages, "\n"))
+	return &ClaimImportError{Message: errorResponse.Message, FailedOperations: errorResponse.FailedOperations}
 }
 
 func collectFailedOperations(failedOperations []FailedOperation) []string {
@@ -0,0 +1,304 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Sensitivity levels of the exported files
+const CLASSIFICATION_PUBLIC = "PUBLIC"
+const CLASSIFICATION_SENSITIVE = "SENSITIVE"
+const CLASSIFICATION_RESTRICTED = "RESTRICTED"
+
+const CLASSIFICATION_COMMENT_PREFIX = "# CLASSIFICATION: "
+
+// PEM headers of certificates and private keys, in plain text and base64 encoded forms.
+var restrictedContentMarkers = []string{"-----BEGIN ", "LS0tLS1CRUdJTi"}
+
+// Write only the resources that differ from the last committed state. Set by the --only-changed flag.
+var ONLY_CHANGED bool
+
+// Export only the resources modified after the given time. Set by the --since flag.
+var SINCE time.Time
+
+// Compress each exported file with gzip. Set by the --gzip flag.
+var GZIP_EXPORT bool
+
+// Add a comment with the sensitivity level to each exported YAML file. Set by the --prefix-sensitive-comments flag.
+var PREFIX_SENSITIVE_COMMENTS bool
+
+// Remove the fields with null values from the exported YAML files. Set by the --omit-null flag.
+var OMIT_NULL bool
+
+// Orders of the keys in the exported YAML files
+const KEY_ORDER_NONE = "none"
+const KEY_ORDER_ALPHABETICAL = "alphabetical"
+const KEY_ORDER_CANONICAL = "canonical"
+
+var KEY_ORDERS = []string{KEY_ORDER_NONE, KEY_ORDER_ALPHABETICAL, KEY_ORDER_CANONICAL}
+
+// Order of the keys in the exported YAML files. Set by the --sort-keys flag.
+var KEY_ORDER = KEY_ORDER_NONE
+
+// Keys written before the other keys of a mapping in the canonical order, so that the identity of a resource comes
+// first. The type tag placeholder always comes first, since the type tags are restored in front of the mapping.
+var canonicalLeadingKeys = []string{"1typeTag", "applicationName", "identityProviderName", "name", "displayName",
+	"description"}
+
+// Generate an OpenAPI spec from the authorized API scopes of each OAuth2 application. Set by the
+// --generate-openapi-specs flag.
+var GENERATE_OPENAPI_SPECS bool
+
+func ParseSince(since string) error {
+
+	if since == "" {
+		return nil
+	}
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return fmt.Errorf("invalid timestamp: %s. The timestamp should be in the RFC3339 format. Ex: 2024-01-02T15:04:05Z", since)
+	}
+	SINCE = sinceTime
+	return nil
+}
+
+// Warn that the resources of a type are exported completely with the --since flag, since the server does not return
+// a modification time for them.
+func WarnUnfilteredSince(resourceType string) {
+
+	if SINCE.IsZero() {
+		return
+	}
+	log.Printf("Warning: The resources of %s do not carry a modification time, and are exported completely "+
+		"regardless of the --since flag.", resourceType)
+}
+
+func IsModifiedSince(lastModified string) bool {
+
+	// Modification times are given in the RFC3339 format or as milliseconds since the epoch. Resources without a
+	// valid modification time are always exported.
+	if SINCE.IsZero() {
+		return true
+	}
+	if modifiedTime, err := time.Parse(time.RFC3339, lastModified); err == nil {
+		return modifiedTime.After(SINCE)
+	}
+	if millis, err := strconv.ParseInt(lastModified, 10, 64); err == nil {
+		return time.Unix(0, millis*int64(time.Millisecond)).After(SINCE)
+	}
+	return true
+}
+
+func WriteExportedFile(exportedFileName string, content []byte) error {
+
+	if OMIT_NULL && IsYamlFile(exportedFileName) {
+		omittedContent, err := OmitNullFields(content)
+		if err != nil {
+			return fmt.Errorf("error when removing the null fields from the exported content: %w", err)
+		}
+		content = omittedContent
+	}
+	if KEY_ORDER != KEY_ORDER_NONE && IsYamlFile(exportedFileName) {
+		sortedContent, err := SortYamlKeys(content, KEY_ORDER)
+		if err != nil {
+			return fmt.Errorf("error when sorting the keys of the exported content: %w", err)
+		}
+		content = sortedContent
+	}
+	if EXCLUDE_CERTS && IsYamlFile(exportedFileName) {
+		content = ExcludeCertificates(content)
+	}
+	if PREFIX_SENSITIVE_COMMENTS && IsYamlFile(exportedFileName) {
+		content = append([]byte(CLASSIFICATION_COMMENT_PREFIX+ClassifyExportedContent(content)+"\n"), content...)
+	}
+
+	// Remove the file of the other format to avoid keeping two files for the same resource.
+	staleFileName := exportedFileName + GZIP_EXTENSION
+	if GZIP_EXPORT {
+		compressedContent, err := compress(content)
+		if err != nil {
+			return fmt.Errorf("error when compressing the exported content: %w", err)
+		}
+		staleFileName = exportedFileName
+		exportedFileName += GZIP_EXTENSION
+		content = compressedContent
+	}
+	if _, err := os.Stat(staleFileName); err == nil {
+		os.Remove(staleFileName)
+	}
+
+	if ONLY_CHANGED && !IsContentChanged(exportedFileName, content) {
+		log.Println("Resource is unchanged since the last commit. Skipping file: " + exportedFileName)
+		return nil
+	}
+
+	if WATCH_EXPORT {
+		if isExportedFileUnchanged(exportedFileName, content) {
+			return nil
+		}
+		log.Println("Resource changed on the server. Updating file: " + exportedFileName)
+		updatedFileCount++
+	}
+	countChangedFile(exportedFileName, content)
+
+	// The file is replaced atomically, so that an interrupt does not leave a partially written file.
+	err := writeFileAtomically(exportedFileName, content, 0644)
+	if err != nil {
+		return fmt.Errorf("error when writing the exported content to file: %w", err)
+	}
+	return nil
+}
+
+// Mask the null values of the given fields, which the server returns in place of the excluded secrets.
+func MaskSensitiveFields(content []byte, fields []string) []byte {
+
+	maskedContent := string(content)
+	for _, field := range fields {
+		re := regexp.MustCompile("(?m)(^[ \\t]*(?:-[ \\t]+)?" + regexp.QuoteMeta(field) + ":[ \\t]*)null[ \\t]*$")
+		maskedContent = re.ReplaceAllString(maskedContent, "${1}"+SENSITIVE_FIELD_MASK)
+	}
+	return []byte(maskedContent)
+}
+
+// Get the fields to be masked in the exported files, including the fields given in the SENSITIVE_FIELDS server config.
+func GetSensitiveFields() []string {
+
+	fields := append([]string{}, defaultSensitiveFields...)
+	for _, field := range SERVER_CONFIGS.SensitiveFields {
+		if field = strings.TrimSpace(field); field != "" && !Contains(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Remove the fields with null values from the YAML content. The null items of lists are kept.
+func OmitNullFields(content []byte) ([]byte, error) {
+
+	var data interface{}
+	if err := yaml.Unmarshal(ReplaceTypeTags(content), &data); err != nil {
+		return nil, fmt.Errorf("error when parsing the content to YAML. %w", err)
+	}
+	omittedContent, err := yaml.Marshal(removeNullFields(data))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the content without null fields. %w", err)
+	}
+	return AddTypeTags(omittedContent), nil
+}
+
+func ValidateKeyOrder(order string) error {
+
+	if !Contains(KEY_ORDERS, order) {
+		return fmt.Errorf("invalid key order: %s. Supported orders are: %s", order, strings.Join(KEY_ORDERS, ", "))
+	}
+	return nil
+}
+
+// Sort the keys of all mappings in the YAML content in the given order. The items of lists keep their order.
+func SortYamlKeys(content []byte, order string) ([]byte, error) {
+
+	if order == KEY_ORDER_NONE {
+		return content, nil
+	}
+	var data interface{}
+	if err := yaml.Unmarshal(ReplaceTypeTags(content), &data); err != nil {
+		return nil, fmt.Errorf("error when parsing the content to YAML. %w", err)
+	}
+	sortedContent, err := yaml.Marshal(sortKeys(data, order))
+	if err != nil {
+		return nil, fmt.Errorf("error when creating the content with sorted keys. %w", err)
+	}
+	return AddTypeTags(sortedContent), nil
+}
+
+func sortKeys(data interface{}, order string) interface{} {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		sortedMap := make(yaml.MapSlice, 0, len(v))
+		for key, value := range v {
+			sortedMap = append(sortedMap, yaml.MapItem{Key: key, Value: sortKeys(value, order)})
+		}
+		sort.Slice(sortedMap, func(i, j int) bool {
+			return isKeyBefore(fmt.Sprint(sortedMap[i].Key), fmt.Sprint(sortedMap[j].Key), order)
+		})
+		return sortedMap
+	case []interface{}:
+		for i, value := range v {
+			v[i] = sortKeys(value, order)
+		}
+	}
+	return data
+}
+
+func isKeyBefore(key, otherKey, order string) bool {
+
+	rank, otherRank := getKeyRank(key, order), getKeyRank(otherKey, order)
+	if rank != otherRank {
+		return rank < otherRank
+	}
+	// Keys differing only in case are ordered by their exact value to keep the order stable.
+	if !strings.EqualFold(key, otherKey) {
+		return strings.ToLower(key) < strings.ToLower(otherKey)
+	}
+	return key < otherKey
+}
+
+func getKeyRank(key, order string) int {
+
+	leadingKeys := canonicalLeadingKeys[:1]
+	if order == KEY_ORDER_CANONICAL {
+		leadingKeys = canonicalLeadingKeys
+	}
+	for i, leadingKey := range leadingKeys {
+		if key == leadingKey {
+			return i
+		}
+	}
+	return len(leadingKeys)
+}
+
+func ClassifyExportedContent(content []byte) string {
+
+	for _, marker := range restrictedContentMarkers {
+		if strings.Contains(string(content), marker) {
+			return CLASSIFICATION_RESTRICTED
+		}
+	}
+	if strings.Contains(string(content), SENSITIVE_FIELD_MASK) {
+		return CLASSIFICATION_SENSITIVE
+	}
+	return CLASSIFICATION_PUBLIC
+}
+
+func IsYamlFile(fileName string) bool {
+
+	fileExtension := strings.ToLower(GetFileInfo(fileName).FileExtension)
+	return fileExtension == ".yml" || fileExtension == ".yaml"
+}
@@ -0,0 +1,103 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"context"
+	"sync"
+)
+
+const DEFAULT_EXPORT_CONCURRENCY = 5
+const MAX_EXPORT_CONCURRENCY = 20
+
+// Number of resources fetched in parallel during the export. Set by the --concurrency flag.
+var EXPORT_CONCURRENCY = DEFAULT_EXPORT_CONCURRENCY
+
+type fetchResult struct {
+	value   interface{}
+	err     error
+	failure *junitFailureRecord
+}
+
+// Pool of workers that fetch the resources in parallel, in the order of the indexes. Only the fetching is done in
+// parallel, so that the files, the summary and the logs are written by the caller in the order of the resources.
+type FetchPool struct {
+	results  []chan fetchResult
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// The fetch function should send the API calls with the given context, which keeps the errors of the calls of each
+// resource apart from the calls sent in parallel.
+func StartFetchPool(count int, fetch func(ctx context.Context, index int) (interface{}, error)) *FetchPool {
+
+	pool := &FetchPool{results: make([]chan fetchResult, count), done: make(chan struct{})}
+	for i := range pool.results {
+		pool.results[i] = make(chan fetchResult, 1)
+	}
+
+	// The HTTP client is created before the workers are started, so that it is created once.
+	GetHttpClient()
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < count; i++ {
+			select {
+			case jobs <- i:
+			case <-pool.done:
+				return
+			}
+		}
+	}()
+	workers := EXPORT_CONCURRENCY
+	if workers < 1 {
+		workers = 1
+	}
+	for worker := 0; worker < workers && worker < count; worker++ {
+		go func() {
+			for i := range jobs {
+				// Resources scheduled before the run is interrupted are not fetched, and fail with the cancellation.
+				var value interface{}
+				ctx, failure := withJUnitFailureRecord(GetRequestContext())
+				err := ctx.Err()
+				if err == nil {
+					value, err = fetch(ctx, i)
+				}
+				pool.results[i] <- fetchResult{value: value, err: err, failure: failure}
+			}
+		}()
+	}
+	return pool
+}
+
+// Wait for the resource at the given index to be fetched. Should not be called after the pool is stopped.
+func (pool *FetchPool) Get(index int) (interface{}, error) {
+
+	result := <-pool.results[index]
+	moveJUnitFailure(result.failure)
+	return result.value, result.err
+}
+
+// Stop fetching the remaining resources, such as when the time budget is exhausted.
+func (pool *FetchPool) Stop() {
+
+	pool.stopOnce.Do(func() {
+		close(pool.done)
+	})
+}
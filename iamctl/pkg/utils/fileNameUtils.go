@@ -0,0 +1,295 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// File that maps the exported files to the names of their resources, for the resources whose names cannot be used
+// as file names as they are. The file is kept in the local directory along with the resource type folders, and has
+// a section for each resource type folder with the file names, without the extension, mapped to the resource names.
+const FILE_NAMES_FILE = "fileNames.yaml"
+
+const fileNamesFileHeader = "# Generated by iamctl. Maps the exported files to the names of the resources that " +
+	"cannot be used as file names.\n# Commit the file along with the resource files.\n"
+
+// Length of the suffix derived from the resource id, which is added to the file name when the names of two
+// resources map to the same file name.
+const FILE_NAME_SUFFIX_LENGTH = 8
+
+// Characters that are path separators or that are not allowed in file names on Windows.
+const invalidFileNameChars = `<>:"/\|?*`
+
+// Device names that cannot be used as file names on Windows, with or without an extension.
+var reservedFileNames = []string{"CON", "PRN", "AUX", "NUL", "COM1", "COM2", "COM3", "COM4", "COM5", "COM6", "COM7",
+	"COM8", "COM9", "LPT1", "LPT2", "LPT3", "LPT4", "LPT5", "LPT6", "LPT7", "LPT8", "LPT9"}
+
+type exportedFile struct {
+	fileName     string
+	resourceName string
+}
+
+// File names of the resources exported to a resource type folder in the current run.
+type exportedFileNames struct {
+	// Exported files by the lower case file name, since the file names are not case sensitive on Windows and macOS.
+	files map[string]exportedFile
+	// File names of the resources named differently in the mapping file before the export.
+	previousFileNames map[string]string
+}
+
+var exportedFileNamesByDir = make(map[string]*exportedFileNames)
+var exportedFileNamesMutex sync.Mutex
+
+// Get a file name for the resource name, without the extension, that is valid on all platforms. The path
+// separators and the characters not allowed on Windows are percent-encoded, along with the trailing dots and spaces,
+// and the reserved device names of Windows are prefixed with an underscore. Other names are returned as they are.
+func GetSafeFileName(resourceName string) string {
+
+	var safeName strings.Builder
+	trimmedName := strings.TrimRight(resourceName, ". ")
+	for i, char := range resourceName {
+		if char < 0x20 || char == 0x7f || strings.ContainsRune(invalidFileNameChars, char) || i >= len(trimmedName) {
+			fmt.Fprintf(&safeName, "%%%02X", char)
+		} else {
+			safeName.WriteRune(char)
+		}
+	}
+	fileName := safeName.String()
+	if Contains(reservedFileNames, strings.ToUpper(strings.SplitN(fileName, ".", 2)[0])) {
+		fileName = "_" + fileName
+	}
+	return fileName
+}
+
+// Start tracking the file names of the resources exported to the given resource type folder. The mapping file is
+// updated with WriteFileNameMapping after the resources are exported.
+func StartFileNameMapping(resourceDirPath string) {
+
+	exportedFileNamesMutex.Lock()
+	defer exportedFileNamesMutex.Unlock()
+	exportedFileNamesByDir[filepath.Clean(resourceDirPath)] = &exportedFileNames{
+		files:             make(map[string]exportedFile),
+		previousFileNames: readFileNameMapping(resourceDirPath),
+	}
+}
+
+// Get the name of the file, without the extension, to export the resource to. The file name given to the resource
+// in the previous exports is kept if it is available, so that the files are not renamed between exports. If the
+// safe file name of the resource is taken by another resource in the run, for example by a resource with a name
+// differing only by case, a suffix derived from the resource id is added to the file name.
+func GetExportedFileName(resourceDirPath string, resourceName string, resourceId string) string {
+
+	exportedFileNamesMutex.Lock()
+	defer exportedFileNamesMutex.Unlock()
+	fileNames, ok := exportedFileNamesByDir[filepath.Clean(resourceDirPath)]
+	if !ok {
+		return GetSafeFileName(resourceName)
+	}
+
+	var candidates []string
+	for fileName, name := range fileNames.previousFileNames {
+		if name == resourceName {
+			candidates = append(candidates, fileName)
+		}
+	}
+	sort.Strings(candidates)
+	idHash := sha256.Sum256([]byte(resourceId))
+	candidates = append(candidates, GetSafeFileName(resourceName),
+		GetSafeFileName(resourceName)+"-"+hex.EncodeToString(idHash[:])[:FILE_NAME_SUFFIX_LENGTH])
+
+	for _, fileName := range candidates {
+		file, isTaken := fileNames.files[strings.ToLower(fileName)]
+		if !isTaken || file.resourceName == resourceName {
+			fileNames.files[strings.ToLower(fileName)] = exportedFile{fileName, resourceName}
+			return fileName
+		}
+	}
+	// Resource ids are unique, so the file name with the suffix is taken only by a resource with the same id.
+	return candidates[len(candidates)-1]
+}
+
+// Update the mapping file with the file names of the resources exported to the given resource type folder that are
+// named differently. The entries of the resources that were not exported in the run are kept if their files exist.
+func WriteFileNameMapping(resourceDirPath string) error {
+
+	exportedFileNamesMutex.Lock()
+	fileNames, ok := exportedFileNamesByDir[filepath.Clean(resourceDirPath)]
+	delete(exportedFileNamesByDir, filepath.Clean(resourceDirPath))
+	exportedFileNamesMutex.Unlock()
+	if !ok {
+		return nil
+	}
+
+	// Only the files written in the export are mapped, so that the files with the resource names are kept for the
+	// resources that failed to export.
+	localFileNames := getLocalFileNames(resourceDirPath)
+	mapping := make(map[string]string)
+	exportedNames := make(map[string]bool)
+	for _, file := range fileNames.files {
+		if !localFileNames[file.fileName] {
+			continue
+		}
+		exportedNames[file.resourceName] = true
+		if file.fileName != file.resourceName {
+			mapping[file.fileName] = file.resourceName
+			removeLegacyFiles(resourceDirPath, file, fileNames.files)
+		}
+	}
+	for fileName, resourceName := range fileNames.previousFileNames {
+		_, isTaken := fileNames.files[strings.ToLower(fileName)]
+		if !exportedNames[resourceName] && !isTaken && localFileNames[fileName] {
+			mapping[fileName] = resourceName
+		}
+	}
+	return writeFileNameMapping(resourceDirPath, mapping)
+}
+
+// Get the name of the resource of a local file. The names of the resources exported to a file with a different
+// name are resolved with the mapping file, and the names of the other resources are derived from the file name. The
+// auth script and client certificate files resolve to the name of their application.
+func ResolveResourceName(resourceFilePath string) string {
+
+	fileName := getReferencingFileName(resourceFilePath)
+	resourceDirPath := filepath.Dir(filepath.Clean(resourceFilePath))
+
+	exportedFileNamesMutex.Lock()
+	fileNames, isExporting := exportedFileNamesByDir[resourceDirPath]
+	if isExporting {
+		if file, ok := fileNames.files[strings.ToLower(fileName)]; ok && file.fileName == fileName {
+			exportedFileNamesMutex.Unlock()
+			return file.resourceName
+		}
+	}
+	exportedFileNamesMutex.Unlock()
+
+	var mapping map[string]string
+	if isExporting {
+		mapping = fileNames.previousFileNames
+	} else {
+		mapping = readFileNameMapping(resourceDirPath)
+	}
+	if resourceName, ok := mapping[fileName]; ok {
+		return resourceName
+	}
+	return fileName
+}
+
+func readFileNameMapping(resourceDirPath string) map[string]string {
+
+	var mappings map[string]map[string]string
+	content, err := ioutil.ReadFile(filepath.Join(filepath.Dir(resourceDirPath), FILE_NAMES_FILE))
+	if err != nil || yaml.Unmarshal(content, &mappings) != nil || mappings[filepath.Base(resourceDirPath)] == nil {
+		return map[string]string{}
+	}
+	return mappings[filepath.Base(resourceDirPath)]
+}
+
+func writeFileNameMapping(resourceDirPath string, mapping map[string]string) error {
+
+	filePath := filepath.Join(filepath.Dir(resourceDirPath), FILE_NAMES_FILE)
+	var mappings map[string]map[string]string
+	existingContent, err := ioutil.ReadFile(filePath)
+	if err == nil {
+		if err := yaml.Unmarshal(existingContent, &mappings); err != nil {
+			return fmt.Errorf("error when parsing the file names mapping: %s", err)
+		}
+	}
+	if mappings == nil {
+		mappings = make(map[string]map[string]string)
+	}
+	if len(mapping) > 0 {
+		mappings[filepath.Base(resourceDirPath)] = mapping
+	} else {
+		delete(mappings, filepath.Base(resourceDirPath))
+	}
+
+	if len(mappings) == 0 {
+		if err := os.Remove(filePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error when removing the file names mapping: %s", err)
+		}
+		return nil
+	}
+	content, err := yaml.Marshal(mappings)
+	if err != nil {
+		return fmt.Errorf("error when writing the file names mapping: %s", err)
+	}
+	content = append([]byte(fileNamesFileHeader), content...)
+	if bytes.Equal(existingContent, content) {
+		return nil
+	}
+	if err := writeFileAtomically(filePath, content, 0644); err != nil {
+		return fmt.Errorf("error when writing the file names mapping: %s", err)
+	}
+	return nil
+}
+
+// Remove the files written with the resource name by the exports before the file names were mapped, since the
+// resource is exported to a file with a different name. The files with names taken by other resources in the run
+// are kept, since they can be the files of those resources on the file systems that are not case sensitive.
+func removeLegacyFiles(resourceDirPath string, exported exportedFile, exportedFiles map[string]exportedFile) {
+
+	if _, isTaken := exportedFiles[strings.ToLower(exported.resourceName)]; isTaken {
+		return
+	}
+	files, err := ioutil.ReadDir(resourceDirPath)
+	if err != nil {
+		return
+	}
+	for _, file := range files {
+		fileName := getReferencingFileName(file.Name())
+		if file.IsDir() || fileName != exported.resourceName {
+			continue
+		}
+		if err := os.Remove(filepath.Join(resourceDirPath, file.Name())); err != nil {
+			log.Println("Error when removing the file: ", file.Name(), err)
+			continue
+		}
+		log.Printf("Removed the file: %s, since the resource is exported to the files named: %s",
+			file.Name(), exported.fileName)
+	}
+}
+
+// Get the names of the files in the folder without the extension.
+func getLocalFileNames(dirPath string) map[string]bool {
+
+	fileNames := make(map[string]bool)
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return fileNames
+	}
+	for _, file := range files {
+		if !file.IsDir() {
+			fileNames[GetFileInfo(file.Name()).ResourceName] = true
+		}
+	}
+	return fileNames
+}
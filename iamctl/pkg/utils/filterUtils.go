@@ -0,0 +1,99 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Names or glob patterns of the resources to be imported. Set by the --include-only flag.
+var INCLUDE_ONLY []string
+
+// Prefix of the names of the resources managed in the run. Set by the --namespace flag.
+var NAMESPACE string
+
+func ParseIncludeFilter(filter string) []string {
+
+	var patterns []string
+	for _, pattern := range strings.Split(filter, ",") {
+		pattern = strings.TrimSpace(pattern)
+		if pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+func IsFilterActive() bool {
+
+	return len(INCLUDE_ONLY) > 0
+}
+
+func IsResourceIncluded(resourceName string) bool {
+
+	if !IsInNamespace(resourceName) {
+		return false
+	}
+	if !IsFilterActive() {
+		return true
+	}
+	return MatchesAnyPattern(resourceName, INCLUDE_ONLY)
+}
+
+func IsInNamespace(resourceName string) bool {
+
+	return NAMESPACE == "" || strings.HasPrefix(resourceName, NAMESPACE)
+}
+
+// Get the filter to list only the resources of the namespace, for the list APIs that support the sw operator.
+// Ex: name sw team-a-
+func GetNamespaceFilter(nameAttribute string) string {
+
+	if NAMESPACE == "" {
+		return ""
+	}
+	return nameAttribute + " sw " + NAMESPACE
+}
+
+func MatchesAnyPattern(value string, patterns []string) bool {
+
+	for _, pattern := range patterns {
+		if pattern == value {
+			return true
+		}
+		if matched, err := filepath.Match(pattern, value); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func IsDeleteAllowed() bool {
+
+	// Deletion is disabled when a filter is active, to avoid removing resources that are not part of the filter.
+	return TOOL_CONFIGS.AllowDelete && !IsFilterActive() && !NO_DELETE && !UPDATE_ONLY
+}
+
+func IsResourceTypeDeleteAllowed(resourceConfigs map[string]interface{}) bool {
+
+	// Deletion is allowed only with the ALLOW_DELETE config in the section of the resource type.
+	allowDelete, _ := resourceConfigs[ALLOW_DELETE_CONFIG].(bool)
+	return allowDelete && !IsFilterActive() && !NO_DELETE && !UPDATE_ONLY
+}
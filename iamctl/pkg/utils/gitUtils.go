@@ -0,0 +1,104 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+func IsGitRepository(dirPath string) bool {
+
+	// The output directory is created by the export if it does not exist, so the nearest existing parent directory
+	// is checked.
+	_, err := getGitRootDir(getNearestExistingDir(dirPath))
+	return err == nil
+}
+
+func GetCommittedFileContent(filePath string) ([]byte, error) {
+
+	absFilePath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error when resolving the file path: %s", err)
+	}
+	fileDir, err := filepath.EvalSymlinks(filepath.Dir(absFilePath))
+	if err != nil {
+		return nil, fmt.Errorf("error when resolving the file path: %s", err)
+	}
+	gitRootDir, err := getGitRootDir(fileDir)
+	if err != nil {
+		return nil, err
+	}
+	relativePath, err := filepath.Rel(gitRootDir, filepath.Join(fileDir, filepath.Base(absFilePath)))
+	if err != nil {
+		return nil, fmt.Errorf("error when resolving the file path relative to the git repository: %s", err)
+	}
+
+	// Git expects forward slashes in the object path regardless of the platform.
+	objectPath := "HEAD:" + filepath.ToSlash(relativePath)
+	content, err := exec.Command("git", "-C", gitRootDir, "show", objectPath).Output()
+	if err != nil {
+		return nil, fmt.Errorf("file is not committed in the git repository: %s", relativePath)
+	}
+	return content, nil
+}
+
+func IsContentChanged(filePath string, content []byte) bool {
+
+	committedContent, err := GetCommittedFileContent(filePath)
+	if err != nil {
+		return true
+	}
+	return sha256.Sum256(committedContent) != sha256.Sum256(content)
+}
+
+func getGitRootDir(dirPath string) (string, error) {
+
+	output, err := exec.Command("git", "-C", dirPath, "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %s", dirPath)
+	}
+	gitRootDir, err := filepath.EvalSymlinks(strings.TrimSpace(string(output)))
+	if err != nil {
+		return "", fmt.Errorf("error when resolving the git repository path: %s", err)
+	}
+	return gitRootDir, nil
+}
+
+func getNearestExistingDir(dirPath string) string {
+
+	absDirPath, err := filepath.Abs(dirPath)
+	if err != nil {
+		return dirPath
+	}
+	for {
+		if info, err := os.Stat(absDirPath); err == nil && info.IsDir() {
+			return absDirPath
+		}
+		parentDir := filepath.Dir(absDirPath)
+		if parentDir == absDirPath {
+			return absDirPath
+		}
+		absDirPath = parentDir
+	}
+}
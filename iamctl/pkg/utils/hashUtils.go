@@ -0,0 +1,111 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+func GetCanonicalHash(fileContent []byte, ignoredFields []string) (string, error) {
+
+	var fileData interface{}
+	err := yaml.Unmarshal(ReplaceTypeTags(fileContent), &fileData)
+	if err != nil {
+		return "", fmt.Errorf("error when parsing the content to YAML. %w", err)
+	}
+
+	// Marshalling sorts the map keys, which makes the hash independent of the key order in the file.
+	canonicalContent, err := yaml.Marshal(removeFields(fileData, ignoredFields))
+	if err != nil {
+		return "", fmt.Errorf("error when creating the canonical content. %w", err)
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(canonicalContent)), nil
+}
+
+func IsContentEqual(localContent []byte, deployedContent []byte, ignoredFields []string) bool {
+
+	// Null fields are compared as absent fields, since the files exported with the --omit-null flag do not have them.
+	localContent, err := OmitNullFields(localContent)
+	if err != nil {
+		return false
+	}
+	deployedContent, err = OmitNullFields(deployedContent)
+	if err != nil {
+		return false
+	}
+	localHash, err := GetCanonicalHash(localContent, ignoredFields)
+	if err != nil {
+		return false
+	}
+	deployedHash, err := GetCanonicalHash(deployedContent, ignoredFields)
+	if err != nil {
+		return false
+	}
+	return localHash == deployedHash
+}
+
+func removeFields(data interface{}, ignoredFields []string) interface{} {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			if Contains(ignoredFields, fmt.Sprintf("%v", key)) {
+				delete(v, key)
+			} else {
+				v[key] = removeFields(value, ignoredFields)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = removeFields(value, ignoredFields)
+		}
+	}
+	return data
+}
+
+func removeNullFields(data interface{}) interface{} {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			if value == nil {
+				delete(v, key)
+			} else {
+				v[key] = removeNullFields(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = removeNullFields(value)
+		}
+	}
+	return data
+}
+
+func GetIgnoredFields(resourceType string) []string {
+
+	switch resourceType {
+	case APPLICATIONS:
+		return applicationIgnoredFields
+	}
+	return []string{}
+}
@@ -0,0 +1,166 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Wraps an HTTP transport to add behaviour such as request signing or instrumentation to the API calls.
+type HttpMiddleware func(next http.RoundTripper) http.RoundTripper
+
+// Adapter to use a function as an HTTP transport.
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+const MAX_LOGGED_BODY_LENGTH = 4096
+
+var httpMiddlewares []HttpMiddleware
+
+var sensitiveBodyPatterns = []struct {
+	pattern     *regexp.Regexp
+	replacement string
+}{
+	// JSON fields. Ex: "clientSecret": "value"
+	{regexp.MustCompile(`("(?i:[^"]*(?:secret|password|token|assertion)[^"]*)"\s*:\s*)"[^"]*"`), `${1}"********"`},
+	// Form and query parameters. Ex: client_secret=value
+	{regexp.MustCompile(`((?i:[\w.-]*(?:secret|password|token|assertion)[\w.-]*)=)[^&\s]*`), `${1}********`},
+	// YAML fields. Ex: oauthConsumerSecret: value
+	{regexp.MustCompile(`(?m)^(\s*-?\s*(?i:[\w.-]*(?:secret|password|token|assertion)[\w.-]*)\s*:[ \t]*)\S.*$`), `${1}'********'`},
+}
+
+// Values such as the decrypted keywords, that should be redacted wherever they appear in the logs.
+var sensitiveValues []string
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+
+	return f(req)
+}
+
+// Add middlewares to the HTTP clients created after the call. The first middleware is the outermost.
+// The middlewares apply to both the token requests and the management API requests. Middlewares that log
+// the request or response bodies should log them with RedactBody.
+func UseHttpMiddleware(middlewares ...HttpMiddleware) {
+
+	httpMiddlewares = append(httpMiddlewares, middlewares...)
+}
+
+func wrapTransport(transport http.RoundTripper, clientMiddlewares ...HttpMiddleware) http.RoundTripper {
+
+	// The trace context is added before the custom middlewares, so that middlewares such as request signing
+	// can include it. The debug logs show the requests as they are sent.
+	var middlewares []HttpMiddleware
+	if IsTracingEnabled() {
+		middlewares = append(middlewares, SpanMiddleware, TraceparentMiddleware)
+	}
+	if IsMetricsEnabled() {
+		middlewares = append(middlewares, MetricsMiddleware)
+	}
+	middlewares = append(middlewares, httpMiddlewares...)
+	middlewares = append(middlewares, clientMiddlewares...)
+	middlewares = append(middlewares, AuditMiddleware, JUnitMiddleware, DebugLogMiddleware)
+
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		transport = middlewares[i](transport)
+	}
+	return transport
+}
+
+// Mask the values of the fields with secrets, so that the body can be logged.
+func RedactBody(body []byte) string {
+
+	redactedBody := redactSensitiveValues(string(body))
+	for _, sensitivePattern := range sensitiveBodyPatterns {
+		redactedBody = sensitivePattern.pattern.ReplaceAllString(redactedBody, sensitivePattern.replacement)
+	}
+	if len(redactedBody) > MAX_LOGGED_BODY_LENGTH {
+		redactedBody = redactedBody[:MAX_LOGGED_BODY_LENGTH] + "...(truncated)"
+	}
+	return redactedBody
+}
+
+// Register a value to be redacted from the logged requests and responses.
+func RegisterSensitiveValue(value string) {
+
+	if value == "" {
+		return
+	}
+	for _, sensitiveValue := range sensitiveValues {
+		if sensitiveValue == value {
+			return
+		}
+	}
+	sensitiveValues = append(sensitiveValues, value)
+}
+
+func redactSensitiveValues(text string) string {
+
+	for _, sensitiveValue := range sensitiveValues {
+		text = strings.ReplaceAll(text, sensitiveValue, "********")
+		if escapedValue := url.QueryEscape(sensitiveValue); escapedValue != sensitiveValue {
+			text = strings.ReplaceAll(text, escapedValue, "********")
+		}
+	}
+	return text
+}
+
+// Log the requests and the responses with the secrets redacted when the --debug flag is set.
+func DebugLogMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if !DEBUG {
+			return next.RoundTrip(req)
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			body, err := ioutil.ReadAll(req.Body)
+			req.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = ioutil.NopCloser(bytes.NewReader(body))
+			LogDebug("HTTP request:", req.Method, redactUrl(req), "\n"+RedactBody(body))
+		} else {
+			LogDebug("HTTP request:", req.Method, redactUrl(req))
+		}
+
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			LogDebug("HTTP request failed:", req.Method, redactUrl(req), err)
+			return resp, err
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+		LogDebug("HTTP response:", resp.Status, "\n"+RedactBody(body))
+		return resp, nil
+	})
+}
+
+func redactUrl(req *http.Request) string {
+
+	redactedUrl := *req.URL
+	redactedUrl.RawQuery = RedactBody([]byte(redactedUrl.RawQuery))
+	return redactedUrl.String()
+}
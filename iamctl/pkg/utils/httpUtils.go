@@ -0,0 +1,140 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Maximum duration in seconds of each API call, if the REQUEST_TIMEOUT server config is not set.
+const DEFAULT_REQUEST_TIMEOUT = 60
+
+var apiHttpClient *http.Client
+
+func GetHttpClient() *http.Client {
+
+	if apiHttpClient == nil {
+		apiHttpClient = &http.Client{
+			Transport: wrapTransport(&http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: true,
+				},
+			}),
+			Timeout: getRequestTimeout(SERVER_CONFIGS),
+		}
+	}
+	return apiHttpClient
+}
+
+func NewHttpClient(serverConfigs ServerConfigs) (*http.Client, error) {
+
+	return newHttpClient(serverConfigs, nil, nil)
+}
+
+// Create an HTTP client with the given transport and the middlewares of a client of the client package. The middlewares
+// are applied inside the global middlewares, so that the debug logs and the audit log show the requests as they are sent.
+func newHttpClient(serverConfigs ServerConfigs, transport http.RoundTripper, middlewares []HttpMiddleware) (*http.Client, error) {
+
+	if transport != nil {
+		if serverConfigs.ClientCertFile != "" || serverConfigs.ClientKeyFile != "" {
+			return nil, fmt.Errorf("the client certificate cannot be used with a custom transport. Configure the " +
+				"client certificate in the transport instead")
+		}
+		return &http.Client{
+			Transport: wrapTransport(transport, middlewares...),
+			Timeout:   getRequestTimeout(serverConfigs),
+		}, nil
+	}
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+
+	// Configure the client certificate for servers that require mutual TLS.
+	if serverConfigs.ClientCertFile != "" || serverConfigs.ClientKeyFile != "" {
+		if serverConfigs.ClientCertFile == "" || serverConfigs.ClientKeyFile == "" {
+			return nil, fmt.Errorf("both %s and %s should be defined to use a client certificate",
+				CLIENT_CERT_FILE_CONFIG, CLIENT_KEY_FILE_CONFIG)
+		}
+		certificate, err := loadClientCertificate(serverConfigs.ClientCertFile, serverConfigs.ClientKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return &http.Client{
+		Transport: wrapTransport(&http.Transport{
+			TLSClientConfig: tlsConfig,
+		}, middlewares...),
+		Timeout: getRequestTimeout(serverConfigs),
+	}, nil
+}
+
+// Get the maximum duration of each API call, including reading the response, so that a hung connection to the
+// server fails the call instead of blocking the run.
+func getRequestTimeout(serverConfigs ServerConfigs) time.Duration {
+
+	if serverConfigs.RequestTimeout > 0 {
+		return time.Duration(serverConfigs.RequestTimeout) * time.Second
+	}
+	return DEFAULT_REQUEST_TIMEOUT * time.Second
+}
+
+func loadClientCertificate(certFile string, keyFile string) (tls.Certificate, error) {
+
+	certPEM, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error when reading the client certificate file: %s", err)
+	}
+	keyPEM, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error when reading the client key file: %s", err)
+	}
+
+	// Decrypt the private key with the passphrase given in the environment variable, if the key is encrypted.
+	keyBlock, _ := pem.Decode(keyPEM)
+	if keyBlock == nil {
+		return tls.Certificate{}, fmt.Errorf("client key file is not in the PEM format")
+	}
+	if x509.IsEncryptedPEMBlock(keyBlock) {
+		passphrase := os.Getenv(IAMCTL_CLIENT_KEY_PASSPHRASE)
+		if passphrase == "" {
+			return tls.Certificate{}, fmt.Errorf("client key is encrypted. Set the passphrase in the %s environment variable",
+				IAMCTL_CLIENT_KEY_PASSPHRASE)
+		}
+		decryptedKey, err := x509.DecryptPEMBlock(keyBlock, []byte(passphrase))
+		if err != nil {
+			return tls.Certificate{}, fmt.Errorf("error when decrypting the client key: %s", err)
+		}
+		keyPEM = pem.EncodeToMemory(&pem.Block{Type: keyBlock.Type, Bytes: decryptedKey})
+	}
+
+	certificate, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("error when loading the client certificate: %s", err)
+	}
+	return certificate, nil
+}
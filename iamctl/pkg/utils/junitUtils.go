@@ -0,0 +1,271 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Path to the JUnit XML report of the run. Set by the --junit-report flag.
+var JUNIT_REPORT string
+
+// Reasons of the skipped resources in the JUnit report.
+const JUNIT_SKIPPED_EXCLUDED = "Excluded by the EXCLUDE list of the tool configs"
+const JUNIT_SKIPPED_FILTERED = "Not selected by the --include-only filter or the namespace"
+const JUNIT_SKIPPED_UNPROCESSED = "Not processed within the time budget"
+const JUNIT_SKIPPED_INTERRUPTED = "Not processed since the run was interrupted"
+const JUNIT_SKIPPED_UNCHANGED = "Unchanged since the last import"
+const JUNIT_SKIPPED_MISSING = "Does not exist in the target environment"
+const JUNIT_SKIPPED_TOOL_APP = "Application of the tool, which is excluded from deletion"
+const JUNIT_SKIPPED_CONFLICT = "Edited on the server since the last export, and kept by the conflict strategy"
+const JUNIT_SKIPPED_SYSTEM_APP = "System application, which is excluded unless the --include-system-apps flag is used"
+
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Skipped  int               `xml:"skipped,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Details string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+var junitReport *junitTestSuites
+var junitResourceStartTime time.Time
+
+// Status and error message of the last failed API call of a resource, to describe the failed resources.
+type junitFailureRecord struct {
+	status  string
+	message string
+}
+
+type junitFailureContextKey struct{}
+
+// Failure of the resource in progress. The calls sent by the fetch pool workers record their failures in the record
+// of their own context instead, which is moved to the resource in progress when the caller takes the fetched result.
+var junitCurrentFailure junitFailureRecord
+var junitFailureMutex sync.Mutex
+
+// Start the JUnit report of the run. An empty report is written right away, so that a report is available even if
+// the run stops before processing any resource.
+func StartJUnitReport(operation string) {
+
+	junitReport = nil
+	if JUNIT_REPORT == "" {
+		return
+	}
+	junitReport = &junitTestSuites{Name: AppName + " " + operation}
+	writeJUnitReport()
+}
+
+// Add a resource that is skipped without being reported to the summary, such as a resource in the EXCLUDE list, to
+// the JUnit report.
+func ReportSkippedResource(resourceType string, resourceName string, reason string) {
+
+	recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, reason)
+}
+
+func startJUnitTestCase() {
+
+	junitResourceStartTime = time.Now()
+	setJUnitFailure(nil, junitFailureRecord{})
+}
+
+// Get a context with a separate failure record, for the calls sent in parallel to the calls of the resource in
+// progress.
+func withJUnitFailureRecord(ctx context.Context) (context.Context, *junitFailureRecord) {
+
+	record := &junitFailureRecord{}
+	return context.WithValue(ctx, junitFailureContextKey{}, record), record
+}
+
+// Set the failure of the record of the given context, or of the resource in progress if the context has no record.
+func setJUnitFailure(ctx context.Context, failure junitFailureRecord) {
+
+	junitFailureMutex.Lock()
+	defer junitFailureMutex.Unlock()
+	if ctx != nil {
+		if record, ok := ctx.Value(junitFailureContextKey{}).(*junitFailureRecord); ok {
+			*record = failure
+			return
+		}
+	}
+	junitCurrentFailure = failure
+}
+
+// Move the failure recorded for a fetched resource to the resource in progress.
+func moveJUnitFailure(record *junitFailureRecord) {
+
+	junitFailureMutex.Lock()
+	defer junitFailureMutex.Unlock()
+	if record != nil && record.message != "" {
+		junitCurrentFailure = *record
+	}
+}
+
+// Add a resource to the JUnit report, with the resource type as the test suite. The report is written after each
+// resource, so that the results of the processed resources are available even if the run is aborted.
+func recordJUnitTestCase(resourceType string, resourceName string, action string, result string, skipReason string) {
+
+	if junitReport == nil {
+		return
+	}
+	var suite *junitTestSuite
+	for _, existingSuite := range junitReport.Suites {
+		if existingSuite.Name == resourceType {
+			suite = existingSuite
+		}
+	}
+	if suite == nil {
+		suite = &junitTestSuite{Name: resourceType}
+		junitReport.Suites = append(junitReport.Suites, suite)
+	}
+
+	testCase := junitTestCase{Name: resourceName, ClassName: resourceType, Time: "0.000"}
+	switch result {
+	case PROGRESS_RESULT_SKIPPED:
+		// A resource can be skipped for more than one reason, such as a system application in the EXCLUDE list.
+		for _, existingCase := range suite.TestCases {
+			if existingCase.Name == resourceName && existingCase.Skipped != nil {
+				return
+			}
+		}
+		testCase.Skipped = &junitSkipped{Message: skipReason}
+		suite.Skipped++
+		junitReport.Skipped++
+	case PROGRESS_RESULT_FAILED:
+		testCase.Time = formatJUnitDuration(time.Since(junitResourceStartTime))
+		testCase.Failure = getJUnitFailure(action)
+		suite.Failures++
+		junitReport.Failures++
+	default:
+		testCase.Time = formatJUnitDuration(time.Since(junitResourceStartTime))
+	}
+	suite.TestCases = append(suite.TestCases, testCase)
+	suite.Tests++
+	junitReport.Tests++
+	writeJUnitReport()
+}
+
+func getJUnitFailure(action string) *junitFailure {
+
+	junitFailureMutex.Lock()
+	failure := junitCurrentFailure
+	junitFailureMutex.Unlock()
+	if failure.message == "" {
+		return &junitFailure{Type: action,
+			Message: "The operation failed without an error response from the server. Check the logs for the error."}
+	}
+	message := strings.TrimSpace(failure.status + " " + failure.message)
+	return &junitFailure{Type: action, Message: message, Details: message}
+}
+
+func formatJUnitDuration(duration time.Duration) string {
+
+	return fmt.Sprintf("%.3f", duration.Seconds())
+}
+
+// Write the report to a temporary file and rename it, so that the report is never left partially written.
+func writeJUnitReport() {
+
+	content, err := xml.MarshalIndent(junitReport, "", "  ")
+	if err != nil {
+		log.Println("Error when writing the JUnit report.", err)
+		return
+	}
+	content = append([]byte(xml.Header), append(content, '\n')...)
+	if err := writeFileAtomically(JUNIT_REPORT, content, 0644); err != nil {
+		log.Println("Error when writing the JUnit report.", err)
+	}
+}
+
+// Keep the status and the error message of the failed API calls when the JUnit report is enabled, so that the
+// failed resources of the report have the error returned by the server.
+func JUnitMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if junitReport == nil {
+			return resp, err
+		}
+		if err != nil {
+			setJUnitFailure(req.Context(), junitFailureRecord{message: redactSensitiveValues(err.Error())})
+			return resp, err
+		}
+		if resp.StatusCode >= http.StatusBadRequest {
+			body, readErr := ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			if readErr != nil {
+				return nil, readErr
+			}
+			resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+			setJUnitFailure(req.Context(), junitFailureRecord{status: resp.Status, message: getServerErrorMessage(body)})
+		}
+		return resp, nil
+	})
+}
+
+func getServerErrorMessage(body []byte) string {
+
+	var errorResponse ErrorResponse
+	if err := json.Unmarshal(body, &errorResponse); err == nil {
+		if errorResponse.Description != "" {
+			return redactSensitiveValues(errorResponse.Description)
+		}
+		if errorResponse.Message != "" {
+			return redactSensitiveValues(errorResponse.Message)
+		}
+	}
+	return RedactBody(body)
+}
@@ -0,0 +1,282 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+type KeywordEnvironment struct {
+	Name string
+	// Keyword values keyed by the keyword path. Resource specific keywords are prefixed with the resource type and name.
+	Keywords map[string]string
+	// Hosts that belong to the environment.
+	Hosts []string
+}
+
+var keywordConfigSections = []string{KEYWORD_MAPPINGS_CONFIG, APPLICATIONS_CONFIG, IDP_CONFIG,
+	CLAIM_CONFIG, USERSTORES_CONFIG, EMAIL_TEMPLATES_CONFIG, USERS_CONFIG, XACML_POLICIES_CONFIG, ROLES_CONFIG,
+	API_RESOURCES_CONFIG, GOVERNANCE_CONNECTORS_CONFIG,
+	BRANDING_CONFIG, OIDC_SCOPES_CONFIG, NOTIFICATION_SENDERS_CONFIG, WORKFLOWS_CONFIG, CORS_CONFIG, ORGANIZATIONS_CONFIG, TENANTS_CONFIG,
+	ORGANIZATION_IDS_CONFIG}
+
+func LoadKeywordEnvironments(configPaths []string) ([]KeywordEnvironment, error) {
+
+	var environments []KeywordEnvironment
+	for _, configPath := range configPaths {
+		keywordConfigFile, err := ioutil.ReadFile(filepath.Join(configPath, KEYWORD_CONFIG_FILE))
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the keyword config file in %s: %s", configPath, err)
+		}
+		keywordConfigFile = ReplacePlaceholders(keywordConfigFile)
+
+		// Server configs are optional and only used to resolve the hosts of the environment.
+		serverConfigFile, err := ioutil.ReadFile(filepath.Join(configPath, SERVER_CONFIG_FILE))
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error when reading the server config file in %s: %s", configPath, err)
+		}
+		serverConfigFile = ReplacePlaceholders(serverConfigFile)
+
+		environmentNames, err := getEnvironmentSections(keywordConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("keyword configs in %s are not in the correct format: %s", configPath, err)
+		}
+		// Config folders without environment sections hold the configs of a single environment named after the folder.
+		if len(environmentNames) == 0 {
+			environment, err := newKeywordEnvironment(filepath.Base(configPath), keywordConfigFile, serverConfigFile)
+			if err != nil {
+				return nil, fmt.Errorf("error in the configs of %s: %s", configPath, err)
+			}
+			environments = append(environments, environment)
+			continue
+		}
+
+		// Values inherited from the default section are shared across environments by design and are not checked.
+		defaultEnvironment, err := getDefaultKeywordEnvironment(keywordConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("error in the default configs of %s: %s", configPath, err)
+		}
+		for _, environmentName := range environmentNames {
+			resolvedKeywordConfigs, err := ResolveEnvironmentConfigs(keywordConfigFile, environmentName)
+			if err != nil {
+				return nil, err
+			}
+			resolvedServerConfigs := serverConfigFile
+			if len(serverConfigFile) > 0 {
+				if resolvedServerConfigs, err = ResolveEnvironmentConfigs(serverConfigFile, environmentName); err != nil {
+					resolvedServerConfigs = nil
+				}
+			}
+			environment, err := newKeywordEnvironment(environmentName, resolvedKeywordConfigs, resolvedServerConfigs)
+			if err != nil {
+				return nil, fmt.Errorf("error in the configs of environment %s: %s", environmentName, err)
+			}
+			for keywordPath, value := range defaultEnvironment.Keywords {
+				if environment.Keywords[keywordPath] == value {
+					delete(environment.Keywords, keywordPath)
+				}
+			}
+			environments = append(environments, environment)
+		}
+	}
+	return environments, nil
+}
+
+func LintKeywordEnvironments(environments []KeywordEnvironment, keys []string, allowedShared []string) []string {
+
+	var issues []string
+
+	// Group the environments by keyword path and value to find the values shared across environments.
+	sharedValues := make(map[string]map[string][]string)
+	for _, environment := range environments {
+		for keywordPath, value := range environment.Keywords {
+			if !isLintedKeyword(keywordPath, value, keys) || isSharingAllowed(keywordPath, value, allowedShared) {
+				continue
+			}
+			if sharedValues[keywordPath] == nil {
+				sharedValues[keywordPath] = make(map[string][]string)
+			}
+			sharedValues[keywordPath][value] = append(sharedValues[keywordPath][value], environment.Name)
+
+			// Check whether the value points to a host of another environment.
+			host := getHostName(value)
+			if host == "" || containsHost(environment.Hosts, host) {
+				continue
+			}
+			for _, otherEnvironment := range environments {
+				if otherEnvironment.Name != environment.Name && containsHost(otherEnvironment.Hosts, host) {
+					issues = append(issues, fmt.Sprintf("Keyword %s in environment %s points to host %s of environment %s.",
+						keywordPath, environment.Name, host, otherEnvironment.Name))
+				}
+			}
+		}
+	}
+
+	for keywordPath, values := range sharedValues {
+		for value, environmentNames := range values {
+			if len(environmentNames) > 1 {
+				issues = append(issues, fmt.Sprintf("Keyword %s has the same value %s in environments: %s.",
+					keywordPath, value, strings.Join(environmentNames, ", ")))
+			}
+		}
+	}
+	sort.Strings(issues)
+	return issues
+}
+
+func getEnvironmentSections(keywordConfigFile []byte) ([]string, error) {
+
+	var configs map[string]interface{}
+	if err := json.Unmarshal(keywordConfigFile, &configs); err != nil {
+		return nil, err
+	}
+	for _, section := range keywordConfigSections {
+		if _, ok := configs[section]; ok {
+			return nil, nil
+		}
+	}
+
+	var environmentNames []string
+	for key, value := range configs {
+		if _, ok := value.(map[string]interface{}); ok && key != DEFAULT_ENV_SECTION {
+			environmentNames = append(environmentNames, key)
+		}
+	}
+	sort.Strings(environmentNames)
+	return environmentNames, nil
+}
+
+func getDefaultKeywordEnvironment(keywordConfigFile []byte) (KeywordEnvironment, error) {
+
+	var configs map[string]interface{}
+	if err := json.Unmarshal(keywordConfigFile, &configs); err != nil {
+		return KeywordEnvironment{}, err
+	}
+	defaultConfigs, err := json.Marshal(configs[DEFAULT_ENV_SECTION])
+	if err != nil || configs[DEFAULT_ENV_SECTION] == nil {
+		return KeywordEnvironment{Keywords: map[string]string{}}, err
+	}
+	return newKeywordEnvironment(DEFAULT_ENV_SECTION, defaultConfigs, nil)
+}
+
+func newKeywordEnvironment(name string, keywordConfigFile []byte, serverConfigFile []byte) (KeywordEnvironment, error) {
+
+	environment := KeywordEnvironment{
+		Name:     name,
+		Keywords: make(map[string]string),
+	}
+
+	var keywordConfigs map[string]interface{}
+	if err := json.Unmarshal(keywordConfigFile, &keywordConfigs); err != nil {
+		return environment, fmt.Errorf("keyword configs are not in the correct format: %s", err)
+	}
+	addKeywordValues(environment.Keywords, "", keywordConfigs[KEYWORD_MAPPINGS_CONFIG])
+	addKeywordValues(environment.Keywords, ORGANIZATION_IDS_CONFIG+".", keywordConfigs[ORGANIZATION_IDS_CONFIG])
+	for _, resourceType := range keywordConfigSections[1:] {
+		resourceConfigs, _ := keywordConfigs[resourceType].(map[string]interface{})
+		for resourceName, resourceConfig := range resourceConfigs {
+			if resourceConfig, ok := resourceConfig.(map[string]interface{}); ok {
+				prefix := resourceType + "." + resourceName + "."
+				addKeywordValues(environment.Keywords, prefix, resourceConfig[KEYWORD_MAPPINGS_CONFIG])
+			}
+		}
+	}
+
+	if len(serverConfigFile) == 0 {
+		return environment, nil
+	}
+	var serverConfigs map[string]interface{}
+	if err := json.Unmarshal(serverConfigFile, &serverConfigs); err != nil {
+		return environment, fmt.Errorf("server configs are not in the correct format: %s", err)
+	}
+	if serverUrl, ok := serverConfigs[SERVER_URL_CONFIG].(string); ok {
+		if host := getHostName(serverUrl); host != "" {
+			environment.Hosts = append(environment.Hosts, host)
+		}
+	}
+	hosts, _ := serverConfigs[HOSTS_CONFIG].([]interface{})
+	for _, host := range hosts {
+		if host, ok := host.(string); ok {
+			environment.Hosts = append(environment.Hosts, host)
+		}
+	}
+	return environment, nil
+}
+
+func addKeywordValues(keywords map[string]string, prefix string, keywordMapping interface{}) {
+
+	mapping, _ := keywordMapping.(map[string]interface{})
+	for keyword, value := range mapping {
+		if value, ok := value.(string); ok {
+			keywords[prefix+keyword] = value
+		}
+	}
+}
+
+func isLintedKeyword(keywordPath string, value string, keys []string) bool {
+
+	// Check only the URL like values if the keywords to be checked are not specified.
+	if len(keys) == 0 {
+		return getHostName(value) != ""
+	}
+	keyword := keywordPath[strings.LastIndex(keywordPath, ".")+1:]
+	for _, key := range keys {
+		if matched, err := filepath.Match(key, keyword); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+func isSharingAllowed(keywordPath string, value string, allowedShared []string) bool {
+
+	keyword := keywordPath[strings.LastIndex(keywordPath, ".")+1:]
+	for _, allowed := range allowedShared {
+		if allowed == value || allowed == keyword || allowed == keywordPath {
+			return true
+		}
+	}
+	return false
+}
+
+func getHostName(value string) string {
+
+	parsedUrl, err := url.Parse(value)
+	if err != nil || parsedUrl.Scheme == "" {
+		return ""
+	}
+	return strings.ToLower(parsedUrl.Hostname())
+}
+
+func containsHost(hosts []string, host string) bool {
+
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
@@ -21,17 +21,20 @@ package utils
 import (
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"log"
 	"os"
 	"reflect"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v2"
 )
 
+// Matches a mapping key with a type tag. Ex: inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO
+var typeTagRegex = regexp.MustCompile(`^(\s*(?:- )?)([^\s#][^:]*): !!org\.wso2\.(\S+)\s*$`)
+
 func ReplaceKeywords(fileContent string, keywordMapping map[string]interface{}) string {
 
 	// Loop over the keyword mapping and replace each keyword in the file.
@@ -45,6 +48,60 @@ func ReplaceKeywords(fileContent string, keywordMapping map[string]interface{})
 	return fileContent
 }
 
+func AddKeywordPlaceholders(fileContent string, keywordMapping map[string]interface{}) (string, error) {
+
+	// Only the string values equal to a keyword value are replaced, so that a short keyword value, such as the name
+	// of an environment, does not replace parts of the keys and the other values. If more than one keyword has the
+	// same value, the first keyword in the alphabetical order is used.
+	var keywords []string
+	for keyword := range keywordMapping {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	placeholders := make(map[string]string)
+	for _, keyword := range keywords {
+		value, ok := keywordMapping[keyword].(string)
+		if _, exists := placeholders[value]; ok && value != "" && !exists {
+			placeholders[value] = "{{" + keyword + "}}"
+		}
+	}
+	if len(placeholders) == 0 {
+		return fileContent, nil
+	}
+
+	var data interface{}
+	if err := yaml.Unmarshal(ReplaceTypeTags([]byte(fileContent)), &data); err != nil {
+		return "", fmt.Errorf("error when parsing the content to YAML. %w", err)
+	}
+	modifiedContent, err := yaml.Marshal(addKeywordPlaceholders(data, placeholders))
+	if err != nil {
+		return "", fmt.Errorf("error when creating the content with keyword placeholders. %w", err)
+	}
+	return string(AddTypeTags(modifiedContent)), nil
+}
+
+func addKeywordPlaceholders(data interface{}, placeholders map[string]string) interface{} {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			// The type tag placeholders are restored to type tags, so they are never replaced.
+			if key != "1typeTag" {
+				v[key] = addKeywordPlaceholders(value, placeholders)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = addKeywordPlaceholders(value, placeholders)
+		}
+	case string:
+		if placeholder, ok := placeholders[v]; ok {
+			return placeholder
+		}
+	}
+	return data
+}
+
 func ProcessExportedContent(exportedFileName string, exportedFileContent []byte, keywordMapping map[string]interface{}, resourceType string) ([]byte, error) {
 
 	// To preserve type tags in the exported file, replace the type tags with a placeholder.
@@ -60,7 +117,7 @@ func ProcessExportedContent(exportedFileName string, exportedFileContent []byte,
 
 	// Replace ESVs in the exported file according to the keyword placeholders added in the local file.
 	var modifiedExportedYaml interface{}
-	localFileData, err := ioutil.ReadFile(exportedFileName)
+	localFileData, err := ReadResourceFile(exportedFileName)
 	if err != nil {
 		log.Printf("Local file not found at %s. Creating new file.", exportedFileName)
 		modifiedExportedYaml = exportedYaml
@@ -151,6 +208,18 @@ func GetArrayIdentifiers(resourceType string) map[string]string {
 		return userStoreArrayIdentifiers
 	case CLAIMS:
 		return claimArrayIdentifiers
+	case ROLES:
+		return roleArrayIdentifiers
+	case API_RESOURCES:
+		return apiResourceArrayIdentifiers
+	case GOVERNANCE_CONNECTORS:
+		return governanceConnectorArrayIdentifiers
+	case NOTIFICATION_SENDERS:
+		return notificationSenderArrayIdentifiers
+	case WORKFLOWS:
+		return workflowArrayIdentifiers
+	case ORGANIZATIONS:
+		return organizationArrayIdentifiers
 	}
 	return make(map[string]string)
 }
@@ -202,7 +271,8 @@ func ModifyFieldsWithKeywords(exportedFileData interface{}, localFileData interf
 				ReplaceValue(exportedFileData, location, localValue)
 				log.Printf("Info: Keyword added at %s field\n", location)
 			} else {
-				log.Printf("Warning: Keywords at %s field in the local file will be replaced by exported content.", location)
+				LogWarning(WARNING_KEYWORD_OVERRIDE, fmt.Sprintf(
+					"Keywords at %s field in the local file will be replaced by exported content.", location))
 				log.Println("Info: Local Value with Keyword Replaced: ", localReplacedValue)
 				log.Println("Info: Exported Value: ", exportedValue)
 			}
@@ -337,11 +407,38 @@ func GetPathKeys(pathString string) []string {
 
 func ReplaceTypeTags(data []byte) []byte {
 
+	// Move the type tag of a mapping into a placeholder key of the mapping. This applies to any field, so that the
+	// typed fields added in newer server versions are preserved as well.
+	lines := strings.Split(string(data), "\n")
+	var modifiedLines []string
+	for i, line := range lines {
+		match := typeTagRegex.FindStringSubmatch(line)
+		if match == nil {
+			modifiedLines = append(modifiedLines, line)
+			continue
+		}
+		childIndent := getNextLineIndent(lines, i)
+		if childIndent <= len(match[1]) {
+			modifiedLines = append(modifiedLines, line)
+			continue
+		}
+		modifiedLines = append(modifiedLines, match[1]+match[2]+":",
+			strings.Repeat(" ", childIndent)+"1typeTag: "+match[3])
+	}
+	data = []byte(strings.Join(modifiedLines, "\n"))
+
 	re := regexp.MustCompile(`!!org\.wso2\.`)
-	data = re.ReplaceAll(data, []byte("1typeTag: "))
+	return re.ReplaceAll(data, []byte("1typeTag: "))
+}
+
+func getNextLineIndent(lines []string, index int) int {
 
-	re = regexp.MustCompile(`inboundConfigurationProtocol: 1typeTag: `)
-	return re.ReplaceAll(data, []byte("inboundConfigurationProtocol:\n      1typeTag: "))
+	for _, line := range lines[index+1:] {
+		if strings.TrimSpace(line) != "" {
+			return len(line) - len(strings.TrimLeft(line, " "))
+		}
+	}
+	return 0
 }
 
 func AddTypeTags(data []byte) []byte {
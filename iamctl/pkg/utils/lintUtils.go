@@ -0,0 +1,375 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Lint rules
+const LINT_OAUTH_REDIRECT_URI = "oauth-redirect-uri"
+const LINT_ALLOWED_ORIGINS = "allowed-origins"
+const LINT_IDP_CERTIFICATE = "idp-certificate"
+const LINT_DUPLICATE_APP_NAME = "duplicate-app-name"
+const LINT_UNDEFINED_KEYWORD = "undefined-keyword"
+const LINT_CERTIFICATE_PLACEHOLDER = "certificate-placeholder"
+
+var LINT_RULES = []string{LINT_OAUTH_REDIRECT_URI, LINT_ALLOWED_ORIGINS, LINT_IDP_CERTIFICATE, LINT_DUPLICATE_APP_NAME,
+	LINT_UNDEFINED_KEYWORD, LINT_CERTIFICATE_PLACEHOLDER}
+
+const SAML_AUTHENTICATOR_NAME = "SAMLSSOAuthenticator"
+const JWKS_URI_PROPERTY = "jwksUri"
+
+// Grant types that redirect the user agent to the callback URL of the application.
+var redirectGrantTypes = []string{"authorization_code", "implicit"}
+
+var urlRegex = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^/|()\s?#]+`)
+
+type LintViolation struct {
+	Rule     string
+	FilePath string
+	Message  string
+}
+
+type lintApplication struct {
+	ApplicationName             string `yaml:"applicationName"`
+	InboundAuthenticationConfig struct {
+		InboundAuthenticationRequestConfigs []struct {
+			InboundAuthType              string `yaml:"inboundAuthType"`
+			InboundConfigurationProtocol struct {
+				CallbackUrl    string   `yaml:"callbackUrl"`
+				GrantTypes     string   `yaml:"grantTypes"`
+				AllowedOrigins []string `yaml:"allowedOrigins"`
+			} `yaml:"inboundConfigurationProtocol"`
+		} `yaml:"inboundAuthenticationRequestConfigs"`
+	} `yaml:"inboundAuthenticationConfig"`
+}
+
+type lintIdentityProvider struct {
+	Certificate          string `yaml:"certificate"`
+	CertificateInfoArray []struct {
+		CertValue string `yaml:"certValue"`
+	} `yaml:"certificateInfoArray"`
+	IdpProperties []struct {
+		Name  string `yaml:"name"`
+		Value string `yaml:"value"`
+	} `yaml:"idpProperties"`
+	FederatedAuthenticatorConfigs []struct {
+		Name    string `yaml:"name"`
+		Enabled bool   `yaml:"enabled"`
+	} `yaml:"federatedAuthenticatorConfigs"`
+}
+
+func (violation LintViolation) String() string {
+
+	return fmt.Sprintf("%s: [%s] %s", violation.FilePath, violation.Rule, violation.Message)
+}
+
+func ValidateLintRules(rules []string) error {
+
+	for _, rule := range rules {
+		if !Contains(LINT_RULES, rule) {
+			return fmt.Errorf("unknown lint rule: %s. Supported rules: %s", rule, strings.Join(LINT_RULES, ", "))
+		}
+	}
+	return nil
+}
+
+func LintLocalResources(inputDirPath string, disabledRules []string) ([]LintViolation, error) {
+
+	var violations []LintViolation
+	isEnabled := func(rule string) bool {
+		return !Contains(disabledRules, rule)
+	}
+	resourceFiles, err := getLocalResourceFiles(inputDirPath)
+	if err != nil {
+		return nil, err
+	}
+
+	appFiles := make(map[string][]string)
+	for _, resourceFile := range resourceFiles {
+		fileContent, err := ReadResourceFile(resourceFile.path)
+		if err != nil {
+			return nil, fmt.Errorf("error when reading the file: %s. %s", resourceFile.path, err)
+		}
+		relativePath, err := filepath.Rel(inputDirPath, resourceFile.path)
+		if err != nil {
+			relativePath = resourceFile.path
+		}
+		keywordMapping := getResourceKeywordMapping(resourceFile.resourceType, resourceFile.resourceName)
+
+		if isEnabled(LINT_UNDEFINED_KEYWORD) {
+			for _, keyword := range getUndefinedKeywords(string(fileContent), keywordMapping) {
+				violations = append(violations, LintViolation{LINT_UNDEFINED_KEYWORD, relativePath,
+					fmt.Sprintf("Keyword %s has no mapping in the keyword configs", keyword)})
+			}
+		}
+		if IsReferencedFile(resourceFile.path) {
+			continue
+		}
+		if isEnabled(LINT_CERTIFICATE_PLACEHOLDER) {
+			for _, field := range FindCertificatePlaceholders(fileContent) {
+				violations = append(violations, LintViolation{LINT_CERTIFICATE_PLACEHOLDER, relativePath,
+					fmt.Sprintf("Certificate of %s is a placeholder of the --exclude-certs flag and cannot be imported", field)})
+			}
+		}
+
+		// Check the semantic rules on the content that will be imported.
+		fileContent = []byte(ReplaceKeywords(string(fileContent), keywordMapping))
+		switch resourceFile.resourceType {
+		case APPLICATIONS:
+			var app lintApplication
+			if err := yaml.Unmarshal(fileContent, &app); err != nil {
+				return nil, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			if app.ApplicationName != "" {
+				appFiles[app.ApplicationName] = append(appFiles[app.ApplicationName], relativePath)
+			}
+			violations = append(violations, lintApplicationConfigs(app, relativePath, isEnabled)...)
+		case IDENTITY_PROVIDERS:
+			var idp lintIdentityProvider
+			if err := yaml.Unmarshal(fileContent, &idp); err != nil {
+				return nil, fmt.Errorf("invalid file content at: %s. %s", resourceFile.path, err)
+			}
+			if isEnabled(LINT_IDP_CERTIFICATE) {
+				violations = append(violations, lintIdpCertificate(idp, relativePath)...)
+			}
+		}
+	}
+
+	if isEnabled(LINT_DUPLICATE_APP_NAME) {
+		for appName, filePaths := range appFiles {
+			if len(filePaths) < 2 {
+				continue
+			}
+			for _, filePath := range filePaths {
+				violations = append(violations, LintViolation{LINT_DUPLICATE_APP_NAME, filePath,
+					fmt.Sprintf("Application name %s is used in %d files", appName, len(filePaths))})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].String() < violations[j].String()
+	})
+	return violations, nil
+}
+
+type localResourceFile struct {
+	resourceType string
+	resourceName string
+	path         string
+}
+
+func GetLocalResourceFilePaths(inputDirPath string) ([]string, error) {
+
+	resourceFiles, err := getLocalResourceFiles(inputDirPath)
+	if err != nil {
+		return nil, err
+	}
+	var filePaths []string
+	for _, resourceFile := range resourceFiles {
+		filePaths = append(filePaths, resourceFile.path)
+	}
+	return filePaths, nil
+}
+
+func getLocalResourceFiles(inputDirPath string) ([]localResourceFile, error) {
+
+	var resourceFiles []localResourceFile
+	for _, resourceType := range []string{APPLICATIONS, IDENTITY_PROVIDERS, CLAIMS, USERSTORES, EMAIL_TEMPLATES, USERS, XACML_POLICIES, ROLES, API_RESOURCES, GOVERNANCE_CONNECTORS, BRANDING, OIDC_SCOPES, NOTIFICATION_SENDERS, WORKFLOWS, CORS, ORGANIZATIONS} {
+		resourceTypeDir := filepath.Join(inputDirPath, resourceType)
+		files, err := ioutil.ReadDir(resourceTypeDir)
+		if os.IsNotExist(err) {
+			continue
+		} else if err != nil {
+			return nil, fmt.Errorf("error when reading the directory: %s. %s", resourceTypeDir, err)
+		}
+		for _, file := range files {
+			filePath := filepath.Join(resourceTypeDir, file.Name())
+			if IsBaselineFile(file.Name()) {
+				continue
+			}
+			if !file.IsDir() {
+				resourceName := ResolveResourceName(filePath)
+				resourceFiles = append(resourceFiles, localResourceFile{resourceType, resourceName, filePath})
+				continue
+			}
+			// The folders of the organizations keep the resources exported from the sub organizations.
+			if resourceType == ORGANIZATIONS {
+				continue
+			}
+			// Email templates and branding are grouped in a folder per template type or branded resource.
+			// Notification senders are grouped in a folder per channel, and are named by the file.
+			err := filepath.Walk(filePath, func(nestedFilePath string, nestedFile os.FileInfo, err error) error {
+				if err == nil && !nestedFile.IsDir() {
+					resourceName := file.Name()
+					if resourceType == NOTIFICATION_SENDERS {
+						resourceName = GetFileInfo(nestedFile.Name()).ResourceName
+					}
+					resourceFiles = append(resourceFiles, localResourceFile{resourceType, resourceName, nestedFilePath})
+				}
+				return err
+			})
+			if err != nil {
+				return nil, fmt.Errorf("error when reading the directory: %s. %s", filePath, err)
+			}
+		}
+	}
+	return resourceFiles, nil
+}
+
+func lintApplicationConfigs(app lintApplication, filePath string, isEnabled func(string) bool) []LintViolation {
+
+	var violations []LintViolation
+	for _, requestConfig := range app.InboundAuthenticationConfig.InboundAuthenticationRequestConfigs {
+		if strings.ToLower(requestConfig.InboundAuthType) != OAUTH2 {
+			continue
+		}
+		protocol := requestConfig.InboundConfigurationProtocol
+		callbackUrl := strings.TrimSpace(protocol.CallbackUrl)
+
+		if isEnabled(LINT_OAUTH_REDIRECT_URI) && callbackUrl == "" {
+			for _, grantType := range strings.Fields(protocol.GrantTypes) {
+				if Contains(redirectGrantTypes, grantType) {
+					violations = append(violations, LintViolation{LINT_OAUTH_REDIRECT_URI, filePath,
+						fmt.Sprintf("OAuth application with the %s grant type has no redirect URI", grantType)})
+					break
+				}
+			}
+		}
+
+		callbackHosts := getUrlHosts(callbackUrl)
+		if !isEnabled(LINT_ALLOWED_ORIGINS) || len(callbackHosts) == 0 {
+			continue
+		}
+		for _, origin := range protocol.AllowedOrigins {
+			originUrl, err := url.Parse(strings.TrimSpace(origin))
+			if err != nil || originUrl.Hostname() == "" {
+				continue
+			}
+			if !Contains(callbackHosts, originUrl.Hostname()) {
+				violations = append(violations, LintViolation{LINT_ALLOWED_ORIGINS, filePath,
+					fmt.Sprintf("Allowed origin %s does not match the domain of any callback URL", origin)})
+			}
+		}
+	}
+	return violations
+}
+
+func lintIdpCertificate(idp lintIdentityProvider, filePath string) []LintViolation {
+
+	var violations []LintViolation
+	hasCertificate := strings.TrimSpace(idp.Certificate) != ""
+	for _, certificateInfo := range idp.CertificateInfoArray {
+		if strings.TrimSpace(certificateInfo.CertValue) == "" {
+			violations = append(violations, LintViolation{LINT_IDP_CERTIFICATE, filePath,
+				"Identity provider has a certificate entry without certificate content"})
+		} else {
+			hasCertificate = true
+		}
+	}
+	for _, property := range idp.IdpProperties {
+		if property.Name == JWKS_URI_PROPERTY && strings.TrimSpace(property.Value) != "" {
+			hasCertificate = true
+		}
+	}
+
+	// SAML responses cannot be validated without the certificate of the identity provider.
+	for _, authenticator := range idp.FederatedAuthenticatorConfigs {
+		if authenticator.Name == SAML_AUTHENTICATOR_NAME && authenticator.Enabled && !hasCertificate {
+			violations = append(violations, LintViolation{LINT_IDP_CERTIFICATE, filePath,
+				"Identity provider with SAML federation enabled has no certificate or JWKS URI"})
+		}
+	}
+	return violations
+}
+
+func getUndefinedKeywords(fileContent string, keywordMapping map[string]interface{}) []string {
+
+	var undefinedKeywords []string
+	for _, placeholder := range keywordPlaceholderRegex.FindAllString(fileContent, -1) {
+		keyword := strings.TrimSuffix(strings.TrimPrefix(placeholder, "{{"), "}}")
+		if _, ok := keywordMapping[keyword]; !ok && !Contains(undefinedKeywords, keyword) {
+			undefinedKeywords = append(undefinedKeywords, keyword)
+		}
+	}
+	return undefinedKeywords
+}
+
+func getUrlHosts(urls string) []string {
+
+	// Callback URLs can be given as a regex with multiple URLs. Ex: regexp=(https://a.com/cb|https://b.com/cb)
+	var hosts []string
+	for _, match := range urlRegex.FindAllString(urls, -1) {
+		if parsedUrl, err := url.Parse(match); err == nil && parsedUrl.Hostname() != "" {
+			hosts = append(hosts, parsedUrl.Hostname())
+		}
+	}
+	return hosts
+}
+
+func getResourceKeywordMapping(resourceType string, resourceName string) map[string]interface{} {
+
+	var resourceConfigs map[string]interface{}
+	switch resourceType {
+	case APPLICATIONS:
+		resourceConfigs = KEYWORD_CONFIGS.ApplicationConfigs
+	case IDENTITY_PROVIDERS:
+		resourceConfigs = KEYWORD_CONFIGS.IdpConfigs
+	case CLAIMS:
+		resourceConfigs = KEYWORD_CONFIGS.ClaimConfigs
+	case USERSTORES:
+		resourceConfigs = KEYWORD_CONFIGS.UserStoreConfigs
+	case EMAIL_TEMPLATES:
+		resourceConfigs = KEYWORD_CONFIGS.EmailTemplateConfigs
+	case USERS:
+		resourceConfigs = KEYWORD_CONFIGS.UserConfigs
+	case XACML_POLICIES:
+		resourceConfigs = KEYWORD_CONFIGS.XacmlPolicyConfigs
+	case ROLES:
+		resourceConfigs = KEYWORD_CONFIGS.RoleConfigs
+	case API_RESOURCES:
+		resourceConfigs = KEYWORD_CONFIGS.ApiResourceConfigs
+	case GOVERNANCE_CONNECTORS:
+		resourceConfigs = KEYWORD_CONFIGS.GovernanceConnectorConfigs
+	case BRANDING:
+		resourceConfigs = KEYWORD_CONFIGS.BrandingConfigs
+	case OIDC_SCOPES:
+		resourceConfigs = KEYWORD_CONFIGS.OidcScopeConfigs
+	case NOTIFICATION_SENDERS:
+		resourceConfigs = KEYWORD_CONFIGS.NotificationSenderConfigs
+	case WORKFLOWS:
+		resourceConfigs = KEYWORD_CONFIGS.WorkflowConfigs
+	case CORS:
+		resourceConfigs = KEYWORD_CONFIGS.CorsConfigs
+	case ORGANIZATIONS:
+		resourceConfigs = KEYWORD_CONFIGS.OrganizationConfigs
+	}
+	return ResolveAdvancedKeywordMapping(resourceName, resourceConfigs)
+}
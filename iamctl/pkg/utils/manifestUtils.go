@@ -0,0 +1,278 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v2"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+const APPLY_STATE_FILE = ".iamctl-apply-state.json"
+
+// Resource types that can be listed in the resources of an environment manifest.
+var MANIFEST_RESOURCE_TYPES = []string{CLAIMS, OIDC_SCOPES, IDENTITY_PROVIDERS, API_RESOURCES, ROLES, APPLICATIONS, USERSTORES,
+	EMAIL_TEMPLATES, BRANDING, GOVERNANCE_CONNECTORS, CORS, NOTIFICATION_SENDERS, USERS, WORKFLOWS, XACML_POLICIES,
+	ORGANIZATIONS}
+
+// Declarative description of an environment, applied with the apply-environment command.
+type EnvironmentManifest struct {
+	Profile struct {
+		Config string `yaml:"config"`
+		Env    string `yaml:"env"`
+	} `yaml:"profile"`
+	Resources  []ManifestResource `yaml:"resources"`
+	Keywords   map[string]string  `yaml:"keywords"`
+	Hooks      ManifestHooks      `yaml:"hooks"`
+	Guardrails ManifestGuardrails `yaml:"guardrails"`
+
+	// Directory of the manifest file, to resolve the relative paths in the manifest.
+	BaseDir string `yaml:"-"`
+	hash    string
+}
+
+type ManifestResource struct {
+	Dir   string   `yaml:"dir"`
+	Types []string `yaml:"types"`
+}
+
+type ManifestHooks struct {
+	Pre  []string `yaml:"pre"`
+	Post []string `yaml:"post"`
+}
+
+// Overrides of the tool configs and flags for the run. Fields that are not set keep the configured values.
+type ManifestGuardrails struct {
+	AllowDelete *bool  `yaml:"allowDelete"`
+	Strict      *bool  `yaml:"strict"`
+	AbortOnMask *bool  `yaml:"abortOnMask"`
+	TimeBudget  string `yaml:"timeBudget"`
+	WaitTimeout string `yaml:"waitTimeout"`
+}
+
+// Steps completed in a previous run of the same manifest.
+type applyState struct {
+	ManifestHash   string   `json:"manifestHash"`
+	CompletedSteps []string `json:"completedSteps"`
+}
+
+func LoadEnvironmentManifest(manifestPath string) (EnvironmentManifest, error) {
+
+	var manifest EnvironmentManifest
+	content, err := ioutil.ReadFile(manifestPath)
+	if err != nil {
+		return manifest, fmt.Errorf("error when reading the environment manifest: %s", err)
+	}
+	if err := yaml.UnmarshalStrict(content, &manifest); err != nil {
+		return manifest, fmt.Errorf("invalid environment manifest: %s. %s", manifestPath,
+			strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	manifest.BaseDir = filepath.Dir(manifestPath)
+	sum := sha256.Sum256(content)
+	manifest.hash = hex.EncodeToString(sum[:])
+
+	// The nodes of the manifest carry the line numbers of the values for the validation errors.
+	var root yamlv3.Node
+	if err := yamlv3.Unmarshal(content, &root); err != nil {
+		return manifest, fmt.Errorf("invalid environment manifest: %s. %s", manifestPath,
+			strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if err := validateManifest(manifest, &root); err != nil {
+		return manifest, fmt.Errorf("invalid environment manifest: %s. %s", manifestPath, err)
+	}
+	return manifest, nil
+}
+
+func validateManifest(manifest EnvironmentManifest, root *yamlv3.Node) error {
+
+	if len(manifest.Resources) == 0 {
+		return fmt.Errorf("line %d: at least one resource directory should be given", findManifestLine(root, "resources"))
+	}
+	typeDirs := make(map[string]int)
+	for i, resource := range manifest.Resources {
+		if resource.Dir == "" {
+			return fmt.Errorf("line %d: dir is required for each resource", findManifestLine(root, "resources", i))
+		}
+		if fileInfo, err := os.Stat(manifest.ResolvePath(resource.Dir)); err != nil || !fileInfo.IsDir() {
+			return fmt.Errorf("line %d: resource directory does not exist: %s", findManifestLine(root, "resources", i, "dir"),
+				resource.Dir)
+		}
+		for j, resourceType := range resource.Types {
+			if !Contains(MANIFEST_RESOURCE_TYPES, resourceType) {
+				return fmt.Errorf("line %d: unknown resource type: %s. Supported types: %s",
+					findManifestLine(root, "resources", i, "types", j), resourceType, strings.Join(MANIFEST_RESOURCE_TYPES, ", "))
+			}
+		}
+		for _, resourceType := range resource.GetTypes() {
+			typeDirs[resourceType]++
+		}
+	}
+
+	// Deleting the resources of a type imported from multiple directories would delete the resources of the other directories.
+	if manifest.Guardrails.AllowDelete != nil && *manifest.Guardrails.AllowDelete {
+		for resourceType, count := range typeDirs {
+			if count > 1 {
+				return fmt.Errorf("line %d: allowDelete cannot be set when %s are imported from multiple directories",
+					findManifestLine(root, "guardrails", "allowDelete"), resourceType)
+			}
+		}
+	}
+	for key, value := range map[string]string{"timeBudget": manifest.Guardrails.TimeBudget,
+		"waitTimeout": manifest.Guardrails.WaitTimeout} {
+		if _, err := manifest.parseDuration(value); err != nil {
+			return fmt.Errorf("line %d: invalid duration for %s: %s. Ex: 30m", findManifestLine(root, "guardrails", key), key, value)
+		}
+	}
+	return nil
+}
+
+// Returns the resource types of the directory. All resource types are imported if the types are not given.
+func (resource ManifestResource) GetTypes() []string {
+
+	if len(resource.Types) == 0 {
+		return MANIFEST_RESOURCE_TYPES
+	}
+	return resource.Types
+}
+
+func (manifest EnvironmentManifest) ResolvePath(path string) string {
+
+	if path == "" || filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(manifest.BaseDir, path)
+}
+
+func (manifest EnvironmentManifest) GetTimeBudget() time.Duration {
+
+	duration, _ := manifest.parseDuration(manifest.Guardrails.TimeBudget)
+	return duration
+}
+
+func (manifest EnvironmentManifest) GetWaitTimeout() time.Duration {
+
+	duration, _ := manifest.parseDuration(manifest.Guardrails.WaitTimeout)
+	return duration
+}
+
+func (manifest EnvironmentManifest) parseDuration(value string) (time.Duration, error) {
+
+	if value == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// Returns the steps completed in the previous run of the manifest, if the manifest is not changed since then.
+func (manifest EnvironmentManifest) LoadCompletedSteps() []string {
+
+	content, err := ioutil.ReadFile(filepath.Join(manifest.BaseDir, APPLY_STATE_FILE))
+	if err != nil {
+		return nil
+	}
+	var state applyState
+	if json.Unmarshal(content, &state) != nil || state.ManifestHash != manifest.hash {
+		return nil
+	}
+	return state.CompletedSteps
+}
+
+func (manifest EnvironmentManifest) SaveCompletedSteps(steps []string) error {
+
+	statePath := filepath.Join(manifest.BaseDir, APPLY_STATE_FILE)
+	if len(steps) == 0 {
+		if err := os.Remove(statePath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error when removing the apply state file: %s", err)
+		}
+		return nil
+	}
+	content, err := json.MarshalIndent(applyState{ManifestHash: manifest.hash, CompletedSteps: steps}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error when creating the apply state: %s", err)
+	}
+	if err := ioutil.WriteFile(statePath, content, 0644); err != nil {
+		return fmt.Errorf("error when writing the apply state file: %s", err)
+	}
+	return nil
+}
+
+// Run a hook command from the directory of the manifest.
+func (manifest EnvironmentManifest) RunHook(command string) error {
+
+	var hookCmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		hookCmd = exec.Command("cmd", "/C", command)
+	} else {
+		hookCmd = exec.Command("sh", "-c", command)
+	}
+	hookCmd.Dir = manifest.BaseDir
+	hookCmd.Env = append(os.Environ(), IAMCTL_ENV_CONFIG+"="+ENVIRONMENT)
+	hookCmd.Stdout = os.Stdout
+	hookCmd.Stderr = os.Stderr
+	if err := hookCmd.Run(); err != nil {
+		return fmt.Errorf("hook failed: %s. %s", command, err)
+	}
+	return nil
+}
+
+// Returns the line of the node at the given path of mapping keys and sequence indexes. The line of the closest
+// parent node is returned if the node does not exist in the manifest.
+func findManifestLine(node *yamlv3.Node, path ...interface{}) int {
+
+	if node.Kind == yamlv3.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	line := node.Line
+	for _, step := range path {
+		var next *yamlv3.Node
+		switch key := step.(type) {
+		case string:
+			for k := 0; node.Kind == yamlv3.MappingNode && k+1 < len(node.Content); k += 2 {
+				if node.Content[k].Value == key {
+					// The line of the key is used since a block value starts on the next line.
+					line = node.Content[k].Line
+					next = node.Content[k+1]
+				}
+			}
+		case int:
+			if node.Kind == yamlv3.SequenceNode && key < len(node.Content) {
+				next = node.Content[key]
+				line = next.Line
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	if line == 0 {
+		return 1
+	}
+	return line
+}
@@ -0,0 +1,249 @@
+//go:build metrics
+// +build metrics
+
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const METRICS_PATH = "/metrics"
+
+// Port to serve the Prometheus metrics on. Set by the --metrics-port flag.
+var METRICS_PORT int
+
+// Upper bounds of the latency histogram buckets in seconds. Same as the default buckets of the Prometheus clients.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type latencyHistogram struct {
+	bucketCounts []uint64
+	sum          float64
+	count        uint64
+}
+
+type metricsRegistry struct {
+	mutex            sync.Mutex
+	resourceCounters map[string]map[string]float64
+	latencies        map[string]*latencyHistogram
+}
+
+var activeMetrics *metricsRegistry
+
+func IsMetricsEnabled() bool {
+
+	return activeMetrics != nil
+}
+
+// Start serving the metrics if the port is given. The metrics are kept for the lifetime of the process, so that
+// the counters accumulate across the runs of a long-running controller.
+func StartMetricsServer() {
+
+	if METRICS_PORT == 0 || activeMetrics != nil {
+		return
+	}
+	listener, err := net.Listen("tcp", ":"+strconv.Itoa(METRICS_PORT))
+	if err != nil {
+		log.Println("Error when starting the metrics endpoint.", err)
+		return
+	}
+	activeMetrics = &metricsRegistry{
+		resourceCounters: make(map[string]map[string]float64),
+		latencies:        make(map[string]*latencyHistogram),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(METRICS_PATH, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteMetrics(w)
+	})
+	go http.Serve(listener, mux)
+	log.Printf("Serving metrics at: http://localhost:%d%s", METRICS_PORT, METRICS_PATH)
+}
+
+// Record the latency of each API call by the resource type and the operation.
+func MetricsMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if activeMetrics == nil {
+			return next.RoundTrip(req)
+		}
+		startTime := time.Now()
+		resp, err := next.RoundTrip(req)
+		activeMetrics.observeLatency(getRequestResourceType(req.URL.Path), getRequestOperation(req),
+			time.Since(startTime).Seconds())
+		return resp, err
+	})
+}
+
+// Write the metrics in the Prometheus text format.
+func WriteMetrics(w io.Writer) {
+
+	if activeMetrics == nil {
+		return
+	}
+	activeMetrics.mutex.Lock()
+	defer activeMetrics.mutex.Unlock()
+
+	writeCounter(w, "iamctl_resources_exported_total", "Number of resources exported.", EXPORT)
+	writeCounter(w, "iamctl_resources_imported_total", "Number of resources created during import.", IMPORT)
+	writeCounter(w, "iamctl_resources_updated_total", "Number of resources updated during import.", UPDATE)
+	writeCounter(w, "iamctl_resources_deleted_total", "Number of resources deleted.", DELETE)
+	writeCounter(w, "iamctl_resources_failed_total", "Number of resources that failed to be processed.",
+		PROGRESS_RESULT_FAILED)
+
+	name := "iamctl_api_request_duration_seconds"
+	fmt.Fprintf(w, "# HELP %s Latency of the API calls to the target environment.\n", name)
+	fmt.Fprintf(w, "# TYPE %s histogram\n", name)
+	for _, key := range sortedKeys(activeMetrics.latencies) {
+		histogram := activeMetrics.latencies[key]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "%s_bucket{%s,le=\"%s\"} %d\n", name, key, formatFloat(bound), histogram.bucketCounts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, key, histogram.count)
+		fmt.Fprintf(w, "%s_sum{%s} %s\n", name, key, formatFloat(histogram.sum))
+		fmt.Fprintf(w, "%s_count{%s} %d\n", name, key, histogram.count)
+	}
+}
+
+func recordResourceMetric(resourceType string, operation string) {
+
+	if activeMetrics == nil {
+		return
+	}
+	activeMetrics.mutex.Lock()
+	defer activeMetrics.mutex.Unlock()
+
+	counters, ok := activeMetrics.resourceCounters[operation]
+	if !ok {
+		counters = make(map[string]float64)
+		activeMetrics.resourceCounters[operation] = counters
+	}
+	counters[resourceType]++
+}
+
+func (registry *metricsRegistry) observeLatency(resourceType string, operation string, seconds float64) {
+
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+
+	key := fmt.Sprintf("resource_type=%q,operation=%q", resourceType, operation)
+	histogram, ok := registry.latencies[key]
+	if !ok {
+		histogram = &latencyHistogram{bucketCounts: make([]uint64, len(latencyBuckets))}
+		registry.latencies[key] = histogram
+	}
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			histogram.bucketCounts[i]++
+		}
+	}
+	histogram.sum += seconds
+	histogram.count++
+}
+
+func writeCounter(w io.Writer, name string, help string, operation string) {
+
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	counters := activeMetrics.resourceCounters[operation]
+	for _, resourceType := range sortedKeys(counters) {
+		fmt.Fprintf(w, "%s{resource_type=%q} %s\n", name, resourceType, formatFloat(counters[resourceType]))
+	}
+}
+
+func getRequestResourceType(path string) string {
+
+	switch {
+	case strings.Contains(path, "/oauth2/token"):
+		return "Token"
+	case strings.Contains(path, "/scim2/Users"):
+		return USERS
+	case strings.Contains(path, "/scim2/v2/Roles"):
+		return ROLES
+	case strings.Contains(path, "/services/EntitlementPolicyAdminService"):
+		return XACML_POLICIES
+	case strings.Contains(path, "/api/server/v1/"+getResourcePath(WORKFLOW_ASSOCIATIONS)):
+		return WORKFLOWS
+	case strings.Contains(path, "/api/server/v1/"+getResourcePath(AUTHENTICATORS)):
+		return IDENTITY_PROVIDERS
+	}
+	for _, resourceType := range []string{APPLICATIONS, IDENTITY_PROVIDERS, CLAIMS, USERSTORES, EMAIL_TEMPLATES, API_RESOURCES,
+		GOVERNANCE_CONNECTORS, BRANDING, OIDC_SCOPES, NOTIFICATION_SENDERS, WORKFLOWS, CORS, ORGANIZATIONS} {
+		if strings.Contains(path, "/api/server/v1/"+getResourcePath(resourceType)) {
+			return resourceType
+		}
+	}
+	return "Other"
+}
+
+func getRequestOperation(req *http.Request) string {
+
+	switch {
+	case strings.HasSuffix(req.URL.Path, "/exportFile") || strings.HasSuffix(req.URL.Path, "/"+EXPORT):
+		return EXPORT
+	case strings.HasSuffix(req.URL.Path, "/"+IMPORT) && req.Method == http.MethodPut:
+		return UPDATE
+	case strings.HasSuffix(req.URL.Path, "/"+IMPORT):
+		return IMPORT
+	case strings.HasSuffix(req.URL.Path, "/.search"):
+		return "get"
+	}
+	switch req.Method {
+	case http.MethodPost:
+		return "create"
+	case http.MethodPut, http.MethodPatch:
+		return UPDATE
+	case http.MethodDelete:
+		return DELETE
+	}
+	return "get"
+}
+
+func sortedKeys(values interface{}) []string {
+
+	var keys []string
+	switch values := values.(type) {
+	case map[string]float64:
+		for key := range values {
+			keys = append(keys, key)
+		}
+	case map[string]*latencyHistogram:
+		for key := range values {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func formatFloat(value float64) string {
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,52 @@
+//go:build !metrics
+// +build !metrics
+
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"log"
+	"net/http"
+)
+
+// Port to serve the Prometheus metrics on. Set by the --metrics-port flag.
+var METRICS_PORT int
+
+func IsMetricsEnabled() bool {
+
+	return false
+}
+
+// The metrics endpoint is only available in the binaries built with the metrics build tag.
+func StartMetricsServer() {
+
+	if METRICS_PORT != 0 {
+		log.Println("Warning: Metrics are not supported in this build. Build the tool with the metrics build tag " +
+			"to serve the metrics.")
+	}
+}
+
+func MetricsMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return next
+}
+
+func recordResourceMetric(resourceType string, operation string) {
+}
@@ -0,0 +1,44 @@
+//go:build !windows
+// +build !windows
+
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"net"
+	"time"
+)
+
+// Named pipes are only supported on Windows. The progress socket is always a Unix domain socket on other platforms.
+func isNamedPipe(path string) bool {
+
+	return false
+}
+
+func dialPipe(path string, timeout time.Duration) (net.Conn, error) {
+
+	return nil, errors.New("named pipes are only supported on Windows")
+}
+
+func listenPipe(path string) (net.Listener, error) {
+
+	return nil, errors.New("named pipes are only supported on Windows")
+}
@@ -0,0 +1,275 @@
+//go:build windows
+// +build windows
+
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// Prefix of the paths of the named pipes. Ex: \\.\pipe\iamctl
+const NAMED_PIPE_PREFIX = `\\.\pipe\`
+
+// Flags of the named pipes that are not defined in the windows package.
+const pipeAccessDuplex = 0x00000003
+const pipeRejectRemoteClients = 0x00000008
+const pipeUnlimitedInstances = 255
+const pipeBufferSize = 4096
+const waitTimeout = 0x00000102
+
+var kernel32 = windows.NewLazySystemDLL("kernel32.dll")
+var procCreateNamedPipeW = kernel32.NewProc("CreateNamedPipeW")
+var procConnectNamedPipe = kernel32.NewProc("ConnectNamedPipe")
+
+type pipeAddr string
+
+func (addr pipeAddr) Network() string {
+
+	return "pipe"
+}
+
+func (addr pipeAddr) String() string {
+
+	return string(addr)
+}
+
+// Connection to a named pipe. The reads and writes are overlapped, so that they can be stopped at the deadlines.
+type pipeConn struct {
+	handle        windows.Handle
+	path          string
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+// Listener that creates a new instance of the named pipe for each connection.
+type pipeListener struct {
+	path   string
+	mutex  sync.Mutex
+	handle windows.Handle
+	closed bool
+}
+
+func isNamedPipe(path string) bool {
+
+	return len(path) >= len(NAMED_PIPE_PREFIX) && strings.EqualFold(path[:len(NAMED_PIPE_PREFIX)], NAMED_PIPE_PREFIX)
+}
+
+func dialPipe(path string, timeout time.Duration) (net.Conn, error) {
+
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(path), Err: err}
+	}
+	deadline := time.Now().Add(timeout)
+	for {
+		// The pipe is only written to, so that the pipes created only for inbound access can be used.
+		handle, err := windows.CreateFile(pathPtr, windows.GENERIC_WRITE, 0, nil, windows.OPEN_EXISTING,
+			windows.FILE_FLAG_OVERLAPPED, 0)
+		if err == nil {
+			return &pipeConn{handle: handle, path: path}, nil
+		}
+
+		// All instances of the pipe are connected until the reader creates a new instance.
+		if err != windows.ERROR_PIPE_BUSY || time.Now().After(deadline) {
+			return nil, &net.OpError{Op: "dial", Net: "pipe", Addr: pipeAddr(path), Err: err}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func listenPipe(path string) (net.Listener, error) {
+
+	// Fail if the pipe is already used by another listener.
+	handle, err := createPipeInstance(path, windows.FILE_FLAG_FIRST_PIPE_INSTANCE)
+	if err != nil {
+		return nil, &net.OpError{Op: "listen", Net: "pipe", Addr: pipeAddr(path), Err: err}
+	}
+	return &pipeListener{path: path, handle: handle}, nil
+}
+
+func createPipeInstance(path string, flags uint32) (windows.Handle, error) {
+
+	pathPtr, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return windows.InvalidHandle, err
+	}
+	handle, _, err := procCreateNamedPipeW.Call(uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(pipeAccessDuplex|windows.FILE_FLAG_OVERLAPPED|flags), pipeRejectRemoteClients, pipeUnlimitedInstances,
+		pipeBufferSize, pipeBufferSize, 0, 0)
+	if windows.Handle(handle) == windows.InvalidHandle {
+		return windows.InvalidHandle, err
+	}
+	return windows.Handle(handle), nil
+}
+
+// Runs an overlapped operation on the handle and waits for it until the deadline.
+func waitForOverlapped(handle windows.Handle, deadline time.Time,
+	operation func(overlapped *windows.Overlapped) error) (int, error) {
+
+	event, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer windows.CloseHandle(event)
+
+	// The overlapped structure is in use by the system until the operation completes.
+	overlapped := &windows.Overlapped{HEvent: event}
+	var done uint32
+	err = operation(overlapped)
+	if err != nil && err != windows.ERROR_IO_PENDING {
+		return 0, err
+	}
+	timeout := uint32(windows.INFINITE)
+	if !deadline.IsZero() {
+		timeout = 0
+		if remaining := time.Until(deadline); remaining > 0 {
+			timeout = uint32(remaining / time.Millisecond)
+		}
+	}
+	if result, _ := windows.WaitForSingleObject(event, timeout); result == waitTimeout {
+		windows.CancelIoEx(handle, overlapped)
+		windows.GetOverlappedResult(handle, overlapped, &done, true)
+		return int(done), os.ErrDeadlineExceeded
+	}
+	err = windows.GetOverlappedResult(handle, overlapped, &done, true)
+	return int(done), err
+}
+
+func (conn *pipeConn) Read(b []byte) (int, error) {
+
+	n, err := waitForOverlapped(conn.handle, conn.readDeadline, func(overlapped *windows.Overlapped) error {
+		return windows.ReadFile(conn.handle, b, nil, overlapped)
+	})
+
+	// The other end of the pipe is closed.
+	if err == windows.ERROR_BROKEN_PIPE || err == windows.ERROR_PIPE_NOT_CONNECTED {
+		return n, io.EOF
+	}
+	return n, err
+}
+
+func (conn *pipeConn) Write(b []byte) (int, error) {
+
+	return waitForOverlapped(conn.handle, conn.writeDeadline, func(overlapped *windows.Overlapped) error {
+		return windows.WriteFile(conn.handle, b, nil, overlapped)
+	})
+}
+
+func (conn *pipeConn) Close() error {
+
+	return windows.CloseHandle(conn.handle)
+}
+
+func (conn *pipeConn) LocalAddr() net.Addr {
+
+	return pipeAddr(conn.path)
+}
+
+func (conn *pipeConn) RemoteAddr() net.Addr {
+
+	return pipeAddr(conn.path)
+}
+
+func (conn *pipeConn) SetDeadline(deadline time.Time) error {
+
+	conn.readDeadline = deadline
+	conn.writeDeadline = deadline
+	return nil
+}
+
+func (conn *pipeConn) SetReadDeadline(deadline time.Time) error {
+
+	conn.readDeadline = deadline
+	return nil
+}
+
+func (conn *pipeConn) SetWriteDeadline(deadline time.Time) error {
+
+	conn.writeDeadline = deadline
+	return nil
+}
+
+func (listener *pipeListener) Accept() (net.Conn, error) {
+
+	listener.mutex.Lock()
+	handle, closed := listener.handle, listener.closed
+	listener.mutex.Unlock()
+	if closed {
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(listener.path), Err: net.ErrClosed}
+	}
+	_, err := waitForOverlapped(handle, time.Time{}, func(overlapped *windows.Overlapped) error {
+		result, _, err := procConnectNamedPipe.Call(uintptr(handle), uintptr(unsafe.Pointer(overlapped)))
+		if result != 0 {
+			return nil
+		}
+		return err
+	})
+
+	// The client connected before the connection was waited for.
+	if err == windows.ERROR_PIPE_CONNECTED {
+		err = nil
+	}
+
+	// A new instance of the pipe waits for the next connection while the connected instance is in use.
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+	if listener.closed {
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(listener.path), Err: net.ErrClosed}
+	}
+	if err != nil {
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(listener.path), Err: err}
+	}
+	nextHandle, err := createPipeInstance(listener.path, 0)
+	if err != nil {
+		windows.CloseHandle(handle)
+		listener.closed = true
+		return nil, &net.OpError{Op: "accept", Net: "pipe", Addr: pipeAddr(listener.path), Err: err}
+	}
+	listener.handle = nextHandle
+	return &pipeConn{handle: handle, path: listener.path}, nil
+}
+
+func (listener *pipeListener) Close() error {
+
+	listener.mutex.Lock()
+	defer listener.mutex.Unlock()
+	if listener.closed {
+		return nil
+	}
+	listener.closed = true
+
+	// Stop the pending accept before the pipe is closed.
+	windows.CancelIoEx(listener.handle, nil)
+	return windows.CloseHandle(listener.handle)
+}
+
+func (listener *pipeListener) Addr() net.Addr {
+
+	return pipeAddr(listener.path)
+}
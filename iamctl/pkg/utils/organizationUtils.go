@@ -0,0 +1,135 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"path/filepath"
+)
+
+const ORGANIZATIONS_DIR = "Organizations"
+const ORGANIZATION_SWITCH_GRANT = "organization_switch"
+const ORGANIZATION_VIEW_SCOPE = "internal_organization_view"
+const ORGANIZATION_SCOPE = "internal_org_application_mgt_view internal_org_application_mgt_create " +
+	"internal_org_application_mgt_update internal_org_application_mgt_delete internal_org_idp_view " +
+	"internal_org_idp_create internal_org_idp_update internal_org_idp_delete"
+
+// Resource types that are managed in a sub organization.
+var ORGANIZATION_RESOURCE_TYPES = []string{APPLICATIONS, IDENTITY_PROVIDERS}
+
+// Name of the sub organization to be managed instead of the root organization. Set by the --org flag.
+var ORGANIZATION string
+
+type organizationListResponse struct {
+	Organizations []struct {
+		Id   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"organizations"`
+}
+
+// Get the base URL of the tenant, or of the sub organization selected with the --org flag. The sub organization
+// is resolved from the organization switched access token.
+func GetTenantUrl() string {
+
+	tenantUrl := SERVER_CONFIGS.ServerUrl + "/t/" + SERVER_CONFIGS.TenantDomain
+	if ORGANIZATION != "" {
+		return tenantUrl + "/o"
+	}
+	return tenantUrl
+}
+
+// Get the directory of the sub organization inside the given base directory, so that the resources of
+// different organizations do not overwrite each other.
+func GetOrganizationDir(baseDir string) string {
+
+	if ORGANIZATION == "" {
+		return baseDir
+	}
+	return filepath.Join(baseDir, ORGANIZATIONS_DIR, ORGANIZATION)
+}
+
+func IsOrganizationResourceType(resourceType string) bool {
+
+	return ORGANIZATION == "" || Contains(ORGANIZATION_RESOURCE_TYPES, resourceType)
+}
+
+// Replace the access token of the root organization with a token switched to the sub organization.
+func switchOrganization() error {
+
+	orgId, err := getOrganizationId(ORGANIZATION)
+	if err != nil {
+		return err
+	}
+	log.Printf("Resolved organization: %s with id: %s", ORGANIZATION, orgId)
+
+	body := url.Values{}
+	body.Set("grant_type", ORGANIZATION_SWITCH_GRANT)
+	body.Set("token", SERVER_CONFIGS.Token)
+	body.Set("switching_organization", orgId)
+	body.Set("scope", ORGANIZATION_SCOPE)
+	token, err := sendTokenRequest(GetRequestContext(), SERVER_CONFIGS, body)
+	if err != nil {
+		return fmt.Errorf("error when switching the access token to the organization: %s", err)
+	}
+	SERVER_CONFIGS.Token = token
+	// URLs with the id of the sub organization are expected in its resources, instead of the root organization.
+	SERVER_CONFIGS.OrganizationId = orgId
+	return nil
+}
+
+func getOrganizationId(orgName string) (string, error) {
+
+	reqUrl := SERVER_CONFIGS.ServerUrl + "/t/" + SERVER_CONFIGS.TenantDomain + "/api/server/v1/organizations?" +
+		url.Values{"filter": {"name eq " + orgName}}.Encode()
+	req, err := http.NewRequestWithContext(GetRequestContext(), http.MethodGet, reqUrl, nil)
+	if err != nil {
+		return "", fmt.Errorf("error when creating the organization request: %s", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+SERVER_CONFIGS.Token)
+	req.Header.Set("accept", MEDIA_TYPE_JSON)
+
+	resp, err := GetHttpClient().Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error when retrieving the organization: %s", err)
+	}
+	defer resp.Body.Close()
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("error when reading the organization response: %s", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error when retrieving the organization: %s", resp.Status)
+	}
+
+	var response organizationListResponse
+	if err := json.Unmarshal(respBody, &response); err != nil {
+		return "", fmt.Errorf("error when unmarshalling the organization response: %s", err)
+	}
+	for _, org := range response.Organizations {
+		if org.Name == orgName {
+			return org.Id, nil
+		}
+	}
+	return "", fmt.Errorf("organization: %s is not found", orgName)
+}
@@ -0,0 +1,87 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Output formats of the list commands.
+const OUTPUT_TABLE = "table"
+const OUTPUT_JSON = "json"
+const OUTPUT_YAML = "yaml"
+
+var OUTPUT_FORMATS = []string{OUTPUT_TABLE, OUTPUT_JSON, OUTPUT_YAML}
+
+func ValidateOutputFormat(format string) error {
+
+	if !Contains(OUTPUT_FORMATS, format) {
+		return fmt.Errorf("invalid output format: %s. Supported formats: %s", format, strings.Join(OUTPUT_FORMATS, ", "))
+	}
+	return nil
+}
+
+// Write the listed resources in the JSON or YAML format, so that the output can be processed by other tools.
+func WriteResourceList(writer io.Writer, format string, resources []map[string]interface{}) error {
+
+	// An empty list is written instead of null when no resource is found.
+	if resources == nil {
+		resources = []map[string]interface{}{}
+	}
+	var content []byte
+	var err error
+	switch format {
+	case OUTPUT_JSON:
+		content, err = json.MarshalIndent(resources, "", "  ")
+		content = append(content, '\n')
+	case OUTPUT_YAML:
+		content, err = yaml.Marshal(resources)
+	default:
+		return fmt.Errorf("unsupported output format for the resource list: %s", format)
+	}
+	if err != nil {
+		return fmt.Errorf("error when writing the resource list: %s", err)
+	}
+	_, err = writer.Write(content)
+	return err
+}
+
+// Get a matcher of the resource names for the given filter. The filter is used as a regular expression, and as a
+// substring if it is not a valid regular expression. All names match an empty filter.
+func GetNameMatcher(filter string) func(name string) bool {
+
+	if filter == "" {
+		return func(name string) bool {
+			return true
+		}
+	}
+	filterRegex, err := regexp.Compile(filter)
+	if err != nil {
+		return func(name string) bool {
+			return strings.Contains(name, filter)
+		}
+	}
+	return filterRegex.MatchString
+}
@@ -0,0 +1,105 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// Continue the import when the tool cannot read some of the resource types. Set by the --allow-partial-permissions
+// flag.
+var ALLOW_PARTIAL_PERMISSIONS bool
+
+// Results of the permission probes of each server and resource type, so that a resource type is probed once per run
+// and the coverage report of the run does not request the denied resource types again. A nil result means the
+// resource type is permitted.
+var resourcePermissions = make(map[string]error)
+
+// Check that the tool can read the given resource types before any resource is modified, so that a token without the
+// scopes of a resource type does not leave a partially imported change set. The probe only sends a GET request, so a
+// token that can read but not modify a resource type passes the check.
+func CheckResourcePermissions(resourceTypes []string) error {
+
+	var deniedTypes []string
+	for _, resourceType := range resourceTypes {
+		if IsResourceTypeExcluded(resourceType) {
+			continue
+		}
+		if err := getResourceTypePermission(resourceType); err != nil {
+			deniedTypes = append(deniedTypes, resourceType)
+		}
+	}
+	if len(deniedTypes) == 0 {
+		return nil
+	}
+	if ALLOW_PARTIAL_PERMISSIONS {
+		log.Printf("Read access is denied for the resource types: %s, when probed with a GET request for a single "+
+			"resource. These resource types will fail during the import.", strings.Join(deniedTypes, ", "))
+		return nil
+	}
+	return fmt.Errorf("read access is denied for the resource types: %s, when probed with a GET request for a single "+
+		"resource. Add the required scopes to the application of the tool, or use --allow-partial-permissions to "+
+		"import the other resource types", strings.Join(deniedTypes, ", "))
+}
+
+// Probe whether the tool can read the resource type with a GET request for a single resource.
+// Returns ErrPermissionDenied if the request is denied.
+func getResourceTypePermission(resourceType string) error {
+
+	permissionKey := GetTenantUrl() + "/" + resourceType
+	if result, ok := resourcePermissions[permissionKey]; ok {
+		return result
+	}
+	var result error
+	if path, ok := getPermissionProbePath(resourceType); ok {
+		_, err := SendJsonRequest(GetRequestContext(), http.MethodGet, resourceType, path, nil)
+		if errors.Is(err, ErrPermissionDenied) {
+			result = err
+		} else if err != nil {
+			// Other errors are reported when the resources are processed.
+			LogDebug(fmt.Sprintf("Error when probing the permission of %s: %s", resourceType, err))
+		}
+	}
+	LogDebug(fmt.Sprintf("Permission of %s: %v", permissionKey, result == nil))
+	resourcePermissions[permissionKey] = result
+	return result
+}
+
+// Check the permission probes of the run without sending a request.
+func isResourceTypeDenied(resourceType string) bool {
+
+	result, ok := resourcePermissions[GetTenantUrl()+"/"+resourceType]
+	return ok && result != nil
+}
+
+func getPermissionProbePath(resourceType string) (string, bool) {
+
+	// The count queries request a single resource where the API supports it. Resource types without a JSON
+	// list path are not probed.
+	for _, query := range resourceCountQueries {
+		if query.resourceType == resourceType && len(query.paths) > 0 {
+			return query.paths[0], true
+		}
+	}
+	return "", false
+}
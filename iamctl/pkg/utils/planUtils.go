@@ -0,0 +1,107 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+)
+
+// Print only the planned changes of the import without importing the resources. Set by the --summary-only flag.
+var SUMMARY_ONLY bool
+
+// Changes planned for a resource type, based only on the resource names of the local files and the deployed resources.
+type ImportPlan struct {
+	ResourceType string
+	Creates      int
+	Updates      int
+	Deletes      int
+}
+
+// A deployed resource with the name in the form used for the local file name.
+type DeployedResource struct {
+	Name      string
+	Deletable bool
+}
+
+func NewImportPlan(resourceType string, localNames []string, deployedResources []DeployedResource, deleteAllowed bool) ImportPlan {
+
+	plan := ImportPlan{ResourceType: resourceType}
+	deployedNames := make(map[string]bool)
+	for _, deployedResource := range deployedResources {
+		deployedNames[deployedResource.Name] = true
+	}
+	for _, localName := range localNames {
+		if deployedNames[localName] {
+			plan.Updates++
+		} else {
+			plan.Creates++
+		}
+	}
+	if !deleteAllowed {
+		return plan
+	}
+	for _, deployedResource := range deployedResources {
+		if deployedResource.Deletable && !Contains(localNames, deployedResource.Name) {
+			plan.Deletes++
+		}
+	}
+	return plan
+}
+
+// Get the names of the local resource files of a resource type that are selected for import.
+func GetLocalResourceNames(importFilePath string, resourceConfigs map[string]interface{}) ([]string, error) {
+
+	files, err := ioutil.ReadDir(importFilePath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("error when reading the local resources: %s", err)
+	}
+	var localNames []string
+	for _, file := range files {
+		if file.IsDir() || IsReferencedFile(file.Name()) || IsBaselineFile(file.Name()) {
+			continue
+		}
+		resourceName := ResolveResourceName(filepath.Join(importFilePath, file.Name()))
+		if !IsResourceIncluded(resourceName) || IsResourceExcluded(resourceName, resourceConfigs) {
+			continue
+		}
+		localNames = append(localNames, resourceName)
+	}
+	return localNames, nil
+}
+
+func PrintImportPlans(plans []ImportPlan) {
+
+	var total ImportPlan
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE TYPE\tCREATES\tUPDATES\tDELETES")
+	for _, plan := range plans {
+		fmt.Fprintf(writer, "%s\t%d\t%d\t%d\n", plan.ResourceType, plan.Creates, plan.Updates, plan.Deletes)
+		total.Creates += plan.Creates
+		total.Updates += plan.Updates
+		total.Deletes += plan.Deletes
+	}
+	fmt.Fprintf(writer, "Total\t%d\t%d\t%d\n", total.Creates, total.Updates, total.Deletes)
+	writer.Flush()
+}
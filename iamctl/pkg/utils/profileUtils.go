@@ -0,0 +1,198 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Environment variable to use a profiles file other than the default file in the home directory.
+const IAMCTL_PROFILES_FILE = "IAMCTL_PROFILES_FILE"
+
+const PROFILES_DIR = ".iamctl"
+const PROFILES_FILE = "config.yaml"
+const CURRENT_PROFILE_KEY = "currentProfile"
+
+// Name of the server config profile to be used. Set by the --config-profile flag.
+var CONFIG_PROFILE string
+
+// Server configs of a named target environment in the profiles file. The tool and keyword config paths are used
+// when the --config flag is not set.
+type ConfigProfile struct {
+	Name              string
+	ServerConfigs     ServerConfigs
+	ToolConfigPath    string
+	KeywordConfigPath string
+}
+
+type profilesFile struct {
+	CurrentProfile string                            `yaml:"currentProfile"`
+	Profiles       map[string]map[string]interface{} `yaml:"profiles"`
+}
+
+// Get the path of the profiles file, which is config.yaml in the .iamctl folder of the home directory by default.
+func GetProfilesFilePath() (string, error) {
+
+	if filePath := os.Getenv(IAMCTL_PROFILES_FILE); filePath != "" {
+		return filePath, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error when finding the home directory: %s", err)
+	}
+	return filepath.Join(home, PROFILES_DIR, PROFILES_FILE), nil
+}
+
+// List the names of the profiles in the profiles file, and the name of the default profile.
+func ListConfigProfiles() (names []string, currentProfile string, err error) {
+
+	profiles, err := readProfilesFile()
+	if err != nil {
+		return nil, "", err
+	}
+	for name := range profiles.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, profiles.CurrentProfile, nil
+}
+
+// Set the default profile used when neither the --config-profile flag nor the --config flag is set.
+func UseConfigProfile(name string) error {
+
+	profiles, err := readProfilesFile()
+	if err != nil {
+		return err
+	}
+	if _, ok := profiles.Profiles[name]; !ok {
+		return fmt.Errorf("profile: %s is not defined in the profiles file", name)
+	}
+
+	// The file is updated as a generic map, so that the other keys of the file are kept.
+	filePath, _ := GetProfilesFilePath()
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the profiles file: %s", err)
+	}
+	var fileYaml yaml.MapSlice
+	if err := yaml.Unmarshal(content, &fileYaml); err != nil {
+		return fmt.Errorf("error when parsing the profiles file: %s", err)
+	}
+	updated := false
+	for i := range fileYaml {
+		if fileYaml[i].Key == CURRENT_PROFILE_KEY {
+			fileYaml[i].Value = name
+			updated = true
+		}
+	}
+	if !updated {
+		fileYaml = append(yaml.MapSlice{{Key: CURRENT_PROFILE_KEY, Value: name}}, fileYaml...)
+	}
+	content, err = yaml.Marshal(fileYaml)
+	if err != nil {
+		return fmt.Errorf("error when updating the profiles file: %s", err)
+	}
+	return writeFileAtomically(filePath, content, 0600)
+}
+
+// Get the profile selected with the --config-profile flag. If the flag is not set and useDefault is true, the
+// default profile of the profiles file is returned. Returns nil if no profile is selected.
+func GetActiveConfigProfile(useDefault bool) (*ConfigProfile, error) {
+
+	name := CONFIG_PROFILE
+	if name == "" && !useDefault {
+		return nil, nil
+	}
+	if name == "" {
+		filePath, err := GetProfilesFilePath()
+		if err != nil {
+			return nil, nil
+		}
+		if _, err := os.Stat(filePath); os.IsNotExist(err) {
+			return nil, nil
+		}
+	}
+	profiles, err := readProfilesFile()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = profiles.CurrentProfile
+		if name == "" {
+			return nil, nil
+		}
+	}
+	profileConfigs, ok := profiles.Profiles[name]
+	if !ok {
+		return nil, fmt.Errorf("profile: %s is not defined in the profiles file", name)
+	}
+	return parseConfigProfile(name, profileConfigs)
+}
+
+func parseConfigProfile(name string, profileConfigs map[string]interface{}) (*ConfigProfile, error) {
+
+	profile := ConfigProfile{Name: name}
+	profile.ToolConfigPath, _ = profileConfigs[TOOL_CONFIG_PATH].(string)
+	profile.KeywordConfigPath, _ = profileConfigs[KEYWORD_CONFIG_PATH].(string)
+
+	// The profile holds the same keys as the server config file, so it is resolved in the same way.
+	configFile, err := json.Marshal(profileConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("profile: %s is not in the correct format. %s", name, err)
+	}
+	configFile = ReplacePlaceholders(configFile)
+	if ENCRYPTED_CONFIG {
+		passphrase, err := GetConfigPassphrase()
+		if err != nil {
+			return nil, err
+		}
+		configFile, err = DecryptServerConfigs(configFile, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("error when decrypting the profile: %s. %s", name, err)
+		}
+	}
+	if err := json.Unmarshal(configFile, &profile.ServerConfigs); err != nil {
+		return nil, fmt.Errorf("profile: %s is not in the correct format. %s", name, err)
+	}
+	return &profile, nil
+}
+
+func readProfilesFile() (profilesFile, error) {
+
+	var profiles profilesFile
+	filePath, err := GetProfilesFilePath()
+	if err != nil {
+		return profiles, err
+	}
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return profiles, fmt.Errorf("error when reading the profiles file: %s", err)
+	}
+	if err := yaml.Unmarshal(content, &profiles); err != nil {
+		return profiles, fmt.Errorf("error when parsing the profiles file: %s. %s", filePath, err)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,151 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Percentage step of the progress log lines written when the output is not a terminal.
+const PROGRESS_LOG_STEP = 10
+
+// Disable the progress display. Set by the --no-progress flag.
+var NO_PROGRESS bool
+
+type progressDisplay struct {
+	interactive      bool
+	logOutput        io.Writer
+	resourceType     string
+	total            int
+	processed        int
+	loggedPercentage int
+	lineShown        bool
+}
+
+var display *progressDisplay
+
+// Writes the log lines above the progress counter, so that the counter stays on the last line of the terminal.
+type progressLogWriter struct {
+	out io.Writer
+}
+
+func (writer progressLogWriter) Write(logLine []byte) (int, error) {
+
+	clearProgressLine()
+	n, err := writer.out.Write(logLine)
+	drawProgressLine()
+	return n, err
+}
+
+func startProgressDisplay() {
+
+	if NO_PROGRESS || display != nil {
+		return
+	}
+	display = &progressDisplay{interactive: terminal.IsTerminal(int(os.Stdout.Fd())), logOutput: log.Writer()}
+	if display.interactive {
+		log.SetOutput(progressLogWriter{out: display.logOutput})
+	}
+}
+
+func finishProgressDisplay() {
+
+	if display == nil {
+		return
+	}
+	EndResourceProgress()
+	if display.interactive {
+		log.SetOutput(display.logOutput)
+	}
+	display = nil
+}
+
+// Start the progress of a resource type with the total number of resources of the type. The progress is advanced by
+// the resources that are finished, skipped or failed.
+func StartResourceProgress(resourceType string, total int) {
+
+	if display == nil {
+		return
+	}
+	EndResourceProgress()
+	if total <= 0 {
+		return
+	}
+	display.resourceType = resourceType
+	display.total = total
+	display.processed = 0
+	display.loggedPercentage = 0
+	drawProgressLine()
+}
+
+// End the progress of the current resource type. The last counter is kept on the terminal.
+func EndResourceProgress() {
+
+	if display == nil || display.resourceType == "" {
+		return
+	}
+	if display.interactive && display.lineShown {
+		fmt.Println()
+	} else if !display.interactive && display.loggedPercentage < 100 {
+		log.Printf("Progress: %s %d/%d", display.resourceType, display.processed, display.total)
+	}
+	display.resourceType = ""
+	display.lineShown = false
+}
+
+func advanceProgressDisplay(resourceType string, action string) {
+
+	// Deleted resources are not counted, since the total is the number of resources to export or import.
+	if display == nil || display.resourceType != resourceType || action == DELETE {
+		return
+	}
+	display.processed++
+	if display.interactive {
+		drawProgressLine()
+	} else if percentage := display.processed * 100 / display.total; percentage/PROGRESS_LOG_STEP > display.loggedPercentage/PROGRESS_LOG_STEP {
+		display.loggedPercentage = percentage
+		log.Printf("Progress: %s %d/%d (%d%%)", display.resourceType, display.processed, display.total, percentage)
+	}
+	if display.processed >= display.total {
+		EndResourceProgress()
+	}
+}
+
+func drawProgressLine() {
+
+	if display == nil || !display.interactive || display.resourceType == "" {
+		return
+	}
+	fmt.Printf("\r\033[K%s %d/%d", display.resourceType, display.processed, display.total)
+	display.lineShown = true
+}
+
+func clearProgressLine() {
+
+	if display == nil || !display.lineShown {
+		return
+	}
+	fmt.Print("\r\033[K")
+	display.lineShown = false
+}
@@ -0,0 +1,181 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"encoding/json"
+	"log"
+	"net"
+	"os"
+	"time"
+)
+
+// Version of the progress event schema. Incremented only when a backward incompatible change is made.
+const PROGRESS_SCHEMA_VERSION = 1
+
+// Progress event types
+const PROGRESS_RUN_STARTED = "run-started"
+const PROGRESS_RESOURCE_STARTED = "resource-started"
+const PROGRESS_RESOURCE_FINISHED = "resource-finished"
+const PROGRESS_RUN_FINISHED = "run-finished"
+
+// Progress event results
+const PROGRESS_RESULT_SUCCESS = "success"
+const PROGRESS_RESULT_FAILED = "failed"
+const PROGRESS_RESULT_SKIPPED = "skipped"
+
+// Maximum time to wait for the reader of the progress socket, so that a slow reader never stalls the run.
+const progressTimeout = time.Second
+
+// Path to the socket or the Windows named pipe that the progress events are sent to. Set by the --progress-socket flag.
+var PROGRESS_SOCKET string
+
+type ProgressEvent struct {
+	Version      int              `json:"version"`
+	Event        string           `json:"event"`
+	Timestamp    string           `json:"timestamp"`
+	Operation    string           `json:"operation,omitempty"`
+	ResourceType string           `json:"resourceType,omitempty"`
+	ResourceName string           `json:"resourceName,omitempty"`
+	Action       string           `json:"action,omitempty"`
+	Result       string           `json:"result,omitempty"`
+	Summary      *ProgressSummary `json:"summary,omitempty"`
+}
+
+type ProgressSummary struct {
+	TotalRequests        int `json:"totalRequests"`
+	SuccessfulOperations int `json:"successfulOperations"`
+	FailedOperations     int `json:"failedOperations"`
+}
+
+var progressConn net.Conn
+var currentAction string
+
+func StartProgress(operation string) {
+
+	startProgressDisplay()
+
+	// A run over multiple tenants or directories connects once.
+	if PROGRESS_SOCKET == "" || progressConn != nil {
+		return
+	}
+	conn, err := dialProgressSocket(PROGRESS_SOCKET)
+	if err != nil {
+		log.Printf("Unable to connect to the progress socket: %s. Progress events will not be sent. %s", PROGRESS_SOCKET, err)
+		return
+	}
+	progressConn = conn
+	emitProgressEvent(ProgressEvent{Event: PROGRESS_RUN_STARTED, Operation: operation})
+}
+
+func EmitResourceStarted(resourceType string, resourceName string, action string) {
+
+	currentAction = action
+	resetFailedRequest()
+	startJUnitTestCase()
+	emitProgressEvent(ProgressEvent{
+		Event:        PROGRESS_RESOURCE_STARTED,
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Action:       action,
+	})
+}
+
+func EmitResourceFinished(resourceType string, resourceName string, action string, result string) {
+
+	// Failures are reported without the action, so the action of the resource in progress is used.
+	if action == "" && result == PROGRESS_RESULT_FAILED {
+		action = currentAction
+	}
+	advanceProgressDisplay(resourceType, action)
+	recordRestoreResult(resourceType, resourceName, action, result)
+	emitProgressEvent(ProgressEvent{
+		Event:        PROGRESS_RESOURCE_FINISHED,
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Action:       action,
+		Result:       result,
+	})
+}
+
+func FinishProgress(operation string) {
+
+	finishProgressDisplay()
+	if progressConn == nil {
+		return
+	}
+	emitProgressEvent(ProgressEvent{
+		Event:     PROGRESS_RUN_FINISHED,
+		Operation: operation,
+		Summary: &ProgressSummary{
+			TotalRequests:        SummaryData.TotalRequests,
+			SuccessfulOperations: SummaryData.SuccessfulOperations,
+			FailedOperations:     SummaryData.FailedOperations,
+		},
+	})
+	closeProgressSocket()
+}
+
+func emitProgressEvent(event ProgressEvent) {
+
+	if progressConn == nil {
+		return
+	}
+	event.Version = PROGRESS_SCHEMA_VERSION
+	event.Timestamp = time.Now().UTC().Format(time.RFC3339Nano)
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+
+	// Stop sending events if the reader is disconnected or too slow, instead of failing the run.
+	progressConn.SetWriteDeadline(time.Now().Add(progressTimeout))
+	if _, err := progressConn.Write(append(eventBytes, '\n')); err != nil {
+		log.Printf("Unable to send progress events. Progress events will not be sent for the rest of the run. %s", err)
+		closeProgressSocket()
+	}
+}
+
+// Listens on the socket or the Windows named pipe for the progress events of the runs.
+func ListenProgressSocket(socketPath string) (net.Listener, error) {
+
+	if isNamedPipe(socketPath) {
+		return listenPipe(socketPath)
+	}
+
+	// Remove the socket left behind by a previous listener.
+	if fileInfo, err := os.Stat(socketPath); err == nil && fileInfo.Mode()&os.ModeSocket != 0 {
+		os.Remove(socketPath)
+	}
+	return net.Listen("unix", socketPath)
+}
+
+func dialProgressSocket(socketPath string) (net.Conn, error) {
+
+	if isNamedPipe(socketPath) {
+		return dialPipe(socketPath, progressTimeout)
+	}
+	return net.DialTimeout("unix", socketPath, progressTimeout)
+}
+
+func closeProgressSocket() {
+
+	progressConn.Close()
+	progressConn = nil
+}
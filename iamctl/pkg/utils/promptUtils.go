@@ -0,0 +1,127 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Skip the delete confirmation prompt and update the resources during import even if they are unchanged.
+// Set by the --force flag.
+var FORCE_IMPORT bool
+
+// Skip the delete confirmation prompt and the system resource confirmation prompt during import. Set by the --yes flag.
+var ASSUME_YES bool
+
+// Skip deleting resources during import regardless of the ALLOW_DELETE config. Set by the --no-delete flag.
+var NO_DELETE bool
+
+// Reader of the standard input shared by the prompts of a run. A reader buffers more than the answer it reads, so a
+// new reader for each prompt would lose the answers given in advance for the next prompts.
+var stdinReader *bufio.Reader
+var stdinReaderSource *os.File
+
+func ConfirmDeletion(resourceType string, resourceNames []string) bool {
+
+	if len(resourceNames) == 0 {
+		return false
+	}
+
+	fmt.Printf("The following %s will be deleted from the target environment:\n", resourceType)
+	for _, resourceName := range resourceNames {
+		fmt.Printf("  - %s (%s)\n", resourceName, resourceType)
+	}
+
+	if ASSUME_YES || FORCE_IMPORT {
+		log.Println("Skipping the delete confirmation since the --yes or --force flag is set.")
+		return true
+	}
+
+	// Avoid waiting for an answer that cannot be given when the tool is not run in a terminal.
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		log.Printf("Error: Cannot confirm the deletion of %s since the input is not a terminal. "+
+			"Use the --yes or --force flag to delete without confirmation or the --no-delete flag to skip deletion.", resourceType)
+		return false
+	}
+
+	return readConfirmation("Unable to read the confirmation. Skipping deletion of "+resourceType,
+		"Deletion of "+resourceType+" is cancelled.")
+}
+
+// Confirm the import of the system resources, such as the Console application, since an invalid configuration of a
+// system resource can lock the users out of the target environment.
+func ConfirmSystemResourceImport(resourceType string, resourceNames []string) bool {
+
+	if len(resourceNames) == 0 {
+		return false
+	}
+
+	fmt.Printf("The following system %s will be modified in the target environment:\n", resourceType)
+	for _, resourceName := range resourceNames {
+		fmt.Printf("  - %s (%s)\n", resourceName, resourceType)
+	}
+
+	if ASSUME_YES {
+		log.Println("Skipping the system resource confirmation since the --yes flag is set.")
+		return true
+	}
+	if !terminal.IsTerminal(int(os.Stdin.Fd())) {
+		log.Printf("Error: Cannot confirm the import of the system %s since the input is not a terminal. "+
+			"Use the --yes flag to import without confirmation.", resourceType)
+		return false
+	}
+	return readConfirmation("Unable to read the confirmation. Skipping import of the system "+resourceType,
+		"Import of the system "+resourceType+" is cancelled.")
+}
+
+func readConfirmation(readErrorMessage string, cancelMessage string) bool {
+
+	fmt.Print("Are you sure? (yes/no): ")
+	answer, err := GetStdinReader().ReadString('\n')
+	if err != nil && answer == "" {
+		log.Println(readErrorMessage)
+		return false
+	}
+	if IsConfirmed(answer) {
+		return true
+	}
+	log.Println(cancelMessage)
+	return false
+}
+
+// Get the reader of the standard input shared by the prompts. The reader is replaced if the standard input is.
+func GetStdinReader() *bufio.Reader {
+
+	if stdinReader == nil || stdinReaderSource != os.Stdin {
+		stdinReader, stdinReaderSource = bufio.NewReader(os.Stdin), os.Stdin
+	}
+	return stdinReader
+}
+
+func IsConfirmed(answer string) bool {
+
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "yes" || answer == "y"
+}
@@ -0,0 +1,101 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Remove the exported files of the resources deleted on the server after the export. Set by the --prune flag.
+var PRUNE_EXPORT bool
+
+// Handle the exported YAML files of a resource type whose resources no longer exist on the server. The files are
+// removed with the --prune flag and listed in the summary, or listed in a warning without the flag. Should only be
+// called after all resources of the type are exported successfully, so that the files are compared with the
+// complete list of the deployed resources.
+func HandleStaleExportedFiles(resourceType string, dirPath string, nameKey string, deployedNames []string,
+	resourceConfigs map[string]interface{}) {
+
+	staleFilePaths, err := FindStaleExportedFiles(dirPath, nameKey, deployedNames, resourceConfigs)
+	if err != nil {
+		log.Printf("Error when finding the stale files of %s. %s", resourceType, err)
+		return
+	}
+	if len(staleFilePaths) == 0 {
+		return
+	}
+	if !PRUNE_EXPORT {
+		LogWarning(WARNING_STALE_FILE, fmt.Sprintf("The following %s files belong to resources deleted on the "+
+			"server. Use the --prune flag to remove them: %s", resourceType, strings.Join(staleFilePaths, ", ")))
+		return
+	}
+	for _, staleFilePath := range staleFilePaths {
+		if err := os.Remove(staleFilePath); err != nil {
+			log.Println("Error when removing the file: ", staleFilePath, err)
+			continue
+		}
+		log.Println("Pruned the file:", staleFilePath)
+		AddPrunedFileToSummary(resourceType, filepath.Base(staleFilePath))
+	}
+}
+
+// Get the exported YAML files in the given folder whose resources are not deployed, matching the resources by the
+// name in the file instead of the file name. Files of excluded resources and other namespaces, files without the
+// name and other file types are not returned.
+func FindStaleExportedFiles(dirPath string, nameKey string, deployedNames []string,
+	resourceConfigs map[string]interface{}) ([]string, error) {
+
+	files, err := ioutil.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	var staleFilePaths []string
+	for _, file := range files {
+		if file.IsDir() || !IsYamlFile(file.Name()) {
+			continue
+		}
+		filePath := filepath.Join(dirPath, file.Name())
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		var fileYaml map[string]interface{}
+		if err := yaml.Unmarshal(ReplaceTypeTags(content), &fileYaml); err != nil {
+			log.Printf("Skipping the file: %s when finding the stale files. %s", filePath, err)
+			continue
+		}
+		name, _ := fileYaml[nameKey].(string)
+		if name == "" {
+			continue
+		}
+		name = ReplaceKeywords(name, KEYWORD_CONFIGS.KeywordMappings)
+		if Contains(deployedNames, name) || IsResourceExcluded(name, resourceConfigs) {
+			continue
+		}
+		staleFilePaths = append(staleFilePaths, filePath)
+	}
+	return staleFilePaths, nil
+}
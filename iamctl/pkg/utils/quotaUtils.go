@@ -0,0 +1,78 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package utils
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// Usage of the quota of a resource type in the target environment by an import run.
+type ResourceQuota struct {
+	ResourceType string
+	Deployed     int
+	Creates      int
+	Limit        int
+}
+
+func (quota ResourceQuota) Remaining() int {
+
+	if quota.Deployed > quota.Limit {
+		return 0
+	}
+	return quota.Limit - quota.Deployed
+}
+
+func GetQuotaLimit(resourceType string) (int, bool) {
+
+	// The server does not expose the limits of the plan, so the limits are read from the QUOTA config.
+	var configName string
+	switch resourceType {
+	case APPLICATIONS:
+		configName = APPLICATIONS_CONFIG
+	case IDENTITY_PROVIDERS:
+		configName = IDP_CONFIG
+	}
+	limit, ok := TOOL_CONFIGS.Quota[configName]
+	return limit, ok && configName != ""
+}
+
+func CheckResourceQuotas(quotas []ResourceQuota) error {
+
+	var exceededQuotas []string
+	for _, quota := range quotas {
+		log.Printf("%s: creates: %d, remaining quota: %d", quota.ResourceType, quota.Creates, quota.Remaining())
+		if quota.Creates <= quota.Remaining() {
+			continue
+		}
+		message := fmt.Sprintf("%s: %d resources are to be created, but only %d of the limit of %d remain since %d resources are deployed.",
+			quota.ResourceType, quota.Creates, quota.Remaining(), quota.Limit, quota.Deployed)
+		if IsDeleteAllowed() {
+			// Resources deleted during the import can free the quota, so the import may still complete.
+			LogWarning(WARNING_QUOTA, message+" The import can complete only if enough resources are deleted.")
+			continue
+		}
+		exceededQuotas = append(exceededQuotas, message)
+	}
+	if len(exceededQuotas) > 0 {
+		return fmt.Errorf("the import cannot be completed within the resource quota of the target environment. "+
+			"Increase the limits of the plan or reduce the resources to be created.\n  %s", strings.Join(exceededQuotas, "\n  "))
+	}
+	return nil
+}
@@ -26,8 +26,16 @@ import (
 	"strings"
 )
 
+// Export and import the system applications, such as the Console. Set by the --include-system-apps flag.
+var INCLUDE_SYSTEM_APPS bool
+
 func IsResourceExcluded(resourceName string, resourceConfigs map[string]interface{}) bool {
 
+	if !IsInNamespace(resourceName) {
+		log.Println("Excluded resource outside the namespace: " + resourceName)
+		return true
+	}
+
 	// Include only the resources added to INCLUDE_ONLY config. Note: INCLUDE_ONLY config overrides the EXCLUDE config.
 	includeOnlyResources, ok := resourceConfigs[INCLUDE_ONLY_CONFIG].([]interface{})
 	if ok {
@@ -55,6 +63,11 @@ func IsResourceExcluded(resourceName string, resourceConfigs map[string]interfac
 
 func IsResourceTypeExcluded(resourceType string) bool {
 
+	if !IsOrganizationResourceType(resourceType) {
+		log.Println("Skipping resource type not managed in the organization: " + resourceType)
+		return true
+	}
+
 	// Include only the resource types added to INCLUDE_ONLY config. Note: INCLUDE_ONLY config overrides the EXCLUDE config.
 	if len(TOOL_CONFIGS.IncludeOnly) > 0 {
 		for _, resource := range TOOL_CONFIGS.IncludeOnly {
@@ -76,27 +89,113 @@ func IsResourceTypeExcluded(resourceType string) bool {
 	return false
 }
 
+// Resolve the keyword mapping of a resource into a new map. The loaded keyword configs are never modified, so that
+// the mappings of different resources can be resolved concurrently and modified by the callers.
 func ResolveAdvancedKeywordMapping(resourceName string, resourceConfigs map[string]interface{}) map[string]interface{} {
 
-	defaultKeywordMapping := KEYWORD_CONFIGS.KeywordMappings
-
-	// Check if resource specific configs exist for the given resource and if not return the default keyword mappings.
-	if resourceSpecificConfigs, ok := resourceConfigs[resourceName]; ok {
-		// Check if advanced keyword mappings exist for the given resource.
-		if resourceKeywordMap, ok := resourceSpecificConfigs.(map[string]interface{})[KEYWORD_MAPPINGS_CONFIG].(map[string]interface{}); ok {
+	resolvedKeywordMap := make(map[string]interface{}, len(KEYWORD_CONFIGS.KeywordMappings))
+	for key, value := range KEYWORD_CONFIGS.KeywordMappings {
+		resolvedKeywordMap[key] = value
+	}
 
-			mergedKeywordMap := make(map[string]interface{})
-			for key, value := range defaultKeywordMapping {
-				mergedKeywordMap[key] = value.(string)
-			}
-			// Override the default keyword mappings with the resource specific keyword mappings.
+	// Override the default keyword mappings with the resource specific keyword mappings, if they exist.
+	if resourceSpecificConfigs, ok := resourceConfigs[resourceName].(map[string]interface{}); ok {
+		if resourceKeywordMap, ok := resourceSpecificConfigs[KEYWORD_MAPPINGS_CONFIG].(map[string]interface{}); ok {
 			for key, value := range resourceKeywordMap {
-				mergedKeywordMap[key] = value.(string)
+				resolvedKeywordMap[key] = value
 			}
-			return mergedKeywordMap
 		}
 	}
-	return defaultKeywordMapping
+	return resolvedKeywordMap
+}
+
+func IsSystemIdp(idpName string) bool {
+
+	// System IDPs can be overridden with the SYSTEM_IDPS config under identity providers.
+	systemIdps := []string{RESIDENT_IDP_NAME, FILE_BASED_IDP_NAME}
+	if configuredIdps, ok := TOOL_CONFIGS.IdpConfigs[SYSTEM_IDPS_CONFIG].([]interface{}); ok {
+		systemIdps = nil
+		for _, idp := range configuredIdps {
+			if idp, ok := idp.(string); ok {
+				systemIdps = append(systemIdps, idp)
+			}
+		}
+	}
+	for _, systemIdp := range systemIdps {
+		if systemIdp == idpName {
+			return true
+		}
+	}
+	return false
+}
+
+func IsSystemClaim(claimURI string, claimProperties map[string]string) bool {
+
+	// Claims can be marked as system claims with the SYSTEM_CLAIMS config under claims, in addition to the
+	// claims flagged as system claims by the server.
+	if claimProperties[SYSTEM_CLAIM_PROPERTY] == "true" {
+		return true
+	}
+	return MatchesAnyPattern(claimURI, getStringList(TOOL_CONFIGS.ClaimConfigs[SYSTEM_CLAIMS_CONFIG]))
+}
+
+func IsSystemRole(roleName string) bool {
+
+	// Roles created by the server are skipped by default. The system roles can be overridden with the SYSTEM_ROLES
+	// config under roles. Roles of an application audience are matched as <application name>/<role name>.
+	systemRoles := []string{"everyone", "system", "admin", "Internal/*", "Application/*", CONSOLE + "/*", MY_ACCOUNT + "/*"}
+	if configuredRoles, ok := TOOL_CONFIGS.RoleConfigs[SYSTEM_ROLES_CONFIG].([]interface{}); ok {
+		systemRoles = getStringList(configuredRoles)
+	}
+	return MatchesAnyPattern(roleName, systemRoles)
+}
+
+func IsSystemApplication(appName string, isSystemApp bool) bool {
+
+	// Applications marked as system applications by the server, and the Console and My Account applications by default,
+	// are skipped. The names can be overridden with the SYSTEM_APPS config under applications.
+	if isSystemApp {
+		return true
+	}
+	systemApps := []string{CONSOLE, MY_ACCOUNT}
+	if configuredApps, ok := TOOL_CONFIGS.ApplicationConfigs[SYSTEM_APPS_CONFIG].([]interface{}); ok {
+		systemApps = getStringList(configuredApps)
+	}
+	return MatchesAnyPattern(appName, systemApps)
+}
+
+func IsSystemApiResource(identifier string, apiResourceType string) bool {
+
+	// Only the business API resources are managed by default. Other API resources can be marked as system API
+	// resources with the SYSTEM_API_RESOURCES config under API resources.
+	if apiResourceType != "" && !strings.EqualFold(apiResourceType, BUSINESS_API_RESOURCE_TYPE) {
+		return true
+	}
+	return MatchesAnyPattern(identifier, getStringList(TOOL_CONFIGS.ApiResourceConfigs[SYSTEM_API_RESOURCES_CONFIG]))
+}
+
+func IsDefaultOidcScope(scopeName string) bool {
+
+	// Scopes created by the server are protected from deletion, but their claims can be updated.
+	return Contains(defaultOidcScopes, scopeName)
+}
+
+func GetBrandingScreens() []string {
+
+	// The server does not list the screens with custom text, so the screens are taken from the SCREENS config
+	// under branding.
+	if screens := getStringList(TOOL_CONFIGS.BrandingConfigs[SCREENS_CONFIG]); len(screens) > 0 {
+		return screens
+	}
+	return defaultBrandingScreens
+}
+
+func GetBrandingLocales() []string {
+
+	if locales := getStringList(TOOL_CONFIGS.BrandingConfigs[LOCALES_CONFIG]); len(locales) > 0 {
+		return locales
+	}
+	return []string{DEFAULT_BRANDING_LOCALE}
 }
 
 func AreSecretsExcluded(resourceConfigs map[string]interface{}) bool {
@@ -113,23 +212,40 @@ func AreSecretsExcluded(resourceConfigs map[string]interface{}) bool {
 func RemoveDeletedLocalResources(filePath string, deployedResourceNames []string) {
 
 	// Remove local files of resources that do not exist in the remote during export.
-	files, err := ioutil.ReadDir(filePath)
+	staleFilePaths, err := GetStaleLocalFiles(filePath, deployedResourceNames)
 	if err != nil {
 		log.Println("Error loading local files: ", err)
 		return
 	}
+	for _, staleFilePath := range staleFilePaths {
+		fileName := filepath.Base(staleFilePath)
+		err := os.Remove(staleFilePath)
+		if err != nil {
+			log.Println("Error when removing the file: ", fileName, err)
+		} else {
+			log.Println("Removed the file:", fileName)
+		}
+	}
+}
 
+// Get the paths of the local files in the given folder that do not belong to any of the deployed resources.
+func GetStaleLocalFiles(filePath string, deployedResourceNames []string) ([]string, error) {
+
+	files, err := ioutil.ReadDir(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var staleFilePaths []string
 	for _, file := range files {
 		fileName := file.Name()
-		if !Contains(deployedResourceNames, GetFileInfo(fileName).ResourceName) {
-			err := os.Remove(filepath.Join(filePath, fileName))
-			if err != nil {
-				log.Println("Error when removing the file: ", fileName, err)
-			} else {
-				log.Println("Removed the file:", fileName)
-			}
+		// Keep the auth script and client certificate files of the deployed applications.
+		resourceName := ResolveResourceName(filepath.Join(filePath, fileName))
+		// Keep the files of the other namespaces, as their resources are not listed in the run.
+		if !Contains(deployedResourceNames, resourceName) && IsInNamespace(resourceName) {
+			staleFilePaths = append(staleFilePaths, filepath.Join(filePath, fileName))
 		}
 	}
+	return staleFilePaths, nil
 }
 
 func RemoveSecretMasks(modifiedFileData string) string {
@@ -137,3 +253,43 @@ func RemoveSecretMasks(modifiedFileData string) string {
 	modifiedFileData = strings.ReplaceAll(modifiedFileData, SENSITIVE_FIELD_MASK, "null")
 	return modifiedFileData
 }
+
+type ExternallyManagedSignatures struct {
+	NamePatterns []string
+	TemplateIds  []string
+	Creators     []string
+}
+
+func GetExternallyManagedSignatures(resourceConfigs map[string]interface{}) ExternallyManagedSignatures {
+
+	// The signatures configured with the EXTERNALLY_MANAGED config are added to the default signatures.
+	signatures := ExternallyManagedSignatures{
+		NamePatterns: append([]string{}, defaultExternallyManagedAppNames...),
+	}
+	configuredSignatures, ok := resourceConfigs[EXTERNALLY_MANAGED_CONFIG].(map[string]interface{})
+	if !ok {
+		return signatures
+	}
+	signatures.NamePatterns = append(signatures.NamePatterns, getStringList(configuredSignatures[NAME_PATTERNS_CONFIG])...)
+	signatures.TemplateIds = append(signatures.TemplateIds, getStringList(configuredSignatures[TEMPLATE_IDS_CONFIG])...)
+	signatures.Creators = append(signatures.Creators, getStringList(configuredSignatures[CREATORS_CONFIG])...)
+	return signatures
+}
+
+func IsExternallyManagedDeleteAllowed(resourceConfigs map[string]interface{}) bool {
+
+	deleteAllowed, _ := resourceConfigs[DELETE_EXTERNALLY_MANAGED_CONFIG].(bool)
+	return deleteAllowed
+}
+
+func getStringList(value interface{}) []string {
+
+	var list []string
+	values, _ := value.([]interface{})
+	for _, item := range values {
+		if item, ok := item.(string); ok {
+			list = append(list, item)
+		}
+	}
+	return list
+}
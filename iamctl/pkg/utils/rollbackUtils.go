@@ -0,0 +1,102 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"text/tabwriter"
+)
+
+// Statuses of the resources in the restoration summary of a rollback.
+const RESTORE_RESTORED = "RESTORED"
+const RESTORE_UNCHANGED = "UNCHANGED"
+const RESTORE_SKIPPED = "SKIPPED"
+const RESTORE_FAILED = "FAILED"
+
+// Update only the resources that exist in the target environment, without creating or deleting any resource.
+// Set by the rollback command.
+var UPDATE_ONLY bool
+
+type restoreResult struct {
+	resourceType string
+	resourceName string
+	status       string
+}
+
+var restoreResults []restoreResult
+
+// Check that the backup is restored to the server it was taken from. The backups of another server are restored only
+// when the check is skipped with the --force flag.
+func ValidateBackupServer(metadata BackupMetadata) error {
+
+	backupUrl, err := url.Parse(metadata.ServerUrl)
+	if err != nil || backupUrl.Host == "" {
+		return fmt.Errorf("invalid server URL in the backup metadata: %s", metadata.ServerUrl)
+	}
+	serverUrl, err := url.Parse(SERVER_CONFIGS.ServerUrl)
+	if err != nil {
+		return fmt.Errorf("invalid server URL in the server configs: %s", SERVER_CONFIGS.ServerUrl)
+	}
+	if backupUrl.Host != serverUrl.Host {
+		return fmt.Errorf("the backup is taken from the server: %s, and cannot be restored to the server: %s",
+			backupUrl.Host, serverUrl.Host)
+	}
+	return nil
+}
+
+// Skip a resource that does not exist in the target environment, since the resources are not created in the
+// update only mode.
+func SkipMissingResource(resourceType string, resourceName string) {
+
+	log.Printf("%s: %s does not exist in the target environment. Skipping the resource.", resourceType, resourceName)
+	InitializeResourceSummary()
+	EmitResourceFinished(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED)
+	recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_MISSING)
+}
+
+func PrintRestoreSummary() {
+
+	fmt.Println("Restoration summary:")
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(writer, "RESOURCE TYPE\tRESOURCE\tSTATUS")
+	for _, result := range restoreResults {
+		fmt.Fprintf(writer, "%s\t%s\t%s\n", result.resourceType, result.resourceName, result.status)
+	}
+	writer.Flush()
+}
+
+func recordRestoreResult(resourceType string, resourceName string, action string, result string) {
+
+	if !UPDATE_ONLY {
+		return
+	}
+	status := RESTORE_RESTORED
+	switch {
+	case result == PROGRESS_RESULT_FAILED:
+		status = RESTORE_FAILED
+	case result == PROGRESS_RESULT_SKIPPED:
+		status = RESTORE_SKIPPED
+	case action == UNCHANGED:
+		status = RESTORE_UNCHANGED
+	}
+	restoreResults = append(restoreResults, restoreResult{resourceType, resourceName, status})
+}
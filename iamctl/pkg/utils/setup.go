@@ -20,15 +20,18 @@ package utils
 
 import (
 	"bytes"
-	"crypto/tls"
+	"context"
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type oAuthResponse struct {
@@ -40,64 +43,293 @@ type oAuthResponse struct {
 }
 
 type ServerConfigs struct {
-	ServerUrl    string `json:"SERVER_URL"`
-	ClientId     string `json:"CLIENT_ID"`
-	ClientSecret string `json:"CLIENT_SECRET"`
-	TenantDomain string `json:"TENANT_DOMAIN"`
-	Token        string `json:"TOKEN"`
+	ServerUrl       string   `json:"SERVER_URL"`
+	ClientId        string   `json:"CLIENT_ID"`
+	ClientSecret    string   `json:"CLIENT_SECRET"`
+	TenantDomain    string   `json:"TENANT_DOMAIN"`
+	OrganizationId  string   `json:"ORGANIZATION_ID"`
+	ClientCertFile  string   `json:"CLIENT_CERT_FILE"`
+	ClientKeyFile   string   `json:"CLIENT_KEY_FILE"`
+	Token           string   `json:"TOKEN"`
+	SensitiveFields []string `json:"SENSITIVE_FIELDS"`
+	Tenants         []string `json:"TENANTS"`
+	RequestTimeout  int      `json:"REQUEST_TIMEOUT"`
 }
 
 type ToolConfigs struct {
-	AllowDelete        bool                   `json:"ALLOW_DELETE"`
-	Exclude            []string               `json:"EXCLUDE"`
-	IncludeOnly        []string               `json:"INCLUDE_ONLY"`
-	ExcludeSecrets     bool                   `json:"EXCLUDE_SECRETS"`
-	ApplicationConfigs map[string]interface{} `json:"APPLICATIONS"`
-	IdpConfigs         map[string]interface{} `json:"IDENTITY_PROVIDERS"`
-	ClaimConfigs       map[string]interface{} `json:"CLAIMS"`
-	UserStoreConfigs   map[string]interface{} `json:"USERSTORES"`
+	AllowDelete                bool                   `json:"ALLOW_DELETE"`
+	Exclude                    []string               `json:"EXCLUDE"`
+	IncludeOnly                []string               `json:"INCLUDE_ONLY"`
+	ExcludeSecrets             bool                   `json:"EXCLUDE_SECRETS"`
+	Priority                   []string               `json:"PRIORITY"`
+	Quota                      map[string]int         `json:"QUOTA"`
+	BackupDir                  string                 `json:"BACKUP_DIR"`
+	ApplicationConfigs         map[string]interface{} `json:"APPLICATIONS"`
+	IdpConfigs                 map[string]interface{} `json:"IDENTITY_PROVIDERS"`
+	ClaimConfigs               map[string]interface{} `json:"CLAIMS"`
+	UserStoreConfigs           map[string]interface{} `json:"USERSTORES"`
+	EmailTemplateConfigs       map[string]interface{} `json:"EMAIL_TEMPLATES"`
+	UserConfigs                map[string]interface{} `json:"USERS"`
+	XacmlPolicyConfigs         map[string]interface{} `json:"XACML_POLICIES"`
+	RoleConfigs                map[string]interface{} `json:"ROLES"`
+	ApiResourceConfigs         map[string]interface{} `json:"API_RESOURCES"`
+	GovernanceConnectorConfigs map[string]interface{} `json:"GOVERNANCE_CONNECTORS"`
+	BrandingConfigs            map[string]interface{} `json:"BRANDING"`
+	OidcScopeConfigs           map[string]interface{} `json:"OIDC_SCOPES"`
+	NotificationSenderConfigs  map[string]interface{} `json:"NOTIFICATION_SENDERS"`
+	WorkflowConfigs            map[string]interface{} `json:"WORKFLOWS"`
+	CorsConfigs                map[string]interface{} `json:"CORS"`
+	OrganizationConfigs        map[string]interface{} `json:"ORGANIZATIONS"`
 }
 
 type KeywordConfigs struct {
-	KeywordMappings    map[string]interface{} `json:"KEYWORD_MAPPINGS"`
-	ApplicationConfigs map[string]interface{} `json:"APPLICATIONS"`
-	IdpConfigs         map[string]interface{} `json:"IDENTITY_PROVIDERS"`
-	ClaimConfigs       map[string]interface{} `json:"CLAIMS"`
-	UserStoreConfigs   map[string]interface{} `json:"USERSTORES"`
+	KeywordMappings            map[string]interface{} `json:"KEYWORD_MAPPINGS"`
+	ApplicationConfigs         map[string]interface{} `json:"APPLICATIONS"`
+	IdpConfigs                 map[string]interface{} `json:"IDENTITY_PROVIDERS"`
+	ClaimConfigs               map[string]interface{} `json:"CLAIMS"`
+	UserStoreConfigs           map[string]interface{} `json:"USERSTORES"`
+	EmailTemplateConfigs       map[string]interface{} `json:"EMAIL_TEMPLATES"`
+	UserConfigs                map[string]interface{} `json:"USERS"`
+	XacmlPolicyConfigs         map[string]interface{} `json:"XACML_POLICIES"`
+	RoleConfigs                map[string]interface{} `json:"ROLES"`
+	ApiResourceConfigs         map[string]interface{} `json:"API_RESOURCES"`
+	GovernanceConnectorConfigs map[string]interface{} `json:"GOVERNANCE_CONNECTORS"`
+	BrandingConfigs            map[string]interface{} `json:"BRANDING"`
+	OidcScopeConfigs           map[string]interface{} `json:"OIDC_SCOPES"`
+	NotificationSenderConfigs  map[string]interface{} `json:"NOTIFICATION_SENDERS"`
+	WorkflowConfigs            map[string]interface{} `json:"WORKFLOWS"`
+	CorsConfigs                map[string]interface{} `json:"CORS"`
+	OrganizationConfigs        map[string]interface{} `json:"ORGANIZATIONS"`
+	TenantConfigs              map[string]interface{} `json:"TENANTS"`
+	OrganizationIds            map[string]interface{} `json:"ORGANIZATION_IDS"`
 }
 
 var SERVER_CONFIGS ServerConfigs
+
+// Configs of a target environment used by the API calls, to switch between multiple target environments in the same
+// process, such as the clients of the client package.
+type RunConfigs struct {
+	ServerConfigs  ServerConfigs
+	ToolConfigs    ToolConfigs
+	KeywordConfigs KeywordConfigs
+	httpClient     *http.Client
+}
+
+// Environment variables that override the server configs. The server configs are resolved in the following order,
+// with the later sources taking precedence:
+//  1. The serverConfig.json file of the config folder, or the SERVER_URL, CLIENT_ID, etc. environment variables
+//     when the --config flag is not used.
+//  2. The environment section of the serverConfig.json file selected with the --env flag.
+//  3. The IAMCTL_ prefixed environment variables below.
+const (
+	IAMCTL_SERVER_URL       = "IAMCTL_SERVER_URL"
+	IAMCTL_CLIENT_ID        = "IAMCTL_CLIENT_ID"
+	IAMCTL_CLIENT_SECRET    = "IAMCTL_CLIENT_SECRET"
+	IAMCTL_TENANT_DOMAIN    = "IAMCTL_TENANT_DOMAIN"
+	IAMCTL_ORGANIZATION_ID  = "IAMCTL_ORGANIZATION_ID"
+	IAMCTL_CLIENT_CERT_FILE = "IAMCTL_CLIENT_CERT_FILE"
+	IAMCTL_CLIENT_KEY_FILE  = "IAMCTL_CLIENT_KEY_FILE"
+)
+
+// Precedence of the config sources, highest first, as explained by the explain command.
+var CONFIG_PRECEDENCE = []string{
+	"Command flags, such as --no-delete and --include-only, over the equivalent tool configs.",
+	"IAMCTL_ prefixed environment variables, such as IAMCTL_CLIENT_SECRET, over the server configs.",
+	"Profile selected with the --config-profile flag, over the server config file of the --config folder.",
+	"Environment section selected with the --env flag, or the IAMCTL_ENV environment variable if the flag is not set.",
+	"The default section of the config files, for the configs not defined in the selected environment section.",
+	"Config files of the --config folder when no environment section is selected, or the default profile of the " +
+		"profiles file when the --config flag is not set.",
+	"SERVER_URL, CLIENT_ID, etc. environment variables when neither the --config flag nor a profile is set.",
+}
+
 var TOOL_CONFIGS ToolConfigs
+
+// Keyword configs are not modified after they are loaded, since the keyword mappings of resources can be resolved
+// concurrently. Use OverrideKeywordMappings to replace the default keyword mappings. The watch mode of the export
+// reloads them only while no poll holds them with HoldKeywordConfigs.
 var KEYWORD_CONFIGS KeywordConfigs
 
+// Path of the loaded keyword config file, to reload the keyword configs when the file changes.
+var keywordConfigFilePath string
+
+// Name of the environment section to be selected from the config files. Set by the --env flag.
+var ENVIRONMENT string
+
+// Print the resolved configs before running the command. Set by the --show-config flag.
+var SHOW_CONFIG bool
+
 func LoadConfigs(envConfigPath string) (baseDir string) {
 
+	if ENVIRONMENT == "" {
+		ENVIRONMENT = os.Getenv(IAMCTL_ENV_CONFIG)
+	}
+	if ENVIRONMENT != "" {
+		log.Println("Selected environment: " + ENVIRONMENT)
+	}
+
 	baseDir, toolConfigFile, keywordConfigPath := loadServerConfigs(envConfigPath)
 	TOOL_CONFIGS = loadToolConfigsFromFile(toolConfigFile)
 	KEYWORD_CONFIGS = loadKeywordConfigsFromFile(keywordConfigPath)
+
+	if SHOW_CONFIG {
+		printEffectiveConfigs()
+	}
+
+	var err error
+	apiHttpClient, err = NewHttpClient(SERVER_CONFIGS)
+	if err != nil {
+		log.Fatalln("Error when configuring the HTTP client.", err)
+	}
+
+	if PREFLIGHT_TIMEOUT > 0 {
+		if err := RunPreflightCheck(SERVER_CONFIGS, PREFLIGHT_TIMEOUT); err != nil {
+			log.Fatalln("Pre-flight check failed.", err)
+		}
+		log.Println("Pre-flight check completed successfully.")
+	}
+
+	// Get access token.
+	SERVER_CONFIGS.Token = getAccessToken(SERVER_CONFIGS)
+	log.Println("Access Token recieved succesfully.")
+	if ORGANIZATION != "" {
+		if err := switchOrganization(); err != nil {
+			log.Fatalln("Error when switching to the organization: "+ORGANIZATION+".", err)
+		}
+	}
 	return baseDir
 }
 
+// Create the configs of a target environment, with an HTTP client configured for the server. The access token is
+// not requested, so that it can be requested with the configs selected by SetRunConfigs. The HTTP client sends the
+// requests with the given transport, if not nil, and applies the given middlewares inside the global middlewares.
+func NewRunConfigs(serverConfigs ServerConfigs, toolConfigs ToolConfigs, keywordConfigs KeywordConfigs,
+	transport http.RoundTripper, middlewares ...HttpMiddleware) (RunConfigs, error) {
+
+	if serverConfigs.ServerUrl == "" {
+		return RunConfigs{}, fmt.Errorf("server URL is not defined")
+	}
+	serverConfigs.ServerUrl = strings.TrimSuffix(serverConfigs.ServerUrl, "/")
+	if serverConfigs.TenantDomain == "" {
+		serverConfigs.TenantDomain = DEFAULT_TENANT_DOMAIN
+	}
+	httpClient, err := newHttpClient(serverConfigs, transport, middlewares)
+	if err != nil {
+		return RunConfigs{}, fmt.Errorf("error when configuring the HTTP client: %s", err)
+	}
+	return RunConfigs{ServerConfigs: serverConfigs, ToolConfigs: toolConfigs, KeywordConfigs: keywordConfigs,
+		httpClient: httpClient}, nil
+}
+
+// Get the configs of the target environment loaded by LoadConfigs or selected by SetRunConfigs.
+func GetRunConfigs() RunConfigs {
+
+	return RunConfigs{ServerConfigs: SERVER_CONFIGS, ToolConfigs: TOOL_CONFIGS, KeywordConfigs: KEYWORD_CONFIGS,
+		httpClient: GetHttpClient()}
+}
+
+// Select the configs of the target environment for the API calls sent after the call.
+func SetRunConfigs(configs RunConfigs) {
+
+	SERVER_CONFIGS, TOOL_CONFIGS, KEYWORD_CONFIGS = configs.ServerConfigs, configs.ToolConfigs, configs.KeywordConfigs
+	apiHttpClient = configs.httpClient
+}
+
+// Request an access token with the client credentials of the server configs.
+func RequestAccessToken(ctx context.Context, serverConfigs ServerConfigs) (string, error) {
+
+	return requestAccessToken(ctx, serverConfigs)
+}
+
+// Override the default keyword mappings with the given keywords. A new map is created instead of modifying the
+// loaded map, so that the keyword mappings resolved before the override are not changed.
+func OverrideKeywordMappings(keywords map[string]string) {
+
+	keywordMappings := make(map[string]interface{}, len(KEYWORD_CONFIGS.KeywordMappings)+len(keywords))
+	for keyword, value := range KEYWORD_CONFIGS.KeywordMappings {
+		keywordMappings[keyword] = value
+	}
+	for keyword, value := range keywords {
+		keywordMappings[keyword] = value
+	}
+	KEYWORD_CONFIGS.KeywordMappings = keywordMappings
+}
+
+// Wait until the health check API of the server responds successfully, so that a new environment can be
+// configured as soon as the server is started.
+func WaitForServer(envConfigPath string, timeout time.Duration) error {
+
+	loadServerConfigs(envConfigPath)
+	client, err := NewHttpClient(SERVER_CONFIGS)
+	if err != nil {
+		return fmt.Errorf("error when configuring the HTTP client: %s", err)
+	}
+	healthCheckUrl := SERVER_CONFIGS.ServerUrl + HEALTH_CHECK_PATH
+	deadline := time.Now().Add(timeout)
+	for {
+		resp, err := client.Get(healthCheckUrl)
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				return nil
+			}
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("server is not available at %s after %s", SERVER_CONFIGS.ServerUrl, timeout)
+		}
+		log.Println("Waiting for the server to be available: " + SERVER_CONFIGS.ServerUrl)
+		time.Sleep(SERVER_WAIT_INTERVAL)
+	}
+}
+
+// Load only the keyword configs of the given config folder, without connecting to the server.
+func LoadKeywordConfigs(envConfigPath string) {
+
+	if ENVIRONMENT == "" {
+		ENVIRONMENT = os.Getenv(IAMCTL_ENV_CONFIG)
+	}
+	KEYWORD_CONFIGS = loadKeywordConfigsFromFile(filepath.Join(envConfigPath, KEYWORD_CONFIG_FILE))
+}
+
 func loadServerConfigs(envConfigPath string) (baseDir string, toolConfigPath string, keywordConfigPath string) {
 
-	if envConfigPath == "" {
+	// The default profile is only used when the config folder is not given, so that the server config file of the
+	// config folder is not overridden without the --config-profile flag.
+	profile, err := GetActiveConfigProfile(envConfigPath == "")
+	if err != nil {
+		log.Fatalln("Error when loading the config profile.", err)
+	}
+	if envConfigPath == "" && profile != nil {
+		log.Println("Loading configs from the config profile: " + profile.Name)
+		SERVER_CONFIGS = profile.ServerConfigs
+		toolConfigPath, keywordConfigPath = profile.ToolConfigPath, profile.KeywordConfigPath
+		if toolConfigPath == "" {
+			toolConfigPath = os.Getenv(TOOL_CONFIG_PATH)
+		}
+		if keywordConfigPath == "" {
+			keywordConfigPath = os.Getenv(KEYWORD_CONFIG_PATH)
+		}
+		baseDir = filepath.Dir(filepath.Dir(filepath.Dir(toolConfigPath)))
+	} else if envConfigPath == "" {
 		log.Println("Loading configs from environment variables.")
 		toolConfigPath, keywordConfigPath = loadConfigsFromEnvVar()
 		baseDir = filepath.Dir(filepath.Dir(filepath.Dir(toolConfigPath)))
 	} else {
 		log.Println("Loading configs from config files.")
-		baseDir = filepath.Dir(filepath.Dir(envConfigPath))
+		baseDir = GetBaseDir(envConfigPath)
 		serverConfigFile := filepath.Join(envConfigPath, SERVER_CONFIG_FILE)
 		toolConfigPath = filepath.Join(envConfigPath, TOOL_CONFIG_FILE)
 		keywordConfigPath = filepath.Join(envConfigPath, KEYWORD_CONFIG_FILE)
 
-		SERVER_CONFIGS = loadServerConfigsFromFile(serverConfigFile)
+		if profile != nil {
+			log.Println("Loading server configs from the config profile: " + profile.Name)
+			SERVER_CONFIGS = profile.ServerConfigs
+		} else {
+			SERVER_CONFIGS = loadServerConfigsFromFile(serverConfigFile)
+		}
 	}
+	overrideServerConfigsFromEnvVar()
 	sanitizeServerConfigs()
-
-	// Get access token.
-	SERVER_CONFIGS.Token = getAccessToken(SERVER_CONFIGS)
-	log.Println("Access Token recieved succesfully.")
 	return baseDir, toolConfigPath, keywordConfigPath
 }
 
@@ -108,6 +340,16 @@ func loadConfigsFromEnvVar() (toolConfigPath string, keywordConfigPath string) {
 	SERVER_CONFIGS.ClientId = os.Getenv(CLIENT_ID_CONFIG)
 	SERVER_CONFIGS.ClientSecret = os.Getenv(CLIENT_SECRET_CONFIG)
 	SERVER_CONFIGS.TenantDomain = os.Getenv(TENANT_DOMAIN_CONFIG)
+	SERVER_CONFIGS.OrganizationId = os.Getenv(ORGANIZATION_ID_CONFIG)
+	SERVER_CONFIGS.ClientCertFile = os.Getenv(CLIENT_CERT_FILE_CONFIG)
+	SERVER_CONFIGS.ClientKeyFile = os.Getenv(CLIENT_KEY_FILE_CONFIG)
+	if requestTimeout := os.Getenv(REQUEST_TIMEOUT_CONFIG); requestTimeout != "" {
+		timeout, err := strconv.Atoi(requestTimeout)
+		if err != nil {
+			log.Fatalln("Invalid value for " + REQUEST_TIMEOUT_CONFIG + ": " + requestTimeout + ". Expected a number of seconds.")
+		}
+		SERVER_CONFIGS.RequestTimeout = timeout
+	}
 
 	// Load tool config file path from environment variables.
 	toolConfigPath = os.Getenv(TOOL_CONFIG_PATH)
@@ -115,6 +357,28 @@ func loadConfigsFromEnvVar() (toolConfigPath string, keywordConfigPath string) {
 	return toolConfigPath, keywordConfigPath
 }
 
+func overrideServerConfigsFromEnvVar() {
+
+	overrides := []struct {
+		envVar string
+		config *string
+	}{
+		{IAMCTL_SERVER_URL, &SERVER_CONFIGS.ServerUrl},
+		{IAMCTL_CLIENT_ID, &SERVER_CONFIGS.ClientId},
+		{IAMCTL_CLIENT_SECRET, &SERVER_CONFIGS.ClientSecret},
+		{IAMCTL_TENANT_DOMAIN, &SERVER_CONFIGS.TenantDomain},
+		{IAMCTL_ORGANIZATION_ID, &SERVER_CONFIGS.OrganizationId},
+		{IAMCTL_CLIENT_CERT_FILE, &SERVER_CONFIGS.ClientCertFile},
+		{IAMCTL_CLIENT_KEY_FILE, &SERVER_CONFIGS.ClientKeyFile},
+	}
+	for _, override := range overrides {
+		if value, ok := os.LookupEnv(override.envVar); ok && value != "" {
+			log.Println("Server config overridden by the environment variable: " + override.envVar)
+			*override.config = value
+		}
+	}
+}
+
 func loadServerConfigsFromFile(configFilePath string) (serverConfigs ServerConfigs) {
 
 	configFile, err := ioutil.ReadFile(configFilePath)
@@ -125,6 +389,22 @@ func loadServerConfigsFromFile(configFilePath string) (serverConfigs ServerConfi
 	// Replace placeholder keys with environment variable values
 	configFile = ReplacePlaceholders(configFile)
 
+	if ENCRYPTED_CONFIG {
+		passphrase, err := GetConfigPassphrase()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		configFile, err = DecryptServerConfigs(configFile, passphrase)
+		if err != nil {
+			log.Fatalln("Error when decrypting the server config file "+configFilePath+".", err)
+		}
+	}
+
+	configFile, err = ResolveEnvironmentConfigs(configFile, ENVIRONMENT)
+	if err != nil {
+		log.Fatalln("Error when loading the server config file "+configFilePath+".", err)
+	}
+
 	reader := bytes.NewReader(configFile)
 	jsonParser := json.NewDecoder(reader)
 	err = jsonParser.Decode(&serverConfigs)
@@ -158,77 +438,105 @@ func loadToolConfigsFromFile(configFilePath string) (toolConfigs ToolConfigs) {
 
 func loadKeywordConfigsFromFile(configFilePath string) (keywordConfigs KeywordConfigs) {
 
+	keywordConfigs, err := readKeywordConfigsFile(configFilePath)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	keywordConfigFilePath = configFilePath
+	log.Println("Keyword configs loaded successfully from the config file.")
+	return keywordConfigs
+}
+
+// Read the keyword configs of the file without exiting on failure, so that the keyword configs can be reloaded
+// while the tool is running.
+func readKeywordConfigsFile(configFilePath string) (keywordConfigs KeywordConfigs, err error) {
+
 	configFile, err := ioutil.ReadFile(configFilePath)
 	if err != nil {
-		log.Fatalln("Error when reading the keyword config file.", err.Error())
+		return keywordConfigs, fmt.Errorf("error when reading the keyword config file. %s", err)
 	}
 
 	if len(configFile) == 0 {
-		return keywordConfigs
+		return keywordConfigs, nil
 	}
 
 	// Replace placeholder keys with environment variable values
 	configFile = ReplacePlaceholders(configFile)
 
-	err = json.Unmarshal(configFile, &keywordConfigs)
+	configFile, err = ResolveEnvironmentConfigs(configFile, ENVIRONMENT)
 	if err != nil {
-		log.Fatalln("Keyword configs are not in the correct format. Please check the config file.", err)
+		return keywordConfigs, fmt.Errorf("error when loading the keyword config file %s. %s", configFilePath, err)
 	}
 
-	log.Println("Keyword configs loaded successfully from the config file.")
-	return keywordConfigs
+	configFile, err = DecryptKeywordConfigs(configFile, IAMCTL_KEYWORD_KEY)
+	if err != nil {
+		return keywordConfigs, fmt.Errorf("error when loading the keyword config file %s. %s", configFilePath, err)
+	}
+
+	err = json.Unmarshal(configFile, &keywordConfigs)
+	if err != nil {
+		return keywordConfigs, fmt.Errorf("keyword configs are not in the correct format. Please check the config file. %s", err)
+	}
+	return keywordConfigs, nil
 }
 
 func getAccessToken(config ServerConfigs) string {
 
-	var err error
-	var response oAuthResponse
-
 	if config.ServerUrl == "" {
 		log.Fatalln("Server URL is not defined in the config file.")
 	}
-	authUrl := config.ServerUrl + "/t/" + config.TenantDomain + "/oauth2/token"
+	accessToken, err := requestAccessToken(GetRequestContext(), config)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	return accessToken
+}
+
+func requestAccessToken(ctx context.Context, config ServerConfigs) (string, error) {
 
 	body := url.Values{}
 	body.Set("grant_type", "client_credentials")
 	body.Set("scope", SCOPE)
+	if ORGANIZATION != "" {
+		body.Set("scope", SCOPE+" "+ORGANIZATION_VIEW_SCOPE)
+	}
+	return sendTokenRequest(ctx, config, body)
+}
+
+func sendTokenRequest(ctx context.Context, config ServerConfigs, body url.Values) (string, error) {
 
-	req, err := http.NewRequest("POST", authUrl, strings.NewReader(body.Encode()))
+	var response oAuthResponse
+	authUrl := config.ServerUrl + "/t/" + config.TenantDomain + "/oauth2/token"
+
+	req, err := http.NewRequestWithContext(ctx, "POST", authUrl, strings.NewReader(body.Encode()))
 	if err != nil {
-		log.Fatalln(err)
+		return "", err
 	}
 	req.SetBasicAuth(config.ClientId, config.ClientSecret)
 	req.Header.Set("Content-Type", MEDIA_TYPE_FORM)
 	defer req.Body.Close()
 
-	httpClient := &http.Client{
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
-		},
-	}
+	httpClient := GetHttpClient()
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		log.Fatalln(err)
+		return "", err
 	}
 	defer resp.Body.Close()
 
 	respBody, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		log.Fatalln(err)
+		return "", err
 	}
 
 	if resp.StatusCode != 200 {
-		log.Fatalln("Error in getting access token, response: " + string(respBody))
+		return "", fmt.Errorf("error in getting access token, response: %s", respBody)
 	}
 
-	err2 := json.Unmarshal(respBody, &response)
-	if err2 != nil {
-		log.Fatalln(err2)
+	err = json.Unmarshal(respBody, &response)
+	if err != nil {
+		return "", err
 	}
-
-	return response.AccessToken
+	return response.AccessToken, nil
 }
 
 func sanitizeServerConfigs() {
@@ -240,4 +548,99 @@ func sanitizeServerConfigs() {
 		log.Println("Tenant domain not defined. Defaulting to: carbon.super")
 		SERVER_CONFIGS.TenantDomain = DEFAULT_TENANT_DOMAIN
 	}
+	if SERVER_CONFIGS.RequestTimeout < 0 {
+		log.Fatalln(REQUEST_TIMEOUT_CONFIG + " should be a positive number of seconds.")
+	}
+}
+
+func ResolveEnvironmentConfigs(configFile []byte, environment string) ([]byte, error) {
+
+	// Config files without a selected environment are used as they are.
+	if environment == "" {
+		return configFile, nil
+	}
+
+	var environmentConfigs map[string]interface{}
+	err := json.Unmarshal(configFile, &environmentConfigs)
+	if err != nil {
+		return nil, fmt.Errorf("configs are not in the correct format. %w", err)
+	}
+
+	selectedConfigs, ok := environmentConfigs[environment].(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("environment: %s is not defined in the config file", environment)
+	}
+
+	// Values of the selected environment override the values in the default section.
+	effectiveConfigs := make(map[string]interface{})
+	if defaultConfigs, ok := environmentConfigs[DEFAULT_ENV_SECTION].(map[string]interface{}); ok {
+		effectiveConfigs = mergeConfigs(effectiveConfigs, defaultConfigs)
+	}
+	effectiveConfigs = mergeConfigs(effectiveConfigs, selectedConfigs)
+
+	return json.Marshal(effectiveConfigs)
+}
+
+func mergeConfigs(baseConfigs map[string]interface{}, overridingConfigs map[string]interface{}) map[string]interface{} {
+
+	for key, value := range overridingConfigs {
+		baseValue, baseIsMap := baseConfigs[key].(map[string]interface{})
+		overridingValue, overridingIsMap := value.(map[string]interface{})
+		if baseIsMap && overridingIsMap {
+			baseConfigs[key] = mergeConfigs(baseValue, overridingValue)
+		} else if overridingIsMap {
+			baseConfigs[key] = mergeConfigs(make(map[string]interface{}), overridingValue)
+		} else {
+			baseConfigs[key] = value
+		}
+	}
+	return baseConfigs
+}
+
+func printEffectiveConfigs() {
+
+	serverConfigs := SERVER_CONFIGS
+	if serverConfigs.ClientSecret != "" {
+		serverConfigs.ClientSecret = strings.ReplaceAll(SENSITIVE_FIELD_MASK, "'", "")
+	}
+	serverConfigs.Token = ""
+
+	// Keyword values often hold the secrets of the target environment, so only the keyword names are printed.
+	var keywordConfigs interface{}
+	keywordJson, err := json.Marshal(KEYWORD_CONFIGS)
+	if err == nil {
+		err = json.Unmarshal(keywordJson, &keywordConfigs)
+	}
+	if err != nil {
+		log.Println("Error when printing the effective configs.", err)
+		return
+	}
+	effectiveConfigs := map[string]interface{}{
+		"SERVER_CONFIGS":  serverConfigs,
+		"TOOL_CONFIGS":    TOOL_CONFIGS,
+		"KEYWORD_CONFIGS": maskKeywordValues(keywordConfigs),
+	}
+	configJson, err := json.MarshalIndent(effectiveConfigs, "", "  ")
+	if err != nil {
+		log.Println("Error when printing the effective configs.", err)
+		return
+	}
+	fmt.Println("Effective configs:")
+	fmt.Println(redactSensitiveValues(string(configJson)))
+}
+
+// Replace the values of the keyword configs with the mask, keeping the keyword names and the resources they apply to.
+func maskKeywordValues(keywordConfigs interface{}) interface{} {
+
+	switch configs := keywordConfigs.(type) {
+	case map[string]interface{}:
+		for key, value := range configs {
+			configs[key] = maskKeywordValues(value)
+		}
+		return configs
+	case nil:
+		return nil
+	default:
+		return strings.ReplaceAll(SENSITIVE_FIELD_MASK, "'", "")
+	}
 }
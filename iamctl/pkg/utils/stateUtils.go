@@ -0,0 +1,158 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+const IMPORT_STATE_DIR = ".iamctl-state"
+const IMPORT_STATE_FILE = "import-state.json"
+
+// Version of the import state file format. Increment when the format changes in an incompatible way.
+const IMPORT_STATE_VERSION = 1
+
+// ImportState holds the hashes of the last successfully imported content of each resource,
+// keyed by the target environment, the resource type and the resource name.
+// Adopted holds the names of the resources brought under the management of the tool with the adopt command, keyed by
+// the target environment and the resource type.
+type ImportState struct {
+	Version      int                                     `json:"version"`
+	Environments map[string]map[string]map[string]string `json:"environments"`
+	Adopted      map[string]map[string][]string          `json:"adopted,omitempty"`
+}
+
+var importState ImportState
+var importStateFilePath string
+
+func LoadImportState(inputDirPath string) {
+
+	importStateFilePath = filepath.Join(inputDirPath, IMPORT_STATE_DIR, IMPORT_STATE_FILE)
+	importState = ImportState{
+		Version:      IMPORT_STATE_VERSION,
+		Environments: make(map[string]map[string]map[string]string),
+		Adopted:      make(map[string]map[string][]string),
+	}
+
+	content, err := ioutil.ReadFile(importStateFilePath)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			LogWarning(WARNING_IMPORT_STATE, fmt.Sprintf("Unable to read the import state file. All resources will be imported. %s", err))
+		}
+		return
+	}
+	var savedState ImportState
+	if err := json.Unmarshal(content, &savedState); err != nil {
+		LogWarning(WARNING_IMPORT_STATE, fmt.Sprintf("Import state file is not in the correct format. All resources will be imported. %s", err))
+		return
+	}
+	if savedState.Version != IMPORT_STATE_VERSION {
+		LogWarning(WARNING_IMPORT_STATE, fmt.Sprintf("Import state file version %d is not supported. All resources will be imported.", savedState.Version))
+		return
+	}
+	if savedState.Environments != nil {
+		importState.Environments = savedState.Environments
+	}
+	if savedState.Adopted != nil {
+		importState.Adopted = savedState.Adopted
+	}
+}
+
+func IsImportStateUnchanged(resourceType string, resourceName string, content string) bool {
+
+	if FORCE_IMPORT {
+		return false
+	}
+	resourceHashes := importState.Environments[getStateEnvironmentKey()][resourceType]
+	savedHash, ok := resourceHashes[resourceName]
+	return ok && savedHash == getContentHash(content)
+}
+
+func UpdateImportState(resourceType string, resourceName string, content string) {
+
+	if importState.Environments == nil {
+		return
+	}
+	environmentKey := getStateEnvironmentKey()
+	if importState.Environments[environmentKey] == nil {
+		importState.Environments[environmentKey] = make(map[string]map[string]string)
+	}
+	if importState.Environments[environmentKey][resourceType] == nil {
+		importState.Environments[environmentKey][resourceType] = make(map[string]string)
+	}
+	importState.Environments[environmentKey][resourceType][resourceName] = getContentHash(content)
+}
+
+// Mark a resource of the target environment as managed by the tool, so that it is not treated as a resource managed
+// by another product once it is removed from the local directory.
+func MarkResourceAdopted(resourceType string, resourceName string) {
+
+	if importState.Adopted == nil || IsResourceAdopted(resourceType, resourceName) {
+		return
+	}
+	environmentKey := getStateEnvironmentKey()
+	if importState.Adopted[environmentKey] == nil {
+		importState.Adopted[environmentKey] = make(map[string][]string)
+	}
+	adoptedNames := append(importState.Adopted[environmentKey][resourceType], resourceName)
+	sort.Strings(adoptedNames)
+	importState.Adopted[environmentKey][resourceType] = adoptedNames
+}
+
+func IsResourceAdopted(resourceType string, resourceName string) bool {
+
+	return Contains(importState.Adopted[getStateEnvironmentKey()][resourceType], resourceName)
+}
+
+func SaveImportState() error {
+
+	if importStateFilePath == "" {
+		return nil
+	}
+	content, err := json.MarshalIndent(importState, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error when creating the import state content: %s", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(importStateFilePath), 0700); err != nil {
+		return fmt.Errorf("error when creating the import state directory: %s", err)
+	}
+	if err := ioutil.WriteFile(importStateFilePath, content, 0644); err != nil {
+		return fmt.Errorf("error when writing the import state file: %s", err)
+	}
+	return nil
+}
+
+func getStateEnvironmentKey() string {
+
+	// Import state is maintained separately for each target environment.
+	return GetTenantUrl()
+}
+
+func getContentHash(content string) string {
+
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
@@ -20,6 +20,7 @@ package utils
 
 import (
 	"fmt"
+	"strings"
 )
 
 type Summary struct {
@@ -33,10 +34,16 @@ type ResourceSummary struct {
 	SuccessfulExport            int
 	SuccessfulImport            int
 	SuccessfulUpdate            int
+	Unchanged                   int
 	Failed                      int
 	Deleted                     int
 	SecretGeneratedApplications []string
 	FailedResources             []string
+	FilteredResources           []string
+	UnprocessedResources        []string
+	ExternallyManagedResources  []string
+	PrunedFiles                 []string
+	ConflictedResources         []string
 }
 
 var (
@@ -47,6 +54,7 @@ var (
 func PrintSummary(Operation string) {
 
 	InitializeResourceSummary()
+	EndResourceProgress()
 
 	fmt.Println("========================================")
 	fmt.Println("Total Summary:")
@@ -54,6 +62,9 @@ func PrintSummary(Operation string) {
 	fmt.Printf("Total Requests: %d\n", SummaryData.TotalRequests)
 	fmt.Printf("Successful Operations: %d\n", SummaryData.SuccessfulOperations)
 	fmt.Printf("Failed Operations: %d\n", SummaryData.FailedOperations)
+	if backupDir := GetBackupRunDir(); backupDir != "" {
+		fmt.Printf("Backup Directory: %s\n", backupDir)
+	}
 
 	if Operation == IMPORT {
 		PrintImportSummary()
@@ -69,10 +80,19 @@ func PrintExportSummary() {
 		fmt.Printf("%s\n", summary.ResourceType)
 		fmt.Println("----------------------------------------")
 		fmt.Printf("Successful Exports: %d\n", summary.SuccessfulExport)
+		if summary.Unchanged > 0 {
+			fmt.Printf("Unchanged: %d\n", summary.Unchanged)
+		}
 
 		if summary.Failed > 0 {
 			PrintFailedResources(summary)
 		}
+		if len(summary.UnprocessedResources) > 0 {
+			printUnprocessedResources(summary)
+		}
+		if len(summary.PrunedFiles) > 0 {
+			printPrunedFiles(summary)
+		}
 	}
 	fmt.Println("----------------------------------------")
 }
@@ -85,10 +105,25 @@ func PrintImportSummary() {
 		fmt.Println("----------------------------------------")
 		fmt.Printf("Successful Imports: %d\n", summary.SuccessfulImport)
 		fmt.Printf("Successful Updates: %d\n", summary.SuccessfulUpdate)
+		if summary.Unchanged > 0 {
+			fmt.Printf("Unchanged: %d\n", summary.Unchanged)
+		}
 		fmt.Printf("Deleted: %d\n", summary.Deleted)
 		if summary.Failed > 0 {
 			PrintFailedResources(summary)
 		}
+		if len(summary.FilteredResources) > 0 {
+			printFilteredResources(summary)
+		}
+		if len(summary.UnprocessedResources) > 0 {
+			printUnprocessedResources(summary)
+		}
+		if len(summary.ExternallyManagedResources) > 0 {
+			printExternallyManagedResources(summary)
+		}
+		if len(summary.ConflictedResources) > 0 {
+			printConflictedResources(summary)
+		}
 		if summary.ResourceType == APPLICATIONS {
 			printNewSecretApplications(summary)
 		}
@@ -112,6 +147,46 @@ func PrintFailedResources(summary ResourceSummary) {
 	fmt.Println()
 }
 
+func printFilteredResources(summary ResourceSummary) {
+
+	fmt.Println("....................")
+	fmt.Printf("Skipped by filter: %d\n", len(summary.FilteredResources))
+	fmt.Println("....................")
+	fmt.Println(strings.Join(summary.FilteredResources, ", "))
+}
+
+func printUnprocessedResources(summary ResourceSummary) {
+
+	fmt.Println("....................")
+	fmt.Printf("Not processed within the time budget: %d\n", len(summary.UnprocessedResources))
+	fmt.Println("....................")
+	fmt.Println(strings.Join(summary.UnprocessedResources, ", "))
+}
+
+func printExternallyManagedResources(summary ResourceSummary) {
+
+	fmt.Println("....................")
+	fmt.Printf("Externally managed: %d\n", len(summary.ExternallyManagedResources))
+	fmt.Println("....................")
+	fmt.Println(strings.Join(summary.ExternallyManagedResources, ", "))
+}
+
+func printPrunedFiles(summary ResourceSummary) {
+
+	fmt.Println("....................")
+	fmt.Printf("Pruned files: %d\n", len(summary.PrunedFiles))
+	fmt.Println("....................")
+	fmt.Println(strings.Join(summary.PrunedFiles, ", "))
+}
+
+func printConflictedResources(summary ResourceSummary) {
+
+	fmt.Println("....................")
+	fmt.Printf("Kept the server version due to conflicts: %d\n", len(summary.ConflictedResources))
+	fmt.Println("....................")
+	fmt.Println(strings.Join(summary.ConflictedResources, ", "))
+}
+
 func printNewSecretApplications(summary ResourceSummary) {
 
 	if len(summary.SecretGeneratedApplications) > 0 {
@@ -142,9 +217,95 @@ func AddNewSecretIndicatorToSummary(appName string) {
 	ResourceSummaries[APPLICATIONS] = summary
 }
 
-func UpdateSuccessSummary(resourceType string, operation string) {
+func AddFilteredResourceToSummary(resourceType string, resourceName string) {
 
 	InitializeResourceSummary()
+	EmitResourceFinished(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED)
+	recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_FILTERED)
+
+	summary, ok := ResourceSummaries[resourceType]
+	if !ok {
+		summary = ResourceSummary{
+			ResourceType: resourceType,
+		}
+	}
+	summary.FilteredResources = append(summary.FilteredResources, resourceName)
+	ResourceSummaries[resourceType] = summary
+}
+
+func AddUnprocessedResourceToSummary(resourceType string, resourceName string) {
+
+	InitializeResourceSummary()
+	EmitResourceFinished(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED)
+	if IsCancelled() {
+		recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_INTERRUPTED)
+	} else {
+		recordJUnitTestCase(resourceType, resourceName, "", PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_UNPROCESSED)
+	}
+
+	summary, ok := ResourceSummaries[resourceType]
+	if !ok {
+		summary = ResourceSummary{
+			ResourceType: resourceType,
+		}
+	}
+	summary.UnprocessedResources = append(summary.UnprocessedResources, resourceName)
+	ResourceSummaries[resourceType] = summary
+}
+
+func AddExternallyManagedResourceToSummary(resourceType string, resourceName string) {
+
+	InitializeResourceSummary()
+
+	summary, ok := ResourceSummaries[resourceType]
+	if !ok {
+		summary = ResourceSummary{
+			ResourceType: resourceType,
+		}
+	}
+	summary.ExternallyManagedResources = append(summary.ExternallyManagedResources, resourceName)
+	ResourceSummaries[resourceType] = summary
+}
+
+func AddPrunedFileToSummary(resourceType string, fileName string) {
+
+	InitializeResourceSummary()
+
+	summary, ok := ResourceSummaries[resourceType]
+	if !ok {
+		summary = ResourceSummary{
+			ResourceType: resourceType,
+		}
+	}
+	summary.PrunedFiles = append(summary.PrunedFiles, fileName)
+	ResourceSummaries[resourceType] = summary
+}
+
+func AddConflictedResourceToSummary(resourceType string, resourceName string) {
+
+	InitializeResourceSummary()
+
+	summary, ok := ResourceSummaries[resourceType]
+	if !ok {
+		summary = ResourceSummary{
+			ResourceType: resourceType,
+		}
+	}
+	summary.ConflictedResources = append(summary.ConflictedResources, resourceName)
+	ResourceSummaries[resourceType] = summary
+}
+
+func UpdateSuccessSummary(resourceType string, resourceName string, operation string) {
+
+	InitializeResourceSummary()
+	EmitResourceFinished(resourceType, resourceName, operation, PROGRESS_RESULT_SUCCESS)
+	recordResourceMetric(resourceType, operation)
+	recordAuditSuccess(resourceType, resourceName, operation)
+	if operation == UNCHANGED {
+		recordJUnitTestCase(resourceType, resourceName, operation, PROGRESS_RESULT_SKIPPED, JUNIT_SKIPPED_UNCHANGED)
+	} else {
+		recordJUnitTestCase(resourceType, resourceName, operation, PROGRESS_RESULT_SUCCESS, "")
+	}
 
 	SummaryData.TotalRequests++
 	SummaryData.SuccessfulOperations++
@@ -164,6 +325,8 @@ func UpdateSuccessSummary(resourceType string, operation string) {
 		summary.SuccessfulUpdate++
 	case DELETE:
 		summary.Deleted++
+	case UNCHANGED:
+		summary.Unchanged++
 	}
 	ResourceSummaries[resourceType] = summary
 }
@@ -171,6 +334,10 @@ func UpdateSuccessSummary(resourceType string, operation string) {
 func UpdateFailureSummary(resourceType string, resourceName string) {
 
 	InitializeResourceSummary()
+	EmitResourceFinished(resourceType, resourceName, "", PROGRESS_RESULT_FAILED)
+	recordResourceMetric(resourceType, PROGRESS_RESULT_FAILED)
+	recordAuditFailure(resourceType, resourceName, currentAction)
+	recordJUnitTestCase(resourceType, resourceName, currentAction, PROGRESS_RESULT_FAILED, "")
 
 	SummaryData.TotalRequests++
 	SummaryData.FailedOperations++
@@ -186,6 +353,12 @@ func UpdateFailureSummary(resourceType string, resourceName string) {
 	ResourceSummaries[resourceType] = summary
 }
 
+func ResetSummary() {
+
+	SummaryData = Summary{}
+	ResourceSummaries = make(map[string]ResourceSummary)
+}
+
 func InitializeResourceSummary() {
 
 	if ResourceSummaries == nil {
@@ -0,0 +1,137 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+// Run the command for each tenant in the TENANTS server config instead of the TENANT_DOMAIN. Set by the
+// --all-tenants flag.
+var ALL_TENANTS bool
+
+type TenantResult struct {
+	TenantDomain string
+	Summary      Summary
+	// Error that stopped the resources of the tenant from being processed.
+	Error string
+}
+
+// Run the given function for each tenant in the TENANTS server config, with the directory of the tenant inside the
+// given base directory. The tool authenticates to each tenant before the run, and a failure of a tenant does not
+// stop the other tenants.
+func RunForEachTenant(baseDir string, run func(tenantDir string) error) []TenantResult {
+
+	if len(SERVER_CONFIGS.Tenants) == 0 {
+		log.Fatalln("The TENANTS server config should be defined to use the --all-tenants flag.")
+	}
+	defaultKeywordMappings := KEYWORD_CONFIGS.KeywordMappings
+	var results []TenantResult
+	var total Summary
+	for _, tenantDomain := range SERVER_CONFIGS.Tenants {
+		result := TenantResult{TenantDomain: tenantDomain}
+		if IsBudgetExhausted() || IsStopRequested() {
+			result.Error = "the tenant is not processed since the run is stopped"
+			results = append(results, result)
+			continue
+		}
+		log.Println("Processing tenant: " + tenantDomain)
+		ResetSummary()
+		err := switchTenant(tenantDomain, defaultKeywordMappings)
+		if err == nil {
+			err = run(filepath.Join(baseDir, tenantDomain))
+		}
+		if err != nil {
+			log.Printf("Error when processing the tenant: %s. %s", tenantDomain, err)
+			result.Error = err.Error()
+		}
+		result.Summary = SummaryData
+		total.TotalRequests += SummaryData.TotalRequests
+		total.SuccessfulOperations += SummaryData.SuccessfulOperations
+		total.FailedOperations += SummaryData.FailedOperations
+		results = append(results, result)
+	}
+	SummaryData = total
+	return results
+}
+
+func switchTenant(tenantDomain string, defaultKeywordMappings map[string]interface{}) error {
+
+	SERVER_CONFIGS.TenantDomain = tenantDomain
+	// The organization id of the server configs belongs to a single tenant.
+	SERVER_CONFIGS.OrganizationId = ""
+	token, err := requestAccessToken(GetRequestContext(), SERVER_CONFIGS)
+	if err != nil {
+		return fmt.Errorf("error when getting an access token for the tenant: %s", err)
+	}
+	SERVER_CONFIGS.Token = token
+	if ORGANIZATION != "" {
+		if err := switchOrganization(); err != nil {
+			return fmt.Errorf("error when switching to the organization: %s. %s", ORGANIZATION, err)
+		}
+	}
+	KEYWORD_CONFIGS.KeywordMappings = resolveTenantKeywordMappings(tenantDomain, defaultKeywordMappings)
+	return nil
+}
+
+// Get the default keyword mappings with the keyword mappings of the tenant in the TENANTS keyword config, so that
+// the same keyword can have a different value in each tenant. Resource specific keyword mappings take precedence.
+func resolveTenantKeywordMappings(tenantDomain string, defaultKeywordMappings map[string]interface{}) map[string]interface{} {
+
+	keywordMappings := make(map[string]interface{}, len(defaultKeywordMappings))
+	for keyword, value := range defaultKeywordMappings {
+		keywordMappings[keyword] = value
+	}
+	if tenantConfigs, ok := KEYWORD_CONFIGS.TenantConfigs[tenantDomain].(map[string]interface{}); ok {
+		if tenantKeywordMappings, ok := tenantConfigs[KEYWORD_MAPPINGS_CONFIG].(map[string]interface{}); ok {
+			for keyword, value := range tenantKeywordMappings {
+				keywordMappings[keyword] = value
+			}
+		}
+	}
+	return keywordMappings
+}
+
+func PrintTenantSummary(results []TenantResult) {
+
+	fmt.Println("========================================")
+	fmt.Println("Tenant Summary:")
+	fmt.Println("========================================")
+	for _, result := range results {
+		if result.Error != "" {
+			fmt.Printf("%s: failed. %s\n", result.TenantDomain, result.Error)
+			continue
+		}
+		fmt.Printf("%s: %d successful operations, %d failed operations\n", result.TenantDomain,
+			result.Summary.SuccessfulOperations, result.Summary.FailedOperations)
+	}
+}
+
+// Exit with a non-zero exit code if a tenant could not be processed.
+func ExitIfTenantFailed(results []TenantResult) {
+
+	for _, result := range results {
+		if result.Error != "" {
+			os.Exit(1)
+		}
+	}
+}
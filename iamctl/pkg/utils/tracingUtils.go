@@ -0,0 +1,312 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package utils
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const OTEL_ENDPOINT_ENV = "OTEL_EXPORTER_OTLP_ENDPOINT"
+const OTEL_TRACES_PATH = "/v1/traces"
+const OTEL_SERVICE_NAME = "iamctl"
+const TRACEPARENT_HEADER = "traceparent"
+const SPAN_EXPORT_BATCH_SIZE = 50
+
+// Span kinds and status codes of the OTLP specification.
+const spanKindInternal = 1
+const spanKindClient = 3
+const spanStatusError = 2
+
+// OTLP/HTTP endpoint of the OpenTelemetry collector. Set by the --otel-endpoint flag.
+var OTEL_ENDPOINT string
+
+type spanContextKey struct{}
+
+type otelAttribute struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otelStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otelSpan struct {
+	TraceId           string          `json:"traceId"`
+	SpanId            string          `json:"spanId"`
+	ParentSpanId      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otelAttribute `json:"attributes,omitempty"`
+	Status            otelStatus      `json:"status"`
+}
+
+// The spans are exported in batches by a background goroutine, so that the requests are not blocked by the export.
+type tracer struct {
+	mutex        sync.Mutex
+	endpoint     string
+	traceId      string
+	rootSpan     otelSpan
+	pendingSpans []otelSpan
+	finished     bool
+	flush        chan struct{}
+	stop         chan struct{}
+	done         chan struct{}
+}
+
+var activeTracer *tracer
+var activeTracerMutex sync.RWMutex
+
+func IsTracingEnabled() bool {
+
+	return getActiveTracer() != nil
+}
+
+// Get the tracer of the run. The requests sent while the tracing is finished, such as by the fetch pool workers, keep
+// the tracer they got, and their spans are dropped after the final export.
+func getActiveTracer() *tracer {
+
+	activeTracerMutex.RLock()
+	defer activeTracerMutex.RUnlock()
+	return activeTracer
+}
+
+// Start a trace for the run, with a root span of the given name.
+func StartTracing(name string) {
+
+	endpoint := OTEL_ENDPOINT
+	if endpoint == "" {
+		endpoint = os.Getenv(OTEL_ENDPOINT_ENV)
+	}
+	if endpoint == "" {
+		return
+	}
+	if !strings.HasSuffix(endpoint, OTEL_TRACES_PATH) {
+		endpoint = strings.TrimSuffix(endpoint, "/") + OTEL_TRACES_PATH
+	}
+
+	traceId := newTraceId(16)
+	t := &tracer{
+		endpoint: endpoint,
+		traceId:  traceId,
+		rootSpan: otelSpan{
+			TraceId:           traceId,
+			SpanId:            newTraceId(8),
+			Name:              name,
+			Kind:              spanKindInternal,
+			StartTimeUnixNano: unixNano(time.Now()),
+		},
+		flush: make(chan struct{}, 1),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	go t.runExporter()
+	activeTracerMutex.Lock()
+	activeTracer = t
+	activeTracerMutex.Unlock()
+	log.Printf("Tracing is enabled. Trace ID: %s", traceId)
+}
+
+// End the root span and export the remaining spans.
+func FinishTracing() {
+
+	activeTracerMutex.Lock()
+	t := activeTracer
+	activeTracer = nil
+	activeTracerMutex.Unlock()
+	if t == nil {
+		return
+	}
+	t.mutex.Lock()
+	t.rootSpan.EndTimeUnixNano = unixNano(time.Now())
+	t.pendingSpans = append(t.pendingSpans, t.rootSpan)
+	t.finished = true
+	t.mutex.Unlock()
+
+	// Wait for the exporter to send the remaining spans.
+	close(t.stop)
+	<-t.done
+}
+
+// Create a client span for each request.
+func SpanMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		t := getActiveTracer()
+		if t == nil {
+			return next.RoundTrip(req)
+		}
+		span := otelSpan{
+			TraceId:           t.traceId,
+			SpanId:            newTraceId(8),
+			ParentSpanId:      t.rootSpan.SpanId,
+			Name:              req.Method,
+			Kind:              spanKindClient,
+			StartTimeUnixNano: unixNano(time.Now()),
+			Attributes: []otelAttribute{
+				stringAttribute("http.request.method", req.Method),
+				stringAttribute("server.address", req.URL.Hostname()),
+				stringAttribute("url.full", redactUrl(req)),
+			},
+		}
+		req = req.WithContext(context.WithValue(req.Context(), spanContextKey{}, span.SpanId))
+
+		resp, err := next.RoundTrip(req)
+		span.EndTimeUnixNano = unixNano(time.Now())
+		if err != nil {
+			span.Status = otelStatus{Code: spanStatusError, Message: err.Error()}
+		} else {
+			span.Attributes = append(span.Attributes, otelAttribute{Key: "http.response.status_code",
+				Value: map[string]interface{}{"intValue": strconv.Itoa(resp.StatusCode)}})
+			if resp.StatusCode >= 400 {
+				span.Status = otelStatus{Code: spanStatusError}
+			}
+		}
+		t.addSpan(span)
+		return resp, err
+	})
+}
+
+// Propagate the trace context to the server with the W3C traceparent header.
+func TraceparentMiddleware(next http.RoundTripper) http.RoundTripper {
+
+	return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		t := getActiveTracer()
+		if t == nil {
+			return next.RoundTrip(req)
+		}
+		parentSpanId, ok := req.Context().Value(spanContextKey{}).(string)
+		if !ok {
+			parentSpanId = t.rootSpan.SpanId
+		}
+		req = req.Clone(req.Context())
+		req.Header.Set(TRACEPARENT_HEADER, fmt.Sprintf("00-%s-%s-01", t.traceId, parentSpanId))
+		return next.RoundTrip(req)
+	})
+}
+
+func (t *tracer) addSpan(span otelSpan) {
+
+	t.mutex.Lock()
+	if t.finished {
+		t.mutex.Unlock()
+		return
+	}
+	t.pendingSpans = append(t.pendingSpans, span)
+	batchFull := len(t.pendingSpans) >= SPAN_EXPORT_BATCH_SIZE
+	t.mutex.Unlock()
+	if batchFull {
+		// Signal the exporter without waiting, since a pending signal exports all spans added until then.
+		select {
+		case t.flush <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (t *tracer) runExporter() {
+
+	defer close(t.done)
+	for {
+		select {
+		case <-t.flush:
+			t.export()
+		case <-t.stop:
+			t.export()
+			return
+		}
+	}
+}
+
+func (t *tracer) export() {
+
+	t.mutex.Lock()
+	spans := t.pendingSpans
+	t.pendingSpans = nil
+	t.mutex.Unlock()
+	if len(spans) == 0 {
+		return
+	}
+
+	payload := map[string]interface{}{
+		"resourceSpans": []interface{}{
+			map[string]interface{}{
+				"resource": map[string]interface{}{
+					"attributes": []otelAttribute{stringAttribute("service.name", OTEL_SERVICE_NAME)},
+				},
+				"scopeSpans": []interface{}{
+					map[string]interface{}{
+						"scope": map[string]interface{}{"name": OTEL_SERVICE_NAME},
+						"spans": spans,
+					},
+				},
+			},
+		},
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Println("Error when creating the trace export request.", err)
+		return
+	}
+
+	// Use a plain client, so that the export requests are not traced.
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Post(t.endpoint, MEDIA_TYPE_JSON, bytes.NewReader(body))
+	if err != nil {
+		log.Println("Error when exporting the traces.", err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		log.Printf("Error when exporting the traces. Unexpected response: %s", resp.Status)
+	}
+}
+
+func stringAttribute(key string, value string) otelAttribute {
+
+	return otelAttribute{Key: key, Value: map[string]interface{}{"stringValue": value}}
+}
+
+func newTraceId(length int) string {
+
+	id := make([]byte, length)
+	if _, err := rand.Read(id); err != nil {
+		id = []byte(fmt.Sprintf("%0*d", length, time.Now().UnixNano()))[:length]
+	}
+	return hex.EncodeToString(id)
+}
+
+func unixNano(t time.Time) string {
+
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,107 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version of the binary. Set at build time with -ldflags "-X <module>/pkg/utils.VERSION=<version>".
+var VERSION = "dev"
+
+const LATEST_RELEASE_URL = "https://api.github.com/repos/wso2-extensions/identity-tools-cli/releases/latest"
+const IAMCTL_NO_UPDATE_CHECK = "IAMCTL_NO_UPDATE_CHECK"
+
+func GetLatestReleaseVersion() (string, error) {
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	req, err := http.NewRequestWithContext(GetRequestContext(), "GET", LATEST_RELEASE_URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("error when creating the request: %s", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error when retrieving the latest release: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("error when retrieving the latest release: unexpected status %s", resp.Status)
+	}
+
+	var release struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&release); err != nil {
+		return "", fmt.Errorf("error when reading the latest release: %s", err)
+	}
+	if release.TagName == "" {
+		return "", fmt.Errorf("latest release does not have a tag")
+	}
+	return release.TagName, nil
+}
+
+// Returns a negative number if the first version is older than the second, zero if they are equal and a positive
+// number otherwise. A leading "v" and the pre-release suffix are ignored. Ex: v1.0.8, 1.0.8-SNAPSHOT
+func CompareVersions(first string, second string) (int, error) {
+
+	firstParts, err := parseVersion(first)
+	if err != nil {
+		return 0, err
+	}
+	secondParts, err := parseVersion(second)
+	if err != nil {
+		return 0, err
+	}
+	for i := 0; i < len(firstParts) || i < len(secondParts); i++ {
+		var firstPart, secondPart int
+		if i < len(firstParts) {
+			firstPart = firstParts[i]
+		}
+		if i < len(secondParts) {
+			secondPart = secondParts[i]
+		}
+		if firstPart != secondPart {
+			return firstPart - secondPart, nil
+		}
+	}
+	return 0, nil
+}
+
+func parseVersion(version string) ([]int, error) {
+
+	trimmed := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if index := strings.IndexAny(trimmed, "-+"); index >= 0 {
+		trimmed = trimmed[:index]
+	}
+	var parts []int
+	for _, part := range strings.Split(trimmed, ".") {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version: %s", version)
+		}
+		parts = append(parts, number)
+	}
+	return parts, nil
+}
@@ -0,0 +1,298 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Warning categories
+const WARNING_MASKED_SECRET = "masked-secret"
+const WARNING_UNRESOLVED_KEYWORD = "unresolved-keyword"
+const WARNING_EXPIRING_CERTIFICATE = "expiring-certificate"
+const WARNING_KEYWORD_OVERRIDE = "keyword-override"
+const WARNING_NAME_MISMATCH = "name-mismatch"
+const WARNING_CLIENT_AUTH = "client-auth"
+const WARNING_CLAIM_REFERENCE = "claim-reference"
+const WARNING_SYSTEM_CLAIM = "system-claim"
+const WARNING_IMPORT_STATE = "import-state"
+const WARNING_QUOTA = "quota"
+const WARNING_CONFIG = "config"
+const WARNING_TENANT_MISMATCH = "tenant-mismatch"
+const WARNING_UNSUPPORTED_CONNECTOR = "unsupported-connector"
+const WARNING_MISSING_DEPENDENCY = "missing-dependency"
+const WARNING_STALE_FILE = "stale-file"
+const WARNING_DUPLICATE_APP = "duplicate-app"
+const WARNING_CONFLICT = "conflict"
+
+var WARNING_CATEGORIES = []string{WARNING_MASKED_SECRET, WARNING_UNRESOLVED_KEYWORD, WARNING_EXPIRING_CERTIFICATE,
+	WARNING_KEYWORD_OVERRIDE, WARNING_NAME_MISMATCH, WARNING_CLIENT_AUTH, WARNING_CLAIM_REFERENCE, WARNING_SYSTEM_CLAIM,
+	WARNING_IMPORT_STATE, WARNING_QUOTA, WARNING_CONFIG, WARNING_TENANT_MISMATCH,
+	WARNING_UNSUPPORTED_CONNECTOR, WARNING_MISSING_DEPENDENCY, WARNING_STALE_FILE, WARNING_DUPLICATE_APP, WARNING_CONFLICT}
+
+// Treat warnings as errors. Set by the --strict flag.
+var STRICT bool
+
+// Warning categories that are not logged. Set by the --ignore-warning flag.
+var IGNORED_WARNINGS []string
+
+// Fail the import when a masked secret is found in the resource files. Set by the --abort-on-mask flag.
+var ABORT_ON_MASK bool
+
+var strictWarningCount int
+
+var keywordPlaceholderRegex = regexp.MustCompile(`\{\{[A-Za-z0-9_.-]+\}\}`)
+var tenantUrlRegex = regexp.MustCompile(`https?://[^\s"'<>|()]+`)
+var tenantPathRegex = regexp.MustCompile(`/(t|o)/([^/?#]+)`)
+
+type TenantMismatch struct {
+	Path string
+	Url  string
+}
+
+func ValidateWarningCategories(categories []string) error {
+
+	for _, category := range categories {
+		if !Contains(WARNING_CATEGORIES, category) {
+			return fmt.Errorf("unknown warning category: %s. Supported categories: %s",
+				category, strings.Join(WARNING_CATEGORIES, ", "))
+		}
+	}
+	return nil
+}
+
+func LogWarning(category string, message string) {
+
+	if Contains(IGNORED_WARNINGS, category) {
+		return
+	}
+	if STRICT {
+		strictWarningCount++
+		log.Printf("Error: [%s] %s", category, message)
+		return
+	}
+	log.Printf("Warning: [%s] %s", category, message)
+}
+
+func CheckImportContent(resourceType string, resourceName string, fileContent string) {
+
+	// Warn about the keyword placeholders that are not replaced since they are not defined in the keyword configs.
+	unresolvedKeywords := FindUnresolvedKeywords(fileContent)
+	if len(unresolvedKeywords) > 0 {
+		LogWarning(WARNING_UNRESOLVED_KEYWORD, fmt.Sprintf("%s: %s has unresolved keywords: %s",
+			resourceType, resourceName, strings.Join(unresolvedKeywords, ", ")))
+	}
+	if strings.Contains(fileContent, SENSITIVE_FIELD_MASK) {
+		LogWarning(WARNING_MASKED_SECRET, fmt.Sprintf("%s: %s has masked secrets. The masked fields will not be imported.",
+			resourceType, resourceName))
+	}
+}
+
+func ExitIfStrictWarnings() {
+
+	if strictWarningCount > 0 {
+		log.Fatalf("Found %d warnings, which are treated as errors since the --strict flag is set.", strictWarningCount)
+	}
+}
+
+func CheckMaskedSecrets(filePaths []string) error {
+
+	// Masked secrets are detected before importing any resource, so that the import is not partially applied.
+	var maskedFields []string
+	for _, filePath := range filePaths {
+		fileContent, err := ReadResourceFile(filePath)
+		if err != nil {
+			return fmt.Errorf("error when reading the file: %s. %s", filePath, err)
+		}
+		for _, field := range FindMaskedFields(fileContent) {
+			maskedFields = append(maskedFields, fmt.Sprintf("%s: %s", filePath, field))
+		}
+	}
+	if len(maskedFields) > 0 {
+		return fmt.Errorf("found masked secrets in the following fields. Replace the masked values with the secrets "+
+			"or with keyword placeholders before importing.\n  %s", strings.Join(maskedFields, "\n  "))
+	}
+	return nil
+}
+
+func FindMaskedFields(fileContent []byte) []string {
+
+	var maskedFields []string
+	var content interface{}
+	if yaml.Unmarshal(ReplaceTypeTags(fileContent), &content) != nil {
+		// Report the line numbers when the content cannot be parsed.
+		for i, line := range strings.Split(string(fileContent), "\n") {
+			if strings.Contains(line, SENSITIVE_FIELD_MASK) {
+				maskedFields = append(maskedFields, "line "+strconv.Itoa(i+1))
+			}
+		}
+		return maskedFields
+	}
+	walkStringFields(content, "", func(path string, value string) {
+		if value == strings.Trim(SENSITIVE_FIELD_MASK, "'") {
+			maskedFields = append(maskedFields, path)
+		}
+	})
+	return maskedFields
+}
+
+func CheckTenantUrls(inputDirPath string, filePaths []string) error {
+
+	// URLs of other tenants are detected before importing any resource, so that the import is not partially applied.
+	resourceFiles, err := getLocalResourceFiles(inputDirPath)
+	if err != nil {
+		return err
+	}
+	var mismatchedResources []string
+	for _, resourceFile := range resourceFiles {
+		if filePaths != nil && !containsFilePath(filePaths, resourceFile.path) {
+			continue
+		}
+		fileContent, err := ReadResourceFile(resourceFile.path)
+		if err != nil {
+			return fmt.Errorf("error when reading the file: %s. %s", resourceFile.path, err)
+		}
+		resolvedContent := ReplaceKeywords(string(fileContent),
+			getResourceKeywordMapping(resourceFile.resourceType, resourceFile.resourceName))
+		if err := CheckTenantMismatches(resourceFile.resourceType, resourceFile.resourceName, resolvedContent); err != nil {
+			mismatchedResources = append(mismatchedResources, resourceFile.path)
+		}
+	}
+	if len(mismatchedResources) > 0 {
+		return fmt.Errorf("found URLs of another tenant in the following files. Use keyword placeholders for the "+
+			"tenant specific URLs.\n  %s", strings.Join(mismatchedResources, "\n  "))
+	}
+	return nil
+}
+
+func CheckTenantMismatches(resourceType string, resourceName string, fileContent string) error {
+
+	warningCount := strictWarningCount
+	for _, mismatch := range FindTenantMismatches([]byte(fileContent), SERVER_CONFIGS.TenantDomain,
+		SERVER_CONFIGS.OrganizationId) {
+		LogWarning(WARNING_TENANT_MISMATCH, fmt.Sprintf("%s: %s has a URL of another tenant in %s: %s",
+			resourceType, resourceName, mismatch.Path, mismatch.Url))
+	}
+
+	// Only the warnings that are treated as errors fail the check.
+	if strictWarningCount > warningCount {
+		return fmt.Errorf("%s: %s has URLs of another tenant", resourceType, resourceName)
+	}
+	return nil
+}
+
+func FindTenantMismatches(fileContent []byte, tenantDomain string, organizationId string) []TenantMismatch {
+
+	var mismatches []TenantMismatch
+	checkValue := func(path string, value string) {
+		for _, resourceUrl := range tenantUrlRegex.FindAllString(value, -1) {
+			if isForeignTenantUrl(resourceUrl, tenantDomain, organizationId) {
+				mismatches = append(mismatches, TenantMismatch{path, resourceUrl})
+			}
+		}
+	}
+
+	var content interface{}
+	if yaml.Unmarshal(ReplaceTypeTags(fileContent), &content) != nil {
+		// Report the line numbers when the content cannot be parsed.
+		for i, line := range strings.Split(string(fileContent), "\n") {
+			checkValue("line "+strconv.Itoa(i+1), line)
+		}
+		return mismatches
+	}
+	walkStringFields(content, "", checkValue)
+	return mismatches
+}
+
+func isForeignTenantUrl(resourceUrl string, tenantDomain string, organizationId string) bool {
+
+	parsedUrl, err := url.Parse(resourceUrl)
+	if err != nil {
+		return false
+	}
+	for _, match := range tenantPathRegex.FindAllStringSubmatch(parsedUrl.Path, -1) {
+		// Keyword placeholders are resolved before the check, and the remaining ones are reported separately.
+		if strings.Contains(match[2], "{{") {
+			continue
+		}
+		switch match[1] {
+		case "t":
+			if !strings.EqualFold(match[2], tenantDomain) {
+				return true
+			}
+		case "o":
+			// Organization paths can only be verified when the organization of the target environment is configured.
+			if organizationId != "" && !strings.EqualFold(match[2], organizationId) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func containsFilePath(filePaths []string, filePath string) bool {
+
+	for _, path := range filePaths {
+		if filepath.Clean(path) == filepath.Clean(filePath) {
+			return true
+		}
+	}
+	return false
+}
+
+func walkStringFields(data interface{}, path string, visit func(path string, value string)) {
+
+	switch v := data.(type) {
+	case map[interface{}]interface{}:
+		keys := make([]interface{}, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprintf("%v", keys[i]) < fmt.Sprintf("%v", keys[j])
+		})
+		for _, key := range keys {
+			fieldPath := fmt.Sprintf("%v", key)
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			walkStringFields(v[key], fieldPath, visit)
+		}
+	case []interface{}:
+		for i, value := range v {
+			walkStringFields(value, path+"["+strconv.Itoa(i)+"]", visit)
+		}
+	case string:
+		visit(path, v)
+	}
+}
+
+func FindUnresolvedKeywords(fileContent string) []string {
+
+	return keywordPlaceholderRegex.FindAllString(fileContent, -1)
+}
@@ -0,0 +1,190 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Default polling interval in seconds of the --watch mode.
+const DEFAULT_WATCH_INTERVAL = 60
+
+// Set by the --watch flag. Files are written only when the exported content differs from the local file.
+var WATCH_EXPORT bool
+
+var updatedFileCount int
+
+func isExportedFileUnchanged(exportedFileName string, content []byte) bool {
+
+	existingContent, err := ioutil.ReadFile(exportedFileName)
+	return err == nil && bytes.Equal(existingContent, content)
+}
+
+// Returns the number of files updated since the last call, so that each poll reports its own changes.
+func GetUpdatedFileCount() int {
+
+	count := updatedFileCount
+	updatedFileCount = 0
+	return count
+}
+
+func IsServerAvailable() bool {
+
+	resp, err := GetHttpClient().Get(SERVER_CONFIGS.ServerUrl + HEALTH_CHECK_PATH)
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return resp.StatusCode == http.StatusOK
+}
+
+// Get a new access token without exiting on failure, since the server can be unavailable between the polls.
+func RefreshAccessToken() error {
+
+	token, err := requestAccessToken(GetRequestContext(), SERVER_CONFIGS)
+	if err != nil {
+		return fmt.Errorf("error when refreshing the access token: %s", err)
+	}
+	SERVER_CONFIGS.Token = token
+	if ORGANIZATION != "" {
+		return switchOrganization()
+	}
+	return nil
+}
+
+// Guards KEYWORD_CONFIGS while the keyword configs are reloaded in the watch mode.
+var keywordConfigsLock sync.RWMutex
+
+// Keep the keyword configs unchanged until ReleaseKeywordConfigs is called, so that all resources of a poll are
+// exported with the same keyword configs.
+func HoldKeywordConfigs() {
+
+	keywordConfigsLock.RLock()
+}
+
+func ReleaseKeywordConfigs() {
+
+	keywordConfigsLock.RUnlock()
+}
+
+// Reload the keyword configs whenever the keyword config file changes, until the returned function is called.
+// A reload waits for the poll that holds the keyword configs to complete.
+func WatchKeywordConfigs() (func(), error) {
+
+	if keywordConfigFilePath == "" {
+		return func() {}, nil
+	}
+	configFilePath, err := filepath.Abs(keywordConfigFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("error when watching the keyword config file: %s", err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("error when watching the keyword config file: %s", err)
+	}
+	// Editors often replace the file instead of writing to it, so the folder of the file is watched.
+	if err := watcher.Add(filepath.Dir(configFilePath)); err != nil {
+		watcher.Close()
+		return nil, fmt.Errorf("error when watching the keyword config file: %s", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if filepath.Clean(event.Name) == configFilePath && event.Op&(fsnotify.Write|fsnotify.Create) != 0 {
+					ReloadKeywordConfigs()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Println("Error when watching the keyword config file.", err)
+			}
+		}
+	}()
+	return func() {
+		watcher.Close()
+		<-done
+	}, nil
+}
+
+// Reload the keyword configs from the keyword config file. The loaded keyword configs are kept if the file cannot be
+// read, such as when the file is being written. An empty file is also ignored, since a file is truncated before it
+// is written.
+func ReloadKeywordConfigs() {
+
+	if fileInfo, err := os.Stat(keywordConfigFilePath); err == nil && fileInfo.Size() == 0 {
+		return
+	}
+	keywordConfigs, err := readKeywordConfigsFile(keywordConfigFilePath)
+	if err != nil {
+		log.Printf("Error when reloading the keyword configs. The loaded keyword configs are kept. %s", err)
+		return
+	}
+
+	keywordConfigsLock.Lock()
+	changedCount := countChangedKeywordEntries(KEYWORD_CONFIGS, keywordConfigs)
+	if changedCount > 0 {
+		KEYWORD_CONFIGS = keywordConfigs
+	}
+	keywordConfigsLock.Unlock()
+
+	if changedCount > 0 {
+		log.Printf("Keyword configs reloaded at %s. Changed entries: %d.", time.Now().Format(time.RFC3339), changedCount)
+	}
+}
+
+// Count the keyword mappings and resource keyword configs that are added, removed or changed.
+func countChangedKeywordEntries(oldConfigs KeywordConfigs, newConfigs KeywordConfigs) int {
+
+	changedCount := 0
+	oldValue, newValue := reflect.ValueOf(oldConfigs), reflect.ValueOf(newConfigs)
+	for i := 0; i < oldValue.NumField(); i++ {
+		oldEntries := oldValue.Field(i).Interface().(map[string]interface{})
+		newEntries := newValue.Field(i).Interface().(map[string]interface{})
+		for key, value := range oldEntries {
+			if newEntry, ok := newEntries[key]; !ok || !reflect.DeepEqual(value, newEntry) {
+				changedCount++
+			}
+		}
+		for key := range newEntries {
+			if _, ok := oldEntries[key]; !ok {
+				changedCount++
+			}
+		}
+	}
+	return changedCount
+}
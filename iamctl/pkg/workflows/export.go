@@ -0,0 +1,120 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package workflows
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func ExportAll(exportFilePath string) {
+
+	// Export each workflow with its associations to a separate file in the Workflows folder.
+	log.Println("Exporting workflows...")
+	exportFilePath = filepath.Join(exportFilePath, utils.WORKFLOWS)
+
+	if utils.IsResourceTypeExcluded(utils.WORKFLOWS) {
+		return
+	}
+	utils.WarnUnfilteredSince(utils.WORKFLOWS)
+	workflows, err := getWorkflowList()
+	if err != nil {
+		log.Println("Error while retrieving workflow list.", err)
+		return
+	}
+	associations, err := getAssociationList()
+	if err != nil {
+		log.Println("Error while retrieving workflow association list.", err)
+		return
+	}
+	references, err := newApproverReferences()
+	if err != nil {
+		log.Println("Error while retrieving the approver roles of the workflows.", err)
+		return
+	}
+
+	var workflowNames []string
+	for _, workflow := range workflows {
+		workflowNames = append(workflowNames, workflow.Name)
+	}
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	} else if utils.TOOL_CONFIGS.AllowDelete {
+		utils.RemoveDeletedLocalResources(exportFilePath, workflowNames)
+	}
+
+	sort.SliceStable(workflows, func(i, j int) bool {
+		return utils.GetResourcePriority(workflows[i].Name) < utils.GetResourcePriority(workflows[j].Name)
+	})
+	utils.StartResourceProgress(utils.WORKFLOWS, len(workflows))
+	for _, workflow := range workflows {
+		if utils.IsResourceExcluded(workflow.Name, utils.TOOL_CONFIGS.WorkflowConfigs) {
+			utils.ReportSkippedResource(utils.WORKFLOWS, workflow.Name, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.WORKFLOWS, workflow.Name)
+			continue
+		}
+		log.Println("Exporting workflow: ", workflow.Name)
+		utils.EmitResourceStarted(utils.WORKFLOWS, workflow.Name, utils.EXPORT)
+		err := exportWorkflow(workflow.Id, associations, references, exportFilePath)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.WORKFLOWS, workflow.Name)
+			log.Printf("Error while exporting workflow: %s. %s", workflow.Name, err)
+		} else {
+			utils.UpdateSuccessSummary(utils.WORKFLOWS, workflow.Name, utils.EXPORT)
+			log.Println("Workflow exported successfully: ", workflow.Name)
+		}
+	}
+}
+
+func exportWorkflow(workflowId string, associations []workflowAssociation, references *approverReferences,
+	outputDirPath string) error {
+
+	workflow, err := getWorkflow(workflowId)
+	if err != nil {
+		return err
+	}
+	workflow.Associations, err = getWorkflowAssociations(workflow.Name, associations)
+	if err != nil {
+		return err
+	}
+	if err := references.setApproverNames(&workflow); err != nil {
+		return err
+	}
+	content, err := yaml.Marshal(workflow)
+	if err != nil {
+		return fmt.Errorf("error while creating the exported content: %s", err)
+	}
+
+	exportedFileName := filepath.Join(outputDirPath, workflow.Name+".yml")
+	modifiedFile, err := utils.ProcessExportedContent(exportedFileName, content, getWorkflowKeywordMapping(workflow.Name),
+		utils.WORKFLOWS)
+	if err != nil {
+		return fmt.Errorf("error while processing the exported content: %s", err)
+	}
+	return utils.WriteExportedFile(exportedFileName, modifiedFile)
+}
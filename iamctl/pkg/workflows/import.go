@@ -0,0 +1,329 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+type localWorkflow struct {
+	fileName string
+	content  string
+	workflow workflow
+}
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.WORKFLOWS)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.WORKFLOWS) {
+		return
+	}
+
+	log.Println("Importing workflows...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing workflows: ", err)
+		return
+	}
+	workflows, err := getWorkflowList()
+	if err != nil {
+		log.Println("Error importing workflows: ", err)
+		return
+	}
+	associations, err := getAssociationList()
+	if err != nil {
+		log.Println("Error importing workflows: ", err)
+		return
+	}
+	references, err := newApproverReferences()
+	if err != nil {
+		log.Println("Error importing workflows: ", err)
+		return
+	}
+	deployedWorkflows := make(map[string]workflow)
+	for _, workflow := range workflows {
+		deployedWorkflows[workflow.Name] = workflow
+	}
+
+	var localWorkflows []localWorkflow
+	isLocalContentValid := true
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		fileName := utils.GetFileInfo(file.Name()).ResourceName
+		localWorkflow, err := readLocalWorkflow(filepath.Join(importFilePath, file.Name()))
+		if err != nil {
+			isLocalContentValid = false
+			if utils.IsResourceIncluded(fileName) {
+				utils.UpdateFailureSummary(utils.WORKFLOWS, fileName)
+				log.Printf("Invalid file configurations for workflow: %s. %s", fileName, err)
+			}
+			continue
+		}
+		localWorkflows = append(localWorkflows, localWorkflow)
+	}
+	if utils.IsDeleteAllowed() {
+		if isLocalContentValid {
+			removeDeletedDeployedWorkflows(workflows, localWorkflows)
+		} else {
+			log.Println("Skipping the deletion of workflows since some of the local files are invalid.")
+		}
+	}
+
+	sort.SliceStable(localWorkflows, func(i, j int) bool {
+		return utils.GetResourcePriority(localWorkflows[i].workflow.Name) <
+			utils.GetResourcePriority(localWorkflows[j].workflow.Name)
+	})
+	utils.StartResourceProgress(utils.WORKFLOWS, len(localWorkflows))
+	for _, localWorkflow := range localWorkflows {
+		workflowName := localWorkflow.workflow.Name
+		if !utils.IsResourceIncluded(localWorkflow.fileName) {
+			utils.AddFilteredResourceToSummary(utils.WORKFLOWS, workflowName)
+			continue
+		}
+		if utils.IsResourceExcluded(workflowName, utils.TOOL_CONFIGS.WorkflowConfigs) {
+			utils.ReportSkippedResource(utils.WORKFLOWS, workflowName, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.WORKFLOWS, workflowName)
+			continue
+		}
+		utils.EmitResourceStarted(utils.WORKFLOWS, workflowName, utils.IMPORT)
+		var err error
+		if deployedWorkflow, isUpdate := deployedWorkflows[workflowName]; isUpdate {
+			err = updateWorkflow(localWorkflow, deployedWorkflow.Id, associations, references)
+		} else {
+			err = createWorkflow(localWorkflow, references)
+		}
+		if err != nil {
+			utils.UpdateFailureSummary(utils.WORKFLOWS, workflowName)
+			log.Printf("Error when importing workflow: %s. %s", workflowName, err)
+		}
+	}
+}
+
+func readLocalWorkflow(importFilePath string) (localWorkflow, error) {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return localWorkflow{}, fmt.Errorf("error when reading the file for workflow: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	fileName := utils.GetFileInfo(importFilePath).ResourceName
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), getWorkflowKeywordMapping(fileName))
+
+	var workflow workflow
+	err = yaml.Unmarshal([]byte(modifiedFileData), &workflow)
+	if err != nil {
+		return localWorkflow{}, fmt.Errorf("invalid file content for workflow: %s", err)
+	}
+	if workflow.Name == "" {
+		return localWorkflow{}, fmt.Errorf("the name attribute is required")
+	}
+	for _, association := range workflow.Associations {
+		if association.AssociationName == "" || association.Operation == "" {
+			return localWorkflow{}, fmt.Errorf("the associationName and operation attributes are required for the associations")
+		}
+	}
+	return localWorkflow{fileName: fileName, content: modifiedFileData, workflow: workflow}, nil
+}
+
+func createWorkflow(localWorkflow localWorkflow, references *approverReferences) error {
+
+	workflowName := localWorkflow.workflow.Name
+	utils.CheckImportContent(utils.WORKFLOWS, workflowName, localWorkflow.content)
+	resolvedWorkflow, err := references.getWorkflowWithApproverIds(localWorkflow.workflow)
+	if err != nil {
+		return fmt.Errorf("error when resolving the dependencies of workflow: %s", err)
+	}
+
+	log.Println("Creating new workflow: " + workflowName)
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.WORKFLOWS, "", resolvedWorkflow)
+	if err != nil {
+		return fmt.Errorf("error when creating workflow: %s", err)
+	}
+	var createdWorkflow workflow
+	if err := json.Unmarshal(body, &createdWorkflow); err != nil || createdWorkflow.Id == "" {
+		return fmt.Errorf("error when reading the id of the created workflow: %s", string(body))
+	}
+	if err := updateAssociations(createdWorkflow.Id, localWorkflow.workflow.Associations, nil); err != nil {
+		return err
+	}
+	utils.UpdateImportState(utils.WORKFLOWS, workflowName, localWorkflow.content)
+	utils.UpdateSuccessSummary(utils.WORKFLOWS, workflowName, utils.IMPORT)
+	log.Println("Workflow created successfully.")
+	return nil
+}
+
+func updateWorkflow(localWorkflow localWorkflow, workflowId string, associations []workflowAssociation,
+	references *approverReferences) error {
+
+	workflowName := localWorkflow.workflow.Name
+	utils.CheckImportContent(utils.WORKFLOWS, workflowName, localWorkflow.content)
+	if utils.IsImportStateUnchanged(utils.WORKFLOWS, workflowName, localWorkflow.content) {
+		log.Println("Workflow is unchanged since the last import. Skipping update: " + workflowName)
+		utils.UpdateSuccessSummary(utils.WORKFLOWS, workflowName, utils.UNCHANGED)
+		return nil
+	}
+	resolvedWorkflow, err := references.getWorkflowWithApproverIds(localWorkflow.workflow)
+	if err != nil {
+		return fmt.Errorf("error when resolving the dependencies of workflow: %s", err)
+	}
+	deployedWorkflow, err := getWorkflow(workflowId)
+	if err != nil {
+		return err
+	}
+	deployedWorkflow.Associations, err = getWorkflowAssociations(workflowName, associations)
+	if err != nil {
+		return err
+	}
+	if !utils.FORCE_IMPORT && isWorkflowEqual(resolvedWorkflow, deployedWorkflow) {
+		log.Println("Workflow is unchanged. Skipping update: " + workflowName)
+		utils.UpdateImportState(utils.WORKFLOWS, workflowName, localWorkflow.content)
+		utils.UpdateSuccessSummary(utils.WORKFLOWS, workflowName, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating workflow: " + workflowName)
+	_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPut, utils.WORKFLOWS, url.PathEscape(workflowId), resolvedWorkflow)
+	if err != nil {
+		return fmt.Errorf("error when updating workflow: %s", err)
+	}
+	if err := updateAssociations(workflowId, localWorkflow.workflow.Associations, deployedWorkflow.Associations); err != nil {
+		return err
+	}
+	utils.UpdateImportState(utils.WORKFLOWS, workflowName, localWorkflow.content)
+	utils.UpdateSuccessSummary(utils.WORKFLOWS, workflowName, utils.UPDATE)
+	log.Println("Workflow updated successfully.")
+	return nil
+}
+
+func updateAssociations(workflowId string, localAssociations []workflowAssociation,
+	deployedAssociations []workflowAssociation) error {
+
+	// The associations of the workflow are matched by the name. Associations removed from the file are deleted
+	// along with the update of the workflow, similar to the other attributes of the workflow.
+	deployedAssociationsByName := make(map[string]workflowAssociation)
+	for _, association := range deployedAssociations {
+		deployedAssociationsByName[association.AssociationName] = association
+	}
+	for _, association := range localAssociations {
+		payload := map[string]interface{}{
+			"associationName":      association.AssociationName,
+			"operation":            association.Operation,
+			"workflowId":           workflowId,
+			"associationCondition": association.AssociationCondition,
+		}
+		deployedAssociation, exists := deployedAssociationsByName[association.AssociationName]
+		delete(deployedAssociationsByName, association.AssociationName)
+		if exists {
+			if normalizeAssociation(deployedAssociation) == association {
+				continue
+			}
+			payload["isEnabled"] = association.IsEnabled
+			_, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.WORKFLOW_ASSOCIATIONS,
+				url.PathEscape(deployedAssociation.Id), payload)
+			if err != nil {
+				return fmt.Errorf("error when updating the workflow association: %s. %s", association.AssociationName, err)
+			}
+			continue
+		}
+
+		// New associations are enabled by the server, so a disabled association is disabled after it is created.
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPost, utils.WORKFLOW_ASSOCIATIONS, "", payload)
+		if err != nil {
+			return fmt.Errorf("error when creating the workflow association: %s. %s", association.AssociationName, err)
+		}
+		if association.IsEnabled {
+			continue
+		}
+		var createdAssociation workflowAssociation
+		if err := json.Unmarshal(body, &createdAssociation); err != nil || createdAssociation.Id == "" {
+			return fmt.Errorf("error when reading the id of the created workflow association: %s", string(body))
+		}
+		_, err = utils.SendJsonRequest(utils.GetRequestContext(), http.MethodPatch, utils.WORKFLOW_ASSOCIATIONS,
+			url.PathEscape(createdAssociation.Id), map[string]interface{}{"isEnabled": false})
+		if err != nil {
+			return fmt.Errorf("error when disabling the workflow association: %s. %s", association.AssociationName, err)
+		}
+	}
+	for _, association := range deployedAssociationsByName {
+		log.Println("Workflow association not found locally. Deleting association: ", association.AssociationName)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), url.PathEscape(association.Id), utils.WORKFLOW_ASSOCIATIONS)
+		if err != nil {
+			return fmt.Errorf("error when deleting the workflow association: %s. %s", association.AssociationName, err)
+		}
+	}
+	return nil
+}
+
+func removeDeletedDeployedWorkflows(deployedWorkflows []workflow, localWorkflows []localWorkflow) {
+
+	// Remove deployed workflows that do not exist locally. The server removes the associations of the workflows.
+	var workflowsToDelete []workflow
+deployedResources:
+	for _, workflow := range deployedWorkflows {
+		for _, localWorkflow := range localWorkflows {
+			if workflow.Name == localWorkflow.workflow.Name {
+				continue deployedResources
+			}
+		}
+		if utils.IsResourceExcluded(workflow.Name, utils.TOOL_CONFIGS.WorkflowConfigs) {
+			log.Printf("Workflow: %s is excluded from deletion.\n", workflow.Name)
+			continue
+		}
+		workflowsToDelete = append(workflowsToDelete, workflow)
+	}
+
+	var workflowNames []string
+	for _, workflow := range workflowsToDelete {
+		workflowNames = append(workflowNames, workflow.Name)
+	}
+	if !utils.ConfirmDeletion(utils.WORKFLOWS, workflowNames) {
+		return
+	}
+	for _, workflow := range workflowsToDelete {
+		log.Println("Workflow not found locally. Deleting workflow: ", workflow.Name)
+		utils.EmitResourceStarted(utils.WORKFLOWS, workflow.Name, utils.DELETE)
+		err := utils.SendDeleteRequest(utils.GetRequestContext(), url.PathEscape(workflow.Id), utils.WORKFLOWS)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.WORKFLOWS, workflow.Name)
+			log.Println("Error deleting workflow: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.WORKFLOWS, workflow.Name, utils.DELETE)
+	}
+}
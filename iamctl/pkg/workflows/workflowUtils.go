@@ -0,0 +1,405 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package workflows
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/users"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const WORKFLOW_LIST_PAGE_SIZE = 100
+
+// Entities that can approve a step of a workflow.
+const APPROVER_ROLES = "roles"
+const APPROVER_USERS = "users"
+
+type workflowStepOption struct {
+	Entity string   `json:"entity" yaml:"entity"`
+	Values []string `json:"values" yaml:"values"`
+}
+
+type workflowStep struct {
+	Step    int                  `json:"step" yaml:"step"`
+	Options []workflowStepOption `json:"options" yaml:"options"`
+}
+
+type workflowTemplate struct {
+	Name  string         `json:"name" yaml:"name"`
+	Steps []workflowStep `json:"steps" yaml:"steps"`
+}
+
+type workflowAssociation struct {
+	Id                   string `json:"id,omitempty" yaml:"-"`
+	AssociationName      string `json:"associationName" yaml:"associationName"`
+	Operation            string `json:"operation" yaml:"operation"`
+	WorkflowName         string `json:"workflowName,omitempty" yaml:"-"`
+	AssociationCondition string `json:"associationCondition,omitempty" yaml:"associationCondition,omitempty"`
+	IsEnabled            bool   `json:"isEnabled" yaml:"isEnabled"`
+}
+
+// The associations of a workflow are managed by a separate API, but are kept in the file of the workflow.
+type workflow struct {
+	Id           string                `json:"id,omitempty" yaml:"-"`
+	Name         string                `json:"name" yaml:"name"`
+	Description  string                `json:"description,omitempty" yaml:"description,omitempty"`
+	Engine       string                `json:"engine" yaml:"engine"`
+	Template     workflowTemplate      `json:"template" yaml:"template"`
+	Associations []workflowAssociation `json:"-" yaml:"associations"`
+}
+
+type workflowListResponse struct {
+	TotalResults int        `json:"totalResults"`
+	Workflows    []workflow `json:"workflows"`
+}
+
+type associationListResponse struct {
+	TotalResults         int                   `json:"totalResults"`
+	WorkflowAssociations []workflowAssociation `json:"workflowAssociations"`
+}
+
+// Approvers of the workflow steps are referred by the ids in the server, which differ between environments.
+// The roles are kept in the local files by the role keys and the users by the usernames.
+type approverReferences struct {
+	roleKeys  map[string]string
+	userNames map[string]string
+	userIds   map[string]string
+}
+
+func getWorkflowList() ([]workflow, error) {
+
+	var workflows []workflow
+	offset := 0
+	for {
+		query := url.Values{}
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(WORKFLOW_LIST_PAGE_SIZE))
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.WORKFLOWS, "?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving workflow list. %w", err)
+		}
+		var response workflowListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved workflow list. %w", err)
+		}
+
+		workflows = append(workflows, response.Workflows...)
+		offset += len(response.Workflows)
+		if len(response.Workflows) == 0 || offset >= response.TotalResults {
+			return workflows, nil
+		}
+	}
+}
+
+func getWorkflow(workflowId string) (workflow, error) {
+
+	var workflowDetails workflow
+	body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.WORKFLOWS, url.PathEscape(workflowId), nil)
+	if err != nil {
+		return workflowDetails, fmt.Errorf("error while retrieving the workflow. %w", err)
+	}
+	err = json.Unmarshal(body, &workflowDetails)
+	if err != nil {
+		return workflowDetails, fmt.Errorf("error when unmarshalling the retrieved workflow. %w", err)
+	}
+	return workflowDetails, nil
+}
+
+func getAssociationList() ([]workflowAssociation, error) {
+
+	var associations []workflowAssociation
+	offset := 0
+	for {
+		query := url.Values{}
+		query.Set("offset", strconv.Itoa(offset))
+		query.Set("limit", strconv.Itoa(WORKFLOW_LIST_PAGE_SIZE))
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.WORKFLOW_ASSOCIATIONS, "?"+query.Encode(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving workflow association list. %w", err)
+		}
+		var response associationListResponse
+		err = json.Unmarshal(body, &response)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved workflow association list. %w", err)
+		}
+
+		associations = append(associations, response.WorkflowAssociations...)
+		offset += len(response.WorkflowAssociations)
+		if len(response.WorkflowAssociations) == 0 || offset >= response.TotalResults {
+			return associations, nil
+		}
+	}
+}
+
+func getWorkflowAssociations(workflowName string, associations []workflowAssociation) ([]workflowAssociation, error) {
+
+	// The association list does not contain the conditions, so the associations of the workflow are retrieved separately.
+	var workflowAssociations []workflowAssociation
+	for _, association := range associations {
+		if association.WorkflowName != workflowName {
+			continue
+		}
+		body, err := utils.SendJsonRequest(utils.GetRequestContext(), http.MethodGet, utils.WORKFLOW_ASSOCIATIONS, url.PathEscape(association.Id), nil)
+		if err != nil {
+			return nil, fmt.Errorf("error while retrieving the workflow association: %s. %w", association.AssociationName, err)
+		}
+		var associationDetails workflowAssociation
+		err = json.Unmarshal(body, &associationDetails)
+		if err != nil {
+			return nil, fmt.Errorf("error when unmarshalling the retrieved workflow association. %w", err)
+		}
+		associationDetails.Id = association.Id
+		workflowAssociations = append(workflowAssociations, associationDetails)
+	}
+	sort.SliceStable(workflowAssociations, func(i, j int) bool {
+		return workflowAssociations[i].AssociationName < workflowAssociations[j].AssociationName
+	})
+	return workflowAssociations, nil
+}
+
+func newApproverReferences() (*approverReferences, error) {
+
+	roleKeys, err := roles.GetDeployedRoleKeys()
+	if err != nil {
+		return nil, err
+	}
+	return &approverReferences{
+		roleKeys:  roleKeys,
+		userNames: make(map[string]string),
+		userIds:   make(map[string]string),
+	}, nil
+}
+
+// Replace the ids of the approvers in the workflow retrieved from the server with the role keys and usernames.
+func (references *approverReferences) setApproverNames(workflow *workflow) error {
+
+	for _, step := range workflow.Template.Steps {
+		for _, option := range step.Options {
+			for i, value := range option.Values {
+				switch option.Entity {
+				case APPROVER_ROLES:
+					roleKey, ok := references.roleKeys[value]
+					if !ok {
+						return fmt.Errorf("approver role with the id: %s is not found", value)
+					}
+					option.Values[i] = roleKey
+				case APPROVER_USERS:
+					userName, err := references.getUserName(value)
+					if err != nil {
+						return err
+					}
+					option.Values[i] = userName
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Get a copy of the local workflow with the approvers replaced by their ids in the target environment.
+// The workflow is not imported if an approver, or the application of an approver role, does not exist.
+func (references *approverReferences) getWorkflowWithApproverIds(localWorkflow workflow) (workflow, error) {
+
+	roleIds := make(map[string]string)
+	for roleId, roleKey := range references.roleKeys {
+		roleIds[roleKey] = roleId
+	}
+	resolvedWorkflow := localWorkflow
+	resolvedWorkflow.Template.Steps = nil
+	var missingRoles []string
+	for _, step := range localWorkflow.Template.Steps {
+		resolvedStep := workflowStep{Step: step.Step}
+		for _, option := range step.Options {
+			resolvedOption := workflowStepOption{Entity: option.Entity}
+			for _, value := range option.Values {
+				switch option.Entity {
+				case APPROVER_ROLES:
+					roleId, ok := roleIds[value]
+					if !ok {
+						missingRoles = append(missingRoles, value)
+					}
+					value = roleId
+				case APPROVER_USERS:
+					userId, err := references.getUserId(value)
+					if err != nil {
+						return resolvedWorkflow, err
+					}
+					value = userId
+				}
+				resolvedOption.Values = append(resolvedOption.Values, value)
+			}
+			resolvedStep.Options = append(resolvedStep.Options, resolvedOption)
+		}
+		resolvedWorkflow.Template.Steps = append(resolvedWorkflow.Template.Steps, resolvedStep)
+	}
+	if len(missingRoles) > 0 {
+		return resolvedWorkflow, getMissingRolesError(missingRoles)
+	}
+	return resolvedWorkflow, nil
+}
+
+func getMissingRolesError(missingRoles []string) error {
+
+	// Roles of an application audience are only available after the application is imported,
+	// so the missing applications are reported separately.
+	deployedAppIds, err := applications.GetDeployedAppIds()
+	if err != nil {
+		return fmt.Errorf("approver roles: %s are not found. %s", strings.Join(missingRoles, ", "), err)
+	}
+	var missingApps []string
+	for _, roleKey := range missingRoles {
+		if !strings.Contains(roleKey, "/") {
+			continue
+		}
+		isAppDeployed := false
+		for appName := range deployedAppIds {
+			if strings.HasPrefix(roleKey, appName+"/") {
+				isAppDeployed = true
+				break
+			}
+		}
+		if !isAppDeployed {
+			missingApps = append(missingApps, roleKey)
+		}
+	}
+	if len(missingApps) > 0 {
+		return fmt.Errorf("applications of the approver roles: %s are not found", strings.Join(missingApps, ", "))
+	}
+	return fmt.Errorf("approver roles: %s are not found", strings.Join(missingRoles, ", "))
+}
+
+func (references *approverReferences) getUserName(userId string) (string, error) {
+
+	if userName, ok := references.userNames[userId]; ok {
+		return userName, nil
+	}
+	userName, err := users.GetUserName(userId)
+	if err != nil {
+		return "", fmt.Errorf("error when retrieving the approver user with the id: %s. %s", userId, err)
+	}
+	references.userNames[userId] = userName
+	return userName, nil
+}
+
+func (references *approverReferences) getUserId(userName string) (string, error) {
+
+	if userId, ok := references.userIds[userName]; ok {
+		return userId, nil
+	}
+	userId, err := users.GetUserId(userName)
+	if err != nil {
+		return "", fmt.Errorf("error when retrieving the approver user: %s. %s", userName, err)
+	}
+	if userId == "" {
+		return "", fmt.Errorf("approver user: %s is not found", userName)
+	}
+	references.userIds[userName] = userId
+	return userId, nil
+}
+
+func isWorkflowEqual(localWorkflow workflow, deployedWorkflow workflow) bool {
+
+	localWorkflow, deployedWorkflow = normalizeWorkflow(localWorkflow), normalizeWorkflow(deployedWorkflow)
+	return reflect.DeepEqual(localWorkflow, deployedWorkflow)
+}
+
+// Get a copy of the workflow that can be compared, without the server generated fields and with the approvers sorted.
+func normalizeWorkflow(workflow workflow) workflow {
+
+	normalized := workflow
+	normalized.Id = ""
+	normalized.Template.Steps = nil
+	for _, step := range workflow.Template.Steps {
+		normalizedStep := workflowStep{Step: step.Step}
+		for _, option := range step.Options {
+			values := append([]string{}, option.Values...)
+			sort.Strings(values)
+			normalizedStep.Options = append(normalizedStep.Options, workflowStepOption{Entity: option.Entity, Values: values})
+		}
+		sort.SliceStable(normalizedStep.Options, func(i, j int) bool {
+			return normalizedStep.Options[i].Entity < normalizedStep.Options[j].Entity
+		})
+		normalized.Template.Steps = append(normalized.Template.Steps, normalizedStep)
+	}
+	normalized.Associations = nil
+	for _, association := range workflow.Associations {
+		normalized.Associations = append(normalized.Associations, normalizeAssociation(association))
+	}
+	sort.SliceStable(normalized.Associations, func(i, j int) bool {
+		return normalized.Associations[i].AssociationName < normalized.Associations[j].AssociationName
+	})
+	return normalized
+}
+
+func normalizeAssociation(association workflowAssociation) workflowAssociation {
+
+	association.Id = ""
+	association.WorkflowName = ""
+	return association
+}
+
+func getWorkflowKeywordMapping(workflowName string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(workflowName, utils.KEYWORD_CONFIGS.WorkflowConfigs)
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	localWorkflowNames, err := utils.GetLocalResourceNames(filepath.Join(inputDirPath, utils.WORKFLOWS),
+		utils.TOOL_CONFIGS.WorkflowConfigs)
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	workflows, err := getWorkflowList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedWorkflows []utils.DeployedResource
+	for _, workflow := range workflows {
+		isDeletable := !utils.IsResourceExcluded(workflow.Name, utils.TOOL_CONFIGS.WorkflowConfigs)
+		deployedWorkflows = append(deployedWorkflows, utils.DeployedResource{Name: workflow.Name, Deletable: isDeletable})
+	}
+	return utils.NewImportPlan(utils.WORKFLOWS, localWorkflowNames, deployedWorkflows, utils.IsDeleteAllowed()), nil
+}
+
+// Get the local files of the workflows that do not exist in the target environment.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	workflows, err := getWorkflowList()
+	if err != nil {
+		return nil, err
+	}
+	var workflowNames []string
+	for _, workflow := range workflows {
+		workflowNames = append(workflowNames, workflow.Name)
+	}
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.WORKFLOWS), workflowNames)
+}
@@ -0,0 +1,109 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package xacmlpolicies
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func ExportAll(exportFilePath string) error {
+
+	// Export each policy to a separate XML file in the XacmlPolicies folder, along with a manifest of the policies.
+	log.Println("Exporting XACML policies...")
+	exportFilePath = filepath.Join(exportFilePath, utils.XACML_POLICIES)
+
+	if _, err := os.Stat(exportFilePath); os.IsNotExist(err) {
+		os.MkdirAll(exportFilePath, 0700)
+	}
+
+	policyIds, err := getPolicyIdList()
+	if err != nil {
+		return err
+	}
+	manifestFilePath := filepath.Join(exportFilePath, utils.XACML_POLICY_MANIFEST_FILE)
+	if utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+		manifestName := utils.GetFileInfo(manifestFilePath).ResourceName
+		utils.RemoveDeletedLocalResources(exportFilePath, append([]string{manifestName}, policyIds...))
+	}
+
+	// Keep the manifest entries of the policies that are not exported in this run, such as the skipped policies.
+	manifestEntries := make(map[string]PolicyManifestEntry)
+	if content, err := utils.ReadResourceFile(manifestFilePath); err == nil {
+		if entries, err := ParsePolicyManifest(content); err == nil {
+			for name, entry := range entries {
+				if utils.Contains(policyIds, name) {
+					manifestEntries[name] = entry
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(policyIds, func(i, j int) bool {
+		return utils.GetResourcePriority(policyIds[i]) < utils.GetResourcePriority(policyIds[j])
+	})
+	utils.StartResourceProgress(utils.XACML_POLICIES, len(policyIds))
+	for _, policyId := range policyIds {
+		if utils.IsResourceExcluded(policyId, utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+			utils.ReportSkippedResource(utils.XACML_POLICIES, policyId, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.XACML_POLICIES, policyId)
+			continue
+		}
+		log.Println("Exporting XACML policy: ", policyId)
+		utils.EmitResourceStarted(utils.XACML_POLICIES, policyId, utils.EXPORT)
+		policy, err := getPolicy(policyId)
+		if err == nil && !utils.IsModifiedSince(policy.LastModifiedTime) {
+			log.Println("XACML policy is not modified since the given time. Skipping policy: ", policyId)
+			manifestEntries[policyId] = PolicyManifestEntry{Name: policyId, Version: policy.Version, Enabled: policy.Active}
+			utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.UNCHANGED)
+			continue
+		}
+		if err == nil {
+			err = exportPolicy(policy, exportFilePath)
+		}
+		if err != nil {
+			utils.UpdateFailureSummary(utils.XACML_POLICIES, policyId)
+			log.Printf("Error while exporting XACML policy: %s. %s", policyId, err)
+			continue
+		}
+		manifestEntries[policyId] = PolicyManifestEntry{Name: policyId, Version: policy.Version, Enabled: policy.Active}
+		utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.EXPORT)
+		log.Println("XACML policy exported successfully: ", policyId)
+	}
+
+	manifest, err := buildPolicyManifest(manifestEntries)
+	if err != nil {
+		return fmt.Errorf("error while creating the XACML policy manifest: %s", err)
+	}
+	return utils.WriteExportedFile(manifestFilePath, manifest)
+}
+
+func exportPolicy(policy policy, outputDirPath string) error {
+
+	exportedFileName := filepath.Join(outputDirPath, policy.PolicyId+".xml")
+	return utils.WriteExportedFile(exportedFileName, []byte(policy.Policy))
+}
@@ -0,0 +1,210 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package xacmlpolicies
+
+import (
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func ImportAll(inputDirPath string) {
+
+	importFilePath := filepath.Join(inputDirPath, utils.XACML_POLICIES)
+	if _, err := os.Stat(importFilePath); os.IsNotExist(err) {
+		return
+	}
+	if utils.IsResourceTypeExcluded(utils.XACML_POLICIES) {
+		return
+	}
+
+	log.Println("Importing XACML policies...")
+	files, err := ioutil.ReadDir(importFilePath)
+	if err != nil {
+		log.Println("Error importing XACML policies: ", err)
+		return
+	}
+
+	// Policies that are not listed in the manifest are enabled by default.
+	manifestEntries := make(map[string]PolicyManifestEntry)
+	manifestFilePath := filepath.Join(importFilePath, utils.XACML_POLICY_MANIFEST_FILE)
+	if utils.ResourceFileExists(manifestFilePath) {
+		content, err := utils.ReadResourceFile(manifestFilePath)
+		if err == nil {
+			manifestEntries, err = ParsePolicyManifest(content)
+		}
+		if err != nil {
+			log.Println("Error importing XACML policies. Invalid policy manifest: ", err)
+			return
+		}
+	}
+
+	deployedPolicyIds, err := getPolicyIdList()
+	if err != nil {
+		log.Println("Error importing XACML policies: ", err)
+		return
+	}
+	if utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+		removeDeletedDeployedPolicies(deployedPolicyIds, files)
+	}
+
+	sort.SliceStable(files, func(i, j int) bool {
+		return utils.GetResourcePriority(files[i].Name()) < utils.GetResourcePriority(files[j].Name())
+	})
+	utils.StartResourceProgress(utils.XACML_POLICIES, len(files))
+	for _, file := range files {
+		if file.IsDir() || !isPolicyFile(file.Name()) {
+			continue
+		}
+		policyId := utils.GetFileInfo(file.Name()).ResourceName
+		if !utils.IsResourceIncluded(policyId) {
+			utils.AddFilteredResourceToSummary(utils.XACML_POLICIES, policyId)
+			continue
+		}
+		if utils.IsResourceExcluded(policyId, utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+			utils.ReportSkippedResource(utils.XACML_POLICIES, policyId, utils.JUNIT_SKIPPED_EXCLUDED)
+			continue
+		}
+		if !utils.IsTimeBudgetAvailable() {
+			utils.AddUnprocessedResourceToSummary(utils.XACML_POLICIES, policyId)
+			continue
+		}
+		enabled := true
+		if entry, ok := manifestEntries[policyId]; ok {
+			enabled = entry.Enabled
+		}
+		utils.EmitResourceStarted(utils.XACML_POLICIES, policyId, utils.IMPORT)
+		err := importPolicy(policyId, filepath.Join(importFilePath, file.Name()), enabled,
+			utils.Contains(deployedPolicyIds, policyId))
+		if err != nil {
+			utils.UpdateFailureSummary(utils.XACML_POLICIES, policyId)
+			log.Printf("Error when importing XACML policy: %s. %s", policyId, err)
+		}
+	}
+}
+
+func importPolicy(policyId string, importFilePath string, enabled bool, isUpdate bool) error {
+
+	fileBytes, err := utils.ReadResourceFile(importFilePath)
+	if err != nil {
+		return fmt.Errorf("error when reading the file for XACML policy: %s", err)
+	}
+
+	// Replace keyword placeholders in the local file according to the keyword mappings added in configs.
+	keywordMapping := getXacmlPolicyKeywordMapping(policyId)
+	modifiedFileData := utils.ReplaceKeywords(string(fileBytes), keywordMapping)
+	utils.CheckImportContent(utils.XACML_POLICIES, policyId, modifiedFileData)
+
+	contentPolicyId, err := GetPolicyId(modifiedFileData)
+	if err != nil {
+		return fmt.Errorf("invalid file content for XACML policy: %s", err)
+	}
+	if contentPolicyId != policyId {
+		return fmt.Errorf("policy ID: %s in the file is not matching with the file name", contentPolicyId)
+	}
+	importState := modifiedFileData + "\nenabled: " + strconv.FormatBool(enabled)
+
+	if !isUpdate {
+		log.Println("Creating new XACML policy: " + policyId)
+		err = addOrUpdatePolicy("addPolicy", policyId, modifiedFileData, enabled)
+		if err != nil {
+			return fmt.Errorf("error when creating XACML policy: %s", err)
+		}
+		utils.UpdateImportState(utils.XACML_POLICIES, policyId, importState)
+		utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.IMPORT)
+		log.Println("XACML policy created successfully.")
+		return nil
+	}
+
+	if utils.IsImportStateUnchanged(utils.XACML_POLICIES, policyId, importState) {
+		log.Println("XACML policy is unchanged since the last import. Skipping update: " + policyId)
+		utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.UNCHANGED)
+		return nil
+	}
+	deployedPolicy, deployedErr := getPolicy(policyId)
+	if deployedErr == nil && !utils.FORCE_IMPORT && deployedPolicy.Active == enabled &&
+		strings.TrimSpace(deployedPolicy.Policy) == strings.TrimSpace(modifiedFileData) {
+		log.Println("XACML policy is unchanged. Skipping update: " + policyId)
+		utils.UpdateImportState(utils.XACML_POLICIES, policyId, importState)
+		utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.UNCHANGED)
+		return nil
+	}
+
+	log.Println("Updating XACML policy: " + policyId)
+	err = addOrUpdatePolicy("updatePolicy", policyId, modifiedFileData, enabled)
+	if err != nil {
+		return fmt.Errorf("error when updating XACML policy: %s", err)
+	}
+	// The enabled state of an existing policy is not changed by the update operation.
+	if deployedErr != nil || deployedPolicy.Active != enabled {
+		err = setPolicyEnabled(policyId, enabled)
+		if err != nil {
+			return fmt.Errorf("error when changing the enabled state of XACML policy: %s", err)
+		}
+	}
+	utils.UpdateImportState(utils.XACML_POLICIES, policyId, importState)
+	utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.UPDATE)
+	log.Println("XACML policy updated successfully.")
+	return nil
+}
+
+func removeDeletedDeployedPolicies(deployedPolicyIds []string, localFiles []os.FileInfo) {
+
+	// Remove deployed policies that do not exist locally.
+	var policiesToDelete []string
+deployedResources:
+	for _, policyId := range deployedPolicyIds {
+		for _, file := range localFiles {
+			if isPolicyFile(file.Name()) && policyId == utils.GetFileInfo(file.Name()).ResourceName {
+				continue deployedResources
+			}
+		}
+		if utils.IsResourceExcluded(policyId, utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+			continue
+		}
+		policiesToDelete = append(policiesToDelete, policyId)
+	}
+
+	if !utils.ConfirmDeletion(utils.XACML_POLICIES, policiesToDelete) {
+		return
+	}
+	for _, policyId := range policiesToDelete {
+		log.Println("XACML policy not found locally. Deleting XACML policy: ", policyId)
+		utils.EmitResourceStarted(utils.XACML_POLICIES, policyId, utils.DELETE)
+		err := removePolicy(policyId)
+		if err != nil {
+			utils.UpdateFailureSummary(utils.XACML_POLICIES, policyId)
+			log.Println("Error deleting XACML policy: ", err)
+			continue
+		}
+		utils.UpdateSuccessSummary(utils.XACML_POLICIES, policyId, utils.DELETE)
+	}
+}
+
+func isPolicyFile(fileName string) bool {
+
+	return strings.ToLower(utils.GetFileInfo(fileName).FileExtension) == ".xml"
+}
@@ -0,0 +1,227 @@
+/**
+* Copyright (c) 2023, WSO2 LLC. (https://www.wso2.com) All Rights Reserved.
+*
+* WSO2 LLC. licenses this file to you under the Apache License,
+* Version 2.0 (the "License"); you may not use this file except
+* in compliance with the License.
+* You may obtain a copy of the License at
+*
+* http://www.apache.org/licenses/LICENSE-2.0
+*
+* Unless required by applicable law or agreed to in writing,
+* software distributed under the License is distributed on an
+* "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+* KIND, either express or implied. See the License for the
+* specific language governing permissions and limitations
+* under the License.
+ */
+
+package xacmlpolicies
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const policyAdminService = "EntitlementPolicyAdminService"
+const serviceNamespace = "http://org.apache.axis2/xsd"
+const dtoNamespace = "http://dto.entitlement.identity.carbon.wso2.org/xsd"
+
+type policy struct {
+	PolicyId         string `xml:"policyId"`
+	Policy           string `xml:"policy"`
+	Version          string `xml:"version"`
+	Active           bool   `xml:"active"`
+	LastModifiedTime string `xml:"lastModifiedTime"`
+}
+
+type PolicyManifest struct {
+	Policies []PolicyManifestEntry `yaml:"policies"`
+}
+
+type PolicyManifestEntry struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	Enabled bool   `yaml:"enabled"`
+}
+
+func getPolicyIdList() ([]string, error) {
+
+	var response struct {
+		PolicyIds []string `xml:"Body>getAllPolicyIdsResponse>return"`
+	}
+	body, err := sendPolicyAdminRequest("getAllPolicyIds", "<xsd:searchString>*</xsd:searchString>")
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving XACML policy list. %w", err)
+	}
+	err = xml.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("error when unmarshalling the retrieved XACML policy list. %w", err)
+	}
+	return response.PolicyIds, nil
+}
+
+func getPolicy(policyId string) (policy, error) {
+
+	var response struct {
+		Policy policy `xml:"Body>getPolicyResponse>return"`
+	}
+	body, err := sendPolicyAdminRequest("getPolicy",
+		"<xsd:policyId>"+escapeXml(policyId)+"</xsd:policyId><xsd:isPDPPolicy>false</xsd:isPDPPolicy>")
+	if err != nil {
+		return policy{}, fmt.Errorf("error while retrieving XACML policy: %s. %w", policyId, err)
+	}
+	err = xml.Unmarshal(body, &response)
+	if err != nil {
+		return policy{}, fmt.Errorf("error when unmarshalling the retrieved XACML policy: %s. %w", policyId, err)
+	}
+	return response.Policy, nil
+}
+
+func addOrUpdatePolicy(operation string, policyId string, policyContent string, enabled bool) error {
+
+	// Policies are published to the PDP when they are added or updated, so that they are evaluated at runtime.
+	policyDTO := "<xsd:policyDTO>" +
+		"<xsd1:active>" + strconv.FormatBool(enabled) + "</xsd1:active>" +
+		"<xsd1:policy>" + escapeXml(policyContent) + "</xsd1:policy>" +
+		"<xsd1:policyId>" + escapeXml(policyId) + "</xsd1:policyId>" +
+		"<xsd1:promote>true</xsd1:promote>" +
+		"</xsd:policyDTO>"
+	_, err := sendPolicyAdminRequest(operation, policyDTO)
+	return err
+}
+
+func setPolicyEnabled(policyId string, enabled bool) error {
+
+	_, err := sendPolicyAdminRequest("enableDisablePolicy",
+		"<xsd:policyId>"+escapeXml(policyId)+"</xsd:policyId><xsd:enable>"+strconv.FormatBool(enabled)+"</xsd:enable>")
+	return err
+}
+
+func removePolicy(policyId string) error {
+
+	_, err := sendPolicyAdminRequest("removePolicy",
+		"<xsd:policyId>"+escapeXml(policyId)+"</xsd:policyId><xsd:dePromote>true</xsd:dePromote>")
+	return err
+}
+
+func sendPolicyAdminRequest(operation string, params string) ([]byte, error) {
+
+	payload := fmt.Sprintf(`<xsd:%s xmlns:xsd="%s" xmlns:xsd1="%s">%s</xsd:%s>`,
+		operation, serviceNamespace, dtoNamespace, params, operation)
+	return utils.SendSoapRequest(utils.GetRequestContext(), policyAdminService, operation, payload)
+}
+
+// Get the ID of the policy or policy set defined in the XACML policy content.
+func GetPolicyId(policyContent string) (string, error) {
+
+	decoder := xml.NewDecoder(strings.NewReader(policyContent))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return "", fmt.Errorf("policy element is not found")
+		} else if err != nil {
+			return "", err
+		}
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range element.Attr {
+			if attr.Name.Local == "PolicyId" || attr.Name.Local == "PolicySetId" {
+				return attr.Value, nil
+			}
+		}
+		return "", fmt.Errorf("policy ID is not defined in the %s element", element.Name.Local)
+	}
+}
+
+func ParsePolicyManifest(content []byte) (map[string]PolicyManifestEntry, error) {
+
+	var manifest PolicyManifest
+	err := yaml.Unmarshal(content, &manifest)
+	if err != nil {
+		return nil, err
+	}
+	entries := make(map[string]PolicyManifestEntry)
+	for _, entry := range manifest.Policies {
+		entries[entry.Name] = entry
+	}
+	return entries, nil
+}
+
+func buildPolicyManifest(entries map[string]PolicyManifestEntry) ([]byte, error) {
+
+	var manifest PolicyManifest
+	for _, entry := range entries {
+		manifest.Policies = append(manifest.Policies, entry)
+	}
+	sort.SliceStable(manifest.Policies, func(i, j int) bool {
+		return manifest.Policies[i].Name < manifest.Policies[j].Name
+	})
+	return yaml.Marshal(manifest)
+}
+
+func getXacmlPolicyKeywordMapping(policyId string) map[string]interface{} {
+
+	return utils.ResolveAdvancedKeywordMapping(policyId, utils.KEYWORD_CONFIGS.XacmlPolicyConfigs)
+}
+
+func escapeXml(value string) string {
+
+	var buf bytes.Buffer
+	xml.EscapeText(&buf, []byte(value))
+	return buf.String()
+}
+
+func GetImportPlan(inputDirPath string) (utils.ImportPlan, error) {
+
+	files, err := ioutil.ReadDir(filepath.Join(inputDirPath, utils.XACML_POLICIES))
+	if err != nil && !os.IsNotExist(err) {
+		return utils.ImportPlan{}, fmt.Errorf("error when reading the XACML policies: %s", err)
+	}
+	var localPolicyIds []string
+	for _, file := range files {
+		policyId := utils.GetFileInfo(file.Name()).ResourceName
+		if file.IsDir() || !isPolicyFile(file.Name()) || !utils.IsResourceIncluded(policyId) ||
+			utils.IsResourceExcluded(policyId, utils.TOOL_CONFIGS.XacmlPolicyConfigs) {
+			continue
+		}
+		localPolicyIds = append(localPolicyIds, policyId)
+	}
+	policyIds, err := getPolicyIdList()
+	if err != nil {
+		return utils.ImportPlan{}, err
+	}
+	var deployedPolicies []utils.DeployedResource
+	for _, policyId := range policyIds {
+		deployedPolicies = append(deployedPolicies, utils.DeployedResource{
+			Name:      policyId,
+			Deletable: !utils.IsResourceExcluded(policyId, utils.TOOL_CONFIGS.XacmlPolicyConfigs),
+		})
+	}
+	return utils.NewImportPlan(utils.XACML_POLICIES, localPolicyIds, deployedPolicies,
+		utils.IsResourceTypeDeleteAllowed(utils.TOOL_CONFIGS.XacmlPolicyConfigs)), nil
+}
+
+// Get the local files of the XACML policies that do not exist in the target environment. The manifest is kept.
+func GetStaleLocalFiles(inputDirPath string) ([]string, error) {
+
+	policyIds, err := getPolicyIdList()
+	if err != nil {
+		return nil, err
+	}
+	manifestName := utils.GetFileInfo(utils.XACML_POLICY_MANIFEST_FILE).ResourceName
+	return utils.GetStaleLocalFiles(filepath.Join(inputDirPath, utils.XACML_POLICIES), append([]string{manifestName}, policyIds...))
+}
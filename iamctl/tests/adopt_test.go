@@ -0,0 +1,129 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const adoptedAppName = "crm/eu"
+
+// Start a mock server with a deployed application that is not managed in the local directory.
+func newAdoptServer(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		switch {
+		case r.Method == "GET" && path == "/applications/":
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "crm/eu"}]}`))
+		case r.Method == "GET" && path == "/applications/app-1/exportFile":
+			w.Header().Set("Content-Disposition", `attachment; filename="crm/eu.yml"`)
+			w.Write([]byte("applicationName: crm/eu\ndescription: CRM of the dev team\n" +
+				"inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundAuthKey: crm_client\n    inboundAuthType: oauth2\n" +
+				"    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      callbackUrl: https://crm.dev.example.com/callback\n      oauthConsumerKey: crm_client\n" +
+				"localAndOutBoundAuthenticationConfig:\n  authenticationScriptConfig:\n" +
+				"    content: 'var onLoginRequest = function(context) { executeStep(1); };'\n"))
+		case r.Method == "GET" && strings.HasPrefix(path, "/applications/app-1"):
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{
+		"ENV":          "dev",
+		"CALLBACK_URL": "https://crm.dev.example.com/callback",
+	}}
+	t.Cleanup(func() { utils.LoadImportState("") })
+}
+
+func TestAdoptApplicationDryRun(t *testing.T) {
+
+	newAdoptServer(t)
+	tempDir := newTempDir(t)
+
+	// A dry run does not write any file.
+	if err := applications.Adopt(adoptedAppName, tempDir, "yaml", true); err != nil {
+		t.Fatalf("Unexpected error when adopting the application in a dry run: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, utils.APPLICATIONS)); !os.IsNotExist(err) {
+		t.Fatalf("Expected no files to be written in a dry run")
+	}
+}
+
+func TestAdoptApplication(t *testing.T) {
+
+	newAdoptServer(t)
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "crm%2Feu.yml")
+
+	if err := applications.Adopt(adoptedAppName, tempDir, "yaml", false); err != nil {
+		t.Fatalf("Unexpected error when adopting the application: %s", err)
+	}
+	content, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Expected the application file to be written with a valid file name: %s", err)
+	}
+	for _, expected := range []string{"callbackUrl: '{{CALLBACK_URL}}'", "description: CRM of the dev team",
+		"!!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO", "file://crm%2Feu" + utils.AUTH_SCRIPT_FILE_SUFFIX} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected the adopted file to contain %q but got:\n%s", expected, content)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, utils.APPLICATIONS, "crm%2Feu"+utils.AUTH_SCRIPT_FILE_SUFFIX)); err != nil {
+		t.Errorf("Expected the auth script to be written to a separate file: %s", err)
+	}
+	if resolvedName := utils.ResolveResourceName(appFilePath); resolvedName != adoptedAppName {
+		t.Errorf("Expected the adopted file to resolve to %q but got %q", adoptedAppName, resolvedName)
+	}
+
+	// The adopted application is recorded in the import state of the target environment.
+	stateContent, err := ioutil.ReadFile(filepath.Join(tempDir, utils.IMPORT_STATE_DIR, utils.IMPORT_STATE_FILE))
+	if err != nil {
+		t.Fatalf("Expected the import state to be written: %s", err)
+	}
+	var state utils.ImportState
+	if err := json.Unmarshal(stateContent, &state); err != nil {
+		t.Fatalf("Unexpected error when reading the import state: %s", err)
+	}
+	environmentKey := utils.GetTenantUrl()
+	if _, ok := state.Environments[environmentKey][utils.APPLICATIONS][adoptedAppName]; !ok {
+		t.Errorf("Expected the hash of the adopted application in the import state but got: %s", stateContent)
+	}
+	utils.LoadImportState(tempDir)
+	if !utils.IsResourceAdopted(utils.APPLICATIONS, adoptedAppName) {
+		t.Errorf("Expected the application to be marked as adopted")
+	}
+}
+
+func TestAdoptManagedApplication(t *testing.T) {
+
+	newAdoptServer(t)
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "crm%2Feu.yml")
+	if err := applications.Adopt(adoptedAppName, tempDir, "yaml", false); err != nil {
+		t.Fatalf("Unexpected error when adopting the application: %s", err)
+	}
+	content, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Expected the application file to be written: %s", err)
+	}
+
+	// Adopting the application again fails without changing the file.
+	err = applications.Adopt(adoptedAppName, tempDir, "yaml", false)
+	if err == nil || !strings.Contains(err.Error(), "a managed file already exists") {
+		t.Errorf("Expected an error for an application with a managed file but got: %v", err)
+	}
+	if newContent, _ := ioutil.ReadFile(appFilePath); string(newContent) != string(content) {
+		t.Errorf("Expected the managed file not to be changed")
+	}
+}
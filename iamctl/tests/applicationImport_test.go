@@ -0,0 +1,170 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const oauthSamlApp = `applicationName: two-phase-app
+description: App with OAuth and SAML inbound protocols
+inboundAuthenticationConfig:
+  inboundAuthenticationRequestConfigs:
+  - inboundAuthKey: two_phase_client
+    inboundAuthType: oauth2
+    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO
+      oauthConsumerKey: two_phase_client
+      oauthVersion: OAuth-2.0
+  - inboundAuthKey: two-phase-issuer
+    inboundAuthType: samlsso
+    inboundConfigurationProtocol: !!org.wso2.carbon.identity.sso.saml.dto.SAMLSSOServiceProviderDTO
+      issuer: two-phase-issuer
+      assertionConsumerUrls:
+      - https://two-phase.example.com/acs
+`
+
+func TestApplicationImportStrategies(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		probeStatus      int
+		updateStatus     int
+		strategy         string
+		expectedRequests []string
+	}{
+		{
+			name:             "single phase when the server does not support separate inbound protocols",
+			probeStatus:      http.StatusNotFound,
+			updateStatus:     http.StatusOK,
+			expectedRequests: []string{"POST full"},
+		},
+		{
+			name:             "two phases when the server supports separate inbound protocols",
+			probeStatus:      http.StatusOK,
+			updateStatus:     http.StatusOK,
+			expectedRequests: []string{"POST base", "PUT full"},
+		},
+		{
+			name:             "rollback when adding the inbound protocol fails",
+			probeStatus:      http.StatusOK,
+			updateStatus:     http.StatusInternalServerError,
+			expectedRequests: []string{"POST base", "PUT full", "DELETE app-1"},
+		},
+		{
+			name:             "single phase when overridden in the configs",
+			probeStatus:      http.StatusOK,
+			updateStatus:     http.StatusOK,
+			strategy:         utils.SINGLE_PHASE_IMPORT,
+			expectedRequests: []string{"POST full"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var requests []string
+			created := false
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				path := r.URL.Path
+				switch {
+				case r.Method == "GET" && strings.HasSuffix(path, "/applications/meta/inbound-protocols"):
+					w.WriteHeader(test.probeStatus)
+					w.Write([]byte(`[]`))
+				case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/applications"):
+					if created {
+						w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "two-phase-app"}]}`))
+					} else {
+						w.Write([]byte(`{"totalResults": 0, "applications": []}`))
+					}
+				case strings.HasSuffix(path, "/applications/import"):
+					body, _ := ioutil.ReadAll(r.Body)
+					if !strings.Contains(string(body), "!!org.wso2.carbon.identity.sso.saml.dto.SAMLSSOServiceProviderDTO") {
+						t.Errorf("Expected the SAML inbound protocol with its type tag in the request but got:\n%s", body)
+					}
+					content := "full"
+					if !strings.Contains(string(body), "inboundAuthType: oauth2") {
+						content = "base"
+					}
+					requests = append(requests, r.Method+" "+content)
+					if r.Method == "POST" {
+						created = true
+						w.WriteHeader(http.StatusCreated)
+						return
+					}
+					w.WriteHeader(test.updateStatus)
+				case r.Method == "DELETE":
+					requests = append(requests, r.Method+" "+path[strings.LastIndex(path, "/")+1:])
+					created = false
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			utils.TOOL_CONFIGS = utils.ToolConfigs{ApplicationConfigs: map[string]interface{}{}}
+			if test.strategy != "" {
+				utils.TOOL_CONFIGS.ApplicationConfigs[utils.IMPORT_STRATEGY_CONFIG] = test.strategy
+			}
+
+			tempDir := newTempDir(t)
+			appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+			if err := os.MkdirAll(appDirPath, 0755); err != nil {
+				t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+			}
+			if err := ioutil.WriteFile(filepath.Join(appDirPath, "two-phase-app.yml"), []byte(oauthSamlApp), 0644); err != nil {
+				t.Fatalf("Unexpected error when writing the application file: %s", err)
+			}
+
+			applications.ImportAll(tempDir)
+			if !reflect.DeepEqual(requests, test.expectedRequests) {
+				t.Errorf("Expected the requests %v but got %v", test.expectedRequests, requests)
+			}
+		})
+	}
+}
+
+func TestServerCapabilitiesProbe(t *testing.T) {
+
+	testCases := []struct {
+		name           string
+		probeStatus    int
+		supported      bool
+		expectedProbes int
+	}{
+		{name: "supported and cached", probeStatus: http.StatusOK, supported: true, expectedProbes: 1},
+		{name: "unsupported and cached when not found", probeStatus: http.StatusNotFound, expectedProbes: 1},
+		{name: "unsupported and cached when not allowed", probeStatus: http.StatusMethodNotAllowed, expectedProbes: 1},
+		{name: "not cached when unauthorized", probeStatus: http.StatusUnauthorized, expectedProbes: 2},
+		{name: "not cached on server errors", probeStatus: http.StatusInternalServerError, expectedProbes: 2},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			probes := 0
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/applications/meta/inbound-protocols") {
+					probes++
+					w.WriteHeader(tc.probeStatus)
+					w.Write([]byte(`[]`))
+					return
+				}
+				w.WriteHeader(http.StatusNotFound)
+			})
+
+			for i := 0; i < 2; i++ {
+				if supported := utils.GetServerCapabilities().SeparateInboundProtocols; supported != tc.supported {
+					t.Errorf("Expected the separate inbound protocols capability to be %t but got %t", tc.supported, supported)
+				}
+			}
+			if probes != tc.expectedProbes {
+				t.Errorf("Expected %d requests to probe the server but got %d", tc.expectedProbes, probes)
+			}
+		})
+	}
+}
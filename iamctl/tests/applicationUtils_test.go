@@ -0,0 +1,721 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const tlsClientAuthApp = `applicationName: mtls-app
+certificateContent: ""
+inboundAuthenticationConfig:
+  inboundAuthenticationRequestConfigs:
+  - inboundAuthKey: mtls_client
+    inboundAuthType: oauth2
+    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO
+      oauthConsumerKey: mtls_client
+      tokenEndpointAuthMethod: tls_client_auth
+      tlsClientAuthSubjectDN: CN=mtls-app,O=WSO2,C=LK
+`
+
+const selfSignedTlsClientAuthApp = `applicationName: self-signed-mtls-app
+certificateContent: '-----BEGIN CERTIFICATE-----MIIDqzCCApOgAwIBAgIEXcKrEDANBgkqhkiG9w0BAQsFADB2-----END CERTIFICATE-----'
+inboundAuthenticationConfig:
+  inboundAuthenticationRequestConfigs:
+  - inboundAuthKey: self_signed_client
+    inboundAuthType: oauth2
+    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO
+      oauthConsumerKey: self_signed_client
+      tokenEndpointAuthMethod: self_signed_tls_client_auth
+`
+
+func TestClientAuthConfigRoundTrip(t *testing.T) {
+
+	testCases := []struct {
+		description    string
+		fileContent    string
+		expectedFields []string
+		certificate    string
+	}{
+		{
+			description: "tls_client_auth",
+			fileContent: tlsClientAuthApp,
+			expectedFields: []string{
+				"tokenEndpointAuthMethod: tls_client_auth",
+				"tlsClientAuthSubjectDN: CN=mtls-app,O=WSO2,C=LK",
+			},
+		},
+		{
+			description: "self_signed_tls_client_auth",
+			fileContent: selfSignedTlsClientAuthApp,
+			expectedFields: []string{
+				"tokenEndpointAuthMethod: self_signed_tls_client_auth",
+				"certificateContent: file://self-signed-mtls-app" + utils.CLIENT_CERTIFICATE_FILE_SUFFIX,
+			},
+			certificate: "-----BEGIN CERTIFICATE-----MIIDqzCCApOgAwIBAgIEXcKrEDANBgkqhkiG9w0BAQsFADB2-----END CERTIFICATE-----",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var appConfig struct {
+				ApplicationName string `yaml:"applicationName"`
+			}
+			yaml.Unmarshal([]byte(tc.fileContent), &appConfig)
+			var importedContent string
+			isImporting := false
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+				switch {
+				case r.Method == "GET" && path == "/applications/" && isImporting:
+					w.Write([]byte(`{"totalResults": 0, "applications": []}`))
+				case r.Method == "GET" && path == "/applications/":
+					w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "` +
+						appConfig.ApplicationName + `"}]}`))
+				case r.Method == "GET" && path == "/applications/app-1/exportFile":
+					w.Header().Set("Content-Disposition", `attachment; filename="`+appConfig.ApplicationName+`.yml"`)
+					w.Write([]byte(tc.fileContent))
+				case r.Method == "GET" && strings.HasPrefix(path, "/applications/app-1"):
+					w.Write([]byte(`{}`))
+				case r.Method == "POST" && path == "/applications/import":
+					file, _, err := r.FormFile("file")
+					if err != nil {
+						w.WriteHeader(http.StatusBadRequest)
+						return
+					}
+					content, _ := ioutil.ReadAll(file)
+					importedContent = string(content)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			tempDir := newTempDir(t)
+
+			applications.ExportAll(tempDir, "yaml")
+			appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, appConfig.ApplicationName+".yml")
+			exported, err := ioutil.ReadFile(appFilePath)
+			if err != nil {
+				t.Fatalf("Expected the application to be exported: %s", err)
+			}
+			for _, field := range tc.expectedFields {
+				if !strings.Contains(string(exported), field) {
+					t.Errorf("Expected exported content to contain %q but got:\n%s", field, exported)
+				}
+			}
+			certificateFilePath := filepath.Join(tempDir, utils.APPLICATIONS,
+				appConfig.ApplicationName+utils.CLIENT_CERTIFICATE_FILE_SUFFIX)
+			certificate, err := ioutil.ReadFile(certificateFilePath)
+			if tc.certificate == "" && err == nil {
+				t.Errorf("Expected no client certificate file but got:\n%s", certificate)
+			} else if tc.certificate != "" && strings.TrimSpace(string(certificate)) != tc.certificate {
+				t.Errorf("Expected the client certificate file to contain the certificate but got: %q %v", certificate, err)
+			}
+			if resourceName := utils.ResolveResourceName(certificateFilePath); resourceName != appConfig.ApplicationName {
+				t.Errorf("Expected the client certificate file to resolve to the application but got: %s", resourceName)
+			}
+
+			isImporting = true
+			applications.ImportAll(tempDir)
+			if importedContent == "" {
+				t.Fatalf("Expected the application to be imported")
+			}
+			for _, field := range tc.expectedFields {
+				if !strings.Contains(field, "certificateContent") && !strings.Contains(importedContent, field) {
+					t.Errorf("Expected imported content to contain %q but got:\n%s", field, importedContent)
+				}
+			}
+			var importedApp map[interface{}]interface{}
+			if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(importedContent)), &importedApp); err != nil {
+				t.Fatalf("Unexpected error when parsing the imported content: %s", err)
+			}
+			if importedApp["certificateContent"] != tc.certificate {
+				t.Errorf("Expected the certificate %q to be imported but got: %v", tc.certificate, importedApp["certificateContent"])
+			}
+			if err := applications.ValidateClientAuthConfig(importedContent); err != nil {
+				t.Errorf("Expected imported content to be valid but got: %s", err)
+			}
+		})
+	}
+}
+
+func TestValidateClientAuthConfig(t *testing.T) {
+
+	testCases := []struct {
+		description string
+		fileContent string
+		expectError bool
+	}{
+		{
+			description: "Valid tls_client_auth",
+			fileContent: tlsClientAuthApp,
+			expectError: false,
+		},
+		{
+			description: "tls_client_auth without subject DN",
+			fileContent: strings.Replace(tlsClientAuthApp, "tlsClientAuthSubjectDN: CN=mtls-app,O=WSO2,C=LK", "", 1),
+			expectError: true,
+		},
+		{
+			description: "tls_client_auth with invalid subject DN",
+			fileContent: strings.Replace(tlsClientAuthApp, "CN=mtls-app,O=WSO2,C=LK", "mtls-app", 1),
+			expectError: true,
+		},
+		{
+			description: "Valid self_signed_tls_client_auth",
+			fileContent: selfSignedTlsClientAuthApp,
+			expectError: false,
+		},
+		{
+			description: "self_signed_tls_client_auth without certificate",
+			fileContent: strings.Replace(selfSignedTlsClientAuthApp, "-----BEGIN CERTIFICATE-----MIIDqzCCApOgAwIBAgIEXcKrEDANBgkqhkiG9w0BAQsFADB2-----END CERTIFICATE-----", "", 1),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := applications.ValidateClientAuthConfig(tc.fileContent)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Expected error: %v but got: %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestIsValidSubjectDN(t *testing.T) {
+
+	testCases := []struct {
+		subjectDN      string
+		expectedResult bool
+	}{
+		{subjectDN: "CN=client,O=WSO2,C=LK", expectedResult: true},
+		{subjectDN: "CN=client\\, Inc,O=WSO2", expectedResult: true},
+		{subjectDN: "2.5.4.3=client", expectedResult: true},
+		{subjectDN: "client", expectedResult: false},
+		{subjectDN: "CN=,O=WSO2", expectedResult: false},
+		{subjectDN: "CN=client,,O=WSO2", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.subjectDN, func(t *testing.T) {
+			result := utils.IsValidSubjectDN(tc.subjectDN)
+			if result != tc.expectedResult {
+				t.Errorf("Expected result to be %v but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestAuthScriptRoundTrip(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	script := "var onLoginRequest = function(context) {\n    executeStep(1);\n};\n"
+	appContent := []byte(`applicationName: script-app
+localAndOutBoundAuthenticationConfig:
+  authenticationScriptConfig:
+    content: "var onLoginRequest = function(context) {\n    executeStep(1);\n};\n"
+    enabled: true
+`)
+
+	extractedContent, extractedScript, err := applications.ExtractAuthScript("script-app", appContent)
+	if err != nil {
+		t.Fatalf("Unexpected error when extracting the auth script: %s", err)
+	}
+	if string(extractedScript) != script {
+		t.Errorf("Expected extracted script %q but got %q", script, extractedScript)
+	}
+	if !strings.Contains(string(extractedContent), "content: file://script-app.authscript.js") {
+		t.Errorf("Expected the script to be replaced with a file reference but got:\n%s", extractedContent)
+	}
+
+	appFilePath := filepath.Join(tempDir, "script-app.yml")
+	if _, err := applications.InjectAuthScript(appFilePath, extractedContent); err == nil ||
+		!strings.Contains(err.Error(), "script-app") || !strings.Contains(err.Error(), "script-app.authscript.js") {
+		t.Errorf("Expected an error naming the app and the script path when the script file is missing but got: %v", err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(tempDir, "script-app.authscript.js"), extractedScript, 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the script file: %s", err)
+	}
+	injectedContent, err := applications.InjectAuthScript(appFilePath, extractedContent)
+	if err != nil {
+		t.Fatalf("Unexpected error when injecting the auth script: %s", err)
+	}
+	_, reExtractedScript, _ := applications.ExtractAuthScript("script-app", injectedContent)
+	if string(reExtractedScript) != script {
+		t.Errorf("Expected injected script %q but got %q", script, reExtractedScript)
+	}
+
+	if !utils.IsAuthScriptFile("script-app.authscript.js.gz") || utils.IsAuthScriptFile("script-app.yml") {
+		t.Errorf("Unexpected result when detecting the auth script files")
+	}
+}
+
+func TestResolvePromotedContents(t *testing.T) {
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"HOST": "prod.example.com"}}
+	defer func() { utils.KEYWORD_CONFIGS = utils.KeywordConfigs{} }()
+
+	testCases := []struct {
+		name            string
+		content         string
+		expectedContent string
+		expectErr       bool
+	}{
+		{
+			name:            "Keywords and masked secrets",
+			content:         "applicationName: MyApp\ncallbackUrl: https://{{HOST}}/cb\noauthConsumerSecret: '********'\n",
+			expectedContent: "applicationName: MyApp\ncallbackUrl: https://prod.example.com/cb\noauthConsumerSecret: null\n",
+		},
+		{
+			name:      "Keyword not defined in the target environment",
+			content:   "applicationName: MyApp\ncallbackUrl: https://{{STAGING_HOST}}/cb\n",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			contents, err := applications.ResolvePromotedContents(map[string]string{"MyApp": tc.content})
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error: %v but got: %v", tc.expectErr, err)
+			}
+			if !tc.expectErr && contents["MyApp"] != tc.expectedContent {
+				t.Errorf("Expected content:\n%s\nbut got:\n%s", tc.expectedContent, contents["MyApp"])
+			}
+		})
+	}
+}
+
+// Application returned by a newer server version, with fields that are not known to the tool.
+const appWithUnknownFields = `applicationName: future-app
+description: App with fields added in a newer server version
+fictionalFlag: true
+fictionalFeatureConfig: !!org.wso2.carbon.identity.application.common.model.FictionalFeatureConfig
+  enabled: true
+  retentionDays: 30
+  allowedOrigins:
+  - https://dev.example.com
+inboundAuthenticationConfig:
+  inboundAuthenticationRequestConfigs:
+  - inboundAuthKey: future_client
+    inboundAuthType: oauth2
+    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO
+      oauthConsumerKey: future_client
+      callbackUrl: https://dev.example.com/callback
+      fictionalProtocolSetting: strict
+`
+
+func TestUnknownFieldsRoundTrip(t *testing.T) {
+
+	var importedContent []byte
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "future-app"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Header().Set("Content-Disposition", `attachment; filename="future-app.yml"`)
+			w.Write([]byte(appWithUnknownFields))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			w.Write([]byte(`{}`))
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("Unexpected error when reading the imported file: %s", err)
+				return
+			}
+			importedContent, _ = ioutil.ReadAll(file)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{
+		"CALLBACK_URL": "https://dev.example.com/callback"}}
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "future-app.yml")
+
+	// Export once and add a keyword placeholder to the local file, so that the second export applies keyword mapping.
+	applications.ExportAll(tempDir, "yaml")
+	localContent, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	localContent = []byte(strings.Replace(string(localContent), "callbackUrl: https://dev.example.com/callback",
+		"callbackUrl: '{{CALLBACK_URL}}'", 1))
+	if err := ioutil.WriteFile(appFilePath, localContent, 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ExportAll(tempDir, "yaml")
+	exportedContent, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	for _, field := range []string{"fictionalFlag: true", "fictionalFeatureConfig:", "retentionDays: 30",
+		"fictionalProtocolSetting: strict", "callbackUrl: '{{CALLBACK_URL}}'"} {
+		if !strings.Contains(string(exportedContent), field) {
+			t.Errorf("Expected exported content to contain %q but got:\n%s", field, exportedContent)
+		}
+	}
+
+	applications.ImportAll(tempDir)
+	expectedContent := utils.ReplaceKeywords(string(exportedContent), utils.KEYWORD_CONFIGS.KeywordMappings)
+	if string(importedContent) != expectedContent {
+		t.Fatalf("Expected the imported content to match the exported file byte for byte.\nExpected:\n%s\nGot:\n%s",
+			expectedContent, importedContent)
+	}
+
+	var original, imported interface{}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags([]byte(appWithUnknownFields)), &original); err != nil {
+		t.Fatalf("Unexpected error when parsing the server content: %s", err)
+	}
+	if err := yaml.Unmarshal(utils.ReplaceTypeTags(importedContent), &imported); err != nil {
+		t.Fatalf("Unexpected error when parsing the imported content: %s", err)
+	}
+	if !reflect.DeepEqual(original, imported) {
+		t.Errorf("Expected the imported content to match the server content.\nExpected:\n%v\nGot:\n%v", original, imported)
+	}
+}
+
+func TestApplicationAccessControl(t *testing.T) {
+
+	var importedContent []byte
+	var patchedContent []byte
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Header().Set("Content-Disposition", `attachment; filename="hr-portal.yml"`)
+			w.Write([]byte("applicationName: hr-portal\ndescription: HR portal\n"))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			w.Write([]byte(`{"accessControl": {"groups": [{"id": "g-2", "name": "PRIMARY/managers"}, {"id": "g-1", "name": "hr-team"}]}}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/scim2/Groups"):
+			if r.URL.Query().Get("filter") == "displayName eq hr-team" {
+				w.Write([]byte(`{"Resources": [{"id": "g-1", "displayName": "hr-team"}]}`))
+			} else {
+				w.Write([]byte(`{"totalResults": 0}`))
+			}
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, _ := r.FormFile("file")
+			importedContent, _ = ioutil.ReadAll(file)
+		case r.Method == "PATCH" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			patchedContent, _ = ioutil.ReadAll(r.Body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "hr-portal.yml")
+
+	applications.ExportAll(tempDir, "yaml")
+	exportedContent, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	expectedConfig := "accessControlConfig:\n  groups:\n  - PRIMARY/managers\n  - hr-team\n"
+	if !strings.Contains(string(exportedContent), expectedConfig) {
+		t.Fatalf("Expected exported content to contain %q but got:\n%s", expectedConfig, exportedContent)
+	}
+
+	// Import with a group that exists in the target environment.
+	localContent := strings.Replace(string(exportedContent), "  - PRIMARY/managers\n", "", 1)
+	if err := ioutil.WriteFile(appFilePath, []byte(localContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if strings.Contains(string(importedContent), "accessControlConfig") || !strings.Contains(string(importedContent), "applicationName: hr-portal") {
+		t.Errorf("Expected the imported content without the access control configurations but got:\n%s", importedContent)
+	}
+	if expected := `{"accessControl":{"groups":[{"id":"g-1"}]}}`; string(patchedContent) != expected {
+		t.Errorf("Expected access control request %s but got %s", expected, patchedContent)
+	}
+
+	// Import with a group that does not exist in the target environment.
+	importedContent, patchedContent = nil, nil
+	localContent = strings.Replace(localContent, "  - hr-team\n", "  - hr-team\n  - contractors\n", 1)
+	if err := ioutil.WriteFile(appFilePath, []byte(localContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if importedContent != nil || patchedContent != nil {
+		t.Errorf("Expected the application not to be imported when a group does not exist")
+	}
+}
+
+func TestApplicationTokenConfig(t *testing.T) {
+
+	var importedContent []byte
+	var oidcRequest map[string]interface{}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Header().Set("Content-Disposition", `attachment; filename="hr-portal.yml"`)
+			w.Write([]byte("applicationName: hr-portal\ndescription: HR portal\n"))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			w.Write([]byte(`{"accessControl": {"groups": []}}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/inbound-protocols/oidc"):
+			w.Write([]byte(`{"grantTypes": ["authorization_code"], "accessToken": {"type": "Default",
+				"userAccessTokenExpiryInSeconds": 3600, "applicationAccessTokenExpiryInSeconds": 3600},
+				"refreshToken": {"expiryInSeconds": 86400}, "idToken": {"expiryInSeconds": 3600,
+				"idTokenSignedResponseAlg": "RS256"}}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/meta/inbound-protocols/oidc"):
+			w.Write([]byte(`{"accessTokenType": {"options": ["Default", "JWT"]},
+				"idTokenSignatureAlgorithm": {"options": ["RS256", "PS256"]}}`))
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, _ := r.FormFile("file")
+			importedContent, _ = ioutil.ReadAll(file)
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/app-1/inbound-protocols/oidc"):
+			json.NewDecoder(r.Body).Decode(&oidcRequest)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "hr-portal.yml")
+
+	applications.ExportAll(tempDir, "yaml")
+	exportedContent, err := ioutil.ReadFile(appFilePath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	expectedConfig := "tokenConfig:\n  accessTokenType: Default\n  applicationAccessTokenExpiryInSeconds: 3600\n" +
+		"  idTokenExpiryInSeconds: 3600\n  idTokenSignatureAlgorithm: RS256\n  refreshTokenExpiryInSeconds: 86400\n" +
+		"  userAccessTokenExpiryInSeconds: 3600\n"
+	if !strings.Contains(string(exportedContent), expectedConfig) {
+		t.Fatalf("Expected exported content to contain %q but got:\n%s", expectedConfig, exportedContent)
+	}
+
+	// Import with token configurations supported by the target environment.
+	localContent := strings.Replace(string(exportedContent), "accessTokenType: Default", "accessTokenType: JWT", 1)
+	localContent = strings.Replace(localContent, "idTokenSignatureAlgorithm: RS256", "idTokenSignatureAlgorithm: PS256", 1)
+	if err := ioutil.WriteFile(appFilePath, []byte(localContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if strings.Contains(string(importedContent), "tokenConfig") || !strings.Contains(string(importedContent), "applicationName: hr-portal") {
+		t.Errorf("Expected the imported content without the token configurations but got:\n%s", importedContent)
+	}
+	accessToken, _ := oidcRequest["accessToken"].(map[string]interface{})
+	idToken, _ := oidcRequest["idToken"].(map[string]interface{})
+	if accessToken["type"] != "JWT" || idToken["idTokenSignedResponseAlg"] != "PS256" || oidcRequest["grantTypes"] == nil {
+		t.Errorf("Expected the token configurations to be set on the deployed OIDC configurations but got %v", oidcRequest)
+	}
+
+	// Import with a signature algorithm that is not supported by the target environment.
+	importedContent, oidcRequest = nil, nil
+	localContent = strings.Replace(localContent, "idTokenSignatureAlgorithm: PS256", "idTokenSignatureAlgorithm: none", 1)
+	if err := ioutil.WriteFile(appFilePath, []byte(localContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if importedContent != nil || oidcRequest != nil {
+		t.Errorf("Expected the application not to be imported when the signature algorithm is not supported")
+	}
+}
+
+func TestSamlApplicationCertificates(t *testing.T) {
+
+	deployedContent := "applicationName: hr-portal\ncertificateContent: MIIBcert\ninboundAuthenticationConfig:\n" +
+		"  inboundAuthenticationRequestConfigs:\n  - inboundAuthKey: hr-portal\n    inboundAuthType: samlsso\n" +
+		"    inboundConfigurationProtocol: !!org.wso2.carbon.identity.sso.saml.dto.SAMLSSOServiceProviderDTO\n" +
+		"      issuer: hr-portal\n      assertionConsumerUrls:\n      - https://hr.example.com/acs\n" +
+		"      defaultAssertionConsumerUrl: https://hr.example.com/acs\n      certificateContent: MIIBcert\n"
+	var updatedContent, createdContent []byte
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Header().Set("Content-Disposition", `attachment; filename="hr-portal.yml"`)
+			w.Write([]byte(deployedContent))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			w.Write([]byte(`{"accessControl": {"groups": []}}`))
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, _ := r.FormFile("file")
+			updatedContent, _ = ioutil.ReadAll(file)
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, _ := r.FormFile("file")
+			createdContent, _ = ioutil.ReadAll(file)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS.ExcludeSecrets = true
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+
+	tempDir := newTempDir(t)
+	appsDir := filepath.Join(tempDir, utils.APPLICATIONS)
+
+	// The certificates of the SAML application are masked when the secrets are excluded.
+	applications.ExportAll(tempDir, "yaml")
+	exportedContent, err := ioutil.ReadFile(filepath.Join(appsDir, "hr-portal.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if strings.Contains(string(exportedContent), "MIIBcert") ||
+		strings.Count(string(exportedContent), "certificateContent: "+utils.SENSITIVE_FIELD_MASK) != 2 ||
+		!strings.Contains(string(exportedContent), "!!org.wso2.carbon.identity.sso.saml.dto.SAMLSSOServiceProviderDTO") {
+		t.Fatalf("Expected the SAML certificates to be masked in the exported content but got:\n%s", exportedContent)
+	}
+
+	// The certificates of the deployed application are retained when updating with masked certificates.
+	applications.ImportAll(tempDir)
+	if strings.Count(string(updatedContent), "certificateContent: MIIBcert") != 2 ||
+		!strings.Contains(string(updatedContent), "issuer: hr-portal") {
+		t.Errorf("Expected the deployed SAML certificates in the updated content but got:\n%s", updatedContent)
+	}
+
+	// A new application cannot be created with masked certificates.
+	newAppContent := strings.Replace(string(exportedContent), "applicationName: hr-portal", "applicationName: payroll", 1)
+	if err := os.Remove(filepath.Join(appsDir, "hr-portal.yml")); err != nil {
+		t.Fatalf("Unexpected error when removing the exported file: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(appsDir, "payroll.yml"), []byte(newAppContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if createdContent != nil {
+		t.Errorf("Expected the application with masked SAML certificates not to be created but got:\n%s", createdContent)
+	}
+
+	// A SAML application without assertion consumer URLs is not imported.
+	newAppContent = strings.Replace(newAppContent, "certificateContent: "+utils.SENSITIVE_FIELD_MASK, "certificateContent: MIIBcert", -1)
+	newAppContent = strings.Replace(newAppContent, "- https://hr.example.com/acs", "", 1)
+	newAppContent = strings.Replace(newAppContent, "assertionConsumerUrls:", "assertionConsumerUrls: []", 1)
+	if err := ioutil.WriteFile(filepath.Join(appsDir, "payroll.yml"), []byte(newAppContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	applications.ImportAll(tempDir)
+	if createdContent != nil {
+		t.Errorf("Expected the SAML application without assertion consumer URLs not to be created but got:\n%s", createdContent)
+	}
+}
+
+// Start a mock server with a deployed application of the owner alice, recording the content of the updates.
+func newApplicationOwnerServer(t *testing.T) (updatedContent *[]byte) {
+
+	deployedOwner := "owner:\n  userName: alice\n  userStoreDomain: PRIMARY\n  tenantDomain: carbon.super\n"
+	userIds := map[string]string{"alice": "user-1", "EMPLOYEES/bob": "user-2"}
+	updatedContent = new([]byte)
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Header().Set("Content-Disposition", `attachment; filename="hr-portal.yml"`)
+			w.Write([]byte("applicationName: hr-portal\n" + deployedOwner))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+			w.Write([]byte(`{"accessControl": {"groups": []}}`))
+		case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/scim2/Users/.search"):
+			var searchRequest struct {
+				Filter string `json:"filter"`
+			}
+			json.NewDecoder(r.Body).Decode(&searchRequest)
+			resources := []map[string]string{}
+			for userName, userId := range userIds {
+				if searchRequest.Filter == `userName eq "`+userName+`"` {
+					resources = append(resources, map[string]string{"id": userId, "userName": userName})
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"totalResults": len(resources), "Resources": resources})
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			file, _, _ := r.FormFile("file")
+			*updatedContent, _ = ioutil.ReadAll(file)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	utils.FORCE_IMPORT = true
+	t.Cleanup(func() { utils.FORCE_IMPORT = false })
+	return updatedContent
+}
+
+func TestExportApplicationOwner(t *testing.T) {
+
+	newApplicationOwnerServer(t)
+	tempDir := newTempDir(t)
+
+	// The owner keys are added to the exported application.
+	applications.ExportAll(tempDir, "yaml")
+	exportedContent, err := ioutil.ReadFile(filepath.Join(tempDir, utils.APPLICATIONS, "hr-portal.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if !strings.Contains(string(exportedContent), "ownerName: alice") ||
+		!strings.Contains(string(exportedContent), "ownerId: user-1") {
+		t.Fatalf("Expected the owner keys in the exported content but got:\n%s", exportedContent)
+	}
+}
+
+func TestImportApplicationOwner(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		ownerName     string
+		expectedOwner map[interface{}]interface{}
+	}{
+		{
+			name:      "The owner is resolved by the name and set when it differs from the deployed owner",
+			ownerName: "EMPLOYEES/bob",
+			expectedOwner: map[interface{}]interface{}{
+				"userName": "bob", "userStoreDomain": "EMPLOYEES", "tenantDomain": "carbon.super"},
+		},
+		{
+			name:      "An application with an owner that is not found in the target environment is not imported",
+			ownerName: "carol",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updatedContent := newApplicationOwnerServer(t)
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.APPLICATIONS), map[string]string{"hr-portal.yml": "" +
+				"applicationName: hr-portal\nowner:\n  userName: alice\n  userStoreDomain: PRIMARY\n" +
+				"  tenantDomain: carbon.super\nownerName: " + tc.ownerName + "\nownerId: user-1\n"})
+
+			applications.ImportAll(tempDir)
+			if tc.expectedOwner == nil {
+				if *updatedContent != nil {
+					t.Errorf("Expected the application not to be updated but got:\n%s", *updatedContent)
+				}
+				return
+			}
+			var appConfig map[string]interface{}
+			if err := yaml.Unmarshal(*updatedContent, &appConfig); err != nil {
+				t.Fatalf("Unexpected error when parsing the updated content: %s", err)
+			}
+			if !reflect.DeepEqual(appConfig["owner"], tc.expectedOwner) || appConfig["ownerName"] != nil ||
+				appConfig["ownerId"] != nil {
+				t.Errorf("Expected the owner %v in the updated content but got:\n%s", tc.expectedOwner, *updatedContent)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package tests
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestAuditLog(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"description": "A scope with the same name already exists."}`))
+	})
+
+	defer func() { utils.AUDIT_LOG = "" }()
+
+	tempDir := newTempDir(t)
+	auditLogPath := filepath.Join(tempDir, "audit.log")
+	if err := ioutil.WriteFile(auditLogPath, []byte(`{"operation": "DELETE"}`+"\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the audit log: %s", err)
+	}
+
+	utils.AUDIT_LOG = auditLogPath
+	utils.OpenAuditLog()
+	utils.EmitResourceStarted(utils.APPLICATIONS, "App1", utils.IMPORT)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "App1", utils.UPDATE)
+	utils.EmitResourceStarted(utils.APPLICATIONS, "App2", utils.EXPORT)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "App2", utils.EXPORT)
+	utils.EmitResourceStarted(utils.OIDC_SCOPES, "scope1", utils.IMPORT)
+	utils.SendJsonRequest(context.Background(), http.MethodPost, utils.OIDC_SCOPES, "", map[string]string{"name": "scope1"})
+	utils.UpdateFailureSummary(utils.OIDC_SCOPES, "scope1")
+	utils.EmitResourceStarted(utils.ROLES, "role1", utils.DELETE)
+	utils.UpdateFailureSummary(utils.ROLES, "role1")
+	utils.CloseAuditLog()
+
+	file, err := os.Open(auditLogPath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the audit log: %s", err)
+	}
+	defer file.Close()
+	var records []utils.AuditRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record utils.AuditRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("Unexpected error when parsing the audit record: %s", err)
+		}
+		if record.Timestamp == "" && len(records) > 0 {
+			t.Errorf("Expected a timestamp in the audit record: %s", scanner.Text())
+		}
+		record.Timestamp = ""
+		records = append(records, record)
+	}
+
+	expected := []utils.AuditRecord{
+		{Operation: utils.AUDIT_DELETE},
+		{Operation: utils.AUDIT_UPDATE, ResourceType: utils.APPLICATIONS, ResourceName: "App1", Success: true},
+		{Operation: utils.AUDIT_CREATE, ResourceType: utils.OIDC_SCOPES, ResourceName: "scope1",
+			ErrorMessage: `409 Conflict {"description": "A scope with the same name already exists."}`},
+		{Operation: utils.AUDIT_DELETE, ResourceType: utils.ROLES, ResourceName: "role1",
+			ErrorMessage: "The operation failed before the request was sent to the server. Check the logs for the error."},
+	}
+	if !reflect.DeepEqual(records, expected) {
+		t.Errorf("Expected audit records %+v but got %+v", expected, records)
+	}
+}
+
+func TestAuditLogNotWritable(t *testing.T) {
+
+	defer func() { utils.AUDIT_LOG = "" }()
+	tempDir := newTempDir(t)
+
+	// The run continues with the records written to stderr.
+	utils.AUDIT_LOG = filepath.Join(tempDir, "missing", "audit.log")
+	utils.OpenAuditLog()
+	if !utils.IsAuditEnabled() {
+		t.Errorf("Expected the audit log to fall back to stderr")
+	}
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "App1", utils.IMPORT)
+	utils.CloseAuditLog()
+	if _, err := os.Stat(utils.AUDIT_LOG); !os.IsNotExist(err) {
+		t.Errorf("Expected the audit log not to be created")
+	}
+}
@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestImportAzureAdGroups(t *testing.T) {
+
+	var createdRoles []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super")
+		switch {
+		case r.Method == "GET" && path == "/scim2/v2/Roles":
+			w.Write([]byte(`{"totalResults": 1, "Resources": [
+				{"id": "r1", "displayName": "approvers", "audience": {"type": "organization", "display": "carbon.super"}}]}`))
+		case r.Method == "POST" && path == "/scim2/v2/Roles":
+			var payload map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&payload)
+			if _, ok := payload["audience"]; ok {
+				t.Errorf("Expected the role to be created in the organization audience but got: %v", payload)
+			}
+			createdRoles = append(createdRoles, payload["displayName"].(string))
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "new"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{RoleConfigs: map[string]interface{}{
+		utils.AZURE_AD_GROUP_MAPPINGS_CONFIG: map[string]interface{}{
+			"HR Managers": "hr-manager",
+			"g-approvers": "approvers",
+			"All Staff":   "",
+		},
+	}}
+	tempDir := newTempDir(t)
+	utils.ResetSummary()
+
+	groupsFile := filepath.Join(tempDir, "groups.json")
+	groups := `{"@odata.context": "https://graph.microsoft.com/v1.0/$metadata#groups", "value": [
+		{"id": "g-hr", "displayName": "HR Managers", "securityEnabled": true},
+		{"id": "g-hr-2", "displayName": "HR Managers", "securityEnabled": true},
+		{"id": "g-approvers", "displayName": "Approvers", "securityEnabled": true},
+		{"id": "g-staff", "displayName": "All Staff", "securityEnabled": true},
+		{"id": "g-team", "displayName": "Team Site", "securityEnabled": false},
+		{"id": "g-finance", "displayName": "Finance"}]}`
+	if err := ioutil.WriteFile(groupsFile, []byte(groups), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+
+	if err := roles.ImportAzureAdGroups(groupsFile); err != nil {
+		t.Fatalf("Unexpected error when importing the Azure AD groups: %s", err)
+	}
+	expectedRoles := []string{"hr-manager", "Finance"}
+	if !reflect.DeepEqual(createdRoles, expectedRoles) {
+		t.Errorf("Expected the created roles %v but got %v", expectedRoles, createdRoles)
+	}
+	if utils.SummaryData.SuccessfulOperations != 3 || utils.SummaryData.FailedOperations != 0 {
+		t.Errorf("Expected the created and the existing roles in the summary but got %+v", utils.SummaryData)
+	}
+
+	// A list of groups is accepted in place of the API response.
+	createdRoles = nil
+	if err := ioutil.WriteFile(groupsFile, []byte(`[{"id": "g-sales", "displayName": "Sales"}]`), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	if err := roles.ImportAzureAdGroups(groupsFile); err != nil || !reflect.DeepEqual(createdRoles, []string{"Sales"}) {
+		t.Errorf("Expected the role of the group in the list to be created but got %v %v", createdRoles, err)
+	}
+
+	if err := ioutil.WriteFile(groupsFile, []byte(`{"value": "invalid"}`), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	if err := roles.ImportAzureAdGroups(groupsFile); err == nil || !strings.Contains(err.Error(), "invalid Azure AD group export") {
+		t.Errorf("Expected an error for the invalid group export but got: %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestBackupBeforeImport(t *testing.T) {
+
+	var requests []string
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1/identity-providers/")
+		requests = append(requests, r.Method+" "+path)
+		switch {
+		case r.Method == "GET" && path == "":
+			w.Write([]byte(`{"totalResults": 2, "identityProviders": [{"id": "idp-1", "name": "Google"},
+				{"id": "idp-2", "name": "Legacy"}]}`))
+		case r.Method == "GET" && path == "idp-1/export":
+			w.Header().Set("Content-Disposition", `attachment; filename="Google.yml"`)
+			w.Write([]byte(googleIdp))
+		case r.Method == "GET" && path == "idp-2/export":
+			w.Header().Set("Content-Disposition", `attachment; filename="Legacy.yml"`)
+			w.Write([]byte("identityProviderName: Legacy\n"))
+		case r.Method == "PUT" && path == "idp-1/import":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "DELETE" && path == "idp-2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tempDir := newTempDir(t)
+	backupDir := filepath.Join(tempDir, "backups")
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{AllowDelete: true, BackupDir: backupDir}
+	utils.FORCE_IMPORT = true
+	utils.ASSUME_YES = true
+	defer func() {
+		utils.FORCE_IMPORT = false
+		utils.ASSUME_YES = false
+	}()
+
+	idpsDir := filepath.Join(tempDir, "input", utils.IDENTITY_PROVIDERS)
+	if err := os.MkdirAll(idpsDir, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the input directory: %s", err)
+	}
+	localIdp := strings.Replace(googleIdp, "deployed-secret", "new-secret", 1)
+	if err := ioutil.WriteFile(filepath.Join(idpsDir, "Google.yml"), []byte(localIdp), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the local file: %s", err)
+	}
+	identityproviders.ImportAll(filepath.Join(tempDir, "input"))
+
+	// The deployed identity providers are backed up before they are updated or deleted.
+	runDir := utils.GetBackupRunDir()
+	if filepath.Dir(runDir) != backupDir {
+		t.Fatalf("Expected a timestamped backup directory in %s but got %s", backupDir, runDir)
+	}
+	metadata, err := utils.ReadBackupMetadata(runDir)
+	if err != nil || metadata.ServerUrl != server.URL || metadata.TenantDomain != "carbon.super" || metadata.ToolVersion == "" {
+		t.Errorf("Expected the backup metadata of the server but got %+v. %v", metadata, err)
+	}
+	googleBackup, err := ioutil.ReadFile(filepath.Join(runDir, utils.IDENTITY_PROVIDERS, "Google.yml"))
+	if err != nil {
+		t.Fatalf("Expected the updated identity provider to be backed up: %s", err)
+	}
+	if strings.Contains(string(googleBackup), "deployed-secret") || !strings.Contains(string(googleBackup), "google-client") {
+		t.Errorf("Expected the secrets to be masked in the backup but got:\n%s", googleBackup)
+	}
+	if _, err := os.Stat(filepath.Join(runDir, utils.IDENTITY_PROVIDERS, "Legacy.yml")); err != nil {
+		t.Errorf("Expected the deleted identity provider to be backed up: %s", err)
+	}
+	expectedOrder := []string{"GET idp-2/export", "DELETE idp-2"}
+	for i, request := range requests {
+		if request == expectedOrder[1] && (i == 0 || requests[i-1] != expectedOrder[0]) {
+			t.Errorf("Expected the identity provider to be backed up before it is deleted but got %v", requests)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/branding"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const localOrgPreference = "locale: en-US\npreference:\n  theme:\n    activeTheme: DARK\n  urls:\n" +
+	"    privacyPolicyURL: https://{{HOST}}/privacy\n"
+
+// Start a mock server with the deployed branding of the organization and an application, recording the requests
+// that change the branding with their queries and bodies.
+func newBrandingServer(t *testing.T) (requests *[]string) {
+
+	preferences := map[string]string{
+		"ORG|carbon.super|en-US": `{"theme": {"activeTheme": "DARK"}, "urls": {"privacyPolicyURL": "https://dev.example.com/privacy"}}`,
+		"APP|app-1|en-US":        `{"theme": {"activeTheme": "LIGHT"}}`,
+	}
+	texts := map[string]string{
+		"ORG|carbon.super|login|en-US": `{"text": {"login.heading": "Sign In"}}`,
+		"ORG|carbon.super|login|fr-FR": `{"text": {"login.heading": "Connexion"}}`,
+	}
+	requests = &[]string{}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/applications/"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "hr-portal"}]}`))
+			return
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/branding-preference"):
+			key := strings.Join([]string{query.Get("type"), query.Get("name"), query.Get("locale")}, "|")
+			if preference, ok := preferences[key]; ok {
+				w.Write([]byte(`{"preference": ` + preference + `}`))
+				return
+			}
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/branding-preference/text"):
+			key := strings.Join([]string{query.Get("type"), query.Get("name"), query.Get("screen"), query.Get("locale")}, "|")
+			if text, ok := texts[key]; ok {
+				w.Write([]byte(`{"preference": ` + text + `}`))
+				return
+			}
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			*requests = append(*requests, r.Method+" "+r.URL.Path+" "+r.URL.RawQuery+" "+string(body))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"HOST": "dev.example.com"}}
+	utils.TOOL_CONFIGS.BrandingConfigs = map[string]interface{}{"SCREENS": []interface{}{"login"},
+		"LOCALES": []interface{}{"en-US", "fr-FR"}}
+	return requests
+}
+
+func TestExportBranding(t *testing.T) {
+
+	newBrandingServer(t)
+	tempDir := newTempDir(t)
+	orgPreferencePath := filepath.Join(tempDir, utils.BRANDING, "Organization", "preference.yml")
+	writeFiles(t, filepath.Dir(orgPreferencePath), map[string]string{"preference.yml": localOrgPreference})
+
+	branding.ExportAll(tempDir, "yaml")
+	for filePath, expected := range map[string]string{
+		orgPreferencePath: "privacyPolicyURL: https://{{HOST}}/privacy",
+		filepath.Join(tempDir, utils.BRANDING, "Organization", "CustomText", "login", "fr-FR.yml"): "login.heading: Connexion",
+		filepath.Join(tempDir, utils.BRANDING, "Applications", "hr-portal", "preference.yml"):      "activeTheme: LIGHT",
+	} {
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Expected the exported file %s: %s", filePath, err)
+		}
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Expected exported content of %s to contain %q but got:\n%s", filePath, expected, content)
+		}
+	}
+}
+
+func TestImportBranding(t *testing.T) {
+
+	testCases := []struct {
+		name          string
+		appPreference string
+		allowDelete   bool
+		checkRequests func(t *testing.T, requests []string)
+	}{
+		{
+			name:          "Unchanged branding is not updated and locales missing locally are kept",
+			appPreference: "preference:\n  theme:\n    activeTheme: LIGHT\n",
+			checkRequests: func(t *testing.T, requests []string) {
+				if len(requests) != 0 {
+					t.Errorf("Expected no requests to update unchanged branding but got %v", requests)
+				}
+			},
+		},
+		{
+			name:          "Locales missing locally are deleted with ALLOW_DELETE",
+			appPreference: "preference:\n  theme:\n    activeTheme: LIGHT\n",
+			allowDelete:   true,
+			checkRequests: func(t *testing.T, requests []string) {
+				if len(requests) != 1 || !strings.HasPrefix(requests[0], "DELETE") ||
+					!strings.Contains(requests[0], "locale=fr-FR") || !strings.Contains(requests[0], "screen=login") {
+					t.Errorf("Expected the custom text of the fr-FR locale to be deleted but got %v", requests)
+				}
+			},
+		},
+		{
+			name:          "Changed branding of an application is updated with its id in the target environment",
+			appPreference: "preference:\n  theme:\n    activeTheme: DARK\n",
+			checkRequests: func(t *testing.T, requests []string) {
+				if len(requests) != 1 {
+					t.Fatalf("Expected an update but got %v", requests)
+				}
+				var updatedPreference map[string]interface{}
+				if err := json.Unmarshal([]byte(strings.SplitN(requests[0], " ", 4)[3]), &updatedPreference); err != nil ||
+					!strings.HasPrefix(requests[0], "PUT") || updatedPreference["name"] != "app-1" ||
+					updatedPreference["locale"] != "en-US" {
+					t.Errorf("Expected the branding preference of app-1 to be updated but got %s", requests[0])
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := newBrandingServer(t)
+			utils.TOOL_CONFIGS.BrandingConfigs["ALLOW_DELETE"] = tc.allowDelete
+			utils.ASSUME_YES = true
+			defer func() { utils.ASSUME_YES = false }()
+			tempDir := newTempDir(t)
+			brandingDirPath := filepath.Join(tempDir, utils.BRANDING)
+			writeFiles(t, filepath.Join(brandingDirPath, "Organization"), map[string]string{"preference.yml": localOrgPreference})
+			writeFiles(t, filepath.Join(brandingDirPath, "Organization", "CustomText", "login"),
+				map[string]string{"en-US.yml": "preference:\n  text:\n    login.heading: Sign In\n"})
+			writeFiles(t, filepath.Join(brandingDirPath, "Applications", "hr-portal"),
+				map[string]string{"preference.yml": tc.appPreference})
+
+			branding.ImportAll(tempDir)
+			tc.checkRequests(t, *requests)
+		})
+	}
+}
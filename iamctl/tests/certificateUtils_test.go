@@ -0,0 +1,89 @@
+package tests
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestExcludeCertificates(t *testing.T) {
+
+	key, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	expiry := time.Date(2030, 6, 30, 23, 59, 59, 0, time.UTC)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com", Organization: []string{"Example"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     expiry,
+	}
+	certificateDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the certificate: %s", err)
+	}
+	pemCertificate := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificateDER}))
+	encodedCertificate := base64.StdEncoding.EncodeToString([]byte(pemCertificate))
+	blockCertificate := "    " + strings.Join(strings.Split(strings.TrimSpace(pemCertificate), "\n"), "\n    ")
+
+	content := `identityProviderName: Partner
+certificate: ` + encodedCertificate + `
+certificateInfoArray:
+- certValue: |-
+` + blockCertificate + `
+  thumbprint: abc
+inboundAuthenticationConfig:
+  certificateContent: ` + utils.SENSITIVE_FIELD_MASK + `
+  samlCertificate:
+    certificateContent: '{{PARTNER_CERTIFICATE}}'
+  certificate: ""
+`
+	description := "# Certificate subject: CN=idp.example.com,O=Example, issuer: CN=idp.example.com,O=Example, " +
+		"expires: 2030-06-30T23:59:59Z"
+	expected := `identityProviderName: Partner
+` + description + `
+certificate: <certificate>
+certificateInfoArray:
+` + description + `
+- certValue: <certificate>
+  thumbprint: abc
+inboundAuthenticationConfig:
+  certificateContent: ` + utils.SENSITIVE_FIELD_MASK + `
+  samlCertificate:
+    certificateContent: '{{PARTNER_CERTIFICATE}}'
+  certificate: ""
+`
+	excludedContent := string(utils.ExcludeCertificates([]byte(content)))
+	if excludedContent != expected {
+		t.Errorf("Expected the content with the certificates excluded:\n%s\nbut got:\n%s", expected, excludedContent)
+	}
+
+	// The excluded content cannot be imported.
+	expectedFields := []string{"certificate", "certificateInfoArray[0].certValue"}
+	if fields := utils.FindCertificatePlaceholders([]byte(excludedContent)); !reflect.DeepEqual(fields, expectedFields) {
+		t.Errorf("Expected the certificate placeholders in the fields %v but got %v", expectedFields, fields)
+	}
+	tempDir := newTempDir(t)
+	filePath := filepath.Join(tempDir, "Partner.yml")
+	if err := ioutil.WriteFile(filePath, []byte(excludedContent), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the resource file: %s", err)
+	}
+	if err := utils.CheckCertificatePlaceholders([]string{filePath}); err == nil ||
+		!strings.Contains(err.Error(), filePath+": certificateInfoArray[0].certValue") {
+		t.Errorf("Expected the import to fail on the certificate placeholders but got: %v", err)
+	}
+	if err := utils.CheckCertificatePlaceholders(nil); err != nil {
+		t.Errorf("Unexpected error when checking the certificate placeholders of no files: %s", err)
+	}
+}
@@ -0,0 +1,103 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func TestWriteChangeLogEntry(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	repoDir := newTempDir(t)
+	runGit := func(args ...string) string {
+		output, err := exec.Command("git", append([]string{"-C", repoDir}, args...)...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("Unexpected error when running git %v: %s", args, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	runGit("init", "-q")
+	runGit("config", "user.name", "Jane Doe")
+	runGit("config", "user.email", "jane@example.com")
+
+	appFile := filepath.Join(repoDir, "hr-portal.yml")
+	ioutil.WriteFile(appFile, []byte("name: hr-portal\n"), 0644)
+	runGit("add", "-A")
+	runGit("commit", "-q", "-m", "Export")
+	headCommit := runGit("rev-parse", "HEAD")
+
+	utils.GIT_LOG = true
+	defer func() {
+		utils.GIT_LOG = false
+	}()
+	changeLogFile := filepath.Join(repoDir, utils.CHANGE_LOG_FILE)
+
+	// Unchanged files do not add an entry.
+	if err := utils.WriteExportedFile(appFile, []byte("name: hr-portal\n")); err != nil {
+		t.Fatalf("Unexpected error when writing the exported file: %s", err)
+	}
+	if err := utils.WriteChangeLogEntry(repoDir); err != nil {
+		t.Fatalf("Unexpected error when writing the change log entry: %s", err)
+	}
+	if _, err := os.Stat(changeLogFile); !os.IsNotExist(err) {
+		t.Errorf("Expected no change log entry when no resource changed")
+	}
+
+	for run := 0; run < 2; run++ {
+		utils.WriteExportedFile(appFile, []byte("name: hr-portal\ndescription: run "+strconv.Itoa(run)+"\n"))
+		utils.WriteExportedFile(filepath.Join(repoDir, "payroll.yml"), []byte("name: payroll\nversion: "+strconv.Itoa(run)+"\n"))
+		if err := utils.WriteChangeLogEntry(repoDir); err != nil {
+			t.Fatalf("Unexpected error when writing the change log entry: %s", err)
+		}
+	}
+
+	content, err := ioutil.ReadFile(changeLogFile)
+	if err != nil {
+		t.Fatalf("Expected the change log file to be written: %s", err)
+	}
+	var entries []map[string]interface{}
+	if err := yaml.Unmarshal(content, &entries); err != nil {
+		t.Fatalf("Expected the change log to be a YAML list but got: %s", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected an entry for each export but got:\n%s", content)
+	}
+	for _, entry := range entries {
+		if entry["user"] != "Jane Doe" || entry["changedResources"] != 2 || entry["commit"] != headCommit ||
+			entry["timestamp"] == nil {
+			t.Errorf("Unexpected change log entry: %v", entry)
+		}
+	}
+}
+
+func TestIsGitRepository(t *testing.T) {
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	repoDir := newTempDir(t)
+	if utils.IsGitRepository(repoDir) {
+		t.Errorf("Expected a directory without a git repository not to be detected")
+	}
+	if output, err := exec.Command("git", "-C", repoDir, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("Unexpected error when running git init: %s", output)
+	}
+
+	// An output directory that is not created yet is checked with its nearest existing parent.
+	if !utils.IsGitRepository(filepath.Join(repoDir, "exports", "dev")) {
+		t.Errorf("Expected a missing directory inside the git repository to be detected")
+	}
+	if !utils.IsGitRepository(repoDir) {
+		t.Errorf("Expected the git repository to be detected")
+	}
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestGetStaleLocalFiles(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/t/carbon.super/api/server/v1/identity-providers/" {
+			w.Write([]byte(`{"totalResults": 1, "identityProviders": [{"id": "idp-1", "name": "Google"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	defer func() { utils.NAMESPACE = "" }()
+
+	tempDir := newTempDir(t)
+	idpsDir := filepath.Join(tempDir, utils.IDENTITY_PROVIDERS)
+	if err := os.MkdirAll(idpsDir, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the identity providers directory: %s", err)
+	}
+	for _, fileName := range []string{"Google.yml", "LOCAL.yml", "Legacy.yml", "team-a-Okta.yml"} {
+		if err := ioutil.WriteFile(filepath.Join(idpsDir, fileName), []byte("identityProviderName: x\n"), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the local file: %s", err)
+		}
+	}
+
+	// The resident identity provider is kept, since it is not in the identity provider list.
+	staleFilePaths, err := identityproviders.GetStaleLocalFiles(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when getting the stale local files: %s", err)
+	}
+	expected := []string{filepath.Join(idpsDir, "Legacy.yml"), filepath.Join(idpsDir, "team-a-Okta.yml")}
+	if !reflect.DeepEqual(staleFilePaths, expected) {
+		t.Errorf("Expected the stale local files %v but got %v", expected, staleFilePaths)
+	}
+
+	// Only the files of the namespace are checked.
+	utils.NAMESPACE = "team-a-"
+	staleFilePaths, err = utils.GetStaleLocalFiles(idpsDir, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error when getting the stale local files: %s", err)
+	}
+	expected = []string{filepath.Join(idpsDir, "team-a-Okta.yml")}
+	if !reflect.DeepEqual(staleFilePaths, expected) {
+		t.Errorf("Expected the stale local files of the namespace %v but got %v", expected, staleFilePaths)
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/client"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Start a mock server that issues an access token to the client credentials of the library client, with the tool
+// configured for a different server, and record the authorization headers of the application list requests.
+func newLibraryClientServer(t *testing.T) (server *httptest.Server, authorizations *[]string) {
+
+	authorizations = &[]string{}
+	server = newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case r.Method == "POST" && path == "/t/carbon.super/oauth2/token":
+			if clientId, clientSecret, _ := r.BasicAuth(); clientId != "lib-client" || clientSecret != "lib-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			w.Write([]byte(`{"access_token": "lib-token", "expires_in": 3600}`))
+		case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/applications"):
+			*authorizations = append(*authorizations, r.Header.Get("Authorization"))
+			w.Write([]byte(`{"totalResults": 2, "applications": [{"id": "app-2", "name": "payroll"},
+				{"id": "app-1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(path, "/applications/meta/inbound-protocols"):
+			w.WriteHeader(http.StatusNotFound)
+		case strings.HasSuffix(path, "/applications/import"):
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.SERVER_CONFIGS = utils.ServerConfigs{ServerUrl: "https://cli.example.com", TenantDomain: "carbon.super"}
+	return server, authorizations
+}
+
+// Create a library client of the mock server.
+func newLibraryClient(t *testing.T, server *httptest.Server) *client.Client {
+
+	libClient, err := client.New(context.Background(), client.Options{ServerUrl: server.URL + "/", ClientId: "lib-client",
+		ClientSecret: "lib-secret"})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the client: %s", err)
+	}
+	return libClient
+}
+
+func TestClient(t *testing.T) {
+
+	server, authorizations := newLibraryClientServer(t)
+	ctx := context.Background()
+	if _, err := client.New(ctx, client.Options{ServerUrl: server.URL, ClientId: "lib-client", ClientSecret: "wrong"}); err == nil {
+		t.Errorf("Expected an error when the access token cannot be requested")
+	}
+	libClient := newLibraryClient(t, server)
+
+	apps, err := libClient.ListApplications(ctx)
+	if err != nil || len(apps) != 2 || apps[0].Name != "hr-portal" || apps[1].Name != "payroll" {
+		t.Errorf("Expected the sorted applications but got %+v %v", apps, err)
+	}
+	for _, authorization := range *authorizations {
+		if authorization != "Bearer lib-token" {
+			t.Errorf("Expected the requests with the access token of the client but got: %s", authorization)
+		}
+	}
+	// The configs of the tool are restored after each call.
+	if utils.SERVER_CONFIGS.ServerUrl != "https://cli.example.com" {
+		t.Errorf("Expected the server configs to be restored but got: %s", utils.SERVER_CONFIGS.ServerUrl)
+	}
+
+	cancelledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := libClient.ListApplications(cancelledCtx); err != context.Canceled {
+		t.Errorf("Expected the cancelled context error but got: %v", err)
+	}
+
+}
+
+func TestClientImport(t *testing.T) {
+
+	server, _ := newLibraryClientServer(t)
+	libClient := newLibraryClient(t, server)
+	ctx := context.Background()
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "hr-portal.yml")
+	os.MkdirAll(filepath.Dir(appFilePath), 0755)
+	if err := ioutil.WriteFile(appFilePath, []byte("applicationName: hr-portal\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	err := libClient.ImportApplication(ctx, appFilePath, client.ImportOptions{})
+	if err == nil || !strings.Contains(err.Error(), "1 Applications failed") {
+		t.Errorf("Expected an error for the failed import but got: %v", err)
+	}
+	if utils.INCLUDE_ONLY != nil {
+		t.Errorf("Expected the include filter of the import to be restored but got: %v", utils.INCLUDE_ONLY)
+	}
+
+	// A system application is imported only when the confirmation is answered, which cannot be done without a terminal.
+	consoleFilePath := filepath.Join(tempDir, utils.APPLICATIONS, utils.CONSOLE+".yml")
+	if err := ioutil.WriteFile(consoleFilePath, []byte("applicationName: "+utils.CONSOLE+"\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	if err := libClient.ImportApplication(ctx, consoleFilePath, client.ImportOptions{IncludeSystemApps: true}); err != nil {
+		t.Errorf("Expected the unconfirmed system application to be skipped but got: %v", err)
+	}
+	err = libClient.ImportApplication(ctx, consoleFilePath, client.ImportOptions{IncludeSystemApps: true, AssumeYes: true})
+	if err == nil || !strings.Contains(err.Error(), "1 Applications failed") {
+		t.Errorf("Expected the confirmed system application to be imported but got: %v", err)
+	}
+	if utils.ASSUME_YES || utils.INCLUDE_SYSTEM_APPS {
+		t.Errorf("Expected the import options to be restored after the call")
+	}
+	if err := libClient.ImportIdp(ctx, appFilePath, client.ImportOptions{}); err == nil ||
+		!strings.Contains(err.Error(), "should be inside the IdentityProviders folder") {
+		t.Errorf("Expected an error for the file outside the identity providers folder but got: %v", err)
+	}
+}
+
+func TestClientTransportOptions(t *testing.T) {
+
+	var signatures, exportedTypes []string
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		signatures = append(signatures, r.Header.Get("X-Signature"))
+		if strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/claim-dialects") {
+			exportedTypes = append(exportedTypes, utils.CLAIMS)
+			w.Write([]byte(`[]`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	var transportRequests int
+	transport := utils.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportRequests++
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	signer := func(next http.RoundTripper) http.RoundTripper {
+		return utils.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Signature", "signed")
+			return next.RoundTrip(req)
+		})
+	}
+	ctx := context.Background()
+	libClient, err := client.New(ctx, client.Options{ServerUrl: server.URL, Token: "lib-token", Transport: transport,
+		Middlewares: []utils.HttpMiddleware{signer}})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the client: %s", err)
+	}
+
+	tempDir := newTempDir(t)
+	libClient.ExportResources(ctx, utils.CLAIMS, tempDir, client.ExportOptions{})
+	if len(exportedTypes) == 0 || transportRequests != len(signatures) {
+		t.Errorf("Expected the export requests to be sent with the transport of the client but got %d of %d",
+			transportRequests, len(signatures))
+	}
+	for _, signature := range signatures {
+		if signature != "signed" {
+			t.Errorf("Expected the requests to be signed by the middleware of the client but got: %q", signature)
+		}
+	}
+
+	// The middlewares of a client are not applied to the requests of the tool or the other clients.
+	otherClient, err := client.New(ctx, client.Options{ServerUrl: server.URL, Token: "lib-token"})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the client: %s", err)
+	}
+	signatures = nil
+	otherClient.ExportResources(ctx, utils.CLAIMS, tempDir, client.ExportOptions{})
+	if len(signatures) == 0 || signatures[0] != "" {
+		t.Errorf("Expected the requests of the other client without the signature but got: %v", signatures)
+	}
+
+	if err := libClient.ExportResources(ctx, utils.USERS, tempDir, client.ExportOptions{}); err == nil {
+		t.Errorf("Expected an error for the resource type that cannot be exported")
+	}
+	if err := libClient.ImportResources(ctx, "Unknown", tempDir, client.ImportOptions{}); err == nil {
+		t.Errorf("Expected an error for the resource type that cannot be imported")
+	}
+	if _, err := client.New(ctx, client.Options{ServerUrl: server.URL, Token: "lib-token", Transport: transport,
+		ClientCertFile: "client.crt", ClientKeyFile: "client.key"}); err == nil {
+		t.Errorf("Expected an error when a client certificate is given with a custom transport")
+	}
+}
@@ -0,0 +1,54 @@
+package tests
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/cmd"
+	_ "github.com/wso2-extensions/identity-tools-cli/iamctl/cmd/cli"
+)
+
+// Commands of the interactive mode, which are not documented with examples.
+var interactiveCommands = []string{"application", "createclientapp", "init", "serverConfiguration"}
+
+func TestCommandExamplesCoverFlags(t *testing.T) {
+	var checkCommand func(command *cobra.Command)
+	checkCommand = func(command *cobra.Command) {
+		for _, subCommand := range command.Commands() {
+			if command == cmd.RootCmd && isInteractiveCommand(subCommand) {
+				continue
+			}
+			checkCommand(subCommand)
+		}
+		if !command.Runnable() || command.Hidden || command == cmd.RootCmd {
+			return
+		}
+		command.LocalNonPersistentFlags().VisitAll(func(flag *pflag.Flag) {
+			if flag.Name == "help" {
+				return
+			}
+			flagPattern := `--` + regexp.QuoteMeta(flag.Name)
+			if flag.Shorthand != "" {
+				flagPattern = `(` + flagPattern + `|-` + regexp.QuoteMeta(flag.Shorthand) + `)`
+			}
+			if !regexp.MustCompile(`(^|\s)` + flagPattern + `(\s|=|$)`).MatchString(command.Example) {
+				t.Errorf("Flag --%s of the command %q is not used in the examples of the command", flag.Name,
+					command.CommandPath())
+			}
+		})
+	}
+	checkCommand(cmd.RootCmd)
+}
+
+func isInteractiveCommand(command *cobra.Command) bool {
+
+	for _, name := range interactiveCommands {
+		if strings.EqualFold(command.Name(), name) {
+			return true
+		}
+	}
+	return false
+}
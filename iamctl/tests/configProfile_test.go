@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const profilesFileContent = `# Target environments of the team.
+currentProfile: dev
+profiles:
+  dev:
+    SERVER_URL: https://dev.example.com
+    CLIENT_ID: dev-client
+    CLIENT_SECRET: ${PROFILE_TEST_SECRET}
+    TOOL_CONFIG_PATH: /configs/dev/toolConfig.json
+  prod:
+    SERVER_URL: https://prod.example.com
+    CLIENT_ID: prod-client
+    TENANTS:
+    - carbon.super
+    - wso2.com
+`
+
+func TestConfigProfiles(t *testing.T) {
+
+	tempDir := newTempDir(t)
+	profilesFilePath := filepath.Join(tempDir, "config.yaml")
+	if err := ioutil.WriteFile(profilesFilePath, []byte(profilesFileContent), 0600); err != nil {
+		t.Fatalf("Unexpected error when writing the profiles file: %s", err)
+	}
+	os.Setenv(utils.IAMCTL_PROFILES_FILE, profilesFilePath)
+	os.Setenv("PROFILE_TEST_SECRET", "dev-secret")
+	defer os.Unsetenv(utils.IAMCTL_PROFILES_FILE)
+	defer os.Unsetenv("PROFILE_TEST_SECRET")
+	defer func() { utils.CONFIG_PROFILE = "" }()
+
+	names, currentProfile, err := utils.ListConfigProfiles()
+	if err != nil || !reflect.DeepEqual(names, []string{"dev", "prod"}) || currentProfile != "dev" {
+		t.Errorf("Expected the profiles [dev prod] with dev as default but got %v %s %v", names, currentProfile, err)
+	}
+
+	profile, err := utils.GetActiveConfigProfile(true)
+	if err != nil || profile == nil {
+		t.Fatalf("Expected the default profile but got %v %v", profile, err)
+	}
+	if profile.Name != "dev" || profile.ServerConfigs.ClientSecret != "dev-secret" ||
+		profile.ToolConfigPath != "/configs/dev/toolConfig.json" {
+		t.Errorf("Expected the dev profile with the resolved secret but got %+v", profile)
+	}
+	if profile, err := utils.GetActiveConfigProfile(false); profile != nil || err != nil {
+		t.Errorf("Expected no profile without the --config-profile flag but got %v %v", profile, err)
+	}
+
+	utils.CONFIG_PROFILE = "prod"
+	profile, err = utils.GetActiveConfigProfile(false)
+	if err != nil || profile == nil || profile.ServerConfigs.ServerUrl != "https://prod.example.com" ||
+		!reflect.DeepEqual(profile.ServerConfigs.Tenants, []string{"carbon.super", "wso2.com"}) {
+		t.Errorf("Expected the prod profile but got %+v %v", profile, err)
+	}
+	utils.CONFIG_PROFILE = "qa"
+	if _, err := utils.GetActiveConfigProfile(true); err == nil {
+		t.Errorf("Expected an error for an undefined profile")
+	}
+	utils.CONFIG_PROFILE = ""
+
+	if err := utils.UseConfigProfile("qa"); err == nil {
+		t.Errorf("Expected an error when using an undefined profile")
+	}
+	if err := utils.UseConfigProfile("prod"); err != nil {
+		t.Fatalf("Unexpected error when setting the default profile: %s", err)
+	}
+	if _, currentProfile, _ := utils.ListConfigProfiles(); currentProfile != "prod" {
+		t.Errorf("Expected prod as the default profile but got %s", currentProfile)
+	}
+	content, _ := ioutil.ReadFile(profilesFilePath)
+	if !strings.Contains(string(content), "CLIENT_SECRET: ${PROFILE_TEST_SECRET}") {
+		t.Errorf("Expected the profiles to be kept unchanged but got:\n%s", content)
+	}
+
+	// Without a profiles file, the server configs are read from the environment variables.
+	os.Setenv(utils.IAMCTL_PROFILES_FILE, filepath.Join(tempDir, "missing.yaml"))
+	if profile, err := utils.GetActiveConfigProfile(true); profile != nil || err != nil {
+		t.Errorf("Expected no profile without a profiles file but got %v %v", profile, err)
+	}
+}
+
+func TestShowConfigMasksKeywordValues(t *testing.T) {
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"access_token": "show-config-token"}`))
+	})
+
+	tempDir := newTempDir(t)
+	configFiles := map[string]string{
+		utils.SERVER_CONFIG_FILE: `{"SERVER_URL": "` + server.URL + `", "CLIENT_ID": "client", "CLIENT_SECRET": "secret"}`,
+		utils.TOOL_CONFIG_FILE:   `{}`,
+		utils.KEYWORD_CONFIG_FILE: `{"KEYWORD_MAPPINGS": {"SMTP_PASSWORD": "smtp-pass-123"},
+			"APPLICATIONS": {"hr-portal": {"CALLBACK_HOST": "hr.example.com", "SCOPES": ["openid"]}}}`,
+	}
+	for fileName, content := range configFiles {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, fileName), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the config file: %s", err)
+		}
+	}
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the pipe: %s", err)
+	}
+	stdout := os.Stdout
+	defer func() { utils.SHOW_CONFIG = false }()
+	utils.SHOW_CONFIG = true
+	os.Stdout = writer
+	utils.LoadConfigs(tempDir)
+	os.Stdout = stdout
+	writer.Close()
+	output, _ := ioutil.ReadAll(reader)
+
+	for _, value := range []string{"smtp-pass-123", "hr.example.com", "openid", "show-config-token"} {
+		if strings.Contains(string(output), value) {
+			t.Errorf("Expected the value %s to be masked in the printed configs but got:\n%s", value, output)
+		}
+	}
+	for _, name := range []string{"SMTP_PASSWORD", "hr-portal", "CALLBACK_HOST", "SCOPES"} {
+		if !strings.Contains(string(output), name) {
+			t.Errorf("Expected the keyword name %s in the printed configs but got:\n%s", name, output)
+		}
+	}
+	if utils.KEYWORD_CONFIGS.KeywordMappings["SMTP_PASSWORD"] != "smtp-pass-123" {
+		t.Errorf("Expected the loaded keyword configs to be unchanged but got: %v", utils.KEYWORD_CONFIGS.KeywordMappings)
+	}
+}
@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func conflictApp(description string) string {
+
+	return "applicationName: conflict-app\napplicationID: app-1\ndescription: " + description + "\n"
+}
+
+func TestApplicationConflictStrategies(t *testing.T) {
+
+	tests := []struct {
+		name             string
+		baseline         string
+		strategy         string
+		expectUpdate     bool
+		expectConflict   bool
+		expectFailure    bool
+		expectedBaseline string
+	}{
+		{
+			name:         "applications without a baseline are updated",
+			strategy:     utils.CONFLICT_STRATEGY_FAIL,
+			expectUpdate: true,
+		},
+		{
+			name:             "applications unchanged on the server are updated and the baseline is refreshed",
+			baseline:         conflictApp("edited on the server"),
+			strategy:         utils.CONFLICT_STRATEGY_FAIL,
+			expectUpdate:     true,
+			expectedBaseline: conflictApp("edited on the server"),
+		},
+		{
+			name:             "server version is overwritten with the ours strategy",
+			baseline:         conflictApp("exported"),
+			strategy:         utils.CONFLICT_STRATEGY_OURS,
+			expectUpdate:     true,
+			expectedBaseline: conflictApp("edited on the server"),
+		},
+		{
+			name:             "server version is kept with the theirs strategy",
+			baseline:         conflictApp("exported"),
+			strategy:         utils.CONFLICT_STRATEGY_THEIRS,
+			expectConflict:   true,
+			expectedBaseline: conflictApp("exported"),
+		},
+		{
+			name:             "import is stopped with the fail strategy",
+			baseline:         conflictApp("exported"),
+			strategy:         utils.CONFLICT_STRATEGY_FAIL,
+			expectFailure:    true,
+			expectedBaseline: conflictApp("exported"),
+		},
+	}
+
+	defaultStrategy := utils.CONFLICT_STRATEGY
+	defer func() {
+		utils.CONFLICT_STRATEGY = defaultStrategy
+		utils.StartTimeBudget(0)
+	}()
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			updated := false
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				path := r.URL.Path
+				switch {
+				case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/applications"):
+					w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "conflict-app"}]}`))
+				case r.Method == "GET" && strings.HasSuffix(path, "/applications/app-1/exportFile"):
+					w.Write([]byte(conflictApp("edited on the server")))
+				case r.Method == "PUT" && strings.HasSuffix(path, "/applications/import"):
+					updated = true
+					w.WriteHeader(http.StatusOK)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			utils.TOOL_CONFIGS = utils.ToolConfigs{ApplicationConfigs: map[string]interface{}{}}
+			utils.CONFLICT_STRATEGY = test.strategy
+			utils.StartTimeBudget(0)
+			utils.ResetSummary()
+
+			tempDir := newTempDir(t)
+			appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+			if err := os.MkdirAll(appDirPath, 0755); err != nil {
+				t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+			}
+			appFilePath := filepath.Join(appDirPath, "conflict-app.yml")
+			if err := ioutil.WriteFile(appFilePath, []byte(conflictApp("local")), 0644); err != nil {
+				t.Fatalf("Unexpected error when writing the application file: %s", err)
+			}
+			baselineFilePath := filepath.Join(appDirPath, "conflict-app"+utils.BASELINE_FILE_EXTENSION)
+			if test.baseline != "" {
+				if err := ioutil.WriteFile(baselineFilePath, []byte(test.baseline), 0644); err != nil {
+					t.Fatalf("Unexpected error when writing the baseline file: %s", err)
+				}
+			}
+
+			applications.ImportAll(tempDir)
+			if updated != test.expectUpdate {
+				t.Errorf("Expected the application to be updated: %t but got: %t", test.expectUpdate, updated)
+			}
+			summary := utils.ResourceSummaries[utils.APPLICATIONS]
+			var expectedConflicts []string
+			if test.expectConflict {
+				expectedConflicts = []string{"conflict-app"}
+			}
+			if !reflect.DeepEqual(summary.ConflictedResources, expectedConflicts) {
+				t.Errorf("Expected the conflicted applications %v but got %v", expectedConflicts, summary.ConflictedResources)
+			}
+			if test.expectFailure != (summary.Failed == 1) || test.expectFailure != utils.IsStopRequested() {
+				t.Errorf("Expected the application to fail and stop the import: %t but got %d failures",
+					test.expectFailure, summary.Failed)
+			}
+			if test.expectedBaseline != "" {
+				baseline, _ := ioutil.ReadFile(baselineFilePath)
+				if string(baseline) != test.expectedBaseline {
+					t.Errorf("Expected the baseline:\n%s\nbut got:\n%s", test.expectedBaseline, baseline)
+				}
+			}
+		})
+	}
+}
+
+func TestBaselineFilesAreNotResourceFiles(t *testing.T) {
+
+	tempDir := newTempDir(t)
+	appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+	if err := os.MkdirAll(appDirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+	}
+	appFilePath := filepath.Join(appDirPath, "conflict-app.yml")
+	for _, filePath := range []string{appFilePath, utils.GetBaselineFilePath(appFilePath)} {
+		if err := ioutil.WriteFile(filePath, []byte(conflictApp("exported")), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+
+	filePaths, err := utils.GetLocalResourceFilePaths(tempDir)
+	if err != nil || !reflect.DeepEqual(filePaths, []string{appFilePath}) {
+		t.Errorf("Expected only the application file but got %v %v", filePaths, err)
+	}
+	if err := utils.ValidateConflictStrategy("mine"); err == nil {
+		t.Errorf("Expected an error for an invalid conflict strategy")
+	}
+}
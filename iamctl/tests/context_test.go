@@ -0,0 +1,123 @@
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestRequestCancellation(t *testing.T) {
+
+	requestStarted := make(chan struct{}, 1)
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requestStarted <- struct{}{}
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+			w.Write([]byte(`{}`))
+		}
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-requestStarted
+		cancel()
+	}()
+	startTime := time.Now()
+	if _, err := utils.SendJsonRequest(ctx, http.MethodGet, utils.APPLICATIONS, "", nil); err == nil {
+		t.Errorf("Expected an error when the request is cancelled")
+	}
+	if elapsed := time.Since(startTime); elapsed >= 5*time.Second {
+		t.Errorf("Expected the request to be cancelled without waiting for the response but it took: %s", elapsed)
+	}
+
+	// Requests with a cancelled context are not sent.
+	if _, err := utils.SendGetListRequest(ctx, utils.APPLICATIONS, -1); err == nil {
+		t.Errorf("Expected an error when the context is already cancelled")
+	}
+	select {
+	case <-requestStarted:
+		t.Errorf("Expected the request not to be sent with a cancelled context")
+	default:
+	}
+}
+
+func TestInterruptHandling(t *testing.T) {
+
+	defer utils.StopInterruptHandling()
+	if utils.GetRequestContext() == nil || utils.IsCancelled() {
+		t.Fatalf("Expected a background context before the interrupt handling is started")
+	}
+
+	utils.StartInterruptHandling()
+	if !utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected the resources to be processed before the tool is interrupted")
+	}
+	process, _ := os.FindProcess(os.Getpid())
+	if err := process.Signal(os.Interrupt); err != nil {
+		t.Fatalf("Unexpected error when interrupting the tool: %s", err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !utils.IsCancelled() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !utils.IsCancelled() {
+		t.Fatalf("Expected the request context to be cancelled when the tool is interrupted")
+	}
+	if utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected the remaining resources not to be processed after the tool is interrupted")
+	}
+
+	utils.StopInterruptHandling()
+	if utils.IsCancelled() {
+		t.Errorf("Expected a background context after the interrupt handling is stopped")
+	}
+}
+
+func TestAppListCancellation(t *testing.T) {
+
+	// The list call is cancelled while it is in flight, as when the tool is interrupted with Ctrl-C.
+	var cancel context.CancelFunc
+	listCancelled := false
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("limit") == "0" {
+			w.Write([]byte(`{"totalResults": 1}`))
+			return
+		}
+		listCancelled = true
+		cancel()
+		<-r.Context().Done()
+	})
+
+	defer func() { utils.SetRequestContext(context.Background()) }()
+
+	tempDir := newTempDir(t)
+	appFilePath := filepath.Join(tempDir, utils.APPLICATIONS, "hr-portal.yml")
+	os.MkdirAll(filepath.Dir(appFilePath), 0755)
+	ioutil.WriteFile(appFilePath, []byte("applicationName: hr-portal\n"), 0644)
+
+	for _, run := range []struct {
+		name    string
+		operate func()
+	}{
+		{"export", func() { applications.ExportAll(tempDir, "yaml") }},
+		{"import", func() { applications.ImportAll(tempDir) }},
+	} {
+		var ctx context.Context
+		ctx, cancel = context.WithCancel(context.Background())
+		utils.SetRequestContext(ctx)
+		listCancelled = false
+		run.operate()
+		cancel()
+		if !listCancelled {
+			t.Errorf("Expected the list call of the %s to be cancelled", run.name)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/cors"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Start a mock server with the deployed CORS origins, recording the requests that add or remove the origins.
+func newCorsServer(t *testing.T) (requests *[]string) {
+
+	requests = &[]string{}
+	origins := []map[string]string{
+		{"id": "o1", "url": "https://app.dev.example.com"},
+		{"id": "o2", "url": "https://old.dev.example.com"},
+	}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1/cors/origins")
+		switch r.Method {
+		case "GET":
+			response, _ := json.Marshal(origins)
+			w.Write(response)
+			return
+		case "POST":
+			var origin map[string]string
+			json.NewDecoder(r.Body).Decode(&origin)
+			*requests = append(*requests, "POST "+origin["url"])
+			origins = append(origins, map[string]string{"id": "o3", "url": origin["url"]})
+			w.WriteHeader(http.StatusCreated)
+		case "DELETE":
+			*requests = append(*requests, "DELETE "+strings.TrimPrefix(path, "/"))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"SPA_HOST": "app.dev.example.com"}}
+	return requests
+}
+
+func TestExportCorsOrigins(t *testing.T) {
+
+	newCorsServer(t)
+	tempDir := newTempDir(t)
+
+	cors.ExportAll(tempDir)
+	exportedContent, err := ioutil.ReadFile(filepath.Join(tempDir, utils.CORS, "cors.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	expectedContent := "origins:\n- https://app.dev.example.com\n- https://old.dev.example.com\n"
+	if string(exportedContent) != expectedContent {
+		t.Fatalf("Expected the exported content:\n%s\nbut got:\n%s", expectedContent, exportedContent)
+	}
+}
+
+func TestImportCorsOrigins(t *testing.T) {
+
+	testCases := []struct {
+		name             string
+		localContent     string
+		allowDelete      bool
+		expectedRequests []string
+	}{
+		{
+			name:         "An invalid origin fails the import before any request is sent",
+			localContent: "origins:\n- https://{{SPA_HOST}}\n- https://new.example.com/login\n",
+		},
+		{
+			name:             "Missing origins are added and extra origins are kept",
+			localContent:     "origins:\n- https://{{SPA_HOST}}\n- https://new.example.com\n",
+			expectedRequests: []string{"POST https://new.example.com"},
+		},
+		{
+			name:             "Extra origins are removed when deleting is allowed",
+			localContent:     "origins:\n- https://{{SPA_HOST}}\n- https://new.example.com\n",
+			allowDelete:      true,
+			expectedRequests: []string{"POST https://new.example.com", "DELETE o2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := newCorsServer(t)
+			utils.TOOL_CONFIGS.AllowDelete = tc.allowDelete
+			utils.ASSUME_YES = true
+			defer func() { utils.ASSUME_YES = false }()
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.CORS), map[string]string{"cors.yml": tc.localContent})
+
+			cors.ImportAll(tempDir)
+			if strings.Join(*requests, ",") != strings.Join(tc.expectedRequests, ",") {
+				t.Fatalf("Expected requests %v but got %v", tc.expectedRequests, *requests)
+			}
+		})
+	}
+}
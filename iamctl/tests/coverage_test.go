@@ -0,0 +1,76 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestResourceCoverage(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super")
+		switch path {
+		case "/api/server/v1/applications":
+			w.Write([]byte(`{"totalResults": 5, "applications": [{"id": "app1", "name": "hr-portal"}]}`))
+		case "/api/server/v1/claim-dialects":
+			w.Write([]byte(`[{"id": "local"}, {"id": "oidc"}, {"id": "scim2"}]`))
+		case "/api/server/v1/notification-senders/email":
+			w.Write([]byte(`[{"name": "EmailPublisher"}]`))
+		case "/scim2/v2/Roles":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tempDir := newTempDir(t)
+	appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+	if err := os.MkdirAll(appDirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+	}
+	for _, fileName := range []string{"hr-portal.yml", "hr-portal.authscript.js", "payroll.yml"} {
+		if err := ioutil.WriteFile(filepath.Join(appDirPath, fileName), []byte("applicationName: app\n"), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the local file: %s", err)
+		}
+	}
+
+	// The auth script of an application is not counted as a separate resource.
+	managedCounts, err := utils.GetLocalManagedCounts(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when counting the local resources: %s", err)
+	}
+	if managedCounts[utils.APPLICATIONS] != 2 {
+		t.Fatalf("Expected 2 managed applications but got %d", managedCounts[utils.APPLICATIONS])
+	}
+
+	coverages := make(map[string]utils.ResourceCoverage)
+	for _, coverage := range utils.GetResourceCoverage(managedCounts) {
+		coverages[coverage.ResourceType] = coverage
+	}
+	tests := []struct {
+		resourceType string
+		deployed     int
+		managed      int
+		reason       string
+	}{
+		{utils.APPLICATIONS, 5, 2, ""},
+		{utils.CLAIMS, 3, 0, ""},
+		{utils.NOTIFICATION_SENDERS, 1, 0, ""},
+		{utils.ROLES, -1, 0, utils.COVERAGE_PERMISSION_DENIED},
+		{utils.WORKFLOWS, -1, 0, utils.COVERAGE_NOT_AVAILABLE},
+		{utils.XACML_POLICIES, -1, 0, utils.COVERAGE_NOT_COUNTABLE},
+	}
+	for _, test := range tests {
+		coverage := coverages[test.resourceType]
+		if coverage.Deployed != test.deployed || coverage.Managed != test.managed || coverage.Reason != test.reason {
+			t.Errorf("Expected the coverage of %s to be %d deployed, %d managed and reason %q but got %+v",
+				test.resourceType, test.deployed, test.managed, test.reason, coverage)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestDependencyFile(t *testing.T) {
+
+	tempDir := newTempDir(t)
+
+	files := map[string]string{
+		"Applications/crm.yml": "applicationName: crm\nclaimConfig:\n  claimMappings:\n" +
+			"  - localClaim:\n      claimUri: http://wso2.org/claims/email\n" +
+			"  - localClaim:\n      claimUri: http://wso2.org/claims/country\n" +
+			"inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n  - inboundAuthType: oauth2\n" +
+			"    inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+			"      oauthConsumerSecret: '{{CRM_CLIENT_SECRET}}'\n" +
+			"localAndOutBoundAuthenticationConfig:\n  authenticationSteps:\n  - federatedIdentityProviders:\n" +
+			"    - identityProviderName: Google\n    - identityProviderName: Google\n",
+		"IdentityProviders/Google.yml": "identityProviderName: Google\nclaimConfig:\n  claimMappings:\n" +
+			"  - localClaim:\n      claimUri: http://wso2.org/claims/email\n",
+		"Roles/crm-viewer.yml": "displayName: crm-viewer\naudience:\n  display: crm\n  type: application\n" +
+			"permissions:\n- value: orders:read\n- value: internal_login\n",
+		"ApiResources/Orders.yml": "identifier: https://orders.example.com\nscopes:\n- name: orders:read\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(tempDir, name)
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+
+	if err := utils.WriteDependencyFile(tempDir); err != nil {
+		t.Fatalf("Unexpected error when writing the dependency file: %s", err)
+	}
+	content, err := ioutil.ReadFile(filepath.Join(tempDir, utils.DEPENDENCIES_FILE))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the dependency file: %s", err)
+	}
+	expectedContent := "applications:\n  crm:\n    identityProviders:\n    - Google\n    claims:\n" +
+		"    - http://wso2.org/claims/country\n    - http://wso2.org/claims/email\n    apiResources:\n" +
+		"      https://orders.example.com:\n      - orders:read\n    scopes:\n    - internal_login\n" +
+		"    secrets:\n      oauthConsumerSecret: '{{CRM_CLIENT_SECRET}}'\nidentityProviders:\n  Google:\n" +
+		"    claims:\n    - http://wso2.org/claims/email\n"
+	if !strings.HasSuffix(string(content), expectedContent) {
+		t.Fatalf("Expected the dependency file to end with:\n%s\nbut got:\n%s", expectedContent, content)
+	}
+
+	graph, err := utils.BuildDependencyGraph(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when building the dependency graph: %s", err)
+	}
+	tree, err := utils.FormatDependencyTree(graph, "crm")
+	if err != nil || !strings.Contains(tree, "  Identity providers:\n    Google\n      Claims:\n        http://wso2.org/claims/email\n") {
+		t.Errorf("Expected the dependency tree with the claims of the identity provider but got:\n%s %v", tree, err)
+	}
+	for _, resourceName := range []string{"Google", "CRM_CLIENT_SECRET", "orders:read"} {
+		reverse, err := utils.FormatReverseDependencies(graph, resourceName)
+		if err != nil || !strings.Contains(reverse, "Applications/crm") {
+			t.Errorf("Expected the application to reference %s but got:\n%s %v", resourceName, reverse, err)
+		}
+	}
+	if _, err := utils.FormatDependencyTree(graph, "unknown"); err == nil {
+		t.Errorf("Expected an error for an unknown resource")
+	}
+
+	// The entries of the removed resources are pruned.
+	os.Remove(filepath.Join(tempDir, "IdentityProviders/Google.yml"))
+	if err := utils.WriteDependencyFile(tempDir); err != nil {
+		t.Fatalf("Unexpected error when writing the dependency file: %s", err)
+	}
+	content, _ = ioutil.ReadFile(filepath.Join(tempDir, utils.DEPENDENCIES_FILE))
+	if strings.Contains(string(content), "identityProviders:\n  Google:") {
+		t.Errorf("Expected the removed identity provider to be pruned but got:\n%s", content)
+	}
+	os.Remove(filepath.Join(tempDir, "Applications/crm.yml"))
+	if err := utils.WriteDependencyFile(tempDir); err != nil {
+		t.Fatalf("Unexpected error when writing the dependency file: %s", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, utils.DEPENDENCIES_FILE)); !os.IsNotExist(err) {
+		t.Errorf("Expected the dependency file to be removed when no resource has dependencies")
+	}
+}
@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+)
+
+func TestFindDuplicateApps(t *testing.T) {
+
+	tempDir := newTempDir(t)
+
+	oauthApp := func(name string, id string, clientId string, callbackUrl string) string {
+		return "applicationName: " + name + "\napplicationID: " + id + "\ndescription: Portal\n" +
+			"inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+			"  - inboundAuthKey: " + clientId + "\n    inboundAuthType: oauth2\n" +
+			"    inboundConfigurationProtocol:\n      oauthConsumerKey: " + clientId + "\n" +
+			"      callbackUrl: " + callbackUrl + "\n"
+	}
+	scriptApp := func(name string) string {
+		return "applicationName: " + name + "\nlocalAndOutBoundAuthenticationConfig:\n" +
+			"  authenticationScriptConfig:\n    content: file://" + name + ".authscript.js\n"
+	}
+	files := map[string]string{
+		"HR Portal.yml":            oauthApp("HR Portal", "1", "client1", "https://hr.example.com"),
+		"HR Portal copy.yml":       oauthApp("HR Portal copy", "2", "client2", "https://hr.example.com"),
+		"Payroll.yml":              oauthApp("Payroll", "3", "client3", "https://payroll.example.com"),
+		"Not exported.yml":         oauthApp("Not exported", "4", "client4", "https://hr.example.com"),
+		"Script app.yml":           scriptApp("Script app"),
+		"Script app.authscript.js": "var onLoginRequest = function(context) {};\n",
+		"Other app.yml":            scriptApp("Other app"),
+		"Other app.authscript.js":  "var onLoginRequest = function(context) { executeStep(1); };\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+
+	// Applications that differ only in the names, ids and client ids are duplicates, while the applications with
+	// different scripts are not, even though the script references only differ in the names.
+	appNames := []string{"HR Portal", "HR Portal copy", "Payroll", "Script app", "Other app"}
+	duplicateApps, err := applications.FindDuplicateApps(tempDir, appNames)
+	expectedDuplicates := [][]string{{"HR Portal", "HR Portal copy"}}
+	if err != nil || !reflect.DeepEqual(duplicateApps, expectedDuplicates) {
+		t.Errorf("Expected the duplicate applications %v but got %v %v", expectedDuplicates, duplicateApps, err)
+	}
+
+	// Applications with the same script content are duplicates.
+	if err := ioutil.WriteFile(filepath.Join(tempDir, "Other app.authscript.js"),
+		[]byte(files["Script app.authscript.js"]), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	duplicateApps, err = applications.FindDuplicateApps(tempDir, []string{"Script app", "Other app", "Payroll"})
+	expectedDuplicates = [][]string{{"Other app", "Script app"}}
+	if err != nil || !reflect.DeepEqual(duplicateApps, expectedDuplicates) {
+		t.Errorf("Expected the duplicate applications %v but got %v %v", expectedDuplicates, duplicateApps, err)
+	}
+}
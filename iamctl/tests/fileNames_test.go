@@ -0,0 +1,184 @@
+package tests
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestGetSafeFileName(t *testing.T) {
+
+	testCases := map[string]string{
+		"HR Portal":     "HR Portal",
+		"payments/prod": "payments%2Fprod",
+		"HR Portal: EU": "HR Portal%3A EU",
+		`a\b|c?d*e`:     "a%5Cb%7Cc%3Fd%2Ae",
+		"<tag>":         "%3Ctag%3E",
+		"portal.":       "portal%2E",
+		"portal. ":      "portal%2E%20",
+		"v1.2 app":      "v1.2 app",
+		"CON":           "_CON",
+		"nul.app":       "_nul.app",
+		"Console":       "Console",
+		"tab\tapp":      "tab%09app",
+	}
+	for name, expected := range testCases {
+		if fileName := utils.GetSafeFileName(name); fileName != expected {
+			t.Errorf("Expected the file name of %q to be %q but got %q", name, expected, fileName)
+		}
+	}
+}
+
+// Start a mock server with the applications of the list, which can be changed between the exports.
+func newFileNamesServer(t *testing.T, appList *[]string) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		switch {
+		case r.Method == "GET" && path == "/applications/":
+			var apps []string
+			for _, name := range *appList {
+				apps = append(apps, fmt.Sprintf(`{"id": "%s", "name": "%s"}`, getTestAppId(name), name))
+			}
+			w.Write([]byte(fmt.Sprintf(`{"totalResults": %d, "applications": [%s]}`, len(apps),
+				strings.Join(apps, ","))))
+		case r.Method == "GET" && strings.HasSuffix(path, "/exportFile"):
+			appId := strings.TrimSuffix(strings.TrimPrefix(path, "/applications/"), "/exportFile")
+			for _, name := range *appList {
+				if getTestAppId(name) == appId {
+					w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.yml"`, name))
+					w.Write([]byte(fmt.Sprintf("applicationName: '%s'\ndescription: %s\n", name, appId)))
+				}
+			}
+		case r.Method == "GET" && strings.HasPrefix(path, "/applications/app-"):
+			w.Write([]byte(`{}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+}
+
+func TestExportedFileNames(t *testing.T) {
+
+	idHash := sha256.Sum256([]byte(getTestAppId("payments")))
+	suffixedFileName := "payments-" + hex.EncodeToString(idHash[:])[:utils.FILE_NAME_SUFFIX_LENGTH]
+	expectedFiles := map[string]string{
+		"payments%2Fprod.yml":     "payments/prod",
+		"HR Portal%3A EU.yml":     "HR Portal: EU",
+		"Payments.yml":            "Payments",
+		suffixedFileName + ".yml": "payments",
+		"Portal.yml":              "Portal",
+	}
+	testCases := []struct {
+		name     string
+		appLists [][]string
+	}{
+		{
+			name:     "Unsafe and clashing names are written to safe file names",
+			appLists: [][]string{{"payments/prod", "HR Portal: EU", "Payments", "payments", "Portal"}},
+		},
+		{
+			name: "The file names are kept when the applications are listed in a different order",
+			appLists: [][]string{{"payments/prod", "HR Portal: EU", "Payments", "payments", "Portal"},
+				{"Portal", "payments", "HR Portal: EU", "Payments", "payments/prod"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var appList []string
+			newFileNamesServer(t, &appList)
+			tempDir := newTempDir(t)
+			appsDir := filepath.Join(tempDir, utils.APPLICATIONS)
+
+			// A file written with the application name by an earlier version is replaced by the file with the safe name.
+			writeFiles(t, appsDir, map[string]string{"HR Portal: EU.yml": "applicationName: 'HR Portal: EU'\n"})
+			for _, appList = range tc.appLists {
+				applications.ExportAll(tempDir, "yaml")
+			}
+
+			files, err := ioutil.ReadDir(appsDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var fileNames, expectedFileNames []string
+			for _, file := range files {
+				fileNames = append(fileNames, file.Name())
+			}
+			for fileName, appName := range expectedFiles {
+				expectedFileNames = append(expectedFileNames, fileName)
+				filePath := filepath.Join(appsDir, fileName)
+				if resolvedName := utils.ResolveResourceName(filePath); resolvedName != appName {
+					t.Errorf("Expected the file %s to resolve to %q but got %q", fileName, appName, resolvedName)
+				}
+				content, _ := ioutil.ReadFile(filePath)
+				if !strings.Contains(string(content), "description: "+getTestAppId(appName)) {
+					t.Errorf("Expected the file %s to have the content of %q but got:\n%s", fileName, appName, content)
+				}
+			}
+			sort.Strings(fileNames)
+			sort.Strings(expectedFileNames)
+			if !reflect.DeepEqual(fileNames, expectedFileNames) {
+				t.Errorf("Expected the exported files %v but got %v", expectedFileNames, fileNames)
+			}
+
+			mapping, err := ioutil.ReadFile(filepath.Join(tempDir, utils.FILE_NAMES_FILE))
+			if err != nil {
+				t.Fatalf("Expected the file names mapping to be written: %s", err)
+			}
+			for _, entry := range []string{"payments%2Fprod: payments/prod", "HR Portal%3A EU: 'HR Portal: EU'",
+				suffixedFileName + ": payments"} {
+				if !strings.Contains(string(mapping), entry) {
+					t.Errorf("Expected the file names mapping to contain %q but got:\n%s", entry, mapping)
+				}
+			}
+			if strings.Contains(string(mapping), "Portal: Portal") {
+				t.Errorf("Expected the applications with valid file names to be left out of the mapping:\n%s", mapping)
+			}
+
+			localNames, err := utils.GetLocalResourceNames(appsDir, nil)
+			sort.Strings(localNames)
+			expectedNames := []string{"HR Portal: EU", "Payments", "Portal", "payments", "payments/prod"}
+			if err != nil || !reflect.DeepEqual(localNames, expectedNames) {
+				t.Errorf("Expected the local application names %v but got %v %v", expectedNames, localNames, err)
+			}
+		})
+	}
+}
+
+func TestFileNamesMappingRemoved(t *testing.T) {
+
+	appList := []string{"payments/prod", "Portal"}
+	newFileNamesServer(t, &appList)
+	tempDir := newTempDir(t)
+	applications.ExportAll(tempDir, "yaml")
+	if _, err := os.Stat(filepath.Join(tempDir, utils.FILE_NAMES_FILE)); err != nil {
+		t.Fatalf("Expected the file names mapping to be written: %s", err)
+	}
+
+	// The mapping is removed when no application needs a different file name.
+	appList = []string{"Portal"}
+	defaultPrune := utils.PRUNE_EXPORT
+	utils.PRUNE_EXPORT = true
+	defer func() { utils.PRUNE_EXPORT = defaultPrune }()
+	applications.ExportAll(tempDir, "yaml")
+	if _, err := os.Stat(filepath.Join(tempDir, utils.FILE_NAMES_FILE)); !os.IsNotExist(err) {
+		t.Errorf("Expected the file names mapping to be removed after the applications are pruned")
+	}
+}
+
+func getTestAppId(appName string) string {
+
+	return "app-" + hex.EncodeToString([]byte(appName))
+}
@@ -0,0 +1,83 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestGetIdp(t *testing.T) {
+
+	var exportQueries []string
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		switch {
+		case r.Method == "GET" && path == "/identity-providers/":
+			w.Write([]byte(`{"totalResults": 1, "identityProviders": [{"id": "idp-1", "name": "Google"}]}`))
+		case r.Method == "GET" && path == "/identity-providers/idp-1/export":
+			exportQueries = append(exportQueries, r.URL.RawQuery)
+			w.Header().Set("Content-Disposition", `attachment; filename="Google.yml"`)
+			w.Write([]byte(googleIdp))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.SERVER_CONFIGS = utils.ServerConfigs{ServerUrl: server.URL, TenantDomain: "carbon.super", Token: "token",
+		ClientId: "iamctl-client"}
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+	tempDir := newTempDir(t)
+	utils.AUDIT_LOG = filepath.Join(tempDir, "audit.log")
+	utils.OpenAuditLog()
+	defer func() {
+		utils.CloseAuditLog()
+		utils.AUDIT_LOG = ""
+	}()
+
+	// The sensitive fields are masked by default, and the access is not recorded.
+	content, err := identityproviders.Get("Google", false)
+	if err != nil {
+		t.Fatalf("Unexpected error when getting the identity provider: %s", err)
+	}
+	if strings.Contains(string(content), "deployed-secret") || !strings.Contains(string(content), "google-client") {
+		t.Errorf("Expected the identity provider with the secrets masked but got:\n%s", content)
+	}
+
+	// The secrets are requested from the server when they are shown.
+	content, err = identityproviders.Get("Google", true)
+	if err != nil {
+		t.Fatalf("Unexpected error when getting the identity provider: %s", err)
+	}
+	if !strings.Contains(string(content), "deployed-secret") {
+		t.Errorf("Expected the identity provider with the secrets but got:\n%s", content)
+	}
+	if len(exportQueries) != 2 || !strings.Contains(exportQueries[0], "excludeSecrets=true") ||
+		!strings.Contains(exportQueries[1], "excludeSecrets=false") {
+		t.Errorf("Expected the secrets to be excluded only in the first export request but got %v", exportQueries)
+	}
+	if _, err := identityproviders.Get("Missing", true); err == nil {
+		t.Errorf("Expected an error for an identity provider that is not deployed")
+	}
+
+	auditLog, err := ioutil.ReadFile(utils.AUDIT_LOG)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the audit log: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(auditLog)), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("Expected a single audit record of the secret access but got:\n%s", auditLog)
+	}
+	var record utils.AuditRecord
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("Unexpected error when parsing the audit record: %s", err)
+	}
+	if record.Operation != utils.AUDIT_SECRET_ACCESS || record.ResourceName != "Google" || !record.Success {
+		t.Errorf("Expected a successful secret access record of the identity provider but got %+v", record)
+	}
+}
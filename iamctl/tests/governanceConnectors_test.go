@@ -0,0 +1,128 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	governanceconnectors "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/governanceConnectors"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const localConnectorCategory = `id: c2lnbg
+name: User Onboarding
+connectors:
+- id: c2VsZg
+  name: self-sign-up
+  properties:
+  - name: SelfRegistration.CallbackRegex
+    value: https://{{HOST}}/.*
+`
+
+// Start a mock server with a deployed connector category, recording the paths and the bodies of the updates.
+func newGovernanceConnectorServer(t *testing.T) (patchedPaths *[]string, patchedContent *[]string) {
+
+	patchedPaths, patchedContent = &[]string{}, &[]string{}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/identity-governance"):
+			w.Write([]byte(`[{"id": "c2lnbg", "name": "User Onboarding", "connectors": [{"id": "c2VsZg"}]}]`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/identity-governance/c2lnbg"):
+			w.Write([]byte(`{"id": "c2lnbg", "name": "User Onboarding", "connectors": [{"id": "c2VsZg",
+				"name": "self-sign-up", "friendlyName": "Self Registration", "properties": [
+				{"name": "SelfRegistration.Enable", "value": "false", "displayName": "Enable"},
+				{"name": "SelfRegistration.CallbackRegex", "value": "https://dev.example.com/.*"},
+				{"name": "SelfRegistration.LockOnCreation", "value": "true"}]}]}`))
+		case r.Method == "PATCH":
+			body, _ := ioutil.ReadAll(r.Body)
+			*patchedPaths = append(*patchedPaths, r.URL.Path)
+			*patchedContent = append(*patchedContent, string(body))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	return patchedPaths, patchedContent
+}
+
+func TestExportGovernanceConnectors(t *testing.T) {
+
+	newGovernanceConnectorServer(t)
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"HOST": "dev.example.com"}}
+	tempDir := newTempDir(t)
+	writeFiles(t, filepath.Join(tempDir, utils.GOVERNANCE_CONNECTORS),
+		map[string]string{"User Onboarding.yml": localConnectorCategory})
+
+	// Keywords of the local file are kept and the properties of the server are added in the export.
+	governanceconnectors.ExportAll(tempDir)
+	exportedContent, err := ioutil.ReadFile(filepath.Join(tempDir, utils.GOVERNANCE_CONNECTORS, "User Onboarding.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	for _, expected := range []string{"value: https://{{HOST}}/.*", "name: SelfRegistration.Enable\n    value: \"false\"",
+		"friendlyName: Self Registration"} {
+		if !strings.Contains(string(exportedContent), expected) {
+			t.Errorf("Expected exported content to contain %q but got:\n%s", expected, exportedContent)
+		}
+	}
+	if strings.Contains(string(exportedContent), "displayName") {
+		t.Errorf("Expected exported content without the display names of the properties but got:\n%s", exportedContent)
+	}
+}
+
+func TestImportGovernanceConnectors(t *testing.T) {
+
+	testCases := []struct {
+		name            string
+		host            string
+		localContent    string
+		expectedUpdates []string
+	}{
+		{
+			name:         "Unchanged properties are not updated",
+			host:         "dev.example.com",
+			localContent: localConnectorCategory + "  - name: SelfRegistration.LockOnCreation\n    value: \"true\"\n",
+		},
+		{
+			name:         "Only the changed properties are updated",
+			host:         "prod.example.com",
+			localContent: localConnectorCategory + "  - name: SelfRegistration.LockOnCreation\n    value: \"true\"\n",
+			expectedUpdates: []string{`{"operation":"UPDATE","properties":[{"name":"SelfRegistration.CallbackRegex",` +
+				`"value":"https://prod.example.com/.*"}]}`},
+		},
+		{
+			name: "Unknown connectors are skipped",
+			host: "dev.example.com",
+			localContent: localConnectorCategory + `- id: bmV3
+  name: new-connector
+  properties:
+  - name: New.Enable
+    value: "true"
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			patchedPaths, patchedContent := newGovernanceConnectorServer(t)
+			utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"HOST": tc.host}}
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.GOVERNANCE_CONNECTORS),
+				map[string]string{"User Onboarding.yml": tc.localContent})
+
+			governanceconnectors.ImportAll(tempDir)
+			if len(*patchedContent) != len(tc.expectedUpdates) {
+				t.Fatalf("Expected the connector updates %v but got %v", tc.expectedUpdates, *patchedContent)
+			}
+			for i, expected := range tc.expectedUpdates {
+				if !strings.HasSuffix((*patchedPaths)[i], "/identity-governance/c2lnbg/connectors/c2VsZg") {
+					t.Errorf("Expected the update of the known connector but got %s", (*patchedPaths)[i])
+				}
+				if (*patchedContent)[i] != expected {
+					t.Errorf("Expected connector update request %s but got %s", expected, (*patchedContent)[i])
+				}
+			}
+		})
+	}
+}
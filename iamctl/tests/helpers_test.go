@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Start a mock server of the target environment and point the server configs of the tool to it, with an access
+// token, until the end of the test. The configs can be replaced by the test and are restored after the test.
+func newMockServer(t *testing.T, handler http.HandlerFunc) *httptest.Server {
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	restoreConfigs(t)
+	utils.SERVER_CONFIGS = utils.ServerConfigs{ServerUrl: server.URL, TenantDomain: "carbon.super", Token: "token"}
+	return server
+}
+
+// Restore the server, tool and keyword configs of the tool and reset the summary after the test, so that the test
+// can replace the configs.
+func restoreConfigs(t *testing.T) {
+
+	configs := utils.GetRunConfigs()
+	t.Cleanup(func() {
+		utils.SetRunConfigs(configs)
+		utils.ResetSummary()
+	})
+}
+
+// Create a temporary directory that is removed after the test.
+func newTempDir(t *testing.T) string {
+
+	tempDir, err := ioutil.TempDir("", "iamctl")
+	if err != nil {
+		t.Fatalf("Unexpected error when creating temp directory: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(tempDir) })
+	return tempDir
+}
+
+// Write the files with the given names and contents to the directory, creating the directory if it does not exist.
+func writeFiles(t *testing.T, dirPath string, files map[string]string) {
+
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the directory: %s", err)
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(dirPath, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+}
@@ -0,0 +1,367 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestMutualTLSClient(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	// Create a self-signed CA and a client certificate issued by the CA.
+	caKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "iamctl-test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the CA certificate: %s", err)
+	}
+	caCert, _ := x509.ParseCertificate(caDER)
+
+	clientKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "iamctl-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the client certificate: %s", err)
+	}
+
+	certFile := filepath.Join(tempDir, "client.crt")
+	keyFile := filepath.Join(tempDir, "client.key")
+	encryptedKeyFile := filepath.Join(tempDir, "client-encrypted.key")
+	ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER}), 0600)
+	keyDER := x509.MarshalPKCS1PrivateKey(clientKey)
+	ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: keyDER}), 0600)
+	encryptedBlock, err := x509.EncryptPEMBlock(rand.Reader, "RSA PRIVATE KEY", keyDER, []byte("secret"), x509.PEMCipherAES256)
+	if err != nil {
+		t.Fatalf("Unexpected error when encrypting the client key: %s", err)
+	}
+	ioutil.WriteFile(encryptedKeyFile, pem.EncodeToMemory(encryptedBlock), 0600)
+
+	// Start a server that requires a client certificate issued by the CA.
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	caPool := x509.NewCertPool()
+	caPool.AddCert(caCert)
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  caPool,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		name          string
+		serverConfigs utils.ServerConfigs
+		passphrase    string
+		expectConfig  bool
+		expectSuccess bool
+	}{
+		{
+			name:          "Client certificate",
+			serverConfigs: utils.ServerConfigs{ClientCertFile: certFile, ClientKeyFile: keyFile},
+			expectConfig:  true,
+			expectSuccess: true,
+		},
+		{
+			name:          "Encrypted client key",
+			serverConfigs: utils.ServerConfigs{ClientCertFile: certFile, ClientKeyFile: encryptedKeyFile},
+			passphrase:    "secret",
+			expectConfig:  true,
+			expectSuccess: true,
+		},
+		{
+			name:          "Encrypted client key without passphrase",
+			serverConfigs: utils.ServerConfigs{ClientCertFile: certFile, ClientKeyFile: encryptedKeyFile},
+			expectConfig:  false,
+		},
+		{
+			name:          "Client key without certificate",
+			serverConfigs: utils.ServerConfigs{ClientKeyFile: keyFile},
+			expectConfig:  false,
+		},
+		{
+			name:          "No client certificate",
+			serverConfigs: utils.ServerConfigs{},
+			expectConfig:  true,
+			expectSuccess: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(utils.IAMCTL_CLIENT_KEY_PASSPHRASE, tc.passphrase)
+			defer os.Unsetenv(utils.IAMCTL_CLIENT_KEY_PASSPHRASE)
+
+			client, err := utils.NewHttpClient(tc.serverConfigs)
+			if (err == nil) != tc.expectConfig {
+				t.Fatalf("Expected client configuration success to be %v but got error: %v", tc.expectConfig, err)
+			}
+			if err != nil {
+				return
+			}
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err == nil) != tc.expectSuccess {
+				t.Errorf("Expected request success to be %v but got error: %v", tc.expectSuccess, err)
+			}
+		})
+	}
+}
+
+func TestHttpMiddlewareAndTracing(t *testing.T) {
+	var receivedHeaders http.Header
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		receivedHeaders = r.Header
+	})
+	var exportedTraces []byte
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		exportedTraces, _ = ioutil.ReadAll(r.Body)
+	}))
+	defer collector.Close()
+
+	utils.UseHttpMiddleware(func(next http.RoundTripper) http.RoundTripper {
+		return utils.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Signature", "signed")
+			return next.RoundTrip(req)
+		})
+	})
+	utils.OTEL_ENDPOINT = collector.URL
+	defer func() { utils.OTEL_ENDPOINT = "" }()
+	utils.StartTracing("iamctl test")
+
+	client, err := utils.NewHttpClient(utils.ServerConfigs{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the HTTP client: %s", err)
+	}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Unexpected error when sending the request: %s", err)
+	}
+	resp.Body.Close()
+	utils.FinishTracing()
+
+	if receivedHeaders.Get("X-Signature") != "signed" {
+		t.Errorf("Expected the header added by the custom middleware")
+	}
+	traceparent := strings.Split(receivedHeaders.Get(utils.TRACEPARENT_HEADER), "-")
+	if len(traceparent) != 4 || len(traceparent[1]) != 32 || len(traceparent[2]) != 16 {
+		t.Fatalf("Expected a W3C traceparent header but got: %v", receivedHeaders.Get(utils.TRACEPARENT_HEADER))
+	}
+	if !strings.Contains(string(exportedTraces), `"traceId":"`+traceparent[1]+`"`) ||
+		!strings.Contains(string(exportedTraces), `"spanId":"`+traceparent[2]+`"`) {
+		t.Errorf("Expected the span of the request to be exported, but got: %s", exportedTraces)
+	}
+}
+
+func TestTracingDoesNotBlockRequests(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {})
+
+	// The collector does not respond until it is released, like a collector that is unavailable.
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	var exportedSpans int
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		body, _ := ioutil.ReadAll(r.Body)
+		mutex.Lock()
+		exportedSpans += strings.Count(string(body), `"spanId"`)
+		mutex.Unlock()
+	}))
+	defer collector.Close()
+
+	utils.OTEL_ENDPOINT = collector.URL
+	defer func() { utils.OTEL_ENDPOINT = "" }()
+	utils.StartTracing("iamctl test")
+	client, err := utils.NewHttpClient(utils.ServerConfigs{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the HTTP client: %s", err)
+	}
+	sendRequests := func(count int) *sync.WaitGroup {
+		var wg sync.WaitGroup
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp, err := client.Get(server.URL); err == nil {
+					resp.Body.Close()
+				}
+			}()
+		}
+		return &wg
+	}
+
+	requestsDone := make(chan struct{})
+	go func() {
+		sendRequests(2 * utils.SPAN_EXPORT_BATCH_SIZE).Wait()
+		close(requestsDone)
+	}()
+	select {
+	case <-requestsDone:
+	case <-time.After(3 * time.Second):
+		t.Fatalf("Expected the requests not to wait for the export of the spans")
+	}
+
+	// Requests sent while the tracing is finished must not fail.
+	close(release)
+	wg := sendRequests(10)
+	utils.FinishTracing()
+	wg.Wait()
+
+	if exportedSpans < 2*utils.SPAN_EXPORT_BATCH_SIZE+1 {
+		t.Errorf("Expected the spans of all requests and the root span to be exported, but got %d spans", exportedSpans)
+	}
+	if utils.IsTracingEnabled() {
+		t.Errorf("Expected the tracing to be disabled after it is finished")
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	testCases := []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{name: "JSON", body: `{"clientId":"abc","clientSecret":"xyz"}`, expected: `{"clientId":"abc","clientSecret":"********"}`},
+		{name: "Form", body: "grant_type=client_credentials&client_secret=xyz", expected: "grant_type=client_credentials&client_secret=********"},
+		{name: "YAML", body: "name: App1\n  oauthConsumerSecret: xyz\n", expected: "name: App1\n  oauthConsumerSecret: '********'\n"},
+		{name: "No secrets", body: "applicationName: App1", expected: "applicationName: App1"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := utils.RedactBody([]byte(tc.body)); result != tc.expected {
+				t.Errorf("Expected %s but got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestCheckServerConnection(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tokenStatus int
+		apiStatus   int
+		expected    []string
+	}{
+		{"Successful connection", http.StatusOK, http.StatusOK, nil},
+		{"Invalid client credentials", http.StatusUnauthorized, http.StatusOK, []string{"401", "client secret"}},
+		{"Missing scopes", http.StatusOK, http.StatusForbidden, []string{"403", "correct scopes"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if strings.HasSuffix(r.URL.Path, "/oauth2/token") {
+					w.WriteHeader(tc.tokenStatus)
+					w.Write([]byte(`{"access_token": "token"}`))
+					return
+				}
+				if r.Header.Get("Authorization") != "Bearer token" {
+					t.Errorf("Expected the access token to be used but got %s", r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tc.apiStatus)
+			})
+
+			err := utils.CheckServerConnection(utils.ServerConfigs{
+				ServerUrl:    server.URL,
+				ClientId:     "client",
+				ClientSecret: "secret",
+				TenantDomain: utils.DEFAULT_TENANT_DOMAIN,
+			})
+			if tc.expected == nil {
+				if err != nil {
+					t.Errorf("Unexpected error: %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("Expected an error but got none")
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Expected the error to contain %q but got %s", expected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestSendPostListRequest(t *testing.T) {
+
+	var requests []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		requests = append(requests, r.Method+" "+r.URL.Path+" "+string(body))
+		w.Write([]byte(`{"totalResults": 0, "Resources": []}`))
+	})
+
+	testCases := []struct {
+		name          string
+		filter        interface{}
+		count         int
+		expectedBody  string
+		expectedError bool
+	}{
+		{"filter with count", map[string]interface{}{"filter": `userName sw "dev"`}, 10,
+			`{"count":10,"filter":"userName sw \"dev\""}`, false},
+		{"filter without count", struct {
+			Filter string `json:"filter"`
+		}{"userName eq john"}, -1, `{"filter":"userName eq john"}`, false},
+		{"no filter", nil, 5, `{"count":5}`, false},
+		{"filter that is not an object", []string{"userName"}, -1, "", true},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests = nil
+			resp, err := utils.SendPostListRequest(context.Background(), "/scim2/Users/.search", tc.filter, tc.count)
+			if tc.expectedError {
+				if err == nil {
+					t.Errorf("Expected an error for the filter %v", tc.filter)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			resp.Body.Close()
+			expected := "POST /t/carbon.super/scim2/Users/.search " + tc.expectedBody
+			if len(requests) != 1 || requests[0] != expected {
+				t.Errorf("Expected request %q but got %v", expected, requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const googleIdp = `identityProviderName: Google
+federatedAuthenticatorConfigs:
+- name: GoogleOIDCAuthenticator
+  enabled: true
+  properties:
+  - name: ClientId
+    value: google-client
+  - name: ClientSecret
+    value: deployed-secret
+    confidential: true
+`
+
+// Start a mock server with a deployed federated identity provider and a local authenticator, recording the updated
+// identity providers by the id and the updated local authenticator.
+func newAuthenticatorServer(t *testing.T) (updatedIdps map[string]string,
+	updatedAuthenticator *identityproviders.LocalAuthenticatorConfig) {
+
+	updatedIdps, updatedAuthenticator = make(map[string]string), &identityproviders.LocalAuthenticatorConfig{}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		switch {
+		case r.Method == "GET" && path == "/identity-providers/":
+			w.Write([]byte(`{"totalResults": 1, "identityProviders": [{"id": "idp-1", "name": "Google"}]}`))
+		case r.Method == "GET" && path == "/identity-providers/idp-1/export":
+			w.Header().Set("Content-Disposition", `attachment; filename="Google.yml"`)
+			w.Write([]byte(googleIdp))
+		case r.Method == "GET" && path == "/identity-providers/LOCAL/export":
+			w.Header().Set("Content-Disposition", `attachment; filename="LOCAL.yml"`)
+			w.Write([]byte("identityProviderName: LOCAL\n"))
+		case r.Method == "PUT" && strings.HasSuffix(path, "/import"):
+			file, _, _ := r.FormFile("file")
+			content, _ := ioutil.ReadAll(file)
+			updatedIdps[strings.Split(path, "/")[2]] = string(content)
+		case r.Method == "GET" && path == "/configs/authenticators":
+			w.Write([]byte(`[{"id": "sms-otp-id", "name": "sms-otp", "type": "LOCAL"},
+				{"id": "request-path-id", "name": "BasicAuthRequestPathAuthenticator", "type": "REQUEST_PATH"}]`))
+		case r.Method == "GET" && path == "/configs/authenticators/sms-otp-id":
+			w.Write([]byte(`{"id": "sms-otp-id", "name": "sms-otp", "displayName": "SMS OTP", "isEnabled": true,
+				"properties": [{"key": "SmsOTP.OtpLength", "value": "6"}, {"key": "SmsOTP.ApiKey", "value": "sms-key"}]}`))
+		case r.Method == "PUT" && path == "/configs/authenticators/sms-otp-id":
+			json.NewDecoder(r.Body).Decode(updatedAuthenticator)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{ExcludeSecrets: true}
+	return updatedIdps, updatedAuthenticator
+}
+
+const exportedResidentIdp = `identityProviderName: LOCAL
+localAuthenticatorConfigs:
+- displayName: SMS OTP
+  isEnabled: true
+  name: sms-otp
+  properties:
+  - key: SmsOTP.ApiKey
+    value: ` + utils.SENSITIVE_FIELD_MASK + `
+  - key: SmsOTP.OtpLength
+    value: "6"
+`
+
+func TestExportAuthenticatorConfigs(t *testing.T) {
+
+	newAuthenticatorServer(t)
+	tempDir := newTempDir(t)
+	idpsDir := filepath.Join(tempDir, utils.IDENTITY_PROVIDERS)
+
+	// The confidential properties of the federated authenticators and the secrets of the local authenticators are masked.
+	identityproviders.ExportAll(tempDir, "yaml")
+	googleContent, err := ioutil.ReadFile(filepath.Join(idpsDir, "Google.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if strings.Contains(string(googleContent), "deployed-secret") || !strings.Contains(string(googleContent), "google-client") {
+		t.Errorf("Expected only the confidential property to be masked in the exported content but got:\n%s", googleContent)
+	}
+	residentContent, err := ioutil.ReadFile(filepath.Join(idpsDir, "LOCAL.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if string(residentContent) != exportedResidentIdp {
+		t.Fatalf("Expected the exported resident identity provider:\n%s\nbut got:\n%s", exportedResidentIdp, residentContent)
+	}
+}
+
+func TestImportAuthenticatorConfigs(t *testing.T) {
+
+	updatedIdps, updatedAuthenticator := newAuthenticatorServer(t)
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+	tempDir := newTempDir(t)
+	writeFiles(t, filepath.Join(tempDir, utils.IDENTITY_PROVIDERS), map[string]string{
+		"Google.yml": strings.Replace(googleIdp, "deployed-secret", utils.SENSITIVE_FIELD_MASK, 1),
+		"LOCAL.yml":  strings.Replace(exportedResidentIdp, `value: "6"`, `value: "8"`, 1),
+	})
+
+	// The masked secrets keep the deployed values, and the local authenticators are updated with the configs API.
+	identityproviders.ImportAll(tempDir)
+	if !strings.Contains(updatedIdps["idp-1"], "value: deployed-secret") {
+		t.Errorf("Expected the deployed secret in the updated identity provider but got:\n%s", updatedIdps["idp-1"])
+	}
+	if updatedIdps["LOCAL"] != "identityProviderName: LOCAL\n" {
+		t.Errorf("Expected the resident identity provider without the local authenticators but got:\n%s", updatedIdps["LOCAL"])
+	}
+	expectedProperties := []identityproviders.AuthenticatorProperty{
+		{Key: "SmsOTP.ApiKey", Value: "sms-key"}, {Key: "SmsOTP.OtpLength", Value: "8"}}
+	if updatedAuthenticator.Name != "sms-otp" || !updatedAuthenticator.IsEnabled ||
+		!reflect.DeepEqual(updatedAuthenticator.Properties, expectedProperties) {
+		t.Errorf("Expected the local authenticator to be updated with the properties %v but got %+v", expectedProperties, updatedAuthenticator)
+	}
+}
+
+func TestConcurrentIdpExport(t *testing.T) {
+
+	var mutex sync.Mutex
+	var activeRequests, maxActiveRequests int
+	idps := []string{}
+	for i := 1; i <= 8; i++ {
+		idps = append(idps, fmt.Sprintf(`{"id": "idp-%d", "name": "Idp%d"}`, i, i))
+	}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1/identity-providers/")
+		switch {
+		case path == "":
+			w.Write([]byte(`{"totalResults": 8, "identityProviders": [` + strings.Join(idps, ",") + `]}`))
+		case strings.HasPrefix(path, "idp-") && strings.HasSuffix(path, "/export"):
+			mutex.Lock()
+			activeRequests++
+			if activeRequests > maxActiveRequests {
+				maxActiveRequests = activeRequests
+			}
+			mutex.Unlock()
+			time.Sleep(20 * time.Millisecond)
+			mutex.Lock()
+			activeRequests--
+			mutex.Unlock()
+
+			idpName := "Idp" + strings.TrimSuffix(strings.TrimPrefix(path, "idp-"), "/export")
+			w.Header().Set("Content-Disposition", `attachment; filename="`+idpName+`.yml"`)
+			w.Write([]byte("identityProviderName: " + idpName + "\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{ExcludeSecrets: true, IdpConfigs: map[string]interface{}{
+		utils.EXCLUDE_CONFIG: []interface{}{utils.RESIDENT_IDP_NAME, "Idp8"}}}
+	utils.EXPORT_CONCURRENCY = 3
+	defer func() { utils.EXPORT_CONCURRENCY = utils.DEFAULT_EXPORT_CONCURRENCY }()
+
+	tempDir := newTempDir(t)
+
+	identityproviders.ExportAll(tempDir, "yaml")
+	if maxActiveRequests < 2 || maxActiveRequests > 3 {
+		t.Errorf("Expected at most 3 identity providers to be fetched in parallel but got %d", maxActiveRequests)
+	}
+	files, _ := ioutil.ReadDir(filepath.Join(tempDir, utils.IDENTITY_PROVIDERS))
+	var fileNames []string
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+	expectedFileNames := []string{"Idp1.yml", "Idp2.yml", "Idp3.yml", "Idp4.yml", "Idp5.yml", "Idp6.yml", "Idp7.yml"}
+	if !reflect.DeepEqual(fileNames, expectedFileNames) {
+		t.Errorf("Expected the exported files %v but got %v", expectedFileNames, fileNames)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestSortByDependencies(t *testing.T) {
+
+	dependencies := map[string][]string{
+		utils.APPLICATIONS:       {utils.IDENTITY_PROVIDERS, utils.CLAIMS},
+		utils.IDENTITY_PROVIDERS: {utils.CLAIMS},
+	}
+	sorted, err := utils.SortByDependencies([]string{utils.APPLICATIONS, utils.CORS, utils.IDENTITY_PROVIDERS, utils.CLAIMS},
+		dependencies)
+	expected := []string{utils.CORS, utils.CLAIMS, utils.IDENTITY_PROVIDERS, utils.APPLICATIONS}
+	if err != nil || !reflect.DeepEqual(sorted, expected) {
+		t.Errorf("Expected the order %v but got %v %v", expected, sorted, err)
+	}
+
+	dependencies[utils.CLAIMS] = []string{utils.APPLICATIONS}
+	if _, err := utils.SortByDependencies([]string{utils.APPLICATIONS, utils.IDENTITY_PROVIDERS, utils.CLAIMS},
+		dependencies); err == nil || !strings.Contains(err.Error(), "cyclic dependency") {
+		t.Errorf("Expected an error for the cyclic dependency but got: %v", err)
+	}
+}
+
+func TestCheckIdpDependencies(t *testing.T) {
+
+	tempDir := newTempDir(t)
+	defer func() { utils.CONTINUE_ON_MISSING_DEPS = false }()
+
+	files := map[string]string{
+		"Applications/crm.yml": "applicationName: crm\nlocalAndOutBoundAuthenticationConfig:\n  authenticationSteps:\n" +
+			"  - federatedIdentityProviders:\n    - identityProviderName: Google\n    - identityProviderName: Okta\n" +
+			"  - federatedIdentityProviders:\n    - identityProviderName: '{{CORPORATE_IDP}}'\n",
+		"Applications/portal.yml": "applicationName: portal\noutboundProvisioningConfig:\n" +
+			"  provisioningIdentityProviders:\n  - identityProviderName: LOCAL\n",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(tempDir, name)
+		os.MkdirAll(filepath.Dir(filePath), 0755)
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"CORPORATE_IDP": "Azure AD"}}
+	defer func() { utils.KEYWORD_CONFIGS = utils.KeywordConfigs{} }()
+
+	availableIdps := func() ([]string, error) { return []string{"Google"}, nil }
+	err := utils.CheckIdpDependencies(tempDir, availableIdps)
+	if err == nil || !strings.Contains(err.Error(), "\n  crm: Okta, Azure AD\n") ||
+		!strings.Contains(err.Error(), "--continue-on-missing-deps") {
+		t.Errorf("Expected an error listing the missing identity providers of crm but got: %v", err)
+	}
+
+	utils.CONTINUE_ON_MISSING_DEPS = true
+	if err := utils.CheckIdpDependencies(tempDir, availableIdps); err != nil {
+		t.Errorf("Expected no error when continuing on missing dependencies but got: %s", err)
+	}
+	utils.CONTINUE_ON_MISSING_DEPS = false
+
+	allIdps := func() ([]string, error) { return []string{"Google", "Okta", "Azure AD"}, nil }
+	if err := utils.CheckIdpDependencies(tempDir, allIdps); err != nil {
+		t.Errorf("Expected no error when all identity providers are available but got: %s", err)
+	}
+
+	// The available identity providers are not retrieved if no included application references an identity provider.
+	utils.INCLUDE_ONLY = []string{"portal"}
+	defer func() { utils.INCLUDE_ONLY = nil }()
+	retrieved := false
+	if err := utils.CheckIdpDependencies(tempDir, func() ([]string, error) {
+		retrieved = true
+		return nil, nil
+	}); err != nil || retrieved {
+		t.Errorf("Expected the identity providers not to be retrieved but got: %v, retrieved: %t", err, retrieved)
+	}
+}
@@ -0,0 +1,154 @@
+package tests
+
+import (
+	"context"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+type junitReport struct {
+	Tests    int `xml:"tests,attr"`
+	Failures int `xml:"failures,attr"`
+	Skipped  int `xml:"skipped,attr"`
+	Suites   []struct {
+		Name      string `xml:"name,attr"`
+		TestCases []struct {
+			Name    string `xml:"name,attr"`
+			Failure *struct {
+				Message string `xml:"message,attr"`
+				Type    string `xml:"type,attr"`
+			} `xml:"failure"`
+			Skipped *struct {
+				Message string `xml:"message,attr"`
+			} `xml:"skipped"`
+		} `xml:"testcase"`
+	} `xml:"testsuite"`
+}
+
+func readJUnitReport(t *testing.T, reportPath string) junitReport {
+
+	content, err := ioutil.ReadFile(reportPath)
+	if err != nil {
+		t.Fatalf("Expected the JUnit report to be written: %s", err)
+	}
+	var report junitReport
+	if err := xml.Unmarshal(content, &report); err != nil {
+		t.Fatalf("Expected a valid JUnit report but got: %s\n%s", err, content)
+	}
+	return report
+}
+
+func TestJUnitReport(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"code": "APP-60001", "message": "Invalid request.", "description": "Invalid callback URL."}`))
+	})
+
+	tempDir := newTempDir(t)
+	reportPath := filepath.Join(tempDir, "report.xml")
+
+	utils.JUNIT_REPORT = reportPath
+	defer func() {
+		utils.JUNIT_REPORT = ""
+		utils.StartJUnitReport(utils.IMPORT)
+	}()
+
+	// The report is written before any resource is processed.
+	utils.StartJUnitReport(utils.IMPORT)
+	if report := readJUnitReport(t, reportPath); report.Tests != 0 {
+		t.Errorf("Expected an empty report at the start of the run but got %d tests", report.Tests)
+	}
+
+	utils.EmitResourceStarted(utils.APPLICATIONS, "hr-portal", utils.UPDATE)
+	utils.SendJsonRequest(context.Background(), http.MethodPut, utils.APPLICATIONS, "app-1", map[string]string{})
+	utils.UpdateFailureSummary(utils.APPLICATIONS, "hr-portal")
+
+	// The report is updated after each resource, so that the results are available if the run is aborted.
+	report := readJUnitReport(t, reportPath)
+	if report.Tests != 1 || report.Failures != 1 {
+		t.Fatalf("Expected the failed resource in the report but got: %+v", report)
+	}
+	failure := report.Suites[0].TestCases[0].Failure
+	if failure == nil || failure.Message != "400 Bad Request Invalid callback URL." || failure.Type != utils.UPDATE {
+		t.Errorf("Expected the failure with the status and the server error but got: %+v", failure)
+	}
+
+	utils.EmitResourceStarted(utils.APPLICATIONS, "payroll", utils.UPDATE)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "payroll", utils.UNCHANGED)
+	utils.EmitResourceStarted(utils.APPLICATIONS, "crm", utils.IMPORT)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "crm", utils.IMPORT)
+	utils.ReportSkippedResource(utils.APPLICATIONS, "Console", utils.JUNIT_SKIPPED_SYSTEM_APP)
+	utils.ReportSkippedResource(utils.APPLICATIONS, "Console", utils.JUNIT_SKIPPED_EXCLUDED)
+	utils.ReportSkippedResource(utils.IDENTITY_PROVIDERS, "Okta", utils.JUNIT_SKIPPED_EXCLUDED)
+	utils.AddFilteredResourceToSummary(utils.IDENTITY_PROVIDERS, "Google")
+
+	report = readJUnitReport(t, reportPath)
+	if report.Tests != 6 || report.Failures != 1 || report.Skipped != 4 || len(report.Suites) != 2 {
+		t.Fatalf("Unexpected totals of the report: %+v", report)
+	}
+	expectedSkipReasons := map[string]string{
+		"payroll": utils.JUNIT_SKIPPED_UNCHANGED,
+		"Console": utils.JUNIT_SKIPPED_SYSTEM_APP,
+		"Okta":    utils.JUNIT_SKIPPED_EXCLUDED,
+		"Google":  utils.JUNIT_SKIPPED_FILTERED,
+	}
+	for _, suite := range report.Suites {
+		for _, testCase := range suite.TestCases {
+			expectedReason, isSkipped := expectedSkipReasons[testCase.Name]
+			if isSkipped && (testCase.Skipped == nil || testCase.Skipped.Message != expectedReason) {
+				t.Errorf("Expected %s: %s to be skipped with the reason: %s", suite.Name, testCase.Name, expectedReason)
+			}
+			if !isSkipped && testCase.Name == "crm" && (testCase.Skipped != nil || testCase.Failure != nil) {
+				t.Errorf("Expected the imported resource to pass")
+			}
+		}
+	}
+}
+
+func TestJUnitReportOfFetchPool(t *testing.T) {
+
+	// Each identity provider fails with an error naming its own path.
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": "IDP-60002", "description": "Not found: ` + path.Base(r.URL.Path) + `"}`))
+	})
+
+	tempDir := newTempDir(t)
+	reportPath := filepath.Join(tempDir, "report.xml")
+
+	utils.JUNIT_REPORT = reportPath
+	defer func() {
+		utils.JUNIT_REPORT = ""
+		utils.StartJUnitReport(utils.EXPORT)
+	}()
+	utils.StartJUnitReport(utils.EXPORT)
+
+	idpIds := []string{"idp-1", "idp-2", "idp-3", "idp-4", "idp-5", "idp-6"}
+	pool := utils.StartFetchPool(len(idpIds), func(ctx context.Context, index int) (interface{}, error) {
+		return utils.SendJsonRequest(ctx, http.MethodGet, utils.IDENTITY_PROVIDERS, idpIds[index], nil)
+	})
+	defer pool.Stop()
+	for i, idpId := range idpIds {
+		utils.EmitResourceStarted(utils.IDENTITY_PROVIDERS, idpId, utils.EXPORT)
+		pool.Get(i)
+		utils.UpdateFailureSummary(utils.IDENTITY_PROVIDERS, idpId)
+	}
+
+	report := readJUnitReport(t, reportPath)
+	if report.Failures != len(idpIds) {
+		t.Fatalf("Expected all identity providers to fail but got: %+v", report)
+	}
+	for _, testCase := range report.Suites[0].TestCases {
+		expected := "404 Not Found Not found: " + testCase.Name
+		if testCase.Failure == nil || testCase.Failure.Message != expected {
+			t.Errorf("Expected the failure of %s to be: %s but got: %+v", testCase.Name, expected, testCase.Failure)
+		}
+	}
+}
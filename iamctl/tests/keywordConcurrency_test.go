@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Run with the -race flag to detect the concurrent modifications of the keyword configs.
+func TestConcurrentKeywordResolution(t *testing.T) {
+	const resourceCount = 200
+	const workerCount = 16
+
+	applicationConfigs := make(map[string]interface{})
+	for i := 0; i < resourceCount; i += 4 {
+		applicationConfigs[fmt.Sprintf("app-%d", i)] = map[string]interface{}{
+			utils.KEYWORD_MAPPINGS_CONFIG: map[string]interface{}{"HOST": fmt.Sprintf("app-%d.example.com", i)},
+		}
+	}
+	restoreConfigs(t)
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{
+		KeywordMappings:    map[string]interface{}{"HOST": "dev.example.com", "PORT": "9443"},
+		ApplicationConfigs: applicationConfigs,
+	}
+
+	content := "callbackUrl: https://{{HOST}}:{{PORT}}/callback\n"
+	resourceNames := make(chan string)
+	errors := make(chan error, resourceCount+4)
+	var workers sync.WaitGroup
+	for w := 0; w < workerCount; w++ {
+		workers.Add(1)
+		go func(worker int) {
+			defer workers.Done()
+			for resourceName := range resourceNames {
+				keywordMapping := utils.ResolveAdvancedKeywordMapping(resourceName, utils.KEYWORD_CONFIGS.ApplicationConfigs)
+				// Callers may modify the resolved mapping without affecting the other resources.
+				keywordMapping["WORKER"] = worker
+				expectedHost := "dev.example.com"
+				if _, ok := applicationConfigs[resourceName]; ok {
+					expectedHost = resourceName + ".example.com"
+				}
+				expected := "callbackUrl: https://" + expectedHost + ":9443/callback\n"
+				if resolved := utils.ReplaceKeywords(content, keywordMapping); resolved != expected {
+					errors <- fmt.Errorf("expected the content of %s to be %q but got %q", resourceName, expected, resolved)
+				}
+			}
+		}(w)
+	}
+
+	// Compare resources with the default mappings while the workers resolve the mappings of the resources.
+	stop := make(chan struct{})
+	var readers sync.WaitGroup
+	for r := 0; r < 4; r++ {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				localContent := utils.ReplaceKeywords(content, utils.KEYWORD_CONFIGS.KeywordMappings)
+				if !utils.IsContentEqual([]byte(localContent), []byte(localContent), nil) {
+					errors <- fmt.Errorf("expected the content to be equal to itself")
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 0; i < resourceCount; i++ {
+		resourceNames <- fmt.Sprintf("app-%d", i)
+	}
+	close(resourceNames)
+	workers.Wait()
+	close(stop)
+	readers.Wait()
+	close(errors)
+
+	for err := range errors {
+		t.Error(err)
+	}
+	if len(utils.KEYWORD_CONFIGS.KeywordMappings) != 2 || utils.KEYWORD_CONFIGS.KeywordMappings["HOST"] != "dev.example.com" {
+		t.Errorf("Expected the loaded keyword mappings to be unchanged but got: %v", utils.KEYWORD_CONFIGS.KeywordMappings)
+	}
+}
+
+func TestKeywordConfigReload(t *testing.T) {
+
+	configDir := newTempDir(t)
+	configFilePath := filepath.Join(configDir, utils.KEYWORD_CONFIG_FILE)
+	writeKeywordConfigs := func(host string) {
+		content := `{"KEYWORD_MAPPINGS": {"HOST": "` + host + `", "PORT": "9443"}}`
+		if err := ioutil.WriteFile(configFilePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the keyword config file: %s", err)
+		}
+	}
+	getHost := func() interface{} {
+		utils.HoldKeywordConfigs()
+		defer utils.ReleaseKeywordConfigs()
+		return utils.KEYWORD_CONFIGS.KeywordMappings["HOST"]
+	}
+	waitForHost := func(host string) bool {
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); {
+			if getHost() == host {
+				return true
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		return false
+	}
+
+	restoreConfigs(t)
+	writeKeywordConfigs("dev.example.com")
+	utils.LoadKeywordConfigs(configDir)
+
+	stopWatch, err := utils.WatchKeywordConfigs()
+	if err != nil {
+		t.Fatalf("Unexpected error when watching the keyword config file: %s", err)
+	}
+	defer stopWatch()
+
+	writeKeywordConfigs("staging.example.com")
+	if !waitForHost("staging.example.com") {
+		t.Fatalf("Expected the keyword configs to be reloaded but got the host: %v", getHost())
+	}
+
+	// A poll holding the keyword configs is exported with the keyword configs loaded when the poll started.
+	utils.HoldKeywordConfigs()
+	writeKeywordConfigs("prod.example.com")
+	time.Sleep(200 * time.Millisecond)
+	host := utils.KEYWORD_CONFIGS.KeywordMappings["HOST"]
+	utils.ReleaseKeywordConfigs()
+	if host != "staging.example.com" {
+		t.Errorf("Expected the keyword configs not to change while they are held but got the host: %v", host)
+	}
+	if !waitForHost("prod.example.com") {
+		t.Errorf("Expected the keyword configs to be reloaded after they are released but got the host: %v", getHost())
+	}
+
+	// Invalid content, such as a partially written file, does not replace the loaded keyword configs.
+	if err := ioutil.WriteFile(configFilePath, []byte(`{"KEYWORD_MAPPINGS": {`), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the keyword config file: %s", err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if host := getHost(); host != "prod.example.com" {
+		t.Errorf("Expected the loaded keyword configs to be kept for an invalid file but got the host: %v", host)
+	}
+}
@@ -613,6 +613,55 @@ func TestAddKeywords(t *testing.T) {
 	}
 }
 
+func TestAddKeywordsToClaimAttributeMappings(t *testing.T) {
+
+	exportedFileData := map[string]interface{}{
+		"claims": []interface{}{
+			map[string]interface{}{
+				"id": "aHR0cDovL3dzbzIub3JnL2NsYWltcy91c2VybmFtZQ",
+				"attributeMapping": []interface{}{
+					map[string]interface{}{"mappedAttribute": "uid", "userstore": "PRIMARY"},
+					map[string]interface{}{"mappedAttribute": "sAMAccountName", "userstore": "AD"},
+				},
+			},
+		},
+	}
+
+	localFileData := []byte(`
+        claims:
+          - id: aHR0cDovL3dzbzIub3JnL2NsYWltcy91c2VybmFtZQ
+            attributeMapping:
+              - mappedAttribute: uid
+                userstore: PRIMARY
+              - mappedAttribute: "{{AD_USERNAME_ATTRIBUTE}}"
+                userstore: AD
+        `)
+
+	expectedExportedFileData := map[string]interface{}{
+		"claims": []interface{}{
+			map[string]interface{}{
+				"id": "aHR0cDovL3dzbzIub3JnL2NsYWltcy91c2VybmFtZQ",
+				"attributeMapping": []interface{}{
+					map[string]interface{}{"mappedAttribute": "uid", "userstore": "PRIMARY"},
+					map[string]interface{}{"mappedAttribute": "{{AD_USERNAME_ATTRIBUTE}}", "userstore": "AD"},
+				},
+			},
+		},
+	}
+
+	keywordMapping := map[string]interface{}{
+		"AD_USERNAME_ATTRIBUTE": "sAMAccountName",
+	}
+	result, err := utils.AddKeywords(exportedFileData, localFileData, keywordMapping, utils.CLAIMS)
+	if err != nil {
+		log.Println("Error when adding keywords: ", err)
+	}
+
+	if !reflect.DeepEqual(result, expectedExportedFileData) {
+		t.Errorf("Expected %+v, but got %+v", expectedExportedFileData, result)
+	}
+}
+
 func TestModifyFieldsWithKeywords(t *testing.T) {
 
 	keywordLocations := []string{"key1", "nestedObject.subKey1.subSubKey2", "properties.[name=element1].subKey2"}
@@ -707,3 +756,104 @@ func TestGetPathKeys(t *testing.T) {
 		}
 	}
 }
+
+func TestAddKeywordPlaceholders(t *testing.T) {
+
+	testCases := []struct {
+		description    string
+		fileContent    string
+		keywordMapping map[string]interface{}
+		expectedResult string
+	}{
+		{
+			description: "Replace keyword value with placeholder",
+			fileContent: "callbackUrl: https://dev.example.com/callback\n",
+			keywordMapping: map[string]interface{}{
+				"CALLBACK_URL": "https://dev.example.com/callback",
+			},
+			expectedResult: "callbackUrl: '{{CALLBACK_URL}}'\n",
+		},
+		{
+			description: "Replace only whole values",
+			fileContent: "applicationName: dev\ndescription: Portal of the dev team\ndev: true\n" +
+				"inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      callbackUrl: https://dev.example.com/callback\n      port: 8443\n" +
+				"    properties:\n    - dev\n",
+			keywordMapping: map[string]interface{}{
+				"ENV":  "dev",
+				"PORT": "8443",
+			},
+			expectedResult: "applicationName: '{{ENV}}'\ndescription: Portal of the dev team\ndev: true\n" +
+				"inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundConfigurationProtocol:\n      !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      callbackUrl: https://dev.example.com/callback\n      port: 8443\n" +
+				"    properties:\n    - '{{ENV}}'\n",
+		},
+		{
+			description: "Ignore empty keyword values",
+			fileContent: "description: sample",
+			keywordMapping: map[string]interface{}{
+				"EMPTY": "",
+			},
+			expectedResult: "description: sample",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result, err := utils.AddKeywordPlaceholders(tc.fileContent, tc.keywordMapping)
+			if err != nil {
+				t.Fatalf("Unexpected error for %s: %s", tc.description, err)
+			}
+			if result != tc.expectedResult {
+				t.Errorf("Unexpected result for %s: expected %v, but got %v", tc.description, tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestLintKeywordEnvironments(t *testing.T) {
+	environments := []utils.KeywordEnvironment{
+		{
+			Name:     "staging",
+			Keywords: map[string]string{"CALLBACK_URL": "https://prod.example.com/callback", "THEME": "dark", "CDN_URL": "https://cdn.example.com"},
+			Hosts:    []string{"staging.example.com"},
+		},
+		{
+			Name:     "prod",
+			Keywords: map[string]string{"CALLBACK_URL": "https://prod.example.com/callback", "THEME": "dark", "CDN_URL": "https://cdn.example.com"},
+			Hosts:    []string{"prod.example.com"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		keys          []string
+		allowedShared []string
+		expected      []string
+	}{
+		{
+			name:          "URL keywords with suppression",
+			allowedShared: []string{"CDN_URL"},
+			expected: []string{
+				"Keyword CALLBACK_URL has the same value https://prod.example.com/callback in environments: staging, prod.",
+				"Keyword CALLBACK_URL in environment staging points to host prod.example.com of environment prod.",
+			},
+		},
+		{
+			name:     "Selected keywords",
+			keys:     []string{"THEME"},
+			expected: []string{"Keyword THEME has the same value dark in environments: staging, prod."},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			issues := utils.LintKeywordEnvironments(environments, tc.keys, tc.allowedShared)
+			if !reflect.DeepEqual(issues, tc.expected) {
+				t.Errorf("Expected issues %v but got %v", tc.expected, issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestResourceListOutput(t *testing.T) {
+
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimSuffix(r.URL.Path, "/")
+		switch {
+		case strings.HasSuffix(path, "/applications"):
+			w.Write([]byte(`{"totalResults": 2, "applications": [
+				{"id": "app-2", "name": "payroll", "accessUrl": "https://payroll.example.com", "templateId": "oidc-web"},
+				{"id": "app-1", "name": "hr-portal", "description": "HR portal"}]}`))
+		case strings.HasSuffix(path, "/identity-providers"):
+			w.Write([]byte(`{"totalResults": 1, "identityProviders": [
+				{"id": "idp-1", "name": "Google", "isEnabled": true, "image": "google.svg"}]}`))
+		case strings.HasSuffix(path, "/identity-providers/idp-1/federated-authenticators"):
+			w.Write([]byte(`{"authenticators": [{"name": "OpenIDConnectAuthenticator", "isEnabled": true}]}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	apps, err := applications.ListApps()
+	if err != nil {
+		t.Fatalf("Unexpected error when listing the applications: %s", err)
+	}
+	if len(apps) != 2 || apps[0].Name != "hr-portal" || apps[1].Attributes["accessUrl"] != "https://payroll.example.com" {
+		t.Fatalf("Expected the sorted applications with all attributes but got %v", apps)
+	}
+
+	var output bytes.Buffer
+	isMatchingName := utils.GetNameMatcher("^pay")
+	var resources []map[string]interface{}
+	for _, app := range apps {
+		if isMatchingName(app.Name) {
+			resources = append(resources, app.Attributes)
+		}
+	}
+	if err := utils.WriteResourceList(&output, utils.OUTPUT_JSON, resources); err != nil {
+		t.Fatalf("Unexpected error when writing the resource list: %s", err)
+	}
+	var listedApps []map[string]interface{}
+	if err := json.Unmarshal(output.Bytes(), &listedApps); err != nil {
+		t.Fatalf("Expected a JSON array but got: %s", output.String())
+	}
+	if len(listedApps) != 1 || listedApps[0]["name"] != "payroll" || listedApps[0]["templateId"] != "oidc-web" {
+		t.Errorf("Expected only the matching application with all attributes but got %v", listedApps)
+	}
+
+	// The authenticators retrieved separately are added to the attributes of the identity provider.
+	idps, err := identityproviders.ListIdps("")
+	if err != nil {
+		t.Fatalf("Unexpected error when listing the identity providers: %s", err)
+	}
+	output.Reset()
+	if err := utils.WriteResourceList(&output, utils.OUTPUT_YAML, []map[string]interface{}{idps[0].Attributes}); err != nil {
+		t.Fatalf("Unexpected error when writing the resource list: %s", err)
+	}
+	expectedYaml := "- federatedAuthenticators:\n    authenticators:\n    - name: OpenIDConnectAuthenticator\n" +
+		"      isEnabled: true\n  id: idp-1\n  image: google.svg\n  isEnabled: true\n  name: Google\n"
+	if output.String() != expectedYaml {
+		t.Errorf("Expected the YAML output:\n%s\nbut got:\n%s", expectedYaml, output.String())
+	}
+
+	// An empty list is written when no resource matches, and an invalid regular expression is used as a substring.
+	output.Reset()
+	utils.WriteResourceList(&output, utils.OUTPUT_JSON, nil)
+	if output.String() != "[]\n" {
+		t.Errorf("Expected an empty JSON array but got: %s", output.String())
+	}
+	if !utils.GetNameMatcher("hr(")("hr(portal") || utils.GetNameMatcher("hr(")("hr-portal") {
+		t.Errorf("Expected an invalid regular expression to be matched as a substring")
+	}
+}
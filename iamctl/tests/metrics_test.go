@@ -0,0 +1,63 @@
+//go:build metrics
+// +build metrics
+
+package tests
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestMetricsEndpoint(t *testing.T) {
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Unexpected error when finding a free port: %s", err)
+	}
+	utils.METRICS_PORT = listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	defer func() { utils.METRICS_PORT = 0 }()
+	utils.StartMetricsServer()
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {})
+	client, err := utils.NewHttpClient(utils.ServerConfigs{})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the HTTP client: %s", err)
+	}
+	resp, err := client.Get(server.URL + "/t/carbon.super/api/server/v1/applications/app-1/exportFile")
+	if err != nil {
+		t.Fatalf("Unexpected error when sending the request: %s", err)
+	}
+	resp.Body.Close()
+
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "app", utils.EXPORT)
+	utils.UpdateSuccessSummary(utils.ROLES, "admin", utils.DELETE)
+	utils.UpdateFailureSummary(utils.APPLICATIONS, "broken-app")
+
+	resp, err = http.Get("http://127.0.0.1:" + strconv.Itoa(utils.METRICS_PORT) + utils.METRICS_PATH)
+	if err != nil {
+		t.Fatalf("Unexpected error when retrieving the metrics: %s", err)
+	}
+	defer resp.Body.Close()
+	body, _ := ioutil.ReadAll(resp.Body)
+
+	expectedLines := []string{
+		`iamctl_resources_exported_total{resource_type="Applications"} 1`,
+		`iamctl_resources_deleted_total{resource_type="Roles"} 1`,
+		`iamctl_resources_failed_total{resource_type="Applications"} 1`,
+		`# TYPE iamctl_api_request_duration_seconds histogram`,
+		`iamctl_api_request_duration_seconds_count{resource_type="Applications",operation="export"} 1`,
+		`iamctl_api_request_duration_seconds_bucket{resource_type="Applications",operation="export",le="+Inf"} 1`,
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("Expected the metrics to contain %q but got:\n%s", line, body)
+		}
+	}
+}
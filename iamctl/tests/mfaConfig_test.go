@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+const mfaApp = `applicationName: mfa-app
+localAndOutBoundAuthenticationConfig:
+  authenticationType: default
+  subjectStepId: 1
+  authenticationSteps:
+  - stepOrder: 1
+    localAuthenticatorConfigs:
+    - name: BasicAuthenticator
+      displayName: Username & Password
+      enabled: true
+  - stepOrder: 2
+    localAuthenticatorConfigs:
+    - name: email-otp-authenticator
+      displayName: Email OTP
+      enabled: true
+mfaConfig:
+  steps:
+  - step: 2
+    options:
+    - idp: LOCAL
+      authenticator: totp
+    - idp: LOCAL
+      authenticator: email-otp-authenticator
+    - idp: Google
+      authenticator: GoogleOIDCAuthenticator
+`
+
+type mfaAppFile struct {
+	MfaConfig                            interface{} `yaml:"mfaConfig"`
+	LocalAndOutBoundAuthenticationConfig struct {
+		AuthenticationType  string `yaml:"authenticationType"`
+		AuthenticationSteps []struct {
+			StepOrder                 int `yaml:"stepOrder"`
+			LocalAuthenticatorConfigs []struct {
+				Name        string `yaml:"name"`
+				DisplayName string `yaml:"displayName"`
+			} `yaml:"localAuthenticatorConfigs"`
+			FederatedIdentityProviders []struct {
+				IdentityProviderName       string `yaml:"identityProviderName"`
+				DefaultAuthenticatorConfig struct {
+					Name string `yaml:"name"`
+				} `yaml:"defaultAuthenticatorConfig"`
+			} `yaml:"federatedIdentityProviders"`
+		} `yaml:"authenticationSteps"`
+	} `yaml:"localAndOutBoundAuthenticationConfig"`
+}
+
+func TestMfaConfigImport(t *testing.T) {
+
+	tests := []struct {
+		name        string
+		mfaConfig   string
+		expectError bool
+	}{
+		{
+			name: "steps are set from the MFA configurations",
+		},
+		{
+			name:        "MFA configurations for the first step are rejected",
+			mfaConfig:   "mfaConfig:\n  steps:\n  - step: 1\n    options:\n    - idp: LOCAL\n      authenticator: totp\n",
+			expectError: true,
+		},
+		{
+			name:        "options without an authenticator are rejected",
+			mfaConfig:   "mfaConfig:\n  steps:\n  - step: 2\n    options:\n    - idp: Google\n",
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var importedContent []byte
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/applications"):
+					w.Write([]byte(`{"totalResults": 0, "applications": []}`))
+				case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+					file, _, _ := r.FormFile("file")
+					importedContent, _ = ioutil.ReadAll(file)
+					w.WriteHeader(http.StatusCreated)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			utils.TOOL_CONFIGS = utils.ToolConfigs{ApplicationConfigs: map[string]interface{}{
+				utils.IMPORT_STRATEGY_CONFIG: utils.SINGLE_PHASE_IMPORT,
+			}}
+
+			tempDir := newTempDir(t)
+			appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+			if err := os.MkdirAll(appDirPath, 0755); err != nil {
+				t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+			}
+			content := mfaApp
+			if test.mfaConfig != "" {
+				content = content[:strings.Index(content, "mfaConfig:")] + test.mfaConfig
+			}
+			if err := ioutil.WriteFile(filepath.Join(appDirPath, "mfa-app.yml"), []byte(content), 0644); err != nil {
+				t.Fatalf("Unexpected error when writing the application file: %s", err)
+			}
+
+			applications.ImportAll(tempDir)
+			if test.expectError {
+				if importedContent != nil {
+					t.Errorf("Expected the application not to be imported but got:\n%s", importedContent)
+				}
+				return
+			}
+
+			var app mfaAppFile
+			if err := yaml.Unmarshal(importedContent, &app); err != nil {
+				t.Fatalf("Unexpected error when parsing the imported application: %s", err)
+			}
+			if app.MfaConfig != nil {
+				t.Errorf("Expected the mfaConfig key to be removed before the import")
+			}
+			authConfig := app.LocalAndOutBoundAuthenticationConfig
+			if authConfig.AuthenticationType != "flow" {
+				t.Errorf("Expected the authentication type: flow but got: %s", authConfig.AuthenticationType)
+			}
+			if len(authConfig.AuthenticationSteps) != 2 {
+				t.Fatalf("Expected 2 authentication steps but got %d", len(authConfig.AuthenticationSteps))
+			}
+			if authConfig.AuthenticationSteps[0].LocalAuthenticatorConfigs[0].Name != "BasicAuthenticator" {
+				t.Errorf("Expected the first step to be kept")
+			}
+			secondStep := authConfig.AuthenticationSteps[1]
+			var localAuthenticators []string
+			for _, authenticator := range secondStep.LocalAuthenticatorConfigs {
+				localAuthenticators = append(localAuthenticators, authenticator.Name+":"+authenticator.DisplayName)
+			}
+			if strings.Join(localAuthenticators, ",") != "totp:,email-otp-authenticator:Email OTP" {
+				t.Errorf("Expected the local authenticators in the order of priority but got %v", localAuthenticators)
+			}
+			if len(secondStep.FederatedIdentityProviders) != 1 ||
+				secondStep.FederatedIdentityProviders[0].IdentityProviderName != "Google" ||
+				secondStep.FederatedIdentityProviders[0].DefaultAuthenticatorConfig.Name != "GoogleOIDCAuthenticator" {
+				t.Errorf("Expected the Google identity provider in the second step but got %v",
+					secondStep.FederatedIdentityProviders)
+			}
+		})
+	}
+}
+
+func TestMfaConfigDependencies(t *testing.T) {
+
+	tempDir := newTempDir(t)
+	appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+	if err := os.MkdirAll(appDirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(appDirPath, "mfa-app.yml"), []byte(mfaApp), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the application file: %s", err)
+	}
+
+	graph, err := utils.BuildDependencyGraph(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when building the dependency graph: %s", err)
+	}
+	identityProviders := graph.Applications["mfa-app"].IdentityProviders
+	if strings.Join(identityProviders, ",") != "Google" {
+		t.Errorf("Expected the identity providers of the MFA configurations but got %v", identityProviders)
+	}
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestNamespaceFilter(t *testing.T) {
+	utils.NAMESPACE = "team-a-"
+	defer func() { utils.NAMESPACE = "" }()
+
+	testCases := []struct {
+		resourceName string
+		expected     bool
+	}{
+		{resourceName: "team-a-portal", expected: true},
+		{resourceName: "team-b-portal", expected: false},
+		{resourceName: "Console", expected: false},
+	}
+	for _, tc := range testCases {
+		if result := utils.IsResourceIncluded(tc.resourceName); result != tc.expected {
+			t.Errorf("Expected IsResourceIncluded(%q) to be %v but got %v", tc.resourceName, tc.expected, result)
+		}
+		if result := utils.IsResourceExcluded(tc.resourceName, nil); result == tc.expected {
+			t.Errorf("Expected IsResourceExcluded(%q) to be %v but got %v", tc.resourceName, !tc.expected, result)
+		}
+	}
+
+	var filter string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		filter = r.URL.Query().Get("filter")
+		w.Write([]byte(`{"applications": []}`))
+	})
+
+	resp, err := utils.SendGetListRequest(context.Background(), utils.APPLICATIONS, -1)
+	if err != nil {
+		t.Fatalf("Unexpected error when listing the applications: %s", err)
+	}
+	resp.Body.Close()
+	if filter != "name sw team-a-" {
+		t.Errorf("Expected the list request to be filtered by the namespace but got the filter: %q", filter)
+	}
+}
+
+func TestRemoveDeletedLocalResourcesInNamespace(t *testing.T) {
+	utils.NAMESPACE = "team-a-"
+	defer func() { utils.NAMESPACE = "" }()
+
+	tempDir := newTempDir(t)
+	for _, fileName := range []string{"team-a-portal.yml", "team-a-legacy.yml", "team-b-portal.yml"} {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, fileName), []byte("applicationName: app\n"), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+
+	utils.RemoveDeletedLocalResources(tempDir, []string{"team-a-portal"})
+
+	files, err := ioutil.ReadDir(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the directory: %s", err)
+	}
+	var fileNames []string
+	for _, file := range files {
+		fileNames = append(fileNames, file.Name())
+	}
+	sort.Strings(fileNames)
+	expected := []string{"team-a-portal.yml", "team-b-portal.yml"}
+	if len(fileNames) != len(expected) || fileNames[0] != expected[0] || fileNames[1] != expected[1] {
+		t.Errorf("Expected the files %v to be kept but got %v", expected, fileNames)
+	}
+}
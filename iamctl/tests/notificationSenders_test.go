@@ -0,0 +1,159 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	notificationsenders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/notificationSenders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Start a mock server with the deployed notification senders, recording the bodies of the requests that change the
+// senders by the method and the path of the request.
+func newNotificationSenderServer(t *testing.T) (requests map[string]map[string]interface{}) {
+
+	requests = make(map[string]map[string]interface{})
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/notification-senders/email"):
+			w.Write([]byte(`[{"name": "EmailPublisher", "smtpServerHost": "smtp.dev.example.com", "smtpPort": 587,
+				"fromAddress": "iam@dev.example.com", "userName": "iam", "password": "dev-password",
+				"properties": [{"key": "mail.smtp.starttls.enable", "value": "true"}]},
+				{"name": "LegacyPublisher", "smtpServerHost": "smtp.old.example.com", "smtpPort": 25}]`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/notification-senders/sms"):
+			w.Write([]byte(`[{"name": "SMSPublisher", "provider": "Twilio", "providerURL": "https://api.twilio.com",
+				"key": "dev-key", "secret": "dev-secret", "sender": "+15550100"}]`))
+		default:
+			var body map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&body)
+			requests[r.Method+" "+strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")] = body
+		}
+	})
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{
+		KeywordMappings: map[string]interface{}{"SMTP_HOST": "smtp.dev.example.com"},
+		NotificationSenderConfigs: map[string]interface{}{"BackupPublisher": map[string]interface{}{
+			utils.KEYWORD_MAPPINGS_CONFIG: map[string]interface{}{"SMTP_PASSWORD": "backup-password"}}},
+	}
+	return requests
+}
+
+func TestExportNotificationSenders(t *testing.T) {
+
+	newNotificationSenderServer(t)
+	tempDir := newTempDir(t)
+	emailDirPath := filepath.Join(tempDir, utils.NOTIFICATION_SENDERS, notificationsenders.EMAIL)
+	smsDirPath := filepath.Join(tempDir, utils.NOTIFICATION_SENDERS, notificationsenders.SMS)
+	writeFiles(t, emailDirPath, map[string]string{"EmailPublisher.yml": "name: EmailPublisher\nsmtpServerHost: '{{SMTP_HOST}}'\n"})
+
+	// Secrets are masked and the keywords of the local files are kept.
+	notificationsenders.ExportAll(tempDir)
+	for filePath, expectedContent := range map[string][]string{
+		filepath.Join(emailDirPath, "EmailPublisher.yml"): {"password: '********'", "smtpServerHost: '{{SMTP_HOST}}'",
+			"key: mail.smtp.starttls.enable"},
+		filepath.Join(smsDirPath, "SMSPublisher.yml"): {"key: '********'", "secret: '********'", "provider: Twilio"},
+	} {
+		content, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatalf("Unexpected error when reading the exported file: %s", err)
+		}
+		for _, expected := range expectedContent {
+			if !strings.Contains(string(content), expected) {
+				t.Errorf("Expected the exported content to contain %q but got:\n%s", expected, content)
+			}
+		}
+		if strings.Contains(string(content), "dev-") {
+			t.Errorf("Expected the exported content without secrets but got:\n%s", content)
+		}
+	}
+}
+
+func TestImportNotificationSenders(t *testing.T) {
+
+	secretEnvName := notificationsenders.GetSecretEnvName("SMSPublisher", "SMS_API_SECRET")
+	os.Setenv(secretEnvName, "prod-secret")
+	defer func() { os.Unsetenv(secretEnvName) }()
+
+	testCases := []struct {
+		name             string
+		emailFiles       map[string]string
+		smsFiles         map[string]string
+		expectedRequests map[string]map[string]interface{}
+	}{
+		{
+			name: "Masked secrets are kept as deployed",
+			emailFiles: map[string]string{"EmailPublisher.yml": "name: EmailPublisher\nsmtpServerHost: '{{SMTP_HOST}}'\n" +
+				"smtpPort: 587\nfromAddress: iam@dev.example.com\nuserName: iam\npassword: '********'\n"},
+			expectedRequests: map[string]map[string]interface{}{"PUT /notification-senders/email/EmailPublisher": {
+				"smtpServerHost": "smtp.prod.example.com", "password": "dev-password"}},
+		},
+		{
+			name: "Masked secrets are resolved from the environment variables",
+			smsFiles: map[string]string{"SMSPublisher.yml": "name: SMSPublisher\nprovider: Twilio\n" +
+				"providerURL: https://api.twilio.com\nkey: '********'\nsecret: '********'\nsender: '+15550100'\n"},
+			expectedRequests: map[string]map[string]interface{}{"PUT /notification-senders/sms/SMSPublisher": {
+				"key": "dev-key", "secret": "prod-secret"}},
+		},
+		{
+			name: "Masked secrets of a new sender are resolved from the keywords",
+			emailFiles: map[string]string{"BackupPublisher.yml": "name: BackupPublisher\n" +
+				"smtpServerHost: smtp.backup.example.com\npassword: '********'\n"},
+			expectedRequests: map[string]map[string]interface{}{"POST /notification-senders/email": {
+				"name": "BackupPublisher", "password": "backup-password"}},
+		},
+		{
+			name: "A new sender with a masked secret that cannot be resolved is not created",
+			emailFiles: map[string]string{"MissingPublisher.yml": "name: MissingPublisher\n" +
+				"smtpServerHost: smtp.backup.example.com\npassword: '********'\n"},
+		},
+		{
+			name:       "Senders are never deleted",
+			emailFiles: map[string]string{},
+			smsFiles:   map[string]string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests := newNotificationSenderServer(t)
+			utils.KEYWORD_CONFIGS.KeywordMappings["SMTP_HOST"] = "smtp.prod.example.com"
+			utils.TOOL_CONFIGS.AllowDelete = true
+			utils.ASSUME_YES = true
+			defer func() { utils.ASSUME_YES = false }()
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.NOTIFICATION_SENDERS, notificationsenders.EMAIL), tc.emailFiles)
+			writeFiles(t, filepath.Join(tempDir, utils.NOTIFICATION_SENDERS, notificationsenders.SMS), tc.smsFiles)
+
+			notificationsenders.ImportAll(tempDir)
+			var requestKeys, expectedKeys []string
+			for key := range requests {
+				requestKeys = append(requestKeys, key)
+			}
+			for key := range tc.expectedRequests {
+				expectedKeys = append(expectedKeys, key)
+			}
+			sort.Strings(requestKeys)
+			sort.Strings(expectedKeys)
+			if strings.Join(requestKeys, ",") != strings.Join(expectedKeys, ",") {
+				t.Fatalf("Expected requests %v but got %v", expectedKeys, requestKeys)
+			}
+			for requestKey, expectedAttributes := range tc.expectedRequests {
+				for attribute, expected := range expectedAttributes {
+					if requests[requestKey][attribute] != expected {
+						t.Errorf("Expected %s of the request %s to be %v but got %v", attribute, requestKey, expected,
+							requests[requestKey][attribute])
+					}
+				}
+				if _, ok := requests[requestKey]["name"]; ok && strings.HasPrefix(requestKey, "PUT") {
+					t.Errorf("Expected the update request without the name but got %v", requests[requestKey])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	oidcscopes "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/oidcScopes"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Start a mock server with the deployed OIDC scopes, recording the requests that change the scopes and their bodies.
+func newOidcScopeServer(t *testing.T) (requests *[]string, bodies map[string]string) {
+
+	requests, bodies = &[]string{}, map[string]string{}
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/oidc/scopes") {
+			w.Write([]byte(`[{"name": "openid", "displayName": "openid", "claims": ["sub"]},
+				{"name": "email", "displayName": "email", "claims": ["email", "email_verified"]},
+				{"name": "internal", "displayName": "Internal", "description": "Internal scope", "claims": []},
+				{"name": "legacy", "displayName": "Legacy", "claims": ["groups"]}]`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		request := r.Method + " " + strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		*requests = append(*requests, request)
+		bodies[request] = string(body)
+		w.WriteHeader(http.StatusOK)
+	})
+	utils.TOOL_CONFIGS = utils.ToolConfigs{
+		OidcScopeConfigs: map[string]interface{}{utils.EXCLUDE_CONFIG: []interface{}{"internal"}},
+	}
+	return requests, bodies
+}
+
+func TestExportOidcScopes(t *testing.T) {
+
+	newOidcScopeServer(t)
+	tempDir := newTempDir(t)
+
+	// Excluded scopes are not exported.
+	oidcscopes.ExportAll(tempDir)
+	scopeDirPath := filepath.Join(tempDir, utils.OIDC_SCOPES)
+	files, err := ioutil.ReadDir(scopeDirPath)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported directory: %s", err)
+	}
+	var exportedFiles []string
+	for _, file := range files {
+		exportedFiles = append(exportedFiles, file.Name())
+	}
+	sort.Strings(exportedFiles)
+	if strings.Join(exportedFiles, ",") != "email.yml,legacy.yml,openid.yml" {
+		t.Fatalf("Expected the exported files email.yml, legacy.yml and openid.yml but got %v", exportedFiles)
+	}
+	exportedContent, err := ioutil.ReadFile(filepath.Join(scopeDirPath, "email.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if !strings.Contains(string(exportedContent), "claims:\n- email\n- email_verified") {
+		t.Errorf("Expected the exported content to contain the claims of the scope but got:\n%s", exportedContent)
+	}
+}
+
+func TestImportOidcScopes(t *testing.T) {
+
+	deployedFiles := map[string]string{
+		"openid.yml": "name: openid\ndisplayName: openid\nclaims:\n- sub\n",
+		"email.yml":  "name: email\ndisplayName: email\nclaims:\n- email\n- email_verified\n",
+		"legacy.yml": "name: legacy\ndisplayName: Legacy\nclaims:\n- groups\n",
+	}
+	testCases := []struct {
+		name             string
+		localFiles       map[string]string
+		allowDelete      bool
+		expectedRequests []string
+	}{
+		{
+			name:       "Unchanged scopes are skipped",
+			localFiles: deployedFiles,
+		},
+		{
+			name: "The claims of a default scope are updated",
+			localFiles: map[string]string{
+				"email.yml": "name: email\ndisplayName: email\nclaims:\n- email_verified\n- email\n- email_alias\n",
+			},
+			expectedRequests: []string{"PUT /oidc/scopes/email"},
+		},
+		{
+			name:             "A new scope is created",
+			localFiles:       map[string]string{"orders.yml": "name: orders\ndisplayName: Orders\nclaims:\n- order_id\n"},
+			expectedRequests: []string{"POST /oidc/scopes"},
+		},
+		{
+			name:             "Locally removed scopes are deleted except the default and excluded scopes",
+			localFiles:       map[string]string{"email.yml": deployedFiles["email.yml"]},
+			allowDelete:      true,
+			expectedRequests: []string{"DELETE /oidc/scopes/legacy"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, bodies := newOidcScopeServer(t)
+			utils.TOOL_CONFIGS.AllowDelete = tc.allowDelete
+			utils.ASSUME_YES = true
+			defer func() { utils.ASSUME_YES = false }()
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.OIDC_SCOPES), tc.localFiles)
+
+			oidcscopes.ImportAll(tempDir)
+			sort.Strings(*requests)
+			if strings.Join(*requests, ",") != strings.Join(tc.expectedRequests, ",") {
+				t.Fatalf("Expected requests %v but got %v", tc.expectedRequests, *requests)
+			}
+			if body, ok := bodies["PUT /oidc/scopes/email"]; ok {
+				var updatedScope map[string]interface{}
+				if err := json.Unmarshal([]byte(body), &updatedScope); err != nil {
+					t.Fatalf("Unexpected error when reading the update request: %s", err)
+				}
+				if _, ok := updatedScope["name"]; ok || len(updatedScope["claims"].([]interface{})) != 3 {
+					t.Errorf("Expected the update request with the claims and without the name but got %s", body)
+				}
+			}
+		})
+	}
+}
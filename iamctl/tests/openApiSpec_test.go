@@ -0,0 +1,79 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+)
+
+func TestOpenApiSpecs(t *testing.T) {
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1")
+		switch path {
+		case "/applications", "/applications/":
+			w.Write([]byte(`{"totalResults": 3, "applications": [{"id": "app-1", "name": "hr-portal"},
+				{"id": "app-2", "name": "saml-app"}, {"id": "app-3", "name": "no-scopes"}]}`))
+		case "/applications/app-1/inbound-protocols/oidc":
+			w.Write([]byte(`{"grantTypes": ["authorization_code", "refresh_token", "urn:ietf:params:oauth:grant-type:jwt-bearer"]}`))
+		case "/applications/app-3/inbound-protocols/oidc":
+			w.Write([]byte(`{"grantTypes": ["client_credentials"]}`))
+		case "/applications/app-1/authorized-apis":
+			w.Write([]byte(`[{"id": "api-1", "identifier": "https://orders.example.com", "displayName": "Orders",
+				"authorizedScopes": [{"name": "write_orders", "displayName": "Write"}, {"name": "read_orders", "displayName": "Read"}]}]`))
+		case "/applications/app-3/authorized-apis":
+			w.Write([]byte(`[]`))
+		case "/api-resources/api-1/scopes":
+			w.Write([]byte(`[{"name": "read_orders", "displayName": "Read", "description": "Read the orders"},
+				{"name": "write_orders", "displayName": "Write"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tempDir := newTempDir(t)
+
+	applications.ExportOpenApiSpecs(tempDir)
+	specsDir := filepath.Join(tempDir, applications.OPENAPI_SPECS_DIR)
+	content, err := ioutil.ReadFile(filepath.Join(specsDir, "hr-portal.yaml"))
+	if err != nil {
+		t.Fatalf("Expected the OpenAPI spec of the OAuth2 application: %s", err)
+	}
+	tenantUrl := server.URL + "/t/carbon.super"
+	expected := `openapi: 3.0.3
+info:
+  title: hr-portal
+  description: 'OAuth2 scopes authorized for the application: hr-portal'
+  version: 1.0.0
+paths: {}
+components:
+  securitySchemes:
+    oauth2:
+      type: oauth2
+      flows:
+        authorizationCode:
+          authorizationUrl: ` + tenantUrl + `/oauth2/authorize
+          tokenUrl: ` + tenantUrl + `/oauth2/token
+          refreshUrl: ` + tenantUrl + `/oauth2/token
+          scopes:
+            read_orders: Read the orders
+            write_orders: Write
+security:
+- oauth2:
+  - read_orders
+  - write_orders
+`
+	if string(content) != expected {
+		t.Errorf("Expected the OpenAPI spec:\n%s\nbut got:\n%s", expected, content)
+	}
+
+	// Applications without the OIDC inbound protocol or without authorized scopes do not have a spec.
+	files, err := ioutil.ReadDir(specsDir)
+	if err != nil || len(files) != 1 {
+		t.Errorf("Expected only the spec of the application with authorized scopes but got %d files", len(files))
+	}
+}
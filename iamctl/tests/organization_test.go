@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestOrganizationSwitch(t *testing.T) {
+
+	var appListAuthorization string
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch strings.TrimSuffix(r.URL.Path, "/") {
+		case "/t/carbon.super/oauth2/token":
+			r.ParseForm()
+			if r.PostForm.Get("grant_type") == "organization_switch" {
+				if r.PostForm.Get("token") != "root-token" || r.PostForm.Get("switching_organization") != "org-id" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.Write([]byte(`{"access_token": "org-token"}`))
+				return
+			}
+			w.Write([]byte(`{"access_token": "root-token"}`))
+		case "/t/carbon.super/api/server/v1/organizations":
+			if r.URL.Query().Get("filter") != "name eq partner-org" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`{"organizations": [{"id": "org-id", "name": "partner-org"}]}`))
+		case "/t/carbon.super/o/api/server/v1/applications":
+			appListAuthorization = r.Header.Get("Authorization")
+			w.Write([]byte(`{"applications": []}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	tempDir := newTempDir(t)
+	configFiles := map[string]string{
+		utils.SERVER_CONFIG_FILE:  `{"SERVER_URL": "` + server.URL + `", "CLIENT_ID": "client", "CLIENT_SECRET": "secret"}`,
+		utils.TOOL_CONFIG_FILE:    `{}`,
+		utils.KEYWORD_CONFIG_FILE: `{}`,
+	}
+	for fileName, content := range configFiles {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, fileName), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the config file: %s", err)
+		}
+	}
+
+	utils.ORGANIZATION = "partner-org"
+	defer func() { utils.ORGANIZATION = "" }()
+
+	utils.LoadConfigs(tempDir)
+	if utils.SERVER_CONFIGS.Token != "org-token" || utils.SERVER_CONFIGS.OrganizationId != "org-id" {
+		t.Fatalf("Expected the token to be switched to the organization but got the configs: %+v", utils.SERVER_CONFIGS)
+	}
+	if _, err := utils.SendJsonRequest(context.Background(), http.MethodGet, utils.APPLICATIONS, "", nil); err != nil {
+		t.Fatalf("Unexpected error when listing the applications of the organization: %s", err)
+	}
+	if appListAuthorization != "Bearer org-token" {
+		t.Errorf("Expected the organization token in the application request but got: %s", appListAuthorization)
+	}
+
+	if !utils.IsResourceTypeExcluded(utils.CLAIMS) || utils.IsResourceTypeExcluded(utils.APPLICATIONS) {
+		t.Errorf("Expected only the organization resource types to be managed in the organization")
+	}
+	expectedDir := filepath.Join("export", utils.ORGANIZATIONS_DIR, "partner-org")
+	if dir := utils.GetOrganizationDir("export"); dir != expectedDir {
+		t.Errorf("Expected the organization directory %s but got %s", expectedDir, dir)
+	}
+}
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/organizations"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const exportedEngineeringOrg = `attributes:
+- key: costCenter
+  value: "1001"
+description: Engineering department
+name: Engineering
+parent: Acme
+sharedApplications:
+- hr-portal
+type: TENANT
+`
+
+// Start a mock server with the deployed organizations, recording the requests that change the organizations and
+// their bodies.
+func newOrganizationServer(t *testing.T) (requests *[]string, requestBodies map[string]map[string]interface{}) {
+
+	requests, requestBodies = &[]string{}, make(map[string]map[string]interface{})
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super")
+		switch {
+		case r.Method == "GET" && strings.HasPrefix(path, "/api/server/v1/applications"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/organizations":
+			if r.URL.Query().Get("recursive") != "true" {
+				t.Errorf("Expected the organizations of all levels to be listed but got the query: %s", r.URL.RawQuery)
+			}
+			w.Write([]byte(`{"organizations": [{"id": "o2", "name": "Engineering"}, {"id": "o1", "name": "Acme"}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/organizations/o1":
+			w.Write([]byte(`{"id": "o1", "name": "Acme", "status": "ACTIVE", "type": "TENANT", "parent": {"id": "root-id"}}`))
+		case r.Method == "GET" && path == "/api/server/v1/organizations/o2":
+			w.Write([]byte(`{"id": "o2", "name": "Engineering", "description": "Engineering department", "status": "ACTIVE",
+				"type": "TENANT", "parent": {"id": "o1"}, "attributes": [{"key": "costCenter", "value": "1001"}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/organizations/root-id/applications/app1/shared-organizations":
+			w.Write([]byte(`{"organizations": [{"id": "o2", "name": "Engineering"}]}`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			requestKey := r.Method + " " + strings.TrimPrefix(path, "/api/server/v1")
+			*requests = append(*requests, requestKey)
+			var payload map[string]interface{}
+			json.Unmarshal(body, &payload)
+			requestBodies[requestKey] = payload
+			if r.Method == "POST" && path == "/api/server/v1/organizations" {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": "new-` + payload["name"].(string) + `"}`))
+			}
+		}
+	})
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+	return requests, requestBodies
+}
+
+func TestExportOrganizations(t *testing.T) {
+
+	newOrganizationServer(t)
+	tempDir := newTempDir(t)
+
+	// The parents and the shared applications are exported by the names.
+	organizations.ExportAll(tempDir)
+	organizationsDir := filepath.Join(tempDir, utils.ORGANIZATIONS)
+	exportedContent, err := ioutil.ReadFile(filepath.Join(organizationsDir, "Engineering.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	for _, expected := range []string{"parent: Acme", "- key: costCenter", "sharedApplications:\n- hr-portal"} {
+		if !strings.Contains(string(exportedContent), expected) {
+			t.Errorf("Expected the exported content to contain %q but got:\n%s", expected, exportedContent)
+		}
+	}
+	rootContent, err := ioutil.ReadFile(filepath.Join(organizationsDir, "Acme.yml"))
+	if err != nil || strings.Contains(string(rootContent), "parent:") {
+		t.Errorf("Expected the top level organization without a parent but got:\n%s", rootContent)
+	}
+}
+
+func TestImportOrganizations(t *testing.T) {
+
+	testCases := []struct {
+		name             string
+		localFiles       map[string]string
+		expectedRequests []string
+		checkBodies      func(t *testing.T, requestBodies map[string]map[string]interface{})
+	}{
+		{
+			name: "A changed organization is updated and unshared from the removed applications",
+			localFiles: map[string]string{"Engineering.yml": strings.Replace(strings.Replace(exportedEngineeringOrg,
+				"Engineering department", "Engineering team", 1), "sharedApplications:\n- hr-portal\n", "", 1)},
+			expectedRequests: []string{"PUT /organizations/o2",
+				"DELETE /organizations/root-id/applications/app1/shared-organizations/o2"},
+			checkBodies: func(t *testing.T, requestBodies map[string]map[string]interface{}) {
+				if requestBodies["PUT /organizations/o2"]["description"] != "Engineering team" {
+					t.Errorf("Expected the updated description but got %v", requestBodies["PUT /organizations/o2"])
+				}
+			},
+		},
+		{
+			name: "New organizations are created after their parents and shared with the applications",
+			localFiles: map[string]string{
+				"Lab.yml":      "name: Lab\nparent: Research\nsharedApplications:\n- hr-portal\n",
+				"Research.yml": "name: Research\nparent: Engineering\n",
+			},
+			expectedRequests: []string{"POST /organizations", "POST /organizations",
+				"POST /organizations/root-id/applications/app1/share"},
+			checkBodies: func(t *testing.T, requestBodies map[string]map[string]interface{}) {
+				createdOrganization := requestBodies["POST /organizations"]
+				if createdOrganization["name"] != "Lab" || createdOrganization["parentId"] != "new-Research" {
+					t.Errorf("Expected the child organization to be created with the id of the created parent but got %v",
+						createdOrganization)
+				}
+				sharedOrganizations, _ := json.Marshal(
+					requestBodies["POST /organizations/root-id/applications/app1/share"]["sharedOrganizations"])
+				if string(sharedOrganizations) != `["new-Lab"]` {
+					t.Errorf("Expected the application to be shared with the created organization but got %s",
+						sharedOrganizations)
+				}
+			},
+		},
+		{
+			name: "Organizations in a cycle are not imported",
+			localFiles: map[string]string{
+				"Loop1.yml": "name: Loop1\nparent: Loop2\n",
+				"Loop2.yml": "name: Loop2\nparent: Loop1\n",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, requestBodies := newOrganizationServer(t)
+			tempDir := newTempDir(t)
+			organizationsDir := filepath.Join(tempDir, utils.ORGANIZATIONS)
+			writeFiles(t, organizationsDir, map[string]string{"Acme.yml": "name: Acme\ntype: TENANT\n",
+				"Engineering.yml": exportedEngineeringOrg})
+			writeFiles(t, organizationsDir, tc.localFiles)
+
+			organizations.ImportAll(tempDir)
+			if strings.Join(*requests, ",") != strings.Join(tc.expectedRequests, ",") {
+				t.Fatalf("Expected requests %v but got %v", tc.expectedRequests, *requests)
+			}
+			if tc.checkBodies != nil {
+				tc.checkBodies(t, requestBodies)
+			}
+		})
+	}
+}
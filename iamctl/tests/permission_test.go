@@ -0,0 +1,50 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestCheckResourcePermissions(t *testing.T) {
+
+	var requests []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.URL.Path)
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/identity-providers"), strings.HasSuffix(r.URL.Path, "/Users"):
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			w.Write([]byte(`{"totalResults": 0}`))
+		}
+	})
+
+	defer func() { utils.ALLOW_PARTIAL_PERMISSIONS = false }()
+
+	resourceTypes := []string{utils.IDENTITY_PROVIDERS, utils.APPLICATIONS, utils.USERS, utils.BRANDING}
+	err := utils.CheckResourcePermissions(resourceTypes)
+	if err == nil {
+		t.Fatalf("Expected an error when read access is denied for some resource types")
+	}
+	if !strings.Contains(err.Error(), "read access is denied for the resource types: IdentityProviders, Users, when probed with a GET request") {
+		t.Errorf("Expected the denied resource types in the error but got: %s", err)
+	}
+	// Branding does not have a probe and is not requested.
+	if len(requests) != 3 {
+		t.Errorf("Expected a probe request for each resource type with a list path but got: %v", requests)
+	}
+
+	// The probe results are reused within the run.
+	utils.ALLOW_PARTIAL_PERMISSIONS = true
+	if err := utils.CheckResourcePermissions(resourceTypes); err != nil {
+		t.Errorf("Expected no error when partial permissions are allowed but got: %s", err)
+	}
+	if len(requests) != 3 {
+		t.Errorf("Expected the probe results to be cached but got the requests: %v", requests)
+	}
+	if err := utils.CheckResourcePermissions([]string{utils.APPLICATIONS}); err != nil {
+		t.Errorf("Expected no error for the permitted resource types but got: %s", err)
+	}
+}
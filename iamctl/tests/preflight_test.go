@@ -0,0 +1,57 @@
+package tests
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestRunPreflightCheck(t *testing.T) {
+
+	tests := []struct {
+		name          string
+		status        int
+		delay         time.Duration
+		expectedError string
+	}{
+		{
+			name:   "server is available",
+			status: http.StatusOK,
+		},
+		{
+			name:          "server is not available",
+			status:        http.StatusServiceUnavailable,
+			expectedError: "Health check response status: 503 Service Unavailable",
+		},
+		{
+			name:          "server does not respond within the timeout",
+			status:        http.StatusOK,
+			delay:         time.Second,
+			expectedError: "did not respond within 100ms",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != utils.HEALTH_CHECK_PATH {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				time.Sleep(test.delay)
+				w.WriteHeader(test.status)
+			})
+
+			err := utils.RunPreflightCheck(utils.ServerConfigs{ServerUrl: server.URL + "/"}, 100*time.Millisecond)
+			if test.expectedError == "" && err != nil {
+				t.Errorf("Expected the pre-flight check to succeed but got: %s", err)
+			}
+			if test.expectedError != "" && (err == nil || !strings.Contains(err.Error(), test.expectedError)) {
+				t.Errorf("Expected an error containing %q but got: %v", test.expectedError, err)
+			}
+		})
+	}
+}
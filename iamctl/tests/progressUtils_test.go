@@ -0,0 +1,125 @@
+package tests
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestProgressEvents(t *testing.T) {
+	tempDir := newTempDir(t)
+	socketPath := filepath.Join(tempDir, "iamctl.sock")
+	if runtime.GOOS == "windows" {
+		socketPath = `\\.\pipe\` + filepath.Base(tempDir)
+	}
+
+	listener, err := utils.ListenProgressSocket(socketPath)
+	if err != nil {
+		t.Fatalf("Unexpected error when listening on the socket: %s", err)
+	}
+	defer listener.Close()
+
+	received := make(chan []utils.ProgressEvent)
+	go func() {
+		var events []utils.ProgressEvent
+		conn, err := listener.Accept()
+		if err != nil {
+			received <- events
+			return
+		}
+		defer conn.Close()
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var event utils.ProgressEvent
+			if err := json.Unmarshal(scanner.Bytes(), &event); err == nil {
+				events = append(events, event)
+			}
+		}
+		received <- events
+	}()
+
+	utils.PROGRESS_SOCKET = socketPath
+	defer func() { utils.PROGRESS_SOCKET = "" }()
+	utils.StartProgress(utils.IMPORT)
+	utils.EmitResourceStarted(utils.APPLICATIONS, "App1", utils.IMPORT)
+	utils.UpdateSuccessSummary(utils.APPLICATIONS, "App1", utils.UPDATE)
+	utils.EmitResourceStarted(utils.APPLICATIONS, "App2", utils.DELETE)
+	utils.UpdateFailureSummary(utils.APPLICATIONS, "App2")
+	utils.FinishProgress(utils.IMPORT)
+
+	var actual [][]string
+	for _, event := range <-received {
+		if event.Version != utils.PROGRESS_SCHEMA_VERSION || event.Timestamp == "" {
+			t.Errorf("Expected the version and timestamp to be set in event: %+v", event)
+		}
+		actual = append(actual, []string{event.Event, event.ResourceName, event.Action, event.Result})
+	}
+	expected := [][]string{
+		{utils.PROGRESS_RUN_STARTED, "", "", ""},
+		{utils.PROGRESS_RESOURCE_STARTED, "App1", utils.IMPORT, ""},
+		{utils.PROGRESS_RESOURCE_FINISHED, "App1", utils.UPDATE, utils.PROGRESS_RESULT_SUCCESS},
+		{utils.PROGRESS_RESOURCE_STARTED, "App2", utils.DELETE, ""},
+		{utils.PROGRESS_RESOURCE_FINISHED, "App2", utils.DELETE, utils.PROGRESS_RESULT_FAILED},
+		{utils.PROGRESS_RUN_FINISHED, "", "", ""},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected events: %v, got: %v", expected, actual)
+	}
+}
+
+func TestProgressEventsWithoutSocket(t *testing.T) {
+	utils.PROGRESS_SOCKET = filepath.Join(os.TempDir(), "iamctl-missing.sock")
+	defer func() { utils.PROGRESS_SOCKET = "" }()
+
+	// Events should be dropped without failing when the socket is not available.
+	utils.StartProgress(utils.EXPORT)
+	utils.EmitResourceStarted(utils.APPLICATIONS, "App1", utils.EXPORT)
+	utils.FinishProgress(utils.EXPORT)
+}
+
+func TestProgressDisplay(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	// The output of the tests is not a terminal, so the progress is logged in steps of the percentage.
+	utils.StartProgress(utils.EXPORT)
+	utils.StartResourceProgress(utils.APPLICATIONS, 20)
+	for i := 0; i < 20; i++ {
+		utils.EmitResourceFinished(utils.APPLICATIONS, "App", utils.EXPORT, utils.PROGRESS_RESULT_SUCCESS)
+	}
+	utils.StartResourceProgress(utils.ROLES, 4)
+	utils.EmitResourceFinished(utils.ROLES, "Role1", utils.DELETE, utils.PROGRESS_RESULT_SUCCESS)
+	utils.EmitResourceFinished(utils.ROLES, "Role2", "", utils.PROGRESS_RESULT_SKIPPED)
+	utils.FinishProgress(utils.EXPORT)
+
+	if count := strings.Count(logs.String(), "Progress: Applications "); count != 10 {
+		t.Errorf("Expected 10 progress lines of the applications but got %d:\n%s", count, logs.String())
+	}
+	for _, expected := range []string{"Applications 2/20 (10%)", "Applications 20/20 (100%)", "Roles 1/4 (25%)", "Roles 1/4\n"} {
+		if !strings.Contains(logs.String(), expected) {
+			t.Errorf("Expected %q in the progress lines but got:\n%s", expected, logs.String())
+		}
+	}
+
+	// The progress is not logged when the display is disabled.
+	logs.Reset()
+	utils.NO_PROGRESS = true
+	defer func() { utils.NO_PROGRESS = false }()
+	utils.StartProgress(utils.EXPORT)
+	utils.StartResourceProgress(utils.APPLICATIONS, 1)
+	utils.EmitResourceFinished(utils.APPLICATIONS, "App", utils.EXPORT, utils.PROGRESS_RESULT_SUCCESS)
+	utils.FinishProgress(utils.EXPORT)
+	if strings.Contains(logs.String(), "Progress:") {
+		t.Errorf("Expected no progress lines when the progress is disabled but got:\n%s", logs.String())
+	}
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"os"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+// Replace the standard input with a pipe with the given content, which is not a terminal.
+func setStdin(t *testing.T, content string) func() {
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the pipe: %s", err)
+	}
+	writer.WriteString(content)
+	writer.Close()
+	stdin := os.Stdin
+	os.Stdin = reader
+	return func() {
+		os.Stdin = stdin
+		reader.Close()
+	}
+}
+
+func TestForceSkipsDeletionConfirmation(t *testing.T) {
+
+	defer setStdin(t, "")()
+	if utils.ConfirmDeletion(utils.ROLES, []string{"viewer"}) {
+		t.Errorf("Expected the deletion not to be confirmed without a terminal")
+	}
+
+	utils.FORCE_IMPORT = true
+	defer func() { utils.FORCE_IMPORT = false }()
+	if !utils.ConfirmDeletion(utils.ROLES, []string{"viewer"}) {
+		t.Errorf("Expected the deletion to be confirmed with the --force flag")
+	}
+	if utils.ConfirmSystemResourceImport(utils.APPLICATIONS, []string{"Console"}) {
+		t.Errorf("Expected the system application import not to be confirmed with the --force flag alone")
+	}
+
+	utils.FORCE_IMPORT = false
+	utils.ASSUME_YES = true
+	defer func() { utils.ASSUME_YES = false }()
+	if !utils.ConfirmDeletion(utils.ROLES, []string{"viewer"}) {
+		t.Errorf("Expected the deletion to be confirmed with the --yes flag")
+	}
+}
+
+func TestPromptsShareStdinReader(t *testing.T) {
+
+	// Answers piped in advance are buffered by the first prompt and are read by the next prompts.
+	defer setStdin(t, "yes\nno\n")()
+	first, _ := utils.GetStdinReader().ReadString('\n')
+	second, _ := utils.GetStdinReader().ReadString('\n')
+	if !utils.IsConfirmed(first) || second != "no\n" {
+		t.Errorf("Expected the answers of both prompts but got: %q, %q", first, second)
+	}
+}
@@ -0,0 +1,181 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+const provisioningApp = `applicationName: provisioning-app
+provisioningConfig:
+  inboundProvisioning:
+    proxyMode: true
+    userStoreDomain: SECONDARY
+  outboundProvisioningIdps:
+  - idp: Google
+    connector: googleapps
+    jit: true
+`
+
+func TestProvisioningConfigImport(t *testing.T) {
+
+	tests := []struct {
+		name               string
+		provisioningConfig string
+		expectedPayload    string
+	}{
+		{
+			name: "provisioning configurations are set after the application is created",
+			expectedPayload: `{"provisioningConfigurations":{"inboundProvisioning":{"proxyMode":true,` +
+				`"provisioningUserstoreDomain":"SECONDARY"},"outboundProvisioningIdps":[{"idp":"Google",` +
+				`"connector":"googleapps","blocking":false,"rules":false,"jit":true}]}}`,
+		},
+		{
+			name:               "outbound provisioning is removed if no identity providers are given",
+			provisioningConfig: "provisioningConfig:\n  inboundProvisioning:\n    proxyMode: false\n",
+			expectedPayload:    `{"provisioningConfigurations":{"inboundProvisioning":{"proxyMode":false},"outboundProvisioningIdps":[]}}`,
+		},
+		{
+			name:               "identity providers without a connector are rejected",
+			provisioningConfig: "provisioningConfig:\n  outboundProvisioningIdps:\n  - idp: Google\n",
+		},
+		{
+			name:               "unknown fields are rejected",
+			provisioningConfig: "provisioningConfig:\n  scimEndpoint: /scim2\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			created := false
+			var importedContent, patchPayload []byte
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/applications"):
+					if created {
+						w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "provisioning-app"}]}`))
+					} else {
+						w.Write([]byte(`{"totalResults": 0, "applications": []}`))
+					}
+				case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+					file, _, _ := r.FormFile("file")
+					importedContent, _ = ioutil.ReadAll(file)
+					created = true
+					w.WriteHeader(http.StatusCreated)
+				case r.Method == "PATCH" && strings.HasSuffix(r.URL.Path, "/applications/app-1"):
+					patchPayload, _ = ioutil.ReadAll(r.Body)
+					w.WriteHeader(http.StatusOK)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			utils.TOOL_CONFIGS = utils.ToolConfigs{ApplicationConfigs: map[string]interface{}{
+				utils.IMPORT_STRATEGY_CONFIG: utils.SINGLE_PHASE_IMPORT,
+			}}
+
+			tempDir := newTempDir(t)
+			appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+			if err := os.MkdirAll(appDirPath, 0755); err != nil {
+				t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+			}
+			content := provisioningApp
+			if test.provisioningConfig != "" {
+				content = content[:strings.Index(content, "provisioningConfig:")] + test.provisioningConfig
+			}
+			if err := ioutil.WriteFile(filepath.Join(appDirPath, "provisioning-app.yml"), []byte(content), 0644); err != nil {
+				t.Fatalf("Unexpected error when writing the application file: %s", err)
+			}
+
+			applications.ImportAll(tempDir)
+			if test.expectedPayload == "" {
+				if importedContent != nil || patchPayload != nil {
+					t.Errorf("Expected the application not to be imported but got:\n%s", importedContent)
+				}
+				return
+			}
+			if strings.Contains(string(importedContent), "provisioningConfig") {
+				t.Errorf("Expected the provisioningConfig key to be removed before the import but got:\n%s", importedContent)
+			}
+			var payload, expectedPayload interface{}
+			json.Unmarshal(patchPayload, &payload)
+			json.Unmarshal([]byte(test.expectedPayload), &expectedPayload)
+			if !reflect.DeepEqual(payload, expectedPayload) {
+				t.Errorf("Expected the provisioning configurations:\n%s\nbut got:\n%s", test.expectedPayload, patchPayload)
+			}
+		})
+	}
+}
+
+func TestProvisioningConfigDependencies(t *testing.T) {
+
+	tempDir := newTempDir(t)
+	appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+	if err := os.MkdirAll(appDirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(appDirPath, "provisioning-app.yml"), []byte(provisioningApp), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the application file: %s", err)
+	}
+
+	graph, err := utils.BuildDependencyGraph(tempDir)
+	if err != nil {
+		t.Fatalf("Unexpected error when building the dependency graph: %s", err)
+	}
+	identityProviders := graph.Applications["provisioning-app"].IdentityProviders
+	if strings.Join(identityProviders, ",") != "Google" {
+		t.Errorf("Expected the outbound provisioning identity providers but got %v", identityProviders)
+	}
+}
+
+func TestApplicationUpdateListsAppsOnce(t *testing.T) {
+
+	listRequests := 0
+	var patchedPaths []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/applications"):
+			// The total count is retrieved without a limit before the application list.
+			if r.URL.Query().Get("limit") != "" {
+				listRequests++
+			}
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app-1", "name": "provisioning-app"}]}`))
+		case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/applications/app-1/exportFile"):
+			w.Write([]byte("applicationName: provisioning-app\ndescription: Deployed application\n"))
+		case r.Method == "PUT" && strings.HasSuffix(r.URL.Path, "/applications/import"):
+			w.WriteHeader(http.StatusOK)
+		case r.Method == "PATCH":
+			patchedPaths = append(patchedPaths, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+
+	tempDir := newTempDir(t)
+	appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+	if err := os.MkdirAll(appDirPath, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the applications directory: %s", err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(appDirPath, "provisioning-app.yml"), []byte(provisioningApp), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the application file: %s", err)
+	}
+
+	applications.ImportAll(tempDir)
+	if len(patchedPaths) != 1 || !strings.HasSuffix(patchedPaths[0], "/applications/app-1") {
+		t.Errorf("Expected the provisioning configurations to be set on app-1 but got the PATCH requests: %v", patchedPaths)
+	}
+	if listRequests != 1 {
+		t.Errorf("Expected the application list to be retrieved once for the import but got %d list requests", listRequests)
+	}
+}
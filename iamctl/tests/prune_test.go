@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestPruneExportedFiles(t *testing.T) {
+
+	tempDir := newTempDir(t)
+
+	files := map[string]string{
+		"HR Portal.yml":             "applicationName: HR Portal\n",
+		"renamed.yml":               "applicationName: Payroll\n",
+		"Deleted app.yml":           "applicationName: Deleted app\n",
+		"Legacy app.yml":            "applicationName: Legacy app\n",
+		"Deleted app.authscript.js": "var onLoginRequest = function(context) {};\n",
+		"Keyword app.yml":           "applicationName: '{{PORTAL_NAME}}'\n",
+		"notes.txt":                 "applicationName: Deleted app\n",
+	}
+	for name, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the file: %s", err)
+		}
+	}
+
+	restoreConfigs(t)
+	defaultPrune := utils.PRUNE_EXPORT
+	defer func() { utils.PRUNE_EXPORT = defaultPrune }()
+	utils.KEYWORD_CONFIGS = utils.KeywordConfigs{KeywordMappings: map[string]interface{}{"PORTAL_NAME": "Partner Portal"}}
+	deployedNames := []string{"HR Portal", "Payroll", "Partner Portal"}
+	resourceConfigs := map[string]interface{}{utils.EXCLUDE_CONFIG: []interface{}{"Legacy app"}}
+
+	staleFilePaths, err := utils.FindStaleExportedFiles(tempDir, "applicationName", deployedNames, resourceConfigs)
+	expectedFilePaths := []string{filepath.Join(tempDir, "Deleted app.yml")}
+	if err != nil || !reflect.DeepEqual(staleFilePaths, expectedFilePaths) {
+		t.Fatalf("Expected the stale files %v but got %v %v", expectedFilePaths, staleFilePaths, err)
+	}
+
+	// Without the --prune flag, the stale files are only listed in a warning.
+	utils.ResetSummary()
+	utils.PRUNE_EXPORT = false
+	utils.HandleStaleExportedFiles(utils.APPLICATIONS, tempDir, "applicationName", deployedNames, resourceConfigs)
+	if _, err := os.Stat(expectedFilePaths[0]); err != nil {
+		t.Errorf("Expected the stale file to be kept without the --prune flag")
+	}
+
+	utils.PRUNE_EXPORT = true
+	utils.HandleStaleExportedFiles(utils.APPLICATIONS, tempDir, "applicationName", deployedNames, resourceConfigs)
+	if _, err := os.Stat(expectedFilePaths[0]); !os.IsNotExist(err) {
+		t.Errorf("Expected the stale file to be removed with the --prune flag")
+	}
+	remainingFiles, _ := ioutil.ReadDir(tempDir)
+	if len(remainingFiles) != len(files)-1 {
+		t.Errorf("Expected only the stale file to be removed but %d files remain", len(remainingFiles))
+	}
+	prunedFiles := utils.ResourceSummaries[utils.APPLICATIONS].PrunedFiles
+	if !reflect.DeepEqual(prunedFiles, []string{"Deleted app.yml"}) {
+		t.Errorf("Expected the pruned file in the summary but got %v", prunedFiles)
+	}
+}
@@ -0,0 +1,94 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestRequestTimeout(t *testing.T) {
+
+	release := make(chan struct{})
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-time.After(5 * time.Second):
+		}
+	})
+	defer close(release)
+
+	client, err := utils.NewHttpClient(utils.ServerConfigs{ServerUrl: server.URL, RequestTimeout: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the HTTP client: %s", err)
+	}
+	start := time.Now()
+	if _, err := client.Get(server.URL); err == nil {
+		t.Fatalf("Expected the request to time out")
+	}
+	if elapsed := time.Since(start); elapsed > 3*time.Second {
+		t.Errorf("Expected the request to time out after the configured timeout but took %s", elapsed)
+	}
+
+	client, err = utils.NewHttpClient(utils.ServerConfigs{ServerUrl: server.URL})
+	if err != nil {
+		t.Fatalf("Unexpected error when creating the HTTP client: %s", err)
+	}
+	if client.Timeout != utils.DEFAULT_REQUEST_TIMEOUT*time.Second {
+		t.Errorf("Expected the default timeout of %d seconds but got %s", utils.DEFAULT_REQUEST_TIMEOUT, client.Timeout)
+	}
+}
+
+func TestFetchPoolCancellation(t *testing.T) {
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	utils.SetRequestContext(ctx)
+	defer utils.SetRequestContext(nil)
+
+	var fetched int32
+	pool := utils.StartFetchPool(3, func(ctx context.Context, index int) (interface{}, error) {
+		atomic.AddInt32(&fetched, 1)
+		return index, nil
+	})
+	defer pool.Stop()
+	for i := 0; i < 3; i++ {
+		if _, err := pool.Get(i); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the resource at %d to fail with the cancellation but got: %v", i, err)
+		}
+	}
+	if fetched != 0 {
+		t.Errorf("Expected no resources to be fetched after the cancellation but %d were fetched", fetched)
+	}
+}
+
+func TestExportedFileWrite(t *testing.T) {
+
+	tempDir := newTempDir(t)
+
+	filePath := filepath.Join(tempDir, "app.yml")
+	if err := ioutil.WriteFile(filePath, []byte("applicationName: old\n"), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the file: %s", err)
+	}
+	if err := utils.WriteExportedFile(filePath, []byte("applicationName: new\n")); err != nil {
+		t.Fatalf("Unexpected error when writing the exported file: %s", err)
+	}
+	content, err := ioutil.ReadFile(filePath)
+	if err != nil || string(content) != "applicationName: new\n" {
+		t.Errorf("Expected the file to be replaced but got: %s %v", content, err)
+	}
+	if info, err := os.Stat(filePath); err != nil || info.Mode().Perm() != 0644 {
+		t.Errorf("Expected the file permissions 0644 but got: %v %v", info.Mode().Perm(), err)
+	}
+	files, _ := ioutil.ReadDir(tempDir)
+	if len(files) != 1 {
+		t.Errorf("Expected no temporary files to be left but found %d files", len(files))
+	}
+}
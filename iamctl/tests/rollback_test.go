@@ -0,0 +1,87 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestValidateBackupServer(t *testing.T) {
+
+	restoreConfigs(t)
+	utils.SERVER_CONFIGS = utils.ServerConfigs{ServerUrl: "https://prod.example.com:9443", TenantDomain: "carbon.super"}
+
+	testCases := []struct {
+		serverUrl string
+		isValid   bool
+	}{
+		{"https://prod.example.com:9443", true},
+		{"https://prod.example.com:9443/", true},
+		{"https://staging.example.com:9443", false},
+		{"https://prod.example.com:9444", false},
+		{"", false},
+	}
+	for _, tc := range testCases {
+		err := utils.ValidateBackupServer(utils.BackupMetadata{ServerUrl: tc.serverUrl})
+		if (err == nil) != tc.isValid {
+			t.Errorf("Expected the backup of the server %q to be valid: %t but got the error: %v", tc.serverUrl, tc.isValid, err)
+		}
+	}
+}
+
+func TestUpdateOnlyImport(t *testing.T) {
+
+	var requests []string
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super/api/server/v1/identity-providers/")
+		requests = append(requests, r.Method+" "+path)
+		switch {
+		case r.Method == "GET" && path == "":
+			w.Write([]byte(`{"totalResults": 2, "identityProviders": [{"id": "idp-1", "name": "Google"},
+				{"id": "idp-2", "name": "Okta"}]}`))
+		case r.Method == "GET" && path == "idp-1/export":
+			w.Header().Set("Content-Disposition", `attachment; filename="Google.yml"`)
+			w.Write([]byte(googleIdp))
+		case r.Method == "PUT" && path == "idp-1/import":
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	utils.TOOL_CONFIGS = utils.ToolConfigs{AllowDelete: true}
+	utils.UPDATE_ONLY = true
+	defer func() { utils.UPDATE_ONLY = false }()
+
+	tempDir := newTempDir(t)
+	idpsDir := filepath.Join(tempDir, utils.IDENTITY_PROVIDERS)
+	if err := os.MkdirAll(idpsDir, 0755); err != nil {
+		t.Fatalf("Unexpected error when creating the identity providers directory: %s", err)
+	}
+	files := map[string]string{
+		"Google.yml":  strings.Replace(googleIdp, "google-client", "restored-client", 1),
+		"Removed.yml": "identityProviderName: Removed\n",
+	}
+	for fileName, content := range files {
+		if err := ioutil.WriteFile(filepath.Join(idpsDir, fileName), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the backup file: %s", err)
+		}
+	}
+
+	// The existing identity provider is updated, and no identity provider is created or deleted.
+	identityproviders.ImportAll(tempDir)
+	for _, request := range requests {
+		if strings.HasPrefix(request, "POST") || strings.HasPrefix(request, "DELETE") {
+			t.Errorf("Expected only the existing identity providers to be updated but got the request: %s", request)
+		}
+	}
+	if !utils.Contains(requests, "PUT idp-1/import") {
+		t.Errorf("Expected the existing identity provider to be updated but got the requests: %v", requests)
+	}
+}
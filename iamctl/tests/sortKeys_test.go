@@ -0,0 +1,133 @@
+package tests
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestSortYamlKeys(t *testing.T) {
+	content := "inboundAuthenticationConfig:\n" +
+		"  inboundAuthenticationRequestConfigs:\n" +
+		"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+		"      oauthConsumerKey: key\n      applicationName: App1\n      callbackUrl: null\n" +
+		"    inboundAuthKey: key\n" +
+		"description: null\nZeta: 1\napplicationName: App1\n" +
+		"claims:\n- uri: http://wso2.org/claims/role\n  name: role\n- uri: http://wso2.org/claims/email\n  name: email\n"
+
+	testCases := []struct {
+		order    string
+		expected string
+	}{
+		{
+			order: utils.KEY_ORDER_ALPHABETICAL,
+			expected: "applicationName: App1\n" +
+				"claims:\n- name: role\n  uri: http://wso2.org/claims/role\n" +
+				"- name: email\n  uri: http://wso2.org/claims/email\n" +
+				"description: null\ninboundAuthenticationConfig:\n" +
+				"  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundAuthKey: key\n" +
+				"    inboundConfigurationProtocol:\n      !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      applicationName: App1\n      callbackUrl: null\n      oauthConsumerKey: key\n" +
+				"Zeta: 1\n",
+		},
+		{
+			order: utils.KEY_ORDER_CANONICAL,
+			expected: "applicationName: App1\ndescription: null\n" +
+				"claims:\n- name: role\n  uri: http://wso2.org/claims/role\n" +
+				"- name: email\n  uri: http://wso2.org/claims/email\n" +
+				"inboundAuthenticationConfig:\n" +
+				"  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundAuthKey: key\n" +
+				"    inboundConfigurationProtocol:\n      !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      applicationName: App1\n      callbackUrl: null\n      oauthConsumerKey: key\n" +
+				"Zeta: 1\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		sortedContent, err := utils.SortYamlKeys([]byte(content), tc.order)
+		if err != nil {
+			t.Fatalf("Unexpected error when sorting the keys in the %s order: %s", tc.order, err)
+		}
+		if string(sortedContent) != tc.expected {
+			t.Errorf("Expected the content sorted in the %s order:\n%s\nbut got:\n%s", tc.order, tc.expected,
+				sortedContent)
+		}
+		if !utils.IsContentEqual(sortedContent, []byte(content), nil) {
+			t.Errorf("Expected the content sorted in the %s order to be equal to the original content", tc.order)
+		}
+	}
+
+	unchangedContent, err := utils.SortYamlKeys([]byte(content), utils.KEY_ORDER_NONE)
+	if err != nil || string(unchangedContent) != content {
+		t.Errorf("Expected the content to be unchanged when the keys are not sorted")
+	}
+}
+
+func TestSortYamlKeysIsStable(t *testing.T) {
+	content := "name: role1\npermissions:\n- display: Login\n  value: /permission/login\naudience:\n  type: app\n  display: App1\n"
+	reorderedContent := "audience:\n  display: App1\n  type: app\npermissions:\n- value: /permission/login\n  display: Login\nname: role1\n"
+
+	for _, order := range []string{utils.KEY_ORDER_ALPHABETICAL, utils.KEY_ORDER_CANONICAL} {
+		sortedContent, err := utils.SortYamlKeys([]byte(content), order)
+		if err != nil {
+			t.Fatalf("Unexpected error when sorting the keys: %s", err)
+		}
+		sortedReorderedContent, err := utils.SortYamlKeys([]byte(reorderedContent), order)
+		if err != nil {
+			t.Fatalf("Unexpected error when sorting the keys: %s", err)
+		}
+		if string(sortedContent) != string(sortedReorderedContent) {
+			t.Errorf("Expected the same content in the %s order regardless of the input order, but got:\n%s\nand:\n%s",
+				order, sortedContent, sortedReorderedContent)
+		}
+	}
+}
+
+func TestWriteExportedFileWithSortedKeys(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	utils.KEY_ORDER = utils.KEY_ORDER_CANONICAL
+	utils.OMIT_NULL = true
+	defer func() {
+		utils.KEY_ORDER = utils.KEY_ORDER_NONE
+		utils.OMIT_NULL = false
+	}()
+
+	fileName := filepath.Join(tempDir, "Google.yml")
+	content := "alias: null\nenable: true\nidentityProviderName: Google\ndisplayName: Google IdP\n"
+	if err := utils.WriteExportedFile(fileName, []byte(content)); err != nil {
+		t.Fatalf("Unexpected error when writing the exported file: %s", err)
+	}
+	writtenContent, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	expected := "identityProviderName: Google\ndisplayName: Google IdP\nenable: true\n"
+	if string(writtenContent) != expected {
+		t.Errorf("Expected the exported content:\n%s\nbut got:\n%s", expected, writtenContent)
+	}
+
+	scriptFileName := filepath.Join(tempDir, "App1.js")
+	script := "var b = 1;\nvar a = 2;\n"
+	if err := utils.WriteExportedFile(scriptFileName, []byte(script)); err != nil {
+		t.Fatalf("Unexpected error when writing the exported script: %s", err)
+	}
+	if writtenScript, _ := ioutil.ReadFile(scriptFileName); string(writtenScript) != script {
+		t.Errorf("Expected the files other than YAML files to be written unchanged")
+	}
+}
+
+func TestValidateKeyOrder(t *testing.T) {
+	for _, order := range utils.KEY_ORDERS {
+		if err := utils.ValidateKeyOrder(order); err != nil {
+			t.Errorf("Expected the key order %s to be valid, but got: %s", order, err)
+		}
+	}
+	if err := utils.ValidateKeyOrder("reverse"); err == nil {
+		t.Errorf("Expected an error for an unknown key order")
+	}
+}
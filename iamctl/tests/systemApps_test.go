@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestIsSystemApplication(t *testing.T) {
+
+	restoreConfigs(t)
+	utils.TOOL_CONFIGS = utils.ToolConfigs{ApplicationConfigs: map[string]interface{}{}}
+	if !utils.IsSystemApplication(utils.CONSOLE, false) || !utils.IsSystemApplication(utils.MY_ACCOUNT, false) {
+		t.Errorf("Expected the Console and My Account applications to be system applications by default")
+	}
+	if utils.IsSystemApplication("hr-portal", false) || !utils.IsSystemApplication("hr-portal", true) {
+		t.Errorf("Expected an application to be a system application only when marked by the server")
+	}
+
+	utils.TOOL_CONFIGS.ApplicationConfigs[utils.SYSTEM_APPS_CONFIG] = []interface{}{"Internal *"}
+	if utils.IsSystemApplication(utils.CONSOLE, false) || !utils.IsSystemApplication("Internal Portal", false) {
+		t.Errorf("Expected the system applications to be overridden by the SYSTEM_APPS config")
+	}
+}
+
+func TestSystemApplicationImport(t *testing.T) {
+
+	tests := []struct {
+		name              string
+		includeSystemApps bool
+		expectedImports   []string
+	}{
+		{
+			name:            "system applications are skipped by default",
+			expectedImports: []string{"hr-portal"},
+		},
+		{
+			name:              "system applications are imported with the include flag and the yes flag",
+			includeSystemApps: true,
+			expectedImports:   []string{"Console", "Internal Portal", "hr-portal"},
+		},
+	}
+
+	defer func() { utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES = false, false }()
+	appNameRegex := regexp.MustCompile(`applicationName: (.*)`)
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var imports, deletes []string
+			newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+				path := r.URL.Path
+				switch {
+				case r.Method == "GET" && strings.HasSuffix(path, "/applications/meta/inbound-protocols"):
+					w.WriteHeader(http.StatusNotFound)
+				case r.Method == "GET" && strings.HasSuffix(strings.TrimSuffix(path, "/"), "/applications"):
+					w.Write([]byte(`{"totalResults": 5, "applications": [{"id": "app-1", "name": "Console"},
+						{"id": "app-2", "name": "My Account"}, {"id": "app-3", "name": "Internal Portal", "isSystemApp": true},
+						{"id": "app-4", "name": "hr-portal"}, {"id": "app-5", "name": "old-app"}]}`))
+				case strings.HasSuffix(path, "/applications/import"):
+					body, _ := ioutil.ReadAll(r.Body)
+					if match := appNameRegex.FindSubmatch(body); match != nil {
+						imports = append(imports, string(match[1]))
+					}
+					w.WriteHeader(http.StatusOK)
+				case r.Method == "DELETE":
+					deletes = append(deletes, path[strings.LastIndex(path, "/")+1:])
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					w.WriteHeader(http.StatusNotFound)
+				}
+			})
+
+			utils.TOOL_CONFIGS = utils.ToolConfigs{AllowDelete: true, ApplicationConfigs: map[string]interface{}{}}
+			utils.INCLUDE_SYSTEM_APPS, utils.ASSUME_YES = test.includeSystemApps, true
+
+			tempDir := newTempDir(t)
+			appDirPath := filepath.Join(tempDir, utils.APPLICATIONS)
+			os.MkdirAll(appDirPath, 0755)
+			for _, appName := range []string{"Console", "Internal Portal", "hr-portal"} {
+				content := "applicationName: " + appName + "\n"
+				if err := ioutil.WriteFile(filepath.Join(appDirPath, appName+".yml"), []byte(content), 0644); err != nil {
+					t.Fatalf("Unexpected error when writing the application file: %s", err)
+				}
+			}
+
+			applications.ImportAll(tempDir)
+			sort.Strings(imports)
+			if !reflect.DeepEqual(imports, test.expectedImports) {
+				t.Errorf("Expected the imported applications %v but got %v", test.expectedImports, imports)
+			}
+			// System applications that are not found locally are never deleted.
+			if !reflect.DeepEqual(deletes, []string{"app-5"}) {
+				t.Errorf("Expected only the non-system application to be deleted but got %v", deletes)
+			}
+		})
+	}
+}
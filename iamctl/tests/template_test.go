@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/applications"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/templates"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"gopkg.in/yaml.v2"
+)
+
+func TestGetTemplate(t *testing.T) {
+
+	for _, templateType := range templates.APP_TEMPLATE_TYPES {
+		content, err := templates.GetTemplate(utils.APPLICATIONS, strings.ToUpper(templateType))
+		if err != nil {
+			t.Fatalf("Unexpected error for the %s application template: %s", templateType, err)
+		}
+		if !strings.Contains(string(content), "applicationName: ") {
+			t.Errorf("Application template %s has no application name", templateType)
+		}
+	}
+	for _, templateType := range templates.IDP_TEMPLATE_TYPES {
+		content, err := templates.GetTemplate(utils.IDENTITY_PROVIDERS, templateType)
+		if err != nil {
+			t.Fatalf("Unexpected error for the %s identity provider template: %s", templateType, err)
+		}
+		if !strings.Contains(string(content), "identityProviderName: ") {
+			t.Errorf("Identity provider template %s has no identity provider name", templateType)
+		}
+	}
+
+	if _, err := templates.GetTemplate(utils.APPLICATIONS, "ws-federation"); err == nil ||
+		!strings.Contains(err.Error(), "oauth2, oidc, saml") {
+		t.Errorf("Expected an error listing the supported types, got: %v", err)
+	}
+	if _, err := templates.GetTemplate(utils.CLAIMS, "oidc"); err == nil {
+		t.Error("Expected an error for a resource type without templates")
+	}
+}
+
+func TestAppTemplatesMatchFileFormat(t *testing.T) {
+
+	for _, templateType := range templates.APP_TEMPLATE_TYPES {
+		content, _ := templates.GetTemplate(utils.APPLICATIONS, templateType)
+		if err := applications.ValidateClientAuthConfig(string(content)); err != nil {
+			t.Errorf("Application template %s has an invalid client authentication config: %s", templateType, err)
+		}
+
+		// The sections added by the tool should match the struct types exactly.
+		var appYaml map[string]interface{}
+		if err := yaml.Unmarshal(utils.ReplaceTypeTags(content), &appYaml); err != nil {
+			t.Fatalf("Error when parsing the application template %s: %s", templateType, err)
+		}
+		sections := map[string]interface{}{
+			applications.TOKEN_CONFIG:          &applications.TokenConfig{},
+			applications.PROVISIONING_CONFIG:   &applications.ProvisioningConfig{},
+			applications.MFA_CONFIG:            &applications.MfaConfig{},
+			applications.ACCESS_CONTROL_CONFIG: &applications.AccessControlConfig{},
+		}
+		for key, config := range sections {
+			section, ok := appYaml[key]
+			if !ok {
+				continue
+			}
+			sectionContent, _ := yaml.Marshal(section)
+			if err := yaml.UnmarshalStrict(sectionContent, config); err != nil {
+				t.Errorf("Section %s of the application template %s does not match the struct type: %s",
+					key, templateType, err)
+			}
+		}
+	}
+}
+
+func TestTemplatesPassLint(t *testing.T) {
+
+	inputDir := newTempDir(t)
+
+	writeTemplates := func(resourceType string, templateTypes []string) {
+		resourceDir := filepath.Join(inputDir, resourceType)
+		if err := os.MkdirAll(resourceDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, templateType := range templateTypes {
+			content, _ := templates.GetTemplate(resourceType, templateType)
+			if err := ioutil.WriteFile(filepath.Join(resourceDir, templateType+".yml"), content, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	writeTemplates(utils.APPLICATIONS, templates.APP_TEMPLATE_TYPES)
+	writeTemplates(utils.IDENTITY_PROVIDERS, templates.IDP_TEMPLATE_TYPES)
+
+	// The secrets of the templates are keywords to be defined in the keyword mappings of the environment.
+	violations, err := utils.LintLocalResources(inputDir, []string{utils.LINT_UNDEFINED_KEYWORD})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, violation := range violations {
+		t.Errorf("Unexpected lint violation in %s: %s (%s)", violation.FilePath, violation.Message, violation.Rule)
+	}
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+)
+
+func TestRunForEachTenant(t *testing.T) {
+
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/t/carbon.super/oauth2/token":
+			w.Write([]byte(`{"access_token": "root-token"}`))
+		case "/t/tenant-a.com/oauth2/token":
+			w.Write([]byte(`{"access_token": "tenant-a-token"}`))
+		case "/t/tenant-c.com/oauth2/token":
+			w.Write([]byte(`{"access_token": "tenant-c-token"}`))
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	})
+
+	tempDir := newTempDir(t)
+	configFiles := map[string]string{
+		utils.SERVER_CONFIG_FILE: `{"SERVER_URL": "` + server.URL + `", "CLIENT_ID": "client", "CLIENT_SECRET": "secret",
+			"TENANTS": ["tenant-a.com", "tenant-b.com", "tenant-c.com"]}`,
+		utils.TOOL_CONFIG_FILE: `{}`,
+		utils.KEYWORD_CONFIG_FILE: `{"KEYWORD_MAPPINGS": {"CALLBACK_HOST": "localhost", "THEME": "default"},
+			"TENANTS": {"tenant-a.com": {"KEYWORD_MAPPINGS": {"CALLBACK_HOST": "a.example.com"}}}}`,
+	}
+	for fileName, content := range configFiles {
+		if err := ioutil.WriteFile(filepath.Join(tempDir, fileName), []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the config file: %s", err)
+		}
+	}
+
+	utils.LoadConfigs(tempDir)
+
+	type tenantRun struct {
+		dir      string
+		token    string
+		keywords map[string]interface{}
+	}
+	var runs []tenantRun
+	results := utils.RunForEachTenant("export", func(tenantDir string) error {
+		runs = append(runs, tenantRun{
+			dir:      tenantDir,
+			token:    utils.SERVER_CONFIGS.Token,
+			keywords: utils.ResolveAdvancedKeywordMapping("hr-portal", utils.KEYWORD_CONFIGS.ApplicationConfigs),
+		})
+		utils.UpdateSuccessSummary(utils.APPLICATIONS, "hr-portal", utils.EXPORT)
+		return nil
+	})
+
+	expectedRuns := []tenantRun{
+		{dir: filepath.Join("export", "tenant-a.com"), token: "tenant-a-token",
+			keywords: map[string]interface{}{"CALLBACK_HOST": "a.example.com", "THEME": "default"}},
+		{dir: filepath.Join("export", "tenant-c.com"), token: "tenant-c-token",
+			keywords: map[string]interface{}{"CALLBACK_HOST": "localhost", "THEME": "default"}},
+	}
+	if !reflect.DeepEqual(runs, expectedRuns) {
+		t.Errorf("Expected the tenant runs %+v but got %+v", expectedRuns, runs)
+	}
+	if len(results) != 3 || results[1].TenantDomain != "tenant-b.com" || results[1].Error == "" {
+		t.Fatalf("Expected the authentication failure of tenant-b.com in the results but got %+v", results)
+	}
+	if results[0].Error != "" || results[0].Summary.SuccessfulOperations != 1 || results[2].Summary.SuccessfulOperations != 1 {
+		t.Errorf("Expected the other tenants to be processed but got %+v", results)
+	}
+	if utils.SummaryData.SuccessfulOperations != 2 {
+		t.Errorf("Expected the total of the tenant summaries but got %+v", utils.SummaryData)
+	}
+}
@@ -1,9 +1,24 @@
 package tests
 
 import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	identityproviders "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/identityProviders"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/roles"
+	userstores "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/userStores"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/users"
 	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	xacmlpolicies "github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/xacmlPolicies"
 )
 
 func TestIsResourceExcluded(t *testing.T) {
@@ -88,3 +103,1321 @@ func TestIsResourceExcluded(t *testing.T) {
 		})
 	}
 }
+
+func TestIsConfirmed(t *testing.T) {
+	testCases := []struct {
+		name           string
+		answer         string
+		expectedResult bool
+	}{
+		{name: "Full answer", answer: "yes\n", expectedResult: true},
+		{name: "Short answer", answer: "y\n", expectedResult: true},
+		{name: "Upper case answer", answer: " YES \n", expectedResult: true},
+		{name: "Negative answer", answer: "no\n", expectedResult: false},
+		{name: "Empty answer", answer: "\n", expectedResult: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := utils.IsConfirmed(tc.answer)
+			if result != tc.expectedResult {
+				t.Errorf("Expected result to be %v but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestResolveEnvironmentConfigs(t *testing.T) {
+	configFile := []byte(`{
+		"default": {
+			"SERVER_URL": "https://localhost:9443",
+			"TENANT_DOMAIN": "carbon.super",
+			"KEYWORD_MAPPINGS": {"CALLBACK_DOMAIN": "localhost", "ENV": "default"}
+		},
+		"staging": {
+			"SERVER_URL": "https://staging.example.com",
+			"KEYWORD_MAPPINGS": {"ENV": "staging"}
+		}
+	}`)
+
+	testCases := []struct {
+		name           string
+		environment    string
+		expectedResult map[string]interface{}
+		expectError    bool
+	}{
+		{
+			name:        "Selected environment overrides default section",
+			environment: "staging",
+			expectedResult: map[string]interface{}{
+				"SERVER_URL":    "https://staging.example.com",
+				"TENANT_DOMAIN": "carbon.super",
+				"KEYWORD_MAPPINGS": map[string]interface{}{
+					"CALLBACK_DOMAIN": "localhost",
+					"ENV":             "staging",
+				},
+			},
+		},
+		{
+			name:        "Undefined environment",
+			environment: "prod",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := utils.ResolveEnvironmentConfigs(configFile, tc.environment)
+			if tc.expectError {
+				if err == nil {
+					t.Errorf("Expected an error for environment %s", tc.environment)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			var resolvedConfigs map[string]interface{}
+			json.Unmarshal(result, &resolvedConfigs)
+			if !reflect.DeepEqual(resolvedConfigs, tc.expectedResult) {
+				t.Errorf("Expected result to be %v but got %v", tc.expectedResult, resolvedConfigs)
+			}
+		})
+	}
+}
+
+func TestIsContentEqual(t *testing.T) {
+	ignoredFields := utils.GetIgnoredFields(utils.APPLICATIONS)
+
+	testCases := []struct {
+		name            string
+		localContent    string
+		deployedContent string
+		expectedResult  bool
+	}{
+		{
+			name:            "Different key order",
+			localContent:    "applicationName: App1\ndescription: Sample app\n",
+			deployedContent: "description: Sample app\napplicationName: App1\n",
+			expectedResult:  true,
+		},
+		{
+			name:            "Server generated fields",
+			localContent:    "applicationName: App1\napplicationID: 2\n",
+			deployedContent: "applicationName: App1\napplicationID: 5\napplicationResourceId: 8e0e3a1c\n",
+			expectedResult:  true,
+		},
+		{
+			name: "Nested secrets",
+			localContent: "applicationName: App1\ninboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n      oauthConsumerSecret: null\n",
+			deployedContent: "applicationName: App1\ninboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n      oauthConsumerSecret: secret\n",
+			expectedResult: true,
+		},
+		{
+			name:            "Changed value",
+			localContent:    "applicationName: App1\ndescription: Sample app\n",
+			deployedContent: "applicationName: App1\ndescription: Changed app\n",
+			expectedResult:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := utils.IsContentEqual([]byte(tc.localContent), []byte(tc.deployedContent), ignoredFields)
+			if result != tc.expectedResult {
+				t.Errorf("Expected result to be %v but got %v", tc.expectedResult, result)
+			}
+		})
+	}
+}
+
+func TestServerConfigEncryptionRoundTrip(t *testing.T) {
+	configFile := []byte(`{
+		"SERVER_URL": "https://localhost:9443",
+		"CLIENT_ID": "client",
+		"CLIENT_SECRET": "secret",
+		"staging": {"CLIENT_SECRET": "staging-secret"}
+	}`)
+
+	encryptedConfigFile, err := utils.EncryptServerConfigs(configFile, "passphrase")
+	if err != nil {
+		t.Fatalf("Unexpected error when encrypting: %s", err)
+	}
+	var encryptedConfigs map[string]interface{}
+	json.Unmarshal(encryptedConfigFile, &encryptedConfigs)
+	if !utils.IsEncryptedValue(encryptedConfigs["CLIENT_SECRET"].(string)) {
+		t.Errorf("Expected CLIENT_SECRET to be encrypted but got %v", encryptedConfigs["CLIENT_SECRET"])
+	}
+	if encryptedConfigs["CLIENT_ID"] != "client" {
+		t.Errorf("Expected CLIENT_ID to be unchanged but got %v", encryptedConfigs["CLIENT_ID"])
+	}
+
+	if _, err := utils.DecryptServerConfigs(encryptedConfigFile, "wrong passphrase"); err == nil {
+		t.Errorf("Expected an error when decrypting with a wrong passphrase")
+	}
+
+	decryptedConfigFile, err := utils.DecryptServerConfigs(encryptedConfigFile, "passphrase")
+	if err != nil {
+		t.Fatalf("Unexpected error when decrypting: %s", err)
+	}
+	var originalConfigs, decryptedConfigs map[string]interface{}
+	json.Unmarshal(configFile, &originalConfigs)
+	json.Unmarshal(decryptedConfigFile, &decryptedConfigs)
+	if !reflect.DeepEqual(originalConfigs, decryptedConfigs) {
+		t.Errorf("Expected decrypted configs to be %v but got %v", originalConfigs, decryptedConfigs)
+	}
+}
+
+func TestKeywordConfigDecryption(t *testing.T) {
+	keyEnv := "IAMCTL_TEST_KW_KEY"
+	os.Setenv(keyEnv, "MDEyMzQ1Njc4OTAxMjM0NTY3ODkwMTIzNDU2Nzg5MDE=")
+	defer os.Unsetenv(keyEnv)
+	key, err := utils.GetKeywordKey(keyEnv)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the key: %s", err)
+	}
+	encryptedValue, err := utils.EncryptKeywordValue("kw-secret-value", key)
+	if err != nil {
+		t.Fatalf("Unexpected error when encrypting: %s", err)
+	}
+
+	configFile := []byte(`{"KEYWORD_MAPPINGS": {"HOST": "localhost"}, "APPLICATIONS": {"App1": {"API_KEY": "` + encryptedValue + `"}}}`)
+	decryptedConfigFile, err := utils.DecryptKeywordConfigs(configFile, keyEnv)
+	if err != nil {
+		t.Fatalf("Unexpected error when decrypting: %s", err)
+	}
+	var keywordConfigs utils.KeywordConfigs
+	json.Unmarshal(decryptedConfigFile, &keywordConfigs)
+	appKeywords := keywordConfigs.ApplicationConfigs["App1"].(map[string]interface{})
+	if appKeywords["API_KEY"] != "kw-secret-value" || keywordConfigs.KeywordMappings["HOST"] != "localhost" {
+		t.Errorf("Unexpected decrypted keyword configs: %s", decryptedConfigFile)
+	}
+	if redacted := utils.RedactBody([]byte(`{"url": "https://localhost?key=kw-secret-value"}`)); strings.Contains(redacted, "kw-secret-value") {
+		t.Errorf("Expected the decrypted value to be redacted but got %s", redacted)
+	}
+
+	tamperedValue := encryptedValue[:len(encryptedValue)-4] + "AAAA"
+	configFile = []byte(`{"KEYWORD_MAPPINGS": {"DB_PASSWORD": "` + tamperedValue + `"}}`)
+	_, err = utils.DecryptKeywordConfigs(configFile, keyEnv)
+	if err == nil {
+		t.Fatalf("Expected an error when decrypting a tampered value")
+	}
+	if !strings.Contains(err.Error(), "KEYWORD_MAPPINGS.DB_PASSWORD") || strings.Contains(err.Error(), tamperedValue) {
+		t.Errorf("Expected the error to name the keyword without the encrypted value but got %s", err)
+	}
+}
+
+func TestServerConfigEnvVarOverrides(t *testing.T) {
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	tempDir := newTempDir(t)
+	serverConfigFile := `{"SERVER_URL": "https://unreachable.invalid", "CLIENT_ID": "file-client", "CLIENT_SECRET": "file-secret"}`
+	if err := ioutil.WriteFile(filepath.Join(tempDir, utils.SERVER_CONFIG_FILE), []byte(serverConfigFile), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the server config file: %s", err)
+	}
+
+	os.Setenv(utils.IAMCTL_SERVER_URL, server.URL+"/")
+	os.Setenv(utils.IAMCTL_CLIENT_SECRET, "env-secret")
+	defer os.Unsetenv(utils.IAMCTL_SERVER_URL)
+	defer os.Unsetenv(utils.IAMCTL_CLIENT_SECRET)
+
+	if err := utils.WaitForServer(tempDir, time.Second); err != nil {
+		t.Fatalf("Expected the server URL to be overridden but got: %s", err)
+	}
+	expected := utils.ServerConfigs{
+		ServerUrl:    server.URL,
+		ClientId:     "file-client",
+		ClientSecret: "env-secret",
+		TenantDomain: utils.DEFAULT_TENANT_DOMAIN,
+	}
+	if !reflect.DeepEqual(utils.SERVER_CONFIGS, expected) {
+		t.Errorf("Expected server configs to be %+v but got %+v", expected, utils.SERVER_CONFIGS)
+	}
+}
+
+func TestGzipExportRoundTrip(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	exportedFileName := filepath.Join(tempDir, "App1.yml")
+	content := []byte("applicationName: App1\n")
+
+	utils.GZIP_EXPORT = true
+	defer func() { utils.GZIP_EXPORT = false }()
+	if err := utils.WriteExportedFile(exportedFileName, content); err != nil {
+		t.Fatalf("Unexpected error when writing the exported file: %s", err)
+	}
+
+	if _, err := os.Stat(exportedFileName + ".gz"); err != nil {
+		t.Fatalf("Expected the compressed file to be created: %s", err)
+	}
+	fileInfo := utils.GetFileInfo(exportedFileName + ".gz")
+	if fileInfo.ResourceName != "App1" || fileInfo.FileName != "App1.yml" || fileInfo.FileExtension != ".yml" {
+		t.Errorf("Unexpected file info for the compressed file: %+v", fileInfo)
+	}
+
+	for _, filePath := range []string{exportedFileName, exportedFileName + ".gz"} {
+		readContent, err := utils.ReadResourceFile(filePath)
+		if err != nil {
+			t.Fatalf("Unexpected error when reading %s: %s", filePath, err)
+		}
+		if string(readContent) != string(content) {
+			t.Errorf("Expected content %q but got %q", content, readContent)
+		}
+	}
+}
+
+func TestOmitNullFields(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	utils.OMIT_NULL = true
+	defer func() { utils.OMIT_NULL = false }()
+
+	content := "applicationName: App1\ndescription: null\ninboundAuthenticationConfig:\n" +
+		"  inboundAuthenticationRequestConfigs:\n" +
+		"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+		"      callbackUrl: null\n      oauthConsumerKey: key\n" +
+		"    inboundAuthKey: key\n    properties: []\nrequestPathAuthenticatorConfigs:\n- null\n"
+	expected := "applicationName: App1\ninboundAuthenticationConfig:\n" +
+		"  inboundAuthenticationRequestConfigs:\n" +
+		"  - inboundAuthKey: key\n" +
+		"    inboundConfigurationProtocol:\n      !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+		"      oauthConsumerKey: key\n    properties: []\nrequestPathAuthenticatorConfigs:\n- null\n"
+
+	fileName := filepath.Join(tempDir, "App1.yml")
+	if err := utils.WriteExportedFile(fileName, []byte(content)); err != nil {
+		t.Fatalf("Unexpected error when writing the exported file: %s", err)
+	}
+	writtenContent, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	if string(writtenContent) != expected {
+		t.Errorf("Expected the exported content:\n%s\nbut got:\n%s", expected, writtenContent)
+	}
+	if !utils.IsContentEqual(writtenContent, []byte(content), nil) {
+		t.Errorf("Expected the content without null fields to be equal to the content with null fields")
+	}
+}
+
+func TestIsResourceIncluded(t *testing.T) {
+	utils.INCLUDE_ONLY = utils.ParseIncludeFilter(" payments-*, hr-portal ,")
+	defer func() { utils.INCLUDE_ONLY = nil }()
+
+	testCases := []struct {
+		resourceName string
+		expected     bool
+	}{
+		{resourceName: "payments-api", expected: true},
+		{resourceName: "payments-", expected: true},
+		{resourceName: "hr-portal", expected: true},
+		{resourceName: "hr-portal-v2", expected: false},
+		{resourceName: "Console", expected: false},
+	}
+
+	for _, tc := range testCases {
+		if result := utils.IsResourceIncluded(tc.resourceName); result != tc.expected {
+			t.Errorf("Expected IsResourceIncluded(%q) to be %v but got %v", tc.resourceName, tc.expected, result)
+		}
+	}
+
+	utils.INCLUDE_ONLY = nil
+	if !utils.IsResourceIncluded("Console") {
+		t.Errorf("Expected all resources to be included when no filter is set")
+	}
+}
+
+func TestGetResourcePriority(t *testing.T) {
+	utils.TOOL_CONFIGS.Priority = []string{"payments-*", "hr-portal"}
+	defer func() { utils.TOOL_CONFIGS.Priority = nil }()
+
+	testCases := []struct {
+		resourceName string
+		expected     int
+	}{
+		{resourceName: "payments-api", expected: 0},
+		{resourceName: "hr-portal", expected: 1},
+		{resourceName: "Console", expected: 2},
+	}
+
+	for _, tc := range testCases {
+		if result := utils.GetResourcePriority(tc.resourceName); result != tc.expected {
+			t.Errorf("Expected priority of %q to be %d but got %d", tc.resourceName, tc.expected, result)
+		}
+	}
+}
+
+func TestIsTimeBudgetAvailable(t *testing.T) {
+	defer utils.StartTimeBudget(0)
+
+	utils.StartTimeBudget(0)
+	if !utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected the time budget to be available when no budget is set")
+	}
+
+	utils.StartTimeBudget(time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	if utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected the time budget to be exhausted")
+	}
+}
+
+func TestIsSystemIdp(t *testing.T) {
+	defer func() { utils.TOOL_CONFIGS.IdpConfigs = nil }()
+
+	testCases := []struct {
+		name       string
+		idpConfigs map[string]interface{}
+		idpName    string
+		expected   bool
+	}{
+		{name: "Default system IDP", idpName: "FILE_BASED", expected: true},
+		{name: "Default non system IDP", idpName: "Google", expected: false},
+		{
+			name:       "Configured system IDP",
+			idpConfigs: map[string]interface{}{"SYSTEM_IDPS": []interface{}{"SSO"}},
+			idpName:    "SSO",
+			expected:   true,
+		},
+		{
+			name:       "Default overridden by config",
+			idpConfigs: map[string]interface{}{"SYSTEM_IDPS": []interface{}{"SSO"}},
+			idpName:    "FILE_BASED",
+			expected:   false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			utils.TOOL_CONFIGS.IdpConfigs = tc.idpConfigs
+			if result := utils.IsSystemIdp(tc.idpName); result != tc.expected {
+				t.Errorf("Expected IsSystemIdp(%q) to be %v but got %v", tc.idpName, tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestImportStateRoundTrip(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	utils.LoadImportState(tempDir)
+	utils.UpdateImportState(utils.APPLICATIONS, "App1", "applicationName: App1\n")
+	if err := utils.SaveImportState(); err != nil {
+		t.Fatalf("Unexpected error when saving the import state: %s", err)
+	}
+
+	utils.LoadImportState(tempDir)
+	if !utils.IsImportStateUnchanged(utils.APPLICATIONS, "App1", "applicationName: App1\n") {
+		t.Errorf("Expected the saved content to be unchanged")
+	}
+	if utils.IsImportStateUnchanged(utils.APPLICATIONS, "App1", "applicationName: App2\n") {
+		t.Errorf("Expected the modified content to be changed")
+	}
+	if utils.IsImportStateUnchanged(utils.IDENTITY_PROVIDERS, "App1", "applicationName: App1\n") {
+		t.Errorf("Expected a resource of another type to be changed")
+	}
+
+	utils.FORCE_IMPORT = true
+	if utils.IsImportStateUnchanged(utils.APPLICATIONS, "App1", "applicationName: App1\n") {
+		t.Errorf("Expected the import state to be ignored when the import is forced")
+	}
+	utils.FORCE_IMPORT = false
+
+	stateFilePath := filepath.Join(tempDir, utils.IMPORT_STATE_DIR, utils.IMPORT_STATE_FILE)
+	if err := ioutil.WriteFile(stateFilePath, []byte(`{"version": 99, "environments": {}}`), 0644); err != nil {
+		t.Fatalf("Unexpected error when writing the import state file: %s", err)
+	}
+	utils.LoadImportState(tempDir)
+	if utils.IsImportStateUnchanged(utils.APPLICATIONS, "App1", "applicationName: App1\n") {
+		t.Errorf("Expected the import state of an unsupported version to be ignored")
+	}
+}
+
+func TestValidateWarningCategories(t *testing.T) {
+	testCases := []struct {
+		name        string
+		categories  []string
+		expectError bool
+	}{
+		{
+			name:        "No categories",
+			categories:  []string{},
+			expectError: false,
+		},
+		{
+			name:        "Supported categories",
+			categories:  []string{utils.WARNING_MASKED_SECRET, utils.WARNING_EXPIRING_CERTIFICATE},
+			expectError: false,
+		},
+		{
+			name:        "Unknown category",
+			categories:  []string{utils.WARNING_MASKED_SECRET, "unknown"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := utils.ValidateWarningCategories(tc.categories)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Expected error: %v, got: %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestGetCertificateExpiry(t *testing.T) {
+	certificate := `-----BEGIN CERTIFICATE-----
+MIIB+jCCAWOgAwIBAgIUEhcEC5qwhYGEwauFcS06L+vVaR4wDQYJKoZIhvcNAQEL
+BQAwDzENMAsGA1UEAwwEdGVzdDAeFw0yNjEwMTcwMDI5NTNaFw0zNjEwMTQwMDI5
+NTNaMA8xDTALBgNVBAMMBHRlc3QwgZ8wDQYJKoZIhvcNAQEBBQADgY0AMIGJAoGB
+AOD1CfiMd0QScFrx2yqBFy71g9PAv7f1kZ1v8VEdf+owgyGJGz6gFQ8Cbh2f8sfi
+wOGLwkzuN98PrnfImYZfSEGM/KXz+MKb65PXPgBp9dov29DDk5ymY4r0HcHQNH0H
+1+iTJEwXCfddjfmr4AcdqVOXaav1seADK9JZ5L4Xio6VAgMBAAGjUzBRMB0GA1Ud
+DgQWBBQK0Du7wwkgFJEax3ga+qQiT/8HpjAfBgNVHSMEGDAWgBQK0Du7wwkgFJEa
+x3ga+qQiT/8HpjAPBgNVHRMBAf8EBTADAQH/MA0GCSqGSIb3DQEBCwUAA4GBAFzr
+deXQxgnUArgNBxowUV54C3zZh7jzzoSe/nCcCdH/15zTR/ryyeo5F7vo8CUjthK9
+/fG7tpRPGR62+9cFuBfXIwEjd4cL5ddBcHPjWOAnfuK+ZVU14tOqntJYJdhErowc
+3dI3UKD3O8LWsbOkEwst0RGJK/dcL7n0hoR6ZvX8
+-----END CERTIFICATE-----`
+	expectedExpiry := time.Date(2036, time.October, 14, 0, 29, 53, 0, time.UTC)
+
+	testCases := []struct {
+		name               string
+		certificateContent string
+		expectError        bool
+	}{
+		{
+			name:               "PEM certificate",
+			certificateContent: certificate,
+		},
+		{
+			name:               "Base64 encoded PEM certificate",
+			certificateContent: "LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCk1JSUIrakNDQVdPZ0F3SUJBZ0lVRWhjRUM1cXdoWUdFd2F1RmNTMDZMK3ZWYVI0d0RRWUpLb1pJaHZjTkFRRUwKQlFBd0R6RU5NQXNHQTFVRUF3d0VkR1Z6ZERBZUZ3MHlOakV3TVRjd01ESTVOVE5hRncwek5qRXdNVFF3TURJNQpOVE5hTUE4eERUQUxCZ05WQkFNTUJIUmxjM1F3Z1o4d0RRWUpLb1pJaHZjTkFRRUJCUUFEZ1kwQU1JR0pBb0dCCkFPRDFDZmlNZDBRU2NGcngyeXFCRnk3MWc5UEF2N2Yxa1oxdjhWRWRmK293Z3lHSkd6NmdGUThDYmgyZjhzZmkKd09HTHdrenVOOThQcm5mSW1ZWmZTRUdNL0tYeitNS2I2NVBYUGdCcDlkb3YyOUREazV5bVk0cjBIY0hRTkgwSAoxK2lUSkV3WENmZGRqZm1yNEFjZHFWT1hhYXYxc2VBREs5Slo1TDRYaW82VkFnTUJBQUdqVXpCUk1CMEdBMVVkCkRnUVdCQlFLMER1N3d3a2dGSkVheDNnYStxUWlULzhIcGpBZkJnTlZIU01FR0RBV2dCUUswRHU3d3drZ0ZKRWEKeDNnYStxUWlULzhIcGpBUEJnTlZIUk1CQWY4RUJUQURBUUgvTUEwR0NTcUdTSWIzRFFFQkN3VUFBNEdCQUZ6cgpkZVhReGduVUFyZ05CeG93VVY1NEMzelpoN2p6em9TZS9uQ2NDZEgvMTV6VFIvcnl5ZW81Rjd2bzhDVWp0aEs5Ci9mRzd0cFJQR1I2Mis5Y0Z1QmZYSXdFamQ0Y0w1ZGRCY0hQaldPQW5mdUsrWlZVMTR0T3FudEpZSmRoRXJvd2MKM2RJM1VLRDNPOExXc2JPa0V3c3QwUkdKSy9kY0w3bjBob1I2WnZYOAotLS0tLUVORCBDRVJUSUZJQ0FURS0tLS0tCg==",
+		},
+		{
+			name:               "Invalid certificate",
+			certificateContent: "invalid",
+			expectError:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			expiry, err := utils.GetCertificateExpiry(tc.certificateContent)
+			if (err != nil) != tc.expectError {
+				t.Fatalf("Expected error: %v, got: %v", tc.expectError, err)
+			}
+			if !tc.expectError && !expiry.Equal(expectedExpiry) {
+				t.Errorf("Expected expiry: %s, got: %s", expectedExpiry, expiry)
+			}
+		})
+	}
+}
+
+func TestGetExternallyManagedSignatures(t *testing.T) {
+	appConfigs := map[string]interface{}{
+		"EXTERNALLY_MANAGED": map[string]interface{}{
+			"NAME_PATTERNS": []interface{}{"analytics-*"},
+			"TEMPLATE_IDS":  []interface{}{"m2m-application"},
+			"CREATORS":      []interface{}{"PRIMARY/apim-service"},
+		},
+	}
+	signatures := utils.GetExternallyManagedSignatures(appConfigs)
+
+	testCases := []struct {
+		name     string
+		value    string
+		patterns []string
+		expected bool
+	}{
+		{name: "Default name pattern", value: "admin_DefaultApplication_PRODUCTION", patterns: signatures.NamePatterns, expected: true},
+		{name: "Configured name pattern", value: "analytics-dashboard", patterns: signatures.NamePatterns, expected: true},
+		{name: "Unmatched name", value: "hr-portal", patterns: signatures.NamePatterns, expected: false},
+		{name: "Configured template id", value: "m2m-application", patterns: signatures.TemplateIds, expected: true},
+		{name: "Configured creator", value: "PRIMARY/apim-service", patterns: signatures.Creators, expected: true},
+		{name: "Unmatched creator", value: "PRIMARY/admin", patterns: signatures.Creators, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := utils.MatchesAnyPattern(tc.value, tc.patterns); result != tc.expected {
+				t.Errorf("Expected MatchesAnyPattern(%q) to be %v but got %v", tc.value, tc.expected, result)
+			}
+		})
+	}
+
+	defaultSignatures := utils.GetExternallyManagedSignatures(map[string]interface{}{})
+	if len(defaultSignatures.TemplateIds) != 0 || len(defaultSignatures.Creators) != 0 {
+		t.Errorf("Expected no default template id or creator signatures but got %+v", defaultSignatures)
+	}
+}
+
+func TestIsResourceTypeDeleteAllowed(t *testing.T) {
+	defer func() {
+		utils.INCLUDE_ONLY = nil
+		utils.NO_DELETE = false
+	}()
+
+	testCases := []struct {
+		name            string
+		resourceConfigs map[string]interface{}
+		includeOnly     []string
+		noDelete        bool
+		expected        bool
+	}{
+		{name: "Not configured", resourceConfigs: map[string]interface{}{}, expected: false},
+		{name: "Allowed for resource type", resourceConfigs: map[string]interface{}{"ALLOW_DELETE": true}, expected: true},
+		{
+			name:            "Filter active",
+			resourceConfigs: map[string]interface{}{"ALLOW_DELETE": true},
+			includeOnly:     []string{"AccountConfirmation"},
+			expected:        false,
+		},
+		{name: "No delete flag", resourceConfigs: map[string]interface{}{"ALLOW_DELETE": true}, noDelete: true, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			utils.INCLUDE_ONLY = tc.includeOnly
+			utils.NO_DELETE = tc.noDelete
+			if result := utils.IsResourceTypeDeleteAllowed(tc.resourceConfigs); result != tc.expected {
+				t.Errorf("Expected IsResourceTypeDeleteAllowed to be %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestMaskSensitiveFields(t *testing.T) {
+	restoreConfigs(t)
+	utils.SERVER_CONFIGS.SensitiveFields = []string{"password", " clientSecret ", "oauthConsumerSecret"}
+
+	fields := utils.GetSensitiveFields()
+	if !reflect.DeepEqual(fields, []string{"oauthConsumerSecret", "password", "clientSecret"}) {
+		t.Fatalf("Unexpected sensitive fields: %v", fields)
+	}
+
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "OAuth consumer secret",
+			content:  "name: App1\n      oauthConsumerSecret: null\n",
+			expected: "name: App1\n      oauthConsumerSecret: '********'\n",
+		},
+		{
+			name:     "Configured fields in a list",
+			content:  "properties:\n  - password: null\n  - clientSecret: null  \n  - clientId: null\n",
+			expected: "properties:\n  - password: '********'\n  - clientSecret: '********'\n  - clientId: null\n",
+		},
+		{
+			name:     "Field with a value",
+			content:  "password: secret\nmyPassword: null\n",
+			expected: "password: secret\nmyPassword: null\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := string(utils.MaskSensitiveFields([]byte(tc.content), fields)); result != tc.expected {
+				t.Errorf("Expected masked content %q but got %q", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestClassifyExportedContent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{name: "No sensitive fields", content: "applicationName: App1\n", expected: utils.CLASSIFICATION_PUBLIC},
+		{name: "Masked field", content: "oauthConsumerSecret: '********'\n", expected: utils.CLASSIFICATION_SENSITIVE},
+		{
+			name:     "PEM certificate",
+			content:  "certificateContent: |\n  -----BEGIN CERTIFICATE-----\n  MIIB\n  -----END CERTIFICATE-----\n",
+			expected: utils.CLASSIFICATION_RESTRICTED,
+		},
+		{
+			name:     "Base64 encoded certificate with masked field",
+			content:  "certificate: LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0t\nclientSecret: '********'\n",
+			expected: utils.CLASSIFICATION_RESTRICTED,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := utils.ClassifyExportedContent([]byte(tc.content)); result != tc.expected {
+				t.Errorf("Expected classification %s but got %s", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestLintLocalResources(t *testing.T) {
+	tempDir := newTempDir(t)
+
+	resourceFiles := map[string]string{
+		"Applications/App1.yml": "applicationName: App1\ninboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+			"  - inboundAuthType: oauth2\n    inboundConfigurationProtocol:\n      callbackUrl: regexp=(https://a.com/cb|https://b.com/cb)\n" +
+			"      grantTypes: authorization_code\n      allowedOrigins:\n      - https://b.com\n      - https://c.com\n",
+		"Applications/App2.yml": "applicationName: App1\ninboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+			"  - inboundAuthType: oauth2\n    inboundConfigurationProtocol:\n      callbackUrl: ''\n      grantTypes: implicit\n",
+		"IdentityProviders/Idp1.yml": "identityProviderName: Idp1\ncertificate: ''\nfederatedAuthenticatorConfigs:\n" +
+			"- name: SAMLSSOAuthenticator\n  enabled: true\n",
+		"IdentityProviders/Idp2.yml": "identityProviderName: Idp2\nfederatedAuthenticatorConfigs:\n- name: SAMLSSOAuthenticator\n" +
+			"  enabled: true\nidpProperties:\n- name: jwksUri\n  value: https://idp.com/jwks\ncertificate: <certificate>\n",
+		"Claims/local.yml": "id: local\ndialectURI: '{{CLAIM_DIALECT}}'\n",
+	}
+	for path, content := range resourceFiles {
+		filePath := filepath.Join(tempDir, path)
+		os.MkdirAll(filepath.Dir(filePath), 0700)
+		if err := ioutil.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error when writing the resource file: %s", err)
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		disabledRules []string
+		expected      []string
+	}{
+		{
+			name:          "All rules enabled",
+			disabledRules: []string{},
+			expected: []string{
+				utils.LINT_ALLOWED_ORIGINS, utils.LINT_DUPLICATE_APP_NAME, utils.LINT_DUPLICATE_APP_NAME,
+				utils.LINT_OAUTH_REDIRECT_URI, utils.LINT_UNDEFINED_KEYWORD, utils.LINT_IDP_CERTIFICATE,
+				utils.LINT_CERTIFICATE_PLACEHOLDER,
+			},
+		},
+		{
+			name:          "Disabled rules",
+			disabledRules: []string{utils.LINT_DUPLICATE_APP_NAME, utils.LINT_UNDEFINED_KEYWORD, utils.LINT_CERTIFICATE_PLACEHOLDER},
+			expected:      []string{utils.LINT_ALLOWED_ORIGINS, utils.LINT_OAUTH_REDIRECT_URI, utils.LINT_IDP_CERTIFICATE},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			violations, err := utils.LintLocalResources(tempDir, tc.disabledRules)
+			if err != nil {
+				t.Fatalf("Unexpected error when linting the resource files: %s", err)
+			}
+			rules := []string{}
+			for _, violation := range violations {
+				rules = append(rules, violation.Rule)
+			}
+			if !reflect.DeepEqual(rules, tc.expected) {
+				t.Errorf("Expected violations %v but got %v", tc.expected, violations)
+			}
+		})
+	}
+}
+
+func TestRemoveSensitiveAttributes(t *testing.T) {
+	user := map[string]interface{}{
+		"userName": "alice",
+		"password": "secret",
+		"emails":   []interface{}{map[string]interface{}{"value": "alice@example.com"}},
+		"urn:scim:wso2:schema": map[string]interface{}{
+			"department":            "eng",
+			"askPassword":           "false",
+			"challengeQuestionUris": "http://wso2.org/claims/challengeQuestion1",
+			"ChallengeQuestion1":    "q1",
+		},
+	}
+	expected := map[string]interface{}{
+		"userName":             "alice",
+		"emails":               []interface{}{map[string]interface{}{"value": "alice@example.com"}},
+		"urn:scim:wso2:schema": map[string]interface{}{"department": "eng"},
+	}
+
+	if result := users.RemoveSensitiveAttributes(user); !reflect.DeepEqual(result, expected) {
+		t.Errorf("Expected user %v but got %v", expected, result)
+	}
+}
+
+func TestFindMaskedFields(t *testing.T) {
+	testCases := []struct {
+		name     string
+		content  string
+		expected []string
+	}{
+		{name: "No masked fields", content: "applicationName: App1\n", expected: nil},
+		{
+			name: "Masked fields in nested maps and lists",
+			content: "applicationName: App1\ninboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n" +
+				"  - inboundConfigurationProtocol: !!org.wso2.carbon.identity.oauth.dto.OAuthConsumerAppDTO\n" +
+				"      oauthConsumerSecret: '********'\nclientSecret: '********'\n",
+			expected: []string{
+				"clientSecret",
+				"inboundAuthenticationConfig.inboundAuthenticationRequestConfigs[0].inboundConfigurationProtocol.oauthConsumerSecret",
+			},
+		},
+		{name: "Invalid YAML", content: "key: [\nsecret: '********'\n", expected: []string{"line 2"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := utils.FindMaskedFields([]byte(tc.content)); !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expected masked fields %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsSystemClaim(t *testing.T) {
+	utils.TOOL_CONFIGS.ClaimConfigs = map[string]interface{}{
+		utils.SYSTEM_CLAIMS_CONFIG: []interface{}{"http://wso2.org/claims/identity/*"},
+	}
+	defer func() { utils.TOOL_CONFIGS.ClaimConfigs = nil }()
+
+	testCases := []struct {
+		name       string
+		claimURI   string
+		properties map[string]string
+		expected   bool
+	}{
+		{name: "Flagged by the server", claimURI: "http://wso2.org/claims/username",
+			properties: map[string]string{utils.SYSTEM_CLAIM_PROPERTY: "true"}, expected: true},
+		{name: "Matching the config", claimURI: "http://wso2.org/claims/identity/accountLocked", expected: true},
+		{name: "Custom claim", claimURI: "http://wso2.org/claims/department",
+			properties: map[string]string{utils.SYSTEM_CLAIM_PROPERTY: "false"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if result := utils.IsSystemClaim(tc.claimURI, tc.properties); result != tc.expected {
+				t.Errorf("Expected %t for claim %s but got %t", tc.expected, tc.claimURI, result)
+			}
+		})
+	}
+}
+
+func TestCheckResourceQuotas(t *testing.T) {
+	testCases := []struct {
+		name        string
+		quotas      []utils.ResourceQuota
+		allowDelete bool
+		expectError bool
+	}{
+		{
+			name:   "Within the quota",
+			quotas: []utils.ResourceQuota{{ResourceType: utils.APPLICATIONS, Deployed: 5, Creates: 5, Limit: 10}},
+		},
+		{
+			name:        "Exceeding the quota",
+			quotas:      []utils.ResourceQuota{{ResourceType: utils.APPLICATIONS, Deployed: 5, Creates: 6, Limit: 10}},
+			expectError: true,
+		},
+		{
+			name:        "More resources deployed than the limit",
+			quotas:      []utils.ResourceQuota{{ResourceType: utils.IDENTITY_PROVIDERS, Deployed: 12, Creates: 1, Limit: 10}},
+			expectError: true,
+		},
+		{
+			name:        "Exceeding the quota with deletion allowed",
+			quotas:      []utils.ResourceQuota{{ResourceType: utils.APPLICATIONS, Deployed: 5, Creates: 6, Limit: 10}},
+			allowDelete: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			utils.TOOL_CONFIGS.AllowDelete = tc.allowDelete
+			defer func() { utils.TOOL_CONFIGS.AllowDelete = false }()
+
+			err := utils.CheckResourceQuotas(tc.quotas)
+			if (err != nil) != tc.expectError {
+				t.Errorf("Expected error: %t but got: %v", tc.expectError, err)
+			}
+		})
+	}
+}
+
+func TestGetPolicyId(t *testing.T) {
+	testCases := []struct {
+		name       string
+		content    string
+		expectedId string
+		expectErr  bool
+	}{
+		{
+			name:       "Policy",
+			content:    `<?xml version="1.0"?><Policy xmlns="urn:oasis:names:tc:xacml:3.0:core:schema:wd-17" PolicyId="time_based_policy" Version="1.0"></Policy>`,
+			expectedId: "time_based_policy",
+		},
+		{
+			name:       "Policy set",
+			content:    `<!-- Policy set --><PolicySet PolicySetId="admin_policies" Version="1.0"></PolicySet>`,
+			expectedId: "admin_policies",
+		},
+		{
+			name:      "Missing policy ID",
+			content:   `<Policy Version="1.0"></Policy>`,
+			expectErr: true,
+		},
+		{
+			name:      "Empty content",
+			content:   "",
+			expectErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			policyId, err := xacmlpolicies.GetPolicyId(tc.content)
+			if (err != nil) != tc.expectErr {
+				t.Fatalf("Expected error: %v but got: %v", tc.expectErr, err)
+			}
+			if policyId != tc.expectedId {
+				t.Errorf("Expected policy ID %s but got %s", tc.expectedId, policyId)
+			}
+		})
+	}
+}
+
+func TestSetConnectionPassword(t *testing.T) {
+	testCases := []struct {
+		name             string
+		content          string
+		expectedReplaced bool
+		expectedContent  string
+	}{
+		{
+			name: "Masked password",
+			content: "name: SECONDARY\nproperties:\n- name: ConnectionURL\n  value: ldap://localhost:10389\n" +
+				"- name: ConnectionPassword\n  value: '********'\n",
+			expectedReplaced: true,
+			expectedContent: "name: SECONDARY\nproperties:\n- name: ConnectionURL\n  value: ldap://localhost:10389\n" +
+				"- name: ConnectionPassword\n  value: s3cret\n",
+		},
+		{
+			name:             "Password set in the file",
+			content:          "name: SECONDARY\nproperties:\n- name: ConnectionPassword\n  value: '{{LDAP_PASSWORD}}'\n",
+			expectedReplaced: false,
+			expectedContent:  "name: SECONDARY\nproperties:\n- name: ConnectionPassword\n  value: '{{LDAP_PASSWORD}}'\n",
+		},
+		{
+			name:             "Other masked property",
+			content:          "name: SECONDARY\nproperties:\n- name: DataSourcePassword\n  value: '********'\n",
+			expectedReplaced: false,
+			expectedContent:  "name: SECONDARY\nproperties:\n- name: DataSourcePassword\n  value: '********'\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, replaced, err := userstores.SetConnectionPassword(tc.content, "s3cret")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if replaced != tc.expectedReplaced || content != tc.expectedContent {
+				t.Errorf("Expected %v and content:\n%s\nbut got %v and content:\n%s", tc.expectedReplaced, tc.expectedContent,
+					replaced, content)
+			}
+		})
+	}
+}
+
+func TestFilterIdpsByProtocol(t *testing.T) {
+	authenticators := func(authenticators ...identityproviders.FederatedAuthenticator) *identityproviders.FederatedAuthenticatorList {
+		return &identityproviders.FederatedAuthenticatorList{Authenticators: authenticators}
+	}
+	idps := []identityproviders.IdpListItem{
+		{Name: "Okta", FederatedAuthenticators: authenticators(
+			identityproviders.FederatedAuthenticator{Name: "OpenIDConnectAuthenticator", IsEnabled: true})},
+		{Name: "ADFS", FederatedAuthenticators: authenticators(
+			identityproviders.FederatedAuthenticator{Name: "PassiveSTSAuthenticator", IsEnabled: true},
+			identityproviders.FederatedAuthenticator{Name: "SAMLSSOAuthenticator", IsEnabled: true})},
+		{Name: "Legacy", FederatedAuthenticators: authenticators(
+			identityproviders.FederatedAuthenticator{Name: "SAMLSSOAuthenticator", IsEnabled: false})},
+		{Name: "Custom"},
+	}
+	testCases := []struct {
+		protocol      string
+		expectedNames []string
+	}{
+		{protocol: identityproviders.PROTOCOL_OIDC, expectedNames: []string{"Okta"}},
+		{protocol: identityproviders.PROTOCOL_SAML, expectedNames: []string{"ADFS"}},
+		{protocol: identityproviders.PROTOCOL_WS_FEDERATION, expectedNames: []string{"ADFS"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.protocol, func(t *testing.T) {
+			var names []string
+			for _, idp := range identityproviders.FilterIdpsByProtocol(idps, tc.protocol) {
+				names = append(names, idp.Name)
+			}
+			if !reflect.DeepEqual(names, tc.expectedNames) {
+				t.Errorf("Expected %v but got %v", tc.expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestFindTenantMismatches(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		organizationId string
+		expected       []utils.TenantMismatch
+	}{
+		{name: "URLs of the target tenant", content: "callbackUrl: https://localhost:9443/t/prod.com/commonauth\n" +
+			"accessUrl: https://app.example.com/login\n", expected: nil},
+		{
+			name: "URL of another tenant in a regex callback URL",
+			content: "inboundAuthenticationConfig:\n  inboundAuthenticationRequestConfigs:\n  - inboundConfigurationProtocol:\n" +
+				"      callbackUrl: regexp=(https://app.example.com/cb|https://localhost:9443/t/dev.com/commonauth)\n",
+			expected: []utils.TenantMismatch{{
+				Path: "inboundAuthenticationConfig.inboundAuthenticationRequestConfigs[0].inboundConfigurationProtocol.callbackUrl",
+				Url:  "https://localhost:9443/t/dev.com/commonauth",
+			}},
+		},
+		{name: "Unresolved keyword", content: "callbackUrl: https://localhost:9443/t/{{TENANT}}/commonauth\n", expected: nil},
+		{name: "Organization not configured", content: "issuer: https://localhost:9443/o/1234/oauth2/token\n", expected: nil},
+		{
+			name:           "URL of another organization",
+			content:        "issuer: https://localhost:9443/t/prod.com/o/1234/oauth2/token\n",
+			organizationId: "5678",
+			expected: []utils.TenantMismatch{{
+				Path: "issuer",
+				Url:  "https://localhost:9443/t/prod.com/o/1234/oauth2/token",
+			}},
+		},
+		{
+			name:     "Invalid YAML",
+			content:  "key: [\n<Target>https://localhost:9443/t/dev.com/scim2</Target>\n",
+			expected: []utils.TenantMismatch{{Path: "line 2", Url: "https://localhost:9443/t/dev.com/scim2"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := utils.FindTenantMismatches([]byte(tc.content), "prod.com", tc.organizationId)
+			if !reflect.DeepEqual(result, tc.expected) {
+				t.Errorf("Expected tenant mismatches %v but got %v", tc.expected, result)
+			}
+		})
+	}
+}
+
+func TestIsSystemRole(t *testing.T) {
+	testCases := []struct {
+		name         string
+		roleName     string
+		audienceType string
+		audienceName string
+		systemRoles  []interface{}
+		expected     bool
+	}{
+		{name: "Default system role", roleName: "everyone", audienceType: "organization", expected: true},
+		{name: "Internal role", roleName: "Internal/everyone", audienceType: "organization", expected: true},
+		{name: "Role of a system application", roleName: "Administrator", audienceType: "application",
+			audienceName: "Console", expected: true},
+		{name: "Organization role", roleName: "hr-manager", audienceType: "organization", expected: false},
+		{name: "Role of an application", roleName: "admin", audienceType: "APPLICATION",
+			audienceName: "hr-portal", expected: false},
+		{name: "Overridden by the config", roleName: "admin", audienceType: "organization",
+			systemRoles: []interface{}{"everyone"}, expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			utils.TOOL_CONFIGS.RoleConfigs = nil
+			if tc.systemRoles != nil {
+				utils.TOOL_CONFIGS.RoleConfigs = map[string]interface{}{utils.SYSTEM_ROLES_CONFIG: tc.systemRoles}
+			}
+			defer func() { utils.TOOL_CONFIGS.RoleConfigs = nil }()
+
+			roleKey := roles.GetRoleKey(tc.roleName, tc.audienceType, tc.audienceName)
+			if result := utils.IsSystemRole(roleKey); result != tc.expected {
+				t.Errorf("Expected %t for role %s but got %t", tc.expected, roleKey, result)
+			}
+		})
+	}
+}
+
+func TestIsSystemApiResource(t *testing.T) {
+	testCases := []struct {
+		name            string
+		identifier      string
+		apiResourceType string
+		systemResources []interface{}
+		expected        bool
+	}{
+		{name: "Business API resource", identifier: "https://orders.example.com", apiResourceType: "BUSINESS", expected: false},
+		{name: "System API resource", identifier: "/api/server/v1/applications", apiResourceType: "SYSTEM", expected: true},
+		{name: "Tenant API resource", identifier: "/api/server/v1/roles", apiResourceType: "TENANT", expected: true},
+		{name: "Local file without a type", identifier: "https://orders.example.com", expected: false},
+		{name: "Matching the config", identifier: "https://internal.example.com/audit", apiResourceType: "BUSINESS",
+			systemResources: []interface{}{"https://internal.example.com/*"}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			utils.TOOL_CONFIGS.ApiResourceConfigs = nil
+			if tc.systemResources != nil {
+				utils.TOOL_CONFIGS.ApiResourceConfigs = map[string]interface{}{utils.SYSTEM_API_RESOURCES_CONFIG: tc.systemResources}
+			}
+			defer func() { utils.TOOL_CONFIGS.ApiResourceConfigs = nil }()
+
+			if result := utils.IsSystemApiResource(tc.identifier, tc.apiResourceType); result != tc.expected {
+				t.Errorf("Expected %t for API resource %s but got %t", tc.expected, tc.identifier, result)
+			}
+		})
+	}
+}
+
+func TestIsModifiedSince(t *testing.T) {
+	testCases := []struct {
+		name         string
+		since        string
+		lastModified string
+		expected     bool
+	}{
+		{name: "Without the since flag", since: "", lastModified: "2020-01-01T00:00:00Z", expected: true},
+		{name: "Modified after", since: "2024-01-01T00:00:00Z", lastModified: "2024-03-01T10:00:00.000Z", expected: true},
+		{name: "Modified before", since: "2024-01-01T00:00:00Z", lastModified: "2023-12-31T23:59:59Z", expected: false},
+		{name: "Epoch milliseconds", since: "2024-01-01T00:00:00Z", lastModified: "1704067200001", expected: true},
+		{name: "Old epoch milliseconds", since: "2024-01-01T00:00:00Z", lastModified: "1700000000000", expected: false},
+		{name: "Missing modification time", since: "2024-01-01T00:00:00Z", lastModified: "", expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := utils.ParseSince(tc.since); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer func() { utils.SINCE = time.Time{} }()
+
+			if result := utils.IsModifiedSince(tc.lastModified); result != tc.expected {
+				t.Errorf("Expected %t for %s but got %t", tc.expected, tc.lastModified, result)
+			}
+		})
+	}
+
+	if err := utils.ParseSince("2024-01-01"); err == nil {
+		t.Errorf("Expected an error for a timestamp without a time")
+	}
+}
+
+func TestWarnUnfilteredSince(t *testing.T) {
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+
+	utils.WarnUnfilteredSince(utils.APPLICATIONS)
+	if logs.Len() != 0 {
+		t.Errorf("Expected no warning without the since flag but got: %s", logs.String())
+	}
+	if err := utils.ParseSince("2024-01-01T00:00:00Z"); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer func() { utils.SINCE = time.Time{} }()
+	utils.WarnUnfilteredSince(utils.APPLICATIONS)
+	if !strings.Contains(logs.String(), "The resources of "+utils.APPLICATIONS+" do not carry a modification time") {
+		t.Errorf("Expected a warning for the resource type exported completely but got: %s", logs.String())
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		first    string
+		second   string
+		expected int
+	}{
+		{name: "Older version", first: "1.0.7", second: "v1.0.8", expected: -1},
+		{name: "Same version", first: "v1.0.8", second: "1.0.8", expected: 0},
+		{name: "Newer version", first: "1.1.0", second: "v1.0.10", expected: 1},
+		{name: "Snapshot version", first: "1.0.8-SNAPSHOT", second: "v1.0.8", expected: 0},
+		{name: "Different number of parts", first: "1.0", second: "v1.0.1", expected: -1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := utils.CompareVersions(tc.first, tc.second)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if sign(result) != tc.expected {
+				t.Errorf("Expected %d when comparing %s with %s but got %d", tc.expected, tc.first, tc.second, result)
+			}
+		})
+	}
+
+	if _, err := utils.CompareVersions("dev", "v1.0.8"); err == nil {
+		t.Errorf("Expected an error for an invalid version")
+	}
+}
+
+func sign(value int) int {
+	if value < 0 {
+		return -1
+	}
+	if value > 0 {
+		return 1
+	}
+	return 0
+}
+
+func TestLoadEnvironmentManifest(t *testing.T) {
+	baseDir := newTempDir(t)
+	if err := os.Mkdir(filepath.Join(baseDir, "base"), 0700); err != nil {
+		t.Fatalf("Error when creating the resource directory: %s", err)
+	}
+
+	testCases := []struct {
+		name          string
+		manifest      string
+		expectedError string
+	}{
+		{
+			name:     "Partial manifest",
+			manifest: "resources:\n  - dir: base\n    types: [Roles]\n",
+		},
+		{
+			name:          "Unknown field",
+			manifest:      "profile:\n  config: configs/dev\n  envv: dev\nresources:\n  - dir: base\n",
+			expectedError: "line 3",
+		},
+		{
+			name:          "Unknown resource type",
+			manifest:      "resources:\n  - dir: base\n    types: [Aplications]\n",
+			expectedError: "line 3: unknown resource type: Aplications",
+		},
+		{
+			name:          "Missing directory",
+			manifest:      "resources:\n  - dir: base\n  - dir: overlays\n",
+			expectedError: "line 3: resource directory does not exist",
+		},
+		{
+			name:          "Invalid duration",
+			manifest:      "resources:\n  - dir: base\nguardrails:\n  timeBudget: 30\n",
+			expectedError: "line 4: invalid duration for timeBudget",
+		},
+		{
+			name:          "Missing directory named in another field",
+			manifest:      "profile:\n  config: overlays\nresources:\n  - dir: base\n  - dir: overlays\n",
+			expectedError: "line 5: resource directory does not exist",
+		},
+		{
+			name:          "Unknown resource type named in a comment",
+			manifest:      "# Roles of the environment\nresources:\n  - dir: base\n    types:\n      - Roles\n      - Role\n",
+			expectedError: "line 6: unknown resource type: Role",
+		},
+		{
+			name:          "Missing resource directory field",
+			manifest:      "resources:\n  - dir: base\n  - types: [Roles]\n",
+			expectedError: "line 3: dir is required for each resource",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manifestPath := filepath.Join(baseDir, "environment.yaml")
+			if err := ioutil.WriteFile(manifestPath, []byte(tc.manifest), 0600); err != nil {
+				t.Fatalf("Error when writing the manifest: %s", err)
+			}
+
+			manifest, err := utils.LoadEnvironmentManifest(manifestPath)
+			if tc.expectedError == "" {
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if types := manifest.Resources[0].GetTypes(); !reflect.DeepEqual(types, []string{utils.ROLES}) {
+					t.Errorf("Expected the types [Roles] but got %v", types)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.expectedError) {
+				t.Errorf("Expected an error containing %q but got %v", tc.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestNewImportPlan(t *testing.T) {
+	deployedResources := []utils.DeployedResource{
+		{Name: "App1", Deletable: true},
+		{Name: "App2", Deletable: true},
+		{Name: "Console", Deletable: false},
+	}
+	testCases := []struct {
+		name          string
+		localNames    []string
+		deleteAllowed bool
+		expected      utils.ImportPlan
+	}{
+		{"Creates and updates", []string{"App1", "App3"}, false, utils.ImportPlan{ResourceType: utils.APPLICATIONS, Creates: 1, Updates: 1}},
+		{"Deletes when allowed", []string{"App1", "App3"}, true, utils.ImportPlan{ResourceType: utils.APPLICATIONS, Creates: 1, Updates: 1, Deletes: 1}},
+		{"No local resources", nil, true, utils.ImportPlan{ResourceType: utils.APPLICATIONS, Deletes: 2}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			plan := utils.NewImportPlan(utils.APPLICATIONS, tc.localNames, deployedResources, tc.deleteAllowed)
+			if plan != tc.expected {
+				t.Errorf("Expected %+v but got %+v", tc.expected, plan)
+			}
+		})
+	}
+}
+
+func TestWatchExport(t *testing.T) {
+
+	isAvailable := false
+	server := newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		if isAvailable && r.URL.Path == utils.HEALTH_CHECK_PATH {
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	utils.SERVER_CONFIGS = utils.ServerConfigs{ServerUrl: server.URL}
+	utils.WATCH_EXPORT = true
+	defer func() {
+		utils.WATCH_EXPORT = false
+		utils.StartTimeBudget(0)
+	}()
+
+	if utils.IsServerAvailable() {
+		t.Errorf("Expected the server to be unavailable")
+	}
+	isAvailable = true
+	if !utils.IsServerAvailable() {
+		t.Errorf("Expected the server to be available")
+	}
+
+	// Only the files with changed content are written.
+	tempDir := newTempDir(t)
+	fileName := filepath.Join(tempDir, "App1.yml")
+	utils.GetUpdatedFileCount()
+	for _, content := range []string{"name: App1\n", "name: App1\n", "name: App2\n"} {
+		if err := utils.WriteExportedFile(fileName, []byte(content)); err != nil {
+			t.Fatalf("Unexpected error when writing the exported file: %s", err)
+		}
+	}
+	if count := utils.GetUpdatedFileCount(); count != 2 {
+		t.Errorf("Expected 2 updated files but got %d", count)
+	}
+
+	// A stop request completes the current resource and skips the remaining ones until a new run starts.
+	utils.StartTimeBudget(0)
+	utils.RequestStop()
+	if utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected no resources to be scheduled after a stop request")
+	}
+	utils.StartTimeBudget(0)
+	if !utils.IsTimeBudgetAvailable() {
+		t.Errorf("Expected resources to be scheduled after a new run is started")
+	}
+}
@@ -0,0 +1,180 @@
+package tests
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/utils"
+	"github.com/wso2-extensions/identity-tools-cli/iamctl/pkg/workflows"
+)
+
+// Start a mock server with a deployed workflow and its approvers, recording the requests that change the workflows
+// and their bodies.
+func newWorkflowServer(t *testing.T) (requests *[]string, requestBodies map[string]map[string]interface{}) {
+
+	requests, requestBodies = &[]string{}, make(map[string]map[string]interface{})
+	newMockServer(t, func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/t/carbon.super")
+		switch {
+		case r.Method == "GET" && path == "/scim2/v2/Roles":
+			w.Write([]byte(`{"totalResults": 2, "Resources": [
+				{"id": "r1", "displayName": "approvers", "audience": {"type": "organization", "display": "carbon.super"}},
+				{"id": "r2", "displayName": "reviewer", "audience": {"type": "application", "display": "hr-portal"}}]}`))
+		case r.Method == "GET" && path == "/scim2/Users/u1":
+			w.Write([]byte(`{"id": "u1", "userName": "alice"}`))
+		case r.Method == "POST" && path == "/scim2/Users/.search":
+			body, _ := ioutil.ReadAll(r.Body)
+			if strings.Contains(string(body), "alice") {
+				w.Write([]byte(`{"totalResults": 1, "Resources": [{"id": "u1", "userName": "alice"}]}`))
+			} else {
+				w.Write([]byte(`{"totalResults": 0, "Resources": []}`))
+			}
+		case r.Method == "GET" && strings.HasPrefix(path, "/api/server/v1/applications"):
+			w.Write([]byte(`{"totalResults": 1, "applications": [{"id": "app1", "name": "hr-portal"}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/workflows":
+			w.Write([]byte(`{"totalResults": 1, "workflows": [{"id": "w1", "name": "UserApproval"}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/workflows/w1":
+			w.Write([]byte(`{"id": "w1", "name": "UserApproval", "description": "Approve new users",
+				"engine": "WorkflowEngine", "template": {"name": "MultiStepApprovalTemplate", "steps": [
+				{"step": 1, "options": [{"entity": "roles", "values": ["r1"]}, {"entity": "users", "values": ["u1"]}]},
+				{"step": 2, "options": [{"entity": "roles", "values": ["r2"]}]}]}}`))
+		case r.Method == "GET" && path == "/api/server/v1/workflow-associations":
+			w.Write([]byte(`{"totalResults": 2, "workflowAssociations": [
+				{"id": "a1", "associationName": "SelfSignUp", "operation": "ADD_USER", "workflowName": "UserApproval", "isEnabled": true},
+				{"id": "a2", "associationName": "RoleUpdates", "operation": "UPDATE_ROLES_OF_USERS", "workflowName": "Other", "isEnabled": true}]}`))
+		case r.Method == "GET" && path == "/api/server/v1/workflow-associations/a1":
+			w.Write([]byte(`{"id": "a1", "associationName": "SelfSignUp", "operation": "ADD_USER", "workflowName": "UserApproval",
+				"associationCondition": "boolean(1)", "isEnabled": true}`))
+		default:
+			body, _ := ioutil.ReadAll(r.Body)
+			requestKey := r.Method + " " + strings.TrimPrefix(path, "/api/server/v1")
+			*requests = append(*requests, requestKey)
+			var payload map[string]interface{}
+			json.Unmarshal(body, &payload)
+			requestBodies[requestKey] = payload
+			if r.Method == "POST" {
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{"id": "new-id"}`))
+			}
+		}
+	})
+	utils.TOOL_CONFIGS = utils.ToolConfigs{}
+	return requests, requestBodies
+}
+
+func TestExportWorkflows(t *testing.T) {
+
+	newWorkflowServer(t)
+	tempDir := newTempDir(t)
+
+	// The approvers are exported by the role keys and usernames, along with the associations of the workflow.
+	workflows.ExportAll(tempDir)
+	exportedContent, err := ioutil.ReadFile(filepath.Join(tempDir, utils.WORKFLOWS, "UserApproval.yml"))
+	if err != nil {
+		t.Fatalf("Unexpected error when reading the exported file: %s", err)
+	}
+	for _, expected := range []string{"- approvers", "- alice", "- hr-portal/reviewer", "associationName: SelfSignUp",
+		"associationCondition: boolean(1)"} {
+		if !strings.Contains(string(exportedContent), expected) {
+			t.Errorf("Expected the exported content to contain %q but got:\n%s", expected, exportedContent)
+		}
+	}
+	if strings.Contains(string(exportedContent), "RoleUpdates") {
+		t.Errorf("Expected the exported content without the associations of other workflows but got:\n%s", exportedContent)
+	}
+}
+
+func TestImportWorkflows(t *testing.T) {
+
+	testCases := []struct {
+		name             string
+		localFiles       map[string]string
+		expectedRequests []string
+		checkBodies      func(t *testing.T, requestBodies map[string]map[string]interface{})
+	}{
+		{
+			name: "A workflow is not imported if the application of an approver role does not exist",
+			localFiles: map[string]string{"MissingApp.yml": "name: MissingApp\nengine: WorkflowEngine\ntemplate:\n" +
+				"  name: MultiStepApprovalTemplate\n  steps:\n  - step: 1\n    options:\n    - entity: roles\n" +
+				"      values:\n      - payroll/approver\n"},
+		},
+		{
+			name: "A new workflow is created with the ids of the approvers",
+			localFiles: map[string]string{"NewApproval.yml": "name: NewApproval\nengine: WorkflowEngine\ntemplate:\n" +
+				"  name: MultiStepApprovalTemplate\n  steps:\n  - step: 1\n    options:\n    - entity: roles\n" +
+				"      values:\n      - hr-portal/reviewer\n    - entity: users\n      values:\n      - alice\n"},
+			expectedRequests: []string{"POST /workflows"},
+			checkBodies: func(t *testing.T, requestBodies map[string]map[string]interface{}) {
+				createdWorkflow, _ := json.Marshal(requestBodies["POST /workflows"]["template"])
+				if !strings.Contains(string(createdWorkflow), `"values":["r2"]`) ||
+					!strings.Contains(string(createdWorkflow), `"values":["u1"]`) {
+					t.Errorf("Expected the created workflow with the approver ids but got %s", createdWorkflow)
+				}
+			},
+		},
+		{
+			name: "A workflow is updated and its new associations are added",
+			localFiles: map[string]string{"UserApproval.yml": `associations:
+- associationName: AddRole
+  operation: ADD_ROLE
+  isEnabled: false
+- associationCondition: boolean(1)
+  associationName: SelfSignUp
+  isEnabled: true
+  operation: ADD_USER
+description: Approve new users
+engine: WorkflowEngine
+name: UserApproval
+template:
+  name: MultiStepApprovalTemplate
+  steps:
+  - options:
+    - entity: roles
+      values:
+      - approvers
+    - entity: users
+      values:
+      - alice
+    step: 1
+  - options:
+    - entity: roles
+      values:
+      - approvers
+    step: 2
+`},
+			expectedRequests: []string{"PUT /workflows/w1", "POST /workflow-associations",
+				"PATCH /workflow-associations/new-id"},
+			checkBodies: func(t *testing.T, requestBodies map[string]map[string]interface{}) {
+				updatedWorkflow, _ := json.Marshal(requestBodies["PUT /workflows/w1"]["template"])
+				if strings.Contains(string(updatedWorkflow), "r2") {
+					t.Errorf("Expected the updated workflow without the removed approver but got %s", updatedWorkflow)
+				}
+				association, enabled := requestBodies["POST /workflow-associations"],
+					requestBodies["PATCH /workflow-associations/new-id"]
+				if association["workflowId"] != "w1" || enabled["isEnabled"] != false {
+					t.Errorf("Expected a disabled association of the workflow w1 but got %v and %v", association, enabled)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests, requestBodies := newWorkflowServer(t)
+			tempDir := newTempDir(t)
+			writeFiles(t, filepath.Join(tempDir, utils.WORKFLOWS), tc.localFiles)
+
+			workflows.ImportAll(tempDir)
+			if strings.Join(*requests, ",") != strings.Join(tc.expectedRequests, ",") {
+				t.Fatalf("Expected requests %v but got %v", tc.expectedRequests, *requests)
+			}
+			if tc.checkBodies != nil {
+				tc.checkBodies(t, requestBodies)
+			}
+		})
+	}
+}